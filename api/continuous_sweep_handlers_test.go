@@ -34,7 +34,7 @@ func TestUpdateContinuousSweepConfig_TripLengths_Success(t *testing.T) {
 	workerManager.SetSweepRunner(runner)
 
 	router := gin.New()
-	router.PUT("/admin/continuous-sweep/config", updateContinuousSweepConfig(workerManager))
+	router.PUT("/admin/continuous-sweep/config", updateContinuousSweepConfig(workerManager, mockDB))
 
 	reqBody := map[string]any{
 		"trip_lengths": []int{7, 3, 3, 5},
@@ -78,7 +78,7 @@ func TestUpdateContinuousSweepConfig_TripLengths_Invalid(t *testing.T) {
 	workerManager.SetSweepRunner(runner)
 
 	router := gin.New()
-	router.PUT("/admin/continuous-sweep/config", updateContinuousSweepConfig(workerManager))
+	router.PUT("/admin/continuous-sweep/config", updateContinuousSweepConfig(workerManager, mockDB))
 
 	reqBody := map[string]any{
 		"trip_lengths": []int{0, 7},
@@ -95,3 +95,62 @@ func TestUpdateContinuousSweepConfig_TripLengths_Invalid(t *testing.T) {
 	cfg := runner.GetConfig()
 	assert.Equal(t, []int{7, 14}, cfg.TripLengths)
 }
+
+func TestUpdateContinuousSweepConfig_NearbyOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	originalAirports := db.Top100Airports
+	db.Top100Airports = []db.TopAirport{
+		{Code: "AAA", Country: "US"},
+		{Code: "BBB", Country: "FR"},
+		{Code: "CCC", Country: "US"},
+	}
+	t.Cleanup(func() { db.Top100Airports = originalAirports })
+
+	mockDB := new(mocks.MockPostgresDB)
+	mockQueue := new(mocks.MockQueue)
+	workerManager := newWorkerManagerForTests(mockQueue, mockDB)
+
+	runner := worker.NewContinuousSweepRunner(mockDB, mockQueue, nil, worker.DefaultContinuousSweepConfig())
+	workerManager.SetSweepRunner(runner)
+	assert.Equal(t, 4*2, runner.GetStatus().TotalRoutes)
+
+	router := gin.New()
+	router.PUT("/admin/continuous-sweep/config", updateContinuousSweepConfig(workerManager, nil))
+
+	body, _ := json.Marshal(map[string]any{"origins": []string{"near:sfo:50"}})
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/admin/continuous-sweep/config", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	cfg := runner.GetConfig()
+	assert.Contains(t, cfg.Origins, "SFO")
+	assert.Contains(t, cfg.Origins, "OAK")
+	assert.NotContains(t, cfg.Origins, "LAX")
+
+	var resp struct {
+		Status         db.SweepStatusResponse `json:"status"`
+		NearbyAirports []struct {
+			Code          string  `json:"code"`
+			Anchor        string  `json:"anchor"`
+			DistanceMiles float64 `json:"distance_miles"`
+		} `json:"nearby_airports"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, cfg.Origins, resp.Status.Origins)
+	// Every nearby origin is swept to the 3 top airports, for 2 trip lengths.
+	assert.Equal(t, len(cfg.Origins)*3*2, resp.Status.TotalRoutes)
+	assert.Len(t, resp.NearbyAirports, len(cfg.Origins))
+	assert.Equal(t, "SFO", resp.NearbyAirports[0].Code)
+
+	body, _ = json.Marshal(map[string]any{"origins": []string{"NEAR:XQZ:50"}})
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/admin/continuous-sweep/config", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/gin-gonic/gin"
+)
+
+// deadLetterQueue returns the dead-letter side of q, answering 501 for queues without one.
+func deadLetterQueue(c *gin.Context, q queue.Queue) (queue.DeadLetterQueue, string, bool) {
+	queueName := c.Param("name")
+	if !isAllowedQueueName(queueName) {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
+		return nil, "", false
+	}
+	dlq, ok := q.(queue.DeadLetterQueue)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, apitypes.ErrorResponse{Error: "Queue has no dead-letter stream"})
+		return nil, "", false
+	}
+	return dlq, queueName, true
+}
+
+// ListDeadLetters lists the jobs of a queue that ran out of attempts, most recent first,
+// optionally only those of one error_class.
+func ListDeadLetters(q queue.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dlq, queueName, ok := deadLetterQueue(c, q)
+		if !ok {
+			return
+		}
+
+		filter := queue.DeadLetterFilter{ErrorClass: strings.TrimSpace(c.Query("error_class"))}
+		limit := 100
+		offset := 0
+		if v := c.Query("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				limit = n
+			}
+		}
+		if v := c.Query("offset"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				offset = n
+			}
+		}
+
+		deadLetters, err := dlq.ListDeadLetters(c.Request.Context(), queueName, filter, limit, offset)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, apitypes.QueueDeadLetterListResponse{
+			Queue:       queueName,
+			ErrorClass:  filter.ErrorClass,
+			Limit:       limit,
+			Offset:      offset,
+			Count:       len(deadLetters),
+			DeadLetters: convertDeadLetters(deadLetters),
+		})
+	}
+}
+
+// GetDeadLetter returns the dead letter of a job with its payload and failure timeline.
+func GetDeadLetter(q queue.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dlq, queueName, ok := deadLetterQueue(c, q)
+		if !ok {
+			return
+		}
+
+		dl, err := dlq.GetDeadLetter(c.Request.Context(), queueName, c.Param("id"))
+		if err != nil {
+			respondDeadLetterError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, apitypes.QueueDeadLetterResponse{Queue: queueName, DeadLetter: convertDeadLetter(dl)})
+	}
+}
+
+// UpdateDeadLetterPayload replaces the payload a dead letter is replayed with, e.g. to fix a
+// request that can never succeed as it was enqueued.
+func UpdateDeadLetterPayload(q queue.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dlq, queueName, ok := deadLetterQueue(c, q)
+		if !ok {
+			return
+		}
+
+		var req apitypes.DeadLetterPayloadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		dl, err := dlq.UpdateDeadLetterPayload(c.Request.Context(), queueName, c.Param("id"), req.Payload)
+		if err != nil {
+			respondDeadLetterError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, apitypes.QueueDeadLetterResponse{Queue: queueName, DeadLetter: convertDeadLetter(dl)})
+	}
+}
+
+// ReplayDeadLetters re-enqueues the selected dead letters of a queue with fresh attempts.
+func ReplayDeadLetters(q queue.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dlq, queueName, ok := deadLetterQueue(c, q)
+		if !ok {
+			return
+		}
+
+		var req apitypes.DeadLetterReplayRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if len(req.JobIDs) == 0 && strings.TrimSpace(req.ErrorClass) == "" {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "job_ids or error_class is required"})
+			return
+		}
+		limit := req.Limit
+		if limit <= 0 {
+			limit = 200
+		}
+
+		filter := queue.DeadLetterFilter{ErrorClass: strings.TrimSpace(req.ErrorClass), JobIDs: req.JobIDs}
+		replayed, err := dlq.ReplayDeadLetters(c.Request.Context(), queueName, filter, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		stats, err := q.GetQueueStats(c.Request.Context(), queueName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, apitypes.QueueDeadLetterReplayResponse{
+			Queue:    queueName,
+			Replayed: replayed,
+			Count:    len(replayed),
+			Stats:    stats,
+		})
+	}
+}
+
+func respondDeadLetterError(c *gin.Context, err error) {
+	if errors.Is(err, queue.ErrDeadLetterNotFound) {
+		c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Dead letter not found"})
+		return
+	}
+	if errors.Is(err, queue.ErrInvalidPayload) {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 )
 
 type DirectSearchDatePlan struct {
@@ -13,7 +15,7 @@ type DirectSearchDatePlan struct {
 	PriceGraph     PriceGraphBuildParams
 }
 
-func PlanDirectSearchDates(now time.Time, req DirectSearchRequest) (DirectSearchDatePlan, error) {
+func PlanDirectSearchDates(now time.Time, req apitypes.DirectSearchRequest) (DirectSearchDatePlan, error) {
 	nowDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 
 	priceGraphParams := PriceGraphBuildParams{
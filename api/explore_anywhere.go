@@ -0,0 +1,309 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/iata"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/pkg/cache"
+	"github.com/gilby125/google-flights-api/pkg/geo"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+const (
+	// defaultExploreWindowDays is the departure range of an explore search without dates.
+	defaultExploreWindowDays = 30
+	// defaultExploreLimit is how many destinations an explore search returns unless asked.
+	defaultExploreLimit = 50
+)
+
+// exploreAnywhereCacheTTL is how long explore results are served from the cache.
+const exploreAnywhereCacheTTL = cache.ShortTTL
+
+// exploreSearcher is the part of [flights.Session] used by explore searches.
+type exploreSearcher interface {
+	GetExploreDestinations(ctx context.Context, args flights.ExploreArgs) ([]flights.ExploreDestination, *flights.ParseErrors, error)
+}
+
+// ExploreAnywhere returns a handler which lists the cheapest destinations from an origin using
+// Google's explore data, including destinations we have never searched. Results are cached
+// through cacheManager, which may be nil; with persist they are stored in neo4jDB. The Google
+// sessions are drawn from sessions.
+//
+// It is not routed yet, see RegisterRoutes.
+func ExploreAnywhere(neo4jDB db.Neo4jDatabase, cacheManager *cache.CacheManager, sessions *flights.SessionPool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req apitypes.ExploreAnywhereRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+		normalizeExploreAnywhereRequest(&req)
+
+		cur, err := currency.ParseISO(req.Currency)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid currency code"})
+			return
+		}
+		now := time.Now().UTC()
+		from, to, err := exploreDateRange(req, now)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if req.Persist && neo4jDB == nil {
+			c.JSON(http.StatusServiceUnavailable, apitypes.ErrorResponse{Error: "neo4j is not configured"})
+			return
+		}
+
+		tripType := flights.RoundTrip
+		if req.TripLength == 0 {
+			tripType = flights.OneWay
+		}
+		args := flights.ExploreArgs{
+			RangeStartDate: from,
+			RangeEndDate:   to,
+			TripLength:     req.TripLength,
+			SrcAirports:    []string{req.Origin},
+			Options: flights.Options{
+				Travelers: flights.Travelers{Adults: req.Adults},
+				Currency:  cur,
+				Stops:     ParseStops(req.Stops),
+				Class:     ParseClass(req.Class),
+				TripType:  tripType,
+				Lang:      language.English,
+			},
+		}
+
+		ctx := c.Request.Context()
+		cacheKey := cache.ExploreAnywhereKey(req.Origin, from.Format(dateLayout), to.Format(dateLayout), req.TripLength,
+			fmt.Sprintf("%s:%s:%s:%d", req.Class, req.Stops, req.Currency, req.Adults))
+
+		var destinations []flights.ExploreDestination
+		var warnings []string
+		cached := false
+		if cacheManager != nil {
+			err := cacheManager.GetJSON(ctx, cacheKey, &destinations)
+			if err == nil {
+				cached = true
+			} else if err != cache.ErrCacheMiss {
+				log.Printf("Failed to read explore destinations from cache: %v", err)
+			}
+		}
+		if !cached {
+			session, err := sessions.Get(ctx)
+			if err != nil {
+				log.Printf("Error creating flight session: %v", err)
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to initialize flight search"})
+				return
+			}
+			destinations, warnings, err = searchExploreDestinations(ctx, session, args)
+			if err != nil {
+				log.Printf("Error exploring destinations from %s: %v", req.Origin, err)
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to explore destinations: " + err.Error()})
+				return
+			}
+			if cacheManager != nil {
+				if err := cacheManager.SetJSON(ctx, cacheKey, destinations, exploreAnywhereCacheTTL); err != nil {
+					log.Printf("Failed to cache explore destinations: %v", err)
+				}
+			}
+		}
+
+		resp := apitypes.ExploreAnywhereResponse{
+			Origin:            req.Origin,
+			Currency:          req.Currency,
+			DepartureDateFrom: from.Format(dateLayout),
+			DepartureDateTo:   to.Format(dateLayout),
+			TripLength:        req.TripLength,
+			TripType:          exploreTripType(req.TripLength),
+			Destinations:      rankExploreDestinations(req.Origin, destinations, req.MaxPrice, req.Limit),
+			Warnings:          warnings,
+			Cached:            cached,
+		}
+		resp.Count = len(resp.Destinations)
+
+		if req.Persist {
+			persisted, persistWarnings := persistExploreDestinations(neo4jDB, req.Origin, req.Class, resp.TripType, resp.Destinations)
+			resp.Persisted = persisted
+			resp.Warnings = append(resp.Warnings, persistWarnings...)
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func normalizeExploreAnywhereRequest(req *apitypes.ExploreAnywhereRequest) {
+	req.Origin = strings.ToUpper(strings.TrimSpace(req.Origin))
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if req.Currency == "" {
+		req.Currency = "USD"
+	}
+	if req.Adults == 0 {
+		req.Adults = 1
+	}
+	if req.Class == "" {
+		req.Class = "economy"
+	}
+	if req.Stops == "" {
+		req.Stops = "any"
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultExploreLimit
+	}
+}
+
+// exploreDateRange returns the departure range of req. Past days of the range are skipped.
+func exploreDateRange(req apitypes.ExploreAnywhereRequest, now time.Time) (time.Time, time.Time, error) {
+	today := truncateDate(now)
+	var from, to time.Time
+	switch {
+	case req.Month != "":
+		if !req.DepartureDateFrom.IsZero() || !req.DepartureDateTo.IsZero() {
+			return from, to, fmt.Errorf("month can't be combined with departure_date_from/departure_date_to")
+		}
+		month, err := time.Parse("2006-01", req.Month)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid month format, use YYYY-MM")
+		}
+		from = month
+		to = month.AddDate(0, 1, -1)
+	case !req.DepartureDateFrom.IsZero():
+		from = truncateDate(req.DepartureDateFrom.Time)
+		to = from.AddDate(0, 0, defaultExploreWindowDays)
+		if !req.DepartureDateTo.IsZero() {
+			to = truncateDate(req.DepartureDateTo.Time)
+		}
+	default:
+		if !req.DepartureDateTo.IsZero() {
+			return from, to, fmt.Errorf("departure_date_to requires departure_date_from")
+		}
+		from = today
+		to = today.AddDate(0, 0, defaultExploreWindowDays)
+	}
+
+	if to.Before(from) {
+		return from, to, fmt.Errorf("departure_date_to must not be before departure_date_from")
+	}
+	if to.Before(today) {
+		return from, to, fmt.Errorf("departure dates must not all be in the past")
+	}
+	if from.Before(today) {
+		from = today
+	}
+	if to.Sub(from) > 161*24*time.Hour {
+		return from, to, fmt.Errorf("departure range must not exceed 161 days")
+	}
+	return from, to, nil
+}
+
+func exploreTripType(tripLength int) string {
+	if tripLength == 0 {
+		return "one_way"
+	}
+	return "round_trip"
+}
+
+// searchExploreDestinations runs the explore search. Parsing problems which still left results
+// are reported as warnings.
+func searchExploreDestinations(ctx context.Context, searcher exploreSearcher, args flights.ExploreArgs) ([]flights.ExploreDestination, []string, error) {
+	destinations, parseErrors, err := searcher.GetExploreDestinations(ctx, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	var warnings []string
+	if parseErrors != nil && parseErrors.UnmarshalFailures > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d explore response section(s) could not be parsed", parseErrors.UnmarshalFailures))
+	}
+	return destinations, warnings, nil
+}
+
+// rankExploreDestinations keeps the limit cheapest destinations at or under maxPrice (0 means
+// any price) and ranks them. destinations must be sorted by price.
+func rankExploreDestinations(origin string, destinations []flights.ExploreDestination, maxPrice float64, limit int) []apitypes.ExploreAnywhereDestination {
+	originLocation := iata.IATATimeZone(origin)
+	originCoordinates := geo.Coordinates{Lat: originLocation.Lat, Lon: originLocation.Lon}
+
+	ranked := []apitypes.ExploreAnywhereDestination{}
+	for _, destination := range destinations {
+		if maxPrice > 0 && destination.Price > maxPrice {
+			continue
+		}
+		if len(ranked) >= limit {
+			break
+		}
+		if destination.AirportCode == origin {
+			continue
+		}
+
+		out := apitypes.ExploreAnywhereDestination{
+			Rank:            len(ranked) + 1,
+			CityID:          destination.CityID,
+			City:            destination.City,
+			Country:         destination.Country,
+			AirportCode:     destination.AirportCode,
+			Lat:             destination.Lat,
+			Lon:             destination.Lon,
+			Price:           destination.Price,
+			DepartureDate:   formatOptionalDate(destination.StartDate),
+			ReturnDate:      formatOptionalDate(destination.ReturnDate),
+			Stops:           destination.Stops,
+			AirlineCode:     destination.AirlineCode,
+			DurationMinutes: int(destination.Duration.Minutes()),
+		}
+		destinationCoordinates := geo.Coordinates{Lat: destination.Lat, Lon: destination.Lon}
+		if destination.AirportCode != "" {
+			if location := iata.IATATimeZone(destination.AirportCode); location.Lat != 0 || location.Lon != 0 {
+				destinationCoordinates = geo.Coordinates{Lat: location.Lat, Lon: location.Lon}
+			}
+		}
+		if (originCoordinates.Lat != 0 || originCoordinates.Lon != 0) && originCoordinates.IsValid() &&
+			(destinationCoordinates.Lat != 0 || destinationCoordinates.Lon != 0) && destinationCoordinates.IsValid() {
+			distance := geo.Haversine(originCoordinates.Lat, originCoordinates.Lon, destinationCoordinates.Lat, destinationCoordinates.Lon)
+			out.DistanceMiles = math.Round(distance*10) / 10
+		}
+		ranked = append(ranked, out)
+	}
+	return ranked
+}
+
+// persistExploreDestinations stores the destinations with an airport as Neo4j price points from
+// origin, creating the airports as needed. It returns how many were stored.
+func persistExploreDestinations(neo4jDB db.Neo4jDatabase, origin, class, tripType string, destinations []apitypes.ExploreAnywhereDestination) (int, []string) {
+	var warnings []string
+	if err := neo4jDB.CreateAirport(origin, "", "", "", 0, 0); err != nil {
+		return 0, []string{fmt.Sprintf("failed to persist origin airport: %v", err)}
+	}
+
+	persisted := 0
+	for _, destination := range destinations {
+		if destination.AirportCode == "" || destination.DepartureDate == "" {
+			continue
+		}
+		lat, lon := destination.Lat, destination.Lon
+		if location := iata.IATATimeZone(destination.AirportCode); location.Lat != 0 || location.Lon != 0 {
+			lat, lon = location.Lat, location.Lon
+		}
+		if err := neo4jDB.CreateAirport(destination.AirportCode, "", destination.City, destination.Country, lat, lon); err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to persist airport %s: %v", destination.AirportCode, err))
+			continue
+		}
+		if err := neo4jDB.AddPricePoint(origin, destination.AirportCode, destination.DepartureDate, destination.ReturnDate,
+			destination.Price, destination.AirlineCode, tripType, class); err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to persist %s->%s: %v", origin, destination.AirportCode, err))
+			continue
+		}
+		persisted++
+	}
+	return persisted, warnings
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExploreSearcher struct {
+	destinations []flights.ExploreDestination
+	parseErrors  *flights.ParseErrors
+	err          error
+	calls        []flights.ExploreArgs
+}
+
+func (f *fakeExploreSearcher) GetExploreDestinations(_ context.Context, args flights.ExploreArgs) ([]flights.ExploreDestination, *flights.ParseErrors, error) {
+	f.calls = append(f.calls, args)
+	return f.destinations, f.parseErrors, f.err
+}
+
+func exploreTestDestinations() []flights.ExploreDestination {
+	start := time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC)
+	return []flights.ExploreDestination{
+		{CityID: "/m/0k049", City: "Los Angeles", Country: "United States", AirportCode: "LAX", Price: 129, StartDate: start, ReturnDate: start.AddDate(0, 0, 7), Stops: 0, AirlineCode: "UA", Duration: 85 * time.Minute},
+		{CityID: "/m/0xyz", City: "Nowhere", Price: 300, StartDate: start, Stops: -1},
+		{CityID: "/m/05qtj", City: "Paris", Country: "France", AirportCode: "CDG", Lat: 48.85, Lon: 2.35, Price: 488, StartDate: start, ReturnDate: start.AddDate(0, 0, 7), Stops: 1},
+		{CityID: "/m/04jpl", City: "London", Country: "United Kingdom", AirportCode: "LHR", Price: 523, StartDate: start, ReturnDate: start.AddDate(0, 0, 7), Stops: 1},
+	}
+}
+
+func TestRankExploreDestinations(t *testing.T) {
+	ranked := rankExploreDestinations("SFO", exploreTestDestinations(), 500, 10)
+	require.Len(t, ranked, 3)
+
+	assert.Equal(t, 1, ranked[0].Rank)
+	assert.Equal(t, "LAX", ranked[0].AirportCode)
+	assert.Equal(t, "2026-06-10", ranked[0].DepartureDate)
+	assert.Equal(t, "2026-06-17", ranked[0].ReturnDate)
+	assert.Equal(t, 85, ranked[0].DurationMinutes)
+	assert.InDelta(t, 337, ranked[0].DistanceMiles, 10)
+
+	// Destinations without coordinates have no distance.
+	assert.Equal(t, "Nowhere", ranked[1].City)
+	assert.Zero(t, ranked[1].DistanceMiles)
+	assert.Equal(t, -1, ranked[1].Stops)
+
+	assert.Equal(t, 3, ranked[2].Rank)
+	assert.Equal(t, "CDG", ranked[2].AirportCode)
+
+	assert.Len(t, rankExploreDestinations("SFO", exploreTestDestinations(), 0, 2), 2)
+}
+
+func TestExploreDateRange(t *testing.T) {
+	now := time.Date(2026, 6, 15, 10, 0, 0, 0, time.UTC)
+
+	from, to, err := exploreDateRange(apitypes.ExploreAnywhereRequest{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-06-15", from.Format(dateLayout))
+	assert.Equal(t, "2026-07-15", to.Format(dateLayout))
+
+	// The past days of the month are skipped.
+	from, to, err = exploreDateRange(apitypes.ExploreAnywhereRequest{Month: "2026-06"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-06-15", from.Format(dateLayout))
+	assert.Equal(t, "2026-06-30", to.Format(dateLayout))
+
+	for name, req := range map[string]apitypes.ExploreAnywhereRequest{
+		"bad month":       {Month: "June"},
+		"past month":      {Month: "2026-05"},
+		"month and date":  {Month: "2026-07", DepartureDateFrom: apitypes.DateOnly{Time: now}},
+		"to without from": {DepartureDateTo: apitypes.DateOnly{Time: now}},
+		"too long": {
+			DepartureDateFrom: apitypes.DateOnly{Time: now},
+			DepartureDateTo:   apitypes.DateOnly{Time: now.AddDate(0, 6, 0)},
+		},
+	} {
+		_, _, err := exploreDateRange(req, now)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestSearchExploreDestinations(t *testing.T) {
+	searcher := &fakeExploreSearcher{
+		destinations: exploreTestDestinations(),
+		parseErrors:  &flights.ParseErrors{UnmarshalFailures: 1},
+	}
+	destinations, warnings, err := searchExploreDestinations(context.Background(), searcher, flights.ExploreArgs{SrcAirports: []string{"SFO"}})
+	require.NoError(t, err)
+	assert.Len(t, destinations, 4)
+	assert.Len(t, warnings, 1)
+
+	searcher = &fakeExploreSearcher{err: flights.ErrRateLimited}
+	_, _, err = searchExploreDestinations(context.Background(), searcher, flights.ExploreArgs{})
+	assert.ErrorIs(t, err, flights.ErrRateLimited)
+}
+
+func TestPersistExploreDestinations(t *testing.T) {
+	neo4jDB := new(mocks.MockNeo4jDatabase)
+	neo4jDB.On("CreateAirport", "SFO", "", "", "", 0.0, 0.0).Return(nil)
+	neo4jDB.On("CreateAirport", "LAX", "", "Los Angeles", "United States", mock.Anything, mock.Anything).Return(nil)
+	neo4jDB.On("CreateAirport", "CDG", "", "Paris", "France", mock.Anything, mock.Anything).Return(errors.New("down"))
+	neo4jDB.On("AddPricePoint", "SFO", "LAX", "2026-06-10", "2026-06-17", 129.0, "UA", "round_trip", "economy").Return(nil)
+
+	ranked := rankExploreDestinations("SFO", exploreTestDestinations(), 500, 10)
+	persisted, warnings := persistExploreDestinations(neo4jDB, "SFO", "economy", "round_trip", ranked)
+	assert.Equal(t, 1, persisted)
+	assert.Len(t, warnings, 1)
+	neo4jDB.AssertExpectations(t)
+}
+
+func TestExploreAnywhere_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/explore/anywhere", ExploreAnywhere(nil, nil, nil))
+
+	for name, body := range map[string]map[string]interface{}{
+		"missing origin":   {},
+		"bad currency":     {"origin": "SFO", "currency": "US1"},
+		"long trip":        {"origin": "SFO", "trip_length": 45},
+		"bad month":        {"origin": "SFO", "month": "2026/06"},
+		"persist no neo4j": {"origin": "SFO", "persist": true},
+	} {
+		data, _ := json.Marshal(body)
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/explore/anywhere", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+
+		assert.Contains(t, []int{http.StatusBadRequest, http.StatusServiceUnavailable}, rec.Code, name)
+	}
+}
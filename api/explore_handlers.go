@@ -7,48 +7,19 @@ import (
 	"strings"
 
 	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 	"github.com/gin-gonic/gin"
 )
 
-type ExploreEdge struct {
-	OriginCode string  `json:"origin_code"`
-	OriginLat  float64 `json:"origin_lat"`
-	OriginLon  float64 `json:"origin_lon"`
-
-	DestCode    string  `json:"dest_code"`
-	DestName    string  `json:"dest_name,omitempty"`
-	DestCity    string  `json:"dest_city,omitempty"`
-	DestCountry string  `json:"dest_country,omitempty"`
-	DestLat     float64 `json:"dest_lat"`
-	DestLon     float64 `json:"dest_lon"`
-
-	CheapestPrice float64 `json:"cheapest_price"`
-	Hops          int     `json:"hops"`
-}
-
-type ExploreResponse struct {
-	Origin          string   `json:"origin"`
-	Origins         []string `json:"origins,omitempty"`
-	MaxHops         int      `json:"maxHops"`
-	MaxPrice        float64  `json:"maxPrice"`
-	DateFrom        string   `json:"dateFrom,omitempty"`
-	DateTo          string   `json:"dateTo,omitempty"`
-	Limit           int      `json:"limit"`
-	Source          string   `json:"source"`
-	MaxAgeDays      int      `json:"maxAgeDays,omitempty"`
-	TripType        string   `json:"tripType,omitempty"`
-	Airlines        []string `json:"airlines,omitempty"`
-	ExcludeAirlines []string `json:"excludeAirlines,omitempty"`
-
-	Count int           `json:"count"`
-	Edges []ExploreEdge `json:"edges"`
-}
-
 // GetTopAirports returns a small curated list of airports for UI pickers.
 // GET /api/v1/airports/top
 func GetTopAirports() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusOK, db.Top100Airports)
+		airports := make([]apitypes.TopAirport, 0, len(db.Top100Airports))
+		for _, airport := range db.Top100Airports {
+			airports = append(airports, apitypes.TopAirport{Code: airport.Code, Country: airport.Country})
+		}
+		c.JSON(http.StatusOK, airports)
 	}
 }
 
@@ -57,7 +28,7 @@ func GetTopAirports() gin.HandlerFunc {
 func GetExplore(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if neo4jDB == nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "neo4j is not configured"})
+			c.JSON(http.StatusServiceUnavailable, apitypes.ErrorResponse{Error: "neo4j is not configured"})
 			return
 		}
 
@@ -87,7 +58,7 @@ func GetExplore(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 			addOrigin(origin)
 		}
 		if len(origins) == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "origin (or origins) query parameter is required"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "origin (or origins) query parameter is required"})
 			return
 		}
 
@@ -113,11 +84,11 @@ func GetExplore(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 			source = "price_point"
 		}
 		if source != "price_point" && source != "route" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "source must be one of: price_point, route"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "source must be one of: price_point, route"})
 			return
 		}
 		if source == "route" && (dateFrom != "" || dateTo != "") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "dateFrom/dateTo are only supported with source=price_point"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "dateFrom/dateTo are only supported with source=price_point"})
 			return
 		}
 
@@ -130,14 +101,14 @@ func GetExplore(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 
 		tripType := strings.ToLower(strings.TrimSpace(c.Query("tripType")))
 		if tripType != "" && tripType != "one_way" && tripType != "round_trip" && tripType != "unknown" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "tripType must be one of: one_way, round_trip, unknown"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "tripType must be one of: one_way, round_trip, unknown"})
 			return
 		}
 
 		// Parse class filter (economy, premium_economy, business, first)
 		class := strings.ToLower(strings.TrimSpace(c.Query("class")))
 		if class != "" && class != "economy" && class != "premium_economy" && class != "business" && class != "first" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "class must be one of: economy, premium_economy, business, first"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "class must be one of: economy, premium_economy, business, first"})
 			return
 		}
 
@@ -256,19 +227,19 @@ func GetExplore(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 			"class":           class,
 		})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 		defer result.Close()
 
-		edges := make([]ExploreEdge, 0, 128)
+		edges := make([]apitypes.ExploreEdge, 0, 128)
 		for result.Next() {
 			rec := result.Record()
 			if rec == nil {
 				continue
 			}
 
-			edge := ExploreEdge{}
+			edge := apitypes.ExploreEdge{}
 
 			if v, ok := rec.Get("originCode"); ok {
 				edge.OriginCode, _ = v.(string)
@@ -316,11 +287,11 @@ func GetExplore(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 			edges = append(edges, edge)
 		}
 		if err := result.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, ExploreResponse{
+		c.JSON(http.StatusOK, apitypes.ExploreResponse{
 			Origin:          origin,
 			Origins:         origins,
 			MaxHops:         maxHops,
@@ -0,0 +1,359 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/pkg/export"
+	"github.com/gin-gonic/gin"
+)
+
+// Columns of the exports, in the order of the columns of the db export queries.
+var (
+	bulkSearchResultColumns = []export.Column{
+		{Name: "origin", Type: export.String},
+		{Name: "destination", Type: export.String},
+		{Name: "departure_date", Type: export.Date},
+		{Name: "return_date", Type: export.Date},
+		{Name: "price", Type: export.Float},
+		{Name: "currency", Type: export.String},
+		{Name: "normalized_price", Type: export.Float},
+		{Name: "normalized_currency", Type: export.String},
+		{Name: "airline_code", Type: export.String},
+		{Name: "duration", Type: export.Int},
+		{Name: "src_airport_code", Type: export.String},
+		{Name: "dst_airport_code", Type: export.String},
+		{Name: "src_city", Type: export.String},
+		{Name: "dst_city", Type: export.String},
+		{Name: "flight_duration", Type: export.Int},
+		{Name: "return_flight_duration", Type: export.Int},
+		{Name: "outbound_flights", Type: export.JSON},
+		{Name: "return_flights", Type: export.JSON},
+		{Name: "segment_flights", Type: export.JSON},
+		{Name: "created_at", Type: export.Timestamp},
+	}
+
+	bulkSearchOfferColumns = []export.Column{
+		{Name: "origin", Type: export.String},
+		{Name: "destination", Type: export.String},
+		{Name: "departure_date", Type: export.Date},
+		{Name: "return_date", Type: export.Date},
+		{Name: "price", Type: export.Float},
+		{Name: "currency", Type: export.String},
+		{Name: "normalized_price", Type: export.Float},
+		{Name: "normalized_currency", Type: export.String},
+		{Name: "airline_codes", Type: export.String},
+		{Name: "src_airport_code", Type: export.String},
+		{Name: "dst_airport_code", Type: export.String},
+		{Name: "src_city", Type: export.String},
+		{Name: "dst_city", Type: export.String},
+		{Name: "flight_duration", Type: export.Int},
+		{Name: "return_flight_duration", Type: export.Int},
+		{Name: "distance_miles", Type: export.Float},
+		{Name: "cost_per_mile", Type: export.Float},
+		{Name: "normalized_cost_per_mile", Type: export.Float},
+		{Name: "outbound_flights", Type: export.JSON},
+		{Name: "return_flights", Type: export.JSON},
+		{Name: "segment_flights", Type: export.JSON},
+		{Name: "created_at", Type: export.Timestamp},
+	}
+
+	priceGraphResultColumns = []export.Column{
+		{Name: "sweep_id", Type: export.Int},
+		{Name: "origin", Type: export.String},
+		{Name: "destination", Type: export.String},
+		{Name: "return_origin", Type: export.String},
+		{Name: "return_destination", Type: export.String},
+		{Name: "departure_date", Type: export.Date},
+		{Name: "return_date", Type: export.Date},
+		{Name: "trip_length", Type: export.Int},
+		{Name: "price", Type: export.Float},
+		{Name: "currency", Type: export.String},
+		{Name: "normalized_price", Type: export.Float},
+		{Name: "normalized_currency", Type: export.String},
+		{Name: "distance_miles", Type: export.Float},
+		{Name: "cost_per_mile", Type: export.Float},
+		{Name: "adults", Type: export.Int},
+		{Name: "children", Type: export.Int},
+		{Name: "infants_lap", Type: export.Int},
+		{Name: "infants_seat", Type: export.Int},
+		{Name: "trip_type", Type: export.String},
+		{Name: "class", Type: export.String},
+		{Name: "stops", Type: export.String},
+		{Name: "search_url", Type: export.String},
+		{Name: "queried_at", Type: export.Timestamp},
+	}
+
+	detectedDealColumns = []export.Column{
+		{Name: "id", Type: export.Int},
+		{Name: "origin", Type: export.String},
+		{Name: "destination", Type: export.String},
+		{Name: "departure_date", Type: export.Date},
+		{Name: "return_date", Type: export.Date},
+		{Name: "trip_length", Type: export.Int},
+		{Name: "price", Type: export.Float},
+		{Name: "currency", Type: export.String},
+		{Name: "normalized_price", Type: export.Float},
+		{Name: "normalized_currency", Type: export.String},
+		{Name: "baseline_mean", Type: export.Float},
+		{Name: "baseline_median", Type: export.Float},
+		{Name: "discount_percent", Type: export.Float},
+		{Name: "deal_score", Type: export.Int},
+		{Name: "deal_classification", Type: export.String},
+		{Name: "distance_miles", Type: export.Float},
+		{Name: "cost_per_mile", Type: export.Float},
+		{Name: "cabin_class", Type: export.String},
+		{Name: "source_type", Type: export.String},
+		{Name: "search_url", Type: export.String},
+		{Name: "status", Type: export.String},
+		{Name: "verified", Type: export.Bool},
+		{Name: "verified_price", Type: export.Float},
+		{Name: "first_seen_at", Type: export.Timestamp},
+		{Name: "last_seen_at", Type: export.Timestamp},
+		{Name: "times_seen", Type: export.Int},
+		{Name: "expires_at", Type: export.Timestamp},
+	}
+)
+
+// exportQuery runs the db export query of a dataset with the filter of the request.
+type exportQuery func(ctx context.Context, filter db.ExportFilter) (db.Rows, error)
+
+// exportBulkSearchResults returns a handler which streams the results of a bulk search as CSV,
+// NDJSON or Parquet
+func exportBulkSearchResults(pgDB db.PostgresDB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bulkID, ok := bulkSearchForExport(c, pgDB)
+		if !ok {
+			return
+		}
+		streamExport(c, fmt.Sprintf("bulk_search_%d_results", bulkID), bulkSearchResultColumns,
+			func(ctx context.Context, filter db.ExportFilter) (db.Rows, error) {
+				return pgDB.ExportBulkSearchResults(ctx, bulkID, filter)
+			})
+	}
+}
+
+// exportBulkSearchOffers returns a handler which streams the offers of a bulk search as CSV,
+// NDJSON or Parquet
+func exportBulkSearchOffers(pgDB db.PostgresDB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bulkID, ok := bulkSearchForExport(c, pgDB)
+		if !ok {
+			return
+		}
+		streamExport(c, fmt.Sprintf("bulk_search_%d_offers", bulkID), bulkSearchOfferColumns,
+			func(ctx context.Context, filter db.ExportFilter) (db.Rows, error) {
+				return pgDB.ExportBulkSearchOffers(ctx, bulkID, filter)
+			})
+	}
+}
+
+// bulkSearchForExport returns the bulk search ID of the request, or responds with an error if
+// the bulk search doesn't exist.
+func bulkSearchForExport(c *gin.Context, pgDB db.PostgresDB) (int, bool) {
+	bulkID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || bulkID <= 0 {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid bulk search ID"})
+		return 0, false
+	}
+	if _, err := pgDB.GetBulkSearchByID(c.Request.Context(), bulkID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Bulk search not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to load bulk search: " + err.Error()})
+		}
+		return 0, false
+	}
+	return bulkID, true
+}
+
+// exportPriceGraphSweepResults returns a handler which streams the fares of a price graph sweep
+// as CSV, NDJSON or Parquet
+func exportPriceGraphSweepResults(pgDB db.PostgresDB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sweepID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || sweepID <= 0 {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid sweep ID"})
+			return
+		}
+		if _, err := pgDB.GetPriceGraphSweepByID(c.Request.Context(), sweepID); err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Price graph sweep not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to load sweep metadata: " + err.Error()})
+			}
+			return
+		}
+
+		streamExport(c, fmt.Sprintf("price_graph_sweep_%d", sweepID), priceGraphResultColumns,
+			func(ctx context.Context, filter db.ExportFilter) (db.Rows, error) {
+				return pgDB.ExportPriceGraphResults(ctx, sweepID, filter)
+			})
+	}
+}
+
+// exportContinuousSweepResults returns a handler which streams the fares of the continuous
+// sweep (sweep_id = 0) as CSV, NDJSON or Parquet
+func exportContinuousSweepResults(pgDB db.PostgresDB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		streamExport(c, "continuous_sweep_results", priceGraphResultColumns,
+			func(ctx context.Context, filter db.ExportFilter) (db.Rows, error) {
+				return pgDB.ExportPriceGraphResults(ctx, 0, filter)
+			})
+	}
+}
+
+// exportDeals returns a handler which streams the detected deals as CSV, NDJSON or Parquet.
+// Unlike listDeals, deals of every status are exported unless the status filter is set.
+func exportDeals(pgDB db.PostgresDB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		streamExport(c, "deals", detectedDealColumns, pgDB.ExportDetectedDeals)
+	}
+}
+
+// parseExportFilter reads the filters of an export request: origin, destination, from and to
+// (departure dates, YYYY-MM-DD), and classification and status for deals.
+func parseExportFilter(c *gin.Context) (db.ExportFilter, error) {
+	filter := db.ExportFilter{
+		Origin:         strings.ToUpper(strings.TrimSpace(c.Query("origin"))),
+		Destination:    strings.ToUpper(strings.TrimSpace(c.Query("destination"))),
+		Classification: c.Query("classification"),
+		Status:         c.Query("status"),
+	}
+	for _, param := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &filter.FromDate}, {"to", &filter.ToDate}} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s date %q: want YYYY-MM-DD", param.name, value)
+		}
+		*param.dst = date
+	}
+	if !filter.FromDate.IsZero() && !filter.ToDate.IsZero() && filter.ToDate.Before(filter.FromDate) {
+		return filter, fmt.Errorf("to date must not be before from date")
+	}
+	return filter, nil
+}
+
+// streamExport runs query and streams its rows as a file download in the format of the request
+// (csv by default). Rows are encoded as they are read, so the export is never held in memory.
+func streamExport(c *gin.Context, name string, columns []export.Column, query exportQuery) {
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+		return
+	}
+	filter, err := parseExportFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	rows, err := query(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to export " + name + ": " + err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", format.ContentType())
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	header.Set("Cache-Control", "no-store")
+
+	writer, err := export.NewWriter(format, c.Writer, columns)
+	if err == nil {
+		err = writeExportRows(rows, columns, writer)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	if !c.Writer.Written() {
+		// nothing was sent yet, so the error can still be reported
+		header.Del("Content-Disposition")
+		header.Del("Content-Type")
+		c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to export " + name + ": " + err.Error()})
+		return
+	}
+	// the file is truncated; CSV and NDJSON readers can't tell, Parquet readers miss the footer
+	log.Printf("Export of %s failed after the response started: %v", name, err)
+	c.Abort()
+}
+
+func writeExportRows(rows db.Rows, columns []export.Column, writer export.Writer) error {
+	dest := make([]interface{}, len(columns))
+	for i, column := range columns {
+		switch column.Type {
+		case export.Int:
+			dest[i] = new(sql.NullInt64)
+		case export.Float:
+			dest[i] = new(sql.NullFloat64)
+		case export.Bool:
+			dest[i] = new(sql.NullBool)
+		case export.Date, export.Timestamp:
+			dest[i] = new(sql.NullTime)
+		case export.JSON:
+			dest[i] = new([]byte)
+		default:
+			dest[i] = new(sql.NullString)
+		}
+	}
+
+	row := make([]interface{}, len(columns))
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		for i, d := range dest {
+			row[i] = nil
+			switch v := d.(type) {
+			case *sql.NullString:
+				if v.Valid {
+					row[i] = v.String
+				}
+			case *sql.NullInt64:
+				if v.Valid {
+					row[i] = v.Int64
+				}
+			case *sql.NullFloat64:
+				if v.Valid {
+					row[i] = v.Float64
+				}
+			case *sql.NullBool:
+				if v.Valid {
+					row[i] = v.Bool
+				}
+			case *sql.NullTime:
+				if v.Valid {
+					row[i] = v.Time
+				}
+			case *[]byte:
+				if *v != nil {
+					row[i] = json.RawMessage(*v)
+				}
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
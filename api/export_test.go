@@ -0,0 +1,213 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/test/mocks"
+)
+
+// fakeRows returns driver values like database/sql, scanning them with the sql.Scanner of the
+// destinations.
+type fakeRows struct {
+	rows    [][]interface{}
+	next    int
+	scanErr error
+	closed  bool
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	if r.scanErr != nil {
+		return r.scanErr
+	}
+	row := r.rows[r.next-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("scan of %d columns into %d destinations", len(row), len(dest))
+	}
+	for i, d := range dest {
+		switch v := d.(type) {
+		case sql.Scanner:
+			if err := v.Scan(row[i]); err != nil {
+				return fmt.Errorf("column %d: %w", i, err)
+			}
+		case *[]byte:
+			if row[i] == nil {
+				*v = nil
+			} else {
+				*v = []byte(row[i].(string))
+			}
+		default:
+			return fmt.Errorf("unexpected destination %T", d)
+		}
+	}
+	return nil
+}
+
+func (r *fakeRows) Close() error {
+	r.closed = true
+	return nil
+}
+
+func (r *fakeRows) Err() error { return nil }
+
+func priceGraphExportRow(origin string, price float64, returnDate interface{}) []interface{} {
+	departure := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	return []interface{}{
+		int64(0), origin, "LHR", nil, nil, departure, returnDate, int64(7), price, "USD",
+		nil, nil, 3451.2, 0.12, int64(1), int64(0), int64(0), int64(0), "round_trip", "economy", "any",
+		"https://www.google.com/travel/flights?tfs=x", time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func newExportRouter(mockDB *mocks.MockPostgresDB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/bulk-jobs/:id/offers/export", exportBulkSearchOffers(mockDB))
+	router.GET("/price-graph-sweeps/:id/export", exportPriceGraphSweepResults(mockDB))
+	router.GET("/continuous-sweep/results/export", exportContinuousSweepResults(mockDB))
+	router.GET("/deals/export", exportDeals(mockDB))
+	return router
+}
+
+func serveExport(router *gin.Engine, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, target, nil)
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestExportContinuousSweepResults(t *testing.T) {
+	mockDB := new(mocks.MockPostgresDB)
+	router := newExportRouter(mockDB)
+
+	filter := db.ExportFilter{Origin: "JFK", FromDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}
+	csvRows := &fakeRows{rows: [][]interface{}{
+		priceGraphExportRow("JFK", 412.5, time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC)),
+		priceGraphExportRow("JFK", 399, nil),
+	}}
+	mockDB.On("ExportPriceGraphResults", mock.Anything, 0, filter).Return(csvRows, nil).Once()
+
+	rec := serveExport(router, "/continuous-sweep/results/export?origin=jfk&from=2026-06-01")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="continuous_sweep_results.csv"`, rec.Header().Get("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "sweep_id,origin,destination,return_origin,return_destination,departure_date,return_date,trip_length,price,"))
+	assert.Equal(t, "0,JFK,LHR,,,2026-06-01,2026-06-08,7,412.5,USD,,,3451.2,0.12,1,0,0,0,round_trip,economy,any,https://www.google.com/travel/flights?tfs=x,2026-05-01T12:00:00Z", lines[1])
+	assert.Contains(t, lines[2], ",2026-06-01,,7,399,")
+	assert.True(t, csvRows.closed)
+
+	parquetRows := &fakeRows{rows: [][]interface{}{priceGraphExportRow("JFK", 412.5, nil)}}
+	mockDB.On("ExportPriceGraphResults", mock.Anything, 0, db.ExportFilter{}).Return(parquetRows, nil).Once()
+	rec = serveExport(router, "/continuous-sweep/results/export?format=parquet")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/vnd.apache.parquet", rec.Header().Get("Content-Type"))
+	body := rec.Body.Bytes()
+	assert.Equal(t, "PAR1", string(body[:4]))
+	assert.Equal(t, "PAR1", string(body[len(body)-4:]))
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestExportDeals_NDJSON(t *testing.T) {
+	mockDB := new(mocks.MockPostgresDB)
+	router := newExportRouter(mockDB)
+
+	seen := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	rows := &fakeRows{rows: [][]interface{}{{
+		int64(42), "JFK", "CDG", time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), nil, int64(7), 289.0, "USD",
+		nil, nil, 610.0, 598.0, 52.6, int64(88), "amazing", 3635.0, 0.08, "economy", "sweep",
+		nil, "active", false, nil, seen, seen, int64(3), nil,
+	}}}
+	mockDB.On("ExportDetectedDeals", mock.Anything, db.ExportFilter{Classification: "amazing", Status: "active"}).Return(rows, nil).Once()
+
+	rec := serveExport(router, "/deals/export?format=ndjson&classification=amazing&status=active")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="deals.ndjson"`, rec.Header().Get("Content-Disposition"))
+
+	var deal map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &deal))
+	assert.Equal(t, 42.0, deal["id"])
+	assert.Equal(t, "2026-07-01", deal["departure_date"])
+	assert.Nil(t, deal["return_date"])
+	assert.Equal(t, "amazing", deal["deal_classification"])
+	assert.Equal(t, false, deal["verified"])
+	assert.Equal(t, "2026-05-01T08:00:00Z", deal["first_seen_at"])
+	mockDB.AssertExpectations(t)
+}
+
+func TestExportBulkSearchOffers(t *testing.T) {
+	mockDB := new(mocks.MockPostgresDB)
+	router := newExportRouter(mockDB)
+
+	mockDB.On("GetBulkSearchByID", mock.Anything, 7).Return(&db.BulkSearch{ID: 7}, nil)
+	rows := &fakeRows{rows: [][]interface{}{{
+		"JFK", "LHR", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), nil, 412.5, "USD", nil, nil, "BA,AA",
+		"JFK", "LHR", "New York", "London", int64(415), nil, 3451.2, 0.12, nil,
+		`[{"flight_number":"BA 112"}]`, nil, nil, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+	}}}
+	mockDB.On("ExportBulkSearchOffers", mock.Anything, 7, db.ExportFilter{Destination: "LHR"}).Return(rows, nil).Once()
+
+	rec := serveExport(router, "/bulk-jobs/7/offers/export?format=ndjson&destination=LHR")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, `attachment; filename="bulk_search_7_offers.ndjson"`, rec.Header().Get("Content-Disposition"))
+	assert.Contains(t, rec.Body.String(), `"airline_codes":"BA,AA"`)
+	assert.Contains(t, rec.Body.String(), `"outbound_flights":[{"flight_number":"BA 112"}],"return_flights":null`)
+
+	mockDB.On("GetBulkSearchByID", mock.Anything, 8).Return(nil, errors.New("bulk search with ID 8 not found"))
+	rec = serveExport(router, "/bulk-jobs/8/offers/export")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockDB.AssertExpectations(t)
+}
+
+func TestExportErrors(t *testing.T) {
+	mockDB := new(mocks.MockPostgresDB)
+	router := newExportRouter(mockDB)
+
+	for _, target := range []string{
+		"/continuous-sweep/results/export?format=xlsx",
+		"/continuous-sweep/results/export?from=06/01/2026",
+		"/continuous-sweep/results/export?from=2026-06-30&to=2026-06-01",
+		"/price-graph-sweeps/abc/export",
+	} {
+		rec := serveExport(router, target)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "application/json", target)
+	}
+
+	// errors before the first row are still reported as JSON
+	mockDB.On("ExportDetectedDeals", mock.Anything, db.ExportFilter{}).
+		Return(&fakeRows{rows: [][]interface{}{{}}, scanErr: errors.New("conn reset")}, nil).Once()
+	rec := serveExport(router, "/deals/export?format=parquet")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+	var resp apitypes.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Error, "conn reset")
+
+	mockDB.On("ExportDetectedDeals", mock.Anything, db.ExportFilter{}).Return(nil, errors.New("db down")).Once()
+	rec = serveExport(router, "/deals/export")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	mockDB.AssertExpectations(t)
+}
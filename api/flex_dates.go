@@ -0,0 +1,351 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/pkg/cache"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// defaultFlexDateOfferSearches is how many GetOffers calls a grid makes unless the request
+// asks for more: first for the cells the price graph left empty, then for the cheapest cells.
+const defaultFlexDateOfferSearches = 6
+
+// flexDateCacheTTL is how long a complete grid is served from the cache.
+const flexDateCacheTTL = cache.ShortTTL
+
+// flexDateSearcher is the part of [flights.Session] used to fill a grid.
+type flexDateSearcher interface {
+	GetPriceGraph(ctx context.Context, args flights.PriceGraphArgs) ([]flights.Offer, *flights.ParseErrors, error)
+	GetOffers(ctx context.Context, args flights.Args) ([]flights.FullOffer, *flights.PriceRange, error)
+	FillReturnFlights(ctx context.Context, args flights.Args, offers []flights.FullOffer, limit int) error
+}
+
+// FlexDateSearch returns a handler which prices a ±N days grid around the requested dates.
+// Complete grids are cached through cacheManager, which may be nil; the Google sessions are drawn
+// from sessions.
+func FlexDateSearch(cacheManager *cache.CacheManager, sessions *flights.SessionPool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req apitypes.FlexDateSearchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		req.Origin = strings.ToUpper(req.Origin)
+		req.Destination = strings.ToUpper(req.Destination)
+		req.Currency = strings.ToUpper(req.Currency)
+		req.DepartureDate = apitypes.DateOnly{Time: truncateDate(req.DepartureDate.Time)}
+		if !req.ReturnDate.IsZero() {
+			req.ReturnDate = apitypes.DateOnly{Time: truncateDate(req.ReturnDate.Time)}
+		}
+		cur, err := currency.ParseISO(req.Currency)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid currency code"})
+			return
+		}
+		if req.Origin == req.Destination {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Origin and destination must differ"})
+			return
+		}
+
+		now := time.Now().UTC()
+		latestDeparture := req.DepartureDate.AddDate(0, 0, req.DepartureFlexDays)
+		if latestDeparture.Before(truncateDate(now)) {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Departure dates must not all be in the past"})
+			return
+		}
+		if !req.ReturnDate.IsZero() && req.ReturnDate.Before(req.DepartureDate.Time) {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Return date must not be before departure date"})
+			return
+		}
+		if req.MaxOfferSearches == 0 {
+			req.MaxOfferSearches = defaultFlexDateOfferSearches
+		}
+
+		cacheKey := cache.FlexDateMatrixKey(req.Origin, req.Destination,
+			req.DepartureDate.Format(dateLayout), formatOptionalDate(req.ReturnDate.Time),
+			req.DepartureFlexDays, req.ReturnFlexDays,
+			fmt.Sprintf("%s:%s:%s:%d:%d:%d:%d:%d", req.Class, req.Stops, req.Currency,
+				req.Adults, req.Children, req.InfantsLap, req.InfantsSeat, req.MaxOfferSearches))
+		ctx := c.Request.Context()
+		if cacheManager != nil {
+			var cached apitypes.FlexDateMatrix
+			err := cacheManager.GetJSON(ctx, cacheKey, &cached)
+			if err == nil {
+				cached.Cached = true
+				c.JSON(http.StatusOK, cached)
+				return
+			}
+			if err != cache.ErrCacheMiss {
+				log.Printf("Failed to read flex-date grid from cache: %v", err)
+			}
+		}
+
+		tripType := flights.OneWay
+		if !req.ReturnDate.IsZero() {
+			tripType = flights.RoundTrip
+		}
+		options := flights.Options{
+			Travelers: flights.Travelers{
+				Adults:       req.Adults,
+				Children:     req.Children,
+				InfantOnLap:  req.InfantsLap,
+				InfantInSeat: req.InfantsSeat,
+			},
+			Currency: cur,
+			Stops:    ParseStops(req.Stops),
+			Class:    ParseClass(req.Class),
+			TripType: tripType,
+			Lang:     language.English,
+		}
+
+		session, err := sessions.Get(ctx)
+		if err != nil {
+			log.Printf("Error creating flight session: %v", err)
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to initialize flight search"})
+			return
+		}
+
+		matrix, err := buildFlexDateMatrix(ctx, session, req, options, now)
+		if err != nil {
+			log.Printf("Error building flex-date grid %s->%s: %v", req.Origin, req.Destination, err)
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to search flights: " + err.Error()})
+			return
+		}
+
+		// Grids with failed calls are incomplete, so they are not cached.
+		if cacheManager != nil && len(matrix.Warnings) == 0 {
+			if err := cacheManager.SetJSON(ctx, cacheKey, matrix, flexDateCacheTTL); err != nil {
+				log.Printf("Failed to cache flex-date grid: %v", err)
+			}
+		}
+		c.JSON(http.StatusOK, matrix)
+	}
+}
+
+func formatOptionalDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+// buildFlexDateMatrix fills the grid of req. Google's price graph has a fixed trip length, so
+// every diagonal of the grid (cells with the same number of days between departure and return)
+// takes one price graph call. GetOffers then searches the cells the graph left empty and, with
+// the remaining budget, the cheapest cells to find their itinerary; round trips get the return
+// flights of the cheapest offer with FillReturnFlights. An error is returned only when no call
+// succeeded.
+func buildFlexDateMatrix(ctx context.Context, searcher flexDateSearcher, req apitypes.FlexDateSearchRequest, options flights.Options, now time.Time) (*apitypes.FlexDateMatrix, error) {
+	today := truncateDate(now)
+	roundTrip := !req.ReturnDate.IsZero()
+
+	matrix := &apitypes.FlexDateMatrix{
+		Origin:      req.Origin,
+		Destination: req.Destination,
+		Currency:    req.Currency,
+		Cells:       []apitypes.FlexDateCell{},
+	}
+
+	var departures, returns []time.Time
+	for d := -req.DepartureFlexDays; d <= req.DepartureFlexDays; d++ {
+		date := req.DepartureDate.AddDate(0, 0, d)
+		if date.Before(today) {
+			continue
+		}
+		departures = append(departures, date)
+		matrix.DepartureDates = append(matrix.DepartureDates, date.Format(dateLayout))
+	}
+	if roundTrip {
+		for d := -req.ReturnFlexDays; d <= req.ReturnFlexDays; d++ {
+			date := req.ReturnDate.AddDate(0, 0, d)
+			returns = append(returns, date)
+			matrix.ReturnDates = append(matrix.ReturnDates, date.Format(dateLayout))
+		}
+	}
+
+	// tripLengths groups the cells by diagonal; one-way grids are a single "diagonal".
+	type cellRef struct {
+		index     int
+		departure time.Time
+		ret       time.Time
+	}
+	tripLengths := map[int][]cellRef{}
+	for _, departure := range departures {
+		if !roundTrip {
+			tripLengths[0] = append(tripLengths[0], cellRef{index: len(matrix.Cells), departure: departure})
+			matrix.Cells = append(matrix.Cells, apitypes.FlexDateCell{DepartureDate: departure.Format(dateLayout)})
+			continue
+		}
+		for _, ret := range returns {
+			if ret.Before(departure) {
+				continue
+			}
+			length := int(ret.Sub(departure).Hours() / 24)
+			tripLengths[length] = append(tripLengths[length], cellRef{index: len(matrix.Cells), departure: departure, ret: ret})
+			matrix.Cells = append(matrix.Cells, apitypes.FlexDateCell{
+				DepartureDate: departure.Format(dateLayout),
+				ReturnDate:    ret.Format(dateLayout),
+			})
+		}
+	}
+
+	lengths := make([]int, 0, len(tripLengths))
+	for length := range tripLengths {
+		lengths = append(lengths, length)
+	}
+	sort.Ints(lengths)
+
+	succeeded := false
+	var firstErr error
+	for _, length := range lengths {
+		cells := tripLengths[length]
+		graphLength := length
+		if !roundTrip {
+			graphLength = 1 // like BuildPriceGraphArgs; Google ignores it for one-way graphs
+		}
+		args := flights.PriceGraphArgs{
+			RangeStartDate: cells[0].departure,
+			RangeEndDate:   cells[len(cells)-1].departure,
+			TripLength:     graphLength,
+			SrcAirports:    []string{req.Origin},
+			DstAirports:    []string{req.Destination},
+			Options:        options,
+		}
+		offers, _, err := searcher.GetPriceGraph(ctx, args)
+		matrix.PriceGraphCalls++
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			matrix.Warnings = append(matrix.Warnings, fmt.Sprintf("price graph for a %d day trip failed: %v", length, err))
+			continue
+		}
+		succeeded = true
+
+		prices := make(map[string]float64, len(offers))
+		for _, offer := range offers {
+			if offer.Price > 0 {
+				prices[offer.StartDate.Format(dateLayout)] = offer.Price
+			}
+		}
+		for _, ref := range cells {
+			if price, ok := prices[ref.departure.Format(dateLayout)]; ok {
+				cell := &matrix.Cells[ref.index]
+				cell.Price = price
+				cell.PriceGraphPrice = price
+				cell.Source = "price_graph"
+			}
+		}
+	}
+
+	// Cells without a price come first, then the cheapest ones.
+	order := make([]int, len(matrix.Cells))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		pa, pb := matrix.Cells[order[a]].Price, matrix.Cells[order[b]].Price
+		if (pa == 0) != (pb == 0) {
+			return pa == 0
+		}
+		return pa < pb
+	})
+	if len(order) > req.MaxOfferSearches {
+		order = order[:req.MaxOfferSearches]
+	}
+
+	for _, index := range order {
+		cell := &matrix.Cells[index]
+		departure, _ := time.Parse(dateLayout, cell.DepartureDate)
+		args := flights.Args{
+			Date:        departure,
+			SrcAirports: []string{req.Origin},
+			DstAirports: []string{req.Destination},
+			Options:     options,
+		}
+		if roundTrip {
+			args.ReturnDate, _ = time.Parse(dateLayout, cell.ReturnDate)
+		}
+
+		offers, _, err := searcher.GetOffers(ctx, args)
+		matrix.OfferSearches++
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			matrix.Warnings = append(matrix.Warnings, fmt.Sprintf("flight search for %s failed: %v", flexDateCellLabel(*cell), err))
+			continue
+		}
+		succeeded = true
+
+		if cheapest := cheapestFullOffer(offers); cheapest != nil {
+			if roundTrip {
+				filled := []flights.FullOffer{*cheapest}
+				if err := searcher.FillReturnFlights(ctx, args, filled, 1); err != nil {
+					matrix.Warnings = append(matrix.Warnings, fmt.Sprintf("return flights for %s failed: %v", flexDateCellLabel(*cell), err))
+				}
+				cheapest = &filled[0]
+			}
+
+			airlineCodes := []string{}
+			cell.Price = cheapest.Price
+			cell.Source = "offers"
+			cell.Itinerary = &apitypes.FlexDateItinerary{
+				Price:         cheapest.Price,
+				TotalDuration: int(cheapest.FlightDuration.Minutes()),
+				Segments:      convertFlightSegments(cheapest.Flight, &airlineCodes),
+				AirlineCodes:  airlineCodes,
+			}
+			if len(cheapest.ReturnFlight) > 0 {
+				cell.Itinerary.ReturnSegments = convertFlightSegments(cheapest.ReturnFlight, &airlineCodes)
+				cell.Itinerary.ReturnDuration = int(cheapest.ReturnFlightDuration.Minutes())
+				cell.Itinerary.AirlineCodes = airlineCodes
+			}
+		}
+	}
+
+	if !succeeded && firstErr != nil {
+		return nil, firstErr
+	}
+
+	for i := range matrix.Cells {
+		cell := matrix.Cells[i]
+		if cell.Price > 0 && (matrix.Cheapest == nil || cell.Price < matrix.Cheapest.Price) {
+			matrix.Cheapest = &cell
+		}
+	}
+	return matrix, nil
+}
+
+func flexDateCellLabel(cell apitypes.FlexDateCell) string {
+	if cell.ReturnDate == "" {
+		return cell.DepartureDate
+	}
+	return cell.DepartureDate + "/" + cell.ReturnDate
+}
+
+// cheapestFullOffer returns the cheapest offer with a price, or nil.
+func cheapestFullOffer(offers []flights.FullOffer) *flights.FullOffer {
+	var cheapest *flights.FullOffer
+	for i := range offers {
+		if offers[i].Price <= 0 {
+			continue
+		}
+		if cheapest == nil || offers[i].Price < cheapest.Price {
+			cheapest = &offers[i]
+		}
+	}
+	return cheapest
+}
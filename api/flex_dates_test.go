@@ -0,0 +1,216 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFlexDateSearcher prices a day by its date and trip length, and fails where told to.
+type fakeFlexDateSearcher struct {
+	graphCalls   []flights.PriceGraphArgs
+	offerCalls   []flights.Args
+	returnCalls  int
+	missing      map[string]bool // departure/return labels the price graph has no price for
+	graphErr     error
+	offersErr    error
+	offerPrice   float64
+	graphPriceFn func(departure time.Time, tripLength int) float64
+}
+
+func (f *fakeFlexDateSearcher) GetPriceGraph(_ context.Context, args flights.PriceGraphArgs) ([]flights.Offer, *flights.ParseErrors, error) {
+	f.graphCalls = append(f.graphCalls, args)
+	if f.graphErr != nil {
+		return nil, nil, f.graphErr
+	}
+	var offers []flights.Offer
+	for d := args.RangeStartDate; !d.After(args.RangeEndDate); d = d.AddDate(0, 0, 1) {
+		ret := d.AddDate(0, 0, args.TripLength)
+		if f.missing[d.Format(dateLayout)+"/"+ret.Format(dateLayout)] {
+			continue
+		}
+		offers = append(offers, flights.Offer{StartDate: d, ReturnDate: ret, Price: f.graphPriceFn(d, args.TripLength)})
+	}
+	return offers, nil, nil
+}
+
+func (f *fakeFlexDateSearcher) GetOffers(_ context.Context, args flights.Args) ([]flights.FullOffer, *flights.PriceRange, error) {
+	f.offerCalls = append(f.offerCalls, args)
+	if f.offersErr != nil {
+		return nil, nil, f.offersErr
+	}
+	return []flights.FullOffer{
+		{Offer: flights.Offer{StartDate: args.Date, Price: f.offerPrice + 50}},
+		{
+			Offer:          flights.Offer{StartDate: args.Date, Price: f.offerPrice},
+			Flight:         []flights.Flight{{DepAirportCode: "JFK", ArrAirportCode: "LHR", FlightNumber: "BA 112"}},
+			FlightDuration: 7 * time.Hour,
+		},
+	}, nil, nil
+}
+
+func (f *fakeFlexDateSearcher) FillReturnFlights(_ context.Context, args flights.Args, offers []flights.FullOffer, limit int) error {
+	f.returnCalls++
+	for i := range offers {
+		offers[i].ReturnFlight = []flights.Flight{{DepAirportCode: "LHR", ArrAirportCode: "JFK", FlightNumber: "VS 3"}}
+		offers[i].ReturnFlightDuration = 8 * time.Hour
+	}
+	return nil
+}
+
+func flexDateTestRequest(departure, ret time.Time) apitypes.FlexDateSearchRequest {
+	req := apitypes.FlexDateSearchRequest{
+		Origin:            "JFK",
+		Destination:       "LHR",
+		DepartureDate:     apitypes.DateOnly{Time: departure},
+		DepartureFlexDays: 1,
+		ReturnFlexDays:    1,
+		Adults:            1,
+		Class:             "economy",
+		Stops:             "any",
+		Currency:          "USD",
+		MaxOfferSearches:  2,
+	}
+	if !ret.IsZero() {
+		req.ReturnDate = apitypes.DateOnly{Time: ret}
+	}
+	return req
+}
+
+func TestBuildFlexDateMatrix_RoundTrip(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	departure := time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC)
+	searcher := &fakeFlexDateSearcher{
+		missing:    map[string]bool{"2026-06-11/2026-06-16": true},
+		offerPrice: 400,
+		graphPriceFn: func(d time.Time, tripLength int) float64 {
+			// 2026-06-09 with a 9 day trip is the cheapest cell.
+			return float64(500 + 10*d.Day() + tripLength)
+		},
+	}
+
+	matrix, err := buildFlexDateMatrix(context.Background(), searcher, flexDateTestRequest(departure, departure.AddDate(0, 0, 7)), flights.Options{}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-06-09", "2026-06-10", "2026-06-11"}, matrix.DepartureDates)
+	assert.Equal(t, []string{"2026-06-16", "2026-06-17", "2026-06-18"}, matrix.ReturnDates)
+	require.Len(t, matrix.Cells, 9)
+
+	// Trip lengths 5 to 9 days: one price graph per diagonal of the grid.
+	assert.Equal(t, 5, matrix.PriceGraphCalls)
+	require.Len(t, searcher.graphCalls, 5)
+	assert.Equal(t, 5, searcher.graphCalls[0].TripLength)
+	assert.Equal(t, "2026-06-11", searcher.graphCalls[0].RangeStartDate.Format(dateLayout))
+
+	// The cell missing from the price graph is searched first, then the cheapest one.
+	require.Len(t, searcher.offerCalls, 2)
+	assert.Equal(t, "2026-06-11", searcher.offerCalls[0].Date.Format(dateLayout))
+	assert.Equal(t, "2026-06-16", searcher.offerCalls[0].ReturnDate.Format(dateLayout))
+	assert.Equal(t, "2026-06-09", searcher.offerCalls[1].Date.Format(dateLayout))
+	assert.Equal(t, "2026-06-16", searcher.offerCalls[1].ReturnDate.Format(dateLayout))
+
+	missing := matrix.Cells[6]
+	assert.Equal(t, "2026-06-11", missing.DepartureDate)
+	assert.Equal(t, "2026-06-16", missing.ReturnDate)
+	assert.Equal(t, "offers", missing.Source)
+	assert.Equal(t, float64(400), missing.Price)
+	assert.Zero(t, missing.PriceGraphPrice)
+	require.NotNil(t, missing.Itinerary)
+	assert.Equal(t, 420, missing.Itinerary.TotalDuration)
+	require.Len(t, missing.Itinerary.ReturnSegments, 1)
+	assert.Equal(t, "LHR", missing.Itinerary.ReturnSegments[0].DepartureAirport)
+	assert.Equal(t, 480, missing.Itinerary.ReturnDuration)
+	assert.Equal(t, []string{"BA", "VS"}, missing.Itinerary.AirlineCodes)
+	assert.Equal(t, 2, searcher.returnCalls)
+
+	graphOnly := matrix.Cells[4]
+	assert.Equal(t, "price_graph", graphOnly.Source)
+	assert.Equal(t, float64(607), graphOnly.Price)
+	assert.Nil(t, graphOnly.Itinerary)
+
+	require.NotNil(t, matrix.Cheapest)
+	assert.Equal(t, float64(400), matrix.Cheapest.Price)
+	assert.Empty(t, matrix.Warnings)
+}
+
+func TestBuildFlexDateMatrix_OneWaySkipsPastDates(t *testing.T) {
+	now := time.Date(2026, 6, 10, 8, 0, 0, 0, time.UTC)
+	searcher := &fakeFlexDateSearcher{
+		offersErr:    errors.New("blocked"),
+		graphPriceFn: func(d time.Time, _ int) float64 { return float64(100 + d.Day()) },
+	}
+
+	matrix, err := buildFlexDateMatrix(context.Background(), searcher, flexDateTestRequest(now, time.Time{}), flights.Options{}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-06-10", "2026-06-11"}, matrix.DepartureDates)
+	assert.Empty(t, matrix.ReturnDates)
+	require.Len(t, matrix.Cells, 2)
+	assert.Equal(t, 1, matrix.PriceGraphCalls)
+	assert.Equal(t, float64(110), matrix.Cells[0].Price)
+	assert.Equal(t, "price_graph", matrix.Cells[0].Source)
+	assert.Len(t, matrix.Warnings, 2)
+	require.NotNil(t, matrix.Cheapest)
+	assert.Equal(t, "2026-06-10", matrix.Cheapest.DepartureDate)
+}
+
+func TestBuildFlexDateMatrix_AllCallsFail(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	searcher := &fakeFlexDateSearcher{graphErr: flights.ErrRateLimited, offersErr: flights.ErrRateLimited}
+
+	_, err := buildFlexDateMatrix(context.Background(), searcher, flexDateTestRequest(now.AddDate(0, 1, 0), time.Time{}), flights.Options{}, now)
+	assert.ErrorIs(t, err, flights.ErrRateLimited)
+}
+
+func TestFlexDateSearch_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/flex-dates", FlexDateSearch(nil, nil))
+
+	departure := time.Now().AddDate(0, 1, 0)
+	for name, body := range map[string]map[string]interface{}{
+		"too much flexibility": {"departure_flex_days": 4},
+		"return before departure": {
+			"return_date": departure.AddDate(0, 0, -2).Format(dateLayout),
+		},
+		"past dates": {
+			"departure_date":      time.Now().AddDate(0, 0, -10).Format(dateLayout),
+			"departure_flex_days": 3,
+		},
+		"same airports":     {"destination": "JFK"},
+		"too many searches": {"max_offer_searches": 13},
+	} {
+		request := map[string]interface{}{
+			"origin":         "JFK",
+			"destination":    "LHR",
+			"departure_date": departure.Format(dateLayout),
+			"adults":         1,
+			"class":          "economy",
+			"stops":          "any",
+			"currency":       "USD",
+		}
+		for key, value := range body {
+			request[key] = value
+		}
+
+		data, _ := json.Marshal(request)
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/flex-dates", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, name)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,7 +16,7 @@ func GetCheapestPath(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 		origin := c.Query("origin")
 		dest := c.Query("dest")
 		if origin == "" || dest == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "origin and dest query parameters are required"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "origin and dest query parameters are required"})
 			return
 		}
 
@@ -35,20 +36,16 @@ func GetCheapestPath(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 
 		paths, err := neo4jDB.FindCheapestPath(c.Request.Context(), origin, dest, maxHops, maxPrice)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		if paths == nil {
-			paths = []db.PathResult{}
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"origin":   origin,
-			"dest":     dest,
-			"maxHops":  maxHops,
-			"maxPrice": maxPrice,
-			"paths":    paths,
+		c.JSON(http.StatusOK, apitypes.CheapestPathResponse{
+			Origin:   origin,
+			Dest:     dest,
+			MaxHops:  maxHops,
+			MaxPrice: maxPrice,
+			Paths:    convertPaths(paths),
 		})
 	}
 }
@@ -59,7 +56,7 @@ func GetConnections(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Query("origin")
 		if origin == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "origin query parameter is required"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "origin query parameter is required"})
 			return
 		}
 
@@ -79,20 +76,16 @@ func GetConnections(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 
 		connections, err := neo4jDB.FindConnections(c.Request.Context(), origin, maxHops, maxPrice)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		if connections == nil {
-			connections = []db.Connection{}
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"origin":      origin,
-			"maxHops":     maxHops,
-			"maxPrice":    maxPrice,
-			"count":       len(connections),
-			"connections": connections,
+		c.JSON(http.StatusOK, apitypes.ConnectionsResponse{
+			Origin:      origin,
+			MaxHops:     maxHops,
+			MaxPrice:    maxPrice,
+			Count:       len(connections),
+			Connections: convertConnections(connections),
 		})
 	}
 }
@@ -104,21 +97,21 @@ func GetRouteStats(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 		origin := c.Query("origin")
 		dest := c.Query("dest")
 		if origin == "" || dest == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "origin and dest query parameters are required"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "origin and dest query parameters are required"})
 			return
 		}
 
 		stats, err := neo4jDB.GetRouteStats(c.Request.Context(), origin, dest)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		if stats == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "no price data found for this route"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "no price data found for this route"})
 			return
 		}
 
-		c.JSON(http.StatusOK, stats)
+		c.JSON(http.StatusOK, convertRouteStats(stats))
 	}
 }
@@ -3,7 +3,6 @@ package api
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -17,12 +16,14 @@ import (
 	"github.com/gilby125/google-flights-api/config"
 	"github.com/gilby125/google-flights-api/db"
 	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 	"github.com/gilby125/google-flights-api/pkg/buildinfo"
 	"github.com/gilby125/google-flights-api/pkg/cache"
 	"github.com/gilby125/google-flights-api/pkg/logger"
 	"github.com/gilby125/google-flights-api/pkg/macros"
 	"github.com/gilby125/google-flights-api/pkg/middleware"
 	"github.com/gilby125/google-flights-api/pkg/notify"
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/gilby125/google-flights-api/pkg/worker_registry"
 	"github.com/gilby125/google-flights-api/queue"
 	"github.com/gilby125/google-flights-api/worker"
@@ -35,165 +36,13 @@ import (
 
 const dateLayout = "2006-01-02"
 
-// DateOnly parses YYYY-MM-DD or RFC3339 timestamps into a date (time at midnight UTC)
-type DateOnly struct {
-	time.Time
-}
-
-// UnmarshalJSON accepts either YYYY-MM-DD or RFC3339 timestamps
-func (d *DateOnly) UnmarshalJSON(b []byte) error {
-	str := strings.Trim(string(b), "\"")
-	if str == "" || str == "null" {
-		d.Time = time.Time{}
-		return nil
-	}
-
-	layouts := []string{dateLayout, time.RFC3339}
-	var lastErr error
-	for _, layout := range layouts {
-		parsed, err := time.Parse(layout, str)
-		if err == nil {
-			d.Time = parsed
-			return nil
-		}
-		lastErr = err
-	}
-	return fmt.Errorf("invalid date format %q: %w", str, lastErr)
-}
-
-// MarshalJSON renders dates as YYYY-MM-DD; zero values emit null
-func (d DateOnly) MarshalJSON() ([]byte, error) {
-	if d.Time.IsZero() {
-		return []byte("null"), nil
-	}
-	return []byte(fmt.Sprintf("\"%s\"", d.Time.Format(dateLayout))), nil
-}
-
-// SearchRequest represents a flight search request
-type SearchRequest struct {
-	Origin        string   `json:"origin" binding:"required"`
-	Destination   string   `json:"destination" binding:"required"`
-	DepartureDate DateOnly `json:"departure_date" binding:"required"`
-	ReturnDate    DateOnly `json:"return_date,omitempty"`
-	Adults        int      `json:"adults" binding:"required,min=1"`
-	Children      int      `json:"children" binding:"min=0"`
-	InfantsLap    int      `json:"infants_lap" binding:"min=0"`
-	InfantsSeat   int      `json:"infants_seat" binding:"min=0"`
-	TripType      string   `json:"trip_type" binding:"required,oneof=one_way round_trip"`
-	Class         string   `json:"class" binding:"required,oneof=economy premium_economy business first"`
-	Stops         string   `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"` // Added two_stops_plus
-	Currency      string   `json:"currency" binding:"required,len=3"`
-}
-
-// BulkSearchRequest represents a bulk flight search request
-type BulkSearchRequest struct {
-	Origins           []string `json:"origins" binding:"required,min=1"`
-	Destinations      []string `json:"destinations" binding:"required,min=1"`
-	DepartureDateFrom DateOnly `json:"departure_date_from" binding:"required"`
-	DepartureDateTo   DateOnly `json:"departure_date_to" binding:"required"`
-	ReturnDateFrom    DateOnly `json:"return_date_from,omitempty"`
-	ReturnDateTo      DateOnly `json:"return_date_to,omitempty"`
-	TripLength        int      `json:"trip_length,omitempty" binding:"min=0"`
-	Adults            int      `json:"adults" binding:"required,min=1"`
-	Children          int      `json:"children" binding:"min=0"`
-	InfantsLap        int      `json:"infants_lap" binding:"min=0"`
-	InfantsSeat       int      `json:"infants_seat" binding:"min=0"`
-	TripType          string   `json:"trip_type" binding:"required,oneof=one_way round_trip"`
-	Class             string   `json:"class" binding:"required,oneof=economy premium_economy business first"`
-	Stops             string   `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"`
-	Currency          string   `json:"currency" binding:"required,len=3"`
-	Carriers          []string `json:"carriers,omitempty"`
-}
-
-// JobRequest represents a scheduled job request
-type JobRequest struct {
-	Name              string `json:"name" binding:"required"`
-	Origin            string `json:"origin" binding:"required"`
-	Destination       string `json:"destination" binding:"required"`
-	DateStart         string `json:"date_start" binding:"required"`
-	DateEnd           string `json:"date_end" binding:"required"`
-	ReturnDateStart   string `json:"return_date_start,omitempty"`
-	ReturnDateEnd     string `json:"return_date_end,omitempty"`
-	TripLength        int    `json:"trip_length,omitempty" binding:"min=0"`
-	DynamicDates      bool   `json:"dynamic_dates,omitempty"`       // Use dates relative to execution time
-	DaysFromExecution int    `json:"days_from_execution,omitempty"` // Start searching X days from now
-	SearchWindowDays  int    `json:"search_window_days,omitempty"`  // Search within X days window
-	Adults            int    `json:"adults" binding:"required,min=1"`
-	Children          int    `json:"children" binding:"min=0"`
-	InfantsLap        int    `json:"infants_lap" binding:"min=0"`
-	InfantsSeat       int    `json:"infants_seat" binding:"min=0"`
-	TripType          string `json:"trip_type" binding:"required,oneof=one_way round_trip"`
-	Class             string `json:"class" binding:"required,oneof=economy premium_economy business first"`
-	Stops             string `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"`
-	Currency          string `json:"currency" binding:"required,len=3"`
-	Interval          string `json:"interval" binding:"required"`
-	Time              string `json:"time" binding:"required"`
-	CronExpression    string `json:"cron_expression" binding:"required"`
-}
-
-// BulkJobRequest represents a request to create a scheduled bulk search job
-type BulkJobRequest struct {
-	Name              string   `json:"name" binding:"required"`
-	Origins           []string `json:"origins" binding:"required,min=1"`
-	Destinations      []string `json:"destinations" binding:"required,min=1"`
-	DateStart         string   `json:"date_start" binding:"required"`
-	DateEnd           string   `json:"date_end" binding:"required"`
-	ReturnDateStart   string   `json:"return_date_start,omitempty"`
-	ReturnDateEnd     string   `json:"return_date_end,omitempty"`
-	TripLength        int      `json:"trip_length,omitempty" binding:"min=0"`
-	DynamicDates      bool     `json:"dynamic_dates,omitempty"`       // Use dates relative to execution time
-	DaysFromExecution int      `json:"days_from_execution,omitempty"` // Start searching X days from now
-	SearchWindowDays  int      `json:"search_window_days,omitempty"`  // Search within X days window
-	Adults            int      `json:"adults" binding:"required,min=1"`
-	Children          int      `json:"children" binding:"min=0"`
-	InfantsLap        int      `json:"infants_lap" binding:"min=0"`
-	InfantsSeat       int      `json:"infants_seat" binding:"min=0"`
-	TripType          string   `json:"trip_type" binding:"required,oneof=one_way round_trip"`
-	Class             string   `json:"class" binding:"required,oneof=economy premium_economy business first"`
-	Stops             string   `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"`
-	Currency          string   `json:"currency" binding:"required,len=3"`
-	CronExpression    string   `json:"cron_expression" binding:"required"`
-}
-
-// PriceGraphSweepRequest represents a request to enqueue a price graph sweep
-type PriceGraphSweepRequest struct {
-	Origins           []string `json:"origins" binding:"required,min=1"`
-	Destinations      []string `json:"destinations" binding:"required,min=1"`
-	DepartureDateFrom DateOnly `json:"departure_date_from" binding:"required"`
-	DepartureDateTo   DateOnly `json:"departure_date_to" binding:"required"`
-	TripLengths       []int    `json:"trip_lengths,omitempty"`
-	TripType          string   `json:"trip_type" binding:"required,oneof=one_way round_trip"`
-	// Class is kept for backward compatibility; prefer Classes for multi-cabin sweeps.
-	Class           string   `json:"class,omitempty"`
-	Classes         []string `json:"classes,omitempty"`
-	Stops           string   `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"`
-	Adults          int      `json:"adults" binding:"required,min=1"`
-	Children        int      `json:"children" binding:"min=0"`
-	InfantsLap      int      `json:"infants_lap" binding:"min=0"`
-	InfantsSeat     int      `json:"infants_seat" binding:"min=0"`
-	Currency        string   `json:"currency" binding:"required,len=3"`
-	RateLimitMillis int      `json:"rate_limit_millis,omitempty" binding:"min=0"`
-}
+const idempotencyKeyHeader = "Idempotency-Key"
 
-func maybeNullInt(value sql.NullInt32) interface{} {
-	if !value.Valid {
-		return nil
-	}
-	return value.Int32
-}
-
-func maybeNullTime(value sql.NullTime) interface{} {
-	if !value.Valid {
-		return nil
-	}
-	return value.Time
-}
-
-func maybeNullString(value sql.NullString) interface{} {
-	if !value.Valid {
-		return nil
-	}
-	return value.String
+// idempotentContext returns the request context with the Idempotency-Key header of the request,
+// so a retried request gets the job of the first one. It is only used for the enqueue a request
+// is about; other jobs enqueued on its behalf keep their own keys.
+func idempotentContext(c *gin.Context) context.Context {
+	return queue.WithIdempotencyKey(c.Request.Context(), c.GetHeader(idempotencyKeyHeader))
 }
 
 func normalizePriceGraphSweepClasses(legacyClass string, classes []string) ([]string, error) {
@@ -297,7 +146,7 @@ func GetAirports(pgDB db.PostgresDB) gin.HandlerFunc {
 				"duration":   time.Since(start),
 				"ctx_err":    ctx.Err(),
 			}).Error(err, "QueryAirports failed")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query airports"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to query airports"})
 			return
 		}
 		defer rows.Close()
@@ -330,7 +179,7 @@ func GetAirports(pgDB db.PostgresDB) gin.HandlerFunc {
 					"rows_read":  len(airports),
 					"ctx_err":    ctx.Err(),
 				}).Error(err, "GetAirports scan failed")
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan airport row"})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan airport row"})
 				return
 			}
 			airports = append(airports, airport)
@@ -358,26 +207,20 @@ func GetAirports(pgDB db.PostgresDB) gin.HandlerFunc {
 				"duration":   time.Since(start),
 				"rows_read":  len(airports),
 			}).Error(err, "GetAirports rows iteration failed")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating airport rows"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating airport rows"})
 			return
 		}
 
-		// Convert to map[string]interface{} for JSON response to handle nulls gracefully
-		response := []map[string]interface{}{}
+		response := make([]apitypes.AirportResponse, 0, len(airports))
 		for _, airport := range airports {
-			airportMap := map[string]interface{}{
-				"code":    airport.Code,
-				"name":    airport.Name,
-				"city":    airport.City,
-				"country": airport.Country,
-			}
-			if airport.Latitude.Valid {
-				airportMap["latitude"] = airport.Latitude.Float64
-			}
-			if airport.Longitude.Valid {
-				airportMap["longitude"] = airport.Longitude.Float64
-			}
-			response = append(response, airportMap)
+			response = append(response, apitypes.AirportResponse{
+				Code:      airport.Code,
+				Name:      airport.Name,
+				City:      airport.City,
+				Country:   airport.Country,
+				Latitude:  maybeNullFloat(airport.Latitude),
+				Longitude: maybeNullFloat(airport.Longitude),
+			})
 		}
 
 		c.JSON(http.StatusOK, response)
@@ -419,22 +262,22 @@ func GetAirlines(pgDB db.PostgresDB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		rows, err := pgDB.QueryAirlines(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query airlines"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to query airlines"})
 			return
 		}
 		defer rows.Close()
 
-		airlines := []db.Airline{} // Use the defined struct
+		airlines := []apitypes.AirlineResponse{}
 		for rows.Next() {
-			var airline db.Airline
+			var airline apitypes.AirlineResponse
 			if err := rows.Scan(&airline.Code, &airline.Name, &airline.Country); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan airline row"})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan airline row"})
 				return
 			}
 			airlines = append(airlines, airline)
 		}
 		if err := rows.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating airline rows"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating airline rows"})
 			return
 		}
 
@@ -445,9 +288,9 @@ func GetAirlines(pgDB db.PostgresDB) gin.HandlerFunc {
 // CreateSearch returns a handler for creating a new flight search
 func CreateSearch(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var req SearchRequest
+		var req apitypes.SearchRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
@@ -455,45 +298,65 @@ func CreateSearch(q queue.Queue) gin.HandlerFunc {
 		iataRegex := regexp.MustCompile(`^[A-Z]{3}$`)     // Simple IATA check
 		currencyRegex := regexp.MustCompile(`^[A-Z]{3}$`) // Simple Currency check
 
+		var segments []worker.SearchSegment
+		if req.TripType == "multi_city" {
+			var err error
+			segments, err = validateSearchSegments(req.Segments, time.Now())
+			if err != nil {
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+				return
+			}
+			if !req.ReturnDate.IsZero() {
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Return date should not be provided for multi-city trips"})
+				return
+			}
+			req.Origin = segments[0].Origin
+			req.Destination = segments[len(segments)-1].Destination
+			req.DepartureDate = apitypes.DateOnly{Time: segments[0].DepartureDate}
+		} else if len(req.Segments) > 0 {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Segments are only supported for multi-city trips"})
+			return
+		}
+
 		// Validate IATA codes format
 		if !iataRegex.MatchString(req.Origin) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid origin airport code format"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid origin airport code format"})
 			return
 		}
 		if !iataRegex.MatchString(req.Destination) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid destination airport code format"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid destination airport code format"})
 			return
 		}
 		// Validate Currency format
 		req.Currency = strings.ToUpper(req.Currency) // Standardize before check
 		if !currencyRegex.MatchString(req.Currency) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid currency code format"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid currency code format"})
 			return
 		}
 		// Validate dates
 		now := time.Now().Truncate(24 * time.Hour) // Compare dates only
 		if req.DepartureDate.Before(now) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Departure date must be in the future"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Departure date must be in the future"})
 			return
 		}
 		if req.TripType == "round_trip" {
 			if req.ReturnDate.IsZero() {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Return date is required for round trips"})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Return date is required for round trips"})
 				return
 			}
 			if !req.ReturnDate.Time.After(req.DepartureDate.Time) {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Return date must be after departure date"})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Return date must be after departure date"})
 				return
 			}
-		} else { // one_way
+		} else if req.TripType == "one_way" {
 			// Ensure return date is zero/empty for one-way trips
 			if !req.ReturnDate.IsZero() {
 				// Return error if user provided a return date for a one-way trip
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Return date should not be provided for one-way trips"})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Return date should not be provided for one-way trips"})
 				return
 			}
 			// Ensure ReturnDate is zeroed in the payload for one-way
-			req.ReturnDate = DateOnly{}
+			req.ReturnDate = apitypes.DateOnly{}
 		}
 		// --- End Custom Validation ---
 
@@ -511,20 +374,21 @@ func CreateSearch(q queue.Queue) gin.HandlerFunc {
 			Class:         req.Class,
 			Stops:         req.Stops,
 			Currency:      req.Currency, // Already uppercased
+			Segments:      segments,
 		}
 
 		// Enqueue the job
-		jobID, err := q.Enqueue(c.Request.Context(), "flight_search", payload)
+		jobID, err := q.Enqueue(idempotentContext(c), "flight_search", payload)
 		if err != nil {
 			// Log the internal error? Consider adding logging here.
 			// log.Printf("Error enqueuing job: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create search job"}) // Generic error for client
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to create search job"}) // Generic error for client
 			return
 		}
 
-		c.JSON(http.StatusAccepted, gin.H{
-			"job_id":  jobID,
-			"message": "Flight search job created successfully",
+		c.JSON(http.StatusAccepted, apitypes.JobAcceptedResponse{
+			JobID:   jobID,
+			Message: "Flight search job created successfully",
 		})
 	}
 }
@@ -535,7 +399,7 @@ func GetSearchByID(pgDB db.PostgresDB) gin.HandlerFunc {
 		id := c.Param("id")
 		searchID, err := strconv.Atoi(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid search ID"})
 			return
 		}
 
@@ -544,9 +408,9 @@ func GetSearchByID(pgDB db.PostgresDB) gin.HandlerFunc {
 		if err != nil {
 			// TODO: Check for specific not found error type if defined in db package
 			if strings.Contains(err.Error(), "not found") {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Search not found"})
+				c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Search not found"})
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get search query"})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get search query"})
 			}
 			return
 		}
@@ -554,23 +418,23 @@ func GetSearchByID(pgDB db.PostgresDB) gin.HandlerFunc {
 		// Get the flight offers
 		offerRows, err := pgDB.GetFlightOffersBySearchID(c.Request.Context(), searchID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get flight offers"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get flight offers"})
 			return
 		}
 		defer offerRows.Close()
 
-		offers := []map[string]interface{}{}
+		offers := []apitypes.SearchOffer{}
 		for offerRows.Next() {
 			var offer db.FlightOffer // Use the defined struct
 			if err := offerRows.Scan(&offer.ID, &offer.Price, &offer.Currency, &offer.AirlineCodes, &offer.OutboundDuration, &offer.OutboundStops, &offer.ReturnDuration, &offer.ReturnStops, &offer.CreatedAt); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan flight offer: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan flight offer: " + err.Error()})
 				return
 			}
 
 			// Get the flight segments for this offer
 			segmentRows, err := pgDB.GetFlightSegmentsByOfferID(c.Request.Context(), offer.ID)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get flight segments"})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get flight segments"})
 				return
 			}
 
@@ -581,9 +445,10 @@ func GetSearchByID(pgDB db.PostgresDB) gin.HandlerFunc {
 					&segment.AirlineCode, &segment.FlightNumber, &segment.DepartureAirport,
 					&segment.ArrivalAirport, &segment.DepartureTime, &segment.ArrivalTime,
 					&segment.Duration, &segment.Airplane, &segment.Legroom, &segment.IsReturn,
+					&segment.SegmentIndex,
 				); err != nil {
 					segmentRows.Close()
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan flight segment"})
+					c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan flight segment"})
 					return
 				}
 				segments = append(segments, segment)
@@ -591,53 +456,54 @@ func GetSearchByID(pgDB db.PostgresDB) gin.HandlerFunc {
 			segmentErr := segmentRows.Err()
 			segmentRows.Close() // Close immediately after use, not via defer
 			if segmentErr != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating flight segments"})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating flight segments"})
 				return
 			}
 
-			offerMap := map[string]interface{}{
-				"id":         offer.ID,
-				"price":      offer.Price,
-				"currency":   offer.Currency,
-				"created_at": offer.CreatedAt,
-				"segments":   segments, // Use the struct slice directly
-			}
-
-			if offer.AirlineCodes.Valid {
-				offerMap["airline_codes"] = offer.AirlineCodes.String
-			}
-			if offer.OutboundDuration.Valid {
-				offerMap["outbound_duration"] = offer.OutboundDuration.Int64
-			}
-			if offer.OutboundStops.Valid {
-				offerMap["outbound_stops"] = offer.OutboundStops.Int64
-			}
-			if offer.ReturnDuration.Valid {
-				offerMap["return_duration"] = offer.ReturnDuration.Int64
-			}
-			if offer.ReturnStops.Valid {
-				offerMap["return_stops"] = offer.ReturnStops.Int64
-			}
-
-			offers = append(offers, offerMap)
+			offers = append(offers, apitypes.SearchOffer{
+				ID:               offer.ID,
+				Price:            offer.Price,
+				Currency:         offer.Currency,
+				CreatedAt:        offer.CreatedAt,
+				Segments:         convertSearchOfferSegments(segments),
+				AirlineCodes:     offer.AirlineCodes.String,
+				OutboundDuration: maybeNullInt64(offer.OutboundDuration),
+				OutboundStops:    maybeNullInt64(offer.OutboundStops),
+				ReturnDuration:   maybeNullInt64(offer.ReturnDuration),
+				ReturnStops:      maybeNullInt64(offer.ReturnStops),
+			})
 		}
 		if err := offerRows.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating flight offers: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating flight offers: " + err.Error()})
 			return
 		}
 
-		result := map[string]interface{}{
-			"id":             query.ID,
-			"origin":         query.Origin,
-			"destination":    query.Destination,
-			"departure_date": query.DepartureDate,
-			"status":         query.Status,
-			"created_at":     query.CreatedAt,
-			"offers":         offers,
+		result := apitypes.SearchResponse{
+			ID:            query.ID,
+			Origin:        query.Origin,
+			Destination:   query.Destination,
+			DepartureDate: query.DepartureDate,
+			ReturnDate:    maybeNullTime(query.ReturnDate),
+			Status:        query.Status,
+			CreatedAt:     query.CreatedAt,
+			Offers:        offers,
 		}
-
-		if query.ReturnDate.Valid {
-			result["return_date"] = query.ReturnDate.Time
+		if query.TripType == "multi_city" {
+			querySegments, err := pgDB.ListSearchQuerySegments(c.Request.Context(), searchID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get search segments"})
+				return
+			}
+			requested := make([]apitypes.SearchSegmentResponse, 0, len(querySegments))
+			for _, segment := range querySegments {
+				requested = append(requested, apitypes.SearchSegmentResponse{
+					Origin:        segment.Origin,
+					Destination:   segment.Destination,
+					DepartureDate: segment.DepartureDate.Format(dateLayout),
+				})
+			}
+			result.TripType = query.TripType
+			result.Segments = requested
 		}
 
 		c.JSON(http.StatusOK, result)
@@ -661,52 +527,47 @@ func ListSearches(pgDB db.PostgresDB) gin.HandlerFunc { // Changed parameter typ
 		// Get the total count
 		total, err := pgDB.CountSearches(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count searches: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to count searches: " + err.Error()})
 			return
 		}
 
 		// Get the search queries
 		rows, err := pgDB.QuerySearchesPaginated(c.Request.Context(), perPage, offset)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query searches: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to query searches: " + err.Error()})
 			return
 		}
 		defer rows.Close()
 
-		queries := []map[string]interface{}{}
+		queries := []apitypes.SearchResponse{}
 		for rows.Next() {
 			var query db.SearchQuery // Use the defined struct
 			if err := rows.Scan(&query.ID, &query.Origin, &query.Destination, &query.DepartureDate, &query.ReturnDate, &query.Status, &query.CreatedAt); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan search query: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan search query: " + err.Error()})
 				return
 			}
 
-			queryMap := map[string]interface{}{
-				"id":             query.ID,
-				"origin":         query.Origin,
-				"destination":    query.Destination,
-				"departure_date": query.DepartureDate,
-				"status":         query.Status,
-				"created_at":     query.CreatedAt,
-			}
-
-			if query.ReturnDate.Valid {
-				queryMap["return_date"] = query.ReturnDate.Time
-			}
-
-			queries = append(queries, queryMap)
+			queries = append(queries, apitypes.SearchResponse{
+				ID:            query.ID,
+				Origin:        query.Origin,
+				Destination:   query.Destination,
+				DepartureDate: query.DepartureDate,
+				ReturnDate:    maybeNullTime(query.ReturnDate),
+				Status:        query.Status,
+				CreatedAt:     query.CreatedAt,
+			})
 		}
 		if err := rows.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating search queries: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating search queries: " + err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"total":       total,
-			"page":        page,
-			"per_page":    perPage,
-			"total_pages": (total + perPage - 1) / perPage,
-			"data":        queries,
+		c.JSON(http.StatusOK, apitypes.SearchListResponse{
+			Total:      total,
+			Page:       page,
+			PerPage:    perPage,
+			TotalPages: (total + perPage - 1) / perPage,
+			Data:       queries,
 		})
 	}
 }
@@ -717,14 +578,14 @@ func DeleteJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		id := c.Param("id")
 		jobID, err := strconv.Atoi(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid job ID"})
 			return
 		}
 
 		// Begin a transaction
 		tx, err := pgDB.BeginTx(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to begin transaction: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to begin transaction: " + err.Error()})
 			return
 		}
 		defer tx.Rollback() // Ensure rollback on error
@@ -733,26 +594,26 @@ func DeleteJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		ctx := c.Request.Context()
 		err = pgDB.DeleteJobDetailsByJobID(ctx, tx, jobID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job details"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to delete job details"})
 			return
 		}
 
 		// Delete the job
 		rowsAffected, err := pgDB.DeleteScheduledJobByID(ctx, tx, jobID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete scheduled job"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to delete scheduled job"})
 			return
 		}
 
 		// Check if the job was found
 		if rowsAffected == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Job not found"})
 			return
 		}
 
 		// Commit the transaction
 		if err := tx.Commit(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to commit transaction: " + err.Error()})
 			return
 		}
 
@@ -760,7 +621,7 @@ func DeleteJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		// scheduler := workerManager.GetScheduler()
 		// scheduler.RemoveJob(jobID)
 
-		c.JSON(http.StatusOK, gin.H{"message": "Job deleted successfully"})
+		c.JSON(http.StatusOK, apitypes.MessageResponse{Message: "Job deleted successfully"})
 	}
 }
 
@@ -770,7 +631,7 @@ func runJob(q queue.Queue, pgDB db.PostgresDB) gin.HandlerFunc { // Changed para
 		id := c.Param("id")
 		jobID, err := strconv.Atoi(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid job ID"})
 			return
 		}
 
@@ -779,57 +640,33 @@ func runJob(q queue.Queue, pgDB db.PostgresDB) gin.HandlerFunc { // Changed para
 		if err != nil {
 			// TODO: Check for specific not found error type
 			if strings.Contains(err.Error(), "not found") {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+				c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Job not found"})
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job details: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get job details: " + err.Error()})
 			}
 			return
 		}
 
-		// Create a bulk search payload
-		payload := worker.BulkSearchPayload{
-			Origins:           []string{details.Origin},
-			Destinations:      []string{details.Destination},
-			DepartureDateFrom: details.DepartureDateStart,
-			DepartureDateTo:   details.DepartureDateEnd,
-			Adults:            details.Adults,
-			Children:          details.Children,
-			InfantsLap:        details.InfantsLap,
-			InfantsSeat:       details.InfantsSeat,
-			TripType:          details.TripType,
-			Class:             details.Class,    // Pass the original string
-			Stops:             details.Stops,    // Pass the original string
-			Currency:          details.Currency, // Use currency from details
-		}
-
-		// Add optional fields if present
-		if details.ReturnDateStart.Valid {
-			payload.ReturnDateFrom = details.ReturnDateStart.Time
-		}
-		if details.ReturnDateEnd.Valid {
-			payload.ReturnDateTo = details.ReturnDateEnd.Time
-		}
-		if details.TripLength.Valid {
-			payload.TripLength = int(details.TripLength.Int32)
-		}
+		// Same search as a scheduler tick of the job, so that the two collapse into one job
+		payload := worker.BulkSearchPayloadForJob(jobID, details, time.Now())
 
 		// Enqueue the job
-		jobQueueID, err := q.Enqueue(c.Request.Context(), "bulk_search", payload)
+		jobQueueID, err := q.Enqueue(idempotentContext(c), "bulk_search", payload)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue job: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to enqueue job: " + err.Error()})
 			return
 		}
 
 		// Update the last run time
 		err = pgDB.UpdateJobLastRun(c.Request.Context(), jobID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job last run time: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to update job last run time: " + err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusAccepted, gin.H{
-			"job_id":  jobQueueID,
-			"message": "Job triggered successfully",
+		c.JSON(http.StatusAccepted, apitypes.JobAcceptedResponse{
+			JobID:   jobQueueID,
+			Message: "Job triggered successfully",
 		})
 	}
 }
@@ -840,26 +677,26 @@ func enableJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		id := c.Param("id")
 		jobID, err := strconv.Atoi(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid job ID"})
 			return
 		}
 
 		rowsAffected, err := pgDB.UpdateJobEnabled(c.Request.Context(), jobID, true)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable job: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to enable job: " + err.Error()})
 			return
 		}
 
 		// Check if the job was found
 		if rowsAffected == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Job not found"})
 			return
 		}
 
 		// Get the job's cron expression - Removed as cronExpr is not used below
 		// cronExpr, err := pgDB.GetJobCronExpression(c.Request.Context(), jobID)
 		// if err != nil {
-		// 	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job cron expression: " + err.Error()})
+		// 	c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get job cron expression: " + err.Error()})
 		// 	// Consider rolling back the enable status or logging a warning
 		// 	return
 		// }
@@ -870,7 +707,7 @@ func enableJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		// scheduler := workerManager.GetScheduler()
 		// if err := scheduler.AddJob(...); err != nil { ... }
 
-		c.JSON(http.StatusOK, gin.H{"message": "Job enabled successfully"})
+		c.JSON(http.StatusOK, apitypes.MessageResponse{Message: "Job enabled successfully"})
 	}
 }
 
@@ -880,19 +717,19 @@ func disableJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFu
 		id := c.Param("id")
 		jobID, err := strconv.Atoi(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid job ID"})
 			return
 		}
 
 		rowsAffected, err := pgDB.UpdateJobEnabled(c.Request.Context(), jobID, false)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable job: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to disable job: " + err.Error()})
 			return
 		}
 
 		// Check if the job was found
 		if rowsAffected == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Job not found"})
 			return
 		}
 
@@ -900,35 +737,21 @@ func disableJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFu
 		// scheduler := workerManager.GetScheduler()
 		// scheduler.RemoveJob(jobID)
 
-		c.JSON(http.StatusOK, gin.H{"message": "Job disabled successfully"})
+		c.JSON(http.StatusOK, apitypes.MessageResponse{Message: "Job disabled successfully"})
 	}
 }
 
-// getWorkerStatus returns a handler for getting worker status
-type workerStatusResponse struct {
-	ID                  any    `json:"id"`
-	Status              string `json:"status"`
-	CurrentJob          string `json:"current_job,omitempty"`
-	ProcessedJobs       int    `json:"processed_jobs"`
-	Uptime              int64  `json:"uptime"`
-	Source              string `json:"source,omitempty"`
-	Hostname            string `json:"hostname,omitempty"`
-	Concurrency         int    `json:"concurrency,omitempty"`
-	HeartbeatAgeSeconds int64  `json:"heartbeat_age_seconds,omitempty"`
-	Version             string `json:"version,omitempty"`
-}
-
 // GetWorkerStatus returns a handler for getting worker status.
 // It combines local in-process worker goroutines with remote worker instances (via Redis heartbeats).
 func GetWorkerStatus(workerManager WorkerStatusProvider, redisClient *redis.Client, cfg config.WorkerConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		out := make([]workerStatusResponse, 0)
+		out := make([]apitypes.WorkerStatusResponse, 0)
 
 		// Local worker goroutines (in this process)
 		if workerManager != nil {
 			statuses := workerManager.WorkerStatuses()
 			for _, s := range statuses {
-				out = append(out, workerStatusResponse{
+				out = append(out, apitypes.WorkerStatusResponse{
 					ID:            s.ID,
 					Status:        s.Status,
 					CurrentJob:    s.CurrentJob,
@@ -972,7 +795,7 @@ func GetWorkerStatus(workerManager WorkerStatusProvider, redisClient *redis.Clie
 						}
 					}
 
-					out = append(out, workerStatusResponse{
+					out = append(out, apitypes.WorkerStatusResponse{
 						ID:                  hb.ID,
 						Status:              hb.Status,
 						CurrentJob:          hb.CurrentJob,
@@ -998,7 +821,7 @@ func getBulkSearchById(pgDB db.PostgresDB) gin.HandlerFunc { // Changed paramete
 		id := c.Param("id")
 		searchID, err := strconv.Atoi(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bulk search ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid bulk search ID"})
 			return
 		}
 
@@ -1007,9 +830,9 @@ func getBulkSearchById(pgDB db.PostgresDB) gin.HandlerFunc { // Changed paramete
 		if err != nil {
 			// TODO: Check for specific not found error type
 			if strings.Contains(err.Error(), "not found") {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Bulk search not found"})
+				c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Bulk search not found"})
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get bulk search metadata"})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get bulk search metadata"})
 			}
 			return
 		}
@@ -1027,7 +850,7 @@ func getBulkSearchById(pgDB db.PostgresDB) gin.HandlerFunc { // Changed paramete
 
 		rows, err := pgDB.QueryBulkSearchResultsPaginated(c.Request.Context(), searchID, perPage, offset)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query bulk search results"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to query bulk search results"})
 			return
 		}
 		defer rows.Close()
@@ -1035,6 +858,7 @@ func getBulkSearchById(pgDB db.PostgresDB) gin.HandlerFunc { // Changed paramete
 		results := []db.BulkSearchResult{} // Use the defined struct
 		for rows.Next() {
 			var res db.BulkSearchResult
+			var segmentFlights []byte
 			err := rows.Scan(
 				&res.Origin,
 				&res.Destination,
@@ -1053,74 +877,47 @@ func getBulkSearchById(pgDB db.PostgresDB) gin.HandlerFunc { // Changed paramete
 				&res.OutboundFlights,
 				&res.ReturnFlights,
 				&res.OfferJSON,
+				&segmentFlights,
 			)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan bulk search result: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan bulk search result: " + err.Error()})
 				return
 			}
+			res.SegmentFlights = segmentFlights
 			results = append(results, res)
 		}
 		if err := rows.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating bulk search results: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating bulk search results: " + err.Error()})
 			return
 		}
 
-		// Convert results to map[string]interface{} for JSON response to handle nulls
-		resultsMap := []map[string]interface{}{}
+		resultsMap := make([]apitypes.BulkSearchResultResponse, 0, len(results))
 		for _, res := range results {
-			resultMap := map[string]interface{}{
-				"origin":         res.Origin,
-				"destination":    res.Destination,
-				"departure_date": res.DepartureDate,
-				"price":          res.Price,
-				"currency":       res.Currency,
-				"airline_code":   res.AirlineCode,
-				"duration":       res.Duration,
-			}
-			if res.ReturnDate.Valid {
-				resultMap["return_date"] = res.ReturnDate.Time
-			}
-			resultsMap = append(resultsMap, resultMap)
-		}
-
-		var jobID interface{}
-		if search.JobID.Valid {
-			jobID = search.JobID.Int32
-		}
-
-		response := map[string]interface{}{
-			"id":             search.ID,
-			"job_id":         jobID,
-			"status":         search.Status,
-			"total_searches": search.TotalSearches,
-			"completed":      search.Completed,
-			"total_offers":   search.TotalOffers,
-			"error_count":    search.ErrorCount,
-			"created_at":     search.CreatedAt,
-			"updated_at":     search.UpdatedAt,
-			"results":        resultsMap, // Use the converted map
-			"pagination": map[string]interface{}{
-				"page":     page,
-				"per_page": perPage,
+			resultsMap = append(resultsMap, convertBulkSearchResult(res))
+		}
+
+		c.JSON(http.StatusOK, apitypes.BulkSearchResponse{
+			ID:            search.ID,
+			JobID:         maybeNullInt(search.JobID),
+			Status:        search.Status,
+			TotalSearches: search.TotalSearches,
+			Completed:     search.Completed,
+			TotalOffers:   search.TotalOffers,
+			ErrorCount:    search.ErrorCount,
+			CreatedAt:     search.CreatedAt,
+			UpdatedAt:     search.UpdatedAt,
+			CompletedAt:   maybeNullTime(search.CompletedAt),
+			MinPrice:      maybeNullFloat(search.MinPrice),
+			MaxPrice:      maybeNullFloat(search.MaxPrice),
+			AveragePrice:  maybeNullFloat(search.AveragePrice),
+			Results:       resultsMap,
+			Pagination: apitypes.PaginationResponse{
+				Page:    page,
+				PerPage: perPage,
 				// Ensure TotalSearches is used for total pages calculation
-				"total_pages": (search.TotalSearches + perPage - 1) / perPage,
+				TotalPages: (search.TotalSearches + perPage - 1) / perPage,
 			},
-		}
-
-		if search.CompletedAt.Valid {
-			response["completed_at"] = search.CompletedAt.Time
-		}
-		if search.MinPrice.Valid {
-			response["min_price"] = search.MinPrice.Float64
-		}
-		if search.MaxPrice.Valid {
-			response["max_price"] = search.MaxPrice.Float64
-		}
-		if search.AveragePrice.Valid {
-			response["average_price"] = search.AveragePrice.Float64
-		}
-
-		c.JSON(http.StatusOK, response)
+		})
 	}
 }
 
@@ -1138,7 +935,7 @@ func getPriceHistory(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 		if len(includeGroups) > 0 {
 			codes, _, err := macros.ExpandAirlineTokens(includeGroups)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid include_airline_groups: " + err.Error()})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid include_airline_groups: " + err.Error()})
 				return
 			}
 			for _, code := range codes {
@@ -1150,7 +947,7 @@ func getPriceHistory(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 		if len(excludeGroups) > 0 {
 			codes, _, err := macros.ExpandAirlineTokens(excludeGroups)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exclude_airline_groups: " + err.Error()})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid exclude_airline_groups: " + err.Error()})
 				return
 			}
 			for _, code := range codes {
@@ -1167,12 +964,12 @@ func getPriceHistory(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 
 		result, err := neo4jDB.ExecuteReadQuery(ctx, query, params)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query price history"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to query price history"})
 			return
 		}
 		defer result.Close() // Ensure session is closed when done
 
-		priceHistory := []map[string]interface{}{}
+		priceHistory := []apitypes.PricePoint{}
 		for result.Next() {
 			record := result.Record()
 			date, _ := record.Get("date")
@@ -1193,31 +990,35 @@ func getPriceHistory(neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
 				continue
 			}
 
-			var dateVal interface{}
-			if dt, ok := date.(neo4j.Date); ok {
-				dateVal = dt.Time() // Convert neo4j.Date to time.Time
-			} else {
-				dateVal = date
+			airlineName, _ := airline.(string)
+			point := apitypes.PricePoint{Airline: airlineName}
+			switch dt := date.(type) {
+			case neo4j.Date:
+				point.Date = dt.Time() // Convert neo4j.Date to time.Time
+			case time.Time:
+				point.Date = dt
+			}
+			switch p := price.(type) {
+			case float64:
+				point.Price = p
+			case int64:
+				point.Price = float64(p)
 			}
 
-			priceHistory = append(priceHistory, map[string]interface{}{
-				"date":    dateVal,
-				"price":   price,
-				"airline": airline,
-			})
+			priceHistory = append(priceHistory, point)
 		}
 		if err = result.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing price history results"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error processing price history results"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"origin":      origin,
-			"destination": destination,
-			"history":     priceHistory,
-			"filter": map[string]interface{}{
-				"include_airline_groups": includeGroups,
-				"exclude_airline_groups": excludeGroups,
+		c.JSON(http.StatusOK, apitypes.PriceHistoryResponse{
+			Origin:      origin,
+			Destination: destination,
+			History:     priceHistory,
+			Filter: apitypes.AirlineGroupFilter{
+				IncludeAirlineGroups: includeGroups,
+				ExcludeAirlineGroups: excludeGroups,
 			},
 		})
 	}
@@ -1230,13 +1031,13 @@ func listJobs(pgDB db.PostgresDB) gin.HandlerFunc {
 		rows, err := pgDB.ListJobs(c.Request.Context())
 		if err != nil {
 			log.Printf("ListJobs query error: %v", err) // Debug log
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to list jobs: " + err.Error()})
 			return
 		}
 		defer rows.Close()
 		log.Printf("ListJobs query successful, starting to scan rows") // Debug log
 
-		jobs := []map[string]interface{}{}
+		jobs := []apitypes.ScheduledJobResponse{}
 		for rows.Next() {
 			var job db.ScheduledJob // Use the defined struct
 
@@ -1248,59 +1049,43 @@ func listJobs(pgDB db.PostgresDB) gin.HandlerFunc {
 			if err := rows.Scan(&job.ID, &job.Name, &job.CronExpression, &job.Enabled, &job.LastRun, &job.CreatedAt, &job.UpdatedAt,
 				&origin, &destination, &dynamicDates, &daysFromExecution, &searchWindowDays, &tripLength); err != nil {
 				log.Printf("Scan error: %v", err) // Debug log
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan job: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan job: " + err.Error()})
 				return
 			}
 
 			log.Printf("Scanned job %d: %s (origin=%v, dest=%v, dynamic=%v)", job.ID, job.Name, origin.String, destination.String, dynamicDates.Bool)
 
-			jobMap := map[string]interface{}{
-				"id":              job.ID,
-				"name":            job.Name,
-				"cron_expression": job.CronExpression,
-				"enabled":         job.Enabled,
-				"created_at":      job.CreatedAt,
-				"updated_at":      job.UpdatedAt,
-			}
-
-			if job.LastRun.Valid {
-				jobMap["last_run"] = job.LastRun.Time
-			} else {
-				jobMap["last_run"] = nil // Explicitly set null if not valid
+			jobResponse := apitypes.ScheduledJobResponse{
+				ID:             job.ID,
+				Name:           job.Name,
+				CronExpression: job.CronExpression,
+				Enabled:        job.Enabled,
+				LastRun:        maybeNullTime(job.LastRun),
+				CreatedAt:      job.CreatedAt,
+				UpdatedAt:      job.UpdatedAt,
 			}
 
 			// Add job details from database
-			details := map[string]interface{}{}
-
 			if origin.Valid && destination.Valid {
 				// Use real data from database
-				details["origin"] = origin.String
-				details["destination"] = destination.String
-				details["dynamic_dates"] = dynamicDates.Bool
-
-				if daysFromExecution.Valid {
-					details["days_from_execution"] = daysFromExecution.Int32
-				}
-				if searchWindowDays.Valid {
-					details["search_window_days"] = searchWindowDays.Int32
-				}
-				if tripLength.Valid {
-					details["trip_length"] = tripLength.Int32
+				jobResponse.Details = apitypes.ScheduledJobDetails{
+					Origin:            origin.String,
+					Destination:       destination.String,
+					DynamicDates:      dynamicDates.Bool,
+					DaysFromExecution: maybeNullInt(daysFromExecution),
+					SearchWindowDays:  maybeNullInt(searchWindowDays),
+					TripLength:        maybeNullInt(tripLength),
 				}
 			} else {
 				// Fallback - this should not happen for properly created jobs
 				log.Printf("Job %d has no details in database", job.ID)
-				details["origin"] = "N/A"
-				details["destination"] = "N/A"
-				details["dynamic_dates"] = false
+				jobResponse.Details = apitypes.ScheduledJobDetails{Origin: "N/A", Destination: "N/A"}
 			}
 
-			jobMap["details"] = details
-
-			jobs = append(jobs, jobMap)
+			jobs = append(jobs, jobResponse)
 		}
 		if err := rows.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating jobs: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating jobs: " + err.Error()})
 			return
 		}
 
@@ -1316,58 +1101,46 @@ func listBulkSearches(pgDB db.PostgresDB) gin.HandlerFunc {
 
 		rows, err := pgDB.ListBulkSearches(c.Request.Context(), limit, offset)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list bulk searches: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to list bulk searches: " + err.Error()})
 			return
 		}
 		defer rows.Close()
 
-		searches := []map[string]interface{}{}
+		searches := []apitypes.BulkSearchSummary{}
 		for rows.Next() {
 			var search db.BulkSearch
 			if err := rows.Scan(&search.ID, &search.JobID, &search.Status, &search.TotalSearches, &search.Completed,
 				&search.TotalOffers, &search.ErrorCount, &search.Currency, &search.CreatedAt, &search.UpdatedAt,
 				&search.CompletedAt, &search.MinPrice, &search.MaxPrice, &search.AveragePrice); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan bulk search: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan bulk search: " + err.Error()})
 				return
 			}
 
-			record := map[string]interface{}{
-				"id":           search.ID,
-				"status":       search.Status,
-				"total_routes": search.TotalSearches,
-				"completed":    search.Completed,
-				"total_offers": search.TotalOffers,
-				"error_count":  search.ErrorCount,
-				"currency":     search.Currency,
-				"created_at":   search.CreatedAt,
-				"updated_at":   search.UpdatedAt,
-			}
-			if search.JobID.Valid {
-				record["job_id"] = search.JobID.Int32
-			}
-			if search.CompletedAt.Valid {
-				record["completed_at"] = search.CompletedAt.Time
-			}
-			if search.MinPrice.Valid {
-				record["min_price"] = search.MinPrice.Float64
-			}
-			if search.MaxPrice.Valid {
-				record["max_price"] = search.MaxPrice.Float64
-			}
-			if search.AveragePrice.Valid {
-				record["average_price"] = search.AveragePrice.Float64
-			}
-
-			searches = append(searches, record)
+			searches = append(searches, apitypes.BulkSearchSummary{
+				ID:           search.ID,
+				JobID:        maybeNullInt(search.JobID),
+				Status:       search.Status,
+				TotalRoutes:  search.TotalSearches,
+				Completed:    search.Completed,
+				TotalOffers:  search.TotalOffers,
+				ErrorCount:   search.ErrorCount,
+				Currency:     search.Currency,
+				CreatedAt:    search.CreatedAt,
+				UpdatedAt:    search.UpdatedAt,
+				CompletedAt:  maybeNullTime(search.CompletedAt),
+				MinPrice:     maybeNullFloat(search.MinPrice),
+				MaxPrice:     maybeNullFloat(search.MaxPrice),
+				AveragePrice: maybeNullFloat(search.AveragePrice),
+			})
 		}
 		if err := rows.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating bulk searches: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating bulk searches: " + err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"items": searches,
-			"count": len(searches),
+		c.JSON(http.StatusOK, apitypes.BulkSearchListResponse{
+			Items: searches,
+			Count: len(searches),
 		})
 	}
 }
@@ -1377,7 +1150,7 @@ func listBulkSearches(pgDB db.PostgresDB) gin.HandlerFunc {
 func enqueuePriceGraphSweep(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if workerManager == nil || workerManager.GetScheduler() == nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Price graph scheduler unavailable"})
+			c.JSON(http.StatusServiceUnavailable, apitypes.ErrorResponse{Error: "Price graph scheduler unavailable"})
 			return
 		}
 
@@ -1390,13 +1163,21 @@ func enqueuePriceGraphSweep(pgDB db.PostgresDB, workerManager *worker.Manager) g
 			}
 		}
 
-		var req PriceGraphSweepRequest
+		var req apitypes.PriceGraphSweepRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 		if req.DepartureDateFrom.Time.After(req.DepartureDateTo.Time) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "departure_date_from must be before departure_date_to"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "departure_date_from must be before departure_date_to"})
+			return
+		}
+		if len(req.ReturnDestinations) > 0 && len(req.ReturnOrigins) == 0 {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "return_destinations requires return_origins"})
+			return
+		}
+		if len(req.ReturnOrigins) > 0 && req.TripType != "round_trip" {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "return_origins requires trip_type round_trip"})
 			return
 		}
 
@@ -1407,7 +1188,7 @@ func enqueuePriceGraphSweep(pgDB db.PostgresDB, workerManager *worker.Manager) g
 
 		classes, err := normalizePriceGraphSweepClasses(req.Class, req.Classes)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
@@ -1417,102 +1198,116 @@ func enqueuePriceGraphSweep(pgDB db.PostgresDB, workerManager *worker.Manager) g
 		if containsToken(req.Origins, macros.RegionWorldAll) || containsToken(req.Destinations, macros.RegionWorldAll) {
 			worldAll, err := listAllAirportCodes(ctx, pgDB)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 				return
 			}
 			overrides = map[string][]string{macros.RegionWorldAll: worldAll}
 			worldAllCount = len(worldAll)
 		}
+		nearOverrides, nearbyAirports, err := nearbyAirportOverrides(ctx, pgDB, req.Origins, req.Destinations, req.ReturnOrigins, req.ReturnDestinations)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+		overrides = mergeAirportOverrides(overrides, nearOverrides)
 
 		// Expand region tokens in origins and destinations
 		expandedOrigins, originWarnings, err := macros.ExpandAirportTokensWithOverrides(req.Origins, overrides)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid origin: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid origin: " + err.Error()})
 			return
 		}
 		expandedDestinations, destinationWarnings, err := macros.ExpandAirportTokensWithOverrides(req.Destinations, overrides)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid destination: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid destination: " + err.Error()})
+			return
+		}
+
+		expandedReturnOrigins, returnOriginWarnings, err := macros.ExpandAirportTokensWithOverrides(req.ReturnOrigins, overrides)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid return origin: " + err.Error()})
+			return
+		}
+		expandedReturnDestinations, returnDestinationWarnings, err := macros.ExpandAirportTokensWithOverrides(req.ReturnDestinations, overrides)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid return destination: " + err.Error()})
 			return
 		}
 
 		warnings := append([]string{}, originWarnings...)
 		warnings = append(warnings, destinationWarnings...)
+		warnings = append(warnings, returnOriginWarnings...)
+		warnings = append(warnings, returnDestinationWarnings...)
 		if worldAllCount > 0 {
 			warnings = append(warnings, fmt.Sprintf("%s expanded to %d airports", macros.RegionWorldAll, worldAllCount))
 		}
 
 		// Guard against accidental workload explosion from region tokens
 		totalRoutes := len(expandedOrigins) * len(expandedDestinations)
+		if len(expandedReturnOrigins) > 0 {
+			totalRoutes *= len(expandedReturnOrigins) * max(len(expandedReturnDestinations), 1)
+		}
 		if totalRoutes == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":    "At least one origin and one destination are required (after REGION:* expansion)",
-				"warnings": warnings,
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{
+				Error:    "At least one origin and one destination are required (after REGION:* expansion)",
+				Warnings: warnings,
 			})
 			return
 		}
 		const maxSweepRoutes = 10000
 		if totalRoutes > maxSweepRoutes {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":        fmt.Sprintf("Too many routes: %d (max %d). Region tokens can expand to many airports; consider narrowing your search.", totalRoutes, maxSweepRoutes),
-				"total_routes": totalRoutes,
-				"max_routes":   maxSweepRoutes,
-				"origins":      len(expandedOrigins),
-				"destinations": len(expandedDestinations),
-				"warnings":     warnings,
+			c.JSON(http.StatusBadRequest, apitypes.ExpansionLimitResponse{
+				ErrorResponse: apitypes.ErrorResponse{
+					Error:    fmt.Sprintf("Too many routes: %d (max %d). Region tokens can expand to many airports; consider narrowing your search.", totalRoutes, maxSweepRoutes),
+					Warnings: warnings,
+				},
+				TotalRoutes:  totalRoutes,
+				MaxRoutes:    maxSweepRoutes,
+				Origins:      len(expandedOrigins),
+				Destinations: len(expandedDestinations),
 			})
 			return
 		}
 
 		payload := worker.PriceGraphSweepPayload{
-			Origins:           expandedOrigins,
-			Destinations:      expandedDestinations,
-			DepartureDateFrom: req.DepartureDateFrom.Time,
-			DepartureDateTo:   req.DepartureDateTo.Time,
-			TripLengths:       req.TripLengths,
-			TripType:          req.TripType,
-			Class:             classes[0],
-			Classes:           classes,
-			Stops:             stops,
-			Adults:            req.Adults,
-			Children:          req.Children,
-			InfantsLap:        req.InfantsLap,
-			InfantsSeat:       req.InfantsSeat,
-			Currency:          strings.ToUpper(req.Currency),
-			RateLimitMillis:   req.RateLimitMillis,
+			Origins:            expandedOrigins,
+			Destinations:       expandedDestinations,
+			DepartureDateFrom:  req.DepartureDateFrom.Time,
+			DepartureDateTo:    req.DepartureDateTo.Time,
+			TripLengths:        req.TripLengths,
+			TripType:           req.TripType,
+			ReturnOrigins:      expandedReturnOrigins,
+			ReturnDestinations: expandedReturnDestinations,
+			Class:              classes[0],
+			Classes:            classes,
+			Stops:              stops,
+			Adults:             req.Adults,
+			Children:           req.Children,
+			InfantsLap:         req.InfantsLap,
+			InfantsSeat:        req.InfantsSeat,
+			Currency:           strings.ToUpper(req.Currency),
+			RateLimitMillis:    req.RateLimitMillis,
 		}
 
 		sweepID, err := workerManager.GetScheduler().EnqueuePriceGraphSweep(ctx, payload)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue price graph sweep: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to enqueue price graph sweep: " + err.Error()})
 			return
 		}
 
 		sweep, err := pgDB.GetPriceGraphSweepByID(ctx, sweepID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sweep metadata: " + err.Error()})
-			return
-		}
-
-		c.JSON(http.StatusAccepted, gin.H{
-			"message":  "Price graph sweep enqueued",
-			"sweep_id": sweepID,
-			"classes":  classes,
-			"warnings": warnings,
-			"sweep": map[string]interface{}{
-				"id":                sweep.ID,
-				"status":            sweep.Status,
-				"origin_count":      sweep.OriginCount,
-				"destination_count": sweep.DestinationCount,
-				"trip_length_min":   maybeNullInt(sweep.TripLengthMin),
-				"trip_length_max":   maybeNullInt(sweep.TripLengthMax),
-				"currency":          sweep.Currency,
-				"created_at":        sweep.CreatedAt,
-				"updated_at":        sweep.UpdatedAt,
-				"started_at":        maybeNullTime(sweep.StartedAt),
-				"completed_at":      maybeNullTime(sweep.CompletedAt),
-				"error_count":       sweep.ErrorCount,
-			},
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to fetch sweep metadata: " + err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, apitypes.PriceGraphSweepAcceptedResponse{
+			Message:        "Price graph sweep enqueued",
+			SweepID:        sweepID,
+			Classes:        classes,
+			Warnings:       warnings,
+			Sweep:          convertPriceGraphSweep(sweep),
+			NearbyAirports: convertNearbyAirports(nearbyAirports),
 		})
 	}
 }
@@ -1525,48 +1320,31 @@ func listPriceGraphSweeps(pgDB db.PostgresDB) gin.HandlerFunc {
 
 		rows, err := pgDB.ListPriceGraphSweeps(c.Request.Context(), limit, offset)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list price graph sweeps: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to list price graph sweeps: " + err.Error()})
 			return
 		}
 		defer rows.Close()
 
-		results := make([]map[string]interface{}, 0)
+		results := make([]apitypes.PriceGraphSweepSummary, 0)
 		for rows.Next() {
 			var sweep db.PriceGraphSweep
 			if err := rows.Scan(&sweep.ID, &sweep.JobID, &sweep.Status, &sweep.OriginCount, &sweep.DestinationCount,
 				&sweep.TripLengthMin, &sweep.TripLengthMax, &sweep.Currency, &sweep.ErrorCount,
 				&sweep.CreatedAt, &sweep.UpdatedAt, &sweep.StartedAt, &sweep.CompletedAt); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan price graph sweep: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan price graph sweep: " + err.Error()})
 				return
 			}
 
-			entry := map[string]interface{}{
-				"id":                sweep.ID,
-				"status":            sweep.Status,
-				"origin_count":      sweep.OriginCount,
-				"destination_count": sweep.DestinationCount,
-				"trip_length_min":   maybeNullInt(sweep.TripLengthMin),
-				"trip_length_max":   maybeNullInt(sweep.TripLengthMax),
-				"currency":          sweep.Currency,
-				"error_count":       sweep.ErrorCount,
-				"created_at":        sweep.CreatedAt,
-				"updated_at":        sweep.UpdatedAt,
-				"started_at":        maybeNullTime(sweep.StartedAt),
-				"completed_at":      maybeNullTime(sweep.CompletedAt),
-			}
-			if sweep.JobID.Valid {
-				entry["job_id"] = sweep.JobID.Int32
-			}
-			results = append(results, entry)
+			results = append(results, convertPriceGraphSweep(&sweep))
 		}
 		if err := rows.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating sweeps: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating sweeps: " + err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"items": results,
-			"count": len(results),
+		c.JSON(http.StatusOK, apitypes.PriceGraphSweepListResponse{
+			Items: results,
+			Count: len(results),
 		})
 	}
 }
@@ -1576,7 +1354,7 @@ func getPriceGraphSweepResults(pgDB db.PostgresDB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		sweepID, err := strconv.Atoi(c.Param("id"))
 		if err != nil || sweepID <= 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sweep ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid sweep ID"})
 			return
 		}
 
@@ -1584,9 +1362,9 @@ func getPriceGraphSweepResults(pgDB db.PostgresDB) gin.HandlerFunc {
 		summary, err := pgDB.GetPriceGraphSweepByID(ctx, sweepID)
 		if err != nil {
 			if strings.Contains(err.Error(), "not found") {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Price graph sweep not found"})
+				c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Price graph sweep not found"})
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load sweep metadata: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to load sweep metadata: " + err.Error()})
 			}
 			return
 		}
@@ -1596,12 +1374,12 @@ func getPriceGraphSweepResults(pgDB db.PostgresDB) gin.HandlerFunc {
 
 		rows, err := pgDB.ListPriceGraphResults(ctx, sweepID, limit, offset)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sweep results: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to list sweep results: " + err.Error()})
 			return
 		}
 		defer rows.Close()
 
-		results := make([]map[string]interface{}, 0)
+		results := make([]apitypes.PriceGraphResult, 0)
 		for rows.Next() {
 			var (
 				id          int
@@ -1623,6 +1401,8 @@ func getPriceGraphSweepResults(pgDB db.PostgresDB) gin.HandlerFunc {
 				searchURL   sql.NullString
 				queriedAt   time.Time
 				createdAt   time.Time
+				returnFrom  string
+				returnTo    string
 			)
 
 			if err := rows.Scan(
@@ -1645,56 +1425,46 @@ func getPriceGraphSweepResults(pgDB db.PostgresDB) gin.HandlerFunc {
 				&searchURL,
 				&queriedAt,
 				&createdAt,
+				&returnFrom,
+				&returnTo,
 			); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan result: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan result: " + err.Error()})
 				return
 			}
 
-			entry := map[string]interface{}{
-				"id":             id,
-				"sweep_id":       sid,
-				"origin":         origin,
-				"destination":    destination,
-				"departure_date": departure,
-				"return_date":    maybeNullTime(returnDate),
-				"trip_length":    maybeNullInt(tripLength),
-				"price":          price,
-				"currency":       currency,
-				"adults":         adults,
-				"children":       children,
-				"infants_lap":    infantsLap,
-				"infants_seat":   infantsSeat,
-				"trip_type":      tripType,
-				"class":          class,
-				"stops":          stops,
-				"search_url":     maybeNullString(searchURL),
-				"queried_at":     queriedAt,
-				"created_at":     createdAt,
-			}
-			results = append(results, entry)
+			results = append(results, apitypes.PriceGraphResult{
+				ID:                id,
+				SweepID:           sid,
+				Origin:            origin,
+				Destination:       destination,
+				DepartureDate:     departure,
+				ReturnDate:        maybeNullTime(returnDate),
+				TripLength:        maybeNullInt(tripLength),
+				Price:             price,
+				Currency:          currency,
+				Adults:            adults,
+				Children:          children,
+				InfantsLap:        infantsLap,
+				InfantsSeat:       infantsSeat,
+				TripType:          tripType,
+				Class:             class,
+				Stops:             stops,
+				SearchURL:         maybeNullString(searchURL),
+				QueriedAt:         queriedAt,
+				CreatedAt:         createdAt,
+				ReturnOrigin:      returnFrom,
+				ReturnDestination: returnTo,
+			})
 		}
 		if err := rows.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating results: " + err.Error()})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"sweep": map[string]interface{}{
-				"id":                summary.ID,
-				"status":            summary.Status,
-				"origin_count":      summary.OriginCount,
-				"destination_count": summary.DestinationCount,
-				"trip_length_min":   maybeNullInt(summary.TripLengthMin),
-				"trip_length_max":   maybeNullInt(summary.TripLengthMax),
-				"currency":          summary.Currency,
-				"error_count":       summary.ErrorCount,
-				"created_at":        summary.CreatedAt,
-				"updated_at":        summary.UpdatedAt,
-				"started_at":        maybeNullTime(summary.StartedAt),
-				"completed_at":      maybeNullTime(summary.CompletedAt),
-			},
-			"results": results,
-			"count":   len(results),
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating results: " + err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, apitypes.PriceGraphSweepResultsResponse{
+			Sweep:   convertPriceGraphSweep(summary),
+			Results: results,
+			Count:   len(results),
 		})
 	}
 }
@@ -1705,16 +1475,16 @@ func getBulkSearchResults(pgDB db.PostgresDB) gin.HandlerFunc {
 		idParam := c.Param("id")
 		bulkID, err := strconv.Atoi(idParam)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bulk search ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid bulk search ID"})
 			return
 		}
 
 		summary, err := pgDB.GetBulkSearchByID(c.Request.Context(), bulkID)
 		if err != nil {
 			if strings.Contains(err.Error(), "not found") {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Bulk search not found"})
+				c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Bulk search not found"})
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load bulk search: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to load bulk search: " + err.Error()})
 			}
 			return
 		}
@@ -1724,125 +1494,42 @@ func getBulkSearchResults(pgDB db.PostgresDB) gin.HandlerFunc {
 
 		rows, err := pgDB.QueryBulkSearchResultsPaginated(c.Request.Context(), bulkID, limit, offset)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query bulk search results"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to query bulk search results"})
 			return
 		}
 		defer rows.Close()
 
-		results := []map[string]interface{}{}
+		results := []apitypes.BulkSearchResultResponse{}
 		for rows.Next() {
 			var result db.BulkSearchResult
+			var segmentFlights []byte
 			if err := rows.Scan(&result.Origin, &result.Destination, &result.DepartureDate, &result.ReturnDate,
 				&result.Price, &result.Currency, &result.AirlineCode, &result.Duration,
 				&result.SrcAirportCode, &result.DstAirportCode, &result.SrcCity, &result.DstCity,
-				&result.FlightDuration, &result.ReturnFlightDuration, &result.OutboundFlights, &result.ReturnFlights, &result.OfferJSON); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan bulk search result: " + err.Error()})
+				&result.FlightDuration, &result.ReturnFlightDuration, &result.OutboundFlights, &result.ReturnFlights, &result.OfferJSON,
+				&segmentFlights); err != nil {
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to scan bulk search result: " + err.Error()})
 				return
 			}
 
-			entry := map[string]interface{}{
-				"origin":         result.Origin,
-				"destination":    result.Destination,
-				"departure_date": result.DepartureDate,
-				"price":          result.Price,
-				"currency":       result.Currency,
-			}
-			if result.ReturnDate.Valid {
-				entry["return_date"] = result.ReturnDate.Time
-			}
-			if result.AirlineCode.Valid {
-				entry["airline_code"] = result.AirlineCode.String
-			}
-			if result.Duration.Valid {
-				entry["duration"] = result.Duration.Int32
-			}
-			if result.SrcAirportCode.Valid {
-				entry["src_airport_code"] = result.SrcAirportCode.String
-			}
-			if result.DstAirportCode.Valid {
-				entry["dst_airport_code"] = result.DstAirportCode.String
-			}
-			if result.SrcCity.Valid {
-				entry["src_city"] = result.SrcCity.String
-			}
-			if result.DstCity.Valid {
-				entry["dst_city"] = result.DstCity.String
-			}
-			if result.FlightDuration.Valid {
-				entry["flight_duration"] = result.FlightDuration.Int32
-			}
-			if result.ReturnFlightDuration.Valid {
-				entry["return_flight_duration"] = result.ReturnFlightDuration.Int32
-			}
-			if len(result.OutboundFlights) > 0 {
-				entry["outbound_flights"] = json.RawMessage(result.OutboundFlights)
-			}
-			if len(result.ReturnFlights) > 0 {
-				entry["return_flights"] = json.RawMessage(result.ReturnFlights)
-			}
-			if len(result.OfferJSON) > 0 {
-				entry["offer_json"] = json.RawMessage(result.OfferJSON)
-			}
-
-			results = append(results, entry)
+			result.SegmentFlights = segmentFlights
+			results = append(results, convertBulkSearchResult(result))
 		}
 		if err := rows.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating bulk search results: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Error iterating bulk search results: " + err.Error()})
 			return
 		}
 
-		summaryMap := map[string]interface{}{
-			"id":           summary.ID,
-			"status":       summary.Status,
-			"total_routes": summary.TotalSearches,
-			"completed":    summary.Completed,
-			"total_offers": summary.TotalOffers,
-			"error_count":  summary.ErrorCount,
-			"currency":     summary.Currency,
-			"created_at":   summary.CreatedAt,
-			"updated_at":   summary.UpdatedAt,
-		}
-		if summary.JobID.Valid {
-			summaryMap["job_id"] = summary.JobID.Int32
-		}
-		if summary.CompletedAt.Valid {
-			summaryMap["completed_at"] = summary.CompletedAt.Time
-		}
-		if summary.MinPrice.Valid {
-			summaryMap["min_price"] = summary.MinPrice.Float64
-		}
-		if summary.MaxPrice.Valid {
-			summaryMap["max_price"] = summary.MaxPrice.Float64
-		}
-		if summary.AveragePrice.Valid {
-			summaryMap["average_price"] = summary.AveragePrice.Float64
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"summary": summaryMap,
-			"results": results,
-			"count":   len(results),
+		c.JSON(http.StatusOK, apitypes.BulkSearchResultsResponse{
+			Summary: convertBulkSearch(summary),
+			Results: results,
+			Count:   len(results),
 		})
 	}
 }
 
 // getBulkSearchOffers returns the full set of offers captured during a bulk search
 func getBulkSearchOffers(pgDB db.PostgresDB) gin.HandlerFunc {
-	type gridCell struct {
-		DepartureDate time.Time  `json:"departure_date"`
-		ReturnDate    *time.Time `json:"return_date,omitempty"`
-		Price         float64    `json:"price"`
-		Currency      string     `json:"currency"`
-		AirlineCodes  []string   `json:"airline_codes,omitempty"`
-		CreatedAt     time.Time  `json:"created_at"`
-	}
-
-	type routeGrid struct {
-		Origin      string     `json:"origin"`
-		Destination string     `json:"destination"`
-		Cells       []gridCell `json:"cells"`
-	}
-
 	type gridAccumulator struct {
 		origin      string
 		destination string
@@ -1860,18 +1547,18 @@ func getBulkSearchOffers(pgDB db.PostgresDB) gin.HandlerFunc {
 		idParam := c.Param("id")
 		bulkID, err := strconv.Atoi(idParam)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bulk search ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid bulk search ID"})
 			return
 		}
 
 		limit, err := strconv.Atoi(c.DefaultQuery("limit", "200"))
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid limit"})
 			return
 		}
 		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid offset"})
 			return
 		}
 		if offset < 0 {
@@ -1881,7 +1568,7 @@ func getBulkSearchOffers(pgDB db.PostgresDB) gin.HandlerFunc {
 
 		offers, err := pgDB.ListBulkSearchOffers(c.Request.Context(), bulkID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load bulk search offers: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to load bulk search offers: " + err.Error()})
 			return
 		}
 
@@ -1920,57 +1607,36 @@ func getBulkSearchOffers(pgDB db.PostgresDB) gin.HandlerFunc {
 			end = total
 		}
 
-		offerItems := make([]map[string]interface{}, 0, end-offset)
+		offerItems := make([]apitypes.BulkSearchOffer, 0, end-offset)
 		for i := offset; i < end; i++ {
 			offer := offers[i]
-			entry := map[string]interface{}{
-				"origin":         offer.Origin,
-				"destination":    offer.Destination,
-				"departure_date": offer.DepartureDate,
-				"price":          offer.Price,
-				"currency":       offer.Currency,
-				"created_at":     offer.CreatedAt,
-			}
-			if offer.ReturnDate.Valid {
-				entry["return_date"] = offer.ReturnDate.Time
-			}
-			if len(offer.AirlineCodes) > 0 {
-				entry["airline_codes"] = append([]string(nil), offer.AirlineCodes...)
-			}
-			if offer.SrcAirportCode.Valid {
-				entry["src_airport_code"] = offer.SrcAirportCode.String
-			}
-			if offer.DstAirportCode.Valid {
-				entry["dst_airport_code"] = offer.DstAirportCode.String
-			}
-			if offer.SrcCity.Valid {
-				entry["src_city"] = offer.SrcCity.String
-			}
-			if offer.DstCity.Valid {
-				entry["dst_city"] = offer.DstCity.String
-			}
-			if offer.FlightDuration.Valid {
-				entry["flight_duration"] = offer.FlightDuration.Int32
-			}
-			if offer.ReturnFlightDuration.Valid {
-				entry["return_flight_duration"] = offer.ReturnFlightDuration.Int32
-			}
-			if offer.DistanceMiles.Valid {
-				entry["distance_miles"] = offer.DistanceMiles.Float64
-			}
-			if offer.CostPerMile.Valid {
-				entry["cost_per_mile"] = offer.CostPerMile.Float64
-			}
-			if len(offer.OutboundFlights) > 0 {
-				entry["outbound_flights"] = json.RawMessage(offer.OutboundFlights)
-			}
-			if len(offer.ReturnFlights) > 0 {
-				entry["return_flights"] = json.RawMessage(offer.ReturnFlights)
-			}
-			if len(offer.OfferJSON) > 0 {
-				entry["offer_json"] = json.RawMessage(offer.OfferJSON)
-			}
-			offerItems = append(offerItems, entry)
+			offerItems = append(offerItems, apitypes.BulkSearchOffer{
+				BulkSearchResultResponse: apitypes.BulkSearchResultResponse{
+					Origin:               offer.Origin,
+					Destination:          offer.Destination,
+					DepartureDate:        offer.DepartureDate,
+					ReturnDate:           maybeNullTime(offer.ReturnDate),
+					Price:                offer.Price,
+					Currency:             offer.Currency,
+					SrcAirportCode:       offer.SrcAirportCode.String,
+					DstAirportCode:       offer.DstAirportCode.String,
+					SrcCity:              offer.SrcCity.String,
+					DstCity:              offer.DstCity.String,
+					FlightDuration:       maybeNullInt(offer.FlightDuration),
+					ReturnFlightDuration: maybeNullInt(offer.ReturnFlightDuration),
+					OutboundFlights:      offer.OutboundFlights,
+					ReturnFlights:        offer.ReturnFlights,
+					OfferJSON:            offer.OfferJSON,
+					SegmentFlights:       offer.SegmentFlights,
+				},
+				AirlineCodes:          append([]string(nil), offer.AirlineCodes...),
+				CreatedAt:             offer.CreatedAt,
+				DistanceMiles:         maybeNullFloat(offer.DistanceMiles),
+				CostPerMile:           maybeNullFloat(offer.CostPerMile),
+				NormalizedPrice:       maybeNullFloat(offer.NormalizedPrice),
+				NormalizedCurrency:    offer.NormalizedCurrency.String,
+				NormalizedCostPerMile: maybeNullFloat(offer.NormalizedCostPerMile),
+			})
 		}
 
 		routeAccumulators := make(map[string]*gridAccumulator)
@@ -2020,7 +1686,7 @@ func getBulkSearchOffers(pgDB db.PostgresDB) gin.HandlerFunc {
 			}
 		}
 
-		gridRoutes := make([]routeGrid, 0, len(routeAccumulators))
+		gridRoutes := make([]apitypes.BulkSearchRouteGrid, 0, len(routeAccumulators))
 		routeKeys := make([]string, 0, len(routeAccumulators))
 		for key := range routeAccumulators {
 			routeKeys = append(routeKeys, key)
@@ -2029,14 +1695,14 @@ func getBulkSearchOffers(pgDB db.PostgresDB) gin.HandlerFunc {
 
 		for _, key := range routeKeys {
 			acc := routeAccumulators[key]
-			cells := make([]gridCell, 0, len(acc.cells))
+			cells := make([]apitypes.BulkSearchGridCell, 0, len(acc.cells))
 			for _, cell := range acc.cells {
 				var returnDatePtr *time.Time
 				if cell.returnDate.Valid {
 					rt := cell.returnDate.Time
 					returnDatePtr = &rt
 				}
-				cells = append(cells, gridCell{
+				cells = append(cells, apitypes.BulkSearchGridCell{
 					DepartureDate: cell.departureDate,
 					ReturnDate:    returnDatePtr,
 					Price:         cell.price,
@@ -2063,17 +1729,17 @@ func getBulkSearchOffers(pgDB db.PostgresDB) gin.HandlerFunc {
 				return cells[i].ReturnDate.Before(*cells[j].ReturnDate)
 			})
 
-			gridRoutes = append(gridRoutes, routeGrid{
+			gridRoutes = append(gridRoutes, apitypes.BulkSearchRouteGrid{
 				Origin:      acc.origin,
 				Destination: acc.destination,
 				Cells:       cells,
 			})
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"items": offerItems,
-			"count": total,
-			"grid":  gridRoutes,
+		c.JSON(http.StatusOK, apitypes.BulkSearchOffersResponse{
+			Items: offerItems,
+			Count: total,
+			Grid:  gridRoutes,
 		})
 	}
 }
@@ -2081,27 +1747,27 @@ func getBulkSearchOffers(pgDB db.PostgresDB) gin.HandlerFunc {
 // createJob returns a handler for creating a new scheduled job
 func createJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFunc { // Changed parameter type
 	return func(c *gin.Context) {
-		var req JobRequest
+		var req apitypes.JobRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		if req.CronExpression == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "CronExpression is required"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "CronExpression is required"})
 			return
 		}
 
 		// Parse date strings to time.Time objects
 		dateStart, err := time.Parse("2006-01-02", req.DateStart)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_start format. Use YYYY-MM-DD: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid date_start format. Use YYYY-MM-DD: " + err.Error()})
 			return
 		}
 
 		dateEnd, err := time.Parse("2006-01-02", req.DateEnd)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_end format. Use YYYY-MM-DD: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid date_end format. Use YYYY-MM-DD: " + err.Error()})
 			return
 		}
 
@@ -2110,7 +1776,7 @@ func createJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		if req.ReturnDateStart != "" {
 			returnDateStart, err = time.Parse("2006-01-02", req.ReturnDateStart)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return_date_start format. Use YYYY-MM-DD: " + err.Error()})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid return_date_start format. Use YYYY-MM-DD: " + err.Error()})
 				return
 			}
 		}
@@ -2118,7 +1784,7 @@ func createJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		if req.ReturnDateEnd != "" {
 			returnDateEnd, err = time.Parse("2006-01-02", req.ReturnDateEnd)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return_date_end format. Use YYYY-MM-DD: " + err.Error()})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid return_date_end format. Use YYYY-MM-DD: " + err.Error()})
 				return
 			}
 		}
@@ -2126,7 +1792,7 @@ func createJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		// Begin a transaction
 		tx, err := pgDB.BeginTx(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to begin transaction: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to begin transaction: " + err.Error()})
 			return
 		}
 		defer tx.Rollback() // Ensure rollback on error
@@ -2134,7 +1800,7 @@ func createJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		// Validate cron expression format (basic validation)
 		parts := strings.Fields(req.CronExpression)
 		if len(parts) != 5 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cron expression format. Must have 5 space-separated fields: minute hour day month weekday"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid cron expression format. Must have 5 space-separated fields: minute hour day month weekday"})
 			return
 		}
 		// TODO: Add more robust cron expression validation if needed (e.g., using a library)
@@ -2143,7 +1809,7 @@ func createJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		ctx := c.Request.Context()
 		jobID, err := pgDB.CreateScheduledJob(ctx, tx, req.Name, req.CronExpression, true) // Assume enabled by default
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduled job"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to create scheduled job"})
 			return
 		}
 
@@ -2186,14 +1852,14 @@ func createJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		err = pgDB.CreateJobDetails(ctx, tx, details)
 		if err != nil {
 			log.Printf("Failed to create job details: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job details"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to create job details"})
 			return
 		}
 		log.Printf("Successfully created job details for job ID %d", jobID)
 
 		// Commit the transaction
 		if err := tx.Commit(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to commit transaction: " + err.Error()})
 			return
 		}
 
@@ -2201,9 +1867,9 @@ func createJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		// The previous call to scheduler.AddJob was incorrect based on its signature.
 		// TODO: Verify scheduler loading logic handles new jobs correctly.
 
-		c.JSON(http.StatusCreated, gin.H{
-			"id":      jobID,
-			"message": "Job created and scheduled successfully",
+		c.JSON(http.StatusCreated, apitypes.ScheduledJobSavedResponse{
+			ID:      jobID,
+			Message: "Job created and scheduled successfully",
 		})
 	}
 }
@@ -2211,9 +1877,18 @@ func createJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 // createBulkSearch returns a handler for creating a bulk flight search
 func CreateBulkSearch(q queue.Queue, pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var req BulkSearchRequest
+		var req apitypes.BulkSearchRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if req.MaxLayoverMinutes > 0 && req.MinLayoverMinutes > req.MaxLayoverMinutes {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "min_layover_minutes must not exceed max_layover_minutes"})
+			return
+		}
+		market := flights.Options{Country: req.Country, GoogleHost: req.GoogleHost, TimezoneOffset: req.TZOffsetMin}
+		if err := market.ValidateMarket(); err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
@@ -2229,28 +1904,43 @@ func CreateBulkSearch(q queue.Queue, pgDB db.PostgresDB, workerManager *worker.M
 			}
 		}
 
+		if req.TripType == "multi_city" {
+			createMultiCityBulkSearch(c, q, pgDB, req)
+			return
+		}
+		if len(req.Segments) > 0 {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "segments are only supported for multi_city bulk searches"})
+			return
+		}
+
 		ctx := c.Request.Context()
 		var overrides map[string][]string
 		var worldAllCount int
 		if containsToken(req.Origins, macros.RegionWorldAll) || containsToken(req.Destinations, macros.RegionWorldAll) {
 			worldAll, err := listAllAirportCodes(ctx, pgDB)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 				return
 			}
 			overrides = map[string][]string{macros.RegionWorldAll: worldAll}
 			worldAllCount = len(worldAll)
 		}
+		nearOverrides, nearbyAirports, err := nearbyAirportOverrides(ctx, pgDB, req.Origins, req.Destinations)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+		overrides = mergeAirportOverrides(overrides, nearOverrides)
 
 		// Expand region tokens in origins and destinations
 		expandedOrigins, originWarnings, err := macros.ExpandAirportTokensWithOverrides(req.Origins, overrides)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid origin: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid origin: " + err.Error()})
 			return
 		}
 		expandedDestinations, destinationWarnings, err := macros.ExpandAirportTokensWithOverrides(req.Destinations, overrides)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid destination: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid destination: " + err.Error()})
 			return
 		}
 
@@ -2276,9 +1966,9 @@ func CreateBulkSearch(q queue.Queue, pgDB db.PostgresDB, workerManager *worker.M
 
 		totalRoutes := len(expandedOrigins)*len(expandedDestinations) - selfRoutes
 		if totalRoutes == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":    "At least one non-self route is required (origin != destination after REGION:* expansion)",
-				"warnings": warnings,
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{
+				Error:    "At least one non-self route is required (origin != destination after REGION:* expansion)",
+				Warnings: warnings,
 			})
 			return
 		}
@@ -2289,13 +1979,15 @@ func CreateBulkSearch(q queue.Queue, pgDB db.PostgresDB, workerManager *worker.M
 		// Guard against accidental workload explosion from region tokens
 		const maxBulkSearchRoutes = 10000
 		if totalRoutes > maxBulkSearchRoutes {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":        fmt.Sprintf("Too many routes: %d (max %d). Region tokens can expand to many airports; consider narrowing your search.", totalRoutes, maxBulkSearchRoutes),
-				"total_routes": totalRoutes,
-				"max_routes":   maxBulkSearchRoutes,
-				"origins":      len(expandedOrigins),
-				"destinations": len(expandedDestinations),
-				"warnings":     warnings,
+			c.JSON(http.StatusBadRequest, apitypes.ExpansionLimitResponse{
+				ErrorResponse: apitypes.ErrorResponse{
+					Error:    fmt.Sprintf("Too many routes: %d (max %d). Region tokens can expand to many airports; consider narrowing your search.", totalRoutes, maxBulkSearchRoutes),
+					Warnings: warnings,
+				},
+				TotalRoutes:  totalRoutes,
+				MaxRoutes:    maxBulkSearchRoutes,
+				Origins:      len(expandedOrigins),
+				Destinations: len(expandedDestinations),
 			})
 			return
 		}
@@ -2311,44 +2003,60 @@ func CreateBulkSearch(q queue.Queue, pgDB db.PostgresDB, workerManager *worker.M
 			"queued",
 		)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create bulk search record: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to create bulk search record: " + err.Error()})
 			return
 		}
 
 		// Create a worker payload
 		payload := worker.BulkSearchPayload{
-			Origins:           expandedOrigins,
-			Destinations:      expandedDestinations,
-			DepartureDateFrom: req.DepartureDateFrom.Time,
-			DepartureDateTo:   req.DepartureDateTo.Time,
-			ReturnDateFrom:    req.ReturnDateFrom.Time,
-			ReturnDateTo:      req.ReturnDateTo.Time,
-			TripLength:        req.TripLength,
-			Adults:            req.Adults,
-			Children:          req.Children,
-			InfantsLap:        req.InfantsLap,
-			InfantsSeat:       req.InfantsSeat,
-			TripType:          req.TripType,
-			Class:             req.Class,
-			Stops:             req.Stops,
-			Currency:          currencyCode,
-			Carriers:          req.Carriers,
-			BulkSearchID:      bulkSearchID,
+			Origins:             expandedOrigins,
+			Destinations:        expandedDestinations,
+			DepartureDateFrom:   req.DepartureDateFrom.Time,
+			DepartureDateTo:     req.DepartureDateTo.Time,
+			ReturnDateFrom:      req.ReturnDateFrom.Time,
+			ReturnDateTo:        req.ReturnDateTo.Time,
+			TripLength:          req.TripLength,
+			Adults:              req.Adults,
+			Children:            req.Children,
+			InfantsLap:          req.InfantsLap,
+			InfantsSeat:         req.InfantsSeat,
+			TripType:            req.TripType,
+			Class:               req.Class,
+			Stops:               req.Stops,
+			Currency:            currencyCode,
+			Carriers:            req.Carriers,
+			MinLayoverMinutes:   req.MinLayoverMinutes,
+			MaxLayoverMinutes:   req.MaxLayoverMinutes,
+			ExcludeBasicEconomy: req.ExcludeBasicEconomy,
+			CarryOnBags:         req.CarryOnBags,
+			CheckedBags:         req.CheckedBags,
+			Country:             req.Country,
+			GoogleHost:          req.GoogleHost,
+			TZOffsetMin:         req.TZOffsetMin,
+			BulkSearchID:        bulkSearchID,
 		}
 
 		// Enqueue the job
-		jobID, err := q.Enqueue(ctx, "bulk_search", payload)
+		jobID, err := q.Enqueue(idempotentContext(c), "bulk_search", payload)
 		if err != nil {
 			_ = pgDB.UpdateBulkSearchStatus(ctx, bulkSearchID, "failed")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusAccepted, gin.H{
-			"job_id":         jobID,
-			"bulk_search_id": bulkSearchID,
-			"message":        "Bulk flight search job created successfully",
-			"warnings":       warnings,
+		// The same search may already be queued for another bulk search; report that one and
+		// retire the record created above, which no worker will pick up.
+		if existingID := worker.QueuedBulkSearchID(ctx, q, jobID); existingID != 0 && existingID != bulkSearchID {
+			_ = pgDB.UpdateBulkSearchStatus(ctx, bulkSearchID, "duplicate")
+			bulkSearchID = existingID
+		}
+
+		c.JSON(http.StatusAccepted, apitypes.BulkSearchAcceptedResponse{
+			JobID:          jobID,
+			BulkSearchID:   bulkSearchID,
+			Message:        "Bulk flight search job created successfully",
+			Warnings:       warnings,
+			NearbyAirports: convertNearbyAirports(nearbyAirports),
 		})
 	}
 }
@@ -2359,25 +2067,25 @@ func updateJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		id := c.Param("id")
 		jobID, err := strconv.Atoi(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid job ID"})
 			return
 		}
 
-		var req JobRequest
+		var req apitypes.JobRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		// Parse date strings (assuming YYYY-MM-DD format from JobRequest)
 		dateStart, err := time.Parse("2006-01-02", req.DateStart)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_start format. Use YYYY-MM-DD: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid date_start format. Use YYYY-MM-DD: " + err.Error()})
 			return
 		}
 		dateEnd, err := time.Parse("2006-01-02", req.DateEnd)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_end format. Use YYYY-MM-DD: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid date_end format. Use YYYY-MM-DD: " + err.Error()})
 			return
 		}
 		var returnDateStart, returnDateEnd time.Time
@@ -2385,7 +2093,7 @@ func updateJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		if req.ReturnDateStart != "" {
 			returnDateStart, err = time.Parse("2006-01-02", req.ReturnDateStart)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return_date_start format. Use YYYY-MM-DD: " + err.Error()})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid return_date_start format. Use YYYY-MM-DD: " + err.Error()})
 				return
 			}
 			returnStartValid = true
@@ -2393,7 +2101,7 @@ func updateJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		if req.ReturnDateEnd != "" {
 			returnDateEnd, err = time.Parse("2006-01-02", req.ReturnDateEnd)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return_date_end format. Use YYYY-MM-DD: " + err.Error()})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid return_date_end format. Use YYYY-MM-DD: " + err.Error()})
 				return
 			}
 			returnEndValid = true
@@ -2403,7 +2111,7 @@ func updateJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		ctx := c.Request.Context()
 		tx, err := pgDB.BeginTx(ctx)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to begin transaction"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to begin transaction"})
 			return
 		}
 		defer tx.Rollback() // Ensure rollback on error
@@ -2411,7 +2119,7 @@ func updateJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		// Update the job
 		err = pgDB.UpdateScheduledJob(ctx, tx, jobID, req.Name, req.CronExpression)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update scheduled job"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to update scheduled job"})
 			return
 		}
 
@@ -2444,26 +2152,26 @@ func updateJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 		// Update the job details
 		err = pgDB.UpdateJobDetails(ctx, tx, jobID, details)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job details"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to update job details"})
 			return
 		}
 
 		// Commit the transaction
 		if err := tx.Commit(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to commit transaction: " + err.Error()})
 			return
 		}
 
 		// Update the job schedule using the scheduler
 		// scheduler := workerManager.GetScheduler()
 		// if err := scheduler.UpdateJob(jobID, req.CronExpression); err != nil { // TODO: Fix UpdateJob signature/usage
-		// 	c.JSON(http.StatusInternalServerError, gin.H{"error": "Job updated in database but rescheduling failed: " + err.Error()})
+		// 	c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Job updated in database but rescheduling failed: " + err.Error()})
 		// 	return
 		// }
 
-		c.JSON(http.StatusOK, gin.H{
-			"id":      jobID,
-			"message": "Job updated and rescheduled successfully",
+		c.JSON(http.StatusOK, apitypes.ScheduledJobSavedResponse{
+			ID:      jobID,
+			Message: "Job updated and rescheduled successfully",
 		})
 	}
 }
@@ -2472,13 +2180,13 @@ func updateJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFun
 func GetQueueStatus(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get stats for all queue types
-		queueTypes := []string{"flight_search", "bulk_search", "bulk_search_route", "price_graph_sweep", "continuous_price_graph"}
+		queueTypes := []string{"flight_search", "pos_comparison", "bulk_search", "bulk_search_route", "price_graph_sweep", "continuous_price_graph"}
 		allStats := make(map[string]map[string]int64)
 
 		for _, queueType := range queueTypes {
 			stats, err := q.GetQueueStats(c.Request.Context(), queueType)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 				return
 			}
 			allStats[queueType] = stats
@@ -2490,7 +2198,7 @@ func GetQueueStatus(q queue.Queue) gin.HandlerFunc {
 
 func isAllowedQueueName(queueName string) bool {
 	switch queueName {
-	case "flight_search", "bulk_search", "bulk_search_route", "price_graph_sweep", "continuous_price_graph":
+	case "flight_search", "pos_comparison", "bulk_search", "bulk_search_route", "price_graph_sweep", "continuous_price_graph":
 		return true
 	default:
 		return false
@@ -2502,7 +2210,7 @@ func GetQueueBacklog(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
@@ -2515,14 +2223,14 @@ func GetQueueBacklog(q queue.Queue) gin.HandlerFunc {
 
 		jobs, err := q.GetBacklog(c.Request.Context(), queueName, limit)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue": queueName,
-			"limit": limit,
-			"jobs":  jobs,
+		c.JSON(http.StatusOK, apitypes.QueueBacklogResponse{
+			Queue: queueName,
+			Limit: limit,
+			Jobs:  convertQueueJobs(jobs),
 		})
 	}
 }
@@ -2532,7 +2240,7 @@ func ListQueueJobs(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
@@ -2552,17 +2260,17 @@ func ListQueueJobs(q queue.Queue) gin.HandlerFunc {
 
 		jobs, err := q.ListJobs(c.Request.Context(), queueName, state, limit, offset)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue":  queueName,
-			"state":  state,
-			"limit":  limit,
-			"offset": offset,
-			"count":  len(jobs),
-			"jobs":   jobs,
+		c.JSON(http.StatusOK, apitypes.QueueJobListResponse{
+			Queue:  queueName,
+			State:  state,
+			Limit:  limit,
+			Offset: offset,
+			Count:  len(jobs),
+			Jobs:   convertQueueJobs(jobs),
 		})
 	}
 }
@@ -2572,29 +2280,29 @@ func GetQueueJob(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
 		jobID := c.Param("id")
 		if jobID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing job id"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Missing job id"})
 			return
 		}
 
 		job, err := q.GetJob(c.Request.Context(), jobID)
 		if err != nil {
-			if errors.Is(err, redis.Nil) {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			if errors.Is(err, redis.Nil) || errors.Is(err, queue.ErrJobNotFound) {
+				c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Job not found"})
 				return
 			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue": queueName,
-			"job":   job,
+		c.JSON(http.StatusOK, apitypes.QueueJobResponse{
+			Queue: queueName,
+			Job:   convertQueueJob(job),
 		})
 	}
 }
@@ -2604,7 +2312,7 @@ func GetQueueEnqueueMetrics(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
@@ -2617,14 +2325,42 @@ func GetQueueEnqueueMetrics(q queue.Queue) gin.HandlerFunc {
 
 		stats, err := q.GetEnqueueMetrics(c.Request.Context(), queueName, minutes)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue":   queueName,
-			"minutes": minutes,
-			"sources": stats,
+		c.JSON(http.StatusOK, apitypes.QueueEnqueueMetricsResponse{
+			Queue:   queueName,
+			Minutes: minutes,
+			Sources: stats,
+		})
+	}
+}
+
+// GetRateLimitStats shows how long the requests to Google waited for the rate limiter of this
+// process (admin/debug endpoint).
+func GetRateLimitStats(cfg config.RateLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats := ratelimit.Stats()
+		kinds := make(map[string]apitypes.RateLimitKindStats, len(stats))
+		for kind, s := range stats {
+			avgWaitMs := int64(0)
+			if s.Delayed > 0 {
+				avgWaitMs = s.TotalWait.Milliseconds() / s.Delayed
+			}
+			kinds[kind] = apitypes.RateLimitKindStats{
+				Requests:    s.Requests,
+				Delayed:     s.Delayed,
+				Fallbacks:   s.Fallbacks,
+				TotalWaitMs: s.TotalWait.Milliseconds(),
+				AvgWaitMs:   avgWaitMs,
+				MaxWaitMs:   s.MaxWait.Milliseconds(),
+			}
+		}
+
+		c.JSON(http.StatusOK, apitypes.RateLimitStatsResponse{
+			Enabled: cfg.Enabled && ratelimit.Default() != nil,
+			Kinds:   kinds,
 		})
 	}
 }
@@ -2634,26 +2370,26 @@ func ClearQueue(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
 		cleared, err := q.ClearQueue(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		stats, err := q.GetQueueStats(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue":   queueName,
-			"cleared": cleared,
-			"stats":   stats,
+		c.JSON(http.StatusOK, apitypes.QueueActionResponse{
+			Queue:   queueName,
+			Cleared: &cleared,
+			Stats:   stats,
 		})
 	}
 }
@@ -2663,26 +2399,26 @@ func ClearQueueFailed(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
 		cleared, err := q.ClearFailed(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		stats, err := q.GetQueueStats(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue":   queueName,
-			"cleared": cleared,
-			"stats":   stats,
+		c.JSON(http.StatusOK, apitypes.QueueActionResponse{
+			Queue:   queueName,
+			Cleared: &cleared,
+			Stats:   stats,
 		})
 	}
 }
@@ -2692,26 +2428,26 @@ func ClearQueueProcessing(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
 		cleared, err := q.ClearProcessing(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		stats, err := q.GetQueueStats(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue":   queueName,
-			"cleared": cleared,
-			"stats":   stats,
+		c.JSON(http.StatusOK, apitypes.QueueActionResponse{
+			Queue:   queueName,
+			Cleared: &cleared,
+			Stats:   stats,
 		})
 	}
 }
@@ -2721,7 +2457,7 @@ func RetryQueueFailed(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
@@ -2734,21 +2470,21 @@ func RetryQueueFailed(q queue.Queue) gin.HandlerFunc {
 
 		retried, err := q.RetryFailed(c.Request.Context(), queueName, limit)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		stats, err := q.GetQueueStats(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue":   queueName,
-			"retried": retried,
-			"limit":   limit,
-			"stats":   stats,
+		c.JSON(http.StatusOK, apitypes.QueueActionResponse{
+			Queue:   queueName,
+			Retried: &retried,
+			Limit:   limit,
+			Stats:   stats,
 		})
 	}
 }
@@ -2758,25 +2494,25 @@ func CancelQueueJob(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
 		jobID := c.Param("id")
 		if jobID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing job id"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Missing job id"})
 			return
 		}
 
 		if err := q.CancelJob(c.Request.Context(), queueName, jobID); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue":   queueName,
-			"job_id":  jobID,
-			"message": "Cancel requested",
+		c.JSON(http.StatusOK, apitypes.QueueCancelJobResponse{
+			Queue:   queueName,
+			JobID:   jobID,
+			Message: "Cancel requested",
 		})
 	}
 }
@@ -2786,26 +2522,26 @@ func CancelQueueProcessing(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
 		canceled, err := q.CancelProcessing(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		stats, err := q.GetQueueStats(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue":    queueName,
-			"canceled": canceled,
-			"stats":    stats,
+		c.JSON(http.StatusOK, apitypes.QueueActionResponse{
+			Queue:    queueName,
+			Canceled: &canceled,
+			Stats:    stats,
 		})
 	}
 }
@@ -2815,33 +2551,33 @@ func DrainQueue(q queue.Queue) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		queueName := c.Param("name")
 		if !isAllowedQueueName(queueName) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue name"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid queue name"})
 			return
 		}
 
 		canceled, err := q.CancelProcessing(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		cleared, err := q.ClearQueue(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		stats, err := q.GetQueueStats(c.Request.Context(), queueName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue":    queueName,
-			"canceled": canceled,
-			"cleared":  cleared,
-			"stats":    stats,
+		c.JSON(http.StatusOK, apitypes.QueueActionResponse{
+			Queue:    queueName,
+			Canceled: &canceled,
+			Cleared:  &cleared,
+			Stats:    stats,
 		})
 	}
 }
@@ -2852,7 +2588,7 @@ func getJobById(pgDB db.PostgresDB) gin.HandlerFunc { // Changed parameter type
 		id := c.Param("id")
 		jobID, err := strconv.Atoi(id)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid job ID"})
 			return
 		}
 
@@ -2861,9 +2597,9 @@ func getJobById(pgDB db.PostgresDB) gin.HandlerFunc { // Changed parameter type
 		if err != nil {
 			// TODO: Check for specific not found error type
 			if strings.Contains(err.Error(), "not found") {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+				c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Job not found"})
 			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get job: " + err.Error()})
 			}
 			return
 		}
@@ -2872,112 +2608,93 @@ func getJobById(pgDB db.PostgresDB) gin.HandlerFunc { // Changed parameter type
 		details, err := pgDB.GetJobDetailsByID(c.Request.Context(), jobID)
 		if err != nil {
 			// If job exists but details don't, it's an inconsistency, but handle gracefully
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job details: " + err.Error()})
-			return
-		}
-
-		// Build the response
-		jobMap := map[string]interface{}{
-			"id":              job.ID,
-			"name":            job.Name,
-			"cron_expression": job.CronExpression,
-			"enabled":         job.Enabled,
-			"created_at":      job.CreatedAt,
-			"updated_at":      job.UpdatedAt,
-			"details": map[string]interface{}{
-				"origin":               details.Origin,
-				"destination":          details.Destination,
-				"departure_date_start": details.DepartureDateStart.Format("2006-01-02"), // Format dates
-				"departure_date_end":   details.DepartureDateEnd.Format("2006-01-02"),
-				"adults":               details.Adults,
-				"children":             details.Children,
-				"infants_lap":          details.InfantsLap,
-				"infants_seat":         details.InfantsSeat,
-				"trip_type":            details.TripType,
-				"class":                details.Class,
-				"stops":                details.Stops,
-				"currency":             details.Currency, // Include currency
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get job details: " + err.Error()})
+			return
+		}
+
+		response := apitypes.ScheduledJobResponse{
+			ID:             job.ID,
+			Name:           job.Name,
+			CronExpression: job.CronExpression,
+			Enabled:        job.Enabled,
+			LastRun:        maybeNullTime(job.LastRun),
+			CreatedAt:      job.CreatedAt,
+			UpdatedAt:      job.UpdatedAt,
+			Details: apitypes.ScheduledJobDetails{
+				Origin:             details.Origin,
+				Destination:        details.Destination,
+				DynamicDates:       details.DynamicDates,
+				DaysFromExecution:  maybeNullInt(details.DaysFromExecution),
+				SearchWindowDays:   maybeNullInt(details.SearchWindowDays),
+				TripLength:         maybeNullInt(details.TripLength),
+				DepartureDateStart: details.DepartureDateStart.Format(dateLayout),
+				DepartureDateEnd:   details.DepartureDateEnd.Format(dateLayout),
+				Adults:             details.Adults,
+				Children:           details.Children,
+				InfantsLap:         details.InfantsLap,
+				InfantsSeat:        details.InfantsSeat,
+				TripType:           details.TripType,
+				Class:              details.Class,
+				Stops:              details.Stops,
+				Currency:           details.Currency,
 			},
 		}
-
-		if job.LastRun.Valid {
-			jobMap["last_run"] = job.LastRun.Time
-		} else {
-			jobMap["last_run"] = nil
-		}
-
-		detailsMap := jobMap["details"].(map[string]interface{})
 		if details.ReturnDateStart.Valid {
-			detailsMap["return_date_start"] = details.ReturnDateStart.Time.Format("2006-01-02")
-		} else {
-			detailsMap["return_date_start"] = nil
+			start := details.ReturnDateStart.Time.Format(dateLayout)
+			response.Details.ReturnDateStart = &start
 		}
-
 		if details.ReturnDateEnd.Valid {
-			detailsMap["return_date_end"] = details.ReturnDateEnd.Time.Format("2006-01-02")
-		} else {
-			detailsMap["return_date_end"] = nil
+			end := details.ReturnDateEnd.Time.Format(dateLayout)
+			response.Details.ReturnDateEnd = &end
 		}
 
-		if details.TripLength.Valid {
-			detailsMap["trip_length"] = details.TripLength.Int32
-		} else {
-			detailsMap["trip_length"] = nil
-		}
-
-		c.JSON(http.StatusOK, jobMap)
+		c.JSON(http.StatusOK, response)
 	}
 }
 
-// DirectSearchRequest represents a direct flight search request (non-queued)
-type DirectSearchRequest struct {
-	Origin        string `json:"origin" form:"origin"`
-	Destination   string `json:"destination" form:"destination"`
-	DepartureDate string `json:"departure_date" form:"departure_date"`
-	ReturnDate    string `json:"return_date" form:"return_date"`
-	TripType      string `json:"trip_type" form:"trip_type"`
-	Class         string `json:"class" form:"class"`
-	Stops         string `json:"stops" form:"stops"`
-	Adults        int    `json:"adults" form:"adults"`
-	Children      int    `json:"children" form:"children"`
-	InfantsLap    int    `json:"infants_lap" form:"infants_lap"`
-	InfantsSeat   int    `json:"infants_seat" form:"infants_seat"`
-	Currency      string `json:"currency" form:"currency"`
-
-	IncludePriceGraph           bool   `json:"include_price_graph" form:"include_price_graph"`
-	PriceGraphWindowDays        int    `json:"price_graph_window_days" form:"price_graph_window_days"`
-	PriceGraphDepartureDateFrom string `json:"price_graph_departure_date_from" form:"price_graph_departure_date_from"`
-	PriceGraphDepartureDateTo   string `json:"price_graph_departure_date_to" form:"price_graph_departure_date_to"`
-	PriceGraphTripLengthDays    int    `json:"price_graph_trip_length_days" form:"price_graph_trip_length_days"`
-	PriceGraphTopN              int    `json:"price_graph_top_n" form:"price_graph_top_n"`
-
-	// DebugBatches enables per-batch diagnostics in multi-route searches.
-	// Intended for troubleshooting missing origin→destination pairs.
-	DebugBatches bool `json:"debug_batches" form:"debug_batches"`
-
-	// Airline group filtering (best-effort).
-	// - If IncludeAirlineGroups is non-empty, offers must match at least one included group.
-	// - If ExcludeAirlineGroups is non-empty, offers matching any excluded group are removed.
-	//
-	// Group tokens are like: GROUP:LOW_COST, GROUP:STAR_ALLIANCE, GROUP:ONEWORLD, GROUP:SKYTEAM.
-	IncludeAirlineGroups []string `json:"include_airline_groups" form:"include_airline_groups"`
-	ExcludeAirlineGroups []string `json:"exclude_airline_groups" form:"exclude_airline_groups"`
-
-	// Carriers optionally restricts results at the Google query level (affects offers and calendar graph).
-	// Values: airline IATA 2-letter codes (e.g., "UA", "DL") and/or alliance strings
-	// ("STAR_ALLIANCE", "ONEWORLD", "SKYTEAM").
-	//
-	// NOTE: This is an inclusive filter. Exclusion semantics are not supported here; use group filters
-	// for post-fetch filtering.
-	Carriers []string `json:"carriers" form:"carriers"`
+// convertFlightSegments renders flights in the response format of direct searches and appends
+// their airline codes to airlineCodes.
+func convertFlightSegments(legs []flights.Flight, airlineCodes *[]string) []apitypes.FlightSegment {
+	segments := make([]apitypes.FlightSegment, 0, len(legs))
+	for _, flight := range legs {
+		airlineCode := macros.ExtractAirlineCodeFromFlightNumber(flight.FlightNumber)
+		segments = append(segments, apitypes.FlightSegment{
+			DepartureAirport: flight.DepAirportCode,
+			ArrivalAirport:   flight.ArrAirportCode,
+			DepartureTime:    flight.DepTime.Format(time.RFC3339),
+			ArrivalTime:      flight.ArrTime.Format(time.RFC3339),
+			Airline:          flight.AirlineName,
+			AirlineCode:      airlineCode,
+			FlightNumber:     flight.FlightNumber,
+			Duration:         int(flight.Duration.Minutes()),
+			Airplane:         flight.Airplane,
+			Legroom:          flight.Legroom,
+			Amenities: apitypes.FlightAmenities{
+				WiFi:          flight.Amenities.WiFi,
+				Power:         flight.Amenities.Power,
+				Entertainment: flight.Amenities.Entertainment,
+			},
+			Overnight:         flight.Overnight,
+			OftenDelayed:      flight.OftenDelayed,
+			OperatingCarrier:  flight.OperatingCarrier,
+			Codeshares:        flight.Codeshares,
+			CO2EmissionsGrams: flight.CO2Emissions,
+			SeatPitchInches:   flight.SeatPitch,
+		})
+		if airlineCode != "" {
+			*airlineCodes = append(*airlineCodes, airlineCode)
+		}
+	}
+	return segments
 }
 
-// DirectFlightSearch handles direct flight searches (bypasses queue for immediate results)
-func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.HandlerFunc {
+// DirectFlightSearch handles direct flight searches (bypasses queue for immediate results). The
+// Google sessions are drawn from sessions, the pool shared by the direct searches.
+func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase, sessions *flights.SessionPool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		log.Println("Direct search flights handler called")
 
-		var searchRequest DirectSearchRequest
+		var searchRequest apitypes.DirectSearchRequest
 
 		// Try to bind JSON first, then form data if that fails
 		if err := c.ShouldBindJSON(&searchRequest); err != nil {
@@ -3003,6 +2720,10 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 		if searchRequest.TripType == "" {
 			searchRequest.TripType = "one_way"
 		}
+		if searchRequest.TripType == "multi_city" {
+			directMultiCitySearch(c, pgDB, neo4jDB, sessions, searchRequest)
+			return
+		}
 		if searchRequest.IncludePriceGraph && searchRequest.PriceGraphTopN <= 0 {
 			searchRequest.PriceGraphTopN = 10
 		}
@@ -3012,13 +2733,27 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 
 		originTokens, destinationTokens, err := ParseRouteInputs(searchRequest.Origin, searchRequest.Destination)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		if searchRequest.MinLayoverMinutes < 0 || searchRequest.MaxLayoverMinutes < 0 ||
+			(searchRequest.MaxLayoverMinutes > 0 && searchRequest.MinLayoverMinutes > searchRequest.MaxLayoverMinutes) {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "invalid layover limits: min_layover_minutes and max_layover_minutes must be non-negative and min must not exceed max"})
+			return
+		}
+		minLayover := time.Duration(searchRequest.MinLayoverMinutes) * time.Minute
+		maxLayover := time.Duration(searchRequest.MaxLayoverMinutes) * time.Minute
+
+		if searchRequest.CarryOnBags < 0 || searchRequest.CheckedBags < 0 {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "invalid bags: carry_on_bags and checked_bags must be non-negative"})
 			return
 		}
+		requiredBags := flights.Bags{CarryOn: searchRequest.CarryOnBags, Checked: searchRequest.CheckedBags}
 
 		plan, err := PlanDirectSearchDates(time.Now().UTC(), searchRequest)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
@@ -3037,11 +2772,11 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 			}
 		}
 
-		// Create flight session
-		session, err := flights.New()
+		// Draw a flight session from the shared pool
+		session, err := sessions.Get(c.Request.Context())
 		if err != nil {
 			log.Printf("Error creating flight session: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize flight search"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to initialize flight search"})
 			return
 		}
 
@@ -3061,6 +2796,8 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 				Class:         searchRequest.Class,
 				Stops:         searchRequest.Stops,
 				Currency:      searchRequest.Currency,
+				Country:       searchRequest.Country,
+				GoogleHost:    searchRequest.GoogleHost,
 			}
 
 			switch {
@@ -3135,11 +2872,18 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 				InfantOnLap:  searchRequest.InfantsLap,
 				InfantInSeat: searchRequest.InfantsSeat,
 			},
-			Currency: cur,
-			Stops:    stops,
-			Class:    class,
-			TripType: tripType,
-			Lang:     language.English,
+			Currency:       cur,
+			Stops:          stops,
+			Class:          class,
+			TripType:       tripType,
+			Lang:           language.English,
+			Country:        searchRequest.Country,
+			GoogleHost:     searchRequest.GoogleHost,
+			TimezoneOffset: searchRequest.TZOffsetMin,
+		}
+		if err := baseOptions.ValidateMarket(); err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
 		}
 
 		priceGraphParams := plan.PriceGraph
@@ -3199,7 +2943,7 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 			baseOptions.Carriers = append([]string{}, searchRequest.Carriers...)
 		}
 
-		maybeGetPriceGraph := func(routeOrigin, routeDestination string) map[string]interface{} {
+		maybeGetPriceGraph := func(routeOrigin, routeDestination string) *apitypes.PriceGraph {
 			if !searchRequest.IncludePriceGraph {
 				return nil
 			}
@@ -3213,23 +2957,21 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 			response := SerializePriceGraphResponse(routeOrigin, routeDestination, searchRequest.Currency, args, offers, parseErrors, pgErr)
 			if len(searchRequest.IncludeAirlineGroups) > 0 || len(searchRequest.ExcludeAirlineGroups) > 0 {
 				if len(baseOptions.Carriers) == 0 {
-					response["filter_warning"] = "Google price graph could not apply the selected airline-group filters; it reflects all carriers returned by Google for the route/date range."
+					response.FilterWarning = "Google price graph could not apply the selected airline-group filters; it reflects all carriers returned by Google for the route/date range."
 				} else {
-					response["filter_note"] = map[string]interface{}{
-						"google_carriers": baseOptions.Carriers,
-					}
+					response.FilterNote = &apitypes.PriceGraphFilterNote{GoogleCarriers: baseOptions.Carriers}
 				}
-				response["filter"] = map[string]interface{}{
-					"include_airline_groups": searchRequest.IncludeAirlineGroups,
-					"exclude_airline_groups": searchRequest.ExcludeAirlineGroups,
+				response.Filter = &apitypes.AirlineGroupFilter{
+					IncludeAirlineGroups: searchRequest.IncludeAirlineGroups,
+					ExcludeAirlineGroups: searchRequest.ExcludeAirlineGroups,
 				}
 			}
 			if pgErr != nil {
 				return response
 			}
 
-			points, ok := response["points"].([]map[string]interface{})
-			if !ok || len(points) == 0 {
+			points := response.Points
+			if len(points) == 0 {
 				return response
 			}
 
@@ -3254,7 +2996,7 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 					},
 				)
 				if urlErr == nil && url != "" {
-					points[i]["google_flights_url"] = url
+					points[i].GoogleFlightsURL = url
 				}
 
 				// Best-effort persistence of price graph points for history/tracking.
@@ -3285,15 +3027,24 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 					}
 				}
 			}
-			response["points"] = points
 			if searchRequest.PriceGraphTopN > 0 {
-				if top := TopPriceGraphPoints(points, searchRequest.PriceGraphTopN); len(top) > 0 {
-					response["top_points"] = top
-				}
+				response.TopPoints = TopPriceGraphPoints(points, searchRequest.PriceGraphTopN)
 			}
 			return response
 		}
 
+		// Round-trip offers only carry the outbound itinerary; request the return legs of the
+		// cheapest ones so they can be shown and persisted. Each return leg is one more request.
+		const directReturnLegLimit = 5
+		fillReturnFlights := func(args flights.Args, offers []flights.FullOffer) {
+			if args.TripType != flights.RoundTrip || len(offers) == 0 {
+				return
+			}
+			if err := session.FillReturnFlights(c.Request.Context(), args, offers, directReturnLegLimit); err != nil {
+				log.Printf("Error getting return flights for %v->%v: %v", args.SrcAirports, args.DstAirports, err)
+			}
+		}
+
 		offerAirlineCodes := func(offer flights.FullOffer) []string {
 			out := make([]string, 0, len(offer.Flight)+len(offer.ReturnFlight))
 			for _, flight := range offer.Flight {
@@ -3349,14 +3100,21 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 			return out, len(offers) - len(out)
 		}
 
-		expandedOrigins, originWarnings, err := macros.ExpandAirportTokens(originTokens)
+		nearOverrides, nearbyAirports, err := nearbyAirportOverrides(c.Request.Context(), pgDB, originTokens, destinationTokens)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+		nearbyByCode := nearbyAirportsByCode(nearbyAirports)
+
+		expandedOrigins, originWarnings, err := macros.ExpandAirportTokensWithOverrides(originTokens, nearOverrides)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid origin: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid origin: " + err.Error()})
 			return
 		}
-		expandedDestinations, destinationWarnings, err := macros.ExpandAirportTokens(destinationTokens)
+		expandedDestinations, destinationWarnings, err := macros.ExpandAirportTokensWithOverrides(destinationTokens, nearOverrides)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid destination: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid destination: " + err.Error()})
 			return
 		}
 
@@ -3370,52 +3128,27 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 		// (e.g., REGION:CARIBBEAN) can still be used as either origins or destinations.
 		const maxDirectAirportsPerSide = maxDirectAirportsTotal
 		if len(expandedOrigins) > maxDirectAirportsPerSide || len(expandedDestinations) > maxDirectAirportsPerSide || (len(expandedOrigins)+len(expandedDestinations)) > maxDirectAirportsTotal {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf(
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{
+				Error: fmt.Sprintf(
 					"too many airports after expansion (origins=%d, destinations=%d; max %d total across origins+destinations). Narrow your inputs or use /api/v1/bulk-search for large grids.",
 					len(expandedOrigins),
 					len(expandedDestinations),
 					maxDirectAirportsTotal,
 				),
-				"warnings": warnings,
+				Warnings: warnings,
 			})
 			return
 		}
 
-		convertOffers := func(routeOrigin, routeDestination string, offers []flights.FullOffer) ([]map[string]interface{}, float64, map[string]interface{}, bool) {
-			responseOffers := make([]map[string]interface{}, 0, len(offers))
-			var cheapest map[string]interface{}
+		convertOffers := func(routeOrigin, routeDestination string, offers []flights.FullOffer) ([]apitypes.DirectSearchOffer, float64, *apitypes.DirectSearchOffer, bool) {
+			responseOffers := make([]apitypes.DirectSearchOffer, 0, len(offers))
+			cheapestIndex := -1
 			var cheapestPrice float64
-			cheapestSet := false
 
 			for i, offer := range offers {
-				segments := make([]map[string]interface{}, 0, len(offer.Flight))
-				airlineCodes := make([]string, 0, len(offer.Flight))
-				for _, flight := range offer.Flight {
-					airlineCode := macros.ExtractAirlineCodeFromFlightNumber(flight.FlightNumber)
-					segment := map[string]interface{}{
-						"departure_airport": flight.DepAirportCode,
-						"arrival_airport":   flight.ArrAirportCode,
-						"departure_time":    flight.DepTime.Format(time.RFC3339),
-						"arrival_time":      flight.ArrTime.Format(time.RFC3339),
-						"airline":           flight.AirlineName,
-						"airline_code":      airlineCode,
-						"flight_number":     flight.FlightNumber,
-						"duration":          int(flight.Duration.Minutes()),
-						"airplane":          flight.Airplane,
-						"legroom":           flight.Legroom,
-					}
-					segments = append(segments, segment)
-					if airlineCode != "" {
-						airlineCodes = append(airlineCodes, airlineCode)
-					}
-				}
-				for _, flight := range offer.ReturnFlight {
-					airlineCode := macros.ExtractAirlineCodeFromFlightNumber(flight.FlightNumber)
-					if airlineCode != "" {
-						airlineCodes = append(airlineCodes, airlineCode)
-					}
-				}
+				airlineCodes := make([]string, 0, len(offer.Flight)+len(offer.ReturnFlight))
+				segments := convertFlightSegments(offer.Flight, &airlineCodes)
+				returnSegments := convertFlightSegments(offer.ReturnFlight, &airlineCodes)
 
 				airlineGroups := macros.AirlineGroupsForCodes(airlineCodes)
 
@@ -3434,29 +3167,47 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 					googleFlightsUrl = ""
 				}
 
-				responseOffer := map[string]interface{}{
-					"id":                 fmt.Sprintf("offer%d", i+1),
-					"price":              offer.Price,
-					"price_available":    offer.Price > 0,
-					"currency":           searchRequest.Currency,
-					"total_duration":     int(offer.FlightDuration.Minutes()),
-					"segments":           segments,
-					"departure_date":     offer.StartDate.Format("2006-01-02"),
-					"return_date":        offer.ReturnDate.Format("2006-01-02"),
-					"google_flights_url": googleFlightsUrl,
-					"airline_groups":     airlineGroups,
+				responseOffer := apitypes.DirectSearchOffer{
+					ID:               fmt.Sprintf("offer%d", i+1),
+					Price:            offer.Price,
+					PriceAvailable:   offer.Price > 0,
+					Currency:         searchRequest.Currency,
+					TotalDuration:    int(offer.FlightDuration.Minutes()),
+					Segments:         segments,
+					DepartureDate:    offer.StartDate.Format("2006-01-02"),
+					ReturnDate:       offer.ReturnDate.Format("2006-01-02"),
+					GoogleFlightsURL: googleFlightsUrl,
+					AirlineGroups:    airlineGroups,
+					Layovers:         convertLayovers(offer.Layovers),
+					ReturnLayovers:   convertLayovers(offer.ReturnLayovers),
+					Fare: &apitypes.Fare{
+						BasicEconomy:   offer.Fare.BasicEconomy,
+						SeatSelection:  offer.Fare.SeatSelection,
+						BagsUnverified: offer.Fare.BagsUnverified,
+					},
+				}
+				if len(returnSegments) > 0 {
+					responseOffer.ReturnSegments = returnSegments
+					responseOffer.ReturnDuration = int(offer.ReturnFlightDuration.Minutes())
+				}
+				if offer.Fare.BaggageKnown {
+					carryOn, checked := offer.Fare.CarryOnBags, offer.Fare.CheckedBags
+					responseOffer.Fare.CarryOnBags = &carryOn
+					responseOffer.Fare.CheckedBags = &checked
 				}
 
-				if offer.Price > 0 && (!cheapestSet || offer.Price < cheapestPrice) {
-					cheapestSet = true
+				if offer.Price > 0 && (cheapestIndex < 0 || offer.Price < cheapestPrice) {
+					cheapestIndex = len(responseOffers)
 					cheapestPrice = offer.Price
-					cheapest = responseOffer
 				}
 
 				responseOffers = append(responseOffers, responseOffer)
 			}
 
-			return responseOffers, cheapestPrice, cheapest, cheapestSet
+			if cheapestIndex < 0 {
+				return responseOffers, 0, nil, false
+			}
+			return responseOffers, cheapestPrice, &responseOffers[cheapestIndex], true
 		}
 
 		// Single route: preserve the legacy response shape.
@@ -3465,107 +3216,117 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 			searchRequest.Destination = expandedDestinations[0]
 
 			if priceGraphOnly {
-				response := map[string]interface{}{
-					"offers":           []map[string]interface{}{},
-					"search_params":    searchRequest,
-					"price_graph_only": true,
-				}
-
-				if priceGraph := maybeGetPriceGraph(searchRequest.Origin, searchRequest.Destination); priceGraph != nil {
-					response["price_graph"] = priceGraph
-				}
-
-				c.JSON(http.StatusOK, response)
+				c.JSON(http.StatusOK, apitypes.DirectSearchResponse{
+					Offers:         []apitypes.DirectSearchOffer{},
+					SearchParams:   apitypes.DirectSearchParams{DirectSearchRequest: searchRequest},
+					PriceGraphOnly: true,
+					PriceGraph:     maybeGetPriceGraph(searchRequest.Origin, searchRequest.Destination),
+				})
 				return
 			}
 
-			offers, priceRange, err := session.GetOffers(
-				c.Request.Context(),
-				flights.Args{
-					Date:        departureDate,
-					ReturnDate:  returnDate,
-					SrcAirports: []string{searchRequest.Origin},
-					DstAirports: []string{searchRequest.Destination},
-					Options:     baseOptions,
-				},
-			)
+			args := flights.Args{
+				Date:                departureDate,
+				ReturnDate:          returnDate,
+				SrcAirports:         []string{searchRequest.Origin},
+				DstAirports:         []string{searchRequest.Destination},
+				Options:             baseOptions,
+				MinLayover:          minLayover,
+				MaxLayover:          maxLayover,
+				ExcludeBasicEconomy: searchRequest.ExcludeBasicEconomy,
+				RequiredBags:        requiredBags,
+			}
+			offers, priceRange, err := session.GetOffers(c.Request.Context(), args)
 
 			if err != nil {
 				log.Printf("Error searching flights: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search flights: " + err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to search flights: " + err.Error()})
 				return
 			}
+			fillReturnFlights(args, offers)
 
 			persistOffers(searchRequest.Origin, searchRequest.Destination, offers, priceRange)
 
 			filteredOffers, filteredOut := filterOffers(offers)
 			responseOffers, _, _, _ := convertOffers(searchRequest.Origin, searchRequest.Destination, filteredOffers)
-			response := map[string]interface{}{
-				"offers":        responseOffers,
-				"search_params": searchRequest,
+			response := apitypes.DirectSearchResponse{
+				Offers:       responseOffers,
+				SearchParams: apitypes.DirectSearchParams{DirectSearchRequest: searchRequest},
+				PriceRange:   convertPriceRange(priceRange),
+				PriceGraph:   maybeGetPriceGraph(searchRequest.Origin, searchRequest.Destination),
 			}
 			if filteredOut > 0 {
-				response["filter"] = map[string]interface{}{
-					"include_airline_groups": searchRequest.IncludeAirlineGroups,
-					"exclude_airline_groups": searchRequest.ExcludeAirlineGroups,
-					"filtered_out":           filteredOut,
-				}
-			}
-
-			if priceRange != nil {
-				response["price_range"] = map[string]interface{}{
-					"low":  priceRange.Low,
-					"high": priceRange.High,
+				response.Filter = &apitypes.AirlineGroupFilter{
+					IncludeAirlineGroups: searchRequest.IncludeAirlineGroups,
+					ExcludeAirlineGroups: searchRequest.ExcludeAirlineGroups,
+					FilteredOut:          filteredOut,
 				}
 			}
 
-			if priceGraph := maybeGetPriceGraph(searchRequest.Origin, searchRequest.Destination); priceGraph != nil {
-				response["price_graph"] = priceGraph
-			}
-
 			c.JSON(http.StatusOK, response)
 			return
 		}
 
+		var groupFilter *apitypes.AirlineGroupFilter
+		if len(searchRequest.IncludeAirlineGroups) > 0 || len(searchRequest.ExcludeAirlineGroups) > 0 {
+			groupFilter = &apitypes.AirlineGroupFilter{
+				IncludeAirlineGroups: searchRequest.IncludeAirlineGroups,
+				ExcludeAirlineGroups: searchRequest.ExcludeAirlineGroups,
+			}
+		}
+		gridSearchParams := func(returnedRoutes, offersCount int, priceGraphOnly bool) apitypes.DirectSearchParams {
+			return apitypes.DirectSearchParams{
+				DirectSearchRequest: searchRequest,
+				DirectSearchGrid: &apitypes.DirectSearchGrid{
+					Origins:             expandedOrigins,
+					Destinations:        expandedDestinations,
+					RequestedRouteCount: totalRoutes,
+					ReturnedRouteCount:  returnedRoutes,
+					OffersCount:         offersCount,
+					PriceGraphOnly:      priceGraphOnly,
+				},
+			}
+		}
+
 		if priceGraphOnly {
 			const maxDirectRouteFallback = 16
 			if totalRoutes > maxDirectRouteFallback {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": fmt.Sprintf(
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{
+					Error: fmt.Sprintf(
 						"price-graph-only mode supports up to %d routes per request (requested %d). Narrow your route grid or use the admin price-graph sweep pipeline.",
 						maxDirectRouteFallback,
 						totalRoutes,
 					),
-					"warnings": warnings,
+					Warnings: warnings,
 				})
 				return
 			}
 
-			routes := make([]map[string]interface{}, 0, totalRoutes)
+			routes := make([]apitypes.DirectSearchRoute, 0, totalRoutes)
 			for _, origin := range expandedOrigins {
 				for _, destination := range expandedDestinations {
-					route := map[string]interface{}{
-						"origin":      origin,
-						"destination": destination,
+					route := apitypes.DirectSearchRoute{
+						Origin:      origin,
+						Destination: destination,
 					}
+					annotateNearbyRoute(&route, nearbyByCode)
 
 					routeParams := searchRequest
 					routeParams.Origin = origin
 					routeParams.Destination = destination
-					route["search_params"] = routeParams
+					route.SearchParams = &routeParams
 
 					if priceGraph := maybeGetPriceGraph(origin, destination); priceGraph != nil {
-						route["google_price_graph"] = priceGraph
-
-						if top, ok := priceGraph["top_points"].([]map[string]interface{}); ok && len(top) > 0 {
-							first := top[0]
-							summary := map[string]interface{}{
-								"currency":       priceGraph["currency"],
-								"price":          first["price"],
-								"departure_date": first["departure_date"],
-								"return_date":    first["return_date"],
+						route.GooglePriceGraph = priceGraph
+
+						if len(priceGraph.TopPoints) > 0 {
+							first := priceGraph.TopPoints[0]
+							route.Summary = &apitypes.DirectSearchRouteSummary{
+								Currency:      priceGraph.Currency,
+								Price:         first.Price,
+								DepartureDate: first.DepartureDate,
+								ReturnDate:    first.ReturnDate,
 							}
-							route["summary"] = summary
 						}
 					}
 
@@ -3573,35 +3334,13 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 				}
 			}
 
-			response := map[string]interface{}{
-				"routes": routes,
-				"search_params": map[string]interface{}{
-					"origins":               expandedOrigins,
-					"destinations":          expandedDestinations,
-					"departure_date":        searchRequest.DepartureDate,
-					"return_date":           searchRequest.ReturnDate,
-					"trip_type":             searchRequest.TripType,
-					"class":                 searchRequest.Class,
-					"stops":                 searchRequest.Stops,
-					"adults":                searchRequest.Adults,
-					"children":              searchRequest.Children,
-					"infants_lap":           searchRequest.InfantsLap,
-					"infants_seat":          searchRequest.InfantsSeat,
-					"currency":              searchRequest.Currency,
-					"requested_route_count": totalRoutes,
-					"returned_route_count":  len(routes),
-					"price_graph_only":      true,
-				},
-				"price_graph_only": true,
-			}
-			if len(searchRequest.IncludeAirlineGroups) > 0 || len(searchRequest.ExcludeAirlineGroups) > 0 {
-				response["filter"] = map[string]interface{}{
-					"include_airline_groups": searchRequest.IncludeAirlineGroups,
-					"exclude_airline_groups": searchRequest.ExcludeAirlineGroups,
-				}
-			}
-			if len(warnings) > 0 {
-				response["warnings"] = warnings
+			response := apitypes.DirectSearchResponse{
+				Routes:         routes,
+				SearchParams:   gridSearchParams(len(routes), 0, true),
+				PriceGraphOnly: true,
+				Filter:         groupFilter,
+				Warnings:       warnings,
+				NearbyAirports: convertNearbyAirports(nearbyAirports),
 			}
 
 			c.JSON(http.StatusOK, response)
@@ -3612,8 +3351,8 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 		// Fallback to per-route queries for small grids, since Google responses can vary with multi-airport queries.
 		const maxDirectRouteFallback = 16
 		if totalRoutes <= maxDirectRouteFallback {
-			routes := make([]map[string]interface{}, 0, totalRoutes)
-			var overallCheapestOffer map[string]interface{}
+			routes := make([]apitypes.DirectSearchRoute, 0, totalRoutes)
+			var overallCheapestOffer *apitypes.DirectSearchOffer
 			var overallCheapestOrigin string
 			var overallCheapestDestination string
 			var overallCheapestPrice float64
@@ -3621,54 +3360,50 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 
 			for _, origin := range expandedOrigins {
 				for _, destination := range expandedDestinations {
-					offers, priceRange, err := session.GetOffers(
-						c.Request.Context(),
-						flights.Args{
-							Date:        departureDate,
-							ReturnDate:  returnDate,
-							SrcAirports: []string{origin},
-							DstAirports: []string{destination},
-							Options:     baseOptions,
-						},
-					)
-
-					route := map[string]interface{}{
-						"origin":      origin,
-						"destination": destination,
+					args := flights.Args{
+						Date:                departureDate,
+						ReturnDate:          returnDate,
+						SrcAirports:         []string{origin},
+						DstAirports:         []string{destination},
+						Options:             baseOptions,
+						MinLayover:          minLayover,
+						MaxLayover:          maxLayover,
+						ExcludeBasicEconomy: searchRequest.ExcludeBasicEconomy,
+						RequiredBags:        requiredBags,
+					}
+					offers, priceRange, err := session.GetOffers(c.Request.Context(), args)
+
+					route := apitypes.DirectSearchRoute{
+						Origin:      origin,
+						Destination: destination,
 					}
+					annotateNearbyRoute(&route, nearbyByCode)
 
 					if err != nil {
 						log.Printf("Error searching flights for %s->%s: %v", origin, destination, err)
-						route["error"] = "Failed to search flights: " + err.Error()
+						route.Error = "Failed to search flights: " + err.Error()
 						routes = append(routes, route)
 						continue
 					}
+					fillReturnFlights(args, offers)
 
 					persistOffers(origin, destination, offers, priceRange)
 
 					filteredOffers, filteredOut := filterOffers(offers)
 					routeOffers, routeCheapestPrice, routeCheapestOffer, routeCheapestSet := convertOffers(origin, destination, filteredOffers)
-					route["offers"] = routeOffers
+					route.Offers = routeOffers
 					if filteredOut > 0 {
-						route["filter"] = map[string]interface{}{
-							"filtered_out": filteredOut,
-						}
+						route.Filter = &apitypes.AirlineGroupFilter{FilteredOut: filteredOut}
 						if len(routeOffers) == 0 {
-							route["warning"] = "All offers were filtered out by airline group filters."
+							route.Warning = "All offers were filtered out by airline group filters."
 						}
 					}
 
 					routeParams := searchRequest
 					routeParams.Origin = origin
 					routeParams.Destination = destination
-					route["search_params"] = routeParams
-
-					if priceRange != nil {
-						route["price_range"] = map[string]interface{}{
-							"low":  priceRange.Low,
-							"high": priceRange.High,
-						}
-					}
+					route.SearchParams = &routeParams
+					route.PriceRange = convertPriceRange(priceRange)
 
 					if routeCheapestSet && routeCheapestOffer != nil && (!overallCheapestSet || routeCheapestPrice < overallCheapestPrice) {
 						overallCheapestSet = true
@@ -3682,53 +3417,27 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 				}
 			}
 
-			response := map[string]interface{}{
-				"routes": routes,
-				"search_params": map[string]interface{}{
-					"origins":               expandedOrigins,
-					"destinations":          expandedDestinations,
-					"departure_date":        searchRequest.DepartureDate,
-					"return_date":           searchRequest.ReturnDate,
-					"trip_type":             searchRequest.TripType,
-					"class":                 searchRequest.Class,
-					"stops":                 searchRequest.Stops,
-					"adults":                searchRequest.Adults,
-					"children":              searchRequest.Children,
-					"infants_lap":           searchRequest.InfantsLap,
-					"infants_seat":          searchRequest.InfantsSeat,
-					"currency":              searchRequest.Currency,
-					"requested_route_count": totalRoutes,
-					"returned_route_count":  len(routes),
-				},
-			}
-			if len(searchRequest.IncludeAirlineGroups) > 0 || len(searchRequest.ExcludeAirlineGroups) > 0 {
-				response["filter"] = map[string]interface{}{
-					"include_airline_groups": searchRequest.IncludeAirlineGroups,
-					"exclude_airline_groups": searchRequest.ExcludeAirlineGroups,
-				}
-			}
-			if len(warnings) > 0 {
-				response["warnings"] = warnings
+			response := apitypes.DirectSearchResponse{
+				Routes:         routes,
+				SearchParams:   gridSearchParams(len(routes), 0, false),
+				Filter:         groupFilter,
+				Warnings:       warnings,
+				NearbyAirports: convertNearbyAirports(nearbyAirports),
 			}
 			if overallCheapestSet && overallCheapestOffer != nil {
-				response["cheapest"] = map[string]interface{}{
-					"origin":      overallCheapestOrigin,
-					"destination": overallCheapestDestination,
-					"offer":       overallCheapestOffer,
-				}
-			}
-
-			if overallCheapestSet {
-				if priceGraph := maybeGetPriceGraph(overallCheapestOrigin, overallCheapestDestination); priceGraph != nil {
-					response["price_graph"] = priceGraph
+				response.Cheapest = &apitypes.DirectSearchCheapest{
+					Origin:      overallCheapestOrigin,
+					Destination: overallCheapestDestination,
+					Offer:       *overallCheapestOffer,
 				}
+				response.PriceGraph = maybeGetPriceGraph(overallCheapestOrigin, overallCheapestDestination)
 			}
 
 			c.JSON(http.StatusOK, response)
 			return
 		}
 
-		var overallCheapestOffer map[string]interface{}
+		var overallCheapestOffer *apitypes.DirectSearchOffer
 		var overallCheapestOrigin string
 		var overallCheapestDestination string
 		var overallCheapestPrice float64
@@ -3755,46 +3464,25 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 		const maxAirportsPerRequestSide = 10
 		const maxBatchedRequests = 64
 
-		type batchDiagnostics struct {
-			Mode         string `json:"mode"`
-			ChunkSize    int    `json:"chunk_size"`
-			TotalBatches int    `json:"total_batches"`
-			Failed       int    `json:"failed_batches"`
-			Empty        int    `json:"empty_batches"`
-			TotalOffers  int    `json:"total_offers"`
-			DurationMs   int64  `json:"duration_ms"`
-		}
-
-		type batchDebugEntry struct {
-			Mode                string         `json:"mode"`
-			Origin              string         `json:"origin,omitempty"`
-			Destination         string         `json:"destination,omitempty"`
-			Origins             []string       `json:"origins,omitempty"`
-			Destinations        []string       `json:"destinations,omitempty"`
-			Offers              int            `json:"offers"`
-			DurationMs          int64          `json:"duration_ms"`
-			Error               string         `json:"error,omitempty"`
-			DestinationOfferMap map[string]int `json:"destination_offer_map,omitempty"`
-			OriginOfferMap      map[string]int `json:"origin_offer_map,omitempty"`
-			MissingDestinations []string       `json:"missing_destinations,omitempty"`
-			MissingOrigins      []string       `json:"missing_origins,omitempty"`
-		}
-
-		diag := batchDiagnostics{ChunkSize: maxAirportsPerRequestSide}
-		debugEntries := make([]batchDebugEntry, 0, 16)
+		diag := apitypes.DirectSearchBatchSummary{ChunkSize: maxAirportsPerRequestSide}
+		debugEntries := make([]apitypes.DirectSearchBatchDebug, 0, 16)
 		batchStartOverall := time.Now()
 
 		queryArgs := func(srcAirports, dstAirports []string) flights.Args {
 			return flights.Args{
-				Date:        departureDate,
-				ReturnDate:  returnDate,
-				SrcAirports: srcAirports,
-				DstAirports: dstAirports,
-				Options:     baseOptions,
+				Date:                departureDate,
+				ReturnDate:          returnDate,
+				SrcAirports:         srcAirports,
+				DstAirports:         dstAirports,
+				Options:             baseOptions,
+				MinLayover:          minLayover,
+				MaxLayover:          maxLayover,
+				ExcludeBasicEconomy: searchRequest.ExcludeBasicEconomy,
+				RequiredBags:        requiredBags,
 			}
 		}
 
-		appendDebug := func(entry batchDebugEntry) {
+		appendDebug := func(entry apitypes.DirectSearchBatchDebug) {
 			if !searchRequest.DebugBatches {
 				return
 			}
@@ -3815,13 +3503,13 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 			totalBatches := len(expandedOrigins) * len(destChunks)
 			diag.TotalBatches = totalBatches
 			if totalBatches > maxBatchedRequests {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": fmt.Sprintf(
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{
+					Error: fmt.Sprintf(
 						"too many batched requests to execute safely (%d; max %d). Narrow your inputs or use /api/v1/bulk-search.",
 						totalBatches,
 						maxBatchedRequests,
 					),
-					"warnings": warnings,
+					Warnings: warnings,
 				})
 				return
 			}
@@ -3835,7 +3523,7 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 						log.Printf("Error searching flights for %s->* (chunk): %v", origin, err)
 						warnings = append(warnings, fmt.Sprintf("search failed for origin %s: %v", origin, err))
 						diag.Failed++
-						appendDebug(batchDebugEntry{
+						appendDebug(apitypes.DirectSearchBatchDebug{
 							Mode:         diag.Mode,
 							Origin:       origin,
 							Destinations: destChunk,
@@ -3865,7 +3553,7 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 							missingDests = append(missingDests, code)
 						}
 					}
-					appendDebug(batchDebugEntry{
+					appendDebug(apitypes.DirectSearchBatchDebug{
 						Mode:                diag.Mode,
 						Origin:              origin,
 						Destinations:        destChunk,
@@ -3883,13 +3571,13 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 			totalBatches := len(expandedDestinations) * len(originChunks)
 			diag.TotalBatches = totalBatches
 			if totalBatches > maxBatchedRequests {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": fmt.Sprintf(
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{
+					Error: fmt.Sprintf(
 						"too many batched requests to execute safely (%d; max %d). Narrow your inputs or use /api/v1/bulk-search.",
 						totalBatches,
 						maxBatchedRequests,
 					),
-					"warnings": warnings,
+					Warnings: warnings,
 				})
 				return
 			}
@@ -3903,7 +3591,7 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 						log.Printf("Error searching flights for *->%s (chunk): %v", destination, err)
 						warnings = append(warnings, fmt.Sprintf("search failed for destination %s: %v", destination, err))
 						diag.Failed++
-						appendDebug(batchDebugEntry{
+						appendDebug(apitypes.DirectSearchBatchDebug{
 							Mode:        diag.Mode,
 							Destination: destination,
 							Origins:     originChunk,
@@ -3933,7 +3621,7 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 							missingOrigins = append(missingOrigins, code)
 						}
 					}
-					appendDebug(batchDebugEntry{
+					appendDebug(apitypes.DirectSearchBatchDebug{
 						Mode:           diag.Mode,
 						Destination:    destination,
 						Origins:        originChunk,
@@ -3979,7 +3667,7 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 			return routeKeys[i].origin < routeKeys[j].origin
 		})
 
-		routes := make([]map[string]interface{}, 0, len(routeKeys))
+		routes := make([]apitypes.DirectSearchRoute, 0, len(routeKeys))
 		for _, rk := range routeKeys {
 			key := rk.origin + "->" + rk.destination
 			fullOffers := offersByRoute[key]
@@ -3991,31 +3679,30 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 
 			filteredOffers, filteredOut := filterOffers(fullOffers)
 			routeOffers, routeCheapestPrice, routeCheapestOffer, routeCheapestSet := convertOffers(rk.origin, rk.destination, filteredOffers)
-			route := map[string]interface{}{
-				"origin":      rk.origin,
-				"destination": rk.destination,
-				"offers":      routeOffers,
+			route := apitypes.DirectSearchRoute{
+				Origin:      rk.origin,
+				Destination: rk.destination,
+				Offers:      routeOffers,
 			}
+			annotateNearbyRoute(&route, nearbyByCode)
 			if filteredOut > 0 {
-				route["filter"] = map[string]interface{}{
-					"filtered_out": filteredOut,
-				}
+				route.Filter = &apitypes.AirlineGroupFilter{FilteredOut: filteredOut}
 				if len(routeOffers) == 0 {
-					route["warning"] = "All offers were filtered out by airline group filters."
+					route.Warning = "All offers were filtered out by airline group filters."
 				}
 			}
 
 			routeParams := searchRequest
 			routeParams.Origin = rk.origin
 			routeParams.Destination = rk.destination
-			route["search_params"] = routeParams
+			route.SearchParams = &routeParams
 
 			low := 0.0
 			high := 0.0
 			haveRange := false
 			for _, offer := range routeOffers {
-				price, ok := offer["price"].(float64)
-				if !ok || price <= 0 {
+				price := offer.Price
+				if price <= 0 {
 					continue
 				}
 				if !haveRange {
@@ -4032,7 +3719,7 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 				}
 			}
 			if haveRange {
-				route["price_range"] = map[string]interface{}{"low": low, "high": high}
+				route.PriceRange = &apitypes.PriceRange{Low: low, High: high}
 			}
 
 			if routeCheapestSet && routeCheapestOffer != nil && (!overallCheapestSet || routeCheapestPrice < overallCheapestPrice) {
@@ -4047,57 +3734,37 @@ func DirectFlightSearch(pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase) gin.Handle
 		}
 
 		if len(offers) > 0 && len(routes) == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf(
-					"search returned %d offers, but none could be grouped into routes; try narrowing the search or switching to /api/v1/bulk-search",
-					len(offers),
-				),
-				"warnings":      warnings,
-				"batch_summary": diag,
-				"batch_debug":   debugEntries,
+			c.JSON(http.StatusBadRequest, apitypes.DirectSearchErrorResponse{
+				ErrorResponse: apitypes.ErrorResponse{
+					Error: fmt.Sprintf(
+						"search returned %d offers, but none could be grouped into routes; try narrowing the search or switching to /api/v1/bulk-search",
+						len(offers),
+					),
+					Warnings: warnings,
+				},
+				BatchSummary: &diag,
+				BatchDebug:   debugEntries,
 			})
 			return
 		}
 
-		response := map[string]interface{}{
-			"routes":        routes,
-			"batch_summary": diag,
-			"search_params": map[string]interface{}{
-				"origins":               expandedOrigins,
-				"destinations":          expandedDestinations,
-				"departure_date":        searchRequest.DepartureDate,
-				"return_date":           searchRequest.ReturnDate,
-				"trip_type":             searchRequest.TripType,
-				"class":                 searchRequest.Class,
-				"stops":                 searchRequest.Stops,
-				"adults":                searchRequest.Adults,
-				"children":              searchRequest.Children,
-				"infants_lap":           searchRequest.InfantsLap,
-				"infants_seat":          searchRequest.InfantsSeat,
-				"currency":              searchRequest.Currency,
-				"offers_count":          len(offers),
-				"requested_route_count": totalRoutes,
-				"returned_route_count":  len(routes),
-			},
-		}
-		if len(searchRequest.IncludeAirlineGroups) > 0 || len(searchRequest.ExcludeAirlineGroups) > 0 {
-			response["filter"] = map[string]interface{}{
-				"include_airline_groups": searchRequest.IncludeAirlineGroups,
-				"exclude_airline_groups": searchRequest.ExcludeAirlineGroups,
-			}
+		response := apitypes.DirectSearchResponse{
+			Routes:         routes,
+			BatchSummary:   &diag,
+			SearchParams:   gridSearchParams(len(routes), len(offers), false),
+			Filter:         groupFilter,
+			Warnings:       warnings,
+			NearbyAirports: convertNearbyAirports(nearbyAirports),
 		}
 		if searchRequest.DebugBatches {
-			response["batch_debug"] = debugEntries
-		}
-		if len(warnings) > 0 {
-			response["warnings"] = warnings
+			response.BatchDebug = debugEntries
 		}
 
 		if overallCheapestSet && overallCheapestOffer != nil {
-			response["cheapest"] = map[string]interface{}{
-				"origin":      overallCheapestOrigin,
-				"destination": overallCheapestDestination,
-				"offer":       overallCheapestOffer,
+			response.Cheapest = &apitypes.DirectSearchCheapest{
+				Origin:      overallCheapestOrigin,
+				Destination: overallCheapestDestination,
+				Offer:       *overallCheapestOffer,
 			}
 		}
 
@@ -4111,7 +3778,7 @@ func CachedAirportsHandler(cacheManager *cache.CacheManager) gin.HandlerFunc {
 		cacheKey := cache.AirportsKey()
 
 		// Try to get from cache first
-		var airports []map[string]string
+		var airports []apitypes.SampleAirport
 		err := cacheManager.GetJSON(c.Request.Context(), cacheKey, &airports)
 		if err == nil {
 			logger.WithField("cache_key", cacheKey).Debug("Airport data served from cache")
@@ -4125,15 +3792,15 @@ func CachedAirportsHandler(cacheManager *cache.CacheManager) gin.HandlerFunc {
 		}
 
 		// Cache miss - generate data and cache it
-		airports = []map[string]string{
-			{"code": "JFK", "name": "John F. Kennedy International Airport", "city": "New York"},
-			{"code": "LAX", "name": "Los Angeles International Airport", "city": "Los Angeles"},
-			{"code": "ORD", "name": "O'Hare International Airport", "city": "Chicago"},
-			{"code": "LHR", "name": "Heathrow Airport", "city": "London"},
-			{"code": "CDG", "name": "Charles de Gaulle Airport", "city": "Paris"},
-			{"code": "DXB", "name": "Dubai International Airport", "city": "Dubai"},
-			{"code": "NRT", "name": "Narita International Airport", "city": "Tokyo"},
-			{"code": "SIN", "name": "Singapore Changi Airport", "city": "Singapore"},
+		airports = []apitypes.SampleAirport{
+			{Code: "JFK", Name: "John F. Kennedy International Airport", City: "New York"},
+			{Code: "LAX", Name: "Los Angeles International Airport", City: "Los Angeles"},
+			{Code: "ORD", Name: "O'Hare International Airport", City: "Chicago"},
+			{Code: "LHR", Name: "Heathrow Airport", City: "London"},
+			{Code: "CDG", Name: "Charles de Gaulle Airport", City: "Paris"},
+			{Code: "DXB", Name: "Dubai International Airport", City: "Dubai"},
+			{Code: "NRT", Name: "Narita International Airport", City: "Tokyo"},
+			{Code: "SIN", Name: "Singapore Changi Airport", City: "Singapore"},
 		}
 
 		// Cache the data for 24 hours
@@ -4151,12 +3818,12 @@ func CachedAirportsHandler(cacheManager *cache.CacheManager) gin.HandlerFunc {
 // MockAirportsHandler provides mock airport data (deprecated - use CachedAirportsHandler)
 func MockAirportsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		airports := []map[string]string{
-			{"code": "JFK", "name": "John F. Kennedy International Airport", "city": "New York"},
-			{"code": "LAX", "name": "Los Angeles International Airport", "city": "Los Angeles"},
-			{"code": "ORD", "name": "O'Hare International Airport", "city": "Chicago"},
-			{"code": "LHR", "name": "Heathrow Airport", "city": "London"},
-			{"code": "CDG", "name": "Charles de Gaulle Airport", "city": "Paris"},
+		airports := []apitypes.SampleAirport{
+			{Code: "JFK", Name: "John F. Kennedy International Airport", City: "New York"},
+			{Code: "LAX", Name: "Los Angeles International Airport", City: "Los Angeles"},
+			{Code: "ORD", Name: "O'Hare International Airport", City: "Chicago"},
+			{Code: "LHR", Name: "Heathrow Airport", City: "London"},
+			{Code: "CDG", Name: "Charles de Gaulle Airport", City: "Paris"},
 		}
 		c.JSON(http.StatusOK, airports)
 	}
@@ -4168,14 +3835,14 @@ func MockPriceHistoryHandler() gin.HandlerFunc {
 		origin := c.Query("origin")
 		destination := c.Query("destination")
 
-		priceHistory := []map[string]interface{}{
-			{"date": "2023-06-01", "price": 299, "origin": origin, "destination": destination},
-			{"date": "2023-06-02", "price": 310, "origin": origin, "destination": destination},
-			{"date": "2023-06-03", "price": 305, "origin": origin, "destination": destination},
-			{"date": "2023-06-04", "price": 295, "origin": origin, "destination": destination},
-			{"date": "2023-06-05", "price": 320, "origin": origin, "destination": destination},
-			{"date": "2023-06-06", "price": 315, "origin": origin, "destination": destination},
-			{"date": "2023-06-07", "price": 300, "origin": origin, "destination": destination},
+		priceHistory := []apitypes.SamplePricePoint{
+			{Date: "2023-06-01", Price: 299, Origin: origin, Destination: destination},
+			{Date: "2023-06-02", Price: 310, Origin: origin, Destination: destination},
+			{Date: "2023-06-03", Price: 305, Origin: origin, Destination: destination},
+			{Date: "2023-06-04", Price: 295, Origin: origin, Destination: destination},
+			{Date: "2023-06-05", Price: 320, Origin: origin, Destination: destination},
+			{Date: "2023-06-06", Price: 315, Origin: origin, Destination: destination},
+			{Date: "2023-06-07", Price: 300, Origin: origin, Destination: destination},
 		}
 
 		c.JSON(http.StatusOK, priceHistory)
@@ -4185,26 +3852,26 @@ func MockPriceHistoryHandler() gin.HandlerFunc {
 // createBulkJob returns a handler for creating a new scheduled bulk search job
 func createBulkJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var req BulkJobRequest
+		var req apitypes.BulkJobRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
 		if req.CronExpression == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "CronExpression is required"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "CronExpression is required"})
 			return
 		}
 
 		// Parse date strings
 		dateStart, err := time.Parse("2006-01-02", req.DateStart)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_start format. Use YYYY-MM-DD: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid date_start format. Use YYYY-MM-DD: " + err.Error()})
 			return
 		}
 		dateEnd, err := time.Parse("2006-01-02", req.DateEnd)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date_end format. Use YYYY-MM-DD: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid date_end format. Use YYYY-MM-DD: " + err.Error()})
 			return
 		}
 
@@ -4212,7 +3879,7 @@ func createBulkJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.Handle
 		if req.ReturnDateStart != "" {
 			parsedDate, err := time.Parse("2006-01-02", req.ReturnDateStart)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return_date_start format. Use YYYY-MM-DD: " + err.Error()})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid return_date_start format. Use YYYY-MM-DD: " + err.Error()})
 				return
 			}
 			returnDateStart = sql.NullTime{Time: parsedDate, Valid: true}
@@ -4220,7 +3887,7 @@ func createBulkJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.Handle
 		if req.ReturnDateEnd != "" {
 			parsedDate, err := time.Parse("2006-01-02", req.ReturnDateEnd)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return_date_end format. Use YYYY-MM-DD: " + err.Error()})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid return_date_end format. Use YYYY-MM-DD: " + err.Error()})
 				return
 			}
 			returnDateEnd = sql.NullTime{Time: parsedDate, Valid: true}
@@ -4232,22 +3899,28 @@ func createBulkJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.Handle
 		if containsToken(req.Origins, macros.RegionWorldAll) || containsToken(req.Destinations, macros.RegionWorldAll) {
 			worldAll, err := listAllAirportCodes(ctx, pgDB)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 				return
 			}
 			overrides = map[string][]string{macros.RegionWorldAll: worldAll}
 			worldAllCount = len(worldAll)
 		}
+		nearOverrides, nearbyAirports, err := nearbyAirportOverrides(ctx, pgDB, req.Origins, req.Destinations)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+		overrides = mergeAirportOverrides(overrides, nearOverrides)
 
 		// Expand region tokens in origins and destinations
 		expandedOrigins, originWarnings, err := macros.ExpandAirportTokensWithOverrides(req.Origins, overrides)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid origin: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid origin: " + err.Error()})
 			return
 		}
 		expandedDestinations, destinationWarnings, err := macros.ExpandAirportTokensWithOverrides(req.Destinations, overrides)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid destination: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid destination: " + err.Error()})
 			return
 		}
 
@@ -4261,27 +3934,29 @@ func createBulkJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.Handle
 		// This is stricter than bulk search since each job persists to DB and runs repeatedly
 		totalJobs := len(expandedOrigins) * len(expandedDestinations)
 		if totalJobs == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":    "At least one origin and one destination are required (after REGION:* expansion)",
-				"warnings": warnings,
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{
+				Error:    "At least one origin and one destination are required (after REGION:* expansion)",
+				Warnings: warnings,
 			})
 			return
 		}
 		const maxScheduledJobs = 500
 		if totalJobs > maxScheduledJobs {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":        fmt.Sprintf("Too many scheduled jobs to create: %d (max %d). Region tokens can expand to many airports; consider narrowing your search or using bulk-search for one-off runs.", totalJobs, maxScheduledJobs),
-				"total_jobs":   totalJobs,
-				"max_jobs":     maxScheduledJobs,
-				"origins":      len(expandedOrigins),
-				"destinations": len(expandedDestinations),
-				"warnings":     warnings,
+			c.JSON(http.StatusBadRequest, apitypes.ExpansionLimitResponse{
+				ErrorResponse: apitypes.ErrorResponse{
+					Error:    fmt.Sprintf("Too many scheduled jobs to create: %d (max %d). Region tokens can expand to many airports; consider narrowing your search or using bulk-search for one-off runs.", totalJobs, maxScheduledJobs),
+					Warnings: warnings,
+				},
+				TotalJobs:    totalJobs,
+				MaxJobs:      maxScheduledJobs,
+				Origins:      len(expandedOrigins),
+				Destinations: len(expandedDestinations),
 			})
 			return
 		}
 
 		// Create multiple scheduled jobs - one for each origin/destination combination
-		createdJobs := []map[string]interface{}{}
+		createdJobs := []apitypes.BulkJobCreated{}
 
 		for _, origin := range expandedOrigins {
 			for _, destination := range expandedDestinations {
@@ -4291,7 +3966,7 @@ func createBulkJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.Handle
 				// Begin transaction for each job
 				tx, err := pgDB.BeginTx(ctx)
 				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to begin transaction"})
+					c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to begin transaction"})
 					return
 				}
 
@@ -4299,7 +3974,7 @@ func createBulkJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.Handle
 				jobID, err := pgDB.CreateScheduledJob(ctx, tx, jobName, req.CronExpression, true)
 				if err != nil {
 					tx.Rollback()
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduled job"})
+					c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to create scheduled job"})
 					return
 				}
 
@@ -4343,22 +4018,22 @@ func createBulkJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.Handle
 
 				if err := pgDB.CreateJobDetails(ctx, tx, details); err != nil {
 					tx.Rollback()
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job details"})
+					c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to create job details"})
 					return
 				}
 
 				// Commit the transaction
 				if err := tx.Commit(); err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction: " + err.Error()})
+					c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to commit transaction: " + err.Error()})
 					return
 				}
 
-				createdJobs = append(createdJobs, map[string]interface{}{
-					"id":              jobID,
-					"name":            jobName,
-					"origin":          origin,
-					"destination":     destination,
-					"cron_expression": req.CronExpression,
+				createdJobs = append(createdJobs, apitypes.BulkJobCreated{
+					ID:             jobID,
+					Name:           jobName,
+					Origin:         origin,
+					Destination:    destination,
+					CronExpression: req.CronExpression,
 				})
 
 				log.Printf("Created scheduled bulk search job: %s (ID: %d)", jobName, jobID)
@@ -4374,10 +4049,11 @@ func createBulkJob(pgDB db.PostgresDB, workerManager *worker.Manager) gin.Handle
 			}
 		}
 
-		c.JSON(http.StatusCreated, gin.H{
-			"message":  fmt.Sprintf("Created %d scheduled bulk search jobs", len(createdJobs)),
-			"jobs":     createdJobs,
-			"warnings": warnings,
+		c.JSON(http.StatusCreated, apitypes.BulkJobsCreatedResponse{
+			Message:        fmt.Sprintf("Created %d scheduled bulk search jobs", len(createdJobs)),
+			Jobs:           createdJobs,
+			Warnings:       warnings,
+			NearbyAirports: convertNearbyAirports(nearbyAirports),
 		})
 	}
 }
@@ -4389,15 +4065,6 @@ type WorkerStatusProvider interface {
 
 // --- Continuous Sweep Handlers ---
 
-// SweepConfigRequest represents a request to update sweep configuration
-type SweepConfigRequest struct {
-	PacingMode          string `json:"pacing_mode,omitempty"`
-	TargetDurationHours int    `json:"target_duration_hours,omitempty"`
-	MinDelayMs          int    `json:"min_delay_ms,omitempty"`
-	Class               string `json:"class,omitempty"`
-	TripLengths         *[]int `json:"trip_lengths,omitempty"`
-}
-
 func normalizeContinuousSweepTripLengths(input []int) ([]int, error) {
 	if len(input) == 0 {
 		return nil, fmt.Errorf("trip_lengths cannot be empty")
@@ -4436,6 +4103,18 @@ func equalIntSlice(a, b []int) bool {
 	return true
 }
 
+func equalStringSlice(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func updateContinuousSweepDBFlags(ctx context.Context, pgDB db.PostgresDB, isRunning, isPaused *bool) (*db.ContinuousSweepProgress, error) {
 	if pgDB == nil {
 		return nil, fmt.Errorf("postgres is not configured")
@@ -4460,13 +4139,13 @@ type continuousSweepControlStore interface {
 // getContinuousSweepStatus returns the current status of the continuous sweep
 func getContinuousSweepStatus(workerManager *worker.Manager, pgDB db.PostgresDB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		out := gin.H{}
+		var out apitypes.ContinuousSweepStatusResponse
 		if pgDB != nil {
 			dbProgress, err := pgDB.GetContinuousSweepProgress(c.Request.Context())
 			if err != nil {
-				out["db_error"] = err.Error()
-			} else if dbProgress != nil {
-				out["db"] = dbProgress
+				out.DBError = err.Error()
+			} else {
+				out.DB = convertSweepProgress(dbProgress)
 			}
 		}
 
@@ -4477,23 +4156,22 @@ func getContinuousSweepStatus(workerManager *worker.Manager, pgDB db.PostgresDB)
 				if store, ok := q.(continuousSweepControlStore); ok {
 					ctrl, err := store.GetContinuousSweepControlFlags(c.Request.Context())
 					if err != nil {
-						out["control_error"] = err.Error()
-					} else if ctrl != nil {
-						out["control"] = ctrl
+						out.ControlError = err.Error()
+					} else {
+						out.Control = convertSweepControl(ctrl)
 					}
 				}
 			}
 		}
 
 		if pgDB == nil {
-			out["db_error"] = "postgres is not configured"
+			out.DBError = "postgres is not configured"
 		}
 
-		out["server_time"] = time.Now().Format(time.RFC3339)
+		out.ServerTime = time.Now().Format(time.RFC3339)
 
 		if workerManager == nil {
-			out["initialized"] = false
-			out["message"] = "Worker manager not available"
+			out.Message = "Worker manager not available"
 			c.JSON(http.StatusOK, out)
 			return
 		}
@@ -4501,34 +4179,31 @@ func getContinuousSweepStatus(workerManager *worker.Manager, pgDB db.PostgresDB)
 		// Include queue stats so the UI can show whether work is still in-flight/backlogged even if the runner
 		// isn't initialized in this process.
 		if q := workerManager.GetQueue(); q != nil {
-			queues := gin.H{}
+			out.Queues = make(map[string]apitypes.ContinuousSweepQueueStats)
 			for _, name := range []string{"continuous_price_graph"} {
 				stats, err := q.GetQueueStats(c.Request.Context(), name)
 				if err != nil {
-					queues[name] = gin.H{"error": err.Error()}
+					out.Queues[name] = apitypes.ContinuousSweepQueueStats{Error: err.Error()}
 					continue
 				}
-				queues[name] = gin.H{
-					"pending":    stats["pending"],
-					"processing": stats["processing"],
-					"completed":  stats["completed"],
-					"failed":     stats["failed"],
+				out.Queues[name] = apitypes.ContinuousSweepQueueStats{
+					Pending:    stats["pending"],
+					Processing: stats["processing"],
+					Completed:  stats["completed"],
+					Failed:     stats["failed"],
 				}
 			}
-			out["queues"] = queues
 		}
 
 		runner := workerManager.GetSweepRunner()
 		if runner == nil {
-			out["initialized"] = false
-			out["message"] = "Continuous sweep runner not initialized in this process."
+			out.Message = "Continuous sweep runner not initialized in this process."
 			c.JSON(http.StatusOK, out)
 			return
 		}
 
-		status := runner.GetStatus()
-		out["initialized"] = true
-		out["status"] = status
+		out.Initialized = true
+		out.Status = runnerStatus(runner)
 		c.JSON(http.StatusOK, out)
 	}
 }
@@ -4563,7 +4238,7 @@ func startContinuousSweep(workerManager *worker.Manager, pgDB db.PostgresDB, cfg
 
 		// Start the sweep
 		if err := runner.Start(); err != nil {
-			c.JSON(http.StatusConflict, gin.H{"error": "Sweep already running"})
+			c.JSON(http.StatusConflict, apitypes.ErrorResponse{Error: "Sweep already running"})
 			return
 		}
 
@@ -4578,9 +4253,9 @@ func startContinuousSweep(workerManager *worker.Manager, pgDB db.PostgresDB, cfg
 			}
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Continuous sweep started",
-			"status":  runner.GetStatus(),
+		c.JSON(http.StatusOK, apitypes.ContinuousSweepActionResponse{
+			Message: "Continuous sweep started",
+			Status:  runnerStatus(runner),
 		})
 	}
 }
@@ -4614,44 +4289,31 @@ func stopContinuousSweep(workerManager *worker.Manager, pgDB db.PostgresDB) gin.
 			}
 		}
 
-		drainResult := gin.H{}
+		drainResult := &apitypes.ContinuousSweepDrainResult{}
 		if workerManager != nil {
 			if q := workerManager.GetQueue(); q != nil {
 				// When the sweep is stopped, we don't want the continuous queue to keep running in the background.
 				// Best-effort: cancel in-flight jobs and clear pending.
 				canceled, cancelErr := q.CancelProcessing(c.Request.Context(), "continuous_price_graph")
 				if cancelErr != nil {
-					drainResult["error"] = cancelErr.Error()
+					drainResult.Error = cancelErr.Error()
 				} else {
-					drainResult["canceled_processing"] = canceled
+					drainResult.CanceledProcessing = &canceled
 				}
 				cleared, clearErr := q.ClearQueue(c.Request.Context(), "continuous_price_graph")
 				if clearErr != nil {
-					drainResult["error"] = clearErr.Error()
+					drainResult.Error = clearErr.Error()
 				} else {
-					drainResult["cleared_pending"] = cleared
+					drainResult.ClearedPending = &cleared
 				}
 			}
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Continuous sweep stopped",
-			"persist": func() any {
-				if len(persistErrors) == 0 {
-					return nil
-				}
-				return gin.H{"errors": persistErrors}
-			}(),
-			"drain": drainResult,
-			"status": func() any {
-				if workerManager == nil {
-					return nil
-				}
-				if runner := workerManager.GetSweepRunner(); runner != nil {
-					return runner.GetStatus()
-				}
-				return nil
-			}(),
+		c.JSON(http.StatusOK, apitypes.ContinuousSweepActionResponse{
+			Message: "Continuous sweep stopped",
+			Persist: persistResult(persistErrors),
+			Drain:   drainResult,
+			Status:  managerSweepStatus(workerManager),
 		})
 	}
 }
@@ -4682,23 +4344,10 @@ func pauseContinuousSweep(workerManager *worker.Manager, pgDB db.PostgresDB) gin
 			}
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Continuous sweep paused",
-			"persist": func() any {
-				if len(persistErrors) == 0 {
-					return nil
-				}
-				return gin.H{"errors": persistErrors}
-			}(),
-			"status": func() any {
-				if workerManager == nil {
-					return nil
-				}
-				if runner := workerManager.GetSweepRunner(); runner != nil {
-					return runner.GetStatus()
-				}
-				return nil
-			}(),
+		c.JSON(http.StatusOK, apitypes.ContinuousSweepActionResponse{
+			Message: "Continuous sweep paused",
+			Persist: persistResult(persistErrors),
+			Status:  managerSweepStatus(workerManager),
 		})
 	}
 }
@@ -4729,39 +4378,26 @@ func resumeContinuousSweep(workerManager *worker.Manager, pgDB db.PostgresDB) gi
 			}
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Continuous sweep resumed",
-			"persist": func() any {
-				if len(persistErrors) == 0 {
-					return nil
-				}
-				return gin.H{"errors": persistErrors}
-			}(),
-			"status": func() any {
-				if workerManager == nil {
-					return nil
-				}
-				if runner := workerManager.GetSweepRunner(); runner != nil {
-					return runner.GetStatus()
-				}
-				return nil
-			}(),
+		c.JSON(http.StatusOK, apitypes.ContinuousSweepActionResponse{
+			Message: "Continuous sweep resumed",
+			Persist: persistResult(persistErrors),
+			Status:  managerSweepStatus(workerManager),
 		})
 	}
 }
 
 // updateContinuousSweepConfig updates the sweep configuration
-func updateContinuousSweepConfig(workerManager *worker.Manager) gin.HandlerFunc {
+func updateContinuousSweepConfig(workerManager *worker.Manager, pgDB db.PostgresDB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		runner := workerManager.GetSweepRunner()
 		if runner == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Continuous sweep runner not initialized"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Continuous sweep runner not initialized"})
 			return
 		}
 
-		var req SweepConfigRequest
+		var req apitypes.SweepConfigRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid request: " + err.Error()})
 			return
 		}
 
@@ -4773,13 +4409,36 @@ func updateContinuousSweepConfig(workerManager *worker.Manager) gin.HandlerFunc
 		if req.TripLengths != nil {
 			tripLengths, err := normalizeContinuousSweepTripLengths(*req.TripLengths)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
 				return
 			}
 			tripLengthsChanged = !equalIntSlice(tripLengths, prevConfig.TripLengths)
 			newConfig.TripLengths = tripLengths
 		}
 
+		originsChanged := false
+		var nearbyAirports []macros.NearbyAirport
+		if req.Origins != nil {
+			if containsToken(*req.Origins, macros.RegionWorldAll) {
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: macros.RegionWorldAll + " is not supported for the continuous sweep"})
+				return
+			}
+			var nearOverrides map[string][]string
+			var err error
+			nearOverrides, nearbyAirports, err = nearbyAirportOverrides(c.Request.Context(), pgDB, *req.Origins)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+				return
+			}
+			origins, _, err := macros.ExpandAirportTokensWithOverrides(*req.Origins, nearOverrides)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid origin: " + err.Error()})
+				return
+			}
+			originsChanged = !equalStringSlice(origins, prevConfig.Origins)
+			newConfig.Origins = origins
+		}
+
 		// Apply updates
 		if req.PacingMode != "" {
 			if req.PacingMode == "adaptive" {
@@ -4787,7 +4446,7 @@ func updateContinuousSweepConfig(workerManager *worker.Manager) gin.HandlerFunc
 			} else if req.PacingMode == "fixed" {
 				newConfig.PacingMode = worker.PacingModeFixed
 			} else {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pacing_mode. Use 'adaptive' or 'fixed'"})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid pacing_mode. Use 'adaptive' or 'fixed'"})
 				return
 			}
 		}
@@ -4805,22 +4464,23 @@ func updateContinuousSweepConfig(workerManager *worker.Manager) gin.HandlerFunc
 			case "economy", "premium_economy", "business", "first":
 				newConfig.Class = req.Class
 			default:
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class. Use 'economy', 'premium_economy', 'business', or 'first'"})
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid class. Use 'economy', 'premium_economy', 'business', or 'first'"})
 				return
 			}
 		}
 
 		runner.SetConfig(newConfig)
-		if tripLengthsChanged {
+		if tripLengthsChanged || originsChanged {
 			status := runner.GetStatus()
 			if status.IsRunning {
 				runner.RestartSweep()
 			}
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Sweep configuration updated",
-			"status":  runner.GetStatus(),
+		c.JSON(http.StatusOK, apitypes.ContinuousSweepActionResponse{
+			Message:        "Sweep configuration updated",
+			Status:         runnerStatus(runner),
+			NearbyAirports: convertNearbyAirports(nearbyAirports),
 		})
 	}
 }
@@ -4830,14 +4490,14 @@ func skipCurrentRoute(workerManager *worker.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		runner := workerManager.GetSweepRunner()
 		if runner == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Continuous sweep runner not initialized"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Continuous sweep runner not initialized"})
 			return
 		}
 
 		runner.SkipRoute()
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Route skipped",
-			"status":  runner.GetStatus(),
+		c.JSON(http.StatusOK, apitypes.ContinuousSweepActionResponse{
+			Message: "Route skipped",
+			Status:  runnerStatus(runner),
 		})
 	}
 }
@@ -4847,39 +4507,23 @@ func restartCurrentSweep(workerManager *worker.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		runner := workerManager.GetSweepRunner()
 		if runner == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Continuous sweep runner not initialized"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Continuous sweep runner not initialized"})
 			return
 		}
 
 		runner.RestartSweep()
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Sweep restarted from beginning",
-			"status":  runner.GetStatus(),
+		c.JSON(http.StatusOK, apitypes.ContinuousSweepActionResponse{
+			Message: "Sweep restarted from beginning",
+			Status:  runnerStatus(runner),
 		})
 	}
 }
 
-// ContinuousSweepStatsResponse is the JSON-friendly response for sweep stats
-type ContinuousSweepStatsResponse struct {
-	ID                   int        `json:"id"`
-	SweepNumber          int        `json:"sweep_number"`
-	StartedAt            time.Time  `json:"started_at"`
-	CompletedAt          *time.Time `json:"completed_at,omitempty"`
-	TotalRoutes          int        `json:"total_routes"`
-	SuccessfulQueries    int        `json:"successful_queries"`
-	FailedQueries        int        `json:"failed_queries"`
-	TotalDurationSeconds *int       `json:"total_duration_seconds,omitempty"`
-	AvgDelayMs           *int       `json:"avg_delay_ms,omitempty"`
-	MinPriceFound        *float64   `json:"min_price_found,omitempty"`
-	MaxPriceFound        *float64   `json:"max_price_found,omitempty"`
-	CreatedAt            time.Time  `json:"created_at"`
-}
-
 // convertSweepStats converts db.ContinuousSweepStats to JSON-friendly response
-func convertSweepStats(stats []db.ContinuousSweepStats) []ContinuousSweepStatsResponse {
-	result := make([]ContinuousSweepStatsResponse, len(stats))
+func convertSweepStats(stats []db.ContinuousSweepStats) []apitypes.ContinuousSweepStatsResponse {
+	result := make([]apitypes.ContinuousSweepStatsResponse, len(stats))
 	for i, s := range stats {
-		result[i] = ContinuousSweepStatsResponse{
+		result[i] = apitypes.ContinuousSweepStatsResponse{
 			ID:                s.ID,
 			SweepNumber:       s.SweepNumber,
 			StartedAt:         s.StartedAt,
@@ -4919,42 +4563,22 @@ func getContinuousSweepStats(pgDB db.PostgresDB) gin.HandlerFunc {
 
 		stats, err := pgDB.ListContinuousSweepStats(c.Request.Context(), limit)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sweep stats"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to fetch sweep stats"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"stats": convertSweepStats(stats),
-			"count": len(stats),
+		c.JSON(http.StatusOK, apitypes.ContinuousSweepStatsListResponse{
+			Stats: convertSweepStats(stats),
+			Count: len(stats),
 		})
 	}
 }
 
-// ContinuousSweepResultResponse is the JSON-friendly response for sweep results
-type ContinuousSweepResultResponse struct {
-	Origin        string     `json:"origin"`
-	Destination   string     `json:"destination"`
-	DepartureDate time.Time  `json:"departure_date"`
-	ReturnDate    *time.Time `json:"return_date,omitempty"`
-	TripLength    *int       `json:"trip_length,omitempty"`
-	Price         float64    `json:"price"`
-	Currency      string     `json:"currency"`
-	Adults        int        `json:"adults"`
-	Children      int        `json:"children"`
-	InfantsLap    int        `json:"infants_lap"`
-	InfantsSeat   int        `json:"infants_seat"`
-	TripType      string     `json:"trip_type"`
-	Class         string     `json:"class"`
-	Stops         string     `json:"stops"`
-	SearchURL     *string    `json:"search_url,omitempty"`
-	QueriedAt     time.Time  `json:"queried_at"`
-}
-
 // convertSweepResults converts db.PriceGraphResultRecord to JSON-friendly response
-func convertSweepResults(results []db.PriceGraphResultRecord) []ContinuousSweepResultResponse {
-	resp := make([]ContinuousSweepResultResponse, len(results))
+func convertSweepResults(results []db.PriceGraphResultRecord) []apitypes.ContinuousSweepResultResponse {
+	resp := make([]apitypes.ContinuousSweepResultResponse, len(results))
 	for i, r := range results {
-		resp[i] = ContinuousSweepResultResponse{
+		resp[i] = apitypes.ContinuousSweepResultResponse{
 			Origin:        r.Origin,
 			Destination:   r.Destination,
 			DepartureDate: r.DepartureDate,
@@ -5010,18 +4634,18 @@ func getContinuousSweepResults(pgDB db.PostgresDB) gin.HandlerFunc {
 
 		results, err := pgDB.ListContinuousSweepResults(c.Request.Context(), filters)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sweep results"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to fetch sweep results"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"results": convertSweepResults(results),
-			"count":   len(results),
-			"filters": gin.H{
-				"origin":      filters.Origin,
-				"destination": filters.Destination,
-				"from":        filters.FromDate,
-				"to":          filters.ToDate,
+		c.JSON(http.StatusOK, apitypes.ContinuousSweepResultsResponse{
+			Results: convertSweepResults(results),
+			Count:   len(results),
+			Filters: apitypes.ContinuousSweepResultFilters{
+				Origin:      filters.Origin,
+				Destination: filters.Destination,
+				From:        filters.FromDate,
+				To:          filters.ToDate,
 			},
 		})
 	}
@@ -5065,58 +4689,52 @@ func listDeals(pgDB db.PostgresDB) gin.HandlerFunc {
 		deals, err := pgDB.ListActiveDeals(c.Request.Context(), filter)
 		if err != nil {
 			log.Printf("Error fetching deals: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deals"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to fetch deals"})
 			return
 		}
 
 		// Convert to JSON-friendly format
-		response := make([]map[string]interface{}, len(deals))
+		response := make([]apitypes.DealResponse, len(deals))
 		for i, deal := range deals {
-			response[i] = map[string]interface{}{
-				"id":                  deal.ID,
-				"origin":              deal.Origin,
-				"destination":         deal.Destination,
-				"departure_date":      deal.DepartureDate.Format("2006-01-02"),
-				"price":               deal.Price,
-				"currency":            deal.Currency,
-				"discount_percent":    maybeNullFloat(deal.DiscountPercent),
-				"deal_score":          maybeNullInt(deal.DealScore),
-				"deal_classification": maybeNullString(deal.DealClassification),
-				"cost_per_mile":       maybeNullFloat(deal.CostPerMile),
-				"cabin_class":         deal.CabinClass,
-				"status":              deal.Status,
-				"first_seen_at":       deal.FirstSeenAt,
-				"times_seen":          deal.TimesSeen,
+			response[i] = apitypes.DealResponse{
+				ID:                 deal.ID,
+				Origin:             deal.Origin,
+				Destination:        deal.Destination,
+				DepartureDate:      deal.DepartureDate.Format("2006-01-02"),
+				Price:              deal.Price,
+				Currency:           deal.Currency,
+				DiscountPercent:    maybeNullFloat(deal.DiscountPercent),
+				DealScore:          maybeNullInt(deal.DealScore),
+				DealClassification: maybeNullString(deal.DealClassification),
+				CostPerMile:        maybeNullFloat(deal.CostPerMile),
+				CabinClass:         deal.CabinClass,
+				Status:             deal.Status,
+				FirstSeenAt:        deal.FirstSeenAt,
+				TimesSeen:          deal.TimesSeen,
+				SearchURL:          deal.SearchURL.String,
+				NormalizedPrice:    maybeNullFloat(deal.NormalizedPrice),
 			}
 			if deal.ReturnDate.Valid {
-				response[i]["return_date"] = deal.ReturnDate.Time.Format("2006-01-02")
+				response[i].ReturnDate = deal.ReturnDate.Time.Format("2006-01-02")
 			}
-			if deal.SearchURL.Valid {
-				response[i]["search_url"] = deal.SearchURL.String
+			if deal.NormalizedPrice.Valid {
+				response[i].NormalizedCurrency = deal.NormalizedCurrency.String
 			}
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"deals": response,
-			"count": len(deals),
-			"filter": gin.H{
-				"origin":         filter.Origin,
-				"destination":    filter.Destination,
-				"classification": filter.Classification,
-				"status":         filter.Status,
+		c.JSON(http.StatusOK, apitypes.DealListResponse{
+			Deals: response,
+			Count: len(deals),
+			Filter: apitypes.DealFilterResponse{
+				Origin:         filter.Origin,
+				Destination:    filter.Destination,
+				Classification: filter.Classification,
+				Status:         filter.Status,
 			},
 		})
 	}
 }
 
-// maybeNullFloat returns the float value or nil if not valid
-func maybeNullFloat(value sql.NullFloat64) interface{} {
-	if value.Valid {
-		return value.Float64
-	}
-	return nil
-}
-
 // listDealAlerts returns a handler for listing published deal alerts
 func listDealAlerts(pgDB db.PostgresDB) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -5130,35 +4748,33 @@ func listDealAlerts(pgDB db.PostgresDB) gin.HandlerFunc {
 		alerts, err := pgDB.ListDealAlerts(c.Request.Context(), limit, offset)
 		if err != nil {
 			log.Printf("Error fetching deal alerts: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deal alerts"})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to fetch deal alerts"})
 			return
 		}
 
 		// Convert to JSON-friendly format
-		response := make([]map[string]interface{}, len(alerts))
+		response := make([]apitypes.DealAlertResponse, len(alerts))
 		for i, alert := range alerts {
-			response[i] = map[string]interface{}{
-				"id":                  alert.ID,
-				"detected_deal_id":    alert.DetectedDealID,
-				"origin":              alert.Origin,
-				"destination":         alert.Destination,
-				"price":               alert.Price,
-				"currency":            alert.Currency,
-				"discount_percent":    maybeNullFloat(alert.DiscountPercent),
-				"deal_classification": maybeNullString(alert.DealClassification),
-				"deal_score":          maybeNullInt(alert.DealScore),
-				"published_at":        alert.PublishedAt,
-				"publish_method":      alert.PublishMethod,
-				"notification_sent":   alert.NotificationSent,
-			}
-			if alert.NotificationSentAt.Valid {
-				response[i]["notification_sent_at"] = alert.NotificationSentAt.Time
-			}
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"alerts": response,
-			"count":  len(alerts),
+			response[i] = apitypes.DealAlertResponse{
+				ID:                 alert.ID,
+				DetectedDealID:     alert.DetectedDealID,
+				Origin:             alert.Origin,
+				Destination:        alert.Destination,
+				Price:              alert.Price,
+				Currency:           alert.Currency,
+				DiscountPercent:    maybeNullFloat(alert.DiscountPercent),
+				DealClassification: maybeNullString(alert.DealClassification),
+				DealScore:          maybeNullInt(alert.DealScore),
+				PublishedAt:        alert.PublishedAt,
+				PublishMethod:      alert.PublishMethod,
+				NotificationSent:   alert.NotificationSent,
+				NotificationSentAt: maybeNullTime(alert.NotificationSentAt),
+			}
+		}
+
+		c.JSON(http.StatusOK, apitypes.DealAlertListResponse{
+			Alerts: response,
+			Count:  len(alerts),
 		})
 	}
 }
@@ -0,0 +1,324 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/gilby125/google-flights-api/worker"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// Multi-city searches have between 2 and 6 segments, like on Google Flights.
+const (
+	minMultiCitySegments = 2
+	maxMultiCitySegments = 6
+)
+
+// directMultiCityOffersLimit caps how many offers of a direct multi-city search get all their
+// segments requested. Every following segment costs one more request to Google per offer.
+const directMultiCityOffersLimit = 3
+
+var segmentAirportRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// validateSearchSegments checks the segments of a multi-city search: 2-6 segments between
+// distinct airports, departing in order and not before today. Airport codes are upper-cased.
+func validateSearchSegments(segments []apitypes.SearchSegmentRequest, now time.Time) ([]worker.SearchSegment, error) {
+	if len(segments) < minMultiCitySegments || len(segments) > maxMultiCitySegments {
+		return nil, fmt.Errorf("multi_city searches require between %d and %d segments", minMultiCitySegments, maxMultiCitySegments)
+	}
+
+	today := truncateDate(now)
+	result := make([]worker.SearchSegment, 0, len(segments))
+	for i, segment := range segments {
+		origin := strings.ToUpper(strings.TrimSpace(segment.Origin))
+		destination := strings.ToUpper(strings.TrimSpace(segment.Destination))
+		if !segmentAirportRegex.MatchString(origin) {
+			return nil, fmt.Errorf("segment %d: invalid origin airport code format", i+1)
+		}
+		if !segmentAirportRegex.MatchString(destination) {
+			return nil, fmt.Errorf("segment %d: invalid destination airport code format", i+1)
+		}
+		if origin == destination {
+			return nil, fmt.Errorf("segment %d: origin and destination must differ", i+1)
+		}
+		if segment.DepartureDate.IsZero() {
+			return nil, fmt.Errorf("segment %d: departure_date is required", i+1)
+		}
+		date := truncateDate(segment.DepartureDate.Time)
+		if date.Before(today) {
+			return nil, fmt.Errorf("segment %d: departure date must not be in the past", i+1)
+		}
+		if i > 0 && date.Before(result[i-1].DepartureDate) {
+			return nil, fmt.Errorf("segment %d: departure date must not be before segment %d", i+1, i)
+		}
+		result = append(result, worker.SearchSegment{Origin: origin, Destination: destination, DepartureDate: date})
+	}
+	return result, nil
+}
+
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// directMultiCitySearch answers a direct search with trip_type multi_city. The offers of the
+// first segment are completed with the cheapest option of every following segment.
+func directMultiCitySearch(c *gin.Context, pgDB db.PostgresDB, neo4jDB db.Neo4jDatabase, sessions *flights.SessionPool, searchRequest apitypes.DirectSearchRequest) {
+	segments, err := validateSearchSegments(searchRequest.Segments, time.Now().UTC())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if searchRequest.MinLayoverMinutes < 0 || searchRequest.MaxLayoverMinutes < 0 ||
+		(searchRequest.MaxLayoverMinutes > 0 && searchRequest.MinLayoverMinutes > searchRequest.MaxLayoverMinutes) {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "invalid layover limits: min_layover_minutes and max_layover_minutes must be non-negative and min must not exceed max"})
+		return
+	}
+	if searchRequest.CarryOnBags < 0 || searchRequest.CheckedBags < 0 {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "invalid bags: carry_on_bags and checked_bags must be non-negative"})
+		return
+	}
+
+	cur, err := currency.ParseISO(searchRequest.Currency)
+	if err != nil {
+		log.Printf("Invalid currency %s, using USD", searchRequest.Currency)
+		cur = currency.USD
+	}
+
+	first, last := segments[0], segments[len(segments)-1]
+	args := flights.Args{
+		Date:        first.DepartureDate,
+		SrcAirports: []string{first.Origin},
+		DstAirports: []string{last.Destination},
+		Options: flights.Options{
+			Travelers: flights.Travelers{
+				Adults:       searchRequest.Adults,
+				Children:     searchRequest.Children,
+				InfantOnLap:  searchRequest.InfantsLap,
+				InfantInSeat: searchRequest.InfantsSeat,
+			},
+			Currency:       cur,
+			Stops:          ParseStops(searchRequest.Stops),
+			Class:          ParseClass(searchRequest.Class),
+			TripType:       flights.MultiCity,
+			Lang:           language.English,
+			Carriers:       searchRequest.Carriers,
+			Country:        searchRequest.Country,
+			GoogleHost:     searchRequest.GoogleHost,
+			TimezoneOffset: searchRequest.TZOffsetMin,
+		},
+		MinLayover:          time.Duration(searchRequest.MinLayoverMinutes) * time.Minute,
+		MaxLayover:          time.Duration(searchRequest.MaxLayoverMinutes) * time.Minute,
+		ExcludeBasicEconomy: searchRequest.ExcludeBasicEconomy,
+		RequiredBags:        flights.Bags{CarryOn: searchRequest.CarryOnBags, Checked: searchRequest.CheckedBags},
+	}
+	for _, segment := range segments {
+		args.Segments = append(args.Segments, flights.Segment{
+			Date:        segment.DepartureDate,
+			SrcAirports: []string{segment.Origin},
+			DstAirports: []string{segment.Destination},
+		})
+	}
+	if err := args.ValidateMarket(); err != nil {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	session, err := sessions.Get(ctx)
+	if err != nil {
+		log.Printf("Error creating flight session: %v", err)
+		c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to initialize flight search"})
+		return
+	}
+
+	offers, priceRange, err := session.GetOffers(ctx, args)
+	if err != nil {
+		log.Printf("Error searching multi-city flights: %v", err)
+		c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to search flights: " + err.Error()})
+		return
+	}
+	var warnings []string
+	if err := session.FillMultiCityFlights(ctx, args, offers, directMultiCityOffersLimit); err != nil {
+		log.Printf("Error completing multi-city segments: %v", err)
+		warnings = append(warnings, fmt.Sprintf("some itineraries are incomplete: %v", err))
+	}
+
+	if pgDB != nil {
+		payload := worker.FlightSearchPayload{
+			Origin:        first.Origin,
+			Destination:   last.Destination,
+			DepartureDate: first.DepartureDate,
+			Adults:        searchRequest.Adults,
+			Children:      searchRequest.Children,
+			InfantsLap:    searchRequest.InfantsLap,
+			InfantsSeat:   searchRequest.InfantsSeat,
+			TripType:      searchRequest.TripType,
+			Class:         searchRequest.Class,
+			Stops:         searchRequest.Stops,
+			Currency:      searchRequest.Currency,
+			Segments:      segments,
+			Country:       searchRequest.Country,
+			GoogleHost:    searchRequest.GoogleHost,
+		}
+		if err := worker.NewWorker(pgDB, neo4jDB).StoreFlightOffers(ctx, payload, offers, priceRange); err != nil {
+			log.Printf("Failed to persist multi-city direct search in Postgres: %v", err)
+		}
+	}
+
+	googleFlightsURL, err := session.SerializeURL(ctx, args)
+	if err != nil {
+		log.Printf("Error generating Google Flights URL: %v", err)
+		googleFlightsURL = ""
+	}
+
+	responseOffers := make([]apitypes.DirectSearchOffer, 0, len(offers))
+	for i, offer := range offers {
+		responseOffers = append(responseOffers, multiCityOfferResponse(i, offer, segments, searchRequest.Currency))
+	}
+
+	c.JSON(http.StatusOK, apitypes.DirectSearchResponse{
+		Offers:           responseOffers,
+		SearchParams:     apitypes.DirectSearchParams{DirectSearchRequest: searchRequest},
+		GoogleFlightsURL: googleFlightsURL,
+		PriceRange:       convertPriceRange(priceRange),
+		Warnings:         warnings,
+	})
+}
+
+// multiCityOfferResponse renders a multi-city offer. Segments keeps the flights of the first
+// segment like other direct search offers; MultiCitySegments has the flights of every segment
+// once they were chosen, and Complete tells whether Price covers the whole itinerary.
+func multiCityOfferResponse(i int, offer flights.FullOffer, segments []worker.SearchSegment, currencyCode string) apitypes.DirectSearchOffer {
+	airlineCodes := []string{}
+	segmentFlights := offer.SegmentFlights
+	if len(segmentFlights) == 0 {
+		segmentFlights = [][]flights.Flight{offer.Flight}
+	}
+
+	multiCitySegments := make([]apitypes.MultiCitySegment, 0, len(segments))
+	for j, segment := range segments {
+		entry := apitypes.MultiCitySegment{
+			Origin:        segment.Origin,
+			Destination:   segment.Destination,
+			DepartureDate: segment.DepartureDate.Format(dateLayout),
+		}
+		if j < len(segmentFlights) {
+			entry.Flights = convertFlightSegments(segmentFlights[j], &airlineCodes)
+		}
+		multiCitySegments = append(multiCitySegments, entry)
+	}
+
+	complete := len(offer.SegmentFlights) == len(segments)
+	return apitypes.DirectSearchOffer{
+		ID:                fmt.Sprintf("offer%d", i+1),
+		Price:             offer.Price,
+		PriceAvailable:    offer.Price > 0,
+		Currency:          currencyCode,
+		TotalDuration:     int(offer.FlightDuration.Minutes()),
+		DepartureDate:     offer.StartDate.Format(dateLayout),
+		Segments:          convertFlightSegments(offer.Flight, &[]string{}),
+		MultiCitySegments: multiCitySegments,
+		Complete:          &complete,
+		AirlineCodes:      airlineCodes,
+	}
+}
+
+// maxMultiCityBulkDates is the number of departure dates a multi-city bulk search covers at most.
+const maxMultiCityBulkDates = 14
+
+// createMultiCityBulkSearch queues a bulk search of a multi-city itinerary over a range of
+// departure dates. Each date is one search of the bulk run.
+func createMultiCityBulkSearch(c *gin.Context, q queue.Queue, pgDB db.PostgresDB, req apitypes.BulkSearchRequest) {
+	segments, err := validateSearchSegments(req.Segments, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(req.Origins) > 0 || len(req.Destinations) > 0 {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "origins and destinations are taken from segments in multi_city bulk searches"})
+		return
+	}
+
+	// The departure date range applies to the first segment and defaults to its date.
+	from, to := req.DepartureDateFrom.Time, req.DepartureDateTo.Time
+	if from.IsZero() {
+		from = segments[0].DepartureDate
+	}
+	if to.IsZero() {
+		to = from
+	}
+	from, to = truncateDate(from), truncateDate(to)
+	if from.Before(truncateDate(time.Now())) {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "departure_date_from must not be in the past"})
+		return
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "departure_date_to must not be before departure_date_from"})
+		return
+	}
+	totalSearches := int(to.Sub(from).Hours()/24) + 1
+	if totalSearches > maxMultiCityBulkDates {
+		c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: fmt.Sprintf("multi_city bulk searches cover at most %d departure dates", maxMultiCityBulkDates)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	currencyCode := strings.ToUpper(req.Currency)
+	bulkSearchID, err := pgDB.CreateBulkSearchRecord(ctx, sql.NullInt32{}, totalSearches, currencyCode, "queued")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to create bulk search record: " + err.Error()})
+		return
+	}
+
+	first, last := segments[0], segments[len(segments)-1]
+	payload := worker.BulkSearchPayload{
+		Origins:             []string{first.Origin},
+		Destinations:        []string{last.Destination},
+		DepartureDateFrom:   from,
+		DepartureDateTo:     to,
+		Adults:              req.Adults,
+		Children:            req.Children,
+		InfantsLap:          req.InfantsLap,
+		InfantsSeat:         req.InfantsSeat,
+		TripType:            req.TripType,
+		Class:               req.Class,
+		Stops:               req.Stops,
+		Currency:            currencyCode,
+		Carriers:            req.Carriers,
+		MinLayoverMinutes:   req.MinLayoverMinutes,
+		MaxLayoverMinutes:   req.MaxLayoverMinutes,
+		ExcludeBasicEconomy: req.ExcludeBasicEconomy,
+		CarryOnBags:         req.CarryOnBags,
+		CheckedBags:         req.CheckedBags,
+		Country:             req.Country,
+		GoogleHost:          req.GoogleHost,
+		TZOffsetMin:         req.TZOffsetMin,
+		BulkSearchID:        bulkSearchID,
+		Segments:            segments,
+	}
+
+	jobID, err := q.Enqueue(idempotentContext(c), "bulk_search", payload)
+	if err != nil {
+		_ = pgDB.UpdateBulkSearchStatus(ctx, bulkSearchID, "failed")
+		c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, apitypes.BulkSearchAcceptedResponse{
+		JobID:         jobID,
+		BulkSearchID:  bulkSearchID,
+		TotalSearches: totalSearches,
+		Message:       "Bulk flight search job created successfully",
+	})
+}
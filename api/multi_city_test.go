@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/test/mocks"
+	"github.com/gilby125/google-flights-api/worker"
+)
+
+func TestValidateSearchSegments(t *testing.T) {
+	now := time.Date(2026, 5, 1, 15, 0, 0, 0, time.UTC)
+	day := func(d int) apitypes.DateOnly { return apitypes.DateOnly{Time: now.AddDate(0, 0, d)} }
+
+	segments, err := validateSearchSegments([]apitypes.SearchSegmentRequest{
+		{Origin: "jfk", Destination: "LHR", DepartureDate: day(10)},
+		{Origin: "CDG", Destination: "fco", DepartureDate: day(15)},
+		{Origin: "FCO", Destination: "JFK", DepartureDate: day(15)},
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []worker.SearchSegment{
+		{Origin: "JFK", Destination: "LHR", DepartureDate: time.Date(2026, 5, 11, 0, 0, 0, 0, time.UTC)},
+		{Origin: "CDG", Destination: "FCO", DepartureDate: time.Date(2026, 5, 16, 0, 0, 0, 0, time.UTC)},
+		{Origin: "FCO", Destination: "JFK", DepartureDate: time.Date(2026, 5, 16, 0, 0, 0, 0, time.UTC)},
+	}, segments)
+
+	valid := apitypes.SearchSegmentRequest{Origin: "JFK", Destination: "LHR", DepartureDate: day(10)}
+	tests := map[string][]apitypes.SearchSegmentRequest{
+		"one segment":         {valid},
+		"seven segments":      {valid, valid, valid, valid, valid, valid, valid},
+		"bad airport":         {valid, {Origin: "LONDON", Destination: "JFK", DepartureDate: day(12)}},
+		"same airports":       {valid, {Origin: "LHR", Destination: "LHR", DepartureDate: day(12)}},
+		"missing date":        {valid, {Origin: "LHR", Destination: "JFK"}},
+		"past date":           {{Origin: "JFK", Destination: "LHR", DepartureDate: day(-1)}, valid},
+		"dates out of order":  {valid, {Origin: "LHR", Destination: "JFK", DepartureDate: day(9)}},
+		"missing destination": {valid, {Origin: "LHR", DepartureDate: day(12)}},
+	}
+	for name, input := range tests {
+		_, err := validateSearchSegments(input, now)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestCreateSearch_MultiCity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockQueue := new(mocks.MockQueue)
+	router := gin.New()
+	router.POST("/search", CreateSearch(mockQueue))
+
+	first := time.Now().AddDate(0, 1, 0)
+	body := map[string]interface{}{
+		"trip_type": "multi_city",
+		"segments": []map[string]string{
+			{"origin": "JFK", "destination": "LHR", "departure_date": first.Format(dateLayout)},
+			{"origin": "LHR", "destination": "CDG", "departure_date": first.AddDate(0, 0, 4).Format(dateLayout)},
+			{"origin": "CDG", "destination": "JFK", "departure_date": first.AddDate(0, 0, 9).Format(dateLayout)},
+		},
+		"adults":   1,
+		"class":    "economy",
+		"stops":    "any",
+		"currency": "usd",
+	}
+
+	mockQueue.On("Enqueue", mock.Anything, "flight_search", mock.MatchedBy(func(payload interface{}) bool {
+		p, ok := payload.(worker.FlightSearchPayload)
+		return ok && p.TripType == "multi_city" && len(p.Segments) == 3 &&
+			p.Origin == "JFK" && p.Destination == "JFK" &&
+			p.Segments[1].Origin == "LHR" && p.Segments[1].Destination == "CDG" &&
+			p.DepartureDate.Equal(p.Segments[0].DepartureDate) && p.ReturnDate.IsZero()
+	})).Return("job-1", nil).Once()
+
+	data, _ := json.Marshal(body)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/search", bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	mockQueue.AssertExpectations(t)
+}
+
+func TestCreateSearch_MultiCityValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockQueue := new(mocks.MockQueue)
+	router := gin.New()
+	router.POST("/search", CreateSearch(mockQueue))
+
+	date := time.Now().AddDate(0, 1, 0).Format(dateLayout)
+	for name, body := range map[string]map[string]interface{}{
+		"single segment": {
+			"trip_type": "multi_city",
+			"segments":  []map[string]string{{"origin": "JFK", "destination": "LHR", "departure_date": date}},
+		},
+		"segments of a one-way trip": {
+			"trip_type":      "one_way",
+			"origin":         "JFK",
+			"destination":    "LHR",
+			"departure_date": date,
+			"segments": []map[string]string{
+				{"origin": "JFK", "destination": "LHR", "departure_date": date},
+				{"origin": "LHR", "destination": "JFK", "departure_date": date},
+			},
+		},
+	} {
+		body["adults"] = 1
+		body["class"] = "economy"
+		body["stops"] = "any"
+		body["currency"] = "USD"
+
+		data, _ := json.Marshal(body)
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/search", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, name)
+	}
+	mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateBulkSearch_MultiCity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB := new(mocks.MockPostgresDB)
+	mockQueue := new(mocks.MockQueue)
+	router := gin.New()
+	router.POST("/bulk-search", CreateBulkSearch(mockQueue, mockDB, nil))
+
+	first := time.Now().AddDate(0, 1, 0)
+	body := map[string]interface{}{
+		"trip_type": "multi_city",
+		"segments": []map[string]string{
+			{"origin": "SFO", "destination": "NRT", "departure_date": first.Format(dateLayout)},
+			{"origin": "HND", "destination": "SFO", "departure_date": first.AddDate(0, 0, 10).Format(dateLayout)},
+		},
+		"departure_date_from": first.Format(dateLayout),
+		"departure_date_to":   first.AddDate(0, 0, 4).Format(dateLayout),
+		"adults":              2,
+		"class":               "business",
+		"stops":               "any",
+		"currency":            "usd",
+	}
+
+	mockDB.On("CreateBulkSearchRecord", mock.Anything, mock.Anything, 5, "USD", "queued").Return(77, nil).Once()
+	mockQueue.On("Enqueue", mock.Anything, "bulk_search", mock.MatchedBy(func(payload interface{}) bool {
+		p, ok := payload.(worker.BulkSearchPayload)
+		return ok && p.BulkSearchID == 77 && p.TripType == "multi_city" && len(p.Segments) == 2 &&
+			p.Origins[0] == "SFO" && p.Destinations[0] == "SFO" && p.Segments[1].Origin == "HND"
+	})).Return("job-1", nil).Once()
+
+	data, _ := json.Marshal(body)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/bulk-search", bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, float64(5), resp["total_searches"])
+	mockDB.AssertExpectations(t)
+	mockQueue.AssertExpectations(t)
+}
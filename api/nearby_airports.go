@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/pkg/macros"
+)
+
+// nearbyAirportOverrides expands the NEAR:* tokens of inputs with the coordinates of the Postgres
+// airports table on top of the iata package. It returns the expansions, for
+// macros.ExpandAirportTokensWithOverrides, and every matched airport to annotate results with.
+// Without NEAR:* tokens nothing is queried; if the airports table can't be read, only the iata
+// coordinates are used.
+func nearbyAirportOverrides(ctx context.Context, pgDB db.PostgresDB, inputs ...[]string) (map[string][]string, []macros.NearbyAirport, error) {
+	var tokens []string
+	for _, list := range inputs {
+		for _, input := range list {
+			if macros.IsNearToken(input) {
+				tokens = append(tokens, input)
+			}
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, nil, nil
+	}
+
+	var locations []macros.AirportLocation
+	if pgDB != nil {
+		var err error
+		locations, err = listAirportLocations(ctx, pgDB)
+		if err != nil {
+			log.Printf("Expanding nearby-airport tokens without the airports table: %v", err)
+		}
+	}
+
+	overrides := make(map[string][]string, len(tokens))
+	var nearby []macros.NearbyAirport
+	for _, token := range tokens {
+		airports, err := macros.NearbyAirports(token, locations)
+		if err != nil {
+			return nil, nil, err
+		}
+		overrides[token] = macros.NearbyAirportCodes(airports)
+		nearby = append(nearby, airports...)
+	}
+	return overrides, nearby, nil
+}
+
+// mergeAirportOverrides adds the entries of extra to overrides, which may be nil.
+func mergeAirportOverrides(overrides, extra map[string][]string) map[string][]string {
+	if len(extra) == 0 {
+		return overrides
+	}
+	if overrides == nil {
+		overrides = make(map[string][]string, len(extra))
+	}
+	for token, airports := range extra {
+		overrides[token] = airports
+	}
+	return overrides
+}
+
+// nearbyAirportsByCode indexes nearby airports by code, keeping the closest anchor of airports
+// matched by several tokens.
+func nearbyAirportsByCode(nearby []macros.NearbyAirport) map[string]macros.NearbyAirport {
+	byCode := make(map[string]macros.NearbyAirport, len(nearby))
+	for _, airport := range nearby {
+		if existing, ok := byCode[airport.Code]; ok && existing.DistanceMiles <= airport.DistanceMiles {
+			continue
+		}
+		byCode[airport.Code] = airport
+	}
+	return byCode
+}
+
+// annotateNearbyRoute adds the anchor and distance of route's airports which were matched by a
+// NEAR:* token.
+func annotateNearbyRoute(route *apitypes.DirectSearchRoute, byCode map[string]macros.NearbyAirport) {
+	if airport, ok := byCode[route.Origin]; ok {
+		route.OriginAnchor = airport.Anchor
+		route.OriginDistanceMiles = airport.DistanceMiles
+	}
+	if airport, ok := byCode[route.Destination]; ok {
+		route.DestinationAnchor = airport.Anchor
+		route.DestinationDistanceMiles = airport.DistanceMiles
+	}
+}
+
+// listAirportLocations returns the airports table with coordinates.
+func listAirportLocations(ctx context.Context, pgDB db.PostgresDB) ([]macros.AirportLocation, error) {
+	rows, err := pgDB.QueryAirports(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query airports: %w", err)
+	}
+	defer rows.Close()
+
+	locations := make([]macros.AirportLocation, 0, 4096)
+	for rows.Next() {
+		var airport db.Airport
+		if err := rows.Scan(&airport.Code, &airport.Name, &airport.City, &airport.Country, &airport.Latitude, &airport.Longitude); err != nil {
+			return nil, fmt.Errorf("failed to scan airport: %w", err)
+		}
+		if !airport.Latitude.Valid || !airport.Longitude.Valid {
+			continue
+		}
+		locations = append(locations, macros.AirportLocation{
+			Code: airport.Code,
+			Lat:  airport.Latitude.Float64,
+			Lon:  airport.Longitude.Float64,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate airports: %w", err)
+	}
+	return locations, nil
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/pkg/macros"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNearbyAirportOverrides(t *testing.T) {
+	overrides, nearby, err := nearbyAirportOverrides(context.Background(), nil, []string{"JFK"}, []string{"REGION:EUROPE"})
+	require.NoError(t, err)
+	assert.Nil(t, overrides)
+	assert.Empty(t, nearby)
+
+	overrides, nearby, err = nearbyAirportOverrides(context.Background(), nil, []string{"NEAR:SFO:50"}, []string{"LHR"})
+	require.NoError(t, err)
+	require.Contains(t, overrides, "NEAR:SFO:50")
+	assert.Equal(t, "SFO", overrides["NEAR:SFO:50"][0])
+	assert.Len(t, nearby, len(overrides["NEAR:SFO:50"]))
+
+	_, _, err = nearbyAirportOverrides(context.Background(), nil, []string{"NEAR:SFO:5000"})
+	assert.Error(t, err)
+}
+
+func TestAnnotateNearbyRoute(t *testing.T) {
+	byCode := nearbyAirportsByCode([]macros.NearbyAirport{
+		{Code: "OAK", Anchor: "SFO", DistanceMiles: 11.1},
+		{Code: "OAK", Anchor: "SJC", DistanceMiles: 30.2},
+		{Code: "SJC", Anchor: "SJC", DistanceMiles: 0},
+	})
+
+	route := apitypes.DirectSearchRoute{Origin: "OAK", Destination: "LHR"}
+	annotateNearbyRoute(&route, byCode)
+	assert.Equal(t, "SFO", route.OriginAnchor)
+	assert.Equal(t, 11.1, route.OriginDistanceMiles)
+	assert.Empty(t, route.DestinationAnchor)
+}
+
+func TestMergeAirportOverrides(t *testing.T) {
+	assert.Nil(t, mergeAirportOverrides(nil, nil))
+
+	merged := mergeAirportOverrides(nil, map[string][]string{"NEAR:SFO:20": {"SFO", "OAK"}})
+	assert.Equal(t, []string{"SFO", "OAK"}, merged["NEAR:SFO:20"])
+
+	merged = mergeAirportOverrides(map[string][]string{macros.RegionWorldAll: {"JFK"}}, merged)
+	assert.Len(t, merged, 2)
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/pkg/export"
+	"github.com/gilby125/google-flights-api/pkg/health"
+	"github.com/gilby125/google-flights-api/pkg/macros"
+	"github.com/gilby125/google-flights-api/pkg/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// apiOperation describes a route of RegisterRoutes in the OpenAPI document. request and
+// response are values of the types of the JSON bodies the handlers decode and send; a nil
+// request means the route has no body.
+type apiOperation struct {
+	method   string
+	path     string
+	tag      string
+	summary  string
+	request  interface{}
+	response interface{}
+	// status is the status of successful responses, 200 if zero
+	status int
+	query  []openapi.Parameter
+	// optionalBody marks request bodies which may be empty or form encoded
+	optionalBody bool
+	// stream marks Server-Sent Events responses
+	stream bool
+	// export marks file downloads in the formats of pkg/export
+	export bool
+}
+
+const adminPathPrefix = "/api/v1/admin/"
+
+// apiOperations lists the API routes of RegisterRoutes; TestOpenAPISpecCoversRoutes fails if a
+// route is missing here.
+var apiOperations = []apiOperation{
+	{method: http.MethodGet, path: "/health", tag: "health", summary: "Health of the server and its dependencies", response: health.HealthReport{}},
+	{method: http.MethodGet, path: "/health/ready", tag: "health", summary: "Readiness check", response: health.HealthReport{}},
+	{method: http.MethodGet, path: "/health/live", tag: "health", summary: "Liveness check", response: health.HealthReport{}},
+	{method: http.MethodGet, path: "/api/openapi.json", tag: "meta", summary: "This OpenAPI document", response: openapi.Document{}},
+
+	// Legacy routes
+	{method: http.MethodPost, path: "/api/search", tag: "search", summary: "Direct flight search with immediate results", request: apitypes.DirectSearchRequest{}, response: apitypes.DirectSearchResponse{}, optionalBody: true},
+	{method: http.MethodGet, path: "/api/search-test", tag: "search", summary: "Test endpoint", response: apitypes.MessageResponse{}},
+	{method: http.MethodGet, path: "/api/airports", tag: "metadata", summary: "Cached airports", response: []apitypes.SampleAirport{}},
+	{method: http.MethodGet, path: "/api/price-history", tag: "metadata", summary: "Sample price history", response: []apitypes.SamplePricePoint{}, query: stringQuery("origin", "destination")},
+
+	// Metadata
+	{method: http.MethodGet, path: "/api/v1/airports", tag: "metadata", summary: "Airports", response: []apitypes.AirportResponse{}},
+	{method: http.MethodGet, path: "/api/v1/airports/top", tag: "metadata", summary: "Top airports of the continuous sweep", response: []apitypes.TopAirport{}},
+	{method: http.MethodGet, path: "/api/v1/airlines", tag: "metadata", summary: "Airlines", response: []apitypes.AirlineResponse{}},
+	{method: http.MethodGet, path: "/api/v1/regions", tag: "metadata", summary: "REGION:* tokens", response: []macros.RegionInfo{}},
+	{method: http.MethodGet, path: "/api/v1/airline-groups", tag: "metadata", summary: "GROUP:* tokens", response: []macros.AirlineGroupInfo{}},
+
+	// Searches
+	{method: http.MethodPost, path: "/api/v1/search", tag: "search", summary: "Queue a flight search", request: apitypes.SearchRequest{}, response: apitypes.JobAcceptedResponse{}, status: http.StatusAccepted},
+	{method: http.MethodGet, path: "/api/v1/search/:id", tag: "search", summary: "Search with its offers", response: apitypes.SearchResponse{}},
+	{method: http.MethodGet, path: "/api/v1/search", tag: "search", summary: "Searches, newest first", response: apitypes.SearchListResponse{}, query: intQuery("page", "per_page")},
+	{method: http.MethodPost, path: "/api/v1/flex-dates", tag: "search", summary: "Flexible-date price grid", request: apitypes.FlexDateSearchRequest{}, response: apitypes.FlexDateMatrix{}},
+	{method: http.MethodPost, path: "/api/v1/hotels/search", tag: "hotels", summary: "Direct hotel search", request: apitypes.HotelSearchRequest{}, response: apitypes.HotelSearchResponse{}},
+	{method: http.MethodPost, path: "/api/v1/bulk-search", tag: "search", summary: "Queue a bulk search", request: apitypes.BulkSearchRequest{}, response: apitypes.BulkSearchAcceptedResponse{}, status: http.StatusAccepted},
+	{method: http.MethodGet, path: "/api/v1/bulk-search/:id", tag: "search", summary: "Bulk search with its results", response: apitypes.BulkSearchResponse{}, query: intQuery("page", "per_page")},
+	{method: http.MethodPost, path: "/api/v1/pos-comparison", tag: "search", summary: "Queue a point-of-sale price comparison", request: apitypes.PosComparisonRequest{}, response: apitypes.PosComparisonAcceptedResponse{}, status: http.StatusAccepted},
+	{method: http.MethodGet, path: "/api/v1/pos-comparison/:id", tag: "search", summary: "Point-of-sale comparison with its results", response: apitypes.PosComparisonResponse{}},
+	{method: http.MethodGet, path: "/api/v1/price-history/:origin/:destination", tag: "search", summary: "Price history of a route", response: apitypes.PriceHistoryResponse{}, query: arrayQuery("include_airline_groups", "exclude_airline_groups")},
+
+	// Graph
+	{method: http.MethodGet, path: "/api/v1/graph/path", tag: "graph", summary: "Cheapest paths between two airports", response: apitypes.CheapestPathResponse{},
+		query: append(stringQuery("origin", "dest"), append(intQuery("maxHops"), numberQuery("maxPrice")...)...)},
+	{method: http.MethodGet, path: "/api/v1/graph/connections", tag: "graph", summary: "Airports reachable from an origin", response: apitypes.ConnectionsResponse{},
+		query: append(stringQuery("origin"), append(intQuery("maxHops"), numberQuery("maxPrice")...)...)},
+	{method: http.MethodGet, path: "/api/v1/graph/route-stats", tag: "graph", summary: "Price statistics of a route", response: apitypes.RouteStats{}, query: stringQuery("origin", "dest")},
+	{method: http.MethodGet, path: "/api/v1/graph/route-details", tag: "graph", summary: "Price statistics of a route with filters", response: apitypes.RouteStats{},
+		query: append(stringQuery("origin", "dest", "dateFrom", "dateTo", "tripType", "airlines", "excludeAirlines"), intQuery("maxAgeDays", "limitSamples")...)},
+	{method: http.MethodGet, path: "/api/v1/graph/explore", tag: "graph", summary: "Cheapest destinations in the graph", response: apitypes.ExploreResponse{},
+		query: append(stringQuery("origin", "origins", "dateFrom", "dateTo", "source", "tripType", "class", "airlines", "excludeAirlines"), append(intQuery("maxHops", "maxAgeDays", "limit"), numberQuery("maxPrice")...)...)},
+
+	// Admin: scheduled jobs, bulk searches and sweeps
+	{method: http.MethodGet, path: "/api/v1/admin/jobs", tag: "admin", summary: "Scheduled jobs", response: []apitypes.ScheduledJobResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/jobs", tag: "admin", summary: "Create a scheduled job", request: apitypes.JobRequest{}, response: apitypes.ScheduledJobSavedResponse{}, status: http.StatusCreated},
+	{method: http.MethodGet, path: "/api/v1/admin/bulk-jobs", tag: "admin", summary: "Bulk search runs, newest first", response: apitypes.BulkSearchListResponse{}, query: intQuery("limit", "offset")},
+	{method: http.MethodGet, path: "/api/v1/admin/bulk-jobs/:id", tag: "admin", summary: "Bulk search run with its results", response: apitypes.BulkSearchResultsResponse{}, query: intQuery("limit", "offset")},
+	{method: http.MethodPost, path: "/api/v1/admin/bulk-jobs", tag: "admin", summary: "Create a scheduled bulk search job per route", request: apitypes.BulkJobRequest{}, response: apitypes.BulkJobsCreatedResponse{}, status: http.StatusCreated},
+	{method: http.MethodGet, path: "/api/v1/admin/bulk-jobs/:id/offers", tag: "admin", summary: "Offers of a bulk search run", response: apitypes.BulkSearchOffersResponse{},
+		query: append(intQuery("limit", "offset"), stringQuery("sort_by")...)},
+	{method: http.MethodGet, path: "/api/v1/admin/bulk-jobs/:id/export", tag: "admin", summary: "Export the results of a bulk search run", export: true, query: exportParams()},
+	{method: http.MethodGet, path: "/api/v1/admin/bulk-jobs/:id/offers/export", tag: "admin", summary: "Export the offers of a bulk search run", export: true, query: exportParams()},
+	{method: http.MethodPost, path: "/api/v1/admin/price-graph-sweeps", tag: "admin", summary: "Queue a price graph sweep", request: apitypes.PriceGraphSweepRequest{}, response: apitypes.PriceGraphSweepAcceptedResponse{}, status: http.StatusAccepted},
+	{method: http.MethodGet, path: "/api/v1/admin/price-graph-sweeps", tag: "admin", summary: "Price graph sweep runs", response: apitypes.PriceGraphSweepListResponse{}, query: intQuery("limit", "offset")},
+	{method: http.MethodGet, path: "/api/v1/admin/price-graph-sweeps/:id", tag: "admin", summary: "Price graph sweep with its fares", response: apitypes.PriceGraphSweepResultsResponse{}, query: intQuery("limit", "offset")},
+	{method: http.MethodGet, path: "/api/v1/admin/price-graph-sweeps/:id/export", tag: "admin", summary: "Export the fares of a price graph sweep", export: true, query: exportParams()},
+	{method: http.MethodGet, path: "/api/v1/admin/jobs/:id", tag: "admin", summary: "Scheduled job", response: apitypes.ScheduledJobResponse{}},
+	{method: http.MethodPut, path: "/api/v1/admin/jobs/:id", tag: "admin", summary: "Replace a scheduled job", request: apitypes.JobRequest{}, response: apitypes.ScheduledJobSavedResponse{}},
+	{method: http.MethodDelete, path: "/api/v1/admin/jobs/:id", tag: "admin", summary: "Delete a scheduled job", response: apitypes.MessageResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/jobs/:id/run", tag: "admin", summary: "Queue a scheduled job now", response: apitypes.JobAcceptedResponse{}, status: http.StatusAccepted},
+	{method: http.MethodPost, path: "/api/v1/admin/jobs/:id/enable", tag: "admin", summary: "Enable a scheduled job", response: apitypes.MessageResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/jobs/:id/disable", tag: "admin", summary: "Disable a scheduled job", response: apitypes.MessageResponse{}},
+
+	// Admin: workers and queues
+	{method: http.MethodGet, path: "/api/v1/admin/workers", tag: "queue", summary: "Local and remote workers", response: []apitypes.WorkerStatusResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/queue", tag: "queue", summary: "Job counters of every queue by state", response: map[string]map[string]int64{}},
+	{method: http.MethodGet, path: "/api/v1/admin/queue/:name/backlog", tag: "queue", summary: "Unacked jobs of a queue", response: apitypes.QueueBacklogResponse{}, query: intQuery("limit")},
+	{method: http.MethodGet, path: "/api/v1/admin/queue/:name/jobs", tag: "queue", summary: "Jobs of a queue in a state", response: apitypes.QueueJobListResponse{},
+		query: append(stringQuery("state"), intQuery("limit", "offset")...)},
+	{method: http.MethodGet, path: "/api/v1/admin/queue/:name/jobs/:id", tag: "queue", summary: "Job of a queue", response: apitypes.QueueJobResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/queue/:name/jobs/:id/cancel", tag: "queue", summary: "Cancel a job", response: apitypes.QueueCancelJobResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/queue/:name/enqueues", tag: "queue", summary: "Enqueue counts of a queue by source", response: apitypes.QueueEnqueueMetricsResponse{}, query: intQuery("minutes")},
+	{method: http.MethodGet, path: "/api/v1/admin/rate-limits", tag: "queue", summary: "Rate limit configuration", response: apitypes.RateLimitStatsResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/queue/:name/cancel-processing", tag: "queue", summary: "Cancel the processing jobs of a queue", response: apitypes.QueueActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/queue/:name/drain", tag: "queue", summary: "Cancel the processing and clear the pending jobs of a queue", response: apitypes.QueueActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/queue/:name/clear", tag: "queue", summary: "Clear the pending jobs of a queue", response: apitypes.QueueActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/queue/:name/clear-failed", tag: "queue", summary: "Clear the failed jobs of a queue", response: apitypes.QueueActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/queue/:name/clear-processing", tag: "queue", summary: "Clear the processing jobs of a queue", response: apitypes.QueueActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/queue/:name/retry-failed", tag: "queue", summary: "Re-enqueue the failed jobs of a queue", response: apitypes.QueueActionResponse{}, query: intQuery("limit")},
+	{method: http.MethodGet, path: "/api/v1/admin/queue/:name/dead-letters", tag: "queue", summary: "Jobs of a queue that ran out of attempts", response: apitypes.QueueDeadLetterListResponse{},
+		query: append(stringQuery("error_class"), intQuery("limit", "offset")...)},
+	{method: http.MethodPost, path: "/api/v1/admin/queue/:name/dead-letters/replay", tag: "queue", summary: "Re-enqueue selected dead letters of a queue", request: apitypes.DeadLetterReplayRequest{}, response: apitypes.QueueDeadLetterReplayResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/queue/:name/dead-letters/:id", tag: "queue", summary: "Dead letter of a job", response: apitypes.QueueDeadLetterResponse{}},
+	{method: http.MethodPut, path: "/api/v1/admin/queue/:name/dead-letters/:id/payload", tag: "queue", summary: "Replace the payload a dead letter is replayed with", request: apitypes.DeadLetterPayloadRequest{}, response: apitypes.QueueDeadLetterResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/events", tag: "queue", summary: "Worker and queue events (Server-Sent Events)", stream: true},
+
+	// Admin: continuous sweep
+	{method: http.MethodGet, path: "/api/v1/admin/continuous-sweep/status", tag: "continuous-sweep", summary: "State of the continuous sweep", response: apitypes.ContinuousSweepStatusResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/continuous-sweep/start", tag: "continuous-sweep", summary: "Start the continuous sweep", response: apitypes.ContinuousSweepActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/continuous-sweep/stop", tag: "continuous-sweep", summary: "Stop the continuous sweep", response: apitypes.ContinuousSweepActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/continuous-sweep/pause", tag: "continuous-sweep", summary: "Pause the continuous sweep", response: apitypes.ContinuousSweepActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/continuous-sweep/resume", tag: "continuous-sweep", summary: "Resume the continuous sweep", response: apitypes.ContinuousSweepActionResponse{}},
+	{method: http.MethodPut, path: "/api/v1/admin/continuous-sweep/config", tag: "continuous-sweep", summary: "Change the configuration of the continuous sweep", request: apitypes.SweepConfigRequest{}, response: apitypes.ContinuousSweepActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/continuous-sweep/skip", tag: "continuous-sweep", summary: "Skip the current route", response: apitypes.ContinuousSweepActionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/continuous-sweep/restart", tag: "continuous-sweep", summary: "Restart the current sweep", response: apitypes.ContinuousSweepActionResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/continuous-sweep/stats", tag: "continuous-sweep", summary: "Statistics of the last sweeps", response: apitypes.ContinuousSweepStatsListResponse{}, query: intQuery("limit")},
+	{method: http.MethodGet, path: "/api/v1/admin/continuous-sweep/results", tag: "continuous-sweep", summary: "Fares found by the continuous sweep", response: apitypes.ContinuousSweepResultsResponse{},
+		query: append(stringQuery("origin", "destination", "from", "to"), intQuery("limit", "offset")...)},
+	{method: http.MethodGet, path: "/api/v1/admin/continuous-sweep/results/export", tag: "continuous-sweep", summary: "Export the fares found by the continuous sweep", export: true, query: exportParams()},
+
+	// Admin: deals
+	{method: http.MethodGet, path: "/api/v1/admin/deals", tag: "deals", summary: "Detected deals", response: apitypes.DealListResponse{},
+		query: append(stringQuery("origin", "destination", "classification", "status"), intQuery("limit", "offset")...)},
+	{method: http.MethodGet, path: "/api/v1/admin/deals/export", tag: "deals", summary: "Export the detected deals of every status", export: true,
+		query: append(exportParams(), stringQuery("classification", "status")...)},
+	{method: http.MethodGet, path: "/api/v1/admin/deal-alerts", tag: "deals", summary: "Published deal alerts", response: apitypes.DealAlertListResponse{}, query: intQuery("limit", "offset")},
+}
+
+var (
+	openAPISpecOnce sync.Once
+	openAPISpec     *openapi.Document
+)
+
+// OpenAPISpec returns the OpenAPI document of the API, generated from the request and response
+// types of apiOperations. The document is built once and must not be modified.
+func OpenAPISpec() *openapi.Document {
+	openAPISpecOnce.Do(func() {
+		openAPISpec = buildOpenAPISpec()
+	})
+	return openAPISpec
+}
+
+func buildOpenAPISpec() *openapi.Document {
+	doc := openapi.NewDocument(openapi.Info{
+		Title:       "Google Flights API",
+		Version:     "1.0.0",
+		Description: "Flight search, bulk search and price tracking API. See docs/API_CONTRACT.md for the behavior of the endpoints.",
+	})
+
+	generator := openapi.NewGenerator()
+	generator.Override(openapi.Document{}, openapi.Schema{Type: "object", Description: "OpenAPI " + openapi.Version + " document", AdditionalProperties: &openapi.Schema{}})
+	generator.Override(apitypes.DateOnly{}, openapi.Schema{
+		Type:        "string",
+		Format:      "date",
+		Description: "YYYY-MM-DD (RFC 3339 timestamps are accepted)",
+		Nullable:    true,
+	})
+	errorResponse := openapi.Response{Description: "Error", Content: openapi.JSONContent(generator.Schema(apitypes.ErrorResponse{}))}
+
+	for _, route := range apiOperations {
+		op := &openapi.Operation{
+			OperationID: operationID(route.method, route.path),
+			Summary:     route.summary,
+			Tags:        []string{route.tag},
+			Parameters:  route.query,
+			Responses:   map[string]openapi.Response{"default": errorResponse},
+		}
+		if route.request != nil {
+			op.RequestBody = &openapi.RequestBody{
+				Required: !route.optionalBody,
+				Content:  openapi.JSONContent(generator.Schema(route.request)),
+			}
+		}
+
+		status := route.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := openapi.Response{Description: http.StatusText(status)}
+		switch {
+		case route.stream:
+			success.Content = map[string]openapi.MediaType{"text/event-stream": {Schema: &openapi.Schema{Type: "string"}}}
+		case route.export:
+			success.Content = map[string]openapi.MediaType{}
+			for _, format := range export.Formats {
+				schema := &openapi.Schema{Type: "string"}
+				if format == export.Parquet {
+					schema.Format = "binary"
+				}
+				success.Content[format.ContentType()] = openapi.MediaType{Schema: schema}
+			}
+		case route.response != nil:
+			success.Content = openapi.JSONContent(generator.Schema(route.response))
+		}
+		op.Responses[strconv.Itoa(status)] = success
+
+		if strings.HasPrefix(route.path, adminPathPrefix) {
+			op.Security = []map[string][]string{{"bearerAuth": {}}, {"basicAuth": {}}}
+			op.Responses[strconv.Itoa(http.StatusUnauthorized)] = openapi.Response{
+				Description: "Missing or invalid admin credentials (if admin auth is enabled)",
+				Content:     errorResponse.Content,
+			}
+		}
+
+		doc.AddOperation(route.method, route.path, op)
+	}
+
+	doc.Components.Schemas = generator.Schemas()
+	doc.Components.SecuritySchemes = map[string]*openapi.SecurityScheme{
+		"bearerAuth": {Type: "http", Scheme: "bearer"},
+		"basicAuth":  {Type: "http", Scheme: "basic"},
+	}
+	return doc
+}
+
+// GetOpenAPISpec returns a handler which serves the OpenAPI document of the API.
+func GetOpenAPISpec() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, OpenAPISpec())
+	}
+}
+
+// operationID derives a unique operation ID from the method and path of a route, e.g.
+// get_api_v1_admin_jobs_id for GET /api/v1/admin/jobs/:id.
+func operationID(method, path string) string {
+	replacer := strings.NewReplacer("/", "_", ":", "", "*", "", "-", "_", ".", "_")
+	return strings.ToLower(method) + replacer.Replace(path)
+}
+
+func stringQuery(names ...string) []openapi.Parameter {
+	return queryParams(&openapi.Schema{Type: "string"}, names)
+}
+
+func intQuery(names ...string) []openapi.Parameter {
+	return queryParams(&openapi.Schema{Type: "integer"}, names)
+}
+
+func numberQuery(names ...string) []openapi.Parameter {
+	return queryParams(&openapi.Schema{Type: "number"}, names)
+}
+
+func arrayQuery(names ...string) []openapi.Parameter {
+	return queryParams(&openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}, names)
+}
+
+// exportParams returns the format and filter parameters of the export routes.
+func exportParams() []openapi.Parameter {
+	format := openapi.Parameter{Name: "format", In: "query", Schema: &openapi.Schema{Type: "string", Enum: []string{}}}
+	for _, f := range export.Formats {
+		format.Schema.Enum = append(format.Schema.Enum, string(f))
+	}
+	dates := queryParams(&openapi.Schema{Type: "string", Format: "date"}, []string{"from", "to"})
+	return append(append([]openapi.Parameter{format}, stringQuery("origin", "destination")...), dates...)
+}
+
+func queryParams(schema *openapi.Schema, names []string) []openapi.Parameter {
+	params := make([]openapi.Parameter, len(names))
+	for i, name := range names {
+		params[i] = openapi.Parameter{Name: name, In: "query", Schema: schema}
+	}
+	return params
+}
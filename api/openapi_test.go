@@ -0,0 +1,315 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/pkg/cache"
+	"github.com/gilby125/google-flights-api/pkg/middleware"
+	"github.com/gilby125/google-flights-api/pkg/openapi"
+	"github.com/gilby125/google-flights-api/test/mocks"
+)
+
+func newSpecTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router, nil, nil, nil, nil, &config.Config{}, nil)
+	return router
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	router := newSpecTestRouter()
+	spec := OpenAPISpec()
+
+	registered := map[string]bool{}
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") && !strings.HasPrefix(route.Path, "/health") {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		assert.NotNil(t, spec.Operation(route.Method, route.Path), "route %s is missing from apiOperations", key)
+	}
+
+	for _, op := range apiOperations {
+		key := op.method + " " + op.path
+		assert.True(t, registered[key], "apiOperations has %s but RegisterRoutes doesn't", key)
+	}
+}
+
+func TestOpenAPISpecEndpoint(t *testing.T) {
+	router := newSpecTestRouter()
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc openapi.Document
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+
+	search := doc.Paths["/api/v1/search"]["post"]
+	require.NotNil(t, search)
+	assert.Equal(t, "#/components/schemas/SearchRequest", search.RequestSchema().Ref)
+	assert.Equal(t, "#/components/schemas/JobAcceptedResponse", search.ResponseSchema("202").Ref)
+	assert.Empty(t, search.Security)
+
+	searchRequest := doc.Components.Schemas["SearchRequest"]
+	require.NotNil(t, searchRequest)
+	assert.Contains(t, searchRequest.Required, "adults")
+	assert.NotContains(t, searchRequest.Required, "origin", "required_unless is checked by the handler")
+	assert.Equal(t, []string{"economy", "premium_economy", "business", "first"}, searchRequest.Properties["class"].Enum)
+	assert.Equal(t, "date", searchRequest.Properties["departure_date"].Format)
+
+	job := doc.Paths["/api/v1/admin/jobs/{id}"]["put"]
+	require.NotNil(t, job)
+	assert.NotEmpty(t, job.Security)
+	require.Len(t, job.Parameters, 1)
+	assert.Equal(t, openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}, job.Parameters[0])
+
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			assert.NotEmpty(t, op.Responses, "%s %s has no responses", method, path)
+		}
+	}
+}
+
+func TestValidateRequestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockQueue := new(mocks.MockQueue)
+	mockQueue.On("Enqueue", mock.Anything, "flight_search", mock.Anything).Return("job-1", nil)
+
+	router := gin.New()
+	router.Use(middleware.ValidateRequest(OpenAPISpec()))
+	router.POST("/api/v1/search", CreateSearch(mockQueue))
+
+	departure := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+	valid := `{"origin":"JFK","destination":"LAX","departure_date":"` + departure + `","adults":1,"trip_type":"one_way","class":"economy","stops":"any","currency":"USD"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "valid", body: valid, wantStatus: http.StatusAccepted},
+		{name: "unknown fields and case-insensitive names", body: strings.NewReplacer(`"adults"`, `"Adults"`, `"currency"`, `"extra":true,"currency"`).Replace(valid), wantStatus: http.StatusAccepted},
+		{name: "missing required field", body: strings.Replace(valid, `"adults":1,`, "", 1), wantError: "adults is required"},
+		{name: "null required field", body: strings.Replace(valid, `"adults":1`, `"adults":null`, 1), wantError: "adults is required"},
+		{name: "wrong type", body: strings.Replace(valid, `"adults":1`, `"adults":"1"`, 1), wantError: "adults must be an integer"},
+		{name: "below minimum", body: strings.Replace(valid, `"adults":1`, `"adults":0`, 1), wantError: "adults must be at least 1"},
+		{name: "not in enum", body: strings.Replace(valid, `"economy"`, `"coach"`, 1), wantError: "class must be one of [economy premium_economy business first]"},
+		{name: "wrong length", body: strings.Replace(valid, `"USD"`, `"DOLLAR"`, 1), wantError: "currency must be at most 3 characters long"},
+		{name: "nested field", body: strings.Replace(valid, `"currency"`, `"segments":[{"origin":7}],"currency"`, 1), wantError: "segments[0].origin must be a string"},
+		{name: "invalid JSON", body: `{"origin":`, wantError: "invalid JSON"},
+		{name: "empty body", body: "", wantError: "request body is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/search", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(rec, req)
+
+			if tt.wantError == "" {
+				assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+				return
+			}
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			var resp apitypes.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Contains(t, resp.Error, tt.wantError)
+		})
+	}
+}
+
+func TestValidateRequestMiddleware_SkipsOtherContentTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ValidateRequest(OpenAPISpec()))
+	router.POST("/api/search", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.POST("/unknown", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/search", strings.NewReader("origin=JFK&adults=two"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/unknown", strings.NewReader(`{"adults":"two"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestOpenAPISpecMatchesResponses(t *testing.T) {
+	spec := OpenAPISpec()
+
+	// Responses of real handlers have to match the documented schemas
+	router := newSpecTestRouter()
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/health/live", nil)
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, spec.ValidateJSON(spec.Operation(http.MethodGet, "/health/live").ResponseSchema("200"), rec.Body.Bytes()))
+
+	mockQueue := new(mocks.MockQueue)
+	mockQueue.On("Enqueue", mock.Anything, "flight_search", mock.Anything).Return("job-1", nil)
+	searchRouter := gin.New()
+	searchRouter.POST("/api/v1/search", CreateSearch(mockQueue))
+	body, _ := json.Marshal(apitypes.SearchRequest{
+		Origin:        "JFK",
+		Destination:   "LAX",
+		DepartureDate: apitypes.DateOnly{Time: time.Now().AddDate(0, 1, 0)},
+		Adults:        1,
+		TripType:      "one_way",
+		Class:         "economy",
+		Stops:         "any",
+		Currency:      "USD",
+	})
+
+	// the request type itself has to pass its schema
+	op := spec.Operation(http.MethodPost, "/api/v1/search")
+	require.NoError(t, spec.ValidateJSON(op.RequestSchema(), body))
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/search", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	searchRouter.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	assert.NoError(t, spec.ValidateResponseJSON(op.ResponseSchema("202"), rec.Body.Bytes()))
+	assert.NoError(t, spec.ValidateResponseJSON(op.ResponseSchema("default"), []byte(`{"error":"boom"}`)))
+}
+
+func TestOpenAPISpecDocumentsEveryResponse(t *testing.T) {
+	spec := OpenAPISpec()
+	for _, route := range apiOperations {
+		if route.stream || route.export {
+			continue
+		}
+		key := route.method + " " + route.path
+		require.NotNil(t, route.response, "%s has no response type", key)
+		typ := reflect.TypeOf(route.response)
+		for typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+			typ = typ.Elem()
+		}
+		assert.NotEqual(t, reflect.Interface, typ.Kind(), "%s responds with untyped values", key)
+
+		schema := spec.Operation(route.method, route.path).ResponseSchema(strconv.Itoa(max(route.status, http.StatusOK)))
+		require.NotNil(t, schema, key)
+		assert.False(t, schema.Ref == "" && schema.Type == "", "%s has an empty response schema", key)
+	}
+}
+
+// TestOpenAPISpecMatchesHandlerResponses runs handlers against mocked stores and checks their
+// responses against the documented schemas, rejecting fields the document doesn't declare.
+func TestOpenAPISpecMatchesHandlerResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	spec := OpenAPISpec()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	pgDB := new(mocks.MockPostgresDB)
+	rows := new(mocks.MockRows)
+	rows.On("Next").Return(true).Once()
+	rows.On("Next").Return(false)
+	rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*(args.Get(0).(*string)) = "JFK"
+		*(args.Get(1).(*string)) = "John F Kennedy"
+		*(args.Get(2).(*string)) = "New York"
+		*(args.Get(3).(*string)) = "US"
+		*(args.Get(4).(*sql.NullFloat64)) = sql.NullFloat64{Float64: 40.64, Valid: true}
+	})
+	rows.On("Close").Return(nil)
+	rows.On("Err").Return(nil)
+	pgDB.On("QueryAirports", mock.Anything).Return(rows, nil)
+	pgDB.On("ListActiveDeals", mock.Anything, mock.Anything).Return([]db.DetectedDeal{{
+		ID: 1, Origin: "JFK", Destination: "LHR", DepartureDate: now, Price: 199, Currency: "USD",
+		ReturnDate:      sql.NullTime{Time: now.AddDate(0, 0, 7), Valid: true},
+		DiscountPercent: sql.NullFloat64{Float64: 42, Valid: true},
+		DealScore:       sql.NullInt32{Int32: 80, Valid: true},
+		NormalizedPrice: sql.NullFloat64{Float64: 199, Valid: true}, NormalizedCurrency: sql.NullString{String: "USD", Valid: true},
+		CabinClass: "economy", Status: db.DealStatusActive, FirstSeenAt: now, TimesSeen: 2,
+	}}, nil)
+	pgDB.On("ListDealAlerts", mock.Anything, 50, 0).Return([]db.DealAlert{{
+		ID: 1, DetectedDealID: 1, Origin: "JFK", Destination: "LHR", Price: 199, Currency: "USD",
+		PublishedAt: now, PublishMethod: "auto", NotificationSent: true,
+		NotificationSentAt: sql.NullTime{Time: now, Valid: true},
+	}}, nil)
+	pgDB.On("GetContinuousSweepProgress", mock.Anything).Return(&db.ContinuousSweepProgress{
+		SweepNumber: 3, RouteIndex: 10, TotalRoutes: 100, TripLengths: []int{7, 14}, PacingMode: "adaptive",
+		CurrentOrigin: sql.NullString{String: "JFK", Valid: true}, LastUpdated: now, IsRunning: true,
+	}, nil)
+	pgDB.On("ListContinuousSweepStats", mock.Anything, 10).Return([]db.ContinuousSweepStats{{
+		ID: 1, SweepNumber: 3, StartedAt: now, TotalRoutes: 100, SuccessfulQueries: 98, FailedQueries: 2,
+		MinPriceFound: sql.NullFloat64{Float64: 99, Valid: true}, CreatedAt: now,
+	}}, nil)
+
+	q := new(mocks.MockQueue)
+	q.On("CancelProcessing", mock.Anything, "flight_search").Return(int64(0), nil)
+	q.On("ClearQueue", mock.Anything, "flight_search").Return(int64(2), nil)
+	q.On("GetQueueStats", mock.Anything, "flight_search").Return(map[string]int64{"pending": 0, "processing": 0}, nil)
+
+	router := gin.New()
+	router.GET("/api/openapi.json", GetOpenAPISpec())
+	router.GET("/api/airports", CachedAirportsHandler(cache.NewCacheManager(cache.NewMemoryCache(cache.DefaultMemoryCacheEntries))))
+	router.GET("/api/price-history", MockPriceHistoryHandler())
+	router.GET("/api/v1/airports", GetAirports(pgDB))
+	router.GET("/api/v1/airports/top", GetTopAirports())
+	router.GET("/api/v1/admin/deals", listDeals(pgDB))
+	router.GET("/api/v1/admin/deal-alerts", listDealAlerts(pgDB))
+	router.GET("/api/v1/admin/continuous-sweep/status", getContinuousSweepStatus(nil, pgDB))
+	router.GET("/api/v1/admin/continuous-sweep/stats", getContinuousSweepStats(pgDB))
+	router.POST("/api/v1/admin/queue/:name/drain", DrainQueue(q))
+
+	tests := []struct {
+		method string
+		route  string
+		path   string
+	}{
+		{http.MethodGet, "/api/openapi.json", "/api/openapi.json"},
+		{http.MethodGet, "/api/airports", "/api/airports"},
+		{http.MethodGet, "/api/price-history", "/api/price-history?origin=JFK&destination=LHR"},
+		{http.MethodGet, "/api/v1/airports", "/api/v1/airports"},
+		{http.MethodGet, "/api/v1/airports/top", "/api/v1/airports/top"},
+		{http.MethodGet, "/api/v1/admin/deals", "/api/v1/admin/deals"},
+		{http.MethodGet, "/api/v1/admin/deal-alerts", "/api/v1/admin/deal-alerts"},
+		{http.MethodGet, "/api/v1/admin/continuous-sweep/status", "/api/v1/admin/continuous-sweep/status"},
+		{http.MethodGet, "/api/v1/admin/continuous-sweep/stats", "/api/v1/admin/continuous-sweep/stats"},
+		{http.MethodPost, "/api/v1/admin/queue/:name/drain", "/api/v1/admin/queue/flight_search/drain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.route, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			router.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			op := spec.Operation(tt.method, tt.route)
+			require.NotNil(t, op)
+			assert.NoError(t, spec.ValidateResponseJSON(op.ResponseSchema("200"), rec.Body.Bytes()), rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/gilby125/google-flights-api/worker"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/currency"
+)
+
+// CreatePosComparison returns a handler which queues a point-of-sale comparison
+func CreatePosComparison(q queue.Queue, pgDB db.PostgresDB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req apitypes.PosComparisonRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		req.Origin = strings.ToUpper(req.Origin)
+		req.Destination = strings.ToUpper(req.Destination)
+		req.Currency = strings.ToUpper(req.Currency)
+		if _, err := currency.ParseISO(req.Currency); err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid currency code"})
+			return
+		}
+
+		now := time.Now().Truncate(24 * time.Hour)
+		if req.DepartureDate.Before(now) {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Departure date must be in the future"})
+			return
+		}
+		if req.TripType == "round_trip" {
+			if !req.ReturnDate.Time.After(req.DepartureDate.Time) {
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Return date must be after departure date"})
+				return
+			}
+		} else {
+			req.ReturnDate = apitypes.DateOnly{}
+		}
+
+		markets := make([]worker.PosComparisonMarket, 0, len(req.Markets))
+		seen := make(map[string]struct{}, len(req.Markets))
+		for _, m := range req.Markets {
+			market := worker.PosComparisonMarket{
+				Country:    strings.ToUpper(m.Country),
+				Currency:   strings.ToUpper(m.Currency),
+				GoogleHost: strings.ToLower(m.GoogleHost),
+			}
+			options := flights.Options{Country: market.Country, GoogleHost: market.GoogleHost}
+			if err := options.ValidateMarket(); err != nil {
+				c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: err.Error()})
+				return
+			}
+			if market.Currency != "" {
+				if _, err := currency.ParseISO(market.Currency); err != nil {
+					c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid currency code of market " + market.Country})
+					return
+				}
+			}
+			key := market.Country + "/" + market.Currency + "/" + market.GoogleHost
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			markets = append(markets, market)
+		}
+
+		var returnDate sql.NullTime
+		if !req.ReturnDate.IsZero() {
+			returnDate = sql.NullTime{Time: req.ReturnDate.Time, Valid: true}
+		}
+		comparisonID, err := pgDB.CreatePosComparison(c.Request.Context(), db.PosComparison{
+			Origin:        req.Origin,
+			Destination:   req.Destination,
+			DepartureDate: req.DepartureDate.Time,
+			ReturnDate:    returnDate,
+			TripType:      req.TripType,
+			Class:         req.Class,
+			Stops:         req.Stops,
+			Adults:        req.Adults,
+			Children:      req.Children,
+			InfantsLap:    req.InfantsLap,
+			InfantsSeat:   req.InfantsSeat,
+			Currency:      req.Currency,
+			MarketCount:   len(markets),
+		})
+		if err != nil {
+			log.Printf("Error creating pos comparison: %v", err)
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to create comparison"})
+			return
+		}
+
+		payload := worker.PosComparisonPayload{
+			ComparisonID:  comparisonID,
+			Origin:        req.Origin,
+			Destination:   req.Destination,
+			DepartureDate: req.DepartureDate.Time,
+			ReturnDate:    req.ReturnDate.Time,
+			Adults:        req.Adults,
+			Children:      req.Children,
+			InfantsLap:    req.InfantsLap,
+			InfantsSeat:   req.InfantsSeat,
+			TripType:      req.TripType,
+			Class:         req.Class,
+			Stops:         req.Stops,
+			Currency:      req.Currency,
+			Markets:       markets,
+		}
+		jobID, err := q.Enqueue(idempotentContext(c), "pos_comparison", payload)
+		if err != nil {
+			log.Printf("Error enqueuing pos comparison %d: %v", comparisonID, err)
+			_ = pgDB.CompletePosComparison(c.Request.Context(), db.PosComparisonSummary{
+				ComparisonID: comparisonID,
+				Status:       "failed",
+				ErrorMessage: sql.NullString{String: "failed to enqueue", Valid: true},
+			})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to create comparison job"})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, apitypes.PosComparisonAcceptedResponse{
+			ComparisonID: comparisonID,
+			JobID:        jobID,
+			Status:       "queued",
+			Markets:      len(markets),
+		})
+	}
+}
+
+// GetPosComparison returns a handler which reports a point-of-sale comparison and its markets,
+// cheapest first
+func GetPosComparison(pgDB db.PostgresDB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		comparisonID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "Invalid comparison ID"})
+			return
+		}
+
+		comparison, err := pgDB.GetPosComparisonByID(c.Request.Context(), comparisonID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "Comparison not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get comparison"})
+			}
+			return
+		}
+
+		results, err := pgDB.ListPosComparisonResults(c.Request.Context(), comparisonID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: "Failed to get comparison results"})
+			return
+		}
+
+		markets := make([]apitypes.PosComparisonMarket, 0, len(results))
+		for _, r := range results {
+			market := apitypes.PosComparisonMarket{
+				Country:       r.Country,
+				Currency:      r.Currency,
+				AirlineCodes:  r.AirlineCodes,
+				FlightNumbers: r.FlightNumbers,
+				GoogleHost:    r.GoogleHost.String,
+				SearchURL:     r.SearchURL.String,
+				Error:         r.ErrorMessage.String,
+			}
+			if r.Price.Valid {
+				price, normalized := r.Price.Float64, r.NormalizedPrice.Float64
+				market.Price = &price
+				market.NormalizedPrice = &normalized
+			}
+			markets = append(markets, market)
+		}
+
+		response := apitypes.PosComparisonResponse{
+			ID:            comparison.ID,
+			Status:        comparison.Status,
+			Origin:        comparison.Origin,
+			Destination:   comparison.Destination,
+			DepartureDate: comparison.DepartureDate.Format("2006-01-02"),
+			TripType:      comparison.TripType,
+			Class:         comparison.Class,
+			Currency:      comparison.Currency,
+			MarketCount:   comparison.MarketCount,
+			CreatedAt:     comparison.CreatedAt,
+			Markets:       markets,
+			Error:         comparison.ErrorMessage.String,
+			CompletedAt:   maybeNullTime(comparison.CompletedAt),
+		}
+		if comparison.ReturnDate.Valid {
+			response.ReturnDate = comparison.ReturnDate.Time.Format("2006-01-02")
+		}
+		if comparison.CheapestCountry.Valid {
+			response.Cheapest = &apitypes.PosComparisonPrice{
+				Country: comparison.CheapestCountry.String,
+				Price:   comparison.CheapestPrice.Float64,
+			}
+			savings := comparison.Savings.Float64
+			response.Savings = &savings
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/test/mocks"
+	"github.com/gilby125/google-flights-api/worker"
+)
+
+func TestCreatePosComparison_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB := new(mocks.MockPostgresDB)
+	mockQueue := new(mocks.MockQueue)
+
+	router := gin.New()
+	router.POST("/pos-comparison", CreatePosComparison(mockQueue, mockDB))
+
+	reqBody := apitypes.PosComparisonRequest{
+		Origin:        "jfk",
+		Destination:   "lhr",
+		DepartureDate: apitypes.DateOnly{Time: time.Now().AddDate(0, 1, 0)},
+		Adults:        1,
+		TripType:      "one_way",
+		Class:         "economy",
+		Stops:         "any",
+		Currency:      "usd",
+		Markets: []apitypes.PosComparisonMarketRequest{
+			{Country: "us"},
+			{Country: "in", Currency: "inr", GoogleHost: "www.google.co.in"},
+			{Country: "US"},
+		},
+	}
+
+	mockDB.On("CreatePosComparison", mock.Anything, mock.MatchedBy(func(c db.PosComparison) bool {
+		return c.Origin == "JFK" && c.Destination == "LHR" && c.Currency == "USD" && c.MarketCount == 2
+	})).Return(42, nil).Once()
+
+	mockQueue.On("Enqueue", mock.Anything, "pos_comparison", mock.MatchedBy(func(payload interface{}) bool {
+		p, ok := payload.(worker.PosComparisonPayload)
+		if !ok || len(p.Markets) != 2 {
+			return false
+		}
+		return p.ComparisonID == 42 &&
+			p.Markets[0] == worker.PosComparisonMarket{Country: "US"} &&
+			p.Markets[1] == worker.PosComparisonMarket{Country: "IN", Currency: "INR", GoogleHost: "www.google.co.in"}
+	})).Return("job-1", nil).Once()
+
+	body, _ := json.Marshal(reqBody)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/pos-comparison", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	var resp map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, float64(42), resp["comparison_id"])
+	assert.Equal(t, "job-1", resp["job_id"])
+
+	mockDB.AssertExpectations(t)
+	mockQueue.AssertExpectations(t)
+}
+
+func TestCreatePosComparison_InvalidMarket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB := new(mocks.MockPostgresDB)
+	mockQueue := new(mocks.MockQueue)
+
+	router := gin.New()
+	router.POST("/pos-comparison", CreatePosComparison(mockQueue, mockDB))
+
+	reqBody := apitypes.PosComparisonRequest{
+		Origin:        "JFK",
+		Destination:   "LHR",
+		DepartureDate: apitypes.DateOnly{Time: time.Now().AddDate(0, 1, 0)},
+		Adults:        1,
+		TripType:      "one_way",
+		Class:         "economy",
+		Stops:         "any",
+		Currency:      "USD",
+		Markets: []apitypes.PosComparisonMarketRequest{
+			{Country: "US"},
+			{Country: "DE", GoogleHost: "evil.example.com"},
+		},
+	}
+
+	body, _ := json.Marshal(reqBody)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/pos-comparison", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockDB.AssertNotCalled(t, "CreatePosComparison", mock.Anything, mock.Anything)
+	mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 )
 
 const maxPriceGraphRangeDays = 161
@@ -18,70 +19,31 @@ type PriceGraphBuildParams struct {
 	TripLengthDays    int
 }
 
-func TopPriceGraphPoints(points []map[string]interface{}, n int) []map[string]interface{} {
+func TopPriceGraphPoints(points []apitypes.PriceGraphPoint, n int) []apitypes.PriceGraphPoint {
 	if n <= 0 || len(points) == 0 {
 		return nil
 	}
 
-	type scored struct {
-		point map[string]interface{}
-		price float64
-		ok    bool
-	}
-
-	scoredPoints := make([]scored, 0, len(points))
+	priced := make([]apitypes.PriceGraphPoint, 0, len(points))
 	for _, p := range points {
-		if p == nil {
-			continue
-		}
-		priceRaw, ok := p["price"]
-		if !ok {
-			continue
-		}
-		var price float64
-		switch v := priceRaw.(type) {
-		case float64:
-			price = v
-		case float32:
-			price = float64(v)
-		case int:
-			price = float64(v)
-		case int64:
-			price = float64(v)
-		case int32:
-			price = float64(v)
-		case uint:
-			price = float64(v)
-		case uint64:
-			price = float64(v)
-		case uint32:
-			price = float64(v)
-		default:
-			continue
-		}
-		if price <= 0 {
+		if p.Price <= 0 {
 			continue
 		}
-		scoredPoints = append(scoredPoints, scored{point: p, price: price, ok: true})
+		priced = append(priced, p)
 	}
 
-	if len(scoredPoints) == 0 {
+	if len(priced) == 0 {
 		return nil
 	}
 
-	sort.SliceStable(scoredPoints, func(i, j int) bool {
-		return scoredPoints[i].price < scoredPoints[j].price
+	sort.SliceStable(priced, func(i, j int) bool {
+		return priced[i].Price < priced[j].Price
 	})
 
-	if n > len(scoredPoints) {
-		n = len(scoredPoints)
-	}
-
-	out := make([]map[string]interface{}, 0, n)
-	for i := 0; i < n; i++ {
-		out = append(out, scoredPoints[i].point)
+	if n > len(priced) {
+		n = len(priced)
 	}
-	return out
+	return priced[:n]
 }
 
 func BuildPriceGraphArgs(now time.Time, origin, destination string, departureDate, returnDate time.Time, opts flights.Options, params PriceGraphBuildParams) (flights.PriceGraphArgs, error) {
@@ -174,41 +136,35 @@ func BuildPriceGraphArgs(now time.Time, origin, destination string, departureDat
 	}, nil
 }
 
-func SerializePriceGraphResponse(origin, destination, currency string, args flights.PriceGraphArgs, offers []flights.Offer, parseErrors *flights.ParseErrors, err error) map[string]interface{} {
-	out := map[string]interface{}{
-		"origin":      origin,
-		"destination": destination,
-		"currency":    currency,
+func SerializePriceGraphResponse(origin, destination, currency string, args flights.PriceGraphArgs, offers []flights.Offer, parseErrors *flights.ParseErrors, err error) *apitypes.PriceGraph {
+	out := &apitypes.PriceGraph{
+		Origin:         origin,
+		Destination:    destination,
+		Currency:       currency,
+		TripLengthDays: args.TripLength,
 	}
 
 	if !args.RangeStartDate.IsZero() {
-		out["range_start_date"] = args.RangeStartDate.Format(dateLayout)
+		out.RangeStartDate = args.RangeStartDate.Format(dateLayout)
 	}
 	if !args.RangeEndDate.IsZero() {
-		out["range_end_date"] = args.RangeEndDate.Format(dateLayout)
-	}
-	if args.TripLength > 0 {
-		out["trip_length_days"] = args.TripLength
+		out.RangeEndDate = args.RangeEndDate.Format(dateLayout)
 	}
 
 	if err != nil {
-		out["error"] = err.Error()
+		out.Error = err.Error()
 		return out
 	}
 
-	if parseErrors != nil {
-		out["parse_errors"] = parseErrors
-	}
+	out.ParseErrors = convertPriceGraphParseErrors(parseErrors)
 
-	points := make([]map[string]interface{}, 0, len(offers))
+	out.Points = make([]apitypes.PriceGraphPoint, 0, len(offers))
 	for _, offer := range offers {
-		point := map[string]interface{}{
-			"departure_date": offer.StartDate.Format(dateLayout),
-			"return_date":    offer.ReturnDate.Format(dateLayout),
-			"price":          offer.Price,
-		}
-		points = append(points, point)
+		out.Points = append(out.Points, apitypes.PriceGraphPoint{
+			DepartureDate: offer.StartDate.Format(dateLayout),
+			ReturnDate:    offer.ReturnDate.Format(dateLayout),
+			Price:         offer.Price,
+		})
 	}
-	out["points"] = points
 	return out
 }
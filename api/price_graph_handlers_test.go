@@ -17,6 +17,7 @@ import (
 
 	"github.com/gilby125/google-flights-api/config"
 	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 	"github.com/gilby125/google-flights-api/test/mocks"
 	"github.com/gilby125/google-flights-api/worker"
 )
@@ -36,11 +37,11 @@ func TestEnqueuePriceGraphSweepHandler_Success(t *testing.T) {
 	router := gin.New()
 	router.POST("/admin/price-graph-sweeps", enqueuePriceGraphSweep(mockDB, workerManager))
 
-	reqBody := PriceGraphSweepRequest{
+	reqBody := apitypes.PriceGraphSweepRequest{
 		Origins:           []string{"JFK"},
 		Destinations:      []string{"LAX"},
-		DepartureDateFrom: DateOnly{Time: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
-		DepartureDateTo:   DateOnly{Time: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)},
+		DepartureDateFrom: apitypes.DateOnly{Time: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		DepartureDateTo:   apitypes.DateOnly{Time: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)},
 		TripLengths:       []int{5, 7},
 		TripType:          "round_trip",
 		Class:             "business",
@@ -108,11 +109,11 @@ func TestEnqueuePriceGraphSweepHandler_InvalidDates(t *testing.T) {
 	router := gin.New()
 	router.POST("/admin/price-graph-sweeps", enqueuePriceGraphSweep(mockDB, workerManager))
 
-	reqBody := PriceGraphSweepRequest{
+	reqBody := apitypes.PriceGraphSweepRequest{
 		Origins:           []string{"JFK"},
 		Destinations:      []string{"LAX"},
-		DepartureDateFrom: DateOnly{Time: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)},
-		DepartureDateTo:   DateOnly{Time: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		DepartureDateFrom: apitypes.DateOnly{Time: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)},
+		DepartureDateTo:   apitypes.DateOnly{Time: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
 		TripType:          "round_trip",
 		Class:             "economy",
 		Stops:             "nonstop",
@@ -134,6 +135,62 @@ func TestEnqueuePriceGraphSweepHandler_InvalidDates(t *testing.T) {
 	mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything, mock.Anything)
 }
 
+func TestEnqueuePriceGraphSweepHandler_OpenJaw(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDB := new(mocks.MockPostgresDB)
+	mockQueue := new(mocks.MockQueue)
+	workerManager := newWorkerManagerForTests(mockQueue, mockDB)
+
+	router := gin.New()
+	router.POST("/admin/price-graph-sweeps", enqueuePriceGraphSweep(mockDB, workerManager))
+
+	reqBody := apitypes.PriceGraphSweepRequest{
+		Origins:           []string{"SFO"},
+		Destinations:      []string{"LHR"},
+		ReturnOrigins:     []string{"cdg", "AMS"},
+		DepartureDateFrom: apitypes.DateOnly{Time: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		DepartureDateTo:   apitypes.DateOnly{Time: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)},
+		TripLengths:       []int{10},
+		TripType:          "round_trip",
+		Class:             "economy",
+		Stops:             "any",
+		Adults:            1,
+		Currency:          "USD",
+	}
+
+	mockDB.On("CreatePriceGraphSweep", mock.Anything, sql.NullInt32{}, 1, 1,
+		sql.NullInt32{Int32: 10, Valid: true}, sql.NullInt32{Int32: 10, Valid: true}, "USD").
+		Return(7, nil).Once()
+	mockQueue.On("Enqueue", mock.Anything, "price_graph_sweep", mock.MatchedBy(func(payload interface{}) bool {
+		p, ok := payload.(worker.PriceGraphSweepPayload)
+		return ok && assert.ObjectsAreEqual([]string{"CDG", "AMS"}, p.ReturnOrigins) && len(p.ReturnDestinations) == 0
+	})).Return("job-id", nil).Once()
+	mockDB.On("GetPriceGraphSweepByID", mock.Anything, 7).Return(&db.PriceGraphSweep{ID: 7, Status: "queued"}, nil).Once()
+
+	body, _ := json.Marshal(reqBody)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/admin/price-graph-sweeps", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	mockDB.AssertExpectations(t)
+	mockQueue.AssertExpectations(t)
+
+	// Return destinations without return origins are rejected.
+	reqBody.ReturnOrigins = nil
+	reqBody.ReturnDestinations = []string{"JFK"}
+	body, _ = json.Marshal(reqBody)
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/admin/price-graph-sweeps", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "return_destinations requires return_origins")
+}
+
 func TestEnqueuePriceGraphSweepHandler_SchedulerError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -144,11 +201,11 @@ func TestEnqueuePriceGraphSweepHandler_SchedulerError(t *testing.T) {
 	router := gin.New()
 	router.POST("/admin/price-graph-sweeps", enqueuePriceGraphSweep(mockDB, workerManager))
 
-	reqBody := PriceGraphSweepRequest{
+	reqBody := apitypes.PriceGraphSweepRequest{
 		Origins:           []string{"JFK"},
 		Destinations:      []string{"LAX"},
-		DepartureDateFrom: DateOnly{Time: time.Now()},
-		DepartureDateTo:   DateOnly{Time: time.Now().AddDate(0, 0, 3)},
+		DepartureDateFrom: apitypes.DateOnly{Time: time.Now()},
+		DepartureDateTo:   apitypes.DateOnly{Time: time.Now().AddDate(0, 0, 3)},
 		TripType:          "round_trip",
 		Class:             "economy",
 		Stops:             "nonstop",
@@ -0,0 +1,451 @@
+package api
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/hotels"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/pkg/macros"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/gilby125/google-flights-api/worker"
+)
+
+// The response types of the endpoints are in pkg/apitypes, which the Go client shares. The
+// functions below convert the types of the server packages into them.
+
+// maybeNullInt returns the int value or nil if not valid
+func maybeNullInt(value sql.NullInt32) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int32)
+	return &v
+}
+
+// maybeNullInt64 returns the int64 value or nil if not valid
+func maybeNullInt64(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int64
+}
+
+// maybeNullTime returns the time value or nil if not valid
+func maybeNullTime(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+// maybeNullString returns the string value or nil if not valid
+func maybeNullString(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+// maybeNullFloat returns the float value or nil if not valid
+func maybeNullFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
+
+func convertBulkSearch(search *db.BulkSearch) apitypes.BulkSearchSummary {
+	return apitypes.BulkSearchSummary{
+		ID:           search.ID,
+		JobID:        maybeNullInt(search.JobID),
+		Status:       search.Status,
+		TotalRoutes:  search.TotalSearches,
+		Completed:    search.Completed,
+		TotalOffers:  search.TotalOffers,
+		ErrorCount:   search.ErrorCount,
+		Currency:     search.Currency,
+		CreatedAt:    search.CreatedAt,
+		UpdatedAt:    search.UpdatedAt,
+		CompletedAt:  maybeNullTime(search.CompletedAt),
+		MinPrice:     maybeNullFloat(search.MinPrice),
+		MaxPrice:     maybeNullFloat(search.MaxPrice),
+		AveragePrice: maybeNullFloat(search.AveragePrice),
+	}
+}
+
+func convertBulkSearchResult(result db.BulkSearchResult) apitypes.BulkSearchResultResponse {
+	return apitypes.BulkSearchResultResponse{
+		Origin:               result.Origin,
+		Destination:          result.Destination,
+		DepartureDate:        result.DepartureDate,
+		ReturnDate:           maybeNullTime(result.ReturnDate),
+		Price:                result.Price,
+		Currency:             result.Currency,
+		AirlineCode:          maybeNullString(result.AirlineCode),
+		Duration:             maybeNullInt(result.Duration),
+		SrcAirportCode:       result.SrcAirportCode.String,
+		DstAirportCode:       result.DstAirportCode.String,
+		SrcCity:              result.SrcCity.String,
+		DstCity:              result.DstCity.String,
+		FlightDuration:       maybeNullInt(result.FlightDuration),
+		ReturnFlightDuration: maybeNullInt(result.ReturnFlightDuration),
+		OutboundFlights:      result.OutboundFlights,
+		ReturnFlights:        result.ReturnFlights,
+		OfferJSON:            result.OfferJSON,
+		SegmentFlights:       result.SegmentFlights,
+	}
+}
+
+func convertPriceGraphSweep(sweep *db.PriceGraphSweep) apitypes.PriceGraphSweepSummary {
+	return apitypes.PriceGraphSweepSummary{
+		ID:               sweep.ID,
+		JobID:            maybeNullInt(sweep.JobID),
+		Status:           sweep.Status,
+		OriginCount:      sweep.OriginCount,
+		DestinationCount: sweep.DestinationCount,
+		TripLengthMin:    maybeNullInt(sweep.TripLengthMin),
+		TripLengthMax:    maybeNullInt(sweep.TripLengthMax),
+		Currency:         sweep.Currency,
+		ErrorCount:       sweep.ErrorCount,
+		CreatedAt:        sweep.CreatedAt,
+		UpdatedAt:        sweep.UpdatedAt,
+		StartedAt:        maybeNullTime(sweep.StartedAt),
+		CompletedAt:      maybeNullTime(sweep.CompletedAt),
+	}
+}
+
+func convertQueueJob(job *queue.Job) *apitypes.QueueJob {
+	if job == nil {
+		return nil
+	}
+	out := &apitypes.QueueJob{
+		ID:             job.ID,
+		Type:           job.Type,
+		Payload:        job.Payload,
+		CreatedAt:      job.CreatedAt,
+		Attempts:       job.Attempts,
+		MaxAttempts:    job.MaxAttempts,
+		Status:         job.Status,
+		StreamID:       job.StreamID,
+		Priority:       string(job.Priority),
+		Tenant:         job.Tenant,
+		RunAt:          job.RunAt,
+		IdempotencyKey: job.IdempotencyKey,
+	}
+	for _, failure := range job.Failures {
+		out.Failures = append(out.Failures, apitypes.QueueJobFailure{
+			Attempt:    failure.Attempt,
+			Error:      failure.Error,
+			ErrorClass: failure.ErrorClass,
+			Stack:      failure.Stack,
+			WorkerID:   failure.WorkerID,
+			FailedAt:   failure.FailedAt,
+		})
+	}
+	if meta := job.EnqueueMeta; meta != nil {
+		out.EnqueueMeta = &apitypes.EnqueueMeta{
+			Actor:     meta.Actor,
+			RequestID: meta.RequestID,
+			Method:    meta.Method,
+			Path:      meta.Path,
+			RemoteIP:  meta.RemoteIP,
+			UserAgent: meta.UserAgent,
+		}
+	}
+	return out
+}
+
+func convertQueueJobs(jobs []*queue.Job) []*apitypes.QueueJob {
+	out := make([]*apitypes.QueueJob, 0, len(jobs))
+	for _, job := range jobs {
+		out = append(out, convertQueueJob(job))
+	}
+	return out
+}
+
+func convertDeadLetter(dl *queue.DeadLetter) *apitypes.DeadLetter {
+	if dl == nil {
+		return nil
+	}
+	return &apitypes.DeadLetter{
+		EntryID:         dl.EntryID,
+		Queue:           dl.Queue,
+		Job:             convertQueueJob(dl.Job),
+		Error:           dl.Error,
+		ErrorClass:      dl.ErrorClass,
+		WorkerID:        dl.WorkerID,
+		DeadAt:          dl.DeadAt,
+		PayloadEditedAt: dl.PayloadEditedAt,
+	}
+}
+
+func convertDeadLetters(dls []*queue.DeadLetter) []*apitypes.DeadLetter {
+	out := make([]*apitypes.DeadLetter, 0, len(dls))
+	for _, dl := range dls {
+		out = append(out, convertDeadLetter(dl))
+	}
+	return out
+}
+
+func convertSweepStatus(status db.SweepStatusResponse) *apitypes.SweepStatus {
+	return &apitypes.SweepStatus{
+		IsRunning:           status.IsRunning,
+		IsPaused:            status.IsPaused,
+		SweepNumber:         status.SweepNumber,
+		RouteIndex:          status.RouteIndex,
+		TotalRoutes:         status.TotalRoutes,
+		ProgressPercent:     status.ProgressPercent,
+		CurrentOrigin:       status.CurrentOrigin,
+		CurrentDestination:  status.CurrentDestination,
+		Class:               status.Class,
+		Stops:               status.Stops,
+		TripLengths:         status.TripLengths,
+		Origins:             status.Origins,
+		QueriesCompleted:    status.QueriesCompleted,
+		ErrorsCount:         status.ErrorsCount,
+		LastError:           status.LastError,
+		SweepStartedAt:      status.SweepStartedAt,
+		EstimatedCompletion: status.EstimatedCompletion,
+		PacingMode:          status.PacingMode,
+		CurrentDelayMs:      status.CurrentDelayMs,
+		MinDelayMs:          status.MinDelayMs,
+		TargetDurationHours: status.TargetDurationHours,
+		QueriesPerHour:      status.QueriesPerHour,
+	}
+}
+
+// runnerStatus returns the status of the continuous sweep runner of the API process, or nil if
+// it has none.
+func runnerStatus(runner *worker.ContinuousSweepRunner) *apitypes.SweepStatus {
+	if runner == nil {
+		return nil
+	}
+	return convertSweepStatus(runner.GetStatus())
+}
+
+func convertSweepProgress(progress *db.ContinuousSweepProgress) *apitypes.ContinuousSweepProgress {
+	if progress == nil {
+		return nil
+	}
+	return &apitypes.ContinuousSweepProgress{
+		SweepNumber:         progress.SweepNumber,
+		RouteIndex:          progress.RouteIndex,
+		TotalRoutes:         progress.TotalRoutes,
+		CurrentOrigin:       progress.CurrentOrigin.String,
+		CurrentDestination:  progress.CurrentDestination.String,
+		QueriesCompleted:    progress.QueriesCompleted,
+		ErrorsCount:         progress.ErrorsCount,
+		LastError:           progress.LastError.String,
+		SweepStartedAt:      maybeNullTime(progress.SweepStartedAt),
+		LastUpdated:         progress.LastUpdated,
+		TripLengths:         progress.TripLengths,
+		PacingMode:          progress.PacingMode,
+		TargetDurationHours: progress.TargetDurationHours,
+		MinDelayMs:          progress.MinDelayMs,
+		IsRunning:           progress.IsRunning,
+		IsPaused:            progress.IsPaused,
+		InternationalOnly:   progress.InternationalOnly,
+		Origins:             progress.Origins,
+	}
+}
+
+func convertSweepControl(control *queue.ContinuousSweepControl) *apitypes.ContinuousSweepControl {
+	if control == nil {
+		return nil
+	}
+	return &apitypes.ContinuousSweepControl{
+		IsRunning:   control.IsRunning,
+		IsPaused:    control.IsPaused,
+		LastUpdated: control.LastUpdated,
+		Source:      control.Source,
+	}
+}
+
+func convertNearbyAirports(airports []macros.NearbyAirport) []apitypes.NearbyAirport {
+	if len(airports) == 0 {
+		return nil
+	}
+	out := make([]apitypes.NearbyAirport, 0, len(airports))
+	for _, airport := range airports {
+		out = append(out, apitypes.NearbyAirport{
+			Code:          airport.Code,
+			Anchor:        airport.Anchor,
+			DistanceMiles: airport.DistanceMiles,
+		})
+	}
+	return out
+}
+
+func convertSearchOfferSegments(segments []db.FlightSegment) []apitypes.SearchOfferSegment {
+	out := make([]apitypes.SearchOfferSegment, 0, len(segments))
+	for _, segment := range segments {
+		out = append(out, apitypes.SearchOfferSegment{
+			ID:               segment.ID,
+			FlightOfferID:    segment.FlightOfferID,
+			AirlineCode:      segment.AirlineCode,
+			FlightNumber:     segment.FlightNumber,
+			DepartureAirport: segment.DepartureAirport,
+			ArrivalAirport:   segment.ArrivalAirport,
+			DepartureTime:    segment.DepartureTime,
+			ArrivalTime:      segment.ArrivalTime,
+			Duration:         segment.Duration,
+			Airplane:         segment.Airplane,
+			Legroom:          segment.Legroom,
+			IsReturn:         segment.IsReturn,
+			SegmentIndex:     segment.SegmentIndex,
+		})
+	}
+	return out
+}
+
+func convertPaths(paths []db.PathResult) []apitypes.PathResult {
+	out := make([]apitypes.PathResult, 0, len(paths))
+	for _, path := range paths {
+		legs := make([]apitypes.LegInfo, 0, len(path.Legs))
+		for _, leg := range path.Legs {
+			legs = append(legs, apitypes.LegInfo{
+				Origin:      leg.Origin,
+				Destination: leg.Destination,
+				Price:       leg.Price,
+				Airline:     leg.Airline,
+			})
+		}
+		out = append(out, apitypes.PathResult{Stops: path.Stops, TotalPrice: path.TotalPrice, Legs: legs})
+	}
+	return out
+}
+
+func convertConnections(connections []db.Connection) []apitypes.Connection {
+	out := make([]apitypes.Connection, 0, len(connections))
+	for _, connection := range connections {
+		out = append(out, apitypes.Connection{
+			Airport:      connection.Airport,
+			Name:         connection.Name,
+			Country:      connection.Country,
+			CheapestPath: connection.CheapestPath,
+			Hops:         connection.Hops,
+		})
+	}
+	return out
+}
+
+func convertRouteStats(stats *db.RouteStats) *apitypes.RouteStats {
+	if stats == nil {
+		return nil
+	}
+	out := &apitypes.RouteStats{
+		Origin:             stats.Origin,
+		Destination:        stats.Destination,
+		MinPrice:           stats.MinPrice,
+		MaxPrice:           stats.MaxPrice,
+		AvgPrice:           stats.AvgPrice,
+		PricePoints:        stats.PricePoints,
+		Airlines:           stats.Airlines,
+		Source:             stats.Source,
+		Note:               stats.Note,
+		RouteEdges:         stats.RouteEdges,
+		MinPriceDate:       stats.MinPriceDate,
+		MinPriceAirline:    stats.MinPriceAirline,
+		MinPriceSeenAt:     stats.MinPriceSeenAt,
+		MinPriceTripType:   stats.MinPriceTripType,
+		MinPriceReturnDate: stats.MinPriceReturnDate,
+		MaxPriceDate:       stats.MaxPriceDate,
+		MaxPriceAirline:    stats.MaxPriceAirline,
+		MaxPriceSeenAt:     stats.MaxPriceSeenAt,
+		MaxPriceTripType:   stats.MaxPriceTripType,
+		MaxPriceReturnDate: stats.MaxPriceReturnDate,
+		FirstSeenAt:        stats.FirstSeenAt,
+		LastSeenAt:         stats.LastSeenAt,
+	}
+	for _, sample := range stats.Samples {
+		out.Samples = append(out.Samples, apitypes.RoutePricePoint{
+			Date:       sample.Date,
+			Price:      sample.Price,
+			Airline:    sample.Airline,
+			SeenAt:     sample.SeenAt,
+			TripType:   sample.TripType,
+			ReturnDate: sample.ReturnDate,
+		})
+	}
+	return out
+}
+
+func convertHotels(offers []hotels.Hotel) []apitypes.HotelOffer {
+	out := make([]apitypes.HotelOffer, 0, len(offers))
+	for _, hotel := range offers {
+		out = append(out, apitypes.HotelOffer{
+			Name:        hotel.Name,
+			Price:       hotel.Price,
+			Currency:    hotel.Currency,
+			Rating:      hotel.Rating,
+			Stars:       hotel.Stars,
+			Address:     hotel.Address,
+			Description: hotel.Description,
+			Images:      hotel.Images,
+			HotelID:     hotel.HotelID,
+			Latitude:    hotel.Latitude,
+			Longitude:   hotel.Longitude,
+		})
+	}
+	return out
+}
+
+func convertPriceRange(priceRange *flights.PriceRange) *apitypes.PriceRange {
+	if priceRange == nil {
+		return nil
+	}
+	return &apitypes.PriceRange{Low: priceRange.Low, High: priceRange.High}
+}
+
+func convertPriceGraphParseErrors(parseErrors *flights.ParseErrors) *apitypes.PriceGraphParseStats {
+	if parseErrors == nil {
+		return nil
+	}
+	return &apitypes.PriceGraphParseStats{
+		UnmarshalFailures: parseErrors.UnmarshalFailures,
+		DateParseFailures: parseErrors.DateParseFailures,
+		ZeroPriceCount:    parseErrors.ZeroPriceCount,
+		TotalOffersRaw:    parseErrors.TotalOffersRaw,
+		EmptySections:     parseErrors.EmptySections,
+		Samples:           parseErrors.Samples,
+	}
+}
+
+// convertLayovers returns nil for flights without connections, so the layovers are omitted.
+func convertLayovers(layovers []flights.Layover) []apitypes.Layover {
+	if len(layovers) == 0 {
+		return nil
+	}
+	out := make([]apitypes.Layover, 0, len(layovers))
+	for _, layover := range layovers {
+		out = append(out, apitypes.Layover{
+			ArrivalAirport:   layover.ArrAirportCode,
+			DepartureAirport: layover.DepAirportCode,
+			City:             layover.City,
+			Duration:         int(layover.Duration.Minutes()),
+			ChangeOfAirport:  layover.ChangeOfAirport,
+			Overnight:        layover.Overnight,
+			ShortConnection:  layover.ShortConnection,
+		})
+	}
+	return out
+}
+
+// managerSweepStatus returns the status of the sweep runner of workerManager, or nil if the
+// API process has none.
+func managerSweepStatus(workerManager *worker.Manager) *apitypes.SweepStatus {
+	if workerManager == nil {
+		return nil
+	}
+	return runnerStatus(workerManager.GetSweepRunner())
+}
+
+func persistResult(persistErrors []string) *apitypes.ContinuousSweepPersistError {
+	if len(persistErrors) == 0 {
+		return nil
+	}
+	return &apitypes.ContinuousSweepPersistError{Errors: persistErrors}
+}
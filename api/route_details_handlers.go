@@ -6,20 +6,21 @@ import (
 	"strings"
 
 	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 	"github.com/gin-gonic/gin"
 )
 
 func GetRouteDetails(neo4jDB db.Neo4jDatabase, defaultExcludeAirlines []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if neo4jDB == nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "neo4j is not configured"})
+			c.JSON(http.StatusServiceUnavailable, apitypes.ErrorResponse{Error: "neo4j is not configured"})
 			return
 		}
 
 		origin := strings.ToUpper(strings.TrimSpace(c.Query("origin")))
 		dest := strings.ToUpper(strings.TrimSpace(c.Query("dest")))
 		if origin == "" || dest == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "origin and dest query parameters are required"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "origin and dest query parameters are required"})
 			return
 		}
 
@@ -27,7 +28,7 @@ func GetRouteDetails(neo4jDB db.Neo4jDatabase, defaultExcludeAirlines []string)
 		dateTo := strings.TrimSpace(c.Query("dateTo"))
 		tripType := strings.ToLower(strings.TrimSpace(c.Query("tripType")))
 		if tripType != "" && tripType != "one_way" && tripType != "round_trip" && tripType != "unknown" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "tripType must be one of: one_way, round_trip, unknown"})
+			c.JSON(http.StatusBadRequest, apitypes.ErrorResponse{Error: "tripType must be one of: one_way, round_trip, unknown"})
 			return
 		}
 
@@ -204,23 +205,23 @@ func GetRouteDetails(neo4jDB db.Neo4jDatabase, defaultExcludeAirlines []string)
 			"limitSamples":    limitSamples,
 		})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 		defer result.Close()
 
 		if !result.Next() {
-			c.JSON(http.StatusNotFound, gin.H{"error": "no price data found for this route"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "no price data found for this route"})
 			return
 		}
 
 		rec := result.Record()
 		if rec == nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "no price data found for this route"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "no price data found for this route"})
 			return
 		}
 
-		stats := &db.RouteStats{}
+		stats := &apitypes.RouteStats{}
 		if v, ok := rec.Get("origin"); ok {
 			stats.Origin, _ = v.(string)
 		}
@@ -308,7 +309,7 @@ func GetRouteDetails(neo4jDB db.Neo4jDatabase, defaultExcludeAirlines []string)
 					if !ok {
 						continue
 					}
-					sample := db.RoutePricePoint{}
+					sample := apitypes.RoutePricePoint{}
 					if vv, ok := m["date"].(string); ok {
 						sample.Date = vv
 					}
@@ -333,7 +334,7 @@ func GetRouteDetails(neo4jDB db.Neo4jDatabase, defaultExcludeAirlines []string)
 		}
 
 		if err := result.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 			return
 		}
 
@@ -357,7 +358,7 @@ func GetRouteDetails(neo4jDB db.Neo4jDatabase, defaultExcludeAirlines []string)
 				"excludeAirlines": excludeAirlines,
 			})
 			if fbErr != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": fbErr.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: fbErr.Error()})
 				return
 			}
 			defer fallbackResult.Close()
@@ -371,7 +372,7 @@ func GetRouteDetails(neo4jDB db.Neo4jDatabase, defaultExcludeAirlines []string)
 					}
 				}
 				if routeEdgeCount <= 0 {
-					c.JSON(http.StatusNotFound, gin.H{"error": "no route data found for this origin/destination"})
+					c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "no route data found for this origin/destination"})
 					return
 				}
 
@@ -421,11 +422,11 @@ func GetRouteDetails(neo4jDB db.Neo4jDatabase, defaultExcludeAirlines []string)
 			}
 
 			if err := fallbackResult.Err(); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				c.JSON(http.StatusInternalServerError, apitypes.ErrorResponse{Error: err.Error()})
 				return
 			}
 
-			c.JSON(http.StatusNotFound, gin.H{"error": "no route data found for this origin/destination"})
+			c.JSON(http.StatusNotFound, apitypes.ErrorResponse{Error: "no route data found for this origin/destination"})
 			return
 		}
 
@@ -129,6 +129,8 @@ func splitAirportList(raw string) []string {
 			// ok
 		case macros.IsRegionToken(token):
 			// ok
+		case macros.IsNearToken(token):
+			// ok: validated when expanded
 		default:
 			if regionToken, ok := canonicalizeRegionToken(token); ok {
 				token = regionToken
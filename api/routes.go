@@ -8,7 +8,9 @@ import (
 
 	"github.com/gilby125/google-flights-api/config"
 	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/flights"
 	"github.com/gilby125/google-flights-api/hotels"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 	"github.com/gilby125/google-flights-api/pkg/cache"
 	"github.com/gilby125/google-flights-api/pkg/health"
 	"github.com/gilby125/google-flights-api/pkg/macros"
@@ -21,26 +23,37 @@ import (
 
 // RegisterRoutes registers all API routes
 func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Neo4jDB, queue queue.Queue, workerManager *worker.Manager, cfg *config.Config, hotelSession *hotels.Session) {
-	// Initialize cache manager
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisConfig.Host + ":" + cfg.RedisConfig.Port,
-		Password: cfg.RedisConfig.Password,
-		DB:       cfg.RedisConfig.DB,
-	})
-
-	redisCache := cache.NewRedisCache(redisClient, "flights_api")
-	cacheManager := cache.NewCacheManager(redisCache)
+	// Initialize cache manager; without Redis (Postgres queue backend) responses are cached in memory
+	var redisClient *redis.Client
+	var responseCache cache.Cache
+	if cfg.QueueBackend == config.QueueBackendPostgres {
+		responseCache = cache.NewMemoryCache(cache.DefaultMemoryCacheEntries)
+	} else {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisConfig.Host + ":" + cfg.RedisConfig.Port,
+			Password: cfg.RedisConfig.Password,
+			DB:       cfg.RedisConfig.DB,
+		})
+		responseCache = cache.NewRedisCache(redisClient, "flights_api")
+	}
+	cacheManager := cache.NewCacheManager(responseCache)
 
 	// Initialize health checker
 	healthChecker := health.NewHealthChecker("1.0.0")
 	healthChecker.AddChecker(&health.PostgresChecker{DB: postgresDB, Name: "postgres"})
 	healthChecker.AddChecker(&health.Neo4jChecker{DB: neo4jDB, Name: "neo4j"})
-	healthChecker.AddChecker(&health.RedisChecker{Client: redisClient, Name: "redis"})
+	if redisClient != nil {
+		healthChecker.AddChecker(&health.RedisChecker{Client: redisClient, Name: "redis"})
+	}
 	healthChecker.AddChecker(&health.QueueChecker{Queue: queue, Name: "queue"})
 	healthChecker.AddChecker(&health.WorkerChecker{Manager: workerManager, Name: "workers"})
 
+	// Google sessions of the direct (queue-less) searches
+	directSearchSessions := flights.NewSessionPool(cfg.FlightConfig.SessionPoolOptions())
+
 	// Setup middleware
 	router.Use(middleware.RequestID())
+	router.Use(middleware.Tenant(cfg.AdminAuthConfig, cfg.RedisConfig.QueueTenants))
 	router.Use(middleware.RequestLogger())
 	router.Use(middleware.Recovery())
 
@@ -48,7 +61,7 @@ func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Ne
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Idempotency-Key")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -56,6 +69,9 @@ func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Ne
 		c.Next()
 	})
 
+	// Reject JSON request bodies which don't match the OpenAPI document
+	router.Use(middleware.ValidateRequest(OpenAPISpec()))
+
 	// Health endpoints
 	router.GET("/health", func(c *gin.Context) {
 		report := healthChecker.CheckHealth(c.Request.Context())
@@ -80,6 +96,9 @@ func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Ne
 		c.JSON(http.StatusOK, report)
 	})
 
+	// OpenAPI document of the routes below
+	router.GET("/api/openapi.json", GetOpenAPISpec())
+
 	// Legacy API routes (for backward compatibility)
 	apiGroup := router.Group("/api")
 	apiGroup.Use(middleware.ResponseCache(cacheManager, middleware.CacheConfig{
@@ -90,9 +109,9 @@ func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Ne
 	}))
 	{
 		// Direct flight search (immediate results, bypasses queue)
-		apiGroup.POST("/search", DirectFlightSearch(postgresDB, neo4jDB))
+		apiGroup.POST("/search", DirectFlightSearch(postgresDB, neo4jDB, directSearchSessions))
 		apiGroup.GET("/search-test", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "Search test endpoint working"})
+			c.JSON(http.StatusOK, apitypes.MessageResponse{Message: "Search test endpoint working"})
 		})
 
 		// Cached endpoints for development
@@ -129,6 +148,12 @@ func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Ne
 		v1.GET("/search/:id", GetSearchByID(postgresDB))
 		v1.GET("/search", ListSearches(postgresDB))
 
+		// Flexible-date price grid (immediate results, cached)
+		v1.POST("/flex-dates", FlexDateSearch(cacheManager, directSearchSessions))
+
+		// ExploreAnywhere (POST /explore/anywhere) is not routed until the explore parser is backed
+		// by a recorded Google response; flights/testdata/explore.resp is hand-written.
+
 		// Hotel routes
 		hotelsGroup := v1.Group("/hotels")
 		{
@@ -139,6 +164,10 @@ func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Ne
 		v1.POST("/bulk-search", CreateBulkSearch(queue, postgresDB, workerManager))
 		v1.GET("/bulk-search/:id", getBulkSearchById(postgresDB))
 
+		// Point-of-sale price comparison
+		v1.POST("/pos-comparison", CreatePosComparison(queue, postgresDB))
+		v1.GET("/pos-comparison/:id", GetPosComparison(postgresDB))
+
 		// Price history routes
 		v1.GET("/price-history/:origin/:destination", getPriceHistory(neo4jDB))
 
@@ -163,9 +192,12 @@ func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Ne
 			admin.GET("/bulk-jobs/:id", getBulkSearchResults(postgresDB))
 			admin.POST("/bulk-jobs", createBulkJob(postgresDB, workerManager))
 			admin.GET("/bulk-jobs/:id/offers", getBulkSearchOffers(postgresDB))
+			admin.GET("/bulk-jobs/:id/export", exportBulkSearchResults(postgresDB))
+			admin.GET("/bulk-jobs/:id/offers/export", exportBulkSearchOffers(postgresDB))
 			admin.POST("/price-graph-sweeps", enqueuePriceGraphSweep(postgresDB, workerManager))
 			admin.GET("/price-graph-sweeps", listPriceGraphSweeps(postgresDB))
 			admin.GET("/price-graph-sweeps/:id", getPriceGraphSweepResults(postgresDB))
+			admin.GET("/price-graph-sweeps/:id/export", exportPriceGraphSweepResults(postgresDB))
 			admin.GET("/jobs/:id", getJobById(postgresDB))
 			admin.PUT("/jobs/:id", updateJob(postgresDB, workerManager))
 			admin.DELETE("/jobs/:id", DeleteJob(postgresDB, workerManager))
@@ -183,12 +215,17 @@ func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Ne
 			admin.GET("/queue/:name/jobs/:id", GetQueueJob(queue))
 			admin.POST("/queue/:name/jobs/:id/cancel", CancelQueueJob(queue))
 			admin.GET("/queue/:name/enqueues", GetQueueEnqueueMetrics(queue))
+			admin.GET("/rate-limits", GetRateLimitStats(cfg.RateLimitConfig))
 			admin.POST("/queue/:name/cancel-processing", CancelQueueProcessing(queue))
 			admin.POST("/queue/:name/drain", DrainQueue(queue))
 			admin.POST("/queue/:name/clear", ClearQueue(queue))
 			admin.POST("/queue/:name/clear-failed", ClearQueueFailed(queue))
 			admin.POST("/queue/:name/clear-processing", ClearQueueProcessing(queue))
 			admin.POST("/queue/:name/retry-failed", RetryQueueFailed(queue))
+			admin.GET("/queue/:name/dead-letters", ListDeadLetters(queue))
+			admin.POST("/queue/:name/dead-letters/replay", ReplayDeadLetters(queue))
+			admin.GET("/queue/:name/dead-letters/:id", GetDeadLetter(queue))
+			admin.PUT("/queue/:name/dead-letters/:id/payload", UpdateDeadLetterPayload(queue))
 
 			// Real-time events via Server-Sent Events
 			admin.GET("/events", GetAdminEvents(workerManager, redisClient, cfg.WorkerConfig))
@@ -199,14 +236,16 @@ func RegisterRoutes(router *gin.Engine, postgresDB db.PostgresDB, neo4jDB *db.Ne
 			admin.POST("/continuous-sweep/stop", stopContinuousSweep(workerManager, postgresDB))
 			admin.POST("/continuous-sweep/pause", pauseContinuousSweep(workerManager, postgresDB))
 			admin.POST("/continuous-sweep/resume", resumeContinuousSweep(workerManager, postgresDB))
-			admin.PUT("/continuous-sweep/config", updateContinuousSweepConfig(workerManager))
+			admin.PUT("/continuous-sweep/config", updateContinuousSweepConfig(workerManager, postgresDB))
 			admin.POST("/continuous-sweep/skip", skipCurrentRoute(workerManager))
 			admin.POST("/continuous-sweep/restart", restartCurrentSweep(workerManager))
 			admin.GET("/continuous-sweep/stats", getContinuousSweepStats(postgresDB))
 			admin.GET("/continuous-sweep/results", getContinuousSweepResults(postgresDB))
+			admin.GET("/continuous-sweep/results/export", exportContinuousSweepResults(postgresDB))
 
 			// Deal detection endpoints
 			admin.GET("/deals", listDeals(postgresDB))
+			admin.GET("/deals/export", exportDeals(postgresDB))
 			admin.GET("/deal-alerts", listDealAlerts(postgresDB))
 		}
 	}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/gilby125/google-flights-api/test/mocks"
+	"github.com/gilby125/google-flights-api/worker"
+)
+
+// TestRunJobCollapsesWithSchedulerTick runs a scheduled job from its cron tick and from the admin
+// "run now" endpoint, and expects a single bulk_search job.
+func TestRunJobCollapsesWithSchedulerTick(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(t)
+	host, port, _ := strings.Cut(mr.Addr(), ":")
+	q, err := queue.NewRedisQueue(config.RedisConfig{
+		Host:                   host,
+		Port:                   port,
+		QueueGroup:             "test_group",
+		QueueStreamPrefix:      "test_stream",
+		QueueBlockTimeout:      50 * time.Millisecond,
+		QueueVisibilityTimeout: 50 * time.Millisecond,
+		QueueDedupWindow:       time.Minute,
+	})
+	require.NoError(t, err)
+
+	const jobID = 7
+	mockDB := new(mocks.MockPostgresDB)
+	mockDB.On("GetJobDetailsByID", mock.Anything, jobID).Return(&db.JobDetails{
+		JobID:             jobID,
+		Origin:            "JFK",
+		Destination:       "LHR",
+		DynamicDates:      true,
+		DaysFromExecution: sql.NullInt32{Int32: 14, Valid: true},
+		SearchWindowDays:  sql.NullInt32{Int32: 3, Valid: true},
+		TripLength:        sql.NullInt32{Int32: 7, Valid: true},
+		Adults:            1,
+		TripType:          "round_trip",
+		Class:             "economy",
+		Stops:             "any",
+		Currency:          "usd",
+	}, nil)
+	mockDB.On("UpdateJobLastRun", mock.Anything, jobID).Return(nil)
+
+	rows := new(mocks.MockRows)
+	rows.On("Next").Return(true).Once()
+	rows.On("Next").Return(false)
+	rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*int) = jobID
+			*args.Get(1).(*string) = "daily JFK-LHR"
+			*args.Get(2).(*string) = "0 6 * * *"
+			*args.Get(3).(*bool) = true
+		}).
+		Return(nil)
+	rows.On("Close").Return(nil)
+	mockDB.On("ListJobs", mock.Anything).Return(rows, nil)
+
+	var tick func()
+	cronner := new(mocks.MockCronner)
+	cronner.On("Start").Return()
+	cronner.On("AddFunc", "0 6 * * *", mock.Anything).
+		Run(func(args mock.Arguments) { tick = args.Get(1).(func()) }).
+		Return(cron.EntryID(1), nil)
+
+	require.NoError(t, worker.NewScheduler(q, mockDB, cronner).Start())
+	require.NotNil(t, tick)
+	tick()
+
+	router := gin.New()
+	router.POST("/admin/jobs/:id/run", runJob(q, mockDB))
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/admin/jobs/7/run", nil)
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	var accepted apitypes.JobAcceptedResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &accepted))
+
+	jobs, err := q.ListJobs(context.Background(), "bulk_search", "pending", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1, "the tick and the run now should enqueue one bulk search")
+	require.Equal(t, jobs[0].ID, accepted.JobID)
+}
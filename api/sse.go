@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 	"github.com/gilby125/google-flights-api/pkg/worker_registry"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -154,13 +155,13 @@ func GetAdminEvents(workerManager WorkerStatusProvider, redisClient *redis.Clien
 					return
 				case <-ticker.C:
 					// Broadcast worker status
-					out := make([]workerStatusResponse, 0)
+					out := make([]apitypes.WorkerStatusResponse, 0)
 
 					// Local worker goroutines (in this process)
 					if workerManager != nil {
 						statuses := workerManager.WorkerStatuses()
 						for _, s := range statuses {
-							out = append(out, workerStatusResponse{
+							out = append(out, apitypes.WorkerStatusResponse{
 								ID:            s.ID,
 								Status:        s.Status,
 								CurrentJob:    s.CurrentJob,
@@ -218,14 +219,14 @@ func GetAdminEvents(workerManager WorkerStatusProvider, redisClient *redis.Clien
 }
 
 // Helper to get remote workers (extracted from GetWorkerStatus)
-func getRemoteWorkers(ctx context.Context, redisClient *redis.Client, namespace string, ttl time.Duration) ([]workerStatusResponse, error) {
+func getRemoteWorkers(ctx context.Context, redisClient *redis.Client, namespace string, ttl time.Duration) ([]apitypes.WorkerStatusResponse, error) {
 	reg := worker_registry.New(redisClient, namespace)
 	heartbeats, err := reg.ListActive(ctx, ttl, 200)
 	if err != nil {
 		return nil, err
 	}
 
-	out := make([]workerStatusResponse, 0, len(heartbeats))
+	out := make([]apitypes.WorkerStatusResponse, 0, len(heartbeats))
 	now := time.Now().UTC()
 
 	for _, hb := range heartbeats {
@@ -245,7 +246,7 @@ func getRemoteWorkers(ctx context.Context, redisClient *redis.Client, namespace
 			}
 		}
 
-		out = append(out, workerStatusResponse{
+		out = append(out, apitypes.WorkerStatusResponse{
 			ID:                  hb.ID,
 			Status:              hb.Status,
 			CurrentJob:          hb.CurrentJob,
@@ -0,0 +1,22 @@
+package api
+
+import "github.com/gilby125/google-flights-api/pkg/apitypes"
+
+// The request and response types below moved to pkg/apitypes, which the Go client shares. The
+// aliases keep the code that refers to them through package api compiling.
+
+type (
+	DateOnly                      = apitypes.DateOnly
+	SearchRequest                 = apitypes.SearchRequest
+	BulkSearchRequest             = apitypes.BulkSearchRequest
+	JobRequest                    = apitypes.JobRequest
+	BulkJobRequest                = apitypes.BulkJobRequest
+	PriceGraphSweepRequest        = apitypes.PriceGraphSweepRequest
+	DirectSearchRequest           = apitypes.DirectSearchRequest
+	SweepConfigRequest            = apitypes.SweepConfigRequest
+	HotelSearchRequest            = apitypes.HotelSearchRequest
+	ExploreEdge                   = apitypes.ExploreEdge
+	ExploreResponse               = apitypes.ExploreResponse
+	ContinuousSweepResultResponse = apitypes.ContinuousSweepResultResponse
+	ContinuousSweepStatsResponse  = apitypes.ContinuousSweepStatsResponse
+)
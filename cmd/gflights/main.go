@@ -0,0 +1,141 @@
+// Command gflights searches Google Flights from the command line and controls a running flights
+// server.
+//
+// The search commands call Google Flights directly through the flights package:
+//
+//	gflights search -from JFK -to LHR -date 2026-06-01 -return 2026-06-08
+//	gflights price-graph -from JFK -to LHR -start 2026-06-01 -end 2026-07-31 -format csv
+//
+// The server commands call the REST API of the server at -server (GFLIGHTS_SERVER, default
+// http://localhost:8080). Admin endpoints use -token (GFLIGHTS_TOKEN) or -user and -password
+// (GFLIGHTS_USER, GFLIGHTS_PASSWORD):
+//
+//	gflights bulk-search -from JFK,EWR -to REGION:EUROPE -date-from 2026-06-01 -date-to 2026-06-30
+//	gflights queue -watch 5s
+//	gflights dead-letters -class rate_limited bulk_search replay
+//	gflights sweep pause
+//	gflights deals -origin JFK -format json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// command is a subcommand of gflights.
+type command struct {
+	name    string
+	summary string
+	run     func(c *cli, ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{name: "search", summary: "search flights on Google Flights", run: (*cli).search},
+	{name: "price-graph", summary: "show the cheapest fares of a date range on Google Flights", run: (*cli).priceGraph},
+	{name: "bulk-search", summary: "enqueue a bulk search on the server", run: (*cli).bulkSearch},
+	{name: "queue", summary: "show (or watch) the job counters of the server queues", run: (*cli).queueStatus},
+	{name: "dead-letters", summary: "list or replay the jobs of a server queue that ran out of attempts", run: (*cli).deadLetters},
+	{name: "sweep", summary: "show or control the continuous sweep of the server", run: (*cli).sweep},
+	{name: "deals", summary: "list the deals detected by the server", run: (*cli).deals},
+}
+
+// errUsage is returned for invalid arguments whose error was already printed with the usage.
+var errUsage = errors.New("invalid usage")
+
+// cli holds the output streams of the commands.
+type cli struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := &cli{stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(c.run(ctx, os.Args[1:]))
+}
+
+// run executes the command of args and returns the exit code: 0 on success, 1 if the command
+// failed and 2 for invalid usage.
+func (c *cli) run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		c.usage()
+		return 2
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		c.usage()
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(c, ctx, args[1:])
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		default:
+			fmt.Fprintf(c.stderr, "gflights %s: %v\n", cmd.name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(c.stderr, "gflights: unknown command %q\n\n", args[0])
+	c.usage()
+	return 2
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.stderr, "Usage: gflights <command> [flags]")
+	fmt.Fprintln(c.stderr)
+	fmt.Fprintln(c.stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(c.stderr, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(c.stderr)
+	fmt.Fprintln(c.stderr, `Run "gflights <command> -h" for the flags of a command.`)
+}
+
+// newFlagSet creates the flag set of a command; usage is the synopsis of its arguments.
+func (c *cli) newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: gflights %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args, which must not have positional arguments beyond maxArgs.
+func (c *cli) parseFlags(fs *flag.FlagSet, args []string, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > maxArgs {
+		fmt.Fprintf(c.stderr, "unexpected argument %q\n", fs.Arg(maxArgs))
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// usageError prints message with the usage of fs.
+func (c *cli) usageError(fs *flag.FlagSet, format string, args ...interface{}) error {
+	fmt.Fprintf(c.stderr, format+"\n", args...)
+	fs.Usage()
+	return errUsage
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+)
+
+// runCLI runs gflights with args and returns its exit code, stdout and stderr.
+func runCLI(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	c := &cli{stdout: &stdout, stderr: &stderr}
+	code := c.run(context.Background(), args)
+	return code, stdout.String(), stderr.String()
+}
+
+func newTestServer(t *testing.T, mux *http.ServeMux) string {
+	t.Helper()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(value))
+}
+
+func TestRunUsage(t *testing.T) {
+	code, _, stderr := runCLI(t)
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "Commands:")
+
+	code, _, _ = runCLI(t, "help")
+	assert.Equal(t, 0, code)
+
+	code, _, stderr = runCLI(t, "fly")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "fly"`)
+
+	code, _, stderr = runCLI(t, "search", "-h")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stderr, "Usage: gflights search")
+
+	tests := [][]string{
+		{"search", "-from", "JFK", "-to", "LHR"},
+		{"search", "-from", "JFK", "-to", "LHR", "-date", "06/01/2026"},
+		{"search", "-from", "JFK", "-to", "LHR", "-date", "2026-06-08", "-return", "2026-06-01"},
+		{"search", "-from", "JFK", "-to", "LONDON", "-date", "2026-06-01"},
+		{"search", "-from", "JFK", "-to", "LHR", "-date", "2026-06-01", "-class", "coach"},
+		{"search", "-from", "JFK", "-to", "LHR", "-date", "2026-06-01", "-format", "xml"},
+		{"price-graph", "-from", "JFK", "-to", "LHR", "-start", "2026-06-30", "-end", "2026-06-01"},
+		{"bulk-search", "-from", "JFK", "-date-from", "2026-06-01", "-date-to", "2026-06-30"},
+		{"sweep", "launch"},
+		{"queue", "extra"},
+	}
+	for _, args := range tests {
+		code, _, stderr := runCLI(t, args...)
+		assert.Equal(t, 2, code, "%v: %s", args, stderr)
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	tbl := table{header: []string{"ROUTE", "PRICE"}}
+	tbl.add("JFK-LHR", formatPrice(412))
+	tbl.add("JFK-CDG, ORY", formatPrice(0))
+
+	var buf bytes.Buffer
+	require.NoError(t, writeOutput(&buf, formatTable, nil, tbl))
+	assert.Equal(t, "ROUTE         PRICE\nJFK-LHR       412.00\nJFK-CDG, ORY  \n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeOutput(&buf, formatCSV, nil, tbl))
+	assert.Equal(t, "ROUTE,PRICE\nJFK-LHR,412.00\n\"JFK-CDG, ORY\",\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeOutput(&buf, formatJSON, map[string]int{"count": 2}, tbl))
+	assert.JSONEq(t, `{"count":2}`, buf.String())
+
+	assert.Error(t, writeOutput(&buf, "xml", nil, tbl))
+}
+
+func TestDeals(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/admin/deals", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			writeJSON(t, w, http.StatusUnauthorized, apitypes.ErrorResponse{Error: "unauthorized"})
+			return
+		}
+		assert.Equal(t, "JFK", r.URL.Query().Get("origin"))
+		assert.Equal(t, "10", r.URL.Query().Get("limit"))
+		discount, score, classification := 42.5, 87, "great"
+		writeJSON(t, w, http.StatusOK, apitypes.DealListResponse{
+			Deals: []apitypes.DealResponse{{
+				ID:                 7,
+				Origin:             "JFK",
+				Destination:        "LHR",
+				DepartureDate:      "2026-06-01",
+				Price:              299,
+				Currency:           "USD",
+				DiscountPercent:    &discount,
+				DealScore:          &score,
+				DealClassification: &classification,
+				CabinClass:         "economy",
+				Status:             "active",
+				TimesSeen:          3,
+			}},
+			Count: 1,
+		})
+	})
+	server := newTestServer(t, mux)
+
+	code, stdout, stderr := runCLI(t, "deals", "-server", server, "-token", "secret", "-origin", "jfk", "-limit", "10")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "CLASSIFICATION")
+	assert.Regexp(t, `7\s+JFK-LHR\s+2026-06-01\s+299.00\s+USD\s+42%\s+87\s+great\s+economy\s+active\s+3`, stdout)
+
+	code, stdout, stderr = runCLI(t, "deals", "-server", server, "-token", "secret", "-origin", "JFK", "-limit", "10", "-format", "json")
+	require.Equal(t, 0, code, stderr)
+	var resp apitypes.DealListResponse
+	require.NoError(t, json.Unmarshal([]byte(stdout), &resp))
+	assert.Equal(t, 1, resp.Count)
+
+	code, _, stderr = runCLI(t, "deals", "-server", server, "-origin", "JFK", "-limit", "10", "-token", "wrong")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "gflights deals:")
+	assert.Contains(t, stderr, "unauthorized")
+}
+
+func TestQueueAndSweep(t *testing.T) {
+	var actions []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/admin/queue", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusOK, map[string]map[string]int64{
+			"flight_search": {"pending": 4, "processing": 1},
+			"bulk_search":   {"pending": 0, "failed": 2},
+		})
+	})
+	mux.HandleFunc("/api/v1/admin/continuous-sweep/status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusOK, map[string]interface{}{
+			"initialized": true,
+			"server_time": "2026-06-01T12:00:00Z",
+			"status": map[string]interface{}{
+				"is_running": true, "is_paused": true, "sweep_number": 3, "route_index": 25,
+				"total_routes": 100, "progress_percent": 25.0, "pacing_mode": "adaptive",
+			},
+			"queues": map[string]interface{}{"continuous_sweep": map[string]int64{"pending": 9}},
+		})
+	})
+	mux.HandleFunc("/api/v1/admin/continuous-sweep/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		actions = append(actions, r.URL.Path)
+		writeJSON(t, w, http.StatusOK, map[string]interface{}{"message": "Continuous sweep paused", "status": nil})
+	})
+	server := newTestServer(t, mux)
+
+	code, stdout, stderr := runCLI(t, "queue", "-server", server)
+	require.Equal(t, 0, code, stderr)
+	lines := bytes.Split(bytes.TrimSpace([]byte(stdout)), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^QUEUE\s+FAILED\s+PENDING\s+PROCESSING$`, string(lines[0]))
+	assert.Regexp(t, `^bulk_search\s+2\s+0\s+0$`, string(lines[1]))
+	assert.Regexp(t, `^flight_search\s+0\s+4\s+1$`, string(lines[2]))
+
+	code, stdout, stderr = runCLI(t, "queue", "-server", server, "-format", "csv")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "QUEUE,FAILED,PENDING,PROCESSING\nbulk_search,2,0,0\nflight_search,0,4,1\n", stdout)
+
+	code, stdout, stderr = runCLI(t, "sweep", "-server", server)
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `state\s+paused`, stdout)
+	assert.Regexp(t, `progress\s+25/100 routes \(25.0%\)`, stdout)
+	assert.Regexp(t, `continuous_sweep\s+0\s+0\s+9\s+0`, stdout)
+
+	code, stdout, stderr = runCLI(t, "sweep", "-server", server, "pause")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "Continuous sweep paused\n", stdout)
+	assert.Equal(t, []string{"/api/v1/admin/continuous-sweep/pause"}, actions)
+}
+
+func TestBulkSearch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/bulk-search", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, []interface{}{"JFK", "EWR"}, req["origins"])
+		assert.Equal(t, []interface{}{"REGION:EUROPE"}, req["destinations"])
+		assert.Equal(t, "2026-06-01", req["departure_date_from"])
+		assert.Nil(t, req["return_date_from"])
+		assert.Equal(t, "round_trip", req["trip_type"])
+		assert.Equal(t, "EUR", req["currency"])
+		assert.Equal(t, 7.0, req["trip_length"])
+		writeJSON(t, w, http.StatusAccepted, apitypes.BulkSearchAcceptedResponse{
+			JobID:        "job-1",
+			BulkSearchID: 12,
+			Message:      "Bulk search started",
+			Warnings:     []string{"expanded REGION:EUROPE"},
+		})
+	})
+	server := newTestServer(t, mux)
+
+	code, stdout, stderr := runCLI(t, "bulk-search", "-server", server, "-from", "JFK, EWR", "-to", "REGION:EUROPE",
+		"-date-from", "2026-06-01", "-date-to", "2026-06-30", "-trip-type", "round_trip", "-trip-length", "7", "-currency", "eur")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "bulk search: 12")
+	assert.Contains(t, stdout, "job:         job-1")
+	assert.Contains(t, stderr, "warning: expanded REGION:EUROPE")
+}
+
+func TestDeadLetters(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/admin/queue/bulk_search/dead-letters", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "timeout", r.URL.Query().Get("error_class"))
+		writeJSON(t, w, http.StatusOK, map[string]interface{}{
+			"queue": "bulk_search", "error_class": "timeout", "limit": 50, "count": 1,
+			"dead_letters": []map[string]interface{}{{
+				"queue": "bulk_search", "error": "context deadline exceeded", "error_class": "timeout",
+				"worker_id": "worker-1", "dead_at": "2026-06-01T12:00:00Z",
+				"job": map[string]interface{}{"id": "bulk_search-1", "type": "bulk_search", "attempts": 3},
+			}},
+		})
+	})
+	mux.HandleFunc("/api/v1/admin/queue/bulk_search/dead-letters/replay", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var req apitypes.DeadLetterReplayRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []string{"bulk_search-1", "bulk_search-2"}, req.JobIDs)
+		writeJSON(t, w, http.StatusOK, apitypes.QueueDeadLetterReplayResponse{Queue: "bulk_search", Replayed: req.JobIDs, Count: 2})
+	})
+	server := newTestServer(t, mux)
+
+	code, stdout, stderr := runCLI(t, "dead-letters", "-server", server, "-class", "timeout", "bulk_search")
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `^JOB\s+CLASS\s+ATTEMPTS\s+WORKER\s+DEAD\s+ERROR\n`, stdout)
+	assert.Regexp(t, `bulk_search-1\s+timeout\s+3\s+worker-1\s+2026-06-01T12:00:00Z\s+context deadline exceeded`, stdout)
+
+	code, stdout, stderr = runCLI(t, "dead-letters", "-server", server, "-jobs", "bulk_search-1, bulk_search-2", "bulk_search", "replay")
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "replayed 2 jobs of bulk_search\n", stdout)
+
+	code, _, stderr = runCLI(t, "dead-letters", "-server", server, "bulk_search", "replay")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "replay needs -jobs or -class")
+}
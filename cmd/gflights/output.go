@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Output formats of the -format flag.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// table is the tabular output of a command.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+// addFormatFlag adds the -format flag to fs.
+func addFormatFlag(fs *flag.FlagSet) *string {
+	return fs.String("format", formatTable, "output format: table, json or csv")
+}
+
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (want table, json or csv)", format)
+	}
+}
+
+// writeOutput writes value as indented JSON for the json format, and t otherwise.
+func writeOutput(w io.Writer, format string, value interface{}, t table) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case formatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(t.header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(t.rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	case formatTable:
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	default:
+		return checkFormat(format)
+	}
+}
+
+func formatPrice(price float64) string {
+	if price <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+// formatDuration renders durations as hours and minutes, e.g. 7h35m.
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/macros"
+	"golang.org/x/text/currency"
+)
+
+const dateLayout = "2006-01-02"
+
+// maxAirportsPerSide caps the airports of one side of a search, as Google Flights only accepts
+// a handful of airports per field.
+const maxAirportsPerSide = 7
+
+type flightOutput struct {
+	FlightNumber string `json:"flight_number"`
+	Airline      string `json:"airline"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Departure    string `json:"departure"`
+	Arrival      string `json:"arrival"`
+	Duration     string `json:"duration"`
+}
+
+type offerOutput struct {
+	Price         float64        `json:"price"`
+	Currency      string         `json:"currency"`
+	DepartureDate string         `json:"departure_date"`
+	ReturnDate    string         `json:"return_date,omitempty"`
+	Duration      string         `json:"duration"`
+	Stops         int            `json:"stops"`
+	Airlines      []string       `json:"airlines"`
+	Flights       []flightOutput `json:"flights"`
+}
+
+type searchOutput struct {
+	Origins      []string            `json:"origins"`
+	Destinations []string            `json:"destinations"`
+	SearchURL    string              `json:"search_url,omitempty"`
+	PriceRange   *flights.PriceRange `json:"price_range,omitempty"`
+	Offers       []offerOutput       `json:"offers"`
+}
+
+type priceGraphOutput struct {
+	DepartureDate string  `json:"departure_date"`
+	ReturnDate    string  `json:"return_date,omitempty"`
+	Price         float64 `json:"price"`
+	Currency      string  `json:"currency"`
+}
+
+// searchFlags are the flags shared by the search commands.
+type searchFlags struct {
+	from, to   string
+	adults     int
+	children   int
+	class      string
+	stops      string
+	currency   string
+	carriers   string
+	country    string
+	googleHost string
+	format     *string
+}
+
+func addSearchFlags(fs *flag.FlagSet, f *searchFlags) {
+	fs.StringVar(&f.from, "from", "", "origin airports: comma-separated IATA codes, REGION:* or NEAR:<IATA>:<miles> tokens (required)")
+	fs.StringVar(&f.to, "to", "", "destination airports, like -from (required)")
+	fs.IntVar(&f.adults, "adults", 1, "number of adults")
+	fs.IntVar(&f.children, "children", 0, "number of children")
+	fs.StringVar(&f.class, "class", "economy", "cabin class: economy, premium_economy, business or first")
+	fs.StringVar(&f.stops, "stops", "any", "maximum stops: nonstop, one_stop, two_stops or any")
+	fs.StringVar(&f.currency, "currency", "USD", "ISO 4217 currency of the prices")
+	fs.StringVar(&f.carriers, "carriers", "", "comma-separated airline codes or alliances (STAR_ALLIANCE, ONEWORLD, SKYTEAM) to search")
+	fs.StringVar(&f.country, "gl", "", "point-of-sale country, e.g. DE; empty means US")
+	fs.StringVar(&f.googleHost, "google-host", "", "Google domain to query, e.g. www.google.de")
+	f.format = addFormatFlag(fs)
+}
+
+// options returns the flights options and airports of the flags.
+func (f *searchFlags) options() (flights.Options, []string, []string, error) {
+	options := flights.OptionsDefault()
+	if err := checkFormat(*f.format); err != nil {
+		return options, nil, nil, err
+	}
+	if f.adults < 1 || f.children < 0 {
+		return options, nil, nil, fmt.Errorf("-adults must be at least 1 and -children at least 0")
+	}
+	options.Travelers = flights.Travelers{Adults: f.adults, Children: f.children}
+
+	class, err := parseClass(f.class)
+	if err != nil {
+		return options, nil, nil, err
+	}
+	options.Class = class
+	stops, err := parseStops(f.stops)
+	if err != nil {
+		return options, nil, nil, err
+	}
+	options.Stops = stops
+	unit, err := currency.ParseISO(strings.ToUpper(f.currency))
+	if err != nil {
+		return options, nil, nil, fmt.Errorf("invalid currency %q", f.currency)
+	}
+	options.Currency = unit
+	options.Carriers = splitList(f.carriers)
+	options.Country = f.country
+	options.GoogleHost = f.googleHost
+	if err := options.ValidateMarket(); err != nil {
+		return options, nil, nil, err
+	}
+
+	origins, err := expandAirports("-from", f.from)
+	if err != nil {
+		return options, nil, nil, err
+	}
+	destinations, err := expandAirports("-to", f.to)
+	if err != nil {
+		return options, nil, nil, err
+	}
+	return options, origins, destinations, nil
+}
+
+func (c *cli) search(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("search", "-from <airports> -to <airports> -date <YYYY-MM-DD> [flags]")
+	var f searchFlags
+	addSearchFlags(fs, &f)
+	date := fs.String("date", "", "departure date, YYYY-MM-DD (required)")
+	returnDate := fs.String("return", "", "return date of a round trip, YYYY-MM-DD")
+	limit := fs.Int("limit", 20, "maximum offers to show, cheapest first (0 for all)")
+	if err := c.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	options, origins, destinations, err := f.options()
+	if err != nil {
+		return c.usageError(fs, "%v", err)
+	}
+	departure, err := parseDate("-date", *date, true)
+	if err != nil {
+		return c.usageError(fs, "%v", err)
+	}
+	ret, err := parseDate("-return", *returnDate, false)
+	if err != nil {
+		return c.usageError(fs, "%v", err)
+	}
+	options.TripType = flights.OneWay
+	if !ret.IsZero() {
+		if ret.Before(departure) {
+			return c.usageError(fs, "-return must not be before -date")
+		}
+		options.TripType = flights.RoundTrip
+	}
+
+	session, err := flights.New()
+	if err != nil {
+		return fmt.Errorf("failed to create flights session: %w", err)
+	}
+	searchArgs := flights.Args{
+		Date:        departure,
+		ReturnDate:  ret,
+		SrcAirports: origins,
+		DstAirports: destinations,
+		Options:     options,
+	}
+	offers, priceRange, err := session.GetOffers(ctx, searchArgs)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	searchURL, err := session.SerializeURL(ctx, searchArgs)
+	if err != nil {
+		searchURL = ""
+	}
+
+	// cheapest first; offers without a price last
+	sort.SliceStable(offers, func(i, j int) bool {
+		if (offers[i].Price > 0) != (offers[j].Price > 0) {
+			return offers[i].Price > 0
+		}
+		return offers[i].Price < offers[j].Price
+	})
+	if *limit > 0 && len(offers) > *limit {
+		offers = offers[:*limit]
+	}
+
+	out := searchOutput{
+		Origins:      origins,
+		Destinations: destinations,
+		SearchURL:    searchURL,
+		PriceRange:   priceRange,
+		Offers:       make([]offerOutput, 0, len(offers)),
+	}
+	t := table{header: []string{"PRICE", "CURRENCY", "DEPART", "RETURN", "DURATION", "STOPS", "ROUTE", "FLIGHTS", "AIRLINES"}}
+	currencyCode := options.Currency.String()
+	for _, offer := range offers {
+		o := newOfferOutput(offer, currencyCode)
+		out.Offers = append(out.Offers, o)
+
+		route := make([]string, 0, len(o.Flights)+1)
+		numbers := make([]string, 0, len(o.Flights))
+		for i, flight := range o.Flights {
+			if i == 0 {
+				route = append(route, flight.From)
+			}
+			route = append(route, flight.To)
+			numbers = append(numbers, flight.FlightNumber)
+		}
+		t.add(formatPrice(o.Price), currencyCode, o.DepartureDate, o.ReturnDate, o.Duration, fmt.Sprint(o.Stops),
+			strings.Join(route, "-"), strings.Join(numbers, " "), strings.Join(o.Airlines, ", "))
+	}
+
+	if err := writeOutput(c.stdout, *f.format, out, t); err != nil {
+		return err
+	}
+	if *f.format == formatTable && searchURL != "" {
+		fmt.Fprintf(c.stderr, "\nGoogle Flights: %s\n", searchURL)
+	}
+	return nil
+}
+
+func (c *cli) priceGraph(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("price-graph", "-from <airports> -to <airports> -start <YYYY-MM-DD> -end <YYYY-MM-DD> [flags]")
+	var f searchFlags
+	addSearchFlags(fs, &f)
+	start := fs.String("start", "", "first departure date, YYYY-MM-DD (required)")
+	end := fs.String("end", "", "last departure date, YYYY-MM-DD (required)")
+	tripLength := fs.Int("trip-length", 7, "days between departure and return of round trips")
+	oneWay := fs.Bool("one-way", false, "search one-way fares instead of round trips")
+	sortByPrice := fs.Bool("sort-price", false, "sort by price instead of departure date")
+	if err := c.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	options, origins, destinations, err := f.options()
+	if err != nil {
+		return c.usageError(fs, "%v", err)
+	}
+	rangeStart, err := parseDate("-start", *start, true)
+	if err != nil {
+		return c.usageError(fs, "%v", err)
+	}
+	rangeEnd, err := parseDate("-end", *end, true)
+	if err != nil {
+		return c.usageError(fs, "%v", err)
+	}
+	if rangeEnd.Before(rangeStart) {
+		return c.usageError(fs, "-end must not be before -start")
+	}
+	options.TripType = flights.RoundTrip
+	if *oneWay {
+		options.TripType = flights.OneWay
+	} else if *tripLength < 1 {
+		return c.usageError(fs, "-trip-length must be at least 1")
+	}
+
+	session, err := flights.New()
+	if err != nil {
+		return fmt.Errorf("failed to create flights session: %w", err)
+	}
+	offers, _, err := session.GetPriceGraph(ctx, flights.PriceGraphArgs{
+		RangeStartDate: rangeStart,
+		RangeEndDate:   rangeEnd,
+		TripLength:     *tripLength,
+		SrcAirports:    origins,
+		DstAirports:    destinations,
+		Options:        options,
+	})
+	if err != nil {
+		return fmt.Errorf("price graph failed: %w", err)
+	}
+
+	if *sortByPrice {
+		sort.SliceStable(offers, func(i, j int) bool { return offers[i].Price < offers[j].Price })
+	} else {
+		sort.SliceStable(offers, func(i, j int) bool { return offers[i].StartDate.Before(offers[j].StartDate) })
+	}
+
+	currencyCode := options.Currency.String()
+	out := make([]priceGraphOutput, 0, len(offers))
+	t := table{header: []string{"DEPART", "RETURN", "PRICE", "CURRENCY"}}
+	for _, offer := range offers {
+		o := priceGraphOutput{
+			DepartureDate: formatDate(offer.StartDate),
+			Price:         offer.Price,
+			Currency:      currencyCode,
+		}
+		if options.TripType == flights.RoundTrip {
+			o.ReturnDate = formatDate(offer.ReturnDate)
+		}
+		out = append(out, o)
+		t.add(o.DepartureDate, o.ReturnDate, formatPrice(o.Price), currencyCode)
+	}
+	return writeOutput(c.stdout, *f.format, out, t)
+}
+
+func newOfferOutput(offer flights.FullOffer, currencyCode string) offerOutput {
+	o := offerOutput{
+		Price:         offer.Price,
+		Currency:      currencyCode,
+		DepartureDate: formatDate(offer.StartDate),
+		ReturnDate:    formatDate(offer.ReturnDate),
+		Duration:      formatDuration(offer.FlightDuration),
+		Flights:       make([]flightOutput, 0, len(offer.Flight)),
+	}
+	if len(offer.Flight) > 0 {
+		o.Stops = len(offer.Flight) - 1
+	}
+
+	seen := map[string]bool{}
+	for _, flight := range offer.Flight {
+		o.Flights = append(o.Flights, flightOutput{
+			FlightNumber: flight.FlightNumber,
+			Airline:      flight.AirlineName,
+			From:         flight.DepAirportCode,
+			To:           flight.ArrAirportCode,
+			Departure:    flight.DepTime.Format("2006-01-02 15:04"),
+			Arrival:      flight.ArrTime.Format("2006-01-02 15:04"),
+			Duration:     formatDuration(flight.Duration),
+		})
+		if flight.AirlineName != "" && !seen[flight.AirlineName] {
+			seen[flight.AirlineName] = true
+			o.Airlines = append(o.Airlines, flight.AirlineName)
+		}
+	}
+	return o
+}
+
+func parseClass(class string) (flights.Class, error) {
+	switch strings.ToLower(class) {
+	case "economy":
+		return flights.Economy, nil
+	case "premium_economy":
+		return flights.PremiumEconomy, nil
+	case "business":
+		return flights.Business, nil
+	case "first":
+		return flights.First, nil
+	default:
+		return flights.Economy, fmt.Errorf("unknown class %q", class)
+	}
+}
+
+func parseStops(stops string) (flights.Stops, error) {
+	switch strings.ToLower(stops) {
+	case "nonstop":
+		return flights.Nonstop, nil
+	case "one_stop":
+		return flights.Stop1, nil
+	case "two_stops":
+		return flights.Stop2, nil
+	case "any":
+		return flights.AnyStops, nil
+	default:
+		return flights.AnyStops, fmt.Errorf("unknown stops %q", stops)
+	}
+}
+
+// parseDate parses a YYYY-MM-DD flag value; an empty value is the zero time unless required.
+func parseDate(name, value string, required bool) (time.Time, error) {
+	if value == "" {
+		if required {
+			return time.Time{}, fmt.Errorf("%s is required", name)
+		}
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: want YYYY-MM-DD", name, value)
+	}
+	return date, nil
+}
+
+// expandAirports expands the airport codes and REGION:/NEAR: tokens of a flag.
+func expandAirports(name, value string) ([]string, error) {
+	tokens := splitList(value)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%s is required", name)
+	}
+	airports, _, err := macros.ExpandAirportTokens(tokens)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	if len(airports) > maxAirportsPerSide {
+		return nil, fmt.Errorf("%s expands to %d airports; a search takes at most %d", name, len(airports), maxAirportsPerSide)
+	}
+	return airports, nil
+}
+
+// splitList splits a comma-separated flag value and drops empty entries.
+func splitList(value string) []string {
+	var out []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
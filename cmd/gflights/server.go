@@ -0,0 +1,432 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/pkg/client"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// serverFlags are the flags of the commands which call the REST API of a server.
+type serverFlags struct {
+	server   string
+	token    string
+	user     string
+	password string
+}
+
+func addServerFlags(fs *flag.FlagSet, f *serverFlags) {
+	server := os.Getenv("GFLIGHTS_SERVER")
+	if server == "" {
+		server = defaultServer
+	}
+	fs.StringVar(&f.server, "server", server, "address of the flights server (GFLIGHTS_SERVER)")
+	fs.StringVar(&f.token, "token", os.Getenv("GFLIGHTS_TOKEN"), "bearer token of the admin endpoints (GFLIGHTS_TOKEN)")
+	fs.StringVar(&f.user, "user", os.Getenv("GFLIGHTS_USER"), "basic auth user of the admin endpoints (GFLIGHTS_USER)")
+	fs.StringVar(&f.password, "password", os.Getenv("GFLIGHTS_PASSWORD"), "basic auth password of the admin endpoints (GFLIGHTS_PASSWORD)")
+}
+
+func (f *serverFlags) client() (*client.Client, error) {
+	return client.New(client.Config{
+		BaseURL:   f.server,
+		Token:     f.token,
+		Username:  f.user,
+		Password:  f.password,
+		UserAgent: "gflights",
+	})
+}
+
+func (c *cli) bulkSearch(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("bulk-search", "-from <airports> -to <airports> -date-from <YYYY-MM-DD> -date-to <YYYY-MM-DD> [flags]")
+	var sf serverFlags
+	addServerFlags(fs, &sf)
+	from := fs.String("from", "", "origin airports: comma-separated IATA codes, REGION:* or NEAR:<IATA>:<miles> tokens (required)")
+	to := fs.String("to", "", "destination airports, like -from (required)")
+	dateFrom := fs.String("date-from", "", "first departure date, YYYY-MM-DD (required)")
+	dateTo := fs.String("date-to", "", "last departure date, YYYY-MM-DD (required)")
+	returnFrom := fs.String("return-from", "", "first return date of round trips, YYYY-MM-DD")
+	returnTo := fs.String("return-to", "", "last return date of round trips, YYYY-MM-DD")
+	tripLength := fs.Int("trip-length", 0, "days between departure and return of round trips, instead of return dates")
+	tripType := fs.String("trip-type", "one_way", "trip type: one_way or round_trip")
+	adults := fs.Int("adults", 1, "number of adults")
+	children := fs.Int("children", 0, "number of children")
+	class := fs.String("class", "economy", "cabin class: economy, premium_economy, business or first")
+	stops := fs.String("stops", "any", "maximum stops: nonstop, one_stop, two_stops or any")
+	currencyCode := fs.String("currency", "USD", "ISO 4217 currency of the prices")
+	carriers := fs.String("carriers", "", "comma-separated airline codes or alliances to search")
+	country := fs.String("gl", "", "point-of-sale country, e.g. DE")
+	if err := c.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	req := apitypes.BulkSearchRequest{
+		Origins:      splitList(*from),
+		Destinations: splitList(*to),
+		TripLength:   *tripLength,
+		Adults:       *adults,
+		Children:     *children,
+		TripType:     *tripType,
+		Class:        *class,
+		Stops:        *stops,
+		Currency:     strings.ToUpper(*currencyCode),
+		Carriers:     splitList(*carriers),
+		Country:      *country,
+	}
+	if len(req.Origins) == 0 || len(req.Destinations) == 0 {
+		return c.usageError(fs, "-from and -to are required")
+	}
+	// the server validates the rest of the request
+	dates := []struct {
+		name, value string
+		required    bool
+		dst         *apitypes.DateOnly
+	}{
+		{"-date-from", *dateFrom, true, &req.DepartureDateFrom},
+		{"-date-to", *dateTo, true, &req.DepartureDateTo},
+		{"-return-from", *returnFrom, false, &req.ReturnDateFrom},
+		{"-return-to", *returnTo, false, &req.ReturnDateTo},
+	}
+	for _, d := range dates {
+		date, err := parseDate(d.name, d.value, d.required)
+		if err != nil {
+			return c.usageError(fs, "%v", err)
+		}
+		d.dst.Time = date
+	}
+
+	cl, err := sf.client()
+	if err != nil {
+		return err
+	}
+	resp, err := cl.BulkSearch(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stdout, "%s\n", resp.Message)
+	fmt.Fprintf(c.stdout, "bulk search: %d\njob:         %s\n", resp.BulkSearchID, resp.JobID)
+	for _, warning := range resp.Warnings {
+		fmt.Fprintf(c.stderr, "warning: %s\n", warning)
+	}
+	return nil
+}
+
+func (c *cli) queueStatus(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("queue", "[flags]")
+	var sf serverFlags
+	addServerFlags(fs, &sf)
+	watch := fs.Duration("watch", 0, "refresh the counters at this interval until interrupted, e.g. 5s")
+	format := addFormatFlag(fs)
+	if err := c.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return c.usageError(fs, "%v", err)
+	}
+	if *watch < 0 {
+		return c.usageError(fs, "-watch must not be negative")
+	}
+
+	cl, err := sf.client()
+	if err != nil {
+		return err
+	}
+	for {
+		stats, err := cl.QueueStats(ctx)
+		if err != nil {
+			return err
+		}
+		if *watch > 0 && *format == formatTable {
+			fmt.Fprintf(c.stdout, "%s\n", time.Now().Format("15:04:05"))
+		}
+		if err := writeOutput(c.stdout, *format, stats, queueTable(stats)); err != nil {
+			return err
+		}
+		if *watch == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*watch):
+		}
+		if *format == formatTable {
+			fmt.Fprintln(c.stdout)
+		}
+	}
+}
+
+// queueTable has a row per queue and a column per counter of any queue.
+func queueTable(stats map[string]map[string]int64) table {
+	names := make([]string, 0, len(stats))
+	counterSet := map[string]bool{}
+	for name, counters := range stats {
+		names = append(names, name)
+		for counter := range counters {
+			counterSet[counter] = true
+		}
+	}
+	sort.Strings(names)
+	counters := make([]string, 0, len(counterSet))
+	for counter := range counterSet {
+		counters = append(counters, counter)
+	}
+	sort.Strings(counters)
+
+	t := table{header: []string{"QUEUE"}}
+	for _, counter := range counters {
+		t.header = append(t.header, strings.ToUpper(counter))
+	}
+	for _, name := range names {
+		row := []string{name}
+		for _, counter := range counters {
+			row = append(row, strconv.FormatInt(stats[name][counter], 10))
+		}
+		t.add(row...)
+	}
+	return t
+}
+
+func (c *cli) deadLetters(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("dead-letters", "[flags] <queue> [replay]")
+	var sf serverFlags
+	addServerFlags(fs, &sf)
+	class := fs.String("class", "", "only dead letters of this error class, e.g. rate_limited, timeout or panic")
+	jobs := fs.String("jobs", "", "comma-separated job IDs to replay")
+	limit := fs.Int("limit", 50, "maximum dead letters to list or replay")
+	offset := fs.Int("offset", 0, "dead letters to skip when listing")
+	format := addFormatFlag(fs)
+	if err := c.parseFlags(fs, args, 2); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return c.usageError(fs, "%v", err)
+	}
+	if fs.NArg() == 0 {
+		return c.usageError(fs, "missing queue")
+	}
+	queueName := fs.Arg(0)
+	replay := false
+	if fs.NArg() == 2 {
+		if fs.Arg(1) != "replay" {
+			return c.usageError(fs, "unknown action %q", fs.Arg(1))
+		}
+		replay = true
+	}
+
+	var jobIDs []string
+	for _, id := range strings.Split(*jobs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			jobIDs = append(jobIDs, id)
+		}
+	}
+	if replay && len(jobIDs) == 0 && *class == "" {
+		return c.usageError(fs, "replay needs -jobs or -class")
+	}
+
+	cl, err := sf.client()
+	if err != nil {
+		return err
+	}
+
+	if replay {
+		resp, err := cl.ReplayDeadLetters(ctx, queueName, apitypes.DeadLetterReplayRequest{JobIDs: jobIDs, ErrorClass: *class, Limit: *limit})
+		if err != nil {
+			return err
+		}
+		if *format == formatTable {
+			fmt.Fprintf(c.stdout, "replayed %d jobs of %s\n", resp.Count, resp.Queue)
+			return nil
+		}
+		t := table{header: []string{"JOB"}}
+		for _, id := range resp.Replayed {
+			t.add(id)
+		}
+		return writeOutput(c.stdout, *format, resp, t)
+	}
+
+	resp, err := cl.ListDeadLetters(ctx, queueName, client.DeadLetterListOptions{
+		ListOptions: client.ListOptions{Limit: *limit, Offset: *offset},
+		ErrorClass:  *class,
+	})
+	if err != nil {
+		return err
+	}
+
+	t := table{header: []string{"JOB", "CLASS", "ATTEMPTS", "WORKER", "DEAD", "ERROR"}}
+	for _, dl := range resp.DeadLetters {
+		t.add(dl.Job.ID, dl.ErrorClass, strconv.Itoa(dl.Job.Attempts), dl.WorkerID,
+			dl.DeadAt.Format(time.RFC3339), dl.Error)
+	}
+	return writeOutput(c.stdout, *format, resp, t)
+}
+
+// sweepActions are the controls of the continuous sweep.
+var sweepActions = map[string]func(*client.Client, context.Context) (*apitypes.ContinuousSweepActionResponse, error){
+	"start":   (*client.Client).StartContinuousSweep,
+	"stop":    (*client.Client).StopContinuousSweep,
+	"pause":   (*client.Client).PauseContinuousSweep,
+	"resume":  (*client.Client).ResumeContinuousSweep,
+	"skip":    (*client.Client).SkipContinuousSweepRoute,
+	"restart": (*client.Client).RestartContinuousSweep,
+}
+
+func (c *cli) sweep(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("sweep", "[flags] [status|start|stop|pause|resume|skip|restart]")
+	var sf serverFlags
+	addServerFlags(fs, &sf)
+	jsonOutput := fs.Bool("json", false, "print the response as JSON")
+	if err := c.parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	action := "status"
+	if fs.NArg() == 1 {
+		action = fs.Arg(0)
+	}
+	run, ok := sweepActions[action]
+	if !ok && action != "status" {
+		return c.usageError(fs, "unknown action %q", action)
+	}
+
+	cl, err := sf.client()
+	if err != nil {
+		return err
+	}
+	format := formatTable
+	if *jsonOutput {
+		format = formatJSON
+	}
+
+	if action == "status" {
+		resp, err := cl.ContinuousSweepStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if format == formatJSON {
+			return writeOutput(c.stdout, format, resp, table{})
+		}
+		if !resp.Initialized && resp.Message != "" {
+			fmt.Fprintf(c.stdout, "%s\n", resp.Message)
+		}
+		if err := writeOutput(c.stdout, format, nil, sweepStatusTable(resp)); err != nil {
+			return err
+		}
+		if len(resp.Queues) > 0 {
+			fmt.Fprintln(c.stdout)
+			stats := make(map[string]map[string]int64, len(resp.Queues))
+			for name, q := range resp.Queues {
+				stats[name] = map[string]int64{"pending": q.Pending, "processing": q.Processing, "completed": q.Completed, "failed": q.Failed}
+			}
+			return writeOutput(c.stdout, format, nil, queueTable(stats))
+		}
+		return nil
+	}
+
+	resp, err := run(cl, ctx)
+	if err != nil {
+		return err
+	}
+	if format == formatJSON {
+		return writeOutput(c.stdout, format, resp, table{})
+	}
+	fmt.Fprintf(c.stdout, "%s\n", resp.Message)
+	return nil
+}
+
+// sweepStatusTable has a row per field of the sweep status.
+func sweepStatusTable(resp *apitypes.ContinuousSweepStatusResponse) table {
+	t := table{header: []string{"FIELD", "VALUE"}}
+	if status := resp.Status; status != nil {
+		state := "stopped"
+		switch {
+		case status.IsRunning && status.IsPaused:
+			state = "paused"
+		case status.IsRunning:
+			state = "running"
+		}
+		t.add("state", state)
+		t.add("sweep", strconv.Itoa(status.SweepNumber))
+		t.add("progress", fmt.Sprintf("%d/%d routes (%.1f%%)", status.RouteIndex, status.TotalRoutes, status.ProgressPercent))
+		if status.CurrentOrigin != "" {
+			t.add("route", status.CurrentOrigin+"-"+status.CurrentDestination)
+		}
+		t.add("class", status.Class)
+		t.add("stops", status.Stops)
+		t.add("queries", strconv.Itoa(status.QueriesCompleted))
+		t.add("errors", strconv.Itoa(status.ErrorsCount))
+		if status.LastError != "" {
+			t.add("last error", status.LastError)
+		}
+		t.add("pacing", fmt.Sprintf("%s (%.0f queries/hour)", status.PacingMode, status.QueriesPerHour))
+	}
+	if control := resp.Control; control != nil {
+		t.add("control", fmt.Sprintf("running=%t paused=%t (%s)", control.IsRunning, control.IsPaused, control.Source))
+	}
+	if resp.ControlError != "" {
+		t.add("control error", resp.ControlError)
+	}
+	t.add("server time", resp.ServerTime)
+	return t
+}
+
+func (c *cli) deals(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("deals", "[flags]")
+	var sf serverFlags
+	addServerFlags(fs, &sf)
+	origin := fs.String("origin", "", "only deals from this airport")
+	destination := fs.String("destination", "", "only deals to this airport")
+	classification := fs.String("classification", "", "only deals of this classification, e.g. good, great, amazing or error_fare")
+	status := fs.String("status", "", "only deals with this status, e.g. active or expired")
+	limit := fs.Int("limit", 50, "maximum deals to list")
+	offset := fs.Int("offset", 0, "deals to skip")
+	format := addFormatFlag(fs)
+	if err := c.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return c.usageError(fs, "%v", err)
+	}
+
+	cl, err := sf.client()
+	if err != nil {
+		return err
+	}
+	resp, err := cl.ListDeals(ctx, client.DealListOptions{
+		ListOptions:    client.ListOptions{Limit: *limit, Offset: *offset},
+		Origin:         strings.ToUpper(*origin),
+		Destination:    strings.ToUpper(*destination),
+		Classification: *classification,
+		Status:         *status,
+	})
+	if err != nil {
+		return err
+	}
+
+	t := table{header: []string{"ID", "ROUTE", "DEPART", "RETURN", "PRICE", "CURRENCY", "DISCOUNT", "SCORE", "CLASSIFICATION", "CABIN", "STATUS", "SEEN"}}
+	for _, deal := range resp.Deals {
+		discount, score, classification := "", "", ""
+		if deal.DiscountPercent != nil {
+			discount = fmt.Sprintf("%.0f%%", *deal.DiscountPercent)
+		}
+		if deal.DealScore != nil {
+			score = strconv.Itoa(*deal.DealScore)
+		}
+		if deal.DealClassification != nil {
+			classification = *deal.DealClassification
+		}
+		t.add(strconv.Itoa(deal.ID), deal.Origin+"-"+deal.Destination, deal.DepartureDate, deal.ReturnDate,
+			formatPrice(deal.Price), deal.Currency, discount, score, classification, deal.CabinClass, deal.Status,
+			strconv.Itoa(deal.TimesSeen))
+	}
+	return writeOutput(c.stdout, *format, resp, t)
+}
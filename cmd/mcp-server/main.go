@@ -8,8 +8,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gilby125/google-flights-api/config"
 	"github.com/gilby125/google-flights-api/flights"
 	"github.com/gilby125/google-flights-api/hotels"
+	"github.com/gilby125/google-flights-api/pkg/macros"
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"golang.org/x/text/currency"
@@ -18,6 +21,10 @@ import (
 
 const dateLayout = "2006-01-02"
 
+// maxNearbyAirports caps the airports a NEAR: token adds to one side of a search, as Google
+// Flights only accepts a handful of airports per field.
+const maxNearbyAirports = 7
+
 type flightInfo struct {
 	DepAirport string `json:"dep_airport"`
 	ArrAirport string `json:"arr_airport"`
@@ -52,8 +59,18 @@ type segmentInput struct {
 }
 
 func main() {
-	flightSession, err := flights.New()
+	cfg, err := config.Load()
 	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+	// The MCP server has no Redis to share a budget with, but its sessions still mustn't exceed the
+	// configured request rates (the RATE_LIMIT_* settings of the API server).
+	ratelimit.SetDefault(ratelimit.NewLocal(cfg.RateLimitConfig.Limits()))
+
+	sessionPool := flights.NewSessionPool(cfg.FlightConfig.SessionPoolOptions())
+	// Create the first session up front, so a misconfigured environment fails at startup.
+	if _, err := sessionPool.Get(context.Background()); err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing flights session: %v\n", err)
 		os.Exit(1)
 	}
@@ -72,8 +89,8 @@ func main() {
 
 	searchFlightsTool := mcp.NewTool("search_flights",
 		mcp.WithDescription("Search for flights using Google Flights (one-way, round-trip, or multi-city)"),
-		mcp.WithString("origin", mcp.Description("Origin airport code (e.g., SFO, LHR)")),
-		mcp.WithString("destination", mcp.Description("Destination airport code (e.g., JFK, CDG)")),
+		mcp.WithString("origin", mcp.Description("Origin airport code (e.g., SFO, LHR) or NEAR:<IATA>:<miles> for the closest airports within a radius (e.g., NEAR:SFO:150)")),
+		mcp.WithString("destination", mcp.Description("Destination airport code (e.g., JFK, CDG) or NEAR:<IATA>:<miles> (e.g., NEAR:JFK:60)")),
 		mcp.WithString("date", mcp.Description("Departure date (YYYY-MM-DD)")),
 		mcp.WithString("return_date", mcp.Description("Return date (YYYY-MM-DD) for round trips")),
 		mcp.WithString("segments", mcp.Description("JSON array of segments for multi-city. Example: '[{\"origin\":\"SFO\",\"destination\":\"JFK\",\"date\":\"2026-06-01\"}]'")),
@@ -81,6 +98,9 @@ func main() {
 		mcp.WithString("currency", mcp.Description("Currency code (e.g., USD, EUR). Default USD.")),
 		mcp.WithString("carriers", mcp.Description("Comma-separated IATA airline codes and/or alliance tokens (best-effort). Example: 'UA,DL' or 'STAR_ALLIANCE'.")),
 		mcp.WithString("trip_type", mcp.Description("Trip type: 'round_trip', 'one_way', or 'multi_city'. Default: round_trip if return_date is provided, else one_way.")),
+		mcp.WithString("gl", mcp.Description("Point-of-sale country (ISO 3166-1 alpha-2, e.g. DE, IN). Default US.")),
+		mcp.WithString("google_host", mcp.Description("Google domain to query (e.g. www.google.de). Default www.google.com.")),
+		mcp.WithNumber("tz_offset_min", mcp.Description("User timezone offset in minutes as in JavaScript getTimezoneOffset (e.g. -60 for UTC+1). Default -120.")),
 	)
 
 	s.AddTool(searchFlightsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -171,12 +191,24 @@ func main() {
 		options.TripType = tripType
 		options.Lang = language.English
 		options.Carriers = carriers
+		if err := applyMarketArgs(argsMap, &options); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid market: %v", err)), nil
+		}
+
+		srcAirports, srcNearby, err := expandNearbyAirports(origin)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid origin: %v", err)), nil
+		}
+		dstAirports, dstNearby, err := expandNearbyAirports(destination)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid destination: %v", err)), nil
+		}
 
 		searchArgs := flights.Args{
 			Date:        date,
 			ReturnDate:  returnDate,
-			SrcAirports: []string{origin},
-			DstAirports: []string{destination},
+			SrcAirports: srcAirports,
+			DstAirports: dstAirports,
 			Options:     options,
 		}
 
@@ -214,6 +246,11 @@ func main() {
 			}
 		}
 
+		flightSession, err := sessionPool.Get(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error initializing flights session: %v", err)), nil
+		}
+
 		offers, priceRange, err := flightSession.GetOffers(ctx, searchArgs)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Error searching flights: %v", err)), nil
@@ -258,6 +295,9 @@ func main() {
 			"price_range": priceRange,
 			"search_url":  searchURL,
 		}
+		if nearby := append(srcNearby, dstNearby...); len(nearby) > 0 && tripType != flights.MultiCity {
+			resp["nearby_airports"] = nearby
+		}
 
 		jsonBytes, err := json.MarshalIndent(resp, "", "  ")
 		if err != nil {
@@ -268,14 +308,19 @@ func main() {
 	})
 
 	getPriceGraphTool := mcp.NewTool("get_price_graph",
-		mcp.WithDescription("Get price graph data (calendar graph) for a date range (round-trip only)"),
-		mcp.WithString("origin", mcp.Description("Origin airport code (e.g., SFO)"), mcp.Required()),
-		mcp.WithString("destination", mcp.Description("Destination airport code (e.g., CDG)"), mcp.Required()),
+		mcp.WithDescription("Get price graph data (calendar graph) for a date range (round-trip, open-jaw or two-segment multi-city)"),
+		mcp.WithString("origin", mcp.Description("Origin airport code (e.g., SFO) or NEAR:<IATA>:<miles> (e.g., NEAR:SFO:150)"), mcp.Required()),
+		mcp.WithString("destination", mcp.Description("Destination airport code (e.g., CDG) or NEAR:<IATA>:<miles> (e.g., NEAR:CDG:100)"), mcp.Required()),
 		mcp.WithString("range_start_date", mcp.Description("Start date of the range (YYYY-MM-DD)"), mcp.Required()),
 		mcp.WithString("range_end_date", mcp.Description("End date of the range (YYYY-MM-DD)"), mcp.Required()),
 		mcp.WithNumber("trip_length", mcp.Description("Trip length in days (default 7)")),
+		mcp.WithString("return_origin", mcp.Description("Open-jaw: airport the return flight departs from (e.g., LHR when flying into CDG)")),
+		mcp.WithString("return_destination", mcp.Description("Two-segment multi-city: airport the return flight lands in (default: origin; requires return_origin)")),
 		mcp.WithString("currency", mcp.Description("Currency code (default USD)")),
 		mcp.WithString("carriers", mcp.Description("Comma-separated IATA carrier codes/alliance tokens to include (best-effort)")),
+		mcp.WithString("gl", mcp.Description("Point-of-sale country (ISO 3166-1 alpha-2, e.g. DE, IN). Default US.")),
+		mcp.WithString("google_host", mcp.Description("Google domain to query (e.g. www.google.de). Default www.google.com.")),
+		mcp.WithNumber("tz_offset_min", mcp.Description("User timezone offset in minutes as in JavaScript getTimezoneOffset (e.g. -60 for UTC+1). Default -120.")),
 	)
 
 	s.AddTool(getPriceGraphTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -289,6 +334,11 @@ func main() {
 		rangeStartDateStr, _ := argsMap["range_start_date"].(string)
 		rangeEndDateStr, _ := argsMap["range_end_date"].(string)
 		carriersStr, _ := argsMap["carriers"].(string)
+		returnOrigin, _ := argsMap["return_origin"].(string)
+		returnDestination, _ := argsMap["return_destination"].(string)
+		if returnDestination != "" && returnOrigin == "" {
+			return mcp.NewToolResultError("return_destination requires return_origin"), nil
+		}
 
 		tripLengthVal, _ := argsMap["trip_length"].(float64)
 		tripLength := int(tripLengthVal)
@@ -330,15 +380,38 @@ func main() {
 		options.Currency = currUnit
 		options.Lang = language.English
 		options.Carriers = carriers
+		if err := applyMarketArgs(argsMap, &options); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid market: %v", err)), nil
+		}
+
+		srcAirports, srcNearby, err := expandNearbyAirports(origin)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid origin: %v", err)), nil
+		}
+		dstAirports, dstNearby, err := expandNearbyAirports(destination)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid destination: %v", err)), nil
+		}
 
 		pgArgs := flights.PriceGraphArgs{
 			RangeStartDate: rangeStartDate,
 			RangeEndDate:   rangeEndDate,
 			TripLength:     tripLength,
-			SrcAirports:    []string{origin},
-			DstAirports:    []string{destination},
+			SrcAirports:    srcAirports,
+			DstAirports:    dstAirports,
 			Options:        options,
 		}
+		if returnOrigin != "" {
+			pgArgs.ReturnLeg = &flights.PriceGraphLeg{SrcAirports: []string{returnOrigin}}
+			if returnDestination != "" {
+				pgArgs.ReturnLeg.DstAirports = []string{returnDestination}
+			}
+		}
+
+		flightSession, err := sessionPool.Get(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error initializing flights session: %v", err)), nil
+		}
 
 		offers, _, err := flightSession.GetPriceGraph(ctx, pgArgs)
 		if err != nil {
@@ -365,13 +438,7 @@ func main() {
 
 		bestAirline := ""
 		if bestOffer != nil {
-			sampleArgs := flights.Args{
-				Date:        bestOffer.StartDate,
-				ReturnDate:  bestOffer.ReturnDate,
-				SrcAirports: []string{origin},
-				DstAirports: []string{destination},
-				Options:     options,
-			}
+			sampleArgs := pgArgs.OfferArgs(*bestOffer)
 			sampleOffers, _, err := flightSession.GetOffers(ctx, sampleArgs)
 			if err == nil && len(sampleOffers) > 0 && len(sampleOffers[0].Flight) > 0 {
 				bestAirline = sampleOffers[0].Flight[0].AirlineName
@@ -383,6 +450,9 @@ func main() {
 			"best_price":         minPrice,
 			"best_price_airline": bestAirline,
 		}
+		if nearby := append(srcNearby, dstNearby...); len(nearby) > 0 {
+			resp["nearby_airports"] = nearby
+		}
 
 		jsonBytes, err := json.MarshalIndent(resp, "", "  ")
 		if err != nil {
@@ -500,6 +570,34 @@ func main() {
 	}
 }
 
+// applyMarketArgs sets the point of sale of options from the gl, google_host and tz_offset_min
+// tool arguments.
+func applyMarketArgs(argsMap map[string]any, options *flights.Options) error {
+	options.Country, _ = argsMap["gl"].(string)
+	options.GoogleHost, _ = argsMap["google_host"].(string)
+	if tzOffset, ok := argsMap["tz_offset_min"].(float64); ok {
+		offset := int(tzOffset)
+		options.TimezoneOffset = &offset
+	}
+	return options.ValidateMarket()
+}
+
+// expandNearbyAirports expands a NEAR:<IATA>:<miles> token into the closest airports within its
+// radius, with their distance from the anchor. Other inputs are searched as they are.
+func expandNearbyAirports(input string) ([]string, []macros.NearbyAirport, error) {
+	if !macros.IsNearToken(input) {
+		return []string{input}, nil, nil
+	}
+	nearby, err := macros.NearbyAirports(input, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(nearby) > maxNearbyAirports {
+		nearby = nearby[:maxNearbyAirports]
+	}
+	return macros.NearbyAirportCodes(nearby), nearby, nil
+}
+
 func uniqueStrings(input []string) []string {
 	seen := make(map[string]struct{}, len(input))
 	out := make([]string, 0, len(input))
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/joho/godotenv"
 )
 
@@ -20,9 +23,12 @@ type Config struct {
 	Neo4jConfig       Neo4jConfig
 	Neo4jEnabled      bool
 	RedisConfig       RedisConfig
+	QueueBackend      string // QueueBackendRedis or QueueBackendPostgres; the queue settings of RedisConfig apply to both
 	WorkerConfig      WorkerConfig
 	FlightConfig      FlightConfig
 	DealConfig        DealConfig
+	CurrencyConfig    CurrencyConfig
+	RateLimitConfig   RateLimitConfig
 	LetsEncryptConfig LetsEncryptConfig
 	NTFYConfig        NTFYConfig
 	AdminAuthConfig   AdminAuthConfig
@@ -31,10 +37,51 @@ type Config struct {
 	SeedNeo4j         bool
 }
 
+// Queue backends for Config.QueueBackend. With Postgres, the queue and the scheduler lock need
+// no Redis.
+const (
+	QueueBackendRedis    = "redis"
+	QueueBackendPostgres = "postgres"
+)
+
 // FlightConfig holds flight search configuration
 type FlightConfig struct {
 	ExcludedAirlines []string // Airline codes to exclude from results (e.g., NK, G4, F9)
 	TopNDeals        int      // Number of top deals to fetch full itineraries for
+	SessionPoolSize  int      // Number of Google sessions per search type
+	// SessionRetryDelay is how long a session pool slot waits after a failed session creation
+	SessionRetryDelay time.Duration
+}
+
+// SessionPoolOptions returns the options of the Google session pools.
+func (c FlightConfig) SessionPoolOptions() flights.SessionPoolOptions {
+	return flights.SessionPoolOptions{Size: c.SessionPoolSize, RetryDelay: c.SessionRetryDelay}
+}
+
+// RateLimitConfig holds the global budget of the requests sent to Google, shared by all processes
+// through Redis. Rates are in requests per minute; 0 means no limit.
+type RateLimitConfig struct {
+	Enabled             bool
+	OffersPerMinute     float64
+	PriceGraphPerMinute float64
+	LocationPerMinute   float64
+	HotelsPerMinute     float64
+	SessionPerMinute    float64
+	Burst               int
+	KeyPrefix           string
+}
+
+// Limits returns the budgets of the request kinds.
+func (c RateLimitConfig) Limits() ratelimit.Config {
+	return ratelimit.Config{
+		Rates: map[string]ratelimit.Rate{
+			ratelimit.KindOffers:     {PerMinute: c.OffersPerMinute, Burst: c.Burst},
+			ratelimit.KindPriceGraph: {PerMinute: c.PriceGraphPerMinute, Burst: c.Burst},
+			ratelimit.KindLocation:   {PerMinute: c.LocationPerMinute, Burst: c.Burst},
+			ratelimit.KindHotels:     {PerMinute: c.HotelsPerMinute, Burst: c.Burst},
+			ratelimit.KindSession:    {PerMinute: c.SessionPerMinute, Burst: c.Burst},
+		},
+	}
 }
 
 // DealConfig holds deal detection configuration
@@ -61,6 +108,14 @@ type DealConfig struct {
 	AutoPublish bool
 }
 
+// CurrencyConfig holds currency conversion configuration. Prices are normalized to
+// ReportingCurrency with daily exchange rates from RatesFile and RatesURL, tried in that order.
+type CurrencyConfig struct {
+	ReportingCurrency string
+	RatesFile         string // CSV seed of exchange rates (date,base,quote,rate)
+	RatesURL          string // ECB-style JSON rate API, e.g. https://api.frankfurter.app
+}
+
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level  string
@@ -108,6 +163,19 @@ type RedisConfig struct {
 	QueueStreamPrefix      string
 	QueueBlockTimeout      time.Duration
 	QueueVisibilityTimeout time.Duration
+	// QueuePriorityWeights overrides the share of dequeues per priority lane ("high", "normal", "low").
+	QueuePriorityWeights map[string]int
+	// QueueRetryBaseDelay is the backoff before the first retry of a nacked job; it doubles with
+	// every further attempt up to QueueRetryMaxDelay. Zero retries nacked jobs right away.
+	// Set by REDIS_QUEUE_RETRY_BASE_DELAY.
+	QueueRetryBaseDelay time.Duration
+	QueueRetryMaxDelay  time.Duration
+	// QueueDedupWindow is how long a job's idempotency key collapses later jobs with the same
+	// key into it. Zero turns deduplication off.
+	QueueDedupWindow time.Duration
+	// QueueTenants are the tenants accepted from the X-Tenant-ID header of requests without admin
+	// credentials. Other tenants are only accepted from authenticated requests.
+	QueueTenants []string
 }
 
 // WorkerConfig holds worker configuration
@@ -192,6 +260,11 @@ func Load() (*Config, error) {
 	}
 	neo4jEnabled, _ := strconv.ParseBool(getEnv("NEO4J_ENABLED", "true"))
 
+	queueBackend := strings.ToLower(getEnv("QUEUE_BACKEND", QueueBackendRedis))
+	if queueBackend != QueueBackendRedis && queueBackend != QueueBackendPostgres {
+		return nil, fmt.Errorf("invalid QUEUE_BACKEND %q (want %s or %s)", queueBackend, QueueBackendRedis, QueueBackendPostgres)
+	}
+
 	queueBlockTimeout, err := time.ParseDuration(getEnv("REDIS_QUEUE_BLOCK_TIMEOUT", "5s"))
 	if err != nil {
 		queueBlockTimeout = 5 * time.Second
@@ -204,6 +277,18 @@ func Load() (*Config, error) {
 	concurrency, _ := strconv.Atoi(getEnv("WORKER_CONCURRENCY", "5"))
 	maxRetries, _ := strconv.Atoi(getEnv("WORKER_MAX_RETRIES", "3"))
 	retryDelay, _ := time.ParseDuration(getEnv("WORKER_RETRY_DELAY", "30s"))
+	queueRetryBaseDelay, err := time.ParseDuration(getEnv("REDIS_QUEUE_RETRY_BASE_DELAY", "30s"))
+	if err != nil || queueRetryBaseDelay < 0 {
+		queueRetryBaseDelay = 30 * time.Second
+	}
+	queueRetryMaxDelay, err := time.ParseDuration(getEnv("REDIS_QUEUE_RETRY_MAX_DELAY", "15m"))
+	if err != nil {
+		queueRetryMaxDelay = 15 * time.Minute
+	}
+	queueDedupWindow, err := time.ParseDuration(getEnv("REDIS_QUEUE_DEDUP_WINDOW", "10m"))
+	if err != nil || queueDedupWindow < 0 {
+		queueDedupWindow = 10 * time.Minute
+	}
 	jobTimeout, _ := time.ParseDuration(getEnv("WORKER_JOB_TIMEOUT", "10m"))
 	shutdownTimeout, _ := time.ParseDuration(getEnv("WORKER_SHUTDOWN_TIMEOUT", "30s"))
 	schedulerLockTTL, _ := time.ParseDuration(getEnv("SCHEDULER_LOCK_TTL", "30s"))
@@ -217,6 +302,13 @@ func Load() (*Config, error) {
 		queueVisibilityTimeout = jobTimeout
 	}
 
+	var queueTenants []string
+	for _, tenant := range strings.Split(getEnv("REDIS_QUEUE_TENANTS", ""), ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			queueTenants = append(queueTenants, tenant)
+		}
+	}
+
 	redisConfig := RedisConfig{
 		Host:                   getEnv("REDIS_HOST", "redis"),
 		Port:                   getEnv("REDIS_PORT", "6379"),
@@ -226,6 +318,11 @@ func Load() (*Config, error) {
 		QueueStreamPrefix:      getEnv("REDIS_QUEUE_STREAM_PREFIX", "flights"),
 		QueueBlockTimeout:      queueBlockTimeout,
 		QueueVisibilityTimeout: queueVisibilityTimeout,
+		QueuePriorityWeights:   parseWeights(getEnv("REDIS_QUEUE_PRIORITY_WEIGHTS", "")),
+		QueueRetryBaseDelay:    queueRetryBaseDelay,
+		QueueRetryMaxDelay:     queueRetryMaxDelay,
+		QueueDedupWindow:       queueDedupWindow,
+		QueueTenants:           queueTenants,
 	}
 
 	workerID := getEnv("WORKER_ID", "")
@@ -305,9 +402,19 @@ func Load() (*Config, error) {
 	if topNDeals < 1 {
 		topNDeals = 3
 	}
+	sessionPoolSize, _ := strconv.Atoi(getEnv("FLIGHT_SESSION_POOL_SIZE", "3"))
+	if sessionPoolSize < 1 {
+		sessionPoolSize = 3
+	}
+	sessionRetryDelay, err := time.ParseDuration(getEnv("FLIGHT_SESSION_RETRY_DELAY", "30s"))
+	if err != nil || sessionRetryDelay <= 0 {
+		sessionRetryDelay = 30 * time.Second
+	}
 	flightConfig := FlightConfig{
-		ExcludedAirlines: excludedAirlines,
-		TopNDeals:        topNDeals,
+		ExcludedAirlines:  excludedAirlines,
+		TopNDeals:         topNDeals,
+		SessionPoolSize:   sessionPoolSize,
+		SessionRetryDelay: sessionRetryDelay,
 	}
 
 	// Deal detection config
@@ -337,6 +444,35 @@ func Load() (*Config, error) {
 		AutoPublish:          autoPublish,
 	}
 
+	currencyConfig := CurrencyConfig{
+		ReportingCurrency: strings.ToUpper(getEnv("REPORTING_CURRENCY", "USD")),
+		RatesFile:         getEnv("FX_RATES_FILE", ""),
+		RatesURL:          getEnv("FX_RATES_URL", ""),
+	}
+
+	rateLimitEnabled, _ := strconv.ParseBool(getEnv("RATE_LIMIT_ENABLED", "false"))
+	rateLimitBurst, _ := strconv.Atoi(getEnv("RATE_LIMIT_BURST", "5"))
+	if rateLimitBurst < 1 {
+		rateLimitBurst = 1
+	}
+	rateLimitRate := func(key, defaultValue string) float64 {
+		rate, err := strconv.ParseFloat(getEnv(key, defaultValue), 64)
+		if err != nil || rate < 0 {
+			rate, _ = strconv.ParseFloat(defaultValue, 64)
+		}
+		return rate
+	}
+	rateLimitConfig := RateLimitConfig{
+		Enabled:             rateLimitEnabled,
+		OffersPerMinute:     rateLimitRate("RATE_LIMIT_OFFERS_PER_MIN", "60"),
+		PriceGraphPerMinute: rateLimitRate("RATE_LIMIT_PRICE_GRAPH_PER_MIN", "60"),
+		LocationPerMinute:   rateLimitRate("RATE_LIMIT_LOCATION_PER_MIN", "120"),
+		HotelsPerMinute:     rateLimitRate("RATE_LIMIT_HOTELS_PER_MIN", "30"),
+		SessionPerMinute:    rateLimitRate("RATE_LIMIT_SESSION_PER_MIN", "20"),
+		Burst:               rateLimitBurst,
+		KeyPrefix:           getEnv("RATE_LIMIT_KEY_PREFIX", redisConfig.QueueStreamPrefix+":ratelimit"),
+	}
+
 	return &Config{
 		Port:            port,
 		HTTPBindAddr:    httpBindAddr,
@@ -347,9 +483,12 @@ func Load() (*Config, error) {
 		Neo4jConfig:     neo4jConfig,
 		Neo4jEnabled:    neo4jEnabled,
 		RedisConfig:     redisConfig,
+		QueueBackend:    queueBackend,
 		WorkerConfig:    workerConfig,
 		FlightConfig:    flightConfig,
 		DealConfig:      dealConfig,
+		CurrencyConfig:  currencyConfig,
+		RateLimitConfig: rateLimitConfig,
 		NTFYConfig:      ntfyConfig,
 		AdminAuthConfig: adminAuthConfig,
 		WorkerEnabled:   workerEnabled,
@@ -393,6 +532,24 @@ func TestConfig() *Config {
 	return cfg
 }
 
+// parseWeights parses "name=weight" pairs such as "high=8,normal=4,low=1", skipping
+// malformed pairs and weights below 1.
+func parseWeights(s string) map[string]int {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 1 {
+			continue
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = weight
+	}
+	return weights
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -44,6 +44,10 @@ func TestLoad(t *testing.T) {
 		assert.Equal(t, "flights", cfg.RedisConfig.QueueStreamPrefix)
 		assert.Equal(t, 5*time.Second, cfg.RedisConfig.QueueBlockTimeout)
 		assert.Equal(t, 10*time.Minute, cfg.RedisConfig.QueueVisibilityTimeout)
+		assert.Equal(t, 30*time.Second, cfg.RedisConfig.QueueRetryBaseDelay)
+		assert.Equal(t, 15*time.Minute, cfg.RedisConfig.QueueRetryMaxDelay)
+		assert.Equal(t, 10*time.Minute, cfg.RedisConfig.QueueDedupWindow)
+		assert.Equal(t, QueueBackendRedis, cfg.QueueBackend)
 	})
 
 	t.Run("environment variable override", func(t *testing.T) {
@@ -57,6 +61,9 @@ func TestLoad(t *testing.T) {
 		t.Setenv("REDIS_HOST", "cache.example.com")
 		t.Setenv("WORKER_CONCURRENCY", "10")
 		t.Setenv("WORKER_ENABLED", "false")
+		t.Setenv("REDIS_QUEUE_PRIORITY_WEIGHTS", "high=10, low=2,normal=x")
+		t.Setenv("WORKER_RETRY_DELAY", "45s")
+		t.Setenv("REDIS_QUEUE_RETRY_BASE_DELAY", "5s")
 
 		cfg, err := Load()
 		require.NoError(t, err)
@@ -71,6 +78,20 @@ func TestLoad(t *testing.T) {
 		assert.Equal(t, "cache.example.com", cfg.RedisConfig.Host)
 		assert.Equal(t, 10, cfg.WorkerConfig.Concurrency)
 		assert.False(t, cfg.WorkerEnabled)
+		assert.Equal(t, map[string]int{"high": 10, "low": 2}, cfg.RedisConfig.QueuePriorityWeights)
+		assert.Equal(t, 45*time.Second, cfg.WorkerConfig.RetryDelay)
+		assert.Equal(t, 5*time.Second, cfg.RedisConfig.QueueRetryBaseDelay)
+	})
+
+	t.Run("queue backend", func(t *testing.T) {
+		t.Setenv("QUEUE_BACKEND", "Postgres")
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, QueueBackendPostgres, cfg.QueueBackend)
+
+		t.Setenv("QUEUE_BACKEND", "sqs")
+		_, err = Load()
+		assert.Error(t, err)
 	})
 }
 
@@ -0,0 +1,46 @@
+-- Point-of-sale comparisons: the same itinerary priced in several markets (Google gl / host),
+-- with every price normalized to the reporting currency of the comparison.
+
+CREATE TABLE IF NOT EXISTS pos_comparisons (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(32) NOT NULL DEFAULT 'queued',
+    origin VARCHAR(3) NOT NULL,
+    destination VARCHAR(3) NOT NULL,
+    departure_date DATE NOT NULL,
+    return_date DATE,
+    trip_type VARCHAR(20) NOT NULL,
+    class VARCHAR(20) NOT NULL,
+    stops VARCHAR(20) NOT NULL,
+    adults INTEGER NOT NULL DEFAULT 1,
+    children INTEGER NOT NULL DEFAULT 0,
+    infants_lap INTEGER NOT NULL DEFAULT 0,
+    infants_seat INTEGER NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    market_count INTEGER NOT NULL DEFAULT 0,
+    cheapest_country VARCHAR(2),
+    cheapest_price DECIMAL(12, 2),
+    savings DECIMAL(12, 2),
+    error_message TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_pos_comparisons_created_at ON pos_comparisons(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS pos_comparison_results (
+    id SERIAL PRIMARY KEY,
+    comparison_id INTEGER NOT NULL REFERENCES pos_comparisons(id) ON DELETE CASCADE,
+    country VARCHAR(2) NOT NULL,
+    google_host VARCHAR(64),
+    currency VARCHAR(3) NOT NULL,
+    price DECIMAL(12, 2),
+    normalized_price DECIMAL(12, 2),
+    airline_codes TEXT[],
+    flight_numbers TEXT[],
+    search_url TEXT,
+    error_message TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pos_comparison_results_comparison ON pos_comparison_results(comparison_id, normalized_price);
@@ -0,0 +1,32 @@
+-- Daily exchange rate snapshots and prices normalized to the reporting currency, so baselines,
+-- deals, cost-per-mile and route comparisons don't mix currencies.
+
+CREATE TABLE IF NOT EXISTS fx_rates (
+    rate_date DATE NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(20, 10) NOT NULL,
+    source VARCHAR(255) NOT NULL DEFAULT '',
+    fetched_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (rate_date, base_currency, quote_currency)
+);
+
+ALTER TABLE price_graph_results ADD COLUMN IF NOT EXISTS normalized_price DECIMAL(12, 2);
+ALTER TABLE price_graph_results ADD COLUMN IF NOT EXISTS normalized_currency VARCHAR(3);
+ALTER TABLE price_graph_results ADD COLUMN IF NOT EXISTS normalized_cost_per_mile DECIMAL(10, 4);
+
+ALTER TABLE bulk_search_results ADD COLUMN IF NOT EXISTS normalized_price DECIMAL(12, 2);
+ALTER TABLE bulk_search_results ADD COLUMN IF NOT EXISTS normalized_currency VARCHAR(3);
+
+ALTER TABLE bulk_search_offers ADD COLUMN IF NOT EXISTS normalized_price DECIMAL(12, 2);
+ALTER TABLE bulk_search_offers ADD COLUMN IF NOT EXISTS normalized_currency VARCHAR(3);
+ALTER TABLE bulk_search_offers ADD COLUMN IF NOT EXISTS normalized_cost_per_mile DECIMAL(10, 4);
+
+ALTER TABLE detected_deals ADD COLUMN IF NOT EXISTS normalized_price DECIMAL(12, 2);
+ALTER TABLE detected_deals ADD COLUMN IF NOT EXISTS normalized_currency VARCHAR(3);
+
+-- Baselines are computed in one currency; a baseline of another currency is recomputed.
+ALTER TABLE route_baselines ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+CREATE INDEX IF NOT EXISTS idx_price_graph_results_route_normalized
+    ON price_graph_results(origin, destination, normalized_currency);
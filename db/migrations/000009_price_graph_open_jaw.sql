@@ -0,0 +1,27 @@
+-- Open-jaw and two-segment multi-city price graphs: the second flight departs from return_origin
+-- and lands in return_destination. Both are empty for round-trip and one-way results.
+
+ALTER TABLE price_graph_results ADD COLUMN IF NOT EXISTS return_origin VARCHAR(3) NOT NULL DEFAULT '';
+ALTER TABLE price_graph_results ADD COLUMN IF NOT EXISTS return_destination VARCHAR(3) NOT NULL DEFAULT '';
+
+-- The return leg is part of the natural key, so open-jaw results don't overwrite each other.
+DROP INDEX IF EXISTS idx_price_graph_results_upsert_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_graph_results_upsert_key
+ON price_graph_results (
+  sweep_id,
+  origin,
+  destination,
+  return_origin,
+  return_destination,
+  departure_date,
+  trip_length,
+  currency,
+  adults,
+  children,
+  infants_lap,
+  infants_seat,
+  trip_type,
+  class,
+  stops
+);
@@ -0,0 +1,18 @@
+-- Multi-city searches (2-6 segments). The requested segments are stored per search query and the
+-- flights of every segment are kept with their segment index.
+
+CREATE TABLE IF NOT EXISTS search_query_segments (
+    search_query_id INTEGER NOT NULL REFERENCES search_queries(id) ON DELETE CASCADE,
+    segment_index INTEGER NOT NULL,
+    origin VARCHAR(3) NOT NULL,
+    destination VARCHAR(3) NOT NULL,
+    departure_date DATE NOT NULL,
+    PRIMARY KEY (search_query_id, segment_index)
+);
+
+-- 0 is the first (outbound) segment; round-trip return flights keep using is_return.
+ALTER TABLE flight_segments ADD COLUMN IF NOT EXISTS segment_index INTEGER NOT NULL DEFAULT 0;
+
+-- JSON array with the flights of every segment, in segment order.
+ALTER TABLE bulk_search_results ADD COLUMN IF NOT EXISTS segment_flights JSONB;
+ALTER TABLE bulk_search_offers ADD COLUMN IF NOT EXISTS segment_flights JSONB;
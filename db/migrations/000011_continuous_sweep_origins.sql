@@ -0,0 +1,3 @@
+-- Origin airports the continuous sweep is restricted to (empty: every top 100 route).
+ALTER TABLE continuous_sweep_progress
+ADD COLUMN IF NOT EXISTS origins TEXT[] DEFAULT '{}';
@@ -0,0 +1,51 @@
+-- Tables of the Postgres queue backend (QUEUE_BACKEND=postgres), which lets deployments run
+-- without Redis. Unused with the Redis backend.
+
+-- Every job with its full document; the columns workers select on are copied out of it.
+CREATE TABLE IF NOT EXISTS queue_jobs (
+    id TEXT PRIMARY KEY,
+    seq BIGSERIAL NOT NULL,
+    type TEXT NOT NULL,
+    priority TEXT NOT NULL,
+    tenant TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- End of the visibility timeout of a processing job; other workers may claim it after.
+    locked_until TIMESTAMP WITH TIME ZONE,
+    idempotency_key TEXT,
+    job JSONB NOT NULL,
+    dead_letter JSONB,
+    dead_letter_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- Finished jobs are deleted once this passes.
+    expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_ready ON queue_jobs (type, priority, tenant, run_at, seq) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_scheduled ON queue_jobs (run_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_leases ON queue_jobs (locked_until) WHERE status = 'processing';
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_status ON queue_jobs (type, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_idempotency ON queue_jobs (type, tenant, idempotency_key, created_at DESC) WHERE idempotency_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_expires ON queue_jobs (expires_at) WHERE expires_at IS NOT NULL;
+
+-- Cancellation requests, kept apart from the jobs so that they outlive a cleared job.
+CREATE TABLE IF NOT EXISTS queue_job_cancels (
+    job_id TEXT PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Enqueues per job type, minute and source.
+CREATE TABLE IF NOT EXISTS queue_enqueue_metrics (
+    queue TEXT NOT NULL,
+    minute TIMESTAMP WITH TIME ZONE NOT NULL,
+    source TEXT NOT NULL,
+    count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (queue, minute, source)
+);
+
+-- Locks with an owner and an expiry, such as the scheduler leader lock.
+CREATE TABLE IF NOT EXISTS queue_locks (
+    key TEXT PRIMARY KEY,
+    owner TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,8 @@
+-- Route baselines are kept per currency, so results normalized to the reporting currency and
+-- results left in their own currency don't overwrite each other's baseline.
+
+ALTER TABLE route_baselines
+    DROP CONSTRAINT IF EXISTS route_baselines_origin_destination_trip_length_class_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_route_baselines_route_currency
+    ON route_baselines(origin, destination, trip_length, class, currency);
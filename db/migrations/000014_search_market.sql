@@ -0,0 +1,7 @@
+-- Point of sale (Google gl / host) a search was priced in, so that prices of the same route in
+-- different markets can be told apart. Earlier searches all used the default US market.
+
+ALTER TABLE search_queries ADD COLUMN IF NOT EXISTS country VARCHAR(2) NOT NULL DEFAULT 'US';
+ALTER TABLE search_queries ADD COLUMN IF NOT EXISTS google_host VARCHAR(64);
+
+ALTER TABLE flight_offers ADD COLUMN IF NOT EXISTS country VARCHAR(2) NOT NULL DEFAULT 'US';
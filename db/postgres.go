@@ -31,6 +31,7 @@ type PostgresDB interface {
 	GetSearchQueryByID(ctx context.Context, id int) (*SearchQuery, error) // Define SearchQuery struct later
 	GetFlightOffersBySearchID(ctx context.Context, searchID int) (Rows, error)
 	GetFlightSegmentsByOfferID(ctx context.Context, offerID int) (Rows, error)
+	ListSearchQuerySegments(ctx context.Context, searchQueryID int) ([]SearchQuerySegment, error)
 	CountSearches(ctx context.Context) (int, error)
 	QuerySearchesPaginated(ctx context.Context, limit, offset int) (Rows, error)
 	DeleteJobDetailsByJobID(ctx context.Context, tx Tx, jobID int) error
@@ -78,10 +79,16 @@ type PostgresDB interface {
 	ListContinuousSweepStats(ctx context.Context, limit int) ([]ContinuousSweepStats, error)
 	ListContinuousSweepResults(ctx context.Context, filters ContinuousSweepResultsFilter) ([]PriceGraphResultRecord, error)
 
+	// Result exports (unpaginated, streamed by the caller)
+	ExportBulkSearchResults(ctx context.Context, searchID int, filter ExportFilter) (Rows, error)
+	ExportBulkSearchOffers(ctx context.Context, searchID int, filter ExportFilter) (Rows, error)
+	ExportPriceGraphResults(ctx context.Context, sweepID int, filter ExportFilter) (Rows, error)
+	ExportDetectedDeals(ctx context.Context, filter ExportFilter) (Rows, error)
+
 	// Deal detection methods
-	GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class string) (*RouteBaseline, error)
+	GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class, currency string) (*RouteBaseline, error)
 	UpsertRouteBaseline(ctx context.Context, baseline RouteBaseline) error
-	GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class string, windowDays int) ([]float64, error)
+	GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class, currency string, windowDays int) ([]float64, error)
 	InsertDetectedDeal(ctx context.Context, deal DetectedDeal) (int, error)
 	UpsertDetectedDeal(ctx context.Context, deal DetectedDeal) error
 	GetDetectedDealByFingerprint(ctx context.Context, fingerprint string) (*DetectedDeal, error)
@@ -89,6 +96,18 @@ type PostgresDB interface {
 	ExpireOldDeals(ctx context.Context) (int64, error)
 	InsertDealAlert(ctx context.Context, alert DealAlert) (int, error)
 	ListDealAlerts(ctx context.Context, limit, offset int) ([]DealAlert, error)
+
+	// Point-of-sale comparison methods
+	CreatePosComparison(ctx context.Context, comparison PosComparison) (int, error)
+	UpdatePosComparisonStatus(ctx context.Context, comparisonID int, status string) error
+	CompletePosComparison(ctx context.Context, summary PosComparisonSummary) error
+	GetPosComparisonByID(ctx context.Context, comparisonID int) (*PosComparison, error)
+	InsertPosComparisonResult(ctx context.Context, result PosComparisonResult) error
+	ListPosComparisonResults(ctx context.Context, comparisonID int) ([]PosComparisonResult, error)
+
+	// Exchange rate methods
+	GetFXRates(ctx context.Context, day time.Time) ([]FXRate, error)
+	UpsertFXRates(ctx context.Context, rates []FXRate) error
 }
 
 // Tx defines the interface for database transactions
@@ -336,10 +355,10 @@ func (p *PostgresDBImpl) QueryAirlines(ctx context.Context) (Rows, error) {
 func (p *PostgresDBImpl) GetSearchQueryByID(ctx context.Context, id int) (*SearchQuery, error) {
 	var query SearchQuery
 	err := p.db.QueryRowContext(ctx,
-		`SELECT id, origin, destination, departure_date, return_date, status, created_at
+		`SELECT id, origin, destination, departure_date, return_date, COALESCE(trip_type, ''), status, created_at
 		FROM search_queries WHERE id = $1`,
 		id,
-	).Scan(&query.ID, &query.Origin, &query.Destination, &query.DepartureDate, &query.ReturnDate, &query.Status, &query.CreatedAt)
+	).Scan(&query.ID, &query.Origin, &query.Destination, &query.DepartureDate, &query.ReturnDate, &query.TripType, &query.Status, &query.CreatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -366,12 +385,40 @@ func (p *PostgresDBImpl) GetFlightSegmentsByOfferID(ctx context.Context, offerID
 	// Assuming flight_offer_id is the foreign key in flight_segments
 	return p.db.QueryContext(ctx,
 		`SELECT airline_code, flight_number, departure_airport, arrival_airport,
-		departure_time, arrival_time, duration, airplane, legroom, is_return
-		FROM flight_segments WHERE flight_offer_id = $1`, // Adjust column name if needed
+		departure_time, arrival_time, duration, airplane, legroom, is_return, segment_index
+		FROM flight_segments WHERE flight_offer_id = $1
+		ORDER BY segment_index, departure_time`,
 		offerID,
 	)
 }
 
+// ListSearchQuerySegments returns the requested segments of a multi-city search query in order.
+func (p *PostgresDBImpl) ListSearchQuerySegments(ctx context.Context, searchQueryID int) ([]SearchQuerySegment, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT search_query_id, segment_index, origin, destination, departure_date
+		FROM search_query_segments WHERE search_query_id = $1
+		ORDER BY segment_index`,
+		searchQueryID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying segments of search query %d: %w", searchQueryID, err)
+	}
+	defer rows.Close()
+
+	var segments []SearchQuerySegment
+	for rows.Next() {
+		var segment SearchQuerySegment
+		if err := rows.Scan(&segment.SearchQueryID, &segment.SegmentIndex, &segment.Origin, &segment.Destination, &segment.DepartureDate); err != nil {
+			return nil, fmt.Errorf("error scanning segment of search query %d: %w", searchQueryID, err)
+		}
+		segments = append(segments, segment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating segments of search query %d: %w", searchQueryID, err)
+	}
+	return segments, nil
+}
+
 func (p *PostgresDBImpl) CountSearches(ctx context.Context) (int, error) {
 	var total int
 	err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM search_queries").Scan(&total)
@@ -580,7 +627,7 @@ func (p *PostgresDBImpl) QueryBulkSearchResultsPaginated(ctx context.Context, se
 			price, currency, airline_code, duration,
 			src_airport_code, dst_airport_code, src_city, dst_city,
 			flight_duration, return_flight_duration,
-			outbound_flights, return_flights, offer_json
+			outbound_flights, return_flights, offer_json, segment_flights
 		FROM bulk_search_results
 		WHERE bulk_search_id = $1
 		ORDER BY price ASC
@@ -739,6 +786,14 @@ func (p *PostgresDBImpl) FinalizeBulkSearch(ctx context.Context, bulkSearchID in
 	return nil
 }
 
+// nullJSON stores an empty JSON document as NULL.
+func nullJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
+
 func (p *PostgresDBImpl) InsertBulkSearchOffer(ctx context.Context, record BulkSearchOfferRecord) error {
 	airlineCodes := pq.Array(record.AirlineCodes)
 	_, err := p.db.ExecContext(ctx,
@@ -747,8 +802,10 @@ func (p *PostgresDBImpl) InsertBulkSearchOffer(ctx context.Context, record BulkS
 			 price, currency, airline_codes, src_airport_code, dst_airport_code,
 			 src_city, dst_city, flight_duration, return_flight_duration,
 			 distance_miles, cost_per_mile,
-			 outbound_flights, return_flights, offer_json)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`,
+			 outbound_flights, return_flights, offer_json,
+			 normalized_price, normalized_currency, normalized_cost_per_mile, segment_flights)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
+			 $20, $21, $22, $23)`,
 		record.BulkSearchID,
 		record.Origin,
 		record.Destination,
@@ -768,6 +825,10 @@ func (p *PostgresDBImpl) InsertBulkSearchOffer(ctx context.Context, record BulkS
 		record.OutboundFlightsJSON,
 		record.ReturnFlightsJSON,
 		record.OfferJSON,
+		record.NormalizedPrice,
+		record.NormalizedCurrency,
+		record.NormalizedCostPerMile,
+		nullJSON(record.SegmentFlightsJSON),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert bulk search offer: %w", err)
@@ -794,7 +855,8 @@ func (p *PostgresDBImpl) ListBulkSearchOffers(ctx context.Context, searchID int)
 		`SELECT id, bulk_search_id, origin, destination, departure_date, return_date, price, currency,
 			airline_codes, src_airport_code, dst_airport_code, src_city, dst_city,
 			flight_duration, return_flight_duration, distance_miles, cost_per_mile,
-			outbound_flights, return_flights, offer_json, created_at
+			outbound_flights, return_flights, offer_json, created_at,
+			normalized_price, normalized_currency, normalized_cost_per_mile, segment_flights
 		 FROM bulk_search_offers
 		 WHERE bulk_search_id = $1
 		 ORDER BY origin, destination, departure_date, return_date NULLS FIRST, price ASC, created_at ASC`,
@@ -808,8 +870,9 @@ func (p *PostgresDBImpl) ListBulkSearchOffers(ctx context.Context, searchID int)
 	var offers []BulkSearchOffer
 	for rows.Next() {
 		var (
-			offer        BulkSearchOffer
-			airlineCodes pq.StringArray
+			offer          BulkSearchOffer
+			airlineCodes   pq.StringArray
+			segmentFlights []byte
 		)
 
 		if scanErr := rows.Scan(
@@ -834,11 +897,16 @@ func (p *PostgresDBImpl) ListBulkSearchOffers(ctx context.Context, searchID int)
 			&offer.ReturnFlights,
 			&offer.OfferJSON,
 			&offer.CreatedAt,
+			&offer.NormalizedPrice,
+			&offer.NormalizedCurrency,
+			&offer.NormalizedCostPerMile,
+			&segmentFlights,
 		); scanErr != nil {
 			return nil, fmt.Errorf("failed to scan bulk search offer: %w", scanErr)
 		}
 
 		offer.AirlineCodes = append([]string(nil), airlineCodes...)
+		offer.SegmentFlights = segmentFlights
 		offers = append(offers, offer)
 	}
 
@@ -869,9 +937,10 @@ func (p *PostgresDBImpl) InsertBulkSearchResult(ctx context.Context, result Bulk
 			 price, currency, airline_code, duration,
 			 src_airport_code, dst_airport_code, src_city, dst_city,
 			 flight_duration, return_flight_duration,
-			 outbound_flights, return_flights, offer_json)
+			 outbound_flights, return_flights, offer_json,
+			 normalized_price, normalized_currency, segment_flights)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9,
-			 $10, $11, $12, $13, $14, $15, $16, $17, $18)`,
+			 $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`,
 		result.BulkSearchID,
 		result.Origin,
 		result.Destination,
@@ -890,6 +959,9 @@ func (p *PostgresDBImpl) InsertBulkSearchResult(ctx context.Context, result Bulk
 		outboundJSON,
 		returnJSON,
 		offerJSON,
+		result.NormalizedPrice,
+		result.NormalizedCurrency,
+		nullJSON(result.SegmentFlightsJSON),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert bulk search result: %w", err)
@@ -912,8 +984,8 @@ func (p *PostgresDBImpl) InsertBulkSearchResultsBatch(ctx context.Context, resul
 	defer tx.Rollback()
 
 	// Build multi-row INSERT statement
-	// 18 fields per row
-	const numFields = 18
+	// 21 fields per row
+	const numFields = 21
 	valueStrings := make([]string, 0, len(results))
 	valueArgs := make([]interface{}, 0, len(results)*numFields)
 
@@ -934,9 +1006,10 @@ func (p *PostgresDBImpl) InsertBulkSearchResultsBatch(ctx context.Context, resul
 		}
 
 		valueStrings = append(valueStrings, fmt.Sprintf(
-			"($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
+			"($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
 			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8, base+9,
 			base+10, base+11, base+12, base+13, base+14, base+15, base+16, base+17, base+18,
+			base+19, base+20, base+21,
 		))
 		valueArgs = append(valueArgs,
 			result.BulkSearchID,
@@ -957,6 +1030,9 @@ func (p *PostgresDBImpl) InsertBulkSearchResultsBatch(ctx context.Context, resul
 			outboundJSON,
 			returnJSON,
 			offerJSON,
+			result.NormalizedPrice,
+			result.NormalizedCurrency,
+			nullJSON(result.SegmentFlightsJSON),
 		)
 	}
 
@@ -965,7 +1041,8 @@ func (p *PostgresDBImpl) InsertBulkSearchResultsBatch(ctx context.Context, resul
 		 price, currency, airline_code, duration,
 		 src_airport_code, dst_airport_code, src_city, dst_city,
 		 flight_duration, return_flight_duration,
-		 outbound_flights, return_flights, offer_json)
+		 outbound_flights, return_flights, offer_json,
+		 normalized_price, normalized_currency, segment_flights)
 	VALUES ` + strings.Join(valueStrings, ",")
 
 	_, err = tx.ExecContext(ctx, query, valueArgs...)
@@ -1096,16 +1173,21 @@ func (p *PostgresDBImpl) InsertPriceGraphResult(ctx context.Context, record Pric
 				(sweep_id, origin, destination, departure_date, return_date,
 				 trip_length, price, currency, distance_miles, cost_per_mile,
 				 adults, children, infants_lap, infants_seat, trip_type, class, stops, search_url,
-				 queried_at)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
-			 ON CONFLICT (sweep_id, origin, destination, departure_date, trip_length, currency, adults, children, infants_lap, infants_seat, trip_type, class, stops)
+				 queried_at, normalized_price, normalized_currency, normalized_cost_per_mile,
+				 return_origin, return_destination)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
+				 $20, $21, $22, $23, $24)
+			 ON CONFLICT (sweep_id, origin, destination, return_origin, return_destination, departure_date, trip_length, currency, adults, children, infants_lap, infants_seat, trip_type, class, stops)
 			 DO UPDATE SET
 				return_date = EXCLUDED.return_date,
 				price = EXCLUDED.price,
 				distance_miles = COALESCE(EXCLUDED.distance_miles, price_graph_results.distance_miles),
 				cost_per_mile = COALESCE(EXCLUDED.cost_per_mile, price_graph_results.cost_per_mile),
 				search_url = COALESCE(EXCLUDED.search_url, price_graph_results.search_url),
-				queried_at = EXCLUDED.queried_at`,
+				queried_at = EXCLUDED.queried_at,
+				normalized_price = EXCLUDED.normalized_price,
+				normalized_currency = EXCLUDED.normalized_currency,
+				normalized_cost_per_mile = EXCLUDED.normalized_cost_per_mile`,
 		record.SweepID,
 		record.Origin,
 		record.Destination,
@@ -1125,6 +1207,11 @@ func (p *PostgresDBImpl) InsertPriceGraphResult(ctx context.Context, record Pric
 		record.Stops,
 		record.SearchURL,
 		record.QueriedAt,
+		record.NormalizedPrice,
+		record.NormalizedCurrency,
+		record.NormalizedCostPerMile,
+		record.ReturnOrigin,
+		record.ReturnDestination,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert price graph result for %s -> %s: %w", record.Origin, record.Destination, err)
@@ -1143,7 +1230,7 @@ func (p *PostgresDBImpl) ListPriceGraphResults(ctx context.Context, sweepID, lim
 		`SELECT id, sweep_id, origin, destination, departure_date,
 	                return_date, trip_length, price, currency,
 					adults, children, infants_lap, infants_seat, trip_type, class, stops, search_url,
-					queried_at, created_at
+					queried_at, created_at, return_origin, return_destination
 	         FROM price_graph_results
 	         WHERE sweep_id = $1
 	         ORDER BY price ASC
@@ -1158,8 +1245,8 @@ func (p *PostgresDBImpl) SaveContinuousSweepProgress(ctx context.Context, progre
 		`INSERT INTO continuous_sweep_progress
 			(id, sweep_number, route_index, total_routes, current_origin, current_destination,
 			 queries_completed, errors_count, last_error, sweep_started_at, last_updated,
-			 trip_lengths, pacing_mode, target_duration_hours, min_delay_ms, is_running, is_paused, international_only)
-		 VALUES (1, $1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), $10, $11, $12, $13, $14, $15, $16)
+			 trip_lengths, pacing_mode, target_duration_hours, min_delay_ms, is_running, is_paused, international_only, origins)
+		 VALUES (1, $1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), $10, $11, $12, $13, $14, $15, $16, $17)
 		 ON CONFLICT (id) DO UPDATE SET
 			sweep_number = $1,
 			route_index = $2,
@@ -1179,7 +1266,8 @@ func (p *PostgresDBImpl) SaveContinuousSweepProgress(ctx context.Context, progre
 			-- by periodic progress saves (otherwise STOP/PAUSE can be clobbered by a running worker).
 			is_running = continuous_sweep_progress.is_running,
 			is_paused = continuous_sweep_progress.is_paused,
-			international_only = $16`,
+			international_only = $16,
+			origins = $17`,
 		progress.SweepNumber,
 		progress.RouteIndex,
 		progress.TotalRoutes,
@@ -1196,6 +1284,7 @@ func (p *PostgresDBImpl) SaveContinuousSweepProgress(ctx context.Context, progre
 		progress.IsRunning,
 		progress.IsPaused,
 		progress.InternationalOnly,
+		pq.Array(progress.Origins),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save continuous sweep progress: %w", err)
@@ -1236,7 +1325,7 @@ func (p *PostgresDBImpl) GetContinuousSweepProgress(ctx context.Context) (*Conti
 		`SELECT id, sweep_number, route_index, total_routes, current_origin, current_destination,
 		        queries_completed, errors_count, last_error, sweep_started_at, last_updated,
 		        COALESCE(trip_lengths, '{7,14}'), pacing_mode, target_duration_hours, min_delay_ms, is_running, is_paused,
-		        COALESCE(international_only, TRUE)
+		        COALESCE(international_only, TRUE), COALESCE(origins, '{}')
 		 FROM continuous_sweep_progress
 		 WHERE id = 1`,
 	).Scan(
@@ -1258,6 +1347,7 @@ func (p *PostgresDBImpl) GetContinuousSweepProgress(ctx context.Context) (*Conti
 		&progress.IsRunning,
 		&progress.IsPaused,
 		&progress.InternationalOnly,
+		(*pq.StringArray)(&progress.Origins),
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -1441,22 +1531,149 @@ func (p *PostgresDBImpl) ListContinuousSweepResults(ctx context.Context, filters
 	return results, nil
 }
 
+// --- Export Methods ---
+//
+// The export queries return unpaginated rows in insertion order, to be streamed by the caller;
+// their columns are listed in the order of the SELECT.
+
+// conditions appends the SQL conditions of the filter to query, numbering the parameters after
+// the existing args.
+func (f ExportFilter) conditions(query string, args []interface{}) (string, []interface{}) {
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		query += fmt.Sprintf(" AND "+condition, len(args))
+	}
+	if f.Origin != "" {
+		add("origin = $%d", f.Origin)
+	}
+	if f.Destination != "" {
+		add("destination = $%d", f.Destination)
+	}
+	if !f.FromDate.IsZero() {
+		add("departure_date >= $%d", f.FromDate)
+	}
+	if !f.ToDate.IsZero() {
+		add("departure_date <= $%d", f.ToDate)
+	}
+	return query, args
+}
+
+// ExportBulkSearchResults streams the results of a bulk search: origin, destination,
+// departure_date, return_date, price, currency, normalized_price, normalized_currency,
+// airline_code, duration, src_airport_code, dst_airport_code, src_city, dst_city,
+// flight_duration, return_flight_duration, outbound_flights, return_flights, segment_flights
+// and created_at.
+func (p *PostgresDBImpl) ExportBulkSearchResults(ctx context.Context, searchID int, filter ExportFilter) (Rows, error) {
+	query, args := filter.conditions(
+		`SELECT origin, destination, departure_date, return_date, price, currency,
+			normalized_price, normalized_currency, airline_code, duration,
+			src_airport_code, dst_airport_code, src_city, dst_city,
+			flight_duration, return_flight_duration,
+			outbound_flights, return_flights, segment_flights, created_at
+		FROM bulk_search_results
+		WHERE bulk_search_id = $1`,
+		[]interface{}{searchID},
+	)
+	rows, err := p.db.QueryContext(ctx, query+" ORDER BY id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export bulk search results: %w", err)
+	}
+	return rows, nil
+}
+
+// ExportBulkSearchOffers streams the offers of a bulk search: origin, destination,
+// departure_date, return_date, price, currency, normalized_price, normalized_currency,
+// airline_codes (comma-separated), src_airport_code, dst_airport_code, src_city, dst_city,
+// flight_duration, return_flight_duration, distance_miles, cost_per_mile,
+// normalized_cost_per_mile, outbound_flights, return_flights, segment_flights and created_at.
+func (p *PostgresDBImpl) ExportBulkSearchOffers(ctx context.Context, searchID int, filter ExportFilter) (Rows, error) {
+	query, args := filter.conditions(
+		`SELECT origin, destination, departure_date, return_date, price, currency,
+			normalized_price, normalized_currency, array_to_string(airline_codes, ','),
+			src_airport_code, dst_airport_code, src_city, dst_city,
+			flight_duration, return_flight_duration, distance_miles, cost_per_mile,
+			normalized_cost_per_mile, outbound_flights, return_flights, segment_flights, created_at
+		FROM bulk_search_offers
+		WHERE bulk_search_id = $1`,
+		[]interface{}{searchID},
+	)
+	rows, err := p.db.QueryContext(ctx, query+" ORDER BY id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export bulk search offers: %w", err)
+	}
+	return rows, nil
+}
+
+// ExportPriceGraphResults streams the fares of a price graph sweep, or of the continuous sweep
+// for sweep ID 0: sweep_id, origin, destination, return_origin, return_destination,
+// departure_date, return_date, trip_length, price, currency, normalized_price,
+// normalized_currency, distance_miles, cost_per_mile, adults, children, infants_lap,
+// infants_seat, trip_type, class, stops, search_url and queried_at.
+func (p *PostgresDBImpl) ExportPriceGraphResults(ctx context.Context, sweepID int, filter ExportFilter) (Rows, error) {
+	query, args := filter.conditions(
+		`SELECT sweep_id, origin, destination, NULLIF(return_origin, ''), NULLIF(return_destination, ''),
+			departure_date, return_date, trip_length, price, currency,
+			normalized_price, normalized_currency, distance_miles, cost_per_mile,
+			adults, children, infants_lap, infants_seat, trip_type, class, stops, search_url, queried_at
+		FROM price_graph_results
+		WHERE sweep_id = $1`,
+		[]interface{}{sweepID},
+	)
+	rows, err := p.db.QueryContext(ctx, query+" ORDER BY id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export price graph results: %w", err)
+	}
+	return rows, nil
+}
+
+// ExportDetectedDeals streams the detected deals of every status unless filter.Status is set:
+// id, origin, destination, departure_date, return_date, trip_length, price, currency,
+// normalized_price, normalized_currency, baseline_mean, baseline_median, discount_percent,
+// deal_score, deal_classification, distance_miles, cost_per_mile, cabin_class, source_type,
+// search_url, status, verified, verified_price, first_seen_at, last_seen_at, times_seen and
+// expires_at.
+func (p *PostgresDBImpl) ExportDetectedDeals(ctx context.Context, filter ExportFilter) (Rows, error) {
+	query, args := filter.conditions(
+		`SELECT id, origin, destination, departure_date, return_date, trip_length, price, currency,
+			normalized_price, normalized_currency, baseline_mean, baseline_median, discount_percent,
+			deal_score, deal_classification, distance_miles, cost_per_mile, cabin_class, source_type,
+			search_url, status, verified, verified_price, first_seen_at, last_seen_at, times_seen,
+			expires_at
+		FROM detected_deals
+		WHERE 1=1`,
+		nil,
+	)
+	if filter.Classification != "" {
+		args = append(args, filter.Classification)
+		query += fmt.Sprintf(" AND deal_classification = $%d", len(args))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	rows, err := p.db.QueryContext(ctx, query+" ORDER BY id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export detected deals: %w", err)
+	}
+	return rows, nil
+}
+
 // --- Deal Detection Methods ---
 
-// GetRouteBaseline retrieves price baseline for a route
-func (p *PostgresDBImpl) GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class string) (*RouteBaseline, error) {
+// GetRouteBaseline retrieves the price baseline of a route in the given currency
+func (p *PostgresDBImpl) GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class, currency string) (*RouteBaseline, error) {
 	var baseline RouteBaseline
 	err := p.db.QueryRowContext(ctx,
-		`SELECT id, origin, destination, trip_length, class, sample_count,
+		`SELECT id, origin, destination, trip_length, class, currency, sample_count,
 		        mean_price, median_price, stddev_price, min_price, max_price,
 		        p10_price, p25_price, p75_price, p90_price,
 		        window_start, window_end, updated_at, created_at
 		 FROM route_baselines
-		 WHERE origin = $1 AND destination = $2 AND trip_length = $3 AND class = $4`,
-		origin, dest, tripLength, class,
+		 WHERE origin = $1 AND destination = $2 AND trip_length = $3 AND class = $4 AND currency = $5`,
+		origin, dest, tripLength, class, currency,
 	).Scan(
 		&baseline.ID, &baseline.Origin, &baseline.Destination, &baseline.TripLength, &baseline.Class,
-		&baseline.SampleCount, &baseline.MeanPrice, &baseline.MedianPrice, &baseline.StddevPrice,
+		&baseline.Currency, &baseline.SampleCount, &baseline.MeanPrice, &baseline.MedianPrice, &baseline.StddevPrice,
 		&baseline.MinPrice, &baseline.MaxPrice, &baseline.P10Price, &baseline.P25Price,
 		&baseline.P75Price, &baseline.P90Price, &baseline.WindowStart, &baseline.WindowEnd,
 		&baseline.UpdatedAt, &baseline.CreatedAt,
@@ -1476,16 +1693,18 @@ func (p *PostgresDBImpl) UpsertRouteBaseline(ctx context.Context, baseline Route
 		`INSERT INTO route_baselines (origin, destination, trip_length, class, sample_count,
 		                              mean_price, median_price, stddev_price, min_price, max_price,
 		                              p10_price, p25_price, p75_price, p90_price,
-		                              window_start, window_end, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW())
-		 ON CONFLICT (origin, destination, trip_length, class) DO UPDATE SET
+		                              window_start, window_end, currency, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NOW())
+		 ON CONFLICT (origin, destination, trip_length, class, currency) DO UPDATE SET
 		    sample_count = $5, mean_price = $6, median_price = $7, stddev_price = $8,
 		    min_price = $9, max_price = $10, p10_price = $11, p25_price = $12,
-		    p75_price = $13, p90_price = $14, window_start = $15, window_end = $16, updated_at = NOW()`,
+		    p75_price = $13, p90_price = $14, window_start = $15, window_end = $16,
+		    updated_at = NOW()`,
 		baseline.Origin, baseline.Destination, baseline.TripLength, baseline.Class,
 		baseline.SampleCount, baseline.MeanPrice, baseline.MedianPrice, baseline.StddevPrice,
 		baseline.MinPrice, baseline.MaxPrice, baseline.P10Price, baseline.P25Price,
 		baseline.P75Price, baseline.P90Price, baseline.WindowStart, baseline.WindowEnd,
+		baseline.Currency,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to upsert route baseline: %w", err)
@@ -1493,14 +1712,18 @@ func (p *PostgresDBImpl) UpsertRouteBaseline(ctx context.Context, baseline Route
 	return nil
 }
 
-// GetPriceHistoryForRoute retrieves historical prices for baseline calculation
-func (p *PostgresDBImpl) GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class string, windowDays int) ([]float64, error) {
-	query := `SELECT price FROM price_graph_results
+// GetPriceHistoryForRoute retrieves historical prices for baseline calculation in the given
+// currency: prices normalized to it, or prices quoted in it that were never normalized
+func (p *PostgresDBImpl) GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class, currency string, windowDays int) ([]float64, error) {
+	query := `SELECT CASE WHEN normalized_currency = $5 THEN normalized_price ELSE price END AS price
+		 FROM price_graph_results
 		 WHERE origin = $1 AND destination = $2 
 		   AND (trip_length = $3 OR $3 = 0)
-		   AND class = $4`
-	args := []interface{}{origin, dest, tripLength, class}
-	argIdx := 5
+		   AND class = $4
+		   AND (normalized_currency = $5 OR (normalized_currency IS NULL AND currency = $5))
+		   AND return_origin = ''`
+	args := []interface{}{origin, dest, tripLength, class, currency}
+	argIdx := 6
 
 	if windowDays > 0 {
 		query += fmt.Sprintf(" AND queried_at >= NOW() - INTERVAL '1 day' * $%d", argIdx)
@@ -1508,7 +1731,7 @@ func (p *PostgresDBImpl) GetPriceHistoryForRoute(ctx context.Context, origin, de
 		argIdx++
 	}
 
-	query += " AND price > 0 ORDER BY queried_at DESC"
+	query += " AND price > 0 AND COALESCE(normalized_price, price) > 0 ORDER BY queried_at DESC"
 
 	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -1536,16 +1759,16 @@ func (p *PostgresDBImpl) InsertDetectedDeal(ctx context.Context, deal DetectedDe
 		                             deal_score, deal_classification, distance_miles, cost_per_mile,
 		                             cabin_class, source_type, source_id, search_url, deal_fingerprint,
 		                             first_seen_at, last_seen_at, times_seen, status, verified,
-		                             verified_price, verified_at, expires_at)
+		                             verified_price, verified_at, expires_at, normalized_price, normalized_currency)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
-		         $20, $21, $22, $23, $24, $25, $26, $27)
+		         $20, $21, $22, $23, $24, $25, $26, $27, $28, $29)
 		 RETURNING id`,
 		deal.Origin, deal.Destination, deal.DepartureDate, deal.ReturnDate, deal.TripLength,
 		deal.Price, deal.Currency, deal.BaselineMean, deal.BaselineMedian, deal.DiscountPercent,
 		deal.DealScore, deal.DealClassification, deal.DistanceMiles, deal.CostPerMile,
 		deal.CabinClass, deal.SourceType, deal.SourceID, deal.SearchURL, deal.DealFingerprint,
 		deal.FirstSeenAt, deal.LastSeenAt, deal.TimesSeen, deal.Status, deal.Verified,
-		deal.VerifiedPrice, deal.VerifiedAt, deal.ExpiresAt,
+		deal.VerifiedPrice, deal.VerifiedAt, deal.ExpiresAt, deal.NormalizedPrice, deal.NormalizedCurrency,
 	).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert detected deal: %w", err)
@@ -1560,20 +1783,23 @@ func (p *PostgresDBImpl) UpsertDetectedDeal(ctx context.Context, deal DetectedDe
 		                             price, currency, baseline_mean, baseline_median, discount_percent,
 		                             deal_score, deal_classification, distance_miles, cost_per_mile,
 		                             cabin_class, source_type, source_id, search_url, deal_fingerprint,
-		                             first_seen_at, last_seen_at, times_seen, status, expires_at)
+		                             first_seen_at, last_seen_at, times_seen, status, expires_at,
+		                             normalized_price, normalized_currency)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
-		         NOW(), NOW(), 1, $20, $21)
+		         NOW(), NOW(), 1, $20, $21, $22, $23)
 		 ON CONFLICT (deal_fingerprint) DO UPDATE SET
 		    last_seen_at = NOW(),
 		    times_seen = detected_deals.times_seen + 1,
 		    price = LEAST(detected_deals.price, $6),
+		    normalized_price = LEAST(detected_deals.normalized_price, $22),
+		    normalized_currency = COALESCE(detected_deals.normalized_currency, $23),
 		    deal_score = GREATEST(detected_deals.deal_score, $11),
 		    updated_at = NOW()`,
 		deal.Origin, deal.Destination, deal.DepartureDate, deal.ReturnDate, deal.TripLength,
 		deal.Price, deal.Currency, deal.BaselineMean, deal.BaselineMedian, deal.DiscountPercent,
 		deal.DealScore, deal.DealClassification, deal.DistanceMiles, deal.CostPerMile,
 		deal.CabinClass, deal.SourceType, deal.SourceID, deal.SearchURL, deal.DealFingerprint,
-		deal.Status, deal.ExpiresAt,
+		deal.Status, deal.ExpiresAt, deal.NormalizedPrice, deal.NormalizedCurrency,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to upsert detected deal: %w", err)
@@ -1590,7 +1816,8 @@ func (p *PostgresDBImpl) GetDetectedDealByFingerprint(ctx context.Context, finge
 		        deal_score, deal_classification, distance_miles, cost_per_mile,
 		        cabin_class, source_type, source_id, search_url, deal_fingerprint,
 		        first_seen_at, last_seen_at, times_seen, status, verified,
-		        verified_price, verified_at, expires_at, created_at, updated_at
+		        verified_price, verified_at, expires_at, created_at, updated_at,
+		        normalized_price, normalized_currency
 		 FROM detected_deals WHERE deal_fingerprint = $1`,
 		fingerprint,
 	).Scan(
@@ -1600,6 +1827,7 @@ func (p *PostgresDBImpl) GetDetectedDealByFingerprint(ctx context.Context, finge
 		&deal.CostPerMile, &deal.CabinClass, &deal.SourceType, &deal.SourceID, &deal.SearchURL,
 		&deal.DealFingerprint, &deal.FirstSeenAt, &deal.LastSeenAt, &deal.TimesSeen, &deal.Status,
 		&deal.Verified, &deal.VerifiedPrice, &deal.VerifiedAt, &deal.ExpiresAt, &deal.CreatedAt, &deal.UpdatedAt,
+		&deal.NormalizedPrice, &deal.NormalizedCurrency,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -1617,7 +1845,8 @@ func (p *PostgresDBImpl) ListActiveDeals(ctx context.Context, filter DealFilter)
 	                 deal_score, deal_classification, distance_miles, cost_per_mile,
 	                 cabin_class, source_type, source_id, search_url, deal_fingerprint,
 	                 first_seen_at, last_seen_at, times_seen, status, verified,
-	                 verified_price, verified_at, expires_at, created_at, updated_at
+	                 verified_price, verified_at, expires_at, created_at, updated_at,
+	                 normalized_price, normalized_currency
 	          FROM detected_deals WHERE 1=1`
 	args := []interface{}{}
 	argIdx := 1
@@ -1670,6 +1899,7 @@ func (p *PostgresDBImpl) ListActiveDeals(ctx context.Context, filter DealFilter)
 			&deal.CostPerMile, &deal.CabinClass, &deal.SourceType, &deal.SourceID, &deal.SearchURL,
 			&deal.DealFingerprint, &deal.FirstSeenAt, &deal.LastSeenAt, &deal.TimesSeen, &deal.Status,
 			&deal.Verified, &deal.VerifiedPrice, &deal.VerifiedAt, &deal.ExpiresAt, &deal.CreatedAt, &deal.UpdatedAt,
+			&deal.NormalizedPrice, &deal.NormalizedCurrency,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan deal: %w", err)
 		}
@@ -1748,6 +1978,205 @@ func (p *PostgresDBImpl) ListDealAlerts(ctx context.Context, limit, offset int)
 	return alerts, nil
 }
 
+// CreatePosComparison stores a queued point-of-sale comparison and returns its ID
+func (p *PostgresDBImpl) CreatePosComparison(ctx context.Context, comparison PosComparison) (int, error) {
+	if comparison.Status == "" {
+		comparison.Status = "queued"
+	}
+	if comparison.Currency == "" {
+		comparison.Currency = "USD"
+	}
+	var id int
+	err := p.db.QueryRowContext(ctx,
+		`INSERT INTO pos_comparisons
+			(status, origin, destination, departure_date, return_date, trip_type, class, stops,
+			 adults, children, infants_lap, infants_seat, currency, market_count)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		 RETURNING id`,
+		comparison.Status, comparison.Origin, comparison.Destination, comparison.DepartureDate, comparison.ReturnDate,
+		comparison.TripType, comparison.Class, comparison.Stops,
+		comparison.Adults, comparison.Children, comparison.InfantsLap, comparison.InfantsSeat,
+		comparison.Currency, comparison.MarketCount,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create pos comparison: %w", err)
+	}
+	return id, nil
+}
+
+// UpdatePosComparisonStatus sets the status of a point-of-sale comparison
+func (p *PostgresDBImpl) UpdatePosComparisonStatus(ctx context.Context, comparisonID int, status string) error {
+	_, err := p.db.ExecContext(ctx,
+		`UPDATE pos_comparisons SET status = $1, updated_at = NOW() WHERE id = $2`,
+		status, comparisonID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update pos comparison %d status: %w", comparisonID, err)
+	}
+	return nil
+}
+
+// CompletePosComparison stores the outcome of a point-of-sale comparison
+func (p *PostgresDBImpl) CompletePosComparison(ctx context.Context, summary PosComparisonSummary) error {
+	_, err := p.db.ExecContext(ctx,
+		`UPDATE pos_comparisons
+         SET status = $1,
+             cheapest_country = $2,
+             cheapest_price = $3,
+             savings = $4,
+             error_message = $5,
+             completed_at = NOW(),
+             updated_at = NOW()
+         WHERE id = $6`,
+		summary.Status, summary.CheapestCountry, summary.CheapestPrice, summary.Savings,
+		summary.ErrorMessage, summary.ComparisonID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete pos comparison %d: %w", summary.ComparisonID, err)
+	}
+	return nil
+}
+
+// GetPosComparisonByID retrieves a point-of-sale comparison
+func (p *PostgresDBImpl) GetPosComparisonByID(ctx context.Context, comparisonID int) (*PosComparison, error) {
+	var c PosComparison
+	err := p.db.QueryRowContext(ctx,
+		`SELECT id, status, origin, destination, departure_date, return_date, trip_type, class, stops,
+                adults, children, infants_lap, infants_seat, currency, market_count,
+                cheapest_country, cheapest_price, savings, error_message,
+                created_at, updated_at, completed_at
+         FROM pos_comparisons
+         WHERE id = $1`,
+		comparisonID,
+	).Scan(&c.ID, &c.Status, &c.Origin, &c.Destination, &c.DepartureDate, &c.ReturnDate,
+		&c.TripType, &c.Class, &c.Stops, &c.Adults, &c.Children, &c.InfantsLap, &c.InfantsSeat,
+		&c.Currency, &c.MarketCount, &c.CheapestCountry, &c.CheapestPrice, &c.Savings, &c.ErrorMessage,
+		&c.CreatedAt, &c.UpdatedAt, &c.CompletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("pos comparison with ID %d not found", comparisonID)
+		}
+		return nil, fmt.Errorf("error getting pos comparison %d: %w", comparisonID, err)
+	}
+	return &c, nil
+}
+
+// InsertPosComparisonResult stores the result of one market of a point-of-sale comparison
+func (p *PostgresDBImpl) InsertPosComparisonResult(ctx context.Context, result PosComparisonResult) error {
+	_, err := p.db.ExecContext(ctx,
+		`INSERT INTO pos_comparison_results
+			(comparison_id, country, google_host, currency, price, normalized_price,
+			 airline_codes, flight_numbers, search_url, error_message)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		result.ComparisonID, result.Country, result.GoogleHost, result.Currency, result.Price, result.NormalizedPrice,
+		pq.Array(result.AirlineCodes), pq.Array(result.FlightNumbers), result.SearchURL, result.ErrorMessage,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert pos comparison result: %w", err)
+	}
+	return nil
+}
+
+// ListPosComparisonResults retrieves the market results of a point-of-sale comparison, cheapest first
+func (p *PostgresDBImpl) ListPosComparisonResults(ctx context.Context, comparisonID int) ([]PosComparisonResult, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT id, comparison_id, country, google_host, currency, price, normalized_price,
+		        airline_codes, flight_numbers, search_url, error_message, created_at
+		 FROM pos_comparison_results
+		 WHERE comparison_id = $1
+		 ORDER BY normalized_price ASC NULLS LAST, country ASC`,
+		comparisonID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pos comparison results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []PosComparisonResult
+	for rows.Next() {
+		var (
+			result        PosComparisonResult
+			airlineCodes  pq.StringArray
+			flightNumbers pq.StringArray
+		)
+		if err := rows.Scan(
+			&result.ID, &result.ComparisonID, &result.Country, &result.GoogleHost, &result.Currency,
+			&result.Price, &result.NormalizedPrice, &airlineCodes, &flightNumbers,
+			&result.SearchURL, &result.ErrorMessage, &result.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan pos comparison result: %w", err)
+		}
+		result.AirlineCodes = append([]string(nil), airlineCodes...)
+		result.FlightNumbers = append([]string(nil), flightNumbers...)
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pos comparison results: %w", err)
+	}
+	return results, nil
+}
+
+// --- Exchange Rate Methods ---
+
+// GetFXRates returns the latest exchange rate snapshot stored on or before day
+func (p *PostgresDBImpl) GetFXRates(ctx context.Context, day time.Time) ([]FXRate, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT rate_date, base_currency, quote_currency, rate, source, fetched_at
+		 FROM fx_rates
+		 WHERE rate_date = (SELECT MAX(rate_date) FROM fx_rates WHERE rate_date <= $1)
+		 ORDER BY base_currency, quote_currency`,
+		day,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fx rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []FXRate
+	for rows.Next() {
+		var rate FXRate
+		if err := rows.Scan(&rate.RateDate, &rate.BaseCurrency, &rate.QuoteCurrency, &rate.Rate,
+			&rate.Source, &rate.FetchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan fx rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating fx rates: %w", err)
+	}
+	return rates, nil
+}
+
+// UpsertFXRates stores an exchange rate snapshot, replacing the rates already stored for its day
+func (p *PostgresDBImpl) UpsertFXRates(ctx context.Context, rates []FXRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for fx rates: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, rate := range rates {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO fx_rates (rate_date, base_currency, quote_currency, rate, source, fetched_at)
+			 VALUES ($1, $2, $3, $4, $5, NOW())
+			 ON CONFLICT (rate_date, base_currency, quote_currency) DO UPDATE SET
+			    rate = EXCLUDED.rate, source = EXCLUDED.source, fetched_at = NOW()`,
+			rate.RateDate, rate.BaseCurrency, rate.QuoteCurrency, rate.Rate, rate.Source,
+		); err != nil {
+			return fmt.Errorf("failed to upsert fx rate %s/%s: %w", rate.BaseCurrency, rate.QuoteCurrency, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fx rates: %w", err)
+	}
+	return nil
+}
+
 // --- End Implementation ---
 
 // GetDB returns the underlying database connection
@@ -2187,7 +2616,8 @@ func (p *PostgresDBImpl) InitSchema() error {
             min_delay_ms INTEGER DEFAULT 3000,
             is_running BOOLEAN DEFAULT FALSE,
             is_paused BOOLEAN DEFAULT FALSE,
-            international_only BOOLEAN DEFAULT TRUE
+            international_only BOOLEAN DEFAULT TRUE,
+            origins TEXT[] DEFAULT '{}'
         )
     `)
 	if err != nil {
@@ -2202,6 +2632,14 @@ func (p *PostgresDBImpl) InitSchema() error {
 		return fmt.Errorf("failed to add trip_lengths to continuous_sweep_progress: %w", err)
 	}
 
+	_, err = p.db.Exec(`
+        ALTER TABLE continuous_sweep_progress
+        ADD COLUMN IF NOT EXISTS origins TEXT[] DEFAULT '{}'
+    `)
+	if err != nil {
+		return fmt.Errorf("failed to add origins to continuous_sweep_progress: %w", err)
+	}
+
 	// Insert default progress row if not exists
 	_, err = p.db.Exec(`
         INSERT INTO continuous_sweep_progress (id, sweep_number, route_index, total_routes)
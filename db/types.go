@@ -47,10 +47,20 @@ type SearchQuery struct {
 	Destination   string
 	DepartureDate time.Time
 	ReturnDate    sql.NullTime
+	TripType      string
 	Status        string
 	CreatedAt     time.Time
 }
 
+// SearchQuerySegment is a requested segment of a multi-city search query
+type SearchQuerySegment struct {
+	SearchQueryID int
+	SegmentIndex  int
+	Origin        string
+	Destination   string
+	DepartureDate time.Time
+}
+
 // FlightOffer represents the data structure for a single flight offer row
 type FlightOffer struct {
 	ID               int
@@ -78,6 +88,7 @@ type FlightSegment struct {
 	Airplane         string
 	Legroom          string
 	IsReturn         bool
+	SegmentIndex     int // segment of a multi-city search, 0 otherwise
 }
 
 // ScheduledJob represents the data structure for a scheduled job row
@@ -152,6 +163,9 @@ type BulkSearchResult struct {
 	OutboundFlights      json.RawMessage
 	ReturnFlights        json.RawMessage
 	OfferJSON            json.RawMessage
+
+	// Flights of every segment of a multi-city search
+	SegmentFlights json.RawMessage
 }
 
 // BulkSearchSummary represents aggregated results for a bulk search run
@@ -229,6 +243,15 @@ type PriceGraphResultRecord struct {
 	Stops         string
 	SearchURL     sql.NullString
 	QueriedAt     time.Time
+
+	// Price and cost per mile in the reporting currency
+	NormalizedPrice       sql.NullFloat64
+	NormalizedCurrency    sql.NullString
+	NormalizedCostPerMile sql.NullFloat64
+
+	// Second flight of open-jaw and multi-city results; empty for round-trip and one-way results
+	ReturnOrigin      string
+	ReturnDestination string
 }
 
 // BulkSearchResultRecord represents the data needed to insert a bulk search result
@@ -251,6 +274,13 @@ type BulkSearchResultRecord struct {
 	OutboundFlightsJSON  []byte
 	ReturnFlightsJSON    []byte
 	OfferJSON            []byte
+
+	// Price in the reporting currency
+	NormalizedPrice    sql.NullFloat64
+	NormalizedCurrency sql.NullString
+
+	// Flights of every segment of a multi-city search
+	SegmentFlightsJSON []byte
 }
 
 // BulkSearchOffer represents all offers captured during a bulk run
@@ -276,6 +306,14 @@ type BulkSearchOffer struct {
 	ReturnFlights        json.RawMessage
 	OfferJSON            json.RawMessage
 	CreatedAt            time.Time
+
+	// Price and cost per mile in the reporting currency
+	NormalizedPrice       sql.NullFloat64
+	NormalizedCurrency    sql.NullString
+	NormalizedCostPerMile sql.NullFloat64
+
+	// Flights of every segment of a multi-city search
+	SegmentFlights json.RawMessage
 }
 
 // BulkSearchOfferRecord represents the data needed to insert an offer for a bulk run
@@ -299,6 +337,14 @@ type BulkSearchOfferRecord struct {
 	OutboundFlightsJSON  []byte
 	ReturnFlightsJSON    []byte
 	OfferJSON            []byte
+
+	// Price and cost per mile in the reporting currency
+	NormalizedPrice       sql.NullFloat64
+	NormalizedCurrency    sql.NullString
+	NormalizedCostPerMile sql.NullFloat64
+
+	// Flights of every segment of a multi-city search
+	SegmentFlightsJSON []byte
 }
 
 // Airport represents an airport row
@@ -356,6 +402,7 @@ type ContinuousSweepProgress struct {
 	IsRunning           bool
 	IsPaused            bool
 	InternationalOnly   bool
+	Origins             []string
 }
 
 // ContinuousSweepStats represents historical stats for completed sweeps
@@ -387,6 +434,7 @@ type SweepStatusResponse struct {
 	Class               string    `json:"class"`
 	Stops               string    `json:"stops"`
 	TripLengths         []int     `json:"trip_lengths"`
+	Origins             []string  `json:"origins,omitempty"`
 	QueriesCompleted    int       `json:"queries_completed"`
 	ErrorsCount         int       `json:"errors_count"`
 	LastError           string    `json:"last_error,omitempty"`
@@ -409,6 +457,18 @@ type ContinuousSweepResultsFilter struct {
 	Offset      int
 }
 
+// ExportFilter restricts the rows of the result exports. It has the filters of
+// ContinuousSweepResultsFilter without the pagination, as exports stream every matching row.
+type ExportFilter struct {
+	Origin      string
+	Destination string
+	FromDate    time.Time // earliest departure date
+	ToDate      time.Time // latest departure date
+	// Classification and Status only apply to detected deals
+	Classification string
+	Status         string
+}
+
 // --- Deal Detection Types ---
 
 // DealClassification constants
@@ -442,6 +502,7 @@ type RouteBaseline struct {
 	Destination string
 	TripLength  int
 	Class       string
+	Currency    string // currency of the statistics
 	SampleCount int
 	MeanPrice   sql.NullFloat64
 	MedianPrice sql.NullFloat64
@@ -490,6 +551,18 @@ type DetectedDeal struct {
 	ExpiresAt          sql.NullTime
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
+	NormalizedPrice    sql.NullFloat64 // price in the currency of the baseline
+	NormalizedCurrency sql.NullString
+}
+
+// FXRate is one exchange rate of a daily snapshot: one BaseCurrency costs Rate QuoteCurrency.
+type FXRate struct {
+	RateDate      time.Time
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          float64
+	Source        string
+	FetchedAt     time.Time
 }
 
 // DealAlert represents a published deal ready for notification
@@ -540,4 +613,56 @@ type DealFilter struct {
 	Offset         int
 }
 
+// PosComparison is a point-of-sale comparison: one itinerary priced in several markets
+type PosComparison struct {
+	ID              int
+	Status          string
+	Origin          string
+	Destination     string
+	DepartureDate   time.Time
+	ReturnDate      sql.NullTime
+	TripType        string
+	Class           string
+	Stops           string
+	Adults          int
+	Children        int
+	InfantsLap      int
+	InfantsSeat     int
+	Currency        string // reporting currency of the normalized prices
+	MarketCount     int
+	CheapestCountry sql.NullString
+	CheapestPrice   sql.NullFloat64
+	Savings         sql.NullFloat64 // most expensive minus cheapest normalized price
+	ErrorMessage    sql.NullString
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	CompletedAt     sql.NullTime
+}
+
+// PosComparisonSummary carries the final state of a point-of-sale comparison
+type PosComparisonSummary struct {
+	ComparisonID    int
+	Status          string
+	CheapestCountry sql.NullString
+	CheapestPrice   sql.NullFloat64
+	Savings         sql.NullFloat64
+	ErrorMessage    sql.NullString
+}
+
+// PosComparisonResult is the cheapest offer of a point-of-sale comparison in one market
+type PosComparisonResult struct {
+	ID              int
+	ComparisonID    int
+	Country         string
+	GoogleHost      sql.NullString
+	Currency        string // currency of Price
+	Price           sql.NullFloat64
+	NormalizedPrice sql.NullFloat64 // price in the comparison currency
+	AirlineCodes    []string
+	FlightNumbers   []string
+	SearchURL       sql.NullString
+	ErrorMessage    sql.NullString
+	CreatedAt       time.Time
+}
+
 // --- End Struct Definitions ---
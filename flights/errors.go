@@ -0,0 +1,51 @@
+package flights
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gilby125/google-flights-api/pkg/blockdetect"
+)
+
+// Errors returned when Google serves something else than the requested data. They are shared with
+// the hotels package, so [errors.Is] works regardless of the package which returned them.
+var (
+	ErrRateLimited     = blockdetect.ErrRateLimited     // HTTP 429 or the "unusual traffic" page
+	ErrConsentRequired = blockdetect.ErrConsentRequired // cookie consent interstitial
+	ErrBlocked         = blockdetect.ErrBlocked         // HTTP 403 or an unexpected HTML page
+	ErrSchemaChanged   = blockdetect.ErrSchemaChanged   // no part of the response could be decoded
+)
+
+type bufferedBody struct {
+	*bufio.Reader
+	io.Closer
+}
+
+// checkResponse detects the interstitials which Google serves instead of the API responses. The
+// API responses start with the ")]}'" prefix, so any HTML page is an interstitial. The peeked
+// bytes stay readable by the parsers.
+func checkResponse(resp *http.Response) (*http.Response, error) {
+	if err := blockdetect.Classify(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	body := bufio.NewReaderSize(resp.Body, blockdetect.PeekSize)
+	peek, err := body.Peek(blockdetect.PeekSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		resp.Body.Close()
+		return nil, err
+	}
+	if blockdetect.IsHTML(peek) {
+		resp.Body.Close()
+		if err := blockdetect.ClassifyBody(peek); err != nil {
+			return nil, err
+		}
+		return nil, ErrBlocked
+	}
+
+	resp.Body = bufferedBody{Reader: body, Closer: resp.Body}
+	return resp, nil
+}
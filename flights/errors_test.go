@@ -0,0 +1,107 @@
+package flights
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func staticClientMock(t *testing.T, status int, rawURL string, body string) *httpClientMock {
+	u, _ := url.Parse(rawURL)
+	return &httpClientMock{T: t, Responses: []func() (*http.Response, error){
+		func() (*http.Response, error) {
+			return &http.Response{
+				StatusCode: status,
+				Request:    &http.Request{URL: u},
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}, nil
+		},
+	}}
+}
+
+func TestGetOffersInterstitials(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		url      string
+		body     string
+		expected error
+	}{
+		{"unusual traffic", http.StatusOK, "https://www.google.com/_/FlightsFrontendUi/data",
+			`<html><body>Our systems have detected unusual traffic from your computer network.</body></html>`, ErrRateLimited},
+		{"sorry redirect", http.StatusTooManyRequests, "https://www.google.com/sorry/index", `<html></html>`, ErrRateLimited},
+		{"consent", http.StatusOK, "https://consent.google.com/ml", `<html></html>`, ErrConsentRequired},
+		{"forbidden", http.StatusForbidden, "https://www.google.com/_/FlightsFrontendUi/data", ``, ErrBlocked},
+		{"unknown html", http.StatusOK, "https://www.google.com/_/FlightsFrontendUi/data", `<!doctype html><html></html>`, ErrBlocked},
+		{"schema changed", http.StatusOK, "https://www.google.com/_/FlightsFrontendUi/data",
+			")]}'\n\n123\n[[\"wrb.fr\",null,\"[1,2,[\\\"unexpected\\\"]]\"]]\n", ErrSchemaChanged},
+		{"broken frames", http.StatusOK, "https://www.google.com/_/FlightsFrontendUi/data", ")]}'\n\n123\n{\"frame\":1}\n", ErrSchemaChanged},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			session := &Session{client: staticClientMock(t, test.status, test.url, test.body)}
+			_, _, err := session.GetOffers(context.Background(), Args{
+				Date:        testOffersArgs().Date,
+				ReturnDate:  testOffersArgs().ReturnDate,
+				SrcAirports: []string{"WAW"},
+				DstAirports: []string{"ATH"},
+				Options:     OptionsDefault(),
+			})
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestGetPriceGraphSchemaChanged(t *testing.T) {
+	body := ")]}'\n\n123\n[[\"wrb.fr\",null,\"{\\\"unexpected\\\":true}\"]]\n"
+	session := &Session{client: staticClientMock(t, http.StatusOK, "https://www.google.com/_/FlightsFrontendUi/data", body)}
+
+	args := PriceGraphArgs{
+		RangeStartDate: testOffersArgs().Date,
+		RangeEndDate:   testOffersArgs().Date.AddDate(0, 0, 30),
+		TripLength:     7,
+		SrcAirports:    []string{"WAW"},
+		DstAirports:    []string{"ATH"},
+		Options:        OptionsDefault(),
+	}
+	if _, _, err := session.GetPriceGraph(context.Background(), args); !errors.Is(err, ErrSchemaChanged) {
+		t.Fatalf("expected %v, got %v", ErrSchemaChanged, err)
+	}
+}
+
+func TestGetExploreDestinationsSchemaChanged(t *testing.T) {
+	body := ")]}'\n\n123\n[[\"wrb.fr\",null,\"[null,[[1,2],[\\\"unexpected\\\"]]]\"]]\n"
+	session := &Session{client: staticClientMock(t, http.StatusOK, "https://www.google.com/_/FlightsFrontendUi/data", body)}
+
+	args := ExploreArgs{
+		RangeStartDate: testOffersArgs().Date,
+		RangeEndDate:   testOffersArgs().Date.AddDate(0, 0, 30),
+		TripLength:     7,
+		SrcAirports:    []string{"WAW"},
+		Options:        OptionsDefault(),
+	}
+	if _, _, err := session.GetExploreDestinations(context.Background(), args); !errors.Is(err, ErrSchemaChanged) {
+		t.Fatalf("expected %v, got %v", ErrSchemaChanged, err)
+	}
+}
+
+func TestRetryPolicyStopsOnBlocks(t *testing.T) {
+	policy := customRetryPolicy()
+	u, _ := url.Parse("https://www.google.com/sorry/index")
+	retry, err := policy(context.Background(), &http.Response{StatusCode: http.StatusTooManyRequests, Request: &http.Request{URL: u}}, nil)
+	if retry || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("429 shouldn't be retried: retry %t, err %v", retry, err)
+	}
+
+	retry, _ = policy(context.Background(), &http.Response{StatusCode: http.StatusInternalServerError}, nil)
+	if !retry {
+		t.Fatal("server errors should be retried")
+	}
+}
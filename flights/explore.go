@@ -0,0 +1,339 @@
+package flights
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Arguments used in [Session.GetExploreDestinations].
+type ExploreArgs struct {
+	RangeStartDate, RangeEndDate time.Time // days range of the departures
+	TripLength                   int       // number of days between departure and return; ignored for one-way trips
+	SrcCities, SrcAirports       []string  // source; cities and airports of the trip
+	Options                                // additional options
+}
+
+// ExploreDestination is a destination of the "Explore" section of Google Flights with its cheapest
+// fare from the source of the search.
+type ExploreDestination struct {
+	CityID      string        // Google's (Freebase) id of the destination city, e.g. /m/04jpl
+	City        string        // name of the destination city
+	Country     string        // name of the destination country
+	AirportCode string        // IATA code of the airport of the cheapest fare, if known
+	Lat, Lon    float64       // coordinates of the destination city, if known
+	Price       float64       // cheapest fare
+	StartDate   time.Time     // departure date of the cheapest fare
+	ReturnDate  time.Time     // return date of the cheapest fare; zero for one-way trips
+	Stops       int           // number of stops of the cheapest fare, -1 if unknown
+	AirlineCode string        // IATA code of the main airline of the cheapest fare, if known
+	Duration    time.Duration // flight duration of the cheapest fare, if known
+}
+
+// Validates ExploreArgs requirements:
+//   - at least one source location (srcCities / srcAirports)
+//   - srcAirports have to be in the right IATA format: https://en.wikipedia.org/wiki/IATA_airport_code
+//   - dates have to be in the chronological order: today's date -> RangeStartDate -> RangeEndDate
+//   - the difference between RangeStartDate and RangeEndDate cannot be higher than 161 days
+//   - TripLength can't be negative
+//   - Country, GoogleHost and TimezoneOffset have to describe a valid market
+func (a *ExploreArgs) Validate() error {
+	if err := a.ValidateMarket(); err != nil {
+		return err
+	}
+	if err := validateNumberOfLocations(a.SrcCities, a.SrcAirports); err != nil {
+		return fmt.Errorf("src locations: %s", err)
+	}
+	for _, s := range a.SrcAirports {
+		if !isAirportCode(s) {
+			return fmt.Errorf("src airport '%s' is not an airport code", s)
+		}
+	}
+	if a.TripLength < 0 {
+		return fmt.Errorf("trip length can't be negative: %d", a.TripLength)
+	}
+
+	a.RangeStartDate = truncateToDay(a.RangeStartDate)
+	a.RangeEndDate = truncateToDay(a.RangeEndDate)
+	if a.RangeEndDate.Equal(a.RangeStartDate) {
+		a.RangeEndDate = a.RangeStartDate.AddDate(0, 0, 1)
+	}
+	return validateRangeDate(a.RangeStartDate, a.RangeEndDate)
+}
+
+func (s *Session) getExploreReqData(ctx context.Context, args ExploreArgs) (string, error) {
+	serSrcs, err := s.serializeFlightLocations(ctx, args.SrcCities, args.SrcAirports, args.Lang)
+	if err != nil {
+		return "", fmt.Errorf("could not serialize flight src locations: %v", err)
+	}
+	flightArgs := Args{Options: args.Options}
+	serAdults := serializeFlightTravelers(flightArgs)
+	serStops := serializeFlightStop(args.Stops)
+	serCarriers := serializeCarriers(args.Carriers)
+
+	tripType := RoundTrip
+	if args.TripType == OneWay {
+		tripType = OneWay
+	}
+
+	// The destination of an explore search is left empty: Google fills in anywhere.
+	rawData := fmt.Sprintf(`[null,null,%d,null,[],%d,%s,null,null,null,null,null,null,[`,
+		tripType, args.Class, serAdults)
+	rawData += fmt.Sprintf(`[[[%s]],[],null,%s,%s,[],\"%s\",null,[],[],[],null,null,[],3]`,
+		serSrcs, serStops, serCarriers, args.RangeStartDate.Format("2006-01-02"))
+	if tripType == RoundTrip {
+		rawData += fmt.Sprintf(`,[[],[[%s]],null,%s,%s,[],\"%s\",null,[],[],[],null,null,[],3]`,
+			serSrcs, serStops, serCarriers, args.RangeStartDate.AddDate(0, 0, args.TripLength).Format("2006-01-02"))
+	}
+
+	prefix := `[null,"[null,`
+	suffix := fmt.Sprintf(`],null,null,null,1,null,null,null,null,null,[]],[\"%s\",\"%s\"],null,[%d,%d]]"]`,
+		args.RangeStartDate.Format("2006-01-02"), args.RangeEndDate.Format("2006-01-02"), args.TripLength, args.TripLength)
+
+	return url.QueryEscape(prefix + rawData + suffix), nil
+}
+
+func (s *Session) doRequestExplore(ctx context.Context, args ExploreArgs) (*http.Response, error) {
+	url := s.marketURL(args.Options, "/_/FlightsFrontendUi/data/travel.frontend.flights.FlightsFrontendService/GetExploreDestinations?f.sid=-8920707734915550076&bl=boq_travel-frontend-ui_20230627.07_p1&hl="+args.hl()+"&soc-app=162&soc-platform=1&soc-device=1&_reqid=361464&rt=c")
+
+	reqData, err := s.getExploreReqData(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBody := []byte(
+		`f.req=` + reqData +
+			`&at=AAuQa1oq5qIkgkQ2nG9vQZFTgSME%3A` + strconv.FormatInt(time.Now().Unix(), 10) + `&`)
+
+	cookies, err := s.marketCookies(ctx, args.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("accept", `*/*`)
+	req.Header.Set("cache-control", `no-cache`)
+	req.Header.Set("content-type", `application/x-www-form-urlencoded;charset=UTF-8`)
+	req.Header["cookie"] = cookies
+	req.Header.Set("pragma", `no-cache`)
+	req.Header.Set("user-agent", `Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36`)
+	for key, value := range args.marketHeaders("48764689,47907128,48676280,48710756,48627726,48480739,48593234,48707380") {
+		req.Header.Set(key, value)
+	}
+
+	// Explore requests are as heavy as price graphs and share their budget.
+	resp, err := s.do(req, ratelimit.KindPriceGraph)
+	if err != nil {
+		return nil, err
+	}
+	return checkResponse(resp)
+}
+
+// isEntityID reports whether value is a Google (Freebase) entity id such as /m/04jpl or /g/11b6.
+func isEntityID(value interface{}) bool {
+	id, ok := value.(string)
+	return ok && (strings.HasPrefix(id, "/m/") || strings.HasPrefix(id, "/g/"))
+}
+
+// explorePriceRow decodes a fare row:
+// [cityID,[[null,price],token],[srcAirport,dstAirport],startDate,returnDate,stops,airline,minutes]
+// Only the first two elements are required.
+func explorePriceRow(row []interface{}, destination *ExploreDestination) bool {
+	if len(row) < 2 || !isEntityID(row[0]) {
+		return false
+	}
+	pricing, ok := getElement[[]interface{}](row, 1)
+	if !ok {
+		return false
+	}
+	cell, ok := getElement[[]interface{}](pricing, 0)
+	if !ok || len(cell) < 2 || cell[0] != nil {
+		return false
+	}
+	price, ok := cell[1].(float64)
+	if !ok {
+		return false
+	}
+
+	destination.CityID = row[0].(string)
+	destination.Price = price
+	destination.Stops = -1
+	if airports, ok := getElement[[]interface{}](row, 2); ok {
+		destination.AirportCode, _ = getElement[string](airports, 1)
+	}
+	if date, ok := getElement[string](row, 3); ok {
+		destination.StartDate, _ = time.Parse("2006-01-02", date)
+	}
+	if date, ok := getElement[string](row, 4); ok {
+		destination.ReturnDate, _ = time.Parse("2006-01-02", date)
+	}
+	if stops, ok := getElement[float64](row, 5); ok {
+		destination.Stops = int(stops)
+	}
+	destination.AirlineCode, _ = getElement[string](row, 6)
+	if minutes, ok := getElement[float64](row, 7); ok {
+		destination.Duration = time.Duration(minutes) * time.Minute
+	}
+	return true
+}
+
+// exploreCityRow decodes a city row: [cityID,cityName,[lat,lon],countryName]
+func exploreCityRow(row []interface{}, destination *ExploreDestination) bool {
+	if len(row) < 3 || !isEntityID(row[0]) {
+		return false
+	}
+	name, ok := row[1].(string)
+	if !ok || name == "" || isEntityID(name) {
+		return false
+	}
+	coordinates, ok := getElement[[]interface{}](row, 2)
+	if !ok || len(coordinates) < 2 {
+		return false
+	}
+	lat, latOK := coordinates[0].(float64)
+	lon, lonOK := coordinates[1].(float64)
+	if !latOK || !lonOK {
+		return false
+	}
+
+	destination.CityID = row[0].(string)
+	destination.City = name
+	destination.Lat, destination.Lon = lat, lon
+	destination.Country, _ = getElement[string](row, 3)
+	return true
+}
+
+// collectExploreRows walks a decoded section and collects its fare and city rows by city id.
+func collectExploreRows(value interface{}, prices, cities map[string]ExploreDestination, parseErrors *ParseErrors) {
+	row, ok := value.([]interface{})
+	if !ok {
+		return
+	}
+
+	var destination ExploreDestination
+	if explorePriceRow(row, &destination) {
+		parseErrors.TotalOffersRaw++
+		if destination.Price == 0 {
+			parseErrors.ZeroPriceCount++
+			return
+		}
+		if existing, ok := prices[destination.CityID]; !ok || destination.Price < existing.Price {
+			prices[destination.CityID] = destination
+		}
+		return
+	}
+	if exploreCityRow(row, &destination) {
+		cities[destination.CityID] = destination
+		return
+	}
+	for _, element := range row {
+		collectExploreRows(element, prices, cities, parseErrors)
+	}
+}
+
+// GetExploreDestinations retrieves the cheapest destinations reachable from the sources of args
+// ("Explore" section of Google Flight search, destination "Anywhere"). The destinations are
+// returned cheapest first; destinations without a fare are skipped.
+//
+// The layout of the explore response is undocumented and parsed on a best-effort basis: an
+// unrecognized response yields [ErrSchemaChanged].
+//
+// Requirements are described by the [ExploreArgs.Validate] function.
+func (s *Session) GetExploreDestinations(ctx context.Context, args ExploreArgs) ([]ExploreDestination, *ParseErrors, error) {
+	if err := args.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	destinations, parseErrors, err := s.getExploreDestinations(ctx, args)
+	s.recordOutcome(ctx, err, len(destinations) == 0)
+	return destinations, parseErrors, err
+}
+
+func (s *Session) getExploreDestinations(ctx context.Context, args ExploreArgs) ([]ExploreDestination, *ParseErrors, error) {
+	parseErrors := &ParseErrors{Samples: make([]string, 0, 5)}
+	prices := map[string]ExploreDestination{}
+	cities := map[string]ExploreDestination{}
+
+	resp, err := s.doRequestExplore(ctx, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body := bufio.NewReader(resp.Body)
+	skipPrefix(body)
+
+	sectionIndex := 0
+	for {
+		readLine(body) // skip line
+		bytesToDecode, err := getInnerBytes(body)
+		if err != nil {
+			if sectionIndex == 0 && !errors.Is(err, io.EOF) {
+				return nil, parseErrors, fmt.Errorf("%w: GetExploreDestinations: %v", ErrSchemaChanged, err)
+			}
+			break
+		}
+
+		sectionIndex++
+		if len(bytes.TrimSpace(bytesToDecode)) == 0 {
+			parseErrors.EmptySections++
+			continue
+		}
+		var section interface{}
+		if err := json.Unmarshal(bytesToDecode, &section); err != nil {
+			parseErrors.UnmarshalFailures++
+			if len(parseErrors.Samples) < 5 {
+				parseErrors.Samples = append(parseErrors.Samples, sampleFingerprint(fmt.Sprintf("explore_section[%d]", sectionIndex), bytesToDecode))
+			}
+			continue
+		}
+		collectExploreRows(section, prices, cities, parseErrors)
+	}
+
+	if parseErrors.TotalOffersRaw == 0 {
+		// Google lists destinations for every origin it serves, so a response without any
+		// destination row means the layout has changed.
+		if parseErrors.UnmarshalFailures > 0 {
+			return nil, parseErrors, fmt.Errorf("%w: GetExploreDestinations: no section could be decoded", ErrSchemaChanged)
+		}
+		return nil, parseErrors, fmt.Errorf("%w: GetExploreDestinations: no destination found in %d sections", ErrSchemaChanged, sectionIndex)
+	}
+
+	destinations := make([]ExploreDestination, 0, len(prices))
+	for id, destination := range prices {
+		if city, ok := cities[id]; ok {
+			destination.City = city.City
+			destination.Country = city.Country
+			destination.Lat, destination.Lon = city.Lat, city.Lon
+		}
+		if destination.StartDate.IsZero() {
+			destination.StartDate = args.RangeStartDate
+		}
+		if destination.ReturnDate.IsZero() && args.TripType != OneWay {
+			destination.ReturnDate = destination.StartDate.AddDate(0, 0, args.TripLength)
+		}
+		destinations = append(destinations, destination)
+	}
+	sortSlice(destinations, func(lv, rv ExploreDestination) bool {
+		if lv.Price != rv.Price {
+			return lv.Price < rv.Price
+		}
+		return lv.CityID < rv.CityID
+	})
+	return destinations, parseErrors, nil
+}
@@ -0,0 +1,145 @@
+package flights
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// testdata/explore.resp is not a captured response: it was written by hand after the layout
+// parsed by getExploreDestinations, so this test only guards the parser against regressions.
+// Replace it with a scrubbed GetExploreDestinations fixture recorded with FLIGHTS_RECORD_DIR
+// (without the HTTP headers) once one is available; the explore endpoint and MCP tool stay
+// unregistered until then.
+func TestGetExploreDestinationsMock(t *testing.T) {
+	httpClientMock, err := newHttpClientMock(t, "testdata/explore.resp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := &Session{
+		client: httpClientMock,
+	}
+
+	destinations, parseErrors, err := session.GetExploreDestinations(
+		context.Background(),
+		ExploreArgs{
+			RangeStartDate: time.Now().AddDate(0, 0, 2),
+			RangeEndDate:   time.Now().AddDate(0, 0, 30),
+			TripLength:     7,
+			SrcAirports:    []string{"SFO"},
+			Options: Options{
+				Travelers: Travelers{Adults: 1},
+				Currency:  currency.USD,
+				Stops:     AnyStops,
+				Class:     Economy,
+				TripType:  RoundTrip,
+				Lang:      language.English,
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(destinations) != 3 {
+		t.Fatalf("wrong destinations length, expected: 3, received: %d (%+v)", len(destinations), destinations)
+	}
+	if parseErrors.ZeroPriceCount != 1 || parseErrors.TotalOffersRaw != 5 {
+		t.Errorf("unexpected parse errors: %+v", parseErrors)
+	}
+
+	la := destinations[0]
+	if la.City != "Los Angeles" || la.Country != "United States" || la.AirportCode != "LAX" || la.Price != 129 {
+		t.Errorf("unexpected cheapest destination: %+v", la)
+	}
+	if la.Stops != 0 || la.AirlineCode != "UA" || la.Duration != 85*time.Minute {
+		t.Errorf("unexpected cheapest fare details: %+v", la)
+	}
+	if la.StartDate.Format("2006-01-02") != "2024-01-09" || la.ReturnDate.Format("2006-01-02") != "2024-01-16" {
+		t.Errorf("unexpected dates: %s %s", la.StartDate, la.ReturnDate)
+	}
+
+	// The cheapest of the Paris fares is kept.
+	paris := destinations[1]
+	if paris.City != "Paris" || paris.Price != 488 || paris.AirportCode != "CDG" {
+		t.Errorf("unexpected second destination: %+v", paris)
+	}
+	if paris.Lat != 48.8566 || paris.Lon != 2.3522 {
+		t.Errorf("unexpected coordinates: %v %v", paris.Lat, paris.Lon)
+	}
+	if destinations[2].City != "London" {
+		t.Errorf("unexpected third destination: %+v", destinations[2])
+	}
+}
+
+func TestExploreReqData(t *testing.T) {
+	session := &Session{}
+
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	args := ExploreArgs{
+		RangeStartDate: date,
+		RangeEndDate:   date.AddDate(0, 0, 30),
+		TripLength:     7,
+		SrcAirports:    []string{"SFO"},
+		Options: Options{
+			Travelers: Travelers{Adults: 1},
+			Currency:  currency.USD,
+			Stops:     AnyStops,
+			Class:     Economy,
+			TripType:  RoundTrip,
+			Lang:      language.English,
+		},
+	}
+
+	expectedRoundTrip := `[null,"[null,[null,null,1,null,[],1,[1,0,0,0],null,null,null,null,null,null,[[[[[\"SFO\",0]]],[],null,0,[],[],\"2024-01-01\",null,[],[],[],null,null,[],3],[[],[[[\"SFO\",0]]],null,0,[],[],\"2024-01-08\",null,[],[],[],null,null,[],3]],null,null,null,1,null,null,null,null,null,[]],[\"2024-01-01\",\"2024-01-31\"],null,[7,7]]"]`
+	reqData, err := session.getExploreReqData(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unescaped, _ := url.QueryUnescape(reqData); unescaped != expectedRoundTrip {
+		t.Fatalf("wrong unescaped query, expected: %s received: %s", expectedRoundTrip, unescaped)
+	}
+
+	args.TripType = OneWay
+	args.TripLength = 0
+	expectedOneWay := `[null,"[null,[null,null,2,null,[],1,[1,0,0,0],null,null,null,null,null,null,[[[[[\"SFO\",0]]],[],null,0,[],[],\"2024-01-01\",null,[],[],[],null,null,[],3]],null,null,null,1,null,null,null,null,null,[]],[\"2024-01-01\",\"2024-01-31\"],null,[0,0]]"]`
+	reqData, err = session.getExploreReqData(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unescaped, _ := url.QueryUnescape(reqData); unescaped != expectedOneWay {
+		t.Fatalf("wrong unescaped query, expected: %s received: %s", expectedOneWay, unescaped)
+	}
+}
+
+func TestExploreArgsValidate(t *testing.T) {
+	args := ExploreArgs{
+		RangeStartDate: time.Now().AddDate(0, 0, 1),
+		RangeEndDate:   time.Now().AddDate(0, 0, 10),
+		Options:        OptionsDefault(),
+	}
+	if err := args.Validate(); err == nil {
+		t.Error("expected error without source locations")
+	}
+
+	args.SrcAirports = []string{"SFOO"}
+	if err := args.Validate(); err == nil {
+		t.Error("expected error for an invalid airport code")
+	}
+
+	args.SrcAirports = []string{"SFO"}
+	args.TripLength = -1
+	if err := args.Validate(); err == nil {
+		t.Error("expected error for a negative trip length")
+	}
+
+	args.TripLength = 7
+	if err := args.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package flights
+
+import "fmt"
+
+// Positions of the fare data in a raw offer. Google only supplies the fare brand for some markets
+// and carriers (mostly the US ones), so the data is best-effort and may change as Google updates
+// its internal APIs. TestFareFixtureLayout checks the positions against testdata/flight.resp, where
+// index 5 is always [false,false,false] and index 9 is either null or a single count ([[1]]), which
+// isn't enough to tell carry-on from checked bags.
+const (
+	rawOfferFareRestrictions = 5 // [basic economy, carry-on not included, seat selection not included]
+	rawOfferBaggage          = 9 // [[carry-on bags, checked bags]]
+)
+
+// Fare describes the fare brand of an offer, e.g. whether it is a basic economy fare and which
+// bags are included in the price.
+type Fare struct {
+	BasicEconomy   bool // restricted fare, usually without a carry-on bag or seat selection
+	SeatSelection  bool // seat selection is included in the price
+	BaggageKnown   bool // Google supplied the included bags; CarryOnBags and CheckedBags are valid
+	CarryOnBags    int  // number of carry-on bags included in the price
+	CheckedBags    int  // number of checked bags included in the price
+	BagsUnverified bool // kept by Args.RequiredBags although the included bags are unknown
+}
+
+func (f Fare) String() string {
+	out := ""
+	out += fmt.Sprintf("{BasicEconomy: %t ", f.BasicEconomy)
+	out += fmt.Sprintf("SeatSelection: %t ", f.SeatSelection)
+	out += fmt.Sprintf("BaggageKnown: %t ", f.BaggageKnown)
+	out += fmt.Sprintf("CarryOnBags: %d ", f.CarryOnBags)
+	out += fmt.Sprintf("CheckedBags: %d ", f.CheckedBags)
+	out += fmt.Sprintf("BagsUnverified: %t}", f.BagsUnverified)
+	return out
+}
+
+// Bags describes the bags which have to be included in the price of an offer.
+type Bags struct {
+	CarryOn int // number of carry-on bags
+	Checked int // number of checked bags
+}
+
+// decodeFare reads the fare brand of a raw offer. Seat selection is assumed to be included and the
+// bags to be unknown, unless the offer says otherwise.
+func decodeFare(rawOffer []interface{}) Fare {
+	fare := Fare{SeatSelection: true}
+
+	if restrictions, ok := getElement[[]interface{}](rawOffer, rawOfferFareRestrictions); ok {
+		fare.BasicEconomy, _ = getElement[bool](restrictions, 0)
+		carryOnExcluded, _ := getElement[bool](restrictions, 1)
+		seatSelectionExcluded, _ := getElement[bool](restrictions, 2)
+		fare.SeatSelection = !seatSelectionExcluded
+		if carryOnExcluded {
+			fare.BaggageKnown = true
+		}
+	}
+
+	if rawBaggage, ok := getElement[[]interface{}](rawOffer, rawOfferBaggage); ok {
+		if baggage, ok := getElement[[]interface{}](rawBaggage, 0); ok {
+			carryOn, carryOnOk := getElement[float64](baggage, 0)
+			checked, checkedOk := getElement[float64](baggage, 1)
+			if carryOnOk && checkedOk {
+				fare.BaggageKnown = true
+				fare.CarryOnBags = int(carryOn)
+				fare.CheckedBags = int(checked)
+			}
+		}
+	}
+
+	if fare.BasicEconomy && !fare.BaggageKnown {
+		// Basic economy fares don't include any bag apart from a personal item.
+		fare.BaggageKnown = true
+	}
+	return fare
+}
+
+// allowsFare checks the fare against ExcludeBasicEconomy and RequiredBags of the args. Fares with
+// unknown baggage are allowed; filterOffersByFare flags them with BagsUnverified.
+func (a *Args) allowsFare(fare Fare) bool {
+	if a.ExcludeBasicEconomy && fare.BasicEconomy {
+		return false
+	}
+	if a.RequiredBags == (Bags{}) || !fare.BaggageKnown {
+		return true
+	}
+	return fare.CarryOnBags >= a.RequiredBags.CarryOn && fare.CheckedBags >= a.RequiredBags.Checked
+}
+
+func filterOffersByFare(args Args, offers []FullOffer) []FullOffer {
+	if !args.ExcludeBasicEconomy && args.RequiredBags == (Bags{}) {
+		return offers
+	}
+
+	filtered := make([]FullOffer, 0, len(offers))
+	for _, offer := range offers {
+		if !args.allowsFare(offer.Fare) {
+			continue
+		}
+		if args.RequiredBags != (Bags{}) && !offer.Fare.BaggageKnown {
+			offer.Fare.BagsUnverified = true
+		}
+		filtered = append(filtered, offer)
+	}
+	return filtered
+}
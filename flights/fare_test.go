@@ -0,0 +1,130 @@
+package flights
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestDecodeFare(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected Fare
+	}{
+		{`[[],[],null,null,null,[false,false,false]]`, Fare{SeatSelection: true}},
+		{`[[],[],null,null,null,[true,true,true]]`, Fare{BasicEconomy: true, BaggageKnown: true}},
+		{`[[],[],null,null,null,[true,false,false]]`, Fare{BasicEconomy: true, SeatSelection: true, BaggageKnown: true}},
+		{`[[],[],null,null,null,null,null,null,null,[[1,1]]]`, Fare{SeatSelection: true, BaggageKnown: true, CarryOnBags: 1, CheckedBags: 1}},
+		{`[[],[],null,null,null,null,null,null,null,[[2]]]`, Fare{SeatSelection: true}},
+		{`[[],[]]`, Fare{SeatSelection: true}},
+	}
+
+	for _, test := range tests {
+		rawOffer := []interface{}{}
+		if err := json.Unmarshal([]byte(test.raw), &rawOffer); err != nil {
+			t.Fatal(err)
+		}
+		if diff := deep.Equal(test.expected, decodeFare(rawOffer)); diff != nil {
+			t.Errorf("%s: %v", test.raw, diff)
+		}
+	}
+}
+
+func TestFilterOffersByFare(t *testing.T) {
+	basic := FullOffer{Fare: Fare{BasicEconomy: true, BaggageKnown: true}}
+	standard := FullOffer{Fare: Fare{SeatSelection: true, BaggageKnown: true, CarryOnBags: 1}}
+	withBag := FullOffer{Fare: Fare{SeatSelection: true, BaggageKnown: true, CarryOnBags: 1, CheckedBags: 1}}
+	unknown := FullOffer{Fare: Fare{SeatSelection: true}}
+	offers := []FullOffer{basic, standard, withBag, unknown}
+
+	if filtered := filterOffersByFare(Args{}, offers); len(filtered) != 4 {
+		t.Fatalf("offers shouldn't be filtered without fare options: %d", len(filtered))
+	}
+
+	filtered := filterOffersByFare(Args{ExcludeBasicEconomy: true}, offers)
+	if diff := deep.Equal([]FullOffer{standard, withBag, unknown}, filtered); diff != nil {
+		t.Fatal(diff)
+	}
+
+	filtered = filterOffersByFare(Args{RequiredBags: Bags{CarryOn: 1, Checked: 1}}, offers)
+	unverified := unknown
+	unverified.Fare.BagsUnverified = true
+	if diff := deep.Equal([]FullOffer{withBag, unverified}, filtered); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
+// TestFareFixtureLayout checks rawOfferFareRestrictions and rawOfferBaggage against the captured
+// response, so a shift of the offer schema shows up here rather than as silently wrong fares.
+func TestFareFixtureLayout(t *testing.T) {
+	respFile, err := os.Open("testdata/flight.resp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer respFile.Close()
+
+	body := bufio.NewReader(respFile)
+	skipPrefix(body)
+
+	rawOffers := []json.RawMessage{}
+	for {
+		readLine(body) // skip line
+		bytesToDecode, err := getInnerBytes(body)
+		if err != nil {
+			break
+		}
+		rawOffers1, rawOffers2 := []json.RawMessage{}, []json.RawMessage{}
+		if err := json.Unmarshal(bytesToDecode, sectionOffersSchema(&rawOffers1, &rawOffers2, &PriceRange{})); err != nil {
+			continue // not an offers section
+		}
+		rawOffers = append(append(rawOffers, rawOffers1...), rawOffers2...)
+	}
+	if len(rawOffers) == 0 {
+		t.Fatal("no offers parsed")
+	}
+
+	for i, raw := range rawOffers {
+		rawOffer := []interface{}{}
+		if err := json.Unmarshal(raw, &rawOffer); err != nil {
+			t.Fatal(err)
+		}
+
+		restrictions, ok := getElement[[]interface{}](rawOffer, rawOfferFareRestrictions)
+		if !ok || len(restrictions) != 3 {
+			t.Fatalf("offer %d: fare restrictions aren't a 3-element array: %v", i, rawOffer[rawOfferFareRestrictions])
+		}
+		for _, restriction := range restrictions {
+			if _, ok := restriction.(bool); !ok {
+				t.Fatalf("offer %d: fare restriction isn't a bool: %v", i, restrictions)
+			}
+		}
+
+		if rawOffer[rawOfferBaggage] != nil {
+			rawBaggage, _ := getElement[[]interface{}](rawOffer, rawOfferBaggage)
+			baggage, ok := getElement[[]interface{}](rawBaggage, 0)
+			if !ok {
+				t.Fatalf("offer %d: baggage isn't a nested array: %v", i, rawOffer[rawOfferBaggage])
+			}
+			for _, bags := range baggage {
+				if _, ok := bags.(float64); !ok {
+					t.Fatalf("offer %d: bag count isn't a number: %v", i, baggage)
+				}
+			}
+		}
+
+		// The capture has no basic economy fares and only single bag counts.
+		if diff := deep.Equal(Fare{SeatSelection: true}, decodeFare(rawOffer)); diff != nil {
+			t.Errorf("offer %d: %v", i, diff)
+		}
+	}
+}
+
+func TestValidateRequiredBags(t *testing.T) {
+	args := Args{RequiredBags: Bags{Checked: -1}}
+	if err := args.ValidateOffersArgs(); err == nil {
+		t.Fatal("expected an error for negative required bags")
+	}
+}
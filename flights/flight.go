@@ -5,7 +5,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -14,6 +16,7 @@ import (
 
 	_ "time/tzdata"
 
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/hashicorp/go-retryablehttp"
 	"golang.org/x/text/language"
 )
@@ -134,7 +137,43 @@ func serializeCarriers(carriers []string) string {
 	return b.String()
 }
 
+// serializeSelectedFlights serializes the legs of an already chosen itinerary. Google expects them
+// in the segment that was selected so that the next stage of the search (e.g. the return flights
+// of a round-trip or the next segment of a multi-city trip) can be requested.
+func serializeSelectedFlights(flights []Flight) string {
+	if len(flights) == 0 {
+		return "[]"
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, f := range flights {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		airline, number, _ := strings.Cut(f.FlightNumber, " ")
+		fmt.Fprintf(&b, `[\"%s\",\"%s\",\"%s\",null,\"%s\",\"%s\"]`,
+			f.DepAirportCode, f.DepTime.Format("2006-01-02"), f.ArrAirportCode, airline, number)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// selectedFlights returns the chosen flights of the i-th segment, or nil if none was chosen yet.
+func selectedFlights(selected [][]Flight, i int) []Flight {
+	if i < len(selected) {
+		return selected[i]
+	}
+	return nil
+}
+
 func (s *Session) getRawData(ctx context.Context, args Args) (string, error) {
+	return s.getRawDataSelected(ctx, args, nil)
+}
+
+// getRawDataSelected serializes args. selected contains the flights already chosen for each
+// segment (the outbound flights of a round-trip, the first segments of a multi-city trip).
+func (s *Session) getRawDataSelected(ctx context.Context, args Args, selected [][]Flight) (string, error) {
 	serAdults := serializeFlightTravelers(args)
 	serStops := serializeFlightStop(args.Stops)
 	serCarriers := serializeCarriers(args.Carriers)
@@ -158,9 +197,10 @@ func (s *Session) getRawData(ctx context.Context, args Args) (string, error) {
 				return "", fmt.Errorf("could not serialize segment %d dst locations: %v", i, err)
 			}
 			serDate := segment.Date.Format("2006-01-02")
+			serSelected := serializeSelectedFlights(selectedFlights(selected, i))
 
-			rawData += fmt.Sprintf(`[[[%s]],[[%s]],null,%s,%s,[],\"%s\",null,[],[],[],null,null,[],3]`,
-				serSrcs, serDsts, serStops, serCarriers, serDate)
+			rawData += fmt.Sprintf(`[[[%s]],[[%s]],null,%s,%s,[],\"%s\",null,%s,[],[],null,null,[],3]`,
+				serSrcs, serDsts, serStops, serCarriers, serDate, serSelected)
 		}
 	} else {
 		serSrcs, err := s.serializeFlightLocations(ctx, args.SrcCities, args.SrcAirports, args.Lang)
@@ -173,9 +213,10 @@ func (s *Session) getRawData(ctx context.Context, args Args) (string, error) {
 		}
 
 		serDate := args.Date.Format("2006-01-02")
+		serSelected := serializeSelectedFlights(selectedFlights(selected, 0))
 
-		rawData += fmt.Sprintf(`[[[%s]],[[%s]],null,%s,%s,[],\"%s\",null,[],[],[],null,null,[],3]`,
-			serSrcs, serDsts, serStops, serCarriers, serDate)
+		rawData += fmt.Sprintf(`[[[%s]],[[%s]],null,%s,%s,[],\"%s\",null,%s,[],[],null,null,[],3]`,
+			serSrcs, serDsts, serStops, serCarriers, serDate, serSelected)
 
 		if args.TripType == RoundTrip {
 			serReturnDate := args.ReturnDate.Format("2006-01-02")
@@ -188,7 +229,11 @@ func (s *Session) getRawData(ctx context.Context, args Args) (string, error) {
 }
 
 func (s *Session) getFlightReqData(ctx context.Context, args Args) (string, error) {
-	rawData, err := s.getRawData(ctx, args)
+	return s.getFlightReqDataSelected(ctx, args, nil)
+}
+
+func (s *Session) getFlightReqDataSelected(ctx context.Context, args Args, selected [][]Flight) (string, error) {
+	rawData, err := s.getRawDataSelected(ctx, args, selected)
 	if err != nil {
 		return "", err
 	}
@@ -203,10 +248,10 @@ func (s *Session) getFlightReqData(ctx context.Context, args Args) (string, erro
 	return url.QueryEscape(reqData), nil
 }
 
-func (s *Session) doRequestFlights(ctx context.Context, args Args) (*http.Response, error) {
-	url := "https://www.google.com/_/FlightsFrontendUi/data/travel.frontend.flights.FlightsFrontendService/GetShoppingResults?f.sid=-1300922759171628473&bl=boq_travel-frontend-ui_20230627.02_p1&hl=en&soc-app=162&soc-platform=1&soc-device=1&_reqid=52717&rt=c"
+func (s *Session) doRequestFlights(ctx context.Context, args Args, selected [][]Flight) (*http.Response, error) {
+	url := s.marketURL(args.Options, "/_/FlightsFrontendUi/data/travel.frontend.flights.FlightsFrontendService/GetShoppingResults?f.sid=-1300922759171628473&bl=boq_travel-frontend-ui_20230627.02_p1&hl="+args.hl()+"&soc-app=162&soc-platform=1&soc-device=1&_reqid=52717&rt=c")
 
-	reqDate, err := s.getFlightReqData(ctx, args)
+	reqDate, err := s.getFlightReqDataSelected(ctx, args, selected)
 	if err != nil {
 		return nil, fmt.Errorf("could not get request data: %v", err)
 	}
@@ -215,21 +260,30 @@ func (s *Session) doRequestFlights(ctx context.Context, args Args) (*http.Respon
 		`f.req=` + reqDate +
 			`&at=AAuQa1qjMakasqKYcQeoFJjN7RZ3%3A` + strconv.FormatInt(time.Now().Unix(), 10) + `&`)
 
+	cookies, err := s.marketCookies(ctx, args.Options)
+	if err != nil {
+		return nil, err
+	}
+
 	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to do GetShoppingResults request: %v", err)
 	}
 	req.Header.Set("accept", `*/*`)
-	req.Header.Set("accept-language", `en-US,en;q=0.9`)
 	req.Header.Set("cache-control", `no-cache`)
 	req.Header.Set("content-type", `application/x-www-form-urlencoded;charset=UTF-8`)
-	req.Header["cookie"] = s.cookies
+	req.Header["cookie"] = cookies
 	req.Header.Set("pragma", `no-cache`)
 	req.Header.Set("user-agent", `Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/143.0.0.0 Safari/537.36`)
-	req.Header.Set("x-goog-ext-259736195-jspb",
-		fmt.Sprintf(`["en-US","US","%s",1,null,[-120],null,[[48676280,48710756,47907128,48764689,48627726,48480739,48593234,48707380]],1,[]]`, args.Currency)) // language, location, Currency
+	for key, value := range args.marketHeaders("48676280,48710756,47907128,48764689,48627726,48480739,48593234,48707380") {
+		req.Header.Set(key, value) // language, location, currency, timezone
+	}
 
-	return s.client.Do(req)
+	resp, err := s.do(req, ratelimit.KindOffers)
+	if err != nil {
+		return nil, err
+	}
+	return checkResponse(resp)
 }
 
 func getFlightsDuration(flights []Flight) time.Duration {
@@ -306,6 +360,7 @@ func getFlights(rawFlights []json.RawMessage) ([]Flight, error) {
 		flight.ArrTime = time.Date(int(arrYear), time.Month(arrMonth), int(arrDay), int(arrHours), int(arrMinutes), 0, 0, arrLocation)
 		flight.Duration = time.Duration(int64(duration)) * time.Minute
 		flight.FlightNumber = flightNoPart1 + " " + flightNoPart2
+		decodeFlightAttributes(&flight)
 		flights = append(flights, flight)
 	}
 	return flights, nil
@@ -341,6 +396,12 @@ func getSubsectionOffers(rawOffers []json.RawMessage, returnDate time.Time) ([]F
 		offer.ReturnDate = returnDate.UTC()
 
 		offer.FlightDuration = getFlightsDuration(flights)
+		offer.Layovers = getLayovers(flights)
+
+		fareOffer := []interface{}{}
+		if err := json.Unmarshal(rawOffer, &fareOffer); err == nil {
+			offer.Fare = decodeFare(fareOffer)
+		}
 
 		offer.SrcAirportCode = flights[0].DepAirportCode
 		offer.DstAirportCode = flights[len(flights)-1].ArrAirportCode
@@ -390,6 +451,13 @@ func getSectionOffers(bytesToDecode []byte, returnDate time.Time) ([]FullOffer,
 // taken from the "View price history" subsection of the search. If the search doesn't have the "View
 // price history" subsection, then GetOffers returns nil.
 //
+// For round-trip searches only the outbound part of the itinerary is returned. The return flights
+// can be requested with [Session.GetReturnOffers] or [Session.FillReturnFlights].
+//
+// Offers with connections outside of args.MinLayover and args.MaxLayover are left out. So are basic
+// economy fares if args.ExcludeBasicEconomy is set and fares without the bags of args.RequiredBags.
+// Offers whose bags Google didn't supply are kept, but their Fare.BagsUnverified is set.
+//
 // GetPriceGraph returns an error if any of the requests fail or if any of the city names are misspelled.
 //
 // Requirements are described by the [Args.ValidateOffersArgs] function.
@@ -398,10 +466,23 @@ func (s *Session) GetOffers(ctx context.Context, args Args) ([]FullOffer, *Price
 		return nil, nil, err
 	}
 
+	offers, priceRange, err := s.getOffers(ctx, args, nil)
+	s.recordOutcome(ctx, err, len(offers) == 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return filterOffers(args, offers), priceRange, nil
+}
+
+func filterOffers(args Args, offers []FullOffer) []FullOffer {
+	return filterOffersByFare(args, filterOffersByLayovers(args, offers))
+}
+
+func (s *Session) getOffers(ctx context.Context, args Args, selected [][]Flight) ([]FullOffer, *PriceRange, error) {
 	finalOffers := []FullOffer{}
 	var finalPriceRange *PriceRange
 
-	resp, err := s.doRequestFlights(ctx, args)
+	resp, err := s.doRequestFlights(ctx, args, selected)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -410,14 +491,26 @@ func (s *Session) GetOffers(ctx context.Context, args Args) ([]FullOffer, *Price
 	body := bufio.NewReader(resp.Body)
 	skipPrefix(body)
 
+	// Sections which can't be decoded are skipped, unless none of them can be decoded.
+	sections, failedSections := 0, 0
+
 	for {
 		readLine(body) // skip line
 		bytesToDecode, err := getInnerBytes(body)
 		if err != nil {
+			if len(finalOffers) == 0 && (sections > 0 && failedSections == sections || sections == 0 && !errors.Is(err, io.EOF)) {
+				return nil, nil, fmt.Errorf("%w: GetShoppingResults: %v", ErrSchemaChanged, err)
+			}
 			return finalOffers, finalPriceRange, nil
 		}
 
-		offers, priceRange, _ := getSectionOffers(bytesToDecode, args.ReturnDate)
+		if len(bytes.TrimSpace(bytesToDecode)) > 0 {
+			sections++
+		}
+		offers, priceRange, err := getSectionOffers(bytesToDecode, args.ReturnDate)
+		if err != nil && len(bytes.TrimSpace(bytesToDecode)) > 0 {
+			failedSections++
+		}
 		if offers != nil {
 			finalOffers = append(finalOffers, offers...)
 		}
@@ -426,3 +519,223 @@ func (s *Session) GetOffers(ctx context.Context, args Args) ([]FullOffer, *Price
 		}
 	}
 }
+
+// GetReturnOffers retrieves the return flights of a round-trip search for the selected outbound offer
+// (one of the offers returned by [Session.GetOffers] for the same args). It issues the second-stage
+// request which Google Flights sends once an outbound flight is chosen.
+//
+// Every returned [FullOffer] contains the outbound flights of the selected offer together with one of
+// the return options in ReturnFlight. The Price of each offer is the price of the whole round-trip.
+//
+// GetReturnOffers returns an error if args don't describe a round-trip, if the outbound offer doesn't
+// contain any flights or if any of the requests fail.
+func (s *Session) GetReturnOffers(ctx context.Context, args Args, outbound FullOffer) ([]FullOffer, *PriceRange, error) {
+	if args.TripType != RoundTrip {
+		return nil, nil, fmt.Errorf("return offers are only available for round-trip searches")
+	}
+	if len(outbound.Flight) == 0 {
+		return nil, nil, fmt.Errorf("outbound offer doesn't contain any flights")
+	}
+	if err := args.ValidateOffersArgs(); err != nil {
+		return nil, nil, err
+	}
+
+	returnLegs, priceRange, err := s.getOffers(ctx, args, [][]Flight{outbound.Flight})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	offers := make([]FullOffer, 0, len(returnLegs))
+	for _, leg := range returnLegs {
+		offer := outbound
+		offer.Price = leg.Price
+		offer.ReturnFlight = leg.Flight
+		offer.ReturnFlightDuration = leg.FlightDuration
+		offer.ReturnLayovers = leg.Layovers
+		offer.Fare = leg.Fare
+		offers = append(offers, offer)
+	}
+	return filterOffers(args, offers), priceRange, nil
+}
+
+// FillReturnFlights completes the round-trip offers returned by [Session.GetOffers]. For the limit
+// cheapest offers (all offers if limit <= 0) it requests the return options with
+// [Session.GetReturnOffers] and fills ReturnFlight and ReturnFlightDuration with the cheapest one.
+// The Price of a completed offer is updated to the price of the chosen round-trip.
+//
+// Offers are modified in place. Offers for which no return option is found are left unchanged.
+// FillReturnFlights returns the first error encountered, but it still tries to complete the remaining offers.
+func (s *Session) FillReturnFlights(ctx context.Context, args Args, offers []FullOffer, limit int) error {
+	if args.TripType != RoundTrip {
+		return nil
+	}
+
+	indexes := make([]int, 0, len(offers))
+	for i := range offers {
+		if len(offers[i].Flight) > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	sortSlice(indexes, func(lv, rv int) bool {
+		return cheaperOffer(offers[lv], offers[rv])
+	})
+	if limit > 0 && len(indexes) > limit {
+		indexes = indexes[:limit]
+	}
+
+	var firstErr error
+	for _, i := range indexes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		returnOffers, _, err := s.GetReturnOffers(ctx, args, offers[i])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("could not get return flights for offer %d: %w", i, err)
+			}
+			continue
+		}
+		if len(returnOffers) == 0 {
+			continue
+		}
+
+		best := returnOffers[0]
+		for _, o := range returnOffers[1:] {
+			if cheaperOffer(o, best) {
+				best = o
+			}
+		}
+		if best.Price == 0 {
+			best.Price = offers[i].Price
+		}
+		offers[i] = best
+	}
+	return firstErr
+}
+
+// segmentFlights returns the flights chosen so far for the segments of a multi-city offer.
+func segmentFlights(offer FullOffer) [][]Flight {
+	if len(offer.SegmentFlights) > 0 {
+		return offer.SegmentFlights
+	}
+	if len(offer.Flight) > 0 {
+		return [][]Flight{offer.Flight}
+	}
+	return nil
+}
+
+// GetNextSegmentOffers retrieves the options of the next segment of a multi-city search once the
+// flights of the previous segments are chosen: offer is one of the offers returned by
+// [Session.GetOffers] for the same args, or one returned by GetNextSegmentOffers.
+//
+// Every returned [FullOffer] extends the SegmentFlights of offer with one option of the next
+// segment. Its Price is the price of the itinerary so far.
+//
+// GetNextSegmentOffers returns an error if args don't describe a multi-city trip, if flights were
+// already chosen for every segment or if any of the requests fail.
+func (s *Session) GetNextSegmentOffers(ctx context.Context, args Args, offer FullOffer) ([]FullOffer, *PriceRange, error) {
+	if args.TripType != MultiCity {
+		return nil, nil, fmt.Errorf("segment offers are only available for multi-city searches")
+	}
+	chosen := segmentFlights(offer)
+	if len(chosen) == 0 {
+		return nil, nil, fmt.Errorf("offer doesn't contain any flights")
+	}
+	if len(chosen) >= len(args.Segments) {
+		return nil, nil, fmt.Errorf("flights of all %d segments are already chosen", len(args.Segments))
+	}
+	if err := args.ValidateOffersArgs(); err != nil {
+		return nil, nil, err
+	}
+
+	legs, priceRange, err := s.getOffers(ctx, args, chosen)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	offers := make([]FullOffer, 0, len(legs))
+	for _, leg := range legs {
+		next := offer
+		next.Price = leg.Price
+		next.SegmentFlights = append(append(make([][]Flight, 0, len(chosen)+1), chosen...), leg.Flight)
+		next.Fare = leg.Fare
+		offers = append(offers, next)
+	}
+	return filterOffers(args, offers), priceRange, nil
+}
+
+// FillMultiCityFlights completes the multi-city offers returned by [Session.GetOffers]. For the limit
+// cheapest offers (all offers if limit <= 0) it chooses the cheapest option of every following
+// segment with [Session.GetNextSegmentOffers], so SegmentFlights contains the flights of all segments.
+// The Price of a completed offer is updated to the price of the whole itinerary.
+//
+// Offers are modified in place. Offers for which a segment has no option are left unchanged.
+// FillMultiCityFlights returns the first error encountered, but it still tries to complete the
+// remaining offers.
+func (s *Session) FillMultiCityFlights(ctx context.Context, args Args, offers []FullOffer, limit int) error {
+	if args.TripType != MultiCity || len(args.Segments) < 2 {
+		return nil
+	}
+
+	indexes := make([]int, 0, len(offers))
+	for i := range offers {
+		if len(offers[i].Flight) > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	sortSlice(indexes, func(lv, rv int) bool {
+		return cheaperOffer(offers[lv], offers[rv])
+	})
+	if limit > 0 && len(indexes) > limit {
+		indexes = indexes[:limit]
+	}
+
+	var firstErr error
+	for _, i := range indexes {
+		offer := offers[i]
+		offer.SegmentFlights = segmentFlights(offer)
+		for len(offer.SegmentFlights) < len(args.Segments) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			nextOffers, _, err := s.GetNextSegmentOffers(ctx, args, offer)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("could not get segment %d flights for offer %d: %w", len(offer.SegmentFlights), i, err)
+				}
+				break
+			}
+			if len(nextOffers) == 0 {
+				break
+			}
+
+			best := nextOffers[0]
+			for _, o := range nextOffers[1:] {
+				if cheaperOffer(o, best) {
+					best = o
+				}
+			}
+			if best.Price == 0 {
+				best.Price = offer.Price
+			}
+			offer = best
+		}
+		if len(offer.SegmentFlights) == len(args.Segments) {
+			offers[i] = offer
+		}
+	}
+	return firstErr
+}
+
+// cheaperOffer orders offers by price. Offers without a price (0) are placed last.
+func cheaperOffer(lv, rv FullOffer) bool {
+	if lv.Price == 0 {
+		return false
+	}
+	if rv.Price == 0 {
+		return true
+	}
+	return lv.Price < rv.Price
+}
@@ -0,0 +1,114 @@
+package flights
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Positions of the decoded attributes in [Flight.Unknown]. They were identified by comparing the
+// Google Flights API responses with the Google Flights website, so they are best-effort and may
+// change as Google updates its internal APIs.
+const (
+	unknownOperatingCarrier = 2  // "Lufthansa CityLine"
+	unknownArrivalDayOffset = 4  // 1 if the flight arrives on the next day
+	unknownAmenities        = 5  // [null,null,null,null,null,true,null,null,null,null,null,3]
+	unknownLegroomCategory  = 6  // 1 average legroom, 2 below average legroom
+	unknownSeatPitch        = 7  // "29 in"
+	unknownCodeshares       = 8  // [["A3","1501",null,"Aegean"],...]
+	unknownDelayHistory     = 10 // [null,true] if the flight is often delayed
+	unknownCO2Emissions     = 19 // grams
+)
+
+// Positions of the amenities in the amenities array of a flight.
+const (
+	amenityPower         = 5  // in-seat power & USB outlets
+	amenityEntertainment = 8  // on-demand video
+	amenityWiFi          = 11 // Wi-Fi (free or for a fee)
+)
+
+// Amenities describes the on-board amenities of a flight.
+type Amenities struct {
+	WiFi          bool // Wi-Fi is available
+	Power         bool // in-seat power or USB outlets are available
+	Entertainment bool // on-demand video is available
+}
+
+func decodeAmenities(raw interface{}) Amenities {
+	amenities, ok := raw.([]interface{})
+	if !ok {
+		return Amenities{}
+	}
+	isSet := func(index int) bool {
+		switch v := getRawElement(amenities, index).(type) {
+		case bool:
+			return v
+		case float64:
+			return v > 0
+		}
+		return false
+	}
+	return Amenities{
+		WiFi:          isSet(amenityWiFi),
+		Power:         isSet(amenityPower),
+		Entertainment: isSet(amenityEntertainment),
+	}
+}
+
+func decodeCodeshares(raw interface{}) []string {
+	rawCodeshares, ok := raw.([]interface{})
+	if !ok || len(rawCodeshares) == 0 {
+		return nil
+	}
+
+	codeshares := make([]string, 0, len(rawCodeshares))
+	for _, rawCodeshare := range rawCodeshares {
+		codeshare, ok := rawCodeshare.([]interface{})
+		if !ok {
+			continue
+		}
+		airline, _ := getElement[string](codeshare, 0)
+		number, _ := getElement[string](codeshare, 1)
+		if airline == "" || number == "" {
+			continue
+		}
+		codeshares = append(codeshares, airline+" "+number)
+	}
+	return codeshares
+}
+
+// decodeSeatPitch turns the seat pitch like "29 in" into inches. It returns 0 for unknown formats.
+func decodeSeatPitch(raw interface{}) int {
+	pitch, ok := raw.(string)
+	if !ok {
+		return 0
+	}
+	inches, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(pitch), "in")))
+	if err != nil {
+		return 0
+	}
+	return inches
+}
+
+// decodeFlightAttributes fills the typed attributes of the flight from its Unknown fields.
+func decodeFlightAttributes(flight *Flight) {
+	unknown := flight.Unknown
+
+	if operatingCarrier, ok := getElement[string](unknown, unknownOperatingCarrier); ok && operatingCarrier != flight.AirlineName {
+		flight.OperatingCarrier = operatingCarrier
+	}
+	if offset, ok := getElement[float64](unknown, unknownArrivalDayOffset); ok && offset > 0 {
+		flight.Overnight = true
+	}
+	flight.Amenities = decodeAmenities(getRawElement(unknown, unknownAmenities))
+	if category, ok := getElement[float64](unknown, unknownLegroomCategory); ok && category == 2 {
+		flight.BelowAverageLegroom = true
+	}
+	flight.SeatPitch = decodeSeatPitch(getRawElement(unknown, unknownSeatPitch))
+	flight.Codeshares = decodeCodeshares(getRawElement(unknown, unknownCodeshares))
+	if delayHistory, ok := getElement[[]interface{}](unknown, unknownDelayHistory); ok {
+		flight.OftenDelayed, _ = getElement[bool](delayHistory, 1)
+	}
+	if co2, ok := getElement[float64](unknown, unknownCO2Emissions); ok {
+		flight.CO2Emissions = int(co2)
+	}
+}
@@ -0,0 +1,172 @@
+package flights
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+type flightAttributes struct {
+	FlightNumber        string    `json:"flight_number"`
+	OperatingCarrier    string    `json:"operating_carrier,omitempty"`
+	Codeshares          []string  `json:"codeshares,omitempty"`
+	CO2Emissions        int       `json:"co2_emissions"`
+	SeatPitch           int       `json:"seat_pitch"`
+	BelowAverageLegroom bool      `json:"below_average_legroom"`
+	Amenities           Amenities `json:"amenities"`
+	Overnight           bool      `json:"overnight"`
+	OftenDelayed        bool      `json:"often_delayed"`
+}
+
+func parseFlightsFixture(t *testing.T, path string) []Flight {
+	respFile, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer respFile.Close()
+
+	body := bufio.NewReader(respFile)
+	skipPrefix(body)
+
+	flights := []Flight{}
+	for {
+		readLine(body) // skip line
+		bytesToDecode, err := getInnerBytes(body)
+		if err != nil {
+			return flights
+		}
+		offers, _, _ := getSectionOffers(bytesToDecode, time.Time{})
+		for _, offer := range offers {
+			flights = append(flights, offer.Flight...)
+		}
+	}
+}
+
+// TestFlightAttributesGolden guards the decoding of Flight.Unknown against Google schema shifts.
+// Run "go test ./flights -run TestFlightAttributesGolden -update" after refreshing the fixture.
+func TestFlightAttributesGolden(t *testing.T) {
+	const goldenPath = "testdata/flight_attributes.golden.json"
+
+	flights := parseFlightsFixture(t, "testdata/flight.resp")
+	if len(flights) == 0 {
+		t.Fatal("no flights parsed")
+	}
+
+	attributes := make([]flightAttributes, 0, len(flights))
+	for _, f := range flights {
+		attributes = append(attributes, flightAttributes{
+			FlightNumber:        f.FlightNumber,
+			OperatingCarrier:    f.OperatingCarrier,
+			Codeshares:          f.Codeshares,
+			CO2Emissions:        f.CO2Emissions,
+			SeatPitch:           f.SeatPitch,
+			BelowAverageLegroom: f.BelowAverageLegroom,
+			Amenities:           f.Amenities,
+			Overnight:           f.Overnight,
+			OftenDelayed:        f.OftenDelayed,
+		})
+	}
+
+	if *updateGolden {
+		data, err := json.MarshalIndent(attributes, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenPath, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []flightAttributes{}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expected, attributes); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
+// TestFlightAttributesKnownFlights pins the attributes of the WAW-MUC-ATH itinerary in the fixture
+// by hand, so a regenerated golden file cannot silently accept a decoding regression.
+func TestFlightAttributesKnownFlights(t *testing.T) {
+	flights := parseFlightsFixture(t, "testdata/flight.resp")
+
+	byNumber := map[string]Flight{}
+	for _, f := range flights {
+		if _, ok := byNumber[f.FlightNumber]; !ok {
+			byNumber[f.FlightNumber] = f
+		}
+	}
+
+	first, ok := byNumber["LH 1615"]
+	if !ok {
+		t.Fatal("flight LH 1615 not parsed")
+	}
+	if first.OperatingCarrier != "Lufthansa CityLine" {
+		t.Errorf("LH 1615 operating carrier = %q, expected %q", first.OperatingCarrier, "Lufthansa CityLine")
+	}
+	if diff := deep.Equal([]string{"A3 1501"}, first.Codeshares); diff != nil {
+		t.Errorf("LH 1615 codeshares: %v", diff)
+	}
+	if first.CO2Emissions != 115320 {
+		t.Errorf("LH 1615 CO2 emissions = %d, expected 115320", first.CO2Emissions)
+	}
+	if first.SeatPitch != 29 || !first.BelowAverageLegroom {
+		t.Errorf("LH 1615 legroom = %d in (below average %v), expected 29 in below average", first.SeatPitch, first.BelowAverageLegroom)
+	}
+	if first.Amenities != (Amenities{Power: true}) {
+		t.Errorf("LH 1615 amenities = %+v, expected power only", first.Amenities)
+	}
+	if first.Overnight || first.OftenDelayed {
+		t.Errorf("LH 1615 overnight = %v, often delayed = %v, expected neither", first.Overnight, first.OftenDelayed)
+	}
+
+	second, ok := byNumber["LH 1756"]
+	if !ok {
+		t.Fatal("flight LH 1756 not parsed")
+	}
+	if diff := deep.Equal([]string{"A3 1808"}, second.Codeshares); diff != nil {
+		t.Errorf("LH 1756 codeshares: %v", diff)
+	}
+	if second.CO2Emissions != 195400 {
+		t.Errorf("LH 1756 CO2 emissions = %d, expected 195400", second.CO2Emissions)
+	}
+	if !second.Overnight || !second.OftenDelayed {
+		t.Errorf("LH 1756 overnight = %v, often delayed = %v, expected both", second.Overnight, second.OftenDelayed)
+	}
+}
+
+func TestDecodeFlightAttributesMissingData(t *testing.T) {
+	flight := Flight{Unknown: make([]interface{}, 3)}
+	decodeFlightAttributes(&flight)
+
+	if diff := deep.Equal(Flight{Unknown: make([]interface{}, 3)}, flight); diff != nil {
+		t.Fatalf("attributes decoded from empty data: %v", diff)
+	}
+}
+
+func TestDecodeSeatPitch(t *testing.T) {
+	for input, expected := range map[interface{}]int{
+		"29 in": 29,
+		"31in":  31,
+		"":      0,
+		nil:     0,
+		30.0:    0,
+	} {
+		if got := decodeSeatPitch(input); got != expected {
+			t.Errorf("decodeSeatPitch(%v) = %d, expected %d", input, got, expected)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -218,6 +219,13 @@ func TestGetOffersMock(t *testing.T) {
 			FlightNumber:   "LH 1615",
 			AirlineName:    "Lufthansa",
 			Legroom:        "29 inches",
+
+			OperatingCarrier:    "Lufthansa CityLine",
+			Codeshares:          []string{"A3 1501"},
+			CO2Emissions:        115320,
+			SeatPitch:           29,
+			BelowAverageLegroom: true,
+			Amenities:           Amenities{Power: true},
 		}, {
 			DepAirportCode: "MUC",
 			DepAirportName: "Munich International Airport",
@@ -232,6 +240,14 @@ func TestGetOffersMock(t *testing.T) {
 			FlightNumber:   "LH 1756",
 			AirlineName:    "Lufthansa",
 			Legroom:        "29 inches",
+
+			Codeshares:          []string{"A3 1808"},
+			CO2Emissions:        195400,
+			SeatPitch:           29,
+			BelowAverageLegroom: true,
+			Amenities:           Amenities{Power: true},
+			Overnight:           true,
+			OftenDelayed:        true,
 		}},
 		ReturnFlight:   []Flight{},
 		SrcAirportCode: "WAW",
@@ -239,6 +255,13 @@ func TestGetOffersMock(t *testing.T) {
 		SrcCity:        "Warsaw",
 		DstCity:        "Athens",
 		FlightDuration: d3,
+		Layovers: []Layover{{
+			ArrAirportCode: "MUC",
+			DepAirportCode: "MUC",
+			City:           "Munich",
+			Duration:       t3.Sub(t2),
+		}},
+		Fare: Fare{SeatSelection: true},
 	}
 	expectedPriceRange := PriceRange{1300, 2300}
 
@@ -383,3 +406,256 @@ func TestFlightReqData(t *testing.T) {
 		t.Fatalf("wrong unescaped query, expected: %s received: %s", expectedReqData2, reqData2)
 	}
 }
+
+func TestSerializeSelectedFlights(t *testing.T) {
+	if got := serializeSelectedFlights(nil); got != "[]" {
+		t.Fatalf("wrong serialization of empty selection: %s", got)
+	}
+
+	selected := []Flight{{
+		DepAirportCode: "WAW",
+		ArrAirportCode: "MUC",
+		DepTime:        time.Date(2024, 1, 22, 17, 0, 0, 0, time.UTC),
+		FlightNumber:   "LH 1615",
+	}, {
+		DepAirportCode: "MUC",
+		ArrAirportCode: "ATH",
+		DepTime:        time.Date(2024, 1, 22, 21, 25, 0, 0, time.UTC),
+		FlightNumber:   "LH 1756",
+	}}
+
+	expected := `[[\"WAW\",\"2024-01-22\",\"MUC\",null,\"LH\",\"1615\"],[\"MUC\",\"2024-01-22\",\"ATH\",null,\"LH\",\"1756\"]]`
+	if got := serializeSelectedFlights(selected); got != expected {
+		t.Fatalf("wrong serialization, expected: %s received: %s", expected, got)
+	}
+}
+
+// No return-leg response has been recorded yet: testdata/flight.resp is the outbound WAW-ATH
+// response, reused as the return leg of an ATH-WAW search. The test therefore checks the request
+// and how the return flights are attached to the outbound offer, not that Google answers the
+// selected-flight request in this layout. Replace it with a scrubbed GetShoppingResults response
+// to a selected-flight request, recorded with FLIGHTS_RECORD_DIR, once one is available.
+func TestGetReturnOffersMock(t *testing.T) {
+	timeNow = func() time.Time {
+		t, _ := time.Parse(time.RFC3339, "2024-01-15T00:00:00Z")
+		return t
+	}
+	defer func() { timeNow = time.Now }()
+
+	date, _ := time.Parse(time.RFC3339, "2024-01-20T00:00:00Z")
+	returnDate, _ := time.Parse(time.RFC3339, "2024-01-25T00:00:00Z")
+
+	outbound := FullOffer{
+		Offer: Offer{StartDate: date, ReturnDate: returnDate, Price: 1000},
+		Flight: []Flight{{
+			DepAirportCode: "ATH",
+			ArrAirportCode: "WAW",
+			DepTime:        date,
+			ArrTime:        date.Add(3 * time.Hour),
+			FlightNumber:   "LO 432",
+		}},
+		SrcAirportCode: "ATH",
+		DstAirportCode: "WAW",
+		FlightDuration: 3 * time.Hour,
+	}
+
+	httpClientMock, err := newHttpClientMock(t, "testdata/flight.resp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := &Session{
+		client: httpClientMock,
+	}
+
+	args := Args{
+		Date:        date,
+		ReturnDate:  returnDate,
+		SrcAirports: []string{"ATH"},
+		DstAirports: []string{"WAW"},
+		Options:     OptionsDefault(),
+	}
+
+	offers, _, err := session.GetReturnOffers(context.Background(), args, outbound)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(offers) != 21 {
+		t.Fatalf("Not all return offers parsed. Expected number of offers: 21, parsed: %d", len(offers))
+	}
+
+	offer := offers[0]
+	if diff := deep.Equal(outbound.Flight, offer.Flight); diff != nil {
+		t.Fatalf("Outbound flights changed: %v", diff)
+	}
+	if len(offer.ReturnFlight) != 2 {
+		t.Fatalf("Wrong number of return flights: %d", len(offer.ReturnFlight))
+	}
+	if offer.ReturnFlight[0].FlightNumber != "LH 1615" || offer.ReturnFlight[1].ArrAirportCode != "ATH" {
+		t.Fatalf("Wrong return flights: %v", offer.ReturnFlight)
+	}
+	if offer.ReturnFlightDuration != 6*time.Hour+50*time.Minute {
+		t.Fatalf("Wrong return flight duration: %s", offer.ReturnFlightDuration)
+	}
+	if offer.Price != 1315 {
+		t.Fatalf("Wrong round-trip price: %f", offer.Price)
+	}
+
+	if len(httpClientMock.Requests) != 1 {
+		t.Fatalf("Wrong number of requests: %d", len(httpClientMock.Requests))
+	}
+	body, err := httpClientMock.Requests[0].BodyBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqData, err := url.QueryUnescape(string(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(reqData, `[[\"ATH\",\"2024-01-20\",\"WAW\",null,\"LO\",\"432\"]]`) {
+		t.Fatalf("Selected outbound flight missing in the request: %s", reqData)
+	}
+}
+
+func TestGetReturnOffersOneWay(t *testing.T) {
+	session := &Session{}
+
+	args := Args{Options: OptionsDefault()}
+	args.TripType = OneWay
+
+	if _, _, err := session.GetReturnOffers(context.Background(), args, FullOffer{}); err == nil {
+		t.Fatal("expected an error for a one-way search")
+	}
+}
+
+func TestFillReturnFlightsMock(t *testing.T) {
+	timeNow = func() time.Time {
+		t, _ := time.Parse(time.RFC3339, "2024-01-15T00:00:00Z")
+		return t
+	}
+	defer func() { timeNow = time.Now }()
+
+	date, _ := time.Parse(time.RFC3339, "2024-01-20T00:00:00Z")
+	returnDate, _ := time.Parse(time.RFC3339, "2024-01-25T00:00:00Z")
+
+	outbound := func(price float64, flightNumber string) FullOffer {
+		return FullOffer{
+			Offer: Offer{StartDate: date, ReturnDate: returnDate, Price: price},
+			Flight: []Flight{{
+				DepAirportCode: "ATH",
+				ArrAirportCode: "WAW",
+				DepTime:        date,
+				FlightNumber:   flightNumber,
+			}},
+		}
+	}
+	offers := []FullOffer{outbound(2000, "LO 432"), outbound(900, "A3 880")}
+
+	// Only the cheapest offer is completed, so only one response is needed. Like
+	// TestGetReturnOffersMock, this reuses the outbound fixture as the return leg.
+	httpClientMock, err := newHttpClientMock(t, "testdata/flight.resp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &Session{client: httpClientMock}
+
+	args := Args{
+		Date:        date,
+		ReturnDate:  returnDate,
+		SrcAirports: []string{"ATH"},
+		DstAirports: []string{"WAW"},
+		Options:     OptionsDefault(),
+	}
+
+	if err := session.FillReturnFlights(context.Background(), args, offers, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(offers[0].ReturnFlight) != 0 {
+		t.Fatalf("The more expensive offer shouldn't be completed: %v", offers[0].ReturnFlight)
+	}
+	if len(offers[1].ReturnFlight) == 0 {
+		t.Fatal("The cheapest offer wasn't completed")
+	}
+	if offers[1].Flight[0].FlightNumber != "A3 880" {
+		t.Fatalf("Wrong outbound flight: %s", offers[1].Flight[0].FlightNumber)
+	}
+	if offers[1].ReturnFlightDuration == 0 {
+		t.Fatal("Missing return flight duration")
+	}
+}
+
+func TestFillMultiCityFlightsMock(t *testing.T) {
+	timeNow = func() time.Time {
+		t, _ := time.Parse(time.RFC3339, "2024-01-15T00:00:00Z")
+		return t
+	}
+	defer func() { timeNow = time.Now }()
+
+	date, _ := time.Parse(time.RFC3339, "2024-01-20T00:00:00Z")
+	secondDate, _ := time.Parse(time.RFC3339, "2024-01-25T00:00:00Z")
+
+	offers := []FullOffer{{
+		Offer: Offer{StartDate: date, Price: 700},
+		Flight: []Flight{{
+			DepAirportCode: "ATH",
+			ArrAirportCode: "WAW",
+			DepTime:        date,
+			FlightNumber:   "LO 432",
+		}},
+	}}
+
+	httpClientMock, err := newHttpClientMock(t, "testdata/flight.resp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &Session{client: httpClientMock}
+
+	options := OptionsDefault()
+	options.TripType = MultiCity
+	args := Args{
+		Options: options,
+		Segments: []Segment{
+			{Date: date, SrcAirports: []string{"ATH"}, DstAirports: []string{"WAW"}},
+			{Date: secondDate, SrcAirports: []string{"WAW"}, DstAirports: []string{"ATH"}},
+		},
+	}
+
+	if err := session.FillMultiCityFlights(context.Background(), args, offers, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	offer := offers[0]
+	if len(offer.SegmentFlights) != 2 {
+		t.Fatalf("Wrong number of segments: %d", len(offer.SegmentFlights))
+	}
+	if offer.SegmentFlights[0][0].FlightNumber != "LO 432" {
+		t.Fatalf("First segment changed: %v", offer.SegmentFlights[0])
+	}
+	if len(offer.SegmentFlights[1]) == 0 || offer.SegmentFlights[1][len(offer.SegmentFlights[1])-1].ArrAirportCode != "ATH" {
+		t.Fatalf("Wrong second segment: %v", offer.SegmentFlights[1])
+	}
+	if offer.Price == 700 {
+		t.Fatal("Price of the whole itinerary wasn't set")
+	}
+
+	body, err := httpClientMock.Requests[0].BodyBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqData, err := url.QueryUnescape(string(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(reqData, `\"2024-01-20\",null,[[\"ATH\",\"2024-01-20\",\"WAW\",null,\"LO\",\"432\"]]`) {
+		t.Fatalf("Selected first segment missing in the request: %s", reqData)
+	}
+	if !strings.Contains(reqData, `\"2024-01-25\",null,[],`) {
+		t.Fatalf("Second segment shouldn't have a selection: %s", reqData)
+	}
+
+	if _, _, err := session.GetNextSegmentOffers(context.Background(), args, offer); err == nil {
+		t.Fatal("expected an error once all segments are chosen")
+	}
+}
@@ -0,0 +1,104 @@
+package flights
+
+import (
+	"fmt"
+	"time"
+)
+
+// Connections shorter than these durations are marked as [Layover.ShortConnection]. A change of
+// airport needs more time, because it usually involves leaving the airside area.
+const (
+	shortConnection              = time.Hour
+	shortConnectionAirportChange = 3 * time.Hour
+)
+
+// Layover describes the connection between two consecutive flights of a trip.
+//
+// There is deliberately no self-transfer flag: none of the captured responses in testdata has a
+// self-transfer itinerary, so the position of Google's marker is unknown, and deriving it from the
+// carriers would flag ordinary interline and codeshare connections. ChangeOfAirport and
+// ShortConnection cover the connections a self transfer makes risky.
+type Layover struct {
+	ArrAirportCode  string        // airport where the previous flight arrives
+	DepAirportCode  string        // airport where the next flight departs
+	City            string        // city of the connection
+	Duration        time.Duration // time between the arrival and the next departure
+	ChangeOfAirport bool          // the next flight departs from another airport
+	Overnight       bool          // the connection spans the local midnight
+	ShortConnection bool          // the connection is shorter than the recommended minimum
+}
+
+func (l Layover) String() string {
+	out := ""
+	out += fmt.Sprintf("{ArrAirportCode: %s ", l.ArrAirportCode)
+	out += fmt.Sprintf("DepAirportCode: %s ", l.DepAirportCode)
+	out += fmt.Sprintf("City: %s ", l.City)
+	out += fmt.Sprintf("Duration: %s ", l.Duration)
+	out += fmt.Sprintf("ChangeOfAirport: %t ", l.ChangeOfAirport)
+	out += fmt.Sprintf("Overnight: %t ", l.Overnight)
+	out += fmt.Sprintf("ShortConnection: %t}", l.ShortConnection)
+	return out
+}
+
+// getLayovers derives the connections of a trip from its consecutive flights. The flight times are
+// located in the time zones of their airports, so the overnight check uses the local time of the
+// connection.
+func getLayovers(flights []Flight) []Layover {
+	if len(flights) < 2 {
+		return []Layover{}
+	}
+
+	layovers := make([]Layover, 0, len(flights)-1)
+	for i := 1; i < len(flights); i++ {
+		prev, next := flights[i-1], flights[i]
+
+		layover := Layover{
+			ArrAirportCode:  prev.ArrAirportCode,
+			DepAirportCode:  next.DepAirportCode,
+			City:            prev.ArrCity,
+			Duration:        next.DepTime.Sub(prev.ArrTime),
+			ChangeOfAirport: prev.ArrAirportCode != next.DepAirportCode,
+		}
+
+		arrDay := truncateToDay(prev.ArrTime)
+		depDay := truncateToDay(next.DepTime.In(prev.ArrTime.Location()))
+		layover.Overnight = depDay.After(arrDay)
+
+		minConnection := shortConnection
+		if layover.ChangeOfAirport {
+			minConnection = shortConnectionAirportChange
+		}
+		layover.ShortConnection = layover.Duration < minConnection
+
+		layovers = append(layovers, layover)
+	}
+	return layovers
+}
+
+// allowsLayovers checks the layovers against the MinLayover and MaxLayover of the args.
+func (a *Args) allowsLayovers(layovers []Layover) bool {
+	for _, l := range layovers {
+		if a.MinLayover > 0 && l.Duration < a.MinLayover {
+			return false
+		}
+		if a.MaxLayover > 0 && l.Duration > a.MaxLayover {
+			return false
+		}
+	}
+	return true
+}
+
+func filterOffersByLayovers(args Args, offers []FullOffer) []FullOffer {
+	if args.MinLayover <= 0 && args.MaxLayover <= 0 {
+		return offers
+	}
+
+	filtered := make([]FullOffer, 0, len(offers))
+	for _, offer := range offers {
+		if !args.allowsLayovers(offer.Layovers) || !args.allowsLayovers(offer.ReturnLayovers) {
+			continue
+		}
+		filtered = append(filtered, offer)
+	}
+	return filtered
+}
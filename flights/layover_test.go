@@ -0,0 +1,85 @@
+package flights
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestGetLayovers(t *testing.T) {
+	waw, _ := time.LoadLocation("Europe/Warsaw")
+	lhr, _ := time.LoadLocation("Europe/London")
+	jfk, _ := time.LoadLocation("America/New_York")
+
+	flights := []Flight{{
+		DepAirportCode: "WAW",
+		ArrAirportCode: "LHR",
+		ArrCity:        "London",
+		DepTime:        time.Date(2024, 3, 1, 18, 0, 0, 0, waw),
+		ArrTime:        time.Date(2024, 3, 1, 19, 30, 0, 0, lhr),
+	}, {
+		DepAirportCode: "LGW",
+		ArrAirportCode: "JFK",
+		ArrCity:        "New York",
+		DepTime:        time.Date(2024, 3, 2, 6, 30, 0, 0, lhr),
+		ArrTime:        time.Date(2024, 3, 2, 9, 30, 0, 0, jfk),
+	}, {
+		DepAirportCode: "JFK",
+		ArrAirportCode: "BOS",
+		DepTime:        time.Date(2024, 3, 2, 10, 5, 0, 0, jfk),
+		ArrTime:        time.Date(2024, 3, 2, 11, 20, 0, 0, jfk),
+	}}
+
+	expected := []Layover{{
+		ArrAirportCode:  "LHR",
+		DepAirportCode:  "LGW",
+		City:            "London",
+		Duration:        11 * time.Hour,
+		ChangeOfAirport: true,
+		Overnight:       true,
+	}, {
+		ArrAirportCode:  "JFK",
+		DepAirportCode:  "JFK",
+		City:            "New York",
+		Duration:        35 * time.Minute,
+		ShortConnection: true,
+	}}
+
+	if diff := deep.Equal(expected, getLayovers(flights)); diff != nil {
+		t.Fatal(diff)
+	}
+
+	if layovers := getLayovers(flights[:1]); len(layovers) != 0 {
+		t.Fatalf("nonstop flight shouldn't have layovers: %v", layovers)
+	}
+}
+
+func TestFilterOffersByLayovers(t *testing.T) {
+	short := FullOffer{Layovers: []Layover{{Duration: 35 * time.Minute}}}
+	long := FullOffer{Layovers: []Layover{{Duration: 11 * time.Hour}}}
+	longReturn := FullOffer{ReturnLayovers: []Layover{{Duration: 11 * time.Hour}}}
+	nonstop := FullOffer{}
+	offers := []FullOffer{short, long, longReturn, nonstop}
+
+	if filtered := filterOffersByLayovers(Args{}, offers); len(filtered) != 4 {
+		t.Fatalf("offers shouldn't be filtered without limits: %d", len(filtered))
+	}
+
+	filtered := filterOffersByLayovers(Args{MinLayover: time.Hour, MaxLayover: 6 * time.Hour}, offers)
+	if diff := deep.Equal([]FullOffer{nonstop}, filtered); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
+func TestValidateLayovers(t *testing.T) {
+	args := Args{MinLayover: 2 * time.Hour, MaxLayover: time.Hour}
+	if err := args.ValidateOffersArgs(); err == nil {
+		t.Fatal("expected an error when MinLayover is higher than MaxLayover")
+	}
+
+	args = Args{MinLayover: -time.Hour}
+	if err := args.ValidateOffersArgs(); err == nil {
+		t.Fatal("expected an error for a negative MinLayover")
+	}
+}
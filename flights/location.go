@@ -13,6 +13,7 @@ import (
 	"time"
 
 	anyascii "github.com/anyascii/go"
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/hashicorp/go-retryablehttp"
 	"golang.org/x/text/language"
 )
@@ -22,9 +23,9 @@ func getCityReqData(city string) string {
 }
 
 func (s *Session) doRequestLocation(ctx context.Context, city string, lang language.Tag) (*http.Response, error) {
-	requestURL := "https://www.google.com/_/FlightsFrontendUi/data/batchexecute?rpcids=H028ib&source-path=%2Ftravel%2Fflights%2Fsearch&f.sid=-8421128425468344897&bl=boq_travel-frontend-ui_20230613.06_p0" +
+	requestURL := s.url("/_/FlightsFrontendUi/data/batchexecute?rpcids=H028ib&source-path=%2Ftravel%2Fflights%2Fsearch&f.sid=-8421128425468344897&bl=boq_travel-frontend-ui_20230613.06_p0" +
 		"&hl=" + lang.String() +
-		"&soc-app=162&soc-platform=1&soc-device=1&_reqid=444052&rt=c"
+		"&soc-app=162&soc-platform=1&soc-device=1&_reqid=444052&rt=c")
 
 	jsonBody := []byte(
 		`f.req=` + getCityReqData(city) +
@@ -41,7 +42,11 @@ func (s *Session) doRequestLocation(ctx context.Context, city string, lang langu
 	req.Header.Set("pragma", "no-cache")
 	req.Header.Set("user-agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/113.0.0.0 Safari/537.36")
 
-	return s.client.Do(req)
+	resp, err := s.do(req, ratelimit.KindLocation)
+	if err != nil {
+		return nil, err
+	}
+	return checkResponse(resp)
 }
 
 func abbrCitySchema(city, abbrCity *string) *[][][][]interface{} {
@@ -0,0 +1,120 @@
+package flights
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Market defaults used when the corresponding [Options] fields are empty. They match the requests
+// of the Google Flights website opened in the US.
+const (
+	DefaultCountry        = "US"
+	DefaultTimezoneOffset = -120
+)
+
+var (
+	countryPattern    = regexp.MustCompile(`^[A-Z]{2}$`)
+	googleHostPattern = regexp.MustCompile(`^www\.google\.[a-z]{2,3}(\.[a-z]{2})?$`)
+)
+
+// ValidateMarket checks the point-of-sale fields of [Options]. The Google host is restricted to
+// www.google.<tld>, because it may come from the API users.
+func (o *Options) ValidateMarket() error {
+	if o.Country != "" && !countryPattern.MatchString(strings.ToUpper(o.Country)) {
+		return fmt.Errorf("country '%s' is not an ISO 3166-1 alpha-2 code", o.Country)
+	}
+	if o.GoogleHost != "" && !googleHostPattern.MatchString(strings.ToLower(o.GoogleHost)) {
+		return fmt.Errorf("google host '%s' is not a www.google.<tld> domain", o.GoogleHost)
+	}
+	if o.TimezoneOffset != nil && (*o.TimezoneOffset < -14*60 || *o.TimezoneOffset > 12*60) {
+		return fmt.Errorf("timezone offset %d is out of range", *o.TimezoneOffset)
+	}
+	return nil
+}
+
+func (o *Options) country() string {
+	if o.Country == "" {
+		return DefaultCountry
+	}
+	return strings.ToUpper(o.Country)
+}
+
+func (o *Options) timezoneOffset() int {
+	if o.TimezoneOffset == nil {
+		return DefaultTimezoneOffset
+	}
+	return *o.TimezoneOffset
+}
+
+// locale returns the language and region sent to Google, e.g. "en-US". Languages without a region
+// get the point-of-sale country.
+func (o *Options) locale() string {
+	lang := o.Lang
+	if lang == language.Und {
+		lang = language.English
+	}
+	base, _ := lang.Base()
+	if region, confidence := lang.Region(); confidence == language.Exact {
+		return base.String() + "-" + region.String()
+	}
+	return base.String() + "-" + o.country()
+}
+
+// hl returns the language of the hl query parameter, e.g. "en".
+func (o *Options) hl() string {
+	lang, _, _ := strings.Cut(o.locale(), "-")
+	return lang
+}
+
+// marketHeaders returns the headers which tell Google the language, the point of sale, the
+// currency and the timezone of the user. experiments are the feature flags sent by the website.
+func (o *Options) marketHeaders(experiments string) map[string]string {
+	return map[string]string{
+		"accept-language": o.locale() + ",en;q=0.9",
+		"x-goog-ext-259736195-jspb": fmt.Sprintf(`["%s","%s","%s",1,null,[%d],null,[[%s]],1,[]]`,
+			o.locale(), o.country(), o.Currency, o.timezoneOffset(), experiments),
+	}
+}
+
+// marketURL returns the address of the Google endpoint under path on the host of opts. Sessions
+// with a custom base URL (e.g. a test server) keep it.
+func (s *Session) marketURL(opts Options, path string) string {
+	if opts.GoogleHost == "" || (s.baseURL != "" && s.baseURL != DefaultBaseURL) {
+		return s.url(path)
+	}
+	return "https://" + strings.ToLower(opts.GoogleHost) + path
+}
+
+// marketCookies returns the cookies of the requests sent to the host of opts. Google sets its
+// cookies per host, so the cookies of another host than the base URL are requested from it on
+// first use and kept by the session. Sessions with cookies from [SessionOptions.Cookies] or
+// without cookies (replay mode) send theirs everywhere.
+func (s *Session) marketCookies(ctx context.Context, opts Options) ([]string, error) {
+	baseURL := s.marketURL(opts, "")
+	if !s.cookiesPerHost || baseURL == s.url("") {
+		return s.cookies, nil
+	}
+
+	s.hostCookiesMu.Lock()
+	cookies, ok := s.hostCookies[baseURL]
+	s.hostCookiesMu.Unlock()
+	if ok {
+		return cookies, nil
+	}
+
+	cookies, err := s.initCookies(ctx, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	s.hostCookiesMu.Lock()
+	defer s.hostCookiesMu.Unlock()
+	if s.hostCookies == nil {
+		s.hostCookies = map[string][]string{}
+	}
+	s.hostCookies[baseURL] = cookies
+	return cookies, nil
+}
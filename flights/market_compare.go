@@ -0,0 +1,153 @@
+package flights
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"golang.org/x/text/currency"
+)
+
+// Market is a point of sale compared by [Session.CompareMarkets].
+type Market struct {
+	Country    string        // point-of-sale country, see [Options.Country]
+	Currency   currency.Unit // currency charged in this market; the zero value means the reporting currency
+	GoogleHost string        // see [Options.GoogleHost]
+}
+
+func (m Market) String() string {
+	if m.Currency == (currency.Unit{}) {
+		return m.Country
+	}
+	return m.Country + "/" + m.Currency.String()
+}
+
+// MarketOffer is the cheapest offer of an itinerary in one [Market].
+type MarketOffer struct {
+	Market
+	Offer           *FullOffer    // cheapest offer; nil if the market returned none
+	Currency        currency.Unit // currency of Price
+	Price           float64       // price charged in the market
+	NormalizedPrice float64       // price in the reporting currency
+	Err             error         // error of the market's search
+}
+
+// MarketComparison is the result of [Session.CompareMarkets].
+type MarketComparison struct {
+	Currency currency.Unit // reporting currency
+	Offers   []MarketOffer // sorted by NormalizedPrice; markets without a price come last
+}
+
+// Cheapest returns the market with the lowest normalized price, or nil if no market has a price.
+func (c *MarketComparison) Cheapest() *MarketOffer {
+	if len(c.Offers) == 0 || c.Offers[0].Offer == nil {
+		return nil
+	}
+	return &c.Offers[0]
+}
+
+// Savings returns the difference between the most expensive and the cheapest priced market in the
+// reporting currency.
+func (c *MarketComparison) Savings() float64 {
+	cheapest := c.Cheapest()
+	if cheapest == nil {
+		return 0
+	}
+	highest := cheapest.NormalizedPrice
+	for _, o := range c.Offers {
+		if o.Offer != nil && o.NormalizedPrice > highest {
+			highest = o.NormalizedPrice
+		}
+	}
+	return highest - cheapest.NormalizedPrice
+}
+
+// Converter converts amount from one currency to another. It is used by [Session.CompareMarkets]
+// to normalize prices.
+type Converter func(ctx context.Context, amount float64, from, to currency.Unit) (float64, error)
+
+// CompareMarkets runs the search described by args in every market and reports the cheapest
+// offer of each of them. args.Currency is the reporting currency.
+//
+// A market is searched in its own currency only if convert is set; otherwise Google is asked for
+// the reporting currency, so the prices are comparable without exchange rates. A market whose
+// search fails keeps the error in [MarketOffer.Err]. CompareMarkets returns an error if the
+// arguments are invalid or the search failed in every market.
+func (s *Session) CompareMarkets(ctx context.Context, args Args, markets []Market, convert Converter) (*MarketComparison, error) {
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("compare markets: no markets")
+	}
+	for _, m := range markets {
+		market := Options{Country: m.Country, GoogleHost: m.GoogleHost}
+		if err := market.ValidateMarket(); err != nil {
+			return nil, fmt.Errorf("compare markets: %w", err)
+		}
+	}
+
+	comparison := &MarketComparison{Currency: args.Currency}
+	failed := 0
+	var lastErr error
+
+	for _, m := range markets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		marketArgs := args
+		marketArgs.Country = m.Country
+		marketArgs.GoogleHost = m.GoogleHost
+		if convert != nil && m.Currency != (currency.Unit{}) {
+			marketArgs.Currency = m.Currency
+		}
+
+		result := MarketOffer{Market: m, Currency: marketArgs.Currency}
+		offers, _, err := s.GetOffers(ctx, marketArgs)
+		if err == nil {
+			result.Offer = cheapestOffer(offers)
+		}
+		if err == nil && result.Offer != nil {
+			result.Price = result.Offer.Price
+			result.NormalizedPrice = result.Price
+			if result.Currency != args.Currency {
+				result.NormalizedPrice, err = convert(ctx, result.Price, result.Currency, args.Currency)
+				if err != nil {
+					err = fmt.Errorf("convert %s to %s: %w", result.Currency, args.Currency, err)
+					result.Offer = nil
+				}
+			}
+		}
+		if err != nil {
+			result.Err = err
+			failed++
+			lastErr = err
+		}
+		comparison.Offers = append(comparison.Offers, result)
+	}
+
+	if failed == len(markets) {
+		return nil, fmt.Errorf("compare markets: all searches failed: %w", lastErr)
+	}
+
+	sort.SliceStable(comparison.Offers, func(i, j int) bool {
+		oi, oj := comparison.Offers[i], comparison.Offers[j]
+		if (oi.Offer == nil) != (oj.Offer == nil) {
+			return oi.Offer != nil
+		}
+		return oi.NormalizedPrice < oj.NormalizedPrice
+	})
+	return comparison, nil
+}
+
+// cheapestOffer returns the priced offer with the lowest price.
+func cheapestOffer(offers []FullOffer) *FullOffer {
+	var cheapest *FullOffer
+	for i := range offers {
+		if offers[i].Price <= 0 {
+			continue
+		}
+		if cheapest == nil || offers[i].Price < cheapest.Price {
+			cheapest = &offers[i]
+		}
+	}
+	return cheapest
+}
@@ -0,0 +1,79 @@
+package flights
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/currency"
+)
+
+func TestCompareMarkets(t *testing.T) {
+	httpClientMock, err := newHttpClientMock(t, "testdata/flight.resp", "testdata/flight.resp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &Session{client: httpClientMock, Cities: Map[string, string]{}}
+
+	convert := func(ctx context.Context, amount float64, from, to currency.Unit) (float64, error) {
+		if from != currency.EUR || to != currency.USD {
+			t.Fatalf("unexpected conversion %s -> %s", from, to)
+		}
+		return amount / 2, nil
+	}
+
+	markets := []Market{
+		{Country: "US"},
+		{Country: "DE", Currency: currency.EUR, GoogleHost: "www.google.de"},
+	}
+	comparison, err := session.CompareMarkets(context.Background(), testOffersArgs(), markets, convert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(httpClientMock.Requests) != 2 {
+		t.Fatalf("wrong number of requests: %d", len(httpClientMock.Requests))
+	}
+	deRequest := httpClientMock.Requests[1]
+	if deRequest.URL.Host != "www.google.de" {
+		t.Fatalf("DE market requested from %s", deRequest.URL.Host)
+	}
+	if header := deRequest.Header.Get("x-goog-ext-259736195-jspb"); !strings.HasPrefix(header, `["en-DE","DE","EUR"`) {
+		t.Fatalf("wrong DE market header: %s", header)
+	}
+
+	cheapest := comparison.Cheapest()
+	if cheapest == nil || cheapest.Country != "DE" {
+		t.Fatalf("DE should be the cheapest market: %+v", comparison.Offers)
+	}
+	if cheapest.NormalizedPrice != cheapest.Price/2 || cheapest.Currency != currency.EUR {
+		t.Fatalf("wrong normalized price: %+v", cheapest)
+	}
+	if comparison.Savings() != cheapest.Price/2 {
+		t.Fatalf("wrong savings: %f", comparison.Savings())
+	}
+}
+
+func TestCompareMarketsWithoutConverter(t *testing.T) {
+	httpClientMock, err := newHttpClientMock(t, "testdata/flight.resp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &Session{client: httpClientMock, Cities: Map[string, string]{}}
+
+	comparison, err := session.CompareMarkets(context.Background(), testOffersArgs(), []Market{{Country: "IN", Currency: currency.INR}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without a converter the market is searched in the reporting currency.
+	if header := httpClientMock.Requests[0].Header.Get("x-goog-ext-259736195-jspb"); !strings.HasPrefix(header, `["en-IN","IN","USD"`) {
+		t.Fatalf("wrong IN market header: %s", header)
+	}
+	if offer := comparison.Offers[0]; offer.Currency != currency.USD || offer.Price != offer.NormalizedPrice {
+		t.Fatalf("wrong market offer: %+v", offer)
+	}
+
+	if _, err := session.CompareMarkets(context.Background(), testOffersArgs(), []Market{{Country: "Germany"}}, nil); err == nil {
+		t.Fatal("invalid market should fail")
+	}
+}
@@ -0,0 +1,154 @@
+package flights
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+func TestMarketHeaders(t *testing.T) {
+	opts := OptionsDefault()
+	headers := opts.marketHeaders("1,2")
+	if got := headers["x-goog-ext-259736195-jspb"]; got != `["en-US","US","USD",1,null,[-120],null,[[1,2]],1,[]]` {
+		t.Fatalf("default header: %s", got)
+	}
+	if got := headers["accept-language"]; got != "en-US,en;q=0.9" {
+		t.Fatalf("default accept-language: %s", got)
+	}
+
+	offset := -330
+	opts.Lang = language.German
+	opts.Country = "in"
+	opts.Currency = currency.INR
+	opts.TimezoneOffset = &offset
+	if got := opts.marketHeaders("1")["x-goog-ext-259736195-jspb"]; got != `["de-IN","IN","INR",1,null,[-330],null,[[1]],1,[]]` {
+		t.Fatalf("market header: %s", got)
+	}
+	if got := opts.hl(); got != "de" {
+		t.Fatalf("hl: %s", got)
+	}
+
+	opts.Lang = language.BritishEnglish
+	if got := opts.locale(); got != "en-GB" {
+		t.Fatalf("locale with region: %s", got)
+	}
+}
+
+func TestValidateMarket(t *testing.T) {
+	valid := []Options{
+		{},
+		{Country: "de", GoogleHost: "www.google.de"},
+		{GoogleHost: "www.google.co.in"},
+	}
+	for _, opts := range valid {
+		if err := opts.ValidateMarket(); err != nil {
+			t.Errorf("%+v: unexpected error %v", opts, err)
+		}
+	}
+
+	offset := 24 * 60
+	invalid := []Options{
+		{Country: "DEU"},
+		{GoogleHost: "evil.example.com"},
+		{GoogleHost: "www.google.com:8080"},
+		{GoogleHost: "www.google.com/path"},
+		{TimezoneOffset: &offset},
+	}
+	for _, opts := range invalid {
+		if err := opts.ValidateMarket(); err == nil {
+			t.Errorf("%+v: expected an error", opts)
+		}
+	}
+}
+
+func TestMarketURL(t *testing.T) {
+	session := &Session{baseURL: DefaultBaseURL}
+	if got := session.marketURL(Options{GoogleHost: "www.google.de"}, "/x"); got != "https://www.google.de/x" {
+		t.Fatalf("market host: %s", got)
+	}
+	if got := session.marketURL(Options{}, "/x"); got != "https://www.google.com/x" {
+		t.Fatalf("default host: %s", got)
+	}
+
+	session = &Session{baseURL: "http://127.0.0.1:8080"}
+	if got := session.marketURL(Options{GoogleHost: "www.google.de"}, "/x"); got != "http://127.0.0.1:8080/x" {
+		t.Fatalf("custom base URL: %s", got)
+	}
+}
+
+func TestSerializeURLMarket(t *testing.T) {
+	session := &Session{Cities: Map[string, string]{}}
+	args := Args{
+		SrcAirports: []string{"WAW"},
+		DstAirports: []string{"ATH"},
+		Options:     OptionsDefault(),
+	}
+	args.Date = timeNow().AddDate(0, 1, 0)
+	args.ReturnDate = args.Date.AddDate(0, 0, 7)
+	args.Country = "de"
+	args.GoogleHost = "www.google.de"
+
+	url, err := session.SerializeURL(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(url, "https://www.google.de/travel/flights/search?") || !strings.HasSuffix(url, "&hl=en&gl=DE") {
+		t.Fatalf("unexpected url: %s", url)
+	}
+}
+
+// hostCookieClient answers the cookie requests with a cookie named after the requested host.
+type hostCookieClient struct {
+	requests []string
+}
+
+func (c *hostCookieClient) Do(req *retryablehttp.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req.URL.String())
+	header := http.Header{}
+	header.Set("Set-Cookie", "NID="+req.URL.Host+"; path=/")
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestMarketCookies(t *testing.T) {
+	client := &hostCookieClient{}
+	session := &Session{client: client, baseURL: DefaultBaseURL, cookies: []string{"NID=www.google.com"}, cookiesPerHost: true, limitAttempts: true}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		cookies, err := session.marketCookies(ctx, Options{GoogleHost: "www.google.de"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cookies) == 0 || cookies[0] != "NID=www.google.de" {
+			t.Fatalf("cookies of www.google.de: %v", cookies)
+		}
+	}
+	if len(client.requests) != 1 || client.requests[0] != "https://www.google.de/" {
+		t.Fatalf("the cookies of a host are requested once: %v", client.requests)
+	}
+
+	for _, opts := range []Options{{}, {GoogleHost: "www.google.com"}} {
+		cookies, err := session.marketCookies(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cookies) != 1 || cookies[0] != "NID=www.google.com" {
+			t.Fatalf("%+v: cookies of the base URL: %v", opts, cookies)
+		}
+	}
+
+	session.cookiesPerHost = false
+	cookies, err := session.marketCookies(ctx, Options{GoogleHost: "www.google.fr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cookies) != 1 || cookies[0] != "NID=www.google.com" || len(client.requests) != 1 {
+		t.Fatalf("given cookies are sent to every host: %v", cookies)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -16,6 +18,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
@@ -60,7 +63,7 @@ func (s *Session) getPriceGraphReqData(ctx context.Context, args PriceGraphArgs)
 }
 
 func (s *Session) doRequestPriceGraph(ctx context.Context, args PriceGraphArgs) (*http.Response, error) {
-	url := "https://www.google.com/_/FlightsFrontendUi/data/travel.frontend.flights.FlightsFrontendService/GetCalendarGraph?f.sid=-8920707734915550076&bl=boq_travel-frontend-ui_20230627.07_p1&hl=en&soc-app=162&soc-platform=1&soc-device=1&_reqid=261464&rt=c"
+	url := s.marketURL(args.Options, "/_/FlightsFrontendUi/data/travel.frontend.flights.FlightsFrontendService/GetCalendarGraph?f.sid=-8920707734915550076&bl=boq_travel-frontend-ui_20230627.07_p1&hl="+args.hl()+"&soc-app=162&soc-platform=1&soc-device=1&_reqid=261464&rt=c")
 
 	reqDate, err := s.getPriceGraphReqData(ctx, args)
 	if err != nil {
@@ -71,21 +74,30 @@ func (s *Session) doRequestPriceGraph(ctx context.Context, args PriceGraphArgs)
 		`f.req=` + reqDate +
 			`&at=AAuQa1oq5qIkgkQ2nG9vQZFTgSME%3A` + strconv.FormatInt(time.Now().Unix(), 10) + `&`)
 
+	cookies, err := s.marketCookies(ctx, args.Options)
+	if err != nil {
+		return nil, err
+	}
+
 	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("accept", `*/*`)
-	req.Header.Set("accept-language", `en-US,en;q=0.9`)
 	req.Header.Set("cache-control", `no-cache`)
 	req.Header.Set("content-type", `application/x-www-form-urlencoded;charset=UTF-8`)
-	req.Header["cookie"] = s.cookies
+	req.Header["cookie"] = cookies
 	req.Header.Set("pragma", `no-cache`)
 	req.Header.Set("user-agent", `Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36`)
-	req.Header.Set("x-goog-ext-259736195-jspb",
-		fmt.Sprintf(`["en-US","US","%s",1,null,[-120],null,[[48764689,47907128,48676280,48710756,48627726,48480739,48593234,48707380]],1,[]]`, args.Currency))
+	for key, value := range args.marketHeaders("48764689,47907128,48676280,48710756,48627726,48480739,48593234,48707380") {
+		req.Header.Set(key, value)
+	}
 
-	return s.client.Do(req)
+	resp, err := s.do(req, ratelimit.KindPriceGraph)
+	if err != nil {
+		return nil, err
+	}
+	return checkResponse(resp)
 }
 
 func priceGraphSchema(startDate, returnDate *string, price *float64) *[]interface{} {
@@ -297,6 +309,12 @@ func (s *Session) GetPriceGraph(ctx context.Context, args PriceGraphArgs) ([]Off
 		return nil, nil, err
 	}
 
+	offers, parseErrors, err := s.getPriceGraph(ctx, args)
+	s.recordOutcome(ctx, err, len(offers) == 0)
+	return offers, parseErrors, err
+}
+
+func (s *Session) getPriceGraph(ctx context.Context, args PriceGraphArgs) ([]Offer, *ParseErrors, error) {
 	offers := []Offer{}
 	parseErrors := &ParseErrors{Samples: make([]string, 0, 5)}
 
@@ -310,11 +328,16 @@ func (s *Session) GetPriceGraph(ctx context.Context, args PriceGraphArgs) ([]Off
 	skipPrefix(body)
 
 	sectionIndex := 0
+	failedSections := 0
 
 	for {
 		readLine(body) // skip line
 		bytesToDecode, err := getInnerBytes(body)
 		if err != nil {
+			nonEmptySections := sectionIndex - parseErrors.EmptySections
+			if len(offers) == 0 && (nonEmptySections > 0 && failedSections == nonEmptySections || sectionIndex == 0 && !errors.Is(err, io.EOF)) {
+				return nil, parseErrors, fmt.Errorf("%w: GetCalendarGraph: %v", ErrSchemaChanged, err)
+			}
 			sortSlice(offers, func(lv, rv Offer) bool {
 				return lv.StartDate.Before(rv.StartDate)
 			})
@@ -328,6 +351,9 @@ func (s *Session) GetPriceGraph(ctx context.Context, args PriceGraphArgs) ([]Off
 			continue
 		}
 		offers_, sectionErrors := getPriceGraphSection(sectionIndex, bytesToDecode)
+		if offers_ == nil {
+			failedSections++
+		}
 		mergeParseErrors(parseErrors, sectionErrors)
 		offers = append(offers, offers_...)
 	}
@@ -39,6 +39,7 @@ func TestGetPriceGraph(t *testing.T) {
 				TripType:  RoundTrip,
 				Lang:      language.English,
 			},
+			nil,
 		})
 	if err != nil {
 		t.Fatal(err)
@@ -88,6 +89,7 @@ func TestGetPriceGraphTravelers(t *testing.T) {
 			TripType:  RoundTrip,
 			Lang:      language.English,
 		},
+		nil,
 	}
 
 	offers, _, err := session.GetPriceGraph(context.Background(), args)
@@ -158,6 +160,7 @@ func TestGetPriceGraphMock(t *testing.T) {
 				TripType:  RoundTrip,
 				Lang:      language.English,
 			},
+			nil,
 		},
 	)
 	if err != nil {
@@ -210,6 +213,7 @@ func TestPriceGraphReqData(t *testing.T) {
 				TripType:  RoundTrip,
 				Lang:      language.English,
 			},
+			nil,
 		})
 	if err != nil {
 		t.Fatal(err)
@@ -242,6 +246,7 @@ func TestPriceGraphReqData(t *testing.T) {
 				TripType:  OneWay,
 				Lang:      language.English,
 			},
+			nil,
 		})
 	if err != nil {
 		t.Fatal(err)
@@ -256,3 +261,48 @@ func TestPriceGraphReqData(t *testing.T) {
 		t.Fatalf("wrong unescaped query, expected: %s received: %s", expectedReqData2, reqData2)
 	}
 }
+
+func TestPriceGraphReqDataOpenJaw(t *testing.T) {
+	expectedReqData := `[null,"[null,[null,null,3,null,[],1,[1,0,0,0],null,null,null,null,null,null,[[[[[\"SFO\",0]]],[[[\"LHR\",0]]],null,0,[],[],\"2024-01-01\",null,[],[],[],null,null,[],3],[[[[\"CDG\",0]]],[[[\"SFO\",0]]],null,0,[],[],\"2024-01-08\",null,[],[],[],null,null,[],3]],null,null,null,1,null,null,null,null,null,[]],[\"2024-01-01\",\"2024-01-31\"],null,[7,7]]"]`
+
+	args := PriceGraphArgs{
+		RangeStartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		RangeEndDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		TripLength:     7,
+		SrcAirports:    []string{"SFO"},
+		DstAirports:    []string{"LHR"},
+		Options:        OptionsDefault(),
+		ReturnLeg:      &PriceGraphLeg{SrcAirports: []string{"CDG"}},
+	}
+
+	session := &Session{}
+	_reqData, err := session.getPriceGraphReqData(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqData, err := url.QueryUnescape(_reqData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reqData != expectedReqData {
+		t.Fatalf("wrong unescaped query, expected: %s received: %s", expectedReqData, reqData)
+	}
+
+	offerArgs := args.OfferArgs(Offer{
+		StartDate:  time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		ReturnDate: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+	})
+	if offerArgs.TripType != MultiCity || len(offerArgs.Segments) != 2 {
+		t.Fatalf("expected a two-segment multi-city trip, received: %v %v", offerArgs.TripType, offerArgs.Segments)
+	}
+	if diff := deep.Equal(offerArgs.Segments[1], Segment{
+		Date:        time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		SrcAirports: []string{"CDG"},
+		DstAirports: []string{"SFO"},
+	}); diff != nil {
+		t.Fatal(diff)
+	}
+	if args.TripType != RoundTrip {
+		t.Fatalf("OfferArgs modified the trip type of the price graph arguments")
+	}
+}
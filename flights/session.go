@@ -6,11 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/browserutils/kooky"
+	"github.com/gilby125/google-flights-api/pkg/blockdetect"
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
@@ -34,17 +37,89 @@ func (m *Map[K, V]) Load(key K) (value V, ok bool) {
 // Store sets the value for a key.
 func (m *Map[K, V]) Store(key K, value V) { m.m.Store(key, value) }
 
-type httpClient interface {
+// HTTPClient sends the requests of a [Session]. It is implemented by [retryablehttp.Client].
+type HTTPClient interface {
 	Do(req *retryablehttp.Request) (*http.Response, error)
 }
 
+// DefaultBaseURL is the address of Google used by [New].
+const DefaultBaseURL = "https://www.google.com"
+
+const (
+	recordDirEnv = "FLIGHTS_RECORD_DIR"
+	replayDirEnv = "FLIGHTS_REPLAY_DIR"
+)
+
+// SessionOptions configures a [Session] created by [NewWithOptions]. The zero value gives the same
+// session as [New].
+type SessionOptions struct {
+	// Client sends the requests. If nil, a retryable HTTP client using Transport is created.
+	Client HTTPClient
+	// Transport is used by the default client. If nil, [http.DefaultTransport] is used.
+	Transport http.RoundTripper
+	// Cookies returns the cookies sent with every request. If nil, the cookies are taken from
+	// the response of BaseURL and the GOOGLE_ABUSE_EXEMPTION cookie of the local browser.
+	Cookies func(ctx context.Context) ([]string, error)
+	// BaseURL is the address of Google. If empty, [DefaultBaseURL] is used.
+	BaseURL string
+	// RecordDir enables the record mode. Every response is saved in RecordDir
+	// (see [NewRecordTransport]).
+	RecordDir string
+	// ReplayDir enables the replay mode. Responses are read from ReplayDir instead of being
+	// requested from Google (see [NewReplayTransport]). No cookies are requested in this mode
+	// unless Cookies is set.
+	ReplayDir string
+	// RateLimiter throttles the requests of the session. If nil, the process-wide limiter
+	// installed by [ratelimit.SetDefault] is used. The default client takes a token for every
+	// attempt, retries included; a custom Client is charged once per request.
+	RateLimiter ratelimit.Limiter
+}
+
 // Session is the main type that contains all the most important functions to operate the Google Flights API.
 // It is safe for concurrent use by multiple goroutines. (Concurrent example: [github.com/gilby125/google-flights-api/examples/example3])
 type Session struct {
 	Cities Map[string, string] // Map which acts like a cache: city name -> abbravated city names
 
-	client  httpClient
+	client  HTTPClient
 	cookies []string
+	baseURL string
+	limiter ratelimit.Limiter
+	// limitAttempts is set when the transport of client takes the rate limiter tokens itself.
+	limitAttempts bool
+	// hostCookies holds the cookies of the Google hosts of other markets (see [Options.GoogleHost]),
+	// requested on first use when cookiesPerHost is set.
+	hostCookies    map[string][]string
+	hostCookiesMu  sync.Mutex
+	cookiesPerHost bool
+
+	health       sessionHealth // outcomes of the latest requests, used by [SessionPool]
+	healthWindow int
+}
+
+// do sends req, a request of the given kind, within the rate limit.
+func (s *Session) do(req *retryablehttp.Request, kind string) (*http.Response, error) {
+	if s.limitAttempts {
+		return s.client.Do(req.WithContext(withRateLimitKind(req.Context(), kind)))
+	}
+	if err := wait(req.Context(), s.limiter, kind); err != nil {
+		return nil, err
+	}
+	return s.client.Do(req)
+}
+
+func wait(ctx context.Context, limiter ratelimit.Limiter, kind string) error {
+	if limiter != nil {
+		return limiter.Wait(ctx, kind)
+	}
+	return ratelimit.Wait(ctx, kind)
+}
+
+// url returns the address of the Google endpoint under path.
+func (s *Session) url(path string) string {
+	if s.baseURL == "" {
+		return DefaultBaseURL + path
+	}
+	return s.baseURL + path
 }
 
 func customRetryPolicy() func(ctx context.Context, resp *http.Response, err error) (bool, error) {
@@ -59,6 +134,12 @@ func customRetryPolicy() func(ctx context.Context, resp *http.Response, err erro
 			return true, fmt.Errorf("response is nil")
 		}
 
+		// Retrying an interstitial only digs the hole deeper. The consent page is left to the
+		// callers, because the session cookies can be taken from it.
+		if err := blockdetect.Classify(resp); err != nil && !errors.Is(err, blockdetect.ErrConsentRequired) {
+			return false, err
+		}
+
 		if resp.StatusCode != http.StatusOK {
 			return true, fmt.Errorf("wrong status code: %d", resp.StatusCode)
 		}
@@ -77,17 +158,29 @@ func getCookies(res *http.Response) ([]string, error) {
 	return nil, fmt.Errorf("could not find the 'Set-Cookie' header in the initialization response")
 }
 
-func New() (*Session, error) {
+func newRetryableClient(transport http.RoundTripper) *retryablehttp.Client {
 	client := retryablehttp.NewClient()
 	client.RetryMax = 5
 	client.Logger = nil
 	client.CheckRetry = customRetryPolicy()
 	client.RetryWaitMin = time.Second
 	client.HTTPClient.Timeout = 90 * time.Second
+	if transport != nil {
+		client.HTTPClient.Transport = transport
+	}
+	return client
+}
 
-	res, err := client.Get("https://www.google.com/")
+// initCookies requests the session cookies from baseURL, e.g. https://www.google.com.
+func (s *Session) initCookies(ctx context.Context, baseURL string) ([]string, error) {
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/", nil)
 	if err != nil {
-		return nil, fmt.Errorf("new session: err sending request to www.google.com: %w", err)
+		return nil, fmt.Errorf("new session: err creating request to %s: %w", baseURL, err)
+	}
+
+	res, err := s.do(req, ratelimit.KindSession)
+	if err != nil {
+		return nil, fmt.Errorf("new session: err sending request to %s: %w", strings.TrimPrefix(baseURL, "https://"), err)
 	}
 	defer res.Body.Close()
 
@@ -96,16 +189,79 @@ func New() (*Session, error) {
 		return nil, fmt.Errorf("new session: err getting cookies: %w", err)
 	}
 
-	GOOGLE_ABUSE_EXEMPTION := kooky.ReadCookies(kooky.Valid, kooky.DomainHasSuffix(`google.com`), kooky.Name(`GOOGLE_ABUSE_EXEMPTION`))
+	domain := strings.TrimPrefix(strings.TrimPrefix(baseURL, "https://"), "www.")
+	GOOGLE_ABUSE_EXEMPTION := kooky.ReadCookies(kooky.Valid, kooky.DomainHasSuffix(domain), kooky.Name(`GOOGLE_ABUSE_EXEMPTION`))
 
 	if len(GOOGLE_ABUSE_EXEMPTION) == 1 {
 		exemption := GOOGLE_ABUSE_EXEMPTION[0]
 		cookies = append(cookies, fmt.Sprintf("%s=%s", exemption.Name, exemption.Value))
 	}
+	return cookies, nil
+}
+
+// New creates a [Session] which talks to Google Flights. It requests www.google.com to get the
+// session cookies.
+//
+// The record and replay modes of [SessionOptions] can be enabled with the FLIGHTS_RECORD_DIR and
+// FLIGHTS_REPLAY_DIR environment variables, so that the services using New can run against
+// recorded Google payloads.
+func New() (*Session, error) {
+	return NewWithOptions(context.Background(), SessionOptions{
+		RecordDir: os.Getenv(recordDirEnv),
+		ReplayDir: os.Getenv(replayDirEnv),
+	})
+}
+
+// NewWithOptions creates a [Session] configured by opts.
+//
+// NewWithOptions returns an error if both the record and the replay mode are enabled or if the
+// cookies can't be obtained.
+func NewWithOptions(ctx context.Context, opts SessionOptions) (*Session, error) {
+	if opts.RecordDir != "" && opts.ReplayDir != "" {
+		return nil, fmt.Errorf("new session: record and replay modes can't be enabled at the same time")
+	}
+
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
 
-	return &Session{
+	s := &Session{
 		Cities:  Map[string, string]{},
-		client:  client,
-		cookies: cookies,
-	}, nil
+		client:  opts.Client,
+		baseURL: baseURL,
+		limiter: opts.RateLimiter,
+	}
+	if s.client == nil {
+		transport := opts.Transport
+		switch {
+		case opts.ReplayDir != "":
+			transport = NewReplayTransport(opts.ReplayDir)
+		case opts.RecordDir != "":
+			transport = NewRecordTransport(opts.RecordDir, transport)
+		}
+		retryableClient := newRetryableClient(newLimitTransport(opts.RateLimiter, transport))
+		if opts.ReplayDir != "" {
+			// A missing fixture won't appear after a retry.
+			retryableClient.RetryMax = 0
+		}
+		s.client = retryableClient
+		s.limitAttempts = true
+	}
+
+	var err error
+	switch {
+	case opts.Cookies != nil:
+		s.cookies, err = opts.Cookies(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("new session: err getting cookies: %w", err)
+		}
+	case opts.ReplayDir == "":
+		s.cookies, err = s.initCookies(ctx, s.baseURL)
+		if err != nil {
+			return nil, err
+		}
+		s.cookiesPerHost = true
+	}
+	return s, nil
 }
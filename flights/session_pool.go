@@ -0,0 +1,277 @@
+package flights
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults of [SessionPoolOptions].
+const (
+	DefaultSessionPoolSize    = 3
+	DefaultSessionRetryDelay  = 30 * time.Second
+	defaultHealthWindow       = 20
+	defaultHealthMinSamples   = 5
+	defaultMaxFailureRate     = 0.5
+	maxSessionRetryDelayShift = 4 // the retry delay grows up to 16 times RetryDelay
+)
+
+// sessionHealth keeps the outcomes of the latest requests of a [Session]. A request fails if it
+// returns an error or an empty response.
+type sessionHealth struct {
+	mu       sync.Mutex
+	outcomes []bool // ring buffer, true means failure
+	next     int
+	total    int
+	failures int
+}
+
+func (h *sessionHealth) record(failed bool, window int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.outcomes) != window {
+		h.outcomes = make([]bool, window)
+		h.next, h.total, h.failures = 0, 0, 0
+	}
+	if h.total == window && h.outcomes[h.next] {
+		h.failures--
+	}
+	h.outcomes[h.next] = failed
+	if failed {
+		h.failures++
+	}
+	h.next = (h.next + 1) % window
+	if h.total < window {
+		h.total++
+	}
+}
+
+func (h *sessionHealth) snapshot() (samples, failures int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.total, h.failures
+}
+
+// recordOutcome scores the session after a request. Empty responses count as failures, because
+// Google answers a flagged session with empty results rather than an error. Requests cancelled by
+// the caller aren't scored.
+func (s *Session) recordOutcome(ctx context.Context, err error, empty bool) {
+	if ctx.Err() != nil {
+		return
+	}
+	window := s.healthWindow
+	if window <= 0 {
+		window = defaultHealthWindow
+	}
+	s.health.record(err != nil || empty, window)
+}
+
+// SessionPoolOptions configures a [SessionPool]. The zero value gives a pool of
+// [DefaultSessionPoolSize] sessions created by [New].
+type SessionPoolOptions struct {
+	// Size is the number of sessions in the pool.
+	Size int
+	// NewSession creates the sessions of the pool. If nil, [NewWithOptions] with the record and
+	// replay directories of the environment is used (like [New]).
+	NewSession func(ctx context.Context) (*Session, error)
+	// Window is the number of the latest requests of a session which are scored.
+	Window int
+	// MinSamples is the number of scored requests needed before a session can be retired.
+	MinSamples int
+	// MaxFailureRate is the rate of failed or empty responses above which a session is retired and
+	// re-created.
+	MaxFailureRate float64
+	// RetryDelay is how long a slot waits before it tries again to create a session after a
+	// failure. It doubles with every consecutive failure of the slot.
+	RetryDelay time.Duration
+}
+
+// SessionStats describes the health of a session of a [SessionPool].
+type SessionStats struct {
+	Slot        int       // position of the session in the pool
+	Samples     int       // number of scored requests
+	Failures    int       // number of failed or empty responses among Samples
+	FailureRate float64   // Failures / Samples
+	Retired     int       // number of sessions retired in this slot
+	CreatedAt   time.Time // creation time of the current session
+}
+
+type poolSlot struct {
+	mu        sync.Mutex // guards the fields below; never held while a session is created
+	session   *Session
+	retired   int
+	createdAt time.Time
+	failures  int       // consecutive failures to create a session
+	retryAt   time.Time // no session is created before this time after a failure
+	lastErr   error
+
+	create sync.Mutex // held while a session of the slot is created
+}
+
+// SessionPool manages a fixed number of sessions and hands them out round-robin. Every session is
+// scored by the rate of failed or empty responses of its latest requests; a session above
+// MaxFailureRate is retired and re-created on its next turn, so a single flagged session doesn't
+// keep failing forever. Sessions are created lazily.
+//
+// SessionPool is safe for concurrent use by multiple goroutines.
+type SessionPool struct {
+	opts  SessionPoolOptions
+	slots []poolSlot
+	next  atomic.Uint64
+}
+
+// NewSessionPool creates a [SessionPool]. No session is created until [SessionPool.Get] is called.
+func NewSessionPool(opts SessionPoolOptions) *SessionPool {
+	if opts.Size <= 0 {
+		opts.Size = DefaultSessionPoolSize
+	}
+	if opts.NewSession == nil {
+		opts.NewSession = func(ctx context.Context) (*Session, error) {
+			return NewWithOptions(ctx, SessionOptions{
+				RecordDir: os.Getenv(recordDirEnv),
+				ReplayDir: os.Getenv(replayDirEnv),
+			})
+		}
+	}
+	if opts.Window <= 0 {
+		opts.Window = defaultHealthWindow
+	}
+	if opts.MinSamples <= 0 {
+		opts.MinSamples = defaultHealthMinSamples
+	}
+	if opts.MinSamples > opts.Window {
+		opts.MinSamples = opts.Window
+	}
+	if opts.MaxFailureRate <= 0 {
+		opts.MaxFailureRate = defaultMaxFailureRate
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = DefaultSessionRetryDelay
+	}
+	return &SessionPool{opts: opts, slots: make([]poolSlot, opts.Size)}
+}
+
+func (p *SessionPool) healthy(s *Session) bool {
+	samples, failures := s.health.snapshot()
+	if samples < p.opts.MinSamples {
+		return true
+	}
+	return float64(failures)/float64(samples) <= p.opts.MaxFailureRate
+}
+
+// Get returns the next session of the pool. An unhealthy session is replaced by a new one before
+// it is returned. If the replacement can't be created, the old session is returned, so the pool
+// keeps working while Google is unreachable.
+//
+// Sessions are created outside of the slot lock: while one caller replaces an unhealthy session,
+// the others keep getting the old one instead of waiting. After a failed creation the slot doesn't
+// try again until [SessionPoolOptions.RetryDelay] has passed, doubling with every further failure.
+//
+// Get returns an error only if the slot has no session yet and creating one fails or failed
+// within the retry delay.
+func (p *SessionPool) Get(ctx context.Context) (*Session, error) {
+	slot := &p.slots[(p.next.Add(1)-1)%uint64(len(p.slots))]
+
+	slot.mu.Lock()
+	current := slot.session
+	if current != nil && (p.healthy(current) || time.Now().Before(slot.retryAt)) {
+		slot.mu.Unlock()
+		return current, nil
+	}
+	slot.mu.Unlock()
+
+	if current == nil {
+		slot.create.Lock()
+	} else if !slot.create.TryLock() {
+		return current, nil // another caller is replacing the session
+	}
+	defer slot.create.Unlock()
+
+	// The slot may have changed while waiting for the creation lock.
+	slot.mu.Lock()
+	if slot.session != current {
+		session := slot.session
+		slot.mu.Unlock()
+		return session, nil
+	}
+	if time.Now().Before(slot.retryAt) {
+		err := slot.lastErr
+		slot.mu.Unlock()
+		if current != nil {
+			return current, nil
+		}
+		return nil, fmt.Errorf("session pool: %w", err)
+	}
+	slot.mu.Unlock()
+
+	session, err := p.opts.NewSession(ctx)
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+
+	if err != nil {
+		if ctx.Err() == nil {
+			slot.retryAt = time.Now().Add(p.opts.RetryDelay << min(slot.failures, maxSessionRetryDelayShift))
+			slot.failures++
+			slot.lastErr = err
+		}
+		if slot.session != nil {
+			return slot.session, nil
+		}
+		return nil, fmt.Errorf("session pool: %w", err)
+	}
+	session.healthWindow = p.opts.Window
+
+	if slot.session != nil {
+		slot.retired++
+	}
+	slot.session = session
+	slot.createdAt = time.Now()
+	slot.failures = 0
+	slot.retryAt = time.Time{}
+	slot.lastErr = nil
+	return session, nil
+}
+
+// Stats returns the health of every created session of the pool.
+func (p *SessionPool) Stats() []SessionStats {
+	stats := make([]SessionStats, 0, len(p.slots))
+	for i := range p.slots {
+		slot := &p.slots[i]
+		slot.mu.Lock()
+		if slot.session != nil {
+			samples, failures := slot.session.health.snapshot()
+			stat := SessionStats{
+				Slot:      i,
+				Samples:   samples,
+				Failures:  failures,
+				Retired:   slot.retired,
+				CreatedAt: slot.createdAt,
+			}
+			if samples > 0 {
+				stat.FailureRate = float64(failures) / float64(samples)
+			}
+			stats = append(stats, stat)
+		}
+		slot.mu.Unlock()
+	}
+	return stats
+}
+
+// Reset drops all sessions of the pool and clears the retry delays. The sessions are re-created by
+// the next calls of [SessionPool.Get].
+func (p *SessionPool) Reset() {
+	for i := range p.slots {
+		slot := &p.slots[i]
+		slot.mu.Lock()
+		slot.session = nil
+		slot.failures = 0
+		slot.retryAt = time.Time{}
+		slot.lastErr = nil
+		slot.mu.Unlock()
+	}
+}
@@ -0,0 +1,177 @@
+package flights
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestSessionPool(size int, fail *bool) (*SessionPool, *int) {
+	created := 0
+	pool := NewSessionPool(SessionPoolOptions{
+		Size: size,
+		NewSession: func(ctx context.Context) (*Session, error) {
+			if fail != nil && *fail {
+				return nil, fmt.Errorf("google unreachable")
+			}
+			created++
+			return &Session{}, nil
+		},
+		Window:         10,
+		MinSamples:     4,
+		MaxFailureRate: 0.5,
+	})
+	return pool, &created
+}
+
+func TestSessionPoolRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	pool, created := newTestSessionPool(2, nil)
+
+	s1, _ := pool.Get(ctx)
+	s2, _ := pool.Get(ctx)
+	s3, _ := pool.Get(ctx)
+
+	if s1 == s2 {
+		t.Fatal("consecutive sessions should differ")
+	}
+	if s1 != s3 {
+		t.Fatal("sessions should be handed out round-robin")
+	}
+	if *created != 2 {
+		t.Fatalf("expected 2 sessions to be created, created: %d", *created)
+	}
+}
+
+func TestSessionPoolRetiresUnhealthySession(t *testing.T) {
+	ctx := context.Background()
+	pool, created := newTestSessionPool(1, nil)
+
+	session, _ := pool.Get(ctx)
+	for i := 0; i < 3; i++ {
+		session.recordOutcome(ctx, nil, true)
+	}
+	if s, _ := pool.Get(ctx); s != session {
+		t.Fatal("session shouldn't be retired before MinSamples requests")
+	}
+
+	session.recordOutcome(ctx, fmt.Errorf("unusual traffic"), false)
+	replaced, _ := pool.Get(ctx)
+	if replaced == session {
+		t.Fatal("unhealthy session should be replaced")
+	}
+	if *created != 2 {
+		t.Fatalf("expected 2 sessions to be created, created: %d", *created)
+	}
+
+	stats := pool.Stats()
+	if len(stats) != 1 || stats[0].Retired != 1 || stats[0].Samples != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSessionPoolKeepsSessionWhenReplacementFails(t *testing.T) {
+	ctx := context.Background()
+	fail := false
+	pool, _ := newTestSessionPool(1, &fail)
+
+	session, err := pool.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		session.recordOutcome(ctx, nil, true)
+	}
+
+	fail = true
+	if s, err := pool.Get(ctx); err != nil || s != session {
+		t.Fatalf("old session should be returned when the replacement fails: %v", err)
+	}
+
+	pool.Reset()
+	if _, err := pool.Get(ctx); err == nil {
+		t.Fatal("expected an error when no session can be created")
+	}
+}
+
+func TestSessionPoolWaitsAfterFailedCreation(t *testing.T) {
+	ctx := context.Background()
+	fail := true
+	pool, created := newTestSessionPool(1, &fail)
+
+	attempts := 0
+	newSession := pool.opts.NewSession
+	pool.opts.NewSession = func(ctx context.Context) (*Session, error) {
+		attempts++
+		return newSession(ctx)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := pool.Get(ctx); err == nil {
+			t.Fatal("expected an error when no session can be created")
+		}
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt within the retry delay, attempts: %d", attempts)
+	}
+
+	fail = false
+	pool.slots[0].retryAt = time.Now() // the retry delay has passed
+	if _, err := pool.Get(ctx); err != nil || *created != 1 {
+		t.Fatalf("expected a session after the retry delay: %v", err)
+	}
+}
+
+func TestSessionPoolDoesntBlockOnReplacement(t *testing.T) {
+	ctx := context.Background()
+	pool, _ := newTestSessionPool(1, nil)
+
+	session, _ := pool.Get(ctx)
+	for i := 0; i < 4; i++ {
+		session.recordOutcome(ctx, nil, true)
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	pool.opts.NewSession = func(ctx context.Context) (*Session, error) {
+		close(started)
+		<-release
+		return &Session{}, nil
+	}
+	replaced := make(chan *Session)
+	go func() {
+		s, _ := pool.Get(ctx)
+		replaced <- s
+	}()
+	<-started
+
+	if s, err := pool.Get(ctx); err != nil || s != session {
+		t.Fatalf("old session should be returned while it is replaced: %v", err)
+	}
+	close(release)
+	if s := <-replaced; s == session {
+		t.Fatal("unhealthy session should be replaced")
+	}
+}
+
+func TestSessionHealthWindow(t *testing.T) {
+	ctx := context.Background()
+	session := &Session{healthWindow: 4}
+
+	for i := 0; i < 4; i++ {
+		session.recordOutcome(ctx, fmt.Errorf("failed"), false)
+	}
+	for i := 0; i < 3; i++ {
+		session.recordOutcome(ctx, nil, false)
+	}
+	if samples, failures := session.health.snapshot(); samples != 4 || failures != 1 {
+		t.Fatalf("expected 1 failure in the last 4 requests, got %d/%d", failures, samples)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	session.recordOutcome(cancelled, fmt.Errorf("context canceled"), false)
+	if _, failures := session.health.snapshot(); failures != 1 {
+		t.Fatal("cancelled requests shouldn't be scored")
+	}
+}
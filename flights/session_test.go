@@ -2,18 +2,27 @@ package flights
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
+	"github.com/go-test/deep"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
 type httpClientMock struct {
 	Responses []func() (*http.Response, error)
 	T         *testing.T
+	Requests  []*retryablehttp.Request
 }
 
 func newHttpClientMock(t *testing.T, respPaths ...string) (*httpClientMock, error) {
@@ -38,7 +47,7 @@ func newHttpClientMock(t *testing.T, respPaths ...string) (*httpClientMock, erro
 		})
 	}
 
-	return &httpClientMock{responses, t}, nil
+	return &httpClientMock{Responses: responses, T: t}, nil
 }
 
 func (c *httpClientMock) Do(req *retryablehttp.Request) (retres *http.Response, reterr error) {
@@ -46,7 +55,164 @@ func (c *httpClientMock) Do(req *retryablehttp.Request) (retres *http.Response,
 		c.T.Fatalf("HttpClientMock: lack of responses")
 	}
 
+	c.Requests = append(c.Requests, req)
+
 	var r func() (*http.Response, error)
 	r, c.Responses = c.Responses[0], c.Responses[1:]
 	return r()
 }
+
+func testOffersArgs() Args {
+	date := time.Now().AddDate(0, 1, 0)
+	return Args{
+		Date:        date,
+		ReturnDate:  date.AddDate(0, 0, 7),
+		SrcAirports: []string{"WAW"},
+		DstAirports: []string{"ATH"},
+		Options:     OptionsDefault(),
+	}
+}
+
+func TestNewWithOptionsRecordReplay(t *testing.T) {
+	flightResp, err := os.ReadFile("testdata/flight.resp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case r.URL.Path == "/":
+			http.SetCookie(w, &http.Cookie{Name: "NID", Value: "test"})
+		case strings.HasSuffix(r.URL.Path, "/GetShoppingResults"):
+			w.Write(flightResp)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	args := testOffersArgs()
+
+	recordSession, err := NewWithOptions(context.Background(), SessionOptions{
+		BaseURL:   server.URL,
+		RecordDir: dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recordSession.cookies) != 1 || recordSession.cookies[0] != "NID=test" {
+		t.Fatalf("wrong cookies: %v", recordSession.cookies)
+	}
+
+	recorded, _, err := recordSession.GetOffers(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded) != 21 {
+		t.Fatalf("wrong number of recorded offers: %d", len(recorded))
+	}
+
+	fixtures, err := filepath.Glob(filepath.Join(dir, "GetShoppingResults-*.http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != 1 {
+		t.Fatalf("wrong number of recorded fixtures: %v", fixtures)
+	}
+
+	server.Close()
+	requestsBeforeReplay := requests.Load()
+
+	replaySession, err := NewWithOptions(context.Background(), SessionOptions{ReplayDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, _, err := replaySession.GetOffers(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(recorded, replayed); diff != nil {
+		t.Fatalf("replayed offers differ: %v", diff)
+	}
+	if sent := requests.Load() - requestsBeforeReplay; sent != 0 {
+		t.Fatalf("replay mode sent %d requests", sent)
+	}
+
+	// Different dates give a different fingerprint, and the directory has no endpoint fixture.
+	args.Date = args.Date.AddDate(0, 0, 1)
+	if _, _, err := replaySession.GetOffers(context.Background(), args); err == nil {
+		t.Fatal("expected an error for a request without a fixture")
+	}
+}
+
+func TestReplayEndpointFixtures(t *testing.T) {
+	session, err := NewWithOptions(context.Background(), SessionOptions{ReplayDir: "testdata/replay"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offers, priceRange, err := session.GetOffers(context.Background(), testOffersArgs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offers) != 21 {
+		t.Fatalf("wrong number of replayed offers: %d", len(offers))
+	}
+	if priceRange == nil {
+		t.Fatal("missing price range")
+	}
+}
+
+func TestNewWithOptionsRecordAndReplay(t *testing.T) {
+	if _, err := NewWithOptions(context.Background(), SessionOptions{RecordDir: "a", ReplayDir: "b"}); err == nil {
+		t.Fatal("expected an error when both modes are enabled")
+	}
+}
+
+type limiterMock struct {
+	kinds []string
+}
+
+func (l *limiterMock) Wait(ctx context.Context, kind string) error {
+	l.kinds = append(l.kinds, kind)
+	return nil
+}
+
+func TestSessionRateLimiter(t *testing.T) {
+	limiter := &limiterMock{}
+	session, err := NewWithOptions(context.Background(), SessionOptions{ReplayDir: "testdata/replay", RateLimiter: limiter})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := session.GetOffers(context.Background(), testOffersArgs()); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal([]string{ratelimit.KindOffers}, limiter.kinds); diff != nil {
+		t.Fatalf("requests didn't go through the limiter: %v", diff)
+	}
+}
+
+func TestSessionRateLimiterChargesRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "NID", Value: "1"})
+	}))
+	defer server.Close()
+
+	limiter := &limiterMock{}
+	if _, err := NewWithOptions(context.Background(), SessionOptions{BaseURL: server.URL, RateLimiter: limiter}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal([]string{ratelimit.KindSession, ratelimit.KindSession}, limiter.kinds); diff != nil {
+		t.Fatalf("the retry didn't take a token: %v", diff)
+	}
+}
@@ -0,0 +1,161 @@
+package flights
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
+)
+
+// Fixtures of the record and replay modes are named after the requested endpoint and a fingerprint
+// of the request, e.g. "GetShoppingResults-1a2b3c4d5e6f7a8b.http". The "at" form value contains a
+// timestamp, so it isn't part of the fingerprint.
+const fixtureExt = ".http"
+
+func fixtureEndpoint(req *http.Request) string {
+	endpoint := path.Base(req.URL.Path)
+	if endpoint == "/" || endpoint == "." {
+		return "root"
+	}
+	return endpoint
+}
+
+func fixtureName(req *http.Request, body []byte) string {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		form = url.Values{"raw": {string(body)}}
+	}
+	form.Del("at")
+
+	key := req.Method + " " + req.URL.Path + "?" + req.URL.RawQuery + "\n" + form.Encode()
+	return fixtureEndpoint(req) + "-" + fingerprintBytes([]byte(key)) + fixtureExt
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+type recordTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// NewRecordTransport returns an [http.RoundTripper] which sends the requests with next (or
+// [http.DefaultTransport] if next is nil) and saves every successful response in dir, so it can be
+// served later by [NewReplayTransport]. The usual place for the fixtures is flights/testdata.
+func NewRecordTransport(dir string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recordTransport{dir: dir, next: next}
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("record: could not read request body: %w", err)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("record: could not dump response: %w", err)
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("record: could not create %s: %w", t.dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, fixtureName(req, body)), dump, 0o644); err != nil {
+		return nil, fmt.Errorf("record: could not save response: %w", err)
+	}
+	return resp, nil
+}
+
+type replayTransport struct {
+	dir string
+}
+
+// NewReplayTransport returns an [http.RoundTripper] which serves the responses saved by
+// [NewRecordTransport] in dir instead of sending the requests.
+//
+// The fixture of a request is looked up by its fingerprint. If there is no such fixture, a
+// fixture named after the endpoint only (e.g. "GetShoppingResults.http") is served, which allows
+// to replay requests whose dates depend on the current day.
+func NewReplayTransport(dir string) http.RoundTripper {
+	return &replayTransport{dir: dir}
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("replay: could not read request body: %w", err)
+	}
+
+	name := fixtureName(req, body)
+	dump, err := os.ReadFile(filepath.Join(t.dir, name))
+	if os.IsNotExist(err) {
+		dump, err = os.ReadFile(filepath.Join(t.dir, fixtureEndpoint(req)+fixtureExt))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("replay: no fixture %s for %s %s: %w", name, req.Method, req.URL.Redacted(), err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(dump)), req)
+	if err != nil {
+		return nil, fmt.Errorf("replay: could not parse fixture %s: %w", name, err)
+	}
+	return resp, nil
+}
+
+type rateLimitKindKey struct{}
+
+// withRateLimitKind returns a copy of ctx which tells [limitTransport] the kind of the request.
+func withRateLimitKind(ctx context.Context, kind string) context.Context {
+	return context.WithValue(ctx, rateLimitKindKey{}, kind)
+}
+
+// limitTransport takes a token of the rate limiter before every attempt of a request, so that the
+// retries of the retryable client are charged to the budget like the first attempt.
+type limitTransport struct {
+	limiter ratelimit.Limiter // if nil, the process-wide limiter is used
+	next    http.RoundTripper
+}
+
+func newLimitTransport(limiter ratelimit.Limiter, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &limitTransport{limiter: limiter, next: next}
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if kind, ok := req.Context().Value(rateLimitKindKey{}).(string); ok {
+		if err := wait(req.Context(), t.limiter, kind); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
@@ -25,6 +25,17 @@ type Flight struct {
 	Unknown        []interface{} // it contains all unknown data which are parsed from the Google Flights API
 	AirlineName    string        // airline name
 	Legroom        string        // legroom in the airplane seats
+
+	// Attributes decoded from Unknown. They are best-effort and stay empty if Google changes
+	// the position of the data.
+	OperatingCarrier    string    // airline operating the flight if it differs from AirlineName (codeshares)
+	Codeshares          []string  // flight numbers of the marketing carriers selling the same flight, e.g. "A3 1501"
+	CO2Emissions        int       // estimated carbon emissions in grams (0 if unknown)
+	SeatPitch           int       // seat pitch in inches (0 if unknown)
+	BelowAverageLegroom bool      // legroom is below average for the route
+	Amenities           Amenities // on-board amenities
+	Overnight           bool      // the flight arrives on a later day than it departs
+	OftenDelayed        bool      // delay history: the flight is often delayed by 30+ min
 }
 
 func (f Flight) String() string {
@@ -64,17 +75,24 @@ func (o Offer) String() string {
 
 // FullOffer describes the full offer of a trip. [Session.GetOffers] returns a slice of FullOffers.
 //
-// NOTE: ReturnFlight is not implemented yet
+// ReturnFlight is only filled for round-trip offers returned by [Session.GetReturnOffers] or
+// completed by [Session.FillReturnFlights].
 type FullOffer struct {
 	Offer
 	Flight               []Flight      // contains all flights in the trip
-	ReturnFlight         []Flight      // not implemented yet
+	ReturnFlight         []Flight      // contains all flights of the return trip
 	SrcAirportCode       string        // code of the airport where the trip starts
 	DstAirportCode       string        // destination airport
 	SrcCity              string        // source city
 	DstCity              string        // destination city
 	FlightDuration       time.Duration // duration of whole Flight
-	ReturnFlightDuration time.Duration // not implemented yet
+	ReturnFlightDuration time.Duration // duration of whole ReturnFlight
+	Layovers             []Layover     // connections between the flights of Flight
+	ReturnLayovers       []Layover     // connections between the flights of ReturnFlight
+	Fare                 Fare          // fare brand and included bags, if supplied by Google
+	// SegmentFlights contains the flights of every segment of a multi-city trip, once they are
+	// chosen by [Session.FillMultiCityFlights]; SegmentFlights[0] is Flight.
+	SegmentFlights [][]Flight
 }
 
 func (o FullOffer) String() string {
@@ -83,13 +101,16 @@ func (o FullOffer) String() string {
 	out += fmt.Sprintf("ReturnDate: %s\n", o.ReturnDate)
 	out += fmt.Sprintf("Price: %d\n", int(o.Price))
 	out += fmt.Sprintf("Flight: %s\n", o.Flight)
-	// out += fmt.Sprintf("ReturnFlight: %s\n", o.ReturnFlight)
+	out += fmt.Sprintf("ReturnFlight: %s\n", o.ReturnFlight)
 	out += fmt.Sprintf("SrcAirportCode: %s\n", o.SrcAirportCode)
 	out += fmt.Sprintf("DstAirportCode: %s\n", o.DstAirportCode)
 	out += fmt.Sprintf("SrcCity: %s\n", o.SrcCity)
 	out += fmt.Sprintf("DstCity: %s\n", o.DstCity)
-	out += fmt.Sprintf("FlightDuration: %s}\n", o.FlightDuration)
-	// out += fmt.Sprintf("ReturnFlightDuration: %s}\n", o.ReturnFlightDuration)
+	out += fmt.Sprintf("FlightDuration: %s\n", o.FlightDuration)
+	out += fmt.Sprintf("ReturnFlightDuration: %s\n", o.ReturnFlightDuration)
+	out += fmt.Sprintf("Layovers: %s\n", o.Layovers)
+	out += fmt.Sprintf("ReturnLayovers: %s\n", o.ReturnLayovers)
+	out += fmt.Sprintf("Fare: %s}\n", o.Fare)
 	return out
 }
 
@@ -152,6 +173,16 @@ func validateNumberOfLocations(cities, airports []string) error {
 
 var timeNow = time.Now
 
+func validateLayovers(minLayover, maxLayover time.Duration) error {
+	if minLayover < 0 || maxLayover < 0 {
+		return fmt.Errorf("layover limits can't be negative")
+	}
+	if maxLayover > 0 && minLayover > maxLayover {
+		return fmt.Errorf("minLayover is higher than maxLayover")
+	}
+	return nil
+}
+
 func validateDate(date, returnDate time.Time) error {
 	dateDay := truncateToDay(date)
 	now := truncateToDay(timeNow().In(dateDay.Location()))
@@ -238,6 +269,15 @@ type Options struct {
 	// For airlines, use IATA 2-letter codes (e.g., "UA", "DL", "B6").
 	// For alliances, Google currently accepts strings like "STAR_ALLIANCE", "ONEWORLD", "SKYTEAM".
 	Carriers []string
+	// Country is the point of sale (Google's gl parameter), an ISO 3166-1 alpha-2 code such as
+	// "DE". Prices are the ones shown to users in this country. Empty means [DefaultCountry].
+	Country string
+	// GoogleHost is the Google domain which receives the requests, e.g. "www.google.de". Empty
+	// means the base URL of the session.
+	GoogleHost string
+	// TimezoneOffset is the timezone of the user in minutes, as returned by JavaScript's
+	// Date.getTimezoneOffset (-60 for UTC+1). Nil means [DefaultTimezoneOffset].
+	TimezoneOffset *int
 }
 
 func OptionsDefault() Options {
@@ -257,6 +297,24 @@ type PriceGraphArgs struct {
 	TripLength                                     int       // number of days between start trip date and return date
 	SrcCities, SrcAirports, DstCities, DstAirports []string  // source and destination; cities and airports of the trip
 	Options                                                  // additional options
+	// ReturnLeg makes the trip an open-jaw or a two-segment multi-city trip: the second flight
+	// departs from the ReturnLeg sources TripLength days after the first one. Nil means the return
+	// flight of a round-trip (DstCities/DstAirports -> SrcCities/SrcAirports).
+	ReturnLeg *PriceGraphLeg
+}
+
+// PriceGraphLeg is the second flight of an open-jaw or multi-city price graph. Empty destinations
+// mean the sources of the first flight (fly into A, out of B, back home).
+type PriceGraphLeg struct {
+	SrcCities, SrcAirports, DstCities, DstAirports []string
+}
+
+// returnDestinations returns the destinations of the return leg.
+func (a *PriceGraphArgs) returnDestinations() (cities, airports []string) {
+	if len(a.ReturnLeg.DstCities) == 0 && len(a.ReturnLeg.DstAirports) == 0 {
+		return a.SrcCities, a.SrcAirports
+	}
+	return a.ReturnLeg.DstCities, a.ReturnLeg.DstAirports
 }
 
 // Validates PriceGraphArgs requirements:
@@ -265,10 +323,25 @@ type PriceGraphArgs struct {
 //   - srcAirports and dstAirports have to be in the right IATA format: https://en.wikipedia.org/wiki/IATA_airport_code
 //   - dates have to be in the chronological order: today's date -> RangeStartDate -> RangeEndDate
 //   - the difference between RangeStartDate and RangeEndDate cannot be higher than 161 days
+//   - Country, GoogleHost and TimezoneOffset have to describe a valid market
+//   - ReturnLeg, if set, has at least one source location and valid airport codes, and TripLength
+//     can't be negative
 func (a *PriceGraphArgs) Validate() error {
+	if err := a.ValidateMarket(); err != nil {
+		return err
+	}
 	if err := validateLocations(a.SrcCities, a.SrcAirports, a.DstCities, a.DstAirports); err != nil {
 		return err
 	}
+	if a.ReturnLeg != nil {
+		dstCities, dstAirports := a.returnDestinations()
+		if err := validateLocations(a.ReturnLeg.SrcCities, a.ReturnLeg.SrcAirports, dstCities, dstAirports); err != nil {
+			return fmt.Errorf("return leg: %s", err)
+		}
+		if a.TripLength < 0 {
+			return fmt.Errorf("trip length can't be negative: %d", a.TripLength)
+		}
+	}
 
 	a.RangeStartDate = truncateToDay(a.RangeStartDate)
 	a.RangeEndDate = truncateToDay(a.RangeEndDate)
@@ -285,8 +358,15 @@ func (a *PriceGraphArgs) Validate() error {
 	return nil
 }
 
-// Converts PriceGraphArgs to [Args]. It sets the date range to 30 days.
+// Converts PriceGraphArgs to [Args]. It sets the date range to 30 days. With a ReturnLeg the
+// arguments describe a two-segment [MultiCity] trip.
 func (a *PriceGraphArgs) Convert() Args {
+	if a.ReturnLeg != nil {
+		return a.OfferArgs(Offer{
+			StartDate:  a.RangeStartDate,
+			ReturnDate: a.RangeStartDate.AddDate(0, 0, a.TripLength),
+		})
+	}
 	return Args{
 		Date:        a.RangeStartDate,
 		ReturnDate:  a.RangeStartDate.AddDate(0, 0, a.TripLength),
@@ -298,12 +378,64 @@ func (a *PriceGraphArgs) Convert() Args {
 	}
 }
 
+// OfferArgs returns the arguments of [Session.GetOffers] and [Session.SerializeURL] for the dates
+// of an offer of the price graph.
+func (a *PriceGraphArgs) OfferArgs(offer Offer) Args {
+	if a.ReturnLeg == nil {
+		return Args{
+			Date:        offer.StartDate,
+			ReturnDate:  offer.ReturnDate,
+			SrcCities:   a.SrcCities,
+			SrcAirports: a.SrcAirports,
+			DstCities:   a.DstCities,
+			DstAirports: a.DstAirports,
+			Options:     a.Options,
+		}
+	}
+
+	dstCities, dstAirports := a.returnDestinations()
+	options := a.Options
+	options.TripType = MultiCity
+	return Args{
+		Date:        offer.StartDate,
+		ReturnDate:  offer.ReturnDate,
+		SrcCities:   a.SrcCities,
+		SrcAirports: a.SrcAirports,
+		DstCities:   a.DstCities,
+		DstAirports: a.DstAirports,
+		Options:     options,
+		Segments: []Segment{
+			{
+				Date:        offer.StartDate,
+				SrcCities:   a.SrcCities,
+				SrcAirports: a.SrcAirports,
+				DstCities:   a.DstCities,
+				DstAirports: a.DstAirports,
+			},
+			{
+				Date:        offer.ReturnDate,
+				SrcCities:   a.ReturnLeg.SrcCities,
+				SrcAirports: a.ReturnLeg.SrcAirports,
+				DstCities:   dstCities,
+				DstAirports: dstAirports,
+			},
+		},
+	}
+}
+
 // Arguments used in [Session.GetOffers] and [Session.SerializeURL].
 type Args struct {
 	Date, ReturnDate                               time.Time // start trip date and return date
 	SrcCities, SrcAirports, DstCities, DstAirports []string  // source and destination; cities and airports of the trip
 	Options                                                  // additional options
 	Segments                                       []Segment // For multi-city trips
+	// MinLayover and MaxLayover exclude offers with a connection shorter or longer than the given
+	// duration (0 means no limit). The offers are filtered after they are received from Google.
+	MinLayover, MaxLayover time.Duration
+	// ExcludeBasicEconomy leaves out basic economy fares and RequiredBags leaves out fares which
+	// don't include the given bags. Offers whose bags are unknown are kept with Fare.BagsUnverified set.
+	ExcludeBasicEconomy bool
+	RequiredBags        Bags
 }
 
 type Segment struct {
@@ -316,7 +448,20 @@ type Segment struct {
 //   - at least one destination location (dstCities / dstAirports)
 //   - srcAirports and dstAirports have to be in the right IATA format: https://en.wikipedia.org/wiki/IATA_airport_code
 //   - dates have to be in chronological order: today's date -> Date -> ReturnDate
+//   - MinLayover and MaxLayover can't be negative and MinLayover can't be higher than MaxLayover
+//   - RequiredBags can't be negative
+//   - Country, GoogleHost and TimezoneOffset have to describe a valid market
 func (a *Args) ValidateOffersArgs() error {
+	if err := a.ValidateMarket(); err != nil {
+		return err
+	}
+	if err := validateLayovers(a.MinLayover, a.MaxLayover); err != nil {
+		return err
+	}
+	if a.RequiredBags.CarryOn < 0 || a.RequiredBags.Checked < 0 {
+		return fmt.Errorf("required bags can't be negative")
+	}
+
 	if a.TripType == MultiCity {
 		if len(a.Segments) < 1 {
 			return fmt.Errorf("multi-city trip must have at least 1 segment")
@@ -355,7 +500,11 @@ func (a *Args) ValidateOffersArgs() error {
 //   - at least one source location (srcCities / srcAirports)
 //   - at least one destination location (dstCities / dstAirports)
 //   - srcAirports and dstAirports have to be in the right IATA format: https://en.wikipedia.org/wiki/IATA_airport_code
+//   - Country and GoogleHost have to describe a valid market
 func (a *Args) ValidateURLArgs() error {
+	if err := a.ValidateMarket(); err != nil {
+		return err
+	}
 	if a.TripType == MultiCity {
 		if len(a.Segments) < 1 {
 			return fmt.Errorf("multi-city trip must have at least 1 segment")
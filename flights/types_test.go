@@ -219,6 +219,18 @@ func TestValidatePriceGraphArgs(t *testing.T) {
 		RangeEndDate:   time.Now().AddDate(0, 0, 2),
 	}
 	testValidatePriceGraphArgs(t, args, "rangeStartDate is before today's date")
+
+	args = PriceGraphArgs{
+		SrcCities: []string{"abc"}, SrcAirports: []string{}, DstCities: []string{"abc"}, DstAirports: []string{},
+		ReturnLeg: &PriceGraphLeg{},
+	}
+	testValidatePriceGraphArgs(t, args, "return leg: src locations: number of locations should be at least 1, specified: 0")
+
+	args = PriceGraphArgs{
+		SrcCities: []string{"abc"}, SrcAirports: []string{}, DstCities: []string{"abc"}, DstAirports: []string{},
+		ReturnLeg: &PriceGraphLeg{SrcAirports: []string{"CDG"}, DstAirports: []string{wrongAirportCode}},
+	}
+	testValidatePriceGraphArgs(t, args, "return leg: dst airport 'wrong' is not an airport code")
 }
 
 func TestValidateRangeDate_AllowsToday(t *testing.T) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gilby125/google-flights-api/flights/internal/urlpb"
@@ -128,10 +129,18 @@ func (s *Session) SerializeURL(ctx context.Context, args Args) (string, error) {
 		return "", fmt.Errorf("error during url serialization: %s", err)
 	}
 
-	return "https://www.google.com/travel/flights/search" +
+	host := "www.google.com"
+	if args.GoogleHost != "" {
+		host = strings.ToLower(args.GoogleHost)
+	}
+	serialized := "https://" + host + "/travel/flights/search" +
 		"?tfs=" + base64.RawURLEncoding.EncodeToString(tfs) +
 		"&curr=" + args.Currency.String() +
-		"&hl=" + args.Lang.String(), nil
+		"&hl=" + args.Lang.String()
+	if args.Country != "" {
+		serialized += "&gl=" + strings.ToUpper(args.Country)
+	}
+	return serialized, nil
 }
 
 func serializeTripType(tripType TripType) urlpb.Url_TripType {
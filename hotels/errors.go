@@ -0,0 +1,12 @@
+package hotels
+
+import "github.com/gilby125/google-flights-api/pkg/blockdetect"
+
+// Errors returned when Google serves something else than the hotel results. They are shared with
+// the flights package, so [errors.Is] works regardless of the package which returned them.
+var (
+	ErrRateLimited     = blockdetect.ErrRateLimited     // HTTP 429 or the "unusual traffic" page
+	ErrConsentRequired = blockdetect.ErrConsentRequired // cookie consent interstitial
+	ErrBlocked         = blockdetect.ErrBlocked         // HTTP 403
+	ErrSchemaChanged   = blockdetect.ErrSchemaChanged   // the hotel data couldn't be found or decoded
+)
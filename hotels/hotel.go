@@ -10,6 +10,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/gilby125/google-flights-api/pkg/blockdetect"
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
@@ -45,7 +47,7 @@ func (s *Session) GetOffers(ctx context.Context, args Args) ([]Hotel, error) {
 		return nil, fmt.Errorf("serialize hotel url: %w", err)
 	}
 
-	req, err := retryablehttp.NewRequestWithContext(ctx, "GET", urlStr, nil)
+	req, err := retryablehttp.NewRequestWithContext(withRateLimitKind(ctx, ratelimit.KindHotels), "GET", urlStr, nil)
 	if err != nil {
 		return nil, fmt.Errorf("build hotel request: %w", err)
 	}
@@ -58,6 +60,9 @@ func (s *Session) GetOffers(ctx context.Context, args Args) ([]Hotel, error) {
 		return nil, fmt.Errorf("execute hotel request: %w", err)
 	}
 	defer resp.Body.Close()
+	if err := blockdetect.Classify(resp); err != nil {
+		return nil, fmt.Errorf("execute hotel request: %w", err)
+	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -65,7 +70,12 @@ func (s *Session) GetOffers(ctx context.Context, args Args) ([]Hotel, error) {
 	}
 	offers, err := parseHotelsFromHTML(string(bodyBytes), args.Currency.String())
 	if err != nil {
-		return nil, fmt.Errorf("parse hotel response: %w", err)
+		// The hotel page is HTML too, so the interstitial markers are only trusted when the hotel
+		// data is missing.
+		if blockErr := blockdetect.ClassifyBody(bodyBytes); blockErr != nil {
+			return nil, fmt.Errorf("parse hotel response: %w", blockErr)
+		}
+		return nil, fmt.Errorf("parse hotel response: %w: %v", ErrSchemaChanged, err)
 	}
 	return offers, nil
 }
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/browserutils/kooky"
+	"github.com/gilby125/google-flights-api/pkg/blockdetect"
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
@@ -38,6 +40,33 @@ func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error
 	return t.base.RoundTrip(req)
 }
 
+type rateLimitKindKey struct{}
+
+// withRateLimitKind returns a copy of ctx which tells [limitTransport] the kind of the request.
+func withRateLimitKind(ctx context.Context, kind string) context.Context {
+	return context.WithValue(ctx, rateLimitKindKey{}, kind)
+}
+
+// limitTransport takes a token of the rate limiter before every attempt of a request, so that the
+// retries of the retryable client are charged to the budget like the first attempt.
+type limitTransport struct {
+	limiter ratelimit.Limiter // if nil, the process-wide limiter is used
+	next    http.RoundTripper
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if kind, ok := req.Context().Value(rateLimitKindKey{}).(string); ok {
+		wait := ratelimit.Wait
+		if t.limiter != nil {
+			wait = t.limiter.Wait
+		}
+		if err := wait(req.Context(), kind); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
 type httpClient interface {
 	Do(req *retryablehttp.Request) (*http.Response, error)
 }
@@ -62,6 +91,12 @@ func customRetryPolicy() func(ctx context.Context, resp *http.Response, err erro
 			return true, fmt.Errorf("response is nil")
 		}
 
+		// Retrying an interstitial only digs the hole deeper. The consent page is left to the
+		// callers, because the session cookies can be taken from it.
+		if err := blockdetect.Classify(resp); err != nil && !errors.Is(err, blockdetect.ErrConsentRequired) {
+			return false, err
+		}
+
 		if resp.StatusCode != http.StatusOK {
 			return true, fmt.Errorf("wrong status code: %d", resp.StatusCode)
 		}
@@ -80,22 +115,30 @@ func getCookies(res *http.Response) ([]string, error) {
 	return nil, fmt.Errorf("could not find the 'Set-Cookie' header in the initialization response")
 }
 
-func New() (*Session, error) {
+func newRetryableClient(transport http.RoundTripper) *retryablehttp.Client {
 	client := retryablehttp.NewClient()
 	client.RetryMax = 5
 	client.Logger = nil
 	client.CheckRetry = customRetryPolicy()
 	client.RetryWaitMin = time.Second
 	client.HTTPClient.Timeout = 90 * time.Second
+	client.HTTPClient.Transport = transport
+	return client
+}
 
+func New() (*Session, error) {
 	// Set a modern User-Agent
-	client.HTTPClient.Transport = &userAgentTransport{
+	client := newRetryableClient(&limitTransport{next: &userAgentTransport{
 		base: http.DefaultTransport,
 		ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-	}
+	}})
 
 	// Initialize cookies from google.com
-	res, err := client.Get("https://www.google.com/")
+	req, err := retryablehttp.NewRequestWithContext(withRateLimitKind(context.Background(), ratelimit.KindSession), http.MethodGet, "https://www.google.com/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("new session: err creating request to www.google.com: %w", err)
+	}
+	res, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("new session: err sending request to www.google.com: %w", err)
 	}
@@ -0,0 +1,59 @@
+package hotels
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+type limiterMock struct {
+	kinds []string
+}
+
+func (l *limiterMock) Wait(ctx context.Context, kind string) error {
+	l.kinds = append(l.kinds, kind)
+	return nil
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestGetOffersChargesRetries(t *testing.T) {
+	hotel := make([]any, 17)
+	hotel[0] = "Hotel A"
+	hotel[2] = "$123"
+	body := `<script>AF_initDataCallback({key: 'ds:0', data:[[` + mustJSON(t, []any{hotel}) + `]], sideChannel: {}});</script>`
+
+	calls := 0
+	limiter := &limiterMock{}
+	client := newRetryableClient(&limitTransport{limiter: limiter, next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}, Request: req}, nil
+	})})
+	client.RetryWaitMin, client.RetryWaitMax = time.Millisecond, time.Millisecond
+	session := &Session{client: client}
+
+	offers, err := session.GetOffers(context.Background(), Args{
+		Location:     "Paris",
+		CheckInDate:  time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC),
+		CheckOutDate: time.Date(2026, 11, 4, 0, 0, 0, 0, time.UTC),
+		Travelers:    Travelers{Adults: 2},
+		Currency:     currency.USD,
+		Lang:         language.English,
+	})
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	require.Equal(t, []string{ratelimit.KindHotels, ratelimit.KindHotels}, limiter.kinds, "the retry should take a token")
+}
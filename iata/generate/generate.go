@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +24,7 @@ type airport struct {
 }
 
 type result struct {
+	code string
 	line string
 	err  error
 }
@@ -94,7 +96,7 @@ func main() {
 			}
 
 			if ok {
-				res := result{line: fmt.Sprintf(caseTmpl, iata, city, tz, lat, lon), err: nil}
+				res := result{code: iata, line: fmt.Sprintf(caseTmpl, iata, city, tz, lat, lon), err: nil}
 				out <- res
 			}
 		}(a.Iata, a.Tz, a.City, a.Lat, a.Lon)
@@ -132,15 +134,18 @@ func IATATimeZone(iata string) Location {
 `, time.Now().Format(time.DateOnly), commitHash)
 
 	lines := []string{}
+	codes := []string{}
 
 	for res := range out {
 		if res.err != nil {
 			log.Fatal(res.err)
 		}
 		lines = append(lines, res.line)
+		codes = append(codes, res.code)
 	}
 
 	sort.Strings(lines)
+	sort.Strings(codes)
 
 	for _, line := range lines {
 		iataFileContent += line
@@ -149,8 +154,23 @@ func IATATimeZone(iata string) Location {
 	iataFileContent += `	}
 	return Location{"Not supported IATA Code", "Not supported IATA Code", 0, 0}
 }
+
+// Codes returns the supported IATA airport codes in alphabetical order, e.g. to look up the
+// Location of every airport with IATATimeZone.
+func Codes() []string {
+	return append([]string(nil), codes[:]...)
+}
+
+var codes = [...]string{
 `
 
+	for i := 0; i < len(codes); i += 12 {
+		row := codes[i:min(i+12, len(codes))]
+		iataFileContent += "\t\"" + strings.Join(row, "\", \"") + "\",\n"
+	}
+
+	iataFileContent += "}\n"
+
 	iataFile, err := os.Create("./iata/iata.go")
 	if err != nil {
 		log.Fatal(err)
@@ -6840,3 +6840,296 @@ func IATATimeZone(iata string) Location {
 	}
 	return Location{"Not supported IATA Code", "Not supported IATA Code", 0, 0}
 }
+
+// Codes returns the supported IATA airport codes in alphabetical order, e.g. to look up the
+// Location of every airport with IATATimeZone.
+func Codes() []string {
+	return append([]string(nil), codes[:]...)
+}
+
+var codes = [...]string{
+	"AAA", "AAE", "AAL", "AAN", "AAP", "AAR", "AAT", "AAX", "AAZ", "ABA", "ABB", "ABD",
+	"ABE", "ABI", "ABJ", "ABL", "ABM", "ABQ", "ABR", "ABS", "ABT", "ABU", "ABV", "ABX",
+	"ABY", "ABZ", "ACA", "ACC", "ACE", "ACF", "ACH", "ACI", "ACK", "ACT", "ACV", "ACX",
+	"ACY", "ACZ", "ADA", "ADB", "ADD", "ADE", "ADF", "ADK", "ADL", "ADQ", "ADU", "ADZ",
+	"AEB", "AEP", "AER", "AES", "AET", "AEX", "AEY", "AFA", "AFL", "AGA", "AGH", "AGP",
+	"AGR", "AGS", "AGT", "AGU", "AGV", "AGX", "AHB", "AHE", "AHO", "AHU", "AIA", "AIN",
+	"AIR", "AIT", "AJA", "AJF", "AJI", "AJL", "AJN", "AJR", "AJU", "AKA", "AKB", "AKI",
+	"AKJ", "AKK", "AKL", "AKN", "AKP", "AKR", "AKS", "AKU", "AKV", "AKX", "AKY", "ALA",
+	"ALB", "ALC", "ALF", "ALG", "ALH", "ALI", "ALO", "ALP", "ALQ", "ALS", "ALW", "AMA",
+	"AMD", "AMH", "AMM", "AMQ", "AMS", "ANC", "ANF", "ANI", "ANR", "ANU", "ANV", "ANX",
+	"AOE", "AOG", "AOI", "AOJ", "AOK", "AOO", "AOR", "APK", "APL", "APN", "APO", "APW",
+	"AQG", "AQI", "AQJ", "AQP", "ARC", "ARD", "ARH", "ARI", "ARK", "ARM", "ARN", "ART",
+	"ARU", "ASB", "ASE", "ASF", "ASJ", "ASM", "ASO", "ASP", "ASR", "ASU", "ASV", "ASW",
+	"ATA", "ATD", "ATH", "ATK", "ATL", "ATM", "ATQ", "ATW", "ATY", "ATZ", "AUA", "AUC",
+	"AUG", "AUH", "AUK", "AUQ", "AUR", "AUS", "AUU", "AUX", "AUY", "AVA", "AVL", "AVP",
+	"AVV", "AWA", "AWD", "AWZ", "AXA", "AXD", "AXJ", "AXM", "AXP", "AXR", "AXT", "AYP",
+	"AYQ", "AYT", "AZA", "AZD", "AZI", "AZO", "AZR", "AZS", "BAG", "BAH", "BAL", "BAQ",
+	"BAS", "BAU", "BAV", "BAX", "BAY", "BAZ", "BBA", "BBI", "BBK", "BBN", "BBU", "BCD",
+	"BCI", "BCM", "BCN", "BCO", "BCT", "BDA", "BDB", "BDD", "BDJ", "BDL", "BDO", "BDP",
+	"BDQ", "BDR", "BDS", "BDU", "BEB", "BEG", "BEJ", "BEK", "BEL", "BEM", "BEN", "BER",
+	"BES", "BET", "BEU", "BEW", "BEY", "BFD", "BFF", "BFI", "BFJ", "BFL", "BFM", "BFN",
+	"BFS", "BFV", "BFX", "BGA", "BGF", "BGI", "BGM", "BGN", "BGO", "BGR", "BGS", "BGW",
+	"BGX", "BGY", "BHB", "BHD", "BHE", "BHH", "BHI", "BHJ", "BHK", "BHM", "BHO", "BHQ",
+	"BHR", "BHU", "BHX", "BHY", "BIA", "BIH", "BIK", "BIL", "BIM", "BIO", "BIQ", "BIR",
+	"BIS", "BJA", "BJC", "BJF", "BJI", "BJL", "BJM", "BJR", "BJV", "BJW", "BJX", "BJZ",
+	"BKA", "BKB", "BKC", "BKG", "BKI", "BKK", "BKM", "BKO", "BKQ", "BKS", "BKW", "BKZ",
+	"BLA", "BLB", "BLD", "BLI", "BLJ", "BLL", "BLQ", "BLR", "BLV", "BLZ", "BMA", "BME",
+	"BMI", "BMO", "BMU", "BMV", "BMW", "BNA", "BND", "BNE", "BNI", "BNK", "BNN", "BNS",
+	"BNX", "BNY", "BOB", "BOC", "BOD", "BOG", "BOH", "BOI", "BOJ", "BOM", "BON", "BOO",
+	"BOS", "BOY", "BPG", "BPL", "BPN", "BPS", "BPT", "BPX", "BQB", "BQD", "BQK", "BQL",
+	"BQN", "BQS", "BQW", "BRA", "BRB", "BRC", "BRD", "BRE", "BRI", "BRK", "BRL", "BRM",
+	"BRN", "BRO", "BRQ", "BRR", "BRS", "BRU", "BRW", "BSA", "BSB", "BSC", "BSD", "BSG",
+	"BSK", "BSL", "BSO", "BSR", "BTC", "BTH", "BTI", "BTJ", "BTK", "BTM", "BTR", "BTS",
+	"BTT", "BTU", "BTV", "BTW", "BUA", "BUC", "BUD", "BUF", "BUN", "BUP", "BUQ", "BUR",
+	"BUS", "BUW", "BUX", "BUZ", "BVA", "BVB", "BVC", "BVE", "BVG", "BVH", "BVI", "BVS",
+	"BWA", "BWI", "BWK", "BWN", "BWT", "BXG", "BXU", "BXY", "BYC", "BYK", "BYN", "BYO",
+	"BZE", "BZG", "BZL", "BZN", "BZO", "BZR", "BZV", "CAB", "CAC", "CAE", "CAG", "CAH",
+	"CAI", "CAK", "CAL", "CAN", "CAP", "CAU", "CAW", "CAY", "CAZ", "CBB", "CBG", "CBH",
+	"CBL", "CBO", "CBQ", "CBR", "CBT", "CCC", "CCF", "CCJ", "CCK", "CCP", "CCR", "CCS",
+	"CCU", "CCV", "CDB", "CDC", "CDG", "CDP", "CDR", "CDT", "CDV", "CEB", "CEC", "CED",
+	"CEE", "CEI", "CEK", "CEM", "CEN", "CEZ", "CFB", "CFE", "CFG", "CFN", "CFR", "CFS",
+	"CFU", "CGB", "CGD", "CGH", "CGI", "CGK", "CGM", "CGN", "CGO", "CGP", "CGQ", "CGR",
+	"CGY", "CHA", "CHC", "CHG", "CHH", "CHO", "CHQ", "CHS", "CHT", "CHU", "CHX", "CHY",
+	"CIA", "CID", "CIF", "CIH", "CIJ", "CIK", "CIT", "CIU", "CIX", "CIY", "CIZ", "CJA",
+	"CJB", "CJC", "CJJ", "CJM", "CJS", "CJU", "CKB", "CKG", "CKH", "CKO", "CKS", "CKY",
+	"CKZ", "CLD", "CLE", "CLJ", "CLL", "CLO", "CLP", "CLQ", "CLT", "CLV", "CLY", "CMA",
+	"CMB", "CME", "CMF", "CMG", "CMH", "CMI", "CMN", "CMW", "CMX", "CNC", "CND", "CNF",
+	"CNJ", "CNM", "CNN", "CNQ", "CNS", "CNX", "CNY", "COD", "COH", "COK", "COO", "COR",
+	"COS", "COU", "CPC", "CPD", "CPE", "CPH", "CPO", "CPR", "CPT", "CPV", "CPX", "CQD",
+	"CRA", "CRD", "CRI", "CRK", "CRL", "CRM", "CRP", "CRV", "CRW", "CRZ", "CSG", "CSK",
+	"CSU", "CSX", "CSY", "CTA", "CTC", "CTD", "CTG", "CTL", "CTM", "CTS", "CTU", "CUC",
+	"CUE", "CUF", "CUL", "CUM", "CUN", "CUR", "CUU", "CUZ", "CVG", "CVM", "CVN", "CVQ",
+	"CVU", "CWA", "CWB", "CWL", "CXB", "CXI", "CXJ", "CXR", "CXY", "CYA", "CYB", "CYF",
+	"CYI", "CYO", "CYP", "CYS", "CYX", "CYZ", "CZL", "CZM", "CZS", "CZU", "CZX", "DAB",
+	"DAC", "DAD", "DAL", "DAM", "DAR", "DAT", "DAU", "DAV", "DAY", "DBO", "DBQ", "DBR",
+	"DBV", "DCA", "DCM", "DDC", "DDG", "DEB", "DEC", "DED", "DEE", "DEL", "DEN", "DFW",
+	"DGE", "DGH", "DGO", "DGT", "DHI", "DHM", "DHN", "DIB", "DIE", "DIG", "DIK", "DIL",
+	"DIN", "DIR", "DIU", "DIY", "DJB", "DJE", "DJG", "DJJ", "DKR", "DKS", "DLA", "DLC",
+	"DLE", "DLG", "DLH", "DLI", "DLM", "DLU", "DLY", "DLZ", "DMB", "DMD", "DME", "DMK",
+	"DMM", "DMU", "DND", "DNH", "DNZ", "DOB", "DOD", "DOH", "DOL", "DOM", "DOY", "DPL",
+	"DPO", "DPS", "DQA", "DQM", "DRB", "DRG", "DRK", "DRO", "DRS", "DRW", "DSE", "DSM",
+	"DSN", "DSS", "DTB", "DTM", "DTW", "DUB", "DUD", "DUE", "DUJ", "DUR", "DUS", "DUT",
+	"DVL", "DVO", "DWC", "DWD", "DXB", "DYG", "DYR", "DYU", "DZA", "DZN", "EAA", "EAE",
+	"EAM", "EAR", "EAS", "EAT", "EAU", "EBB", "EBH", "EBJ", "EBL", "ECN", "ECP", "EDI",
+	"EDL", "EDO", "EDR", "EEK", "EFL", "EGC", "EGE", "EGM", "EGS", "EGX", "EIN", "EIS",
+	"EJA", "EKO", "ELC", "ELD", "ELG", "ELH", "ELI", "ELM", "ELP", "ELQ", "ELS", "ELU",
+	"EMA", "EMD", "EMK", "EMN", "ENA", "ENE", "ENH", "ENI", "ENU", "ENY", "EOH", "EPR",
+	"EQS", "ERC", "ERF", "ERG", "ERH", "ERI", "ERL", "ERN", "ERS", "ERZ", "ESB", "ESC",
+	"ESD", "ESU", "ETM", "ETR", "ETZ", "EUA", "EUG", "EUN", "EUQ", "EUX", "EVE", "EVG",
+	"EVN", "EVV", "EWB", "EWN", "EWR", "EXT", "EYP", "EYW", "EZE", "EZS", "EZV", "FAC",
+	"FAE", "FAI", "FAO", "FAR", "FAT", "FAV", "FAY", "FBE", "FBM", "FCA", "FCO", "FDE",
+	"FDF", "FDH", "FEC", "FEG", "FEN", "FEZ", "FGU", "FHZ", "FIH", "FIZ", "FJR", "FKB",
+	"FKI", "FKQ", "FKS", "FLA", "FLG", "FLL", "FLN", "FLO", "FLR", "FLS", "FLW", "FLZ",
+	"FMA", "FMI", "FMM", "FMO", "FNA", "FNC", "FNI", "FNT", "FOC", "FOD", "FOG", "FON",
+	"FOR", "FPO", "FRA", "FRD", "FRE", "FRL", "FRO", "FRS", "FRU", "FRW", "FSC", "FSD",
+	"FSM", "FSZ", "FTA", "FTE", "FTU", "FUE", "FUG", "FUJ", "FUK", "FUN", "FUO", "FVM",
+	"FWA", "FYU", "GAE", "GAJ", "GAL", "GAM", "GAN", "GAU", "GAY", "GBE", "GBI", "GBT",
+	"GCC", "GCI", "GCK", "GCM", "GCN", "GCW", "GDE", "GDL", "GDN", "GDQ", "GDT", "GDV",
+	"GDX", "GEA", "GEG", "GEL", "GEO", "GES", "GET", "GEV", "GFF", "GFK", "GGF", "GGG",
+	"GGM", "GGT", "GGW", "GHA", "GHB", "GHC", "GHT", "GIB", "GIC", "GIG", "GIL", "GIS",
+	"GIU", "GIZ", "GJA", "GJL", "GJT", "GKA", "GLA", "GLF", "GLH", "GLT", "GLV", "GMA",
+	"GMB", "GMO", "GMP", "GMR", "GMZ", "GNB", "GND", "GNJ", "GNM", "GNS", "GNV", "GNY",
+	"GOA", "GOB", "GOH", "GOI", "GOJ", "GOM", "GOP", "GOQ", "GOT", "GOU", "GOV", "GOY",
+	"GPA", "GPB", "GPI", "GPS", "GPT", "GRB", "GRI", "GRJ", "GRK", "GRO", "GRQ", "GRR",
+	"GRU", "GRV", "GRW", "GRX", "GRZ", "GSM", "GSO", "GSP", "GST", "GSV", "GTE", "GTF",
+	"GTO", "GTP", "GTR", "GTS", "GUA", "GUC", "GUM", "GUP", "GUR", "GUW", "GVA", "GVR",
+	"GWD", "GWL", "GWT", "GXF", "GYD", "GYE", "GYN", "GYS", "GYU", "GZO", "GZP", "GZT",
+	"HAA", "HAC", "HAD", "HAH", "HAJ", "HAK", "HAM", "HAN", "HAQ", "HAS", "HAU", "HAV",
+	"HAY", "HBA", "HBE", "HBX", "HCQ", "HCR", "HDF", "HDG", "HDK", "HDN", "HDS", "HDY",
+	"HEA", "HEH", "HEK", "HEL", "HER", "HET", "HFE", "HFS", "HFT", "HGA", "HGD", "HGH",
+	"HGN", "HGO", "HGR", "HGU", "HHH", "HHN", "HHQ", "HHR", "HHZ", "HIA", "HIB", "HID",
+	"HIJ", "HIN", "HIR", "HJJ", "HJR", "HKD", "HKG", "HKK", "HKN", "HKT", "HLA", "HLD",
+	"HLH", "HLN", "HLP", "HLZ", "HMA", "HMB", "HME", "HMI", "HMO", "HMV", "HNA", "HND",
+	"HNH", "HNL", "HNM", "HNS", "HNY", "HOB", "HOF", "HOG", "HOI", "HOM", "HOR", "HOT",
+	"HOU", "HOV", "HOX", "HPA", "HPB", "HPH", "HPN", "HRB", "HRE", "HRG", "HRL", "HRO",
+	"HSA", "HSG", "HSL", "HSN", "HSV", "HTA", "HTG", "HTI", "HTN", "HTS", "HTY", "HUH",
+	"HUI", "HUN", "HUS", "HUU", "HUX", "HUY", "HUZ", "HVB", "HVD", "HVG", "HVN", "HVR",
+	"HWN", "HYA", "HYD", "HYN", "HYS", "HZG", "IAA", "IAD", "IAG", "IAH", "IAM", "IAN",
+	"IAO", "IAR", "IAS", "IBA", "IBE", "IBR", "IBZ", "ICI", "ICN", "ICT", "IDA", "IDR",
+	"IEG", "IEV", "IFJ", "IFN", "IFU", "IGA", "IGD", "IGG", "IGR", "IGT", "IGU", "IIL",
+	"IJK", "IKA", "IKI", "IKO", "IKS", "IKT", "IKU", "ILD", "ILG", "ILI", "ILM", "ILO",
+	"ILP", "ILQ", "ILR", "ILY", "IMF", "IMP", "IMT", "INC", "IND", "INF", "INH", "INI",
+	"INL", "INN", "INU", "INV", "INZ", "IOA", "IOM", "IOS", "IPA", "IPC", "IPH", "IPI",
+	"IPL", "IPN", "IQM", "IQN", "IQQ", "IQT", "IRA", "IRC", "IRG", "IRI", "IRJ", "IRK",
+	"IRM", "IRP", "IRZ", "ISA", "ISB", "ISE", "ISG", "ISK", "ISP", "IST", "ISU", "ITB",
+	"ITH", "ITM", "ITO", "IUE", "IVC", "IVL", "IVR", "IWA", "IWD", "IWJ", "IWK", "IXA",
+	"IXB", "IXC", "IXD", "IXE", "IXG", "IXI", "IXJ", "IXK", "IXL", "IXM", "IXR", "IXS",
+	"IXT", "IXU", "IXW", "IXY", "IXZ", "IZA", "IZO", "JAC", "JAE", "JAF", "JAI", "JAN",
+	"JAU", "JAV", "JAX", "JBQ", "JBR", "JCK", "JDH", "JDO", "JDZ", "JED", "JEE", "JEG",
+	"JEK", "JER", "JFK", "JFR", "JGA", "JGN", "JGS", "JHB", "JHG", "JHM", "JHS", "JIA",
+	"JIB", "JIC", "JIJ", "JIK", "JIM", "JIN", "JIQ", "JIU", "JJD", "JJN", "JKH", "JKL",
+	"JKR", "JLN", "JLR", "JMK", "JMS", "JMU", "JNB", "JNG", "JNU", "JNX", "JNZ", "JOE",
+	"JOG", "JOI", "JOS", "JPA", "JPR", "JQA", "JRG", "JRH", "JRO", "JSA", "JSH", "JSI",
+	"JSR", "JST", "JSU", "JSY", "JTC", "JTR", "JTY", "JUB", "JUJ", "JUL", "JUV", "JUZ",
+	"JYV", "JZH", "KAA", "KAB", "KAD", "KAJ", "KAL", "KAN", "KAO", "KAW", "KAZ", "KBH",
+	"KBL", "KBP", "KBR", "KBU", "KBV", "KCA", "KCH", "KCK", "KCM", "KCT", "KCZ", "KDH",
+	"KDI", "KDM", "KDO", "KDU", "KDV", "KEF", "KEJ", "KEM", "KEO", "KEP", "KER", "KET",
+	"KEU", "KFP", "KFS", "KGA", "KGC", "KGD", "KGE", "KGF", "KGI", "KGK", "KGL", "KGP",
+	"KGS", "KHG", "KHH", "KHI", "KHM", "KHN", "KHS", "KHT", "KHV", "KID", "KIE", "KIH",
+	"KIJ", "KIK", "KIM", "KIN", "KIR", "KIS", "KIT", "KIX", "KJA", "KKA", "KKC", "KKE",
+	"KKH", "KKJ", "KKN", "KKQ", "KKR", "KKS", "KKX", "KLF", "KLG", "KLH", "KLN", "KLO",
+	"KLR", "KLU", "KLX", "KME", "KMG", "KMI", "KMJ", "KMO", "KMQ", "KMS", "KMV", "KND",
+	"KNG", "KNH", "KNK", "KNO", "KNQ", "KNS", "KNU", "KNW", "KNX", "KOA", "KOE", "KOI",
+	"KOJ", "KOK", "KOO", "KOP", "KOS", "KOT", "KOV", "KOW", "KPN", "KPO", "KPV", "KPW",
+	"KQH", "KQT", "KRF", "KRK", "KRL", "KRN", "KRO", "KRS", "KRT", "KRW", "KRY", "KSA",
+	"KSC", "KSE", "KSF", "KSH", "KSJ", "KSM", "KSN", "KSO", "KSQ", "KSU", "KSY", "KSZ",
+	"KTA", "KTG", "KTL", "KTM", "KTN", "KTR", "KTS", "KTT", "KTW", "KUA", "KUF", "KUG",
+	"KUH", "KUK", "KUL", "KUM", "KUN", "KUO", "KUS", "KUT", "KUU", "KUV", "KVA", "KVC",
+	"KVG", "KVK", "KVL", "KVX", "KWA", "KWE", "KWI", "KWJ", "KWK", "KWL", "KWM", "KWN",
+	"KWT", "KWZ", "KXB", "KXF", "KXU", "KYA", "KYK", "KYP", "KYU", "KYZ", "KZI", "KZN",
+	"KZO", "KZR", "KZS", "LAD", "LAE", "LAH", "LAK", "LAN", "LAO", "LAP", "LAQ", "LAR",
+	"LAS", "LAU", "LAW", "LAX", "LBA", "LBB", "LBD", "LBE", "LBF", "LBJ", "LBL", "LBR",
+	"LBS", "LBU", "LBV", "LCA", "LCE", "LCG", "LCH", "LCJ", "LCK", "LCX", "LCY", "LDB",
+	"LDE", "LDH", "LDS", "LDU", "LDY", "LEA", "LEB", "LEC", "LED", "LEI", "LEJ", "LEN",
+	"LET", "LEU", "LEX", "LFR", "LFT", "LFW", "LGA", "LGB", "LGG", "LGI", "LGK", "LGL",
+	"LGW", "LHE", "LHG", "LHR", "LHW", "LIF", "LIG", "LIH", "LIL", "LIM", "LIN", "LIO",
+	"LIR", "LIS", "LIT", "LJG", "LJU", "LKA", "LKB", "LKH", "LKL", "LKN", "LKO", "LKY",
+	"LLA", "LLF", "LLI", "LLJ", "LLK", "LLW", "LMA", "LMM", "LMN", "LMP", "LNB", "LNE",
+	"LNJ", "LNK", "LNO", "LNS", "LNV", "LNY", "LNZ", "LOD", "LOE", "LOH", "LOK", "LOO",
+	"LOP", "LOS", "LOY", "LPA", "LPB", "LPI", "LPL", "LPM", "LPP", "LPQ", "LPT", "LPY",
+	"LQM", "LRD", "LRE", "LRH", "LRM", "LRR", "LRS", "LRT", "LRU", "LRV", "LSC", "LSE",
+	"LSH", "LSI", "LSP", "LSQ", "LST", "LSW", "LTI", "LTN", "LTO", "LUD", "LUM", "LUN",
+	"LUO", "LUP", "LUQ", "LUR", "LUV", "LUW", "LUX", "LUZ", "LVI", "LVO", "LWB", "LWS",
+	"LWY", "LXA", "LXG", "LXR", "LXS", "LYA", "LYB", "LYC", "LYG", "LYH", "LYI", "LYP",
+	"LYR", "LYS", "LZH", "LZN", "LZO", "LZY", "MAA", "MAB", "MAD", "MAF", "MAG", "MAH",
+	"MAJ", "MAM", "MAN", "MAO", "MAQ", "MAR", "MAS", "MAU", "MAZ", "MBA", "MBE", "MBI",
+	"MBJ", "MBL", "MBQ", "MBS", "MBT", "MBZ", "MCE", "MCI", "MCK", "MCN", "MCO", "MCP",
+	"MCT", "MCV", "MCW", "MCX", "MCY", "MCZ", "MDC", "MDE", "MDG", "MDI", "MDK", "MDL",
+	"MDQ", "MDT", "MDW", "MDZ", "MEB", "MEC", "MED", "MEE", "MEH", "MEI", "MEL", "MEM",
+	"MEQ", "MEU", "MEX", "MFA", "MFE", "MFK", "MFM", "MFR", "MFU", "MGA", "MGB", "MGF",
+	"MGH", "MGM", "MGQ", "MGT", "MGW", "MGZ", "MHC", "MHD", "MHH", "MHK", "MHQ", "MHT",
+	"MIA", "MID", "MIG", "MII", "MIM", "MIR", "MIS", "MIU", "MJC", "MJF", "MJI", "MJK",
+	"MJM", "MJN", "MJT", "MJU", "MJZ", "MKE", "MKG", "MKK", "MKL", "MKM", "MKP", "MKQ",
+	"MKR", "MKW", "MKY", "MLA", "MLB", "MLE", "MLG", "MLH", "MLI", "MLL", "MLM", "MLN",
+	"MLO", "MLU", "MLX", "MLY", "MMB", "MME", "MMG", "MMH", "MMJ", "MMK", "MMO", "MMU",
+	"MMX", "MMY", "MNA", "MNC", "MNG", "MNI", "MNL", "MNS", "MNX", "MOB", "MOC", "MOF",
+	"MOL", "MOQ", "MOT", "MOU", "MOV", "MOZ", "MPA", "MPH", "MPL", "MPM", "MPN", "MQF",
+	"MQJ", "MQL", "MQM", "MQN", "MQP", "MQT", "MQX", "MRD", "MRE", "MRS", "MRU", "MRV",
+	"MRX", "MRY", "MRZ", "MSA", "MSH", "MSJ", "MSL", "MSN", "MSO", "MSP", "MSQ", "MSR",
+	"MSS", "MST", "MSU", "MSY", "MSZ", "MTE", "MTJ", "MTR", "MTT", "MTV", "MTY", "MUA",
+	"MUB", "MUC", "MUE", "MUH", "MUN", "MUR", "MUX", "MVB", "MVD", "MVF", "MVP", "MVR",
+	"MVT", "MVY", "MWA", "MWF", "MWX", "MWZ", "MXH", "MXL", "MXP", "MXV", "MXX", "MXZ",
+	"MYA", "MYD", "MYG", "MYI", "MYJ", "MYP", "MYQ", "MYR", "MYT", "MYU", "MYW", "MYY",
+	"MZA", "MZG", "MZH", "MZL", "MZO", "MZR", "MZT", "MZV", "MZW", "NAA", "NAG", "NAH",
+	"NAJ", "NAL", "NAM", "NAN", "NAO", "NAP", "NAQ", "NAS", "NAT", "NAU", "NAV", "NAW",
+	"NBC", "NBE", "NBO", "NBX", "NCE", "NCL", "NCU", "NDB", "NDG", "NDJ", "NDR", "NDU",
+	"NEU", "NEV", "NGB", "NGE", "NGO", "NGQ", "NGS", "NHV", "NIM", "NJC", "NJF", "NKC",
+	"NKG", "NKM", "NLA", "NLD", "NLF", "NLG", "NLI", "NLK", "NLU", "NMA", "NME", "NNB",
+	"NNG", "NNL", "NNM", "NNT", "NNY", "NOB", "NOC", "NOJ", "NOP", "NOS", "NOU", "NOV",
+	"NOZ", "NPE", "NPL", "NQN", "NQU", "NQY", "NQZ", "NRA", "NRK", "NRN", "NRR", "NRT",
+	"NSH", "NSI", "NSK", "NSN", "NST", "NTE", "NTG", "NTL", "NTN", "NTQ", "NTX", "NUE",
+	"NUI", "NUK", "NUL", "NUS", "NUX", "NVA", "NVI", "NVT", "NWI", "NYA", "NYI", "NYK",
+	"NYM", "NYO", "NYR", "NYT", "NYU", "NYW", "OAG", "OAJ", "OAK", "OAL", "OAX", "OBO",
+	"OBU", "OCC", "OCJ", "ODB", "ODN", "ODO", "ODY", "OER", "OGD", "OGG", "OGL", "OGS",
+	"OGU", "OGX", "OGZ", "OHD", "OHE", "OHH", "OHO", "OHS", "OIR", "OIT", "OKA", "OKC",
+	"OKD", "OKE", "OKI", "OKJ", "OKR", "OLA", "OLB", "OLF", "OLP", "OLZ", "OMA", "OMD",
+	"OME", "OMH", "OMO", "OMR", "OMS", "OND", "ONG", "ONJ", "ONK", "ONQ", "ONS", "ONT",
+	"OOK", "OOL", "OPF", "OPO", "OPS", "ORB", "ORD", "ORF", "ORH", "ORK", "ORN", "ORT",
+	"ORU", "ORV", "ORX", "ORY", "OSD", "OSI", "OSL", "OSR", "OSS", "OST", "OSY", "OTH",
+	"OTI", "OTP", "OTZ", "OUA", "OUD", "OUI", "OUL", "OUZ", "OVB", "OVD", "OVS", "OWB",
+	"OXB", "OZC", "OZG", "OZZ", "PAB", "PAC", "PAD", "PAE", "PAF", "PAG", "PAH", "PAP",
+	"PAS", "PAT", "PAV", "PBC", "PBG", "PBH", "PBI", "PBJ", "PBM", "PBO", "PBR", "PBU",
+	"PBZ", "PCL", "PCN", "PCP", "PCR", "PDA", "PDG", "PDL", "PDP", "PDS", "PDT", "PDV",
+	"PDX", "PED", "PEE", "PEG", "PEI", "PEK", "PEM", "PEN", "PER", "PES", "PET", "PEU",
+	"PEW", "PEZ", "PFB", "PFO", "PGA", "PGD", "PGF", "PGH", "PGK", "PGV", "PGZ", "PHB",
+	"PHC", "PHE", "PHF", "PHL", "PHO", "PHS", "PHX", "PIA", "PIB", "PIE", "PIH", "PIK",
+	"PIN", "PIP", "PIR", "PIS", "PIT", "PIU", "PIX", "PIZ", "PJA", "PJM", "PKA", "PKB",
+	"PKC", "PKE", "PKN", "PKP", "PKR", "PKU", "PKV", "PKX", "PKY", "PKZ", "PLM", "PLN",
+	"PLO", "PLQ", "PLS", "PLW", "PLX", "PLZ", "PMA", "PMC", "PMF", "PMG", "PMI", "PML",
+	"PMO", "PMR", "PMV", "PMW", "PMY", "PNA", "PNH", "PNI", "PNK", "PNL", "PNP", "PNQ",
+	"PNR", "PNS", "PNT", "PNY", "PNZ", "POA", "POG", "POJ", "POL", "POM", "POP", "POR",
+	"POS", "POZ", "PPB", "PPG", "PPK", "PPN", "PPP", "PPQ", "PPS", "PPT", "PQC", "PQI",
+	"PQQ", "PRA", "PRC", "PRG", "PRI", "PRN", "PRS", "PSA", "PSC", "PSE", "PSG", "PSM",
+	"PSO", "PSP", "PSR", "PSS", "PSU", "PTA", "PTG", "PTH", "PTO", "PTP", "PTQ", "PTU",
+	"PTX", "PTY", "PUB", "PUF", "PUJ", "PUK", "PUQ", "PUS", "PUU", "PUW", "PUY", "PVA",
+	"PVC", "PVD", "PVG", "PVH", "PVK", "PVR", "PVU", "PWE", "PWM", "PWQ", "PXM", "PXO",
+	"PXU", "PYB", "PYH", "PYJ", "PYM", "PZB", "PZO", "PZU", "QBC", "QGP", "QIG", "QOW",
+	"QPA", "QRO", "QSF", "QXB", "RAB", "RAE", "RAH", "RAI", "RAK", "RAO", "RAP", "RAR",
+	"RAS", "RBA", "RBB", "RBR", "RBV", "RBY", "RCB", "RCH", "RCM", "RCQ", "RCU", "RDD",
+	"RDM", "RDO", "RDU", "RDZ", "REA", "REC", "REG", "REL", "REN", "RER", "RES", "RET",
+	"REU", "REX", "RFD", "RFP", "RGA", "RGI", "RGK", "RGL", "RGN", "RGS", "RHD", "RHI",
+	"RHO", "RIA", "RIC", "RIG", "RIH", "RIS", "RIW", "RIX", "RJA", "RJB", "RJH", "RJK",
+	"RJL", "RKA", "RKD", "RKS", "RKT", "RKV", "RLG", "RLO", "RMA", "RMF", "RMI", "RMQ",
+	"RMU", "RNA", "RNB", "RNJ", "RNL", "RNN", "RNO", "RNS", "ROA", "ROB", "ROC", "ROI",
+	"ROK", "RON", "ROO", "ROR", "ROS", "ROT", "ROV", "ROW", "RPR", "RRG", "RRK", "RRR",
+	"RRS", "RSA", "RSD", "RSH", "RST", "RSU", "RSW", "RTA", "RTB", "RTG", "RTM", "RUH",
+	"RUN", "RUR", "RUT", "RVD", "RVE", "RVK", "RVN", "RVV", "RXS", "RZE", "RZR", "SAB",
+	"SAF", "SAG", "SAL", "SAN", "SAP", "SAQ", "SAT", "SAV", "SAW", "SBA", "SBD", "SBH",
+	"SBN", "SBP", "SBR", "SBW", "SBY", "SBZ", "SCC", "SCE", "SCF", "SCK", "SCL", "SCM",
+	"SCN", "SCO", "SCQ", "SCU", "SCV", "SCW", "SCY", "SCZ", "SDD", "SDE", "SDF", "SDG",
+	"SDJ", "SDK", "SDL", "SDN", "SDP", "SDQ", "SDR", "SDU", "SDY", "SEA", "SEB", "SEN",
+	"SEU", "SEZ", "SFA", "SFB", "SFD", "SFG", "SFJ", "SFL", "SFN", "SFO", "SFT", "SGC",
+	"SGD", "SGF", "SGN", "SGO", "SGU", "SGX", "SGY", "SHA", "SHB", "SHC", "SHD", "SHE",
+	"SHH", "SHI", "SHJ", "SHL", "SHM", "SHO", "SHR", "SHS", "SHV", "SHW", "SHX", "SID",
+	"SIF", "SIG", "SIN", "SIR", "SIS", "SIT", "SJC", "SJD", "SJE", "SJI", "SJJ", "SJK",
+	"SJL", "SJO", "SJP", "SJT", "SJU", "SJW", "SJZ", "SKB", "SKD", "SKG", "SKH", "SKK",
+	"SKN", "SKO", "SKP", "SKT", "SKU", "SKX", "SKZ", "SLA", "SLC", "SLE", "SLH", "SLI",
+	"SLK", "SLL", "SLM", "SLN", "SLP", "SLU", "SLV", "SLX", "SLY", "SLZ", "SMA", "SMF",
+	"SMI", "SMK", "SML", "SMQ", "SMR", "SMS", "SMX", "SNA", "SNE", "SNN", "SNO", "SNP",
+	"SNR", "SNU", "SNW", "SOC", "SOF", "SOG", "SOJ", "SON", "SOQ", "SOU", "SOV", "SOW",
+	"SPC", "SPD", "SPI", "SPN", "SPP", "SPS", "SPU", "SPX", "SPY", "SQD", "SQG", "SQJ",
+	"SQL", "SRA", "SRE", "SRG", "SRP", "SRQ", "SRY", "SSA", "SSG", "SSH", "SSJ", "SSR",
+	"STB", "STC", "STD", "STI", "STL", "STM", "STN", "STR", "STS", "STT", "STV", "STW",
+	"STX", "SUB", "SUF", "SUG", "SUJ", "SUN", "SUV", "SUX", "SUY", "SVA", "SVB", "SVC",
+	"SVD", "SVG", "SVI", "SVJ", "SVL", "SVO", "SVQ", "SVU", "SVX", "SVZ", "SWA", "SWF",
+	"SWJ", "SWO", "SWQ", "SWT", "SWV", "SXB", "SXK", "SXM", "SXR", "SXV", "SXZ", "SYD",
+	"SYJ", "SYM", "SYO", "SYR", "SYS", "SYU", "SYX", "SYY", "SYZ", "SZA", "SZB", "SZE",
+	"SZF", "SZG", "SZK", "SZX", "SZY", "SZZ", "TAB", "TAC", "TAE", "TAG", "TAH", "TAK",
+	"TAL", "TAM", "TAO", "TAP", "TAS", "TAT", "TAY", "TAZ", "TBB", "TBG", "TBH", "TBN",
+	"TBO", "TBP", "TBS", "TBT", "TBU", "TBW", "TBZ", "TCA", "TCG", "TCO", "TCQ", "TCR",
+	"TCZ", "TDD", "TDK", "TDX", "TEC", "TEE", "TEI", "TEN", "TEQ", "TER", "TET", "TEX",
+	"TEZ", "TFF", "TFL", "TFN", "TFS", "TFU", "TGC", "TGD", "TGG", "TGI", "TGM", "TGO",
+	"TGP", "TGR", "TGT", "TGU", "TGZ", "THD", "THE", "THL", "THN", "THQ", "THR", "THS",
+	"THU", "THX", "TIA", "TID", "TIF", "TIH", "TIJ", "TIM", "TIN", "TIP", "TIR", "TIU",
+	"TIV", "TIZ", "TJA", "TJH", "TJK", "TJL", "TJM", "TJN", "TJQ", "TJS", "TJU", "TKD",
+	"TKF", "TKG", "TKJ", "TKK", "TKN", "TKP", "TKQ", "TKS", "TKU", "TKV", "TKX", "TLA",
+	"TLC", "TLE", "TLH", "TLI", "TLK", "TLL", "TLM", "TLN", "TLS", "TLU", "TLV", "TLY",
+	"TMC", "TME", "TMI", "TMJ", "TML", "TMM", "TMP", "TMR", "TMS", "TMT", "TMW", "TMX",
+	"TNA", "TNC", "TNE", "TNG", "TNH", "TNJ", "TNN", "TNO", "TNR", "TOB", "TOE", "TOF",
+	"TOG", "TOH", "TOL", "TOS", "TOY", "TPA", "TPE", "TPP", "TPQ", "TPS", "TRC", "TRD",
+	"TRE", "TRF", "TRG", "TRI", "TRK", "TRN", "TRR", "TRS", "TRU", "TRV", "TRW", "TRZ",
+	"TSA", "TSF", "TSJ", "TSM", "TSN", "TSR", "TST", "TSV", "TTA", "TTE", "TTJ", "TTN",
+	"TTQ", "TTT", "TTU", "TUB", "TUC", "TUF", "TUG", "TUI", "TUK", "TUL", "TUN", "TUO",
+	"TUP", "TUR", "TUS", "TUU", "TVC", "TVF", "TVU", "TVY", "TWF", "TWU", "TXF", "TXK",
+	"TXN", "TYD", "TYF", "TYL", "TYN", "TYR", "TYS", "TZL", "TZX", "UAH", "UAK", "UAP",
+	"UAQ", "UAS", "UBA", "UBB", "UBJ", "UBN", "UBP", "UCT", "UDI", "UDR", "UEL", "UEO",
+	"UET", "UFA", "UGC", "UIB", "UIH", "UII", "UIN", "UIO", "UKA", "UKB", "UKG", "UKK",
+	"UKX", "ULB", "ULG", "ULH", "ULK", "ULO", "ULP", "ULV", "UME", "UMU", "UNA", "UNG",
+	"UNK", "UNN", "UPG", "UPN", "URA", "URC", "URE", "URG", "URJ", "URT", "URY", "USH",
+	"USK", "USM", "USN", "USR", "USU", "UTH", "UTN", "UTP", "UTT", "UUA", "UUD", "UUS",
+	"UVE", "UVF", "UYN", "UYU", "VAA", "VAG", "VAI", "VAK", "VAM", "VAN", "VAO", "VAR",
+	"VAS", "VAV", "VAW", "VBA", "VBP", "VBV", "VBY", "VCA", "VCE", "VCL", "VCP", "VCS",
+	"VCT", "VDC", "VDE", "VDH", "VDM", "VDS", "VDY", "VDZ", "VEE", "VEL", "VER", "VFA",
+	"VGA", "VGO", "VGZ", "VHC", "VHM", "VHZ", "VIE", "VIG", "VII", "VIJ", "VIL", "VIT",
+	"VIX", "VJB", "VKG", "VKO", "VKT", "VLC", "VLD", "VLI", "VLL", "VLN", "VLS", "VLV",
+	"VNO", "VNS", "VNX", "VNY", "VOG", "VOL", "VPE", "VPS", "VPY", "VQS", "VRA", "VRB",
+	"VRC", "VRN", "VSA", "VST", "VTE", "VTZ", "VUP", "VUS", "VVC", "VVI", "VVO", "VVZ",
+	"VXC", "VXE", "VXO", "WAA", "WAE", "WAG", "WAW", "WBM", "WBQ", "WDH", "WEF", "WEH",
+	"WEI", "WGA", "WGE", "WGP", "WHK", "WIC", "WIL", "WIN", "WJU", "WKA", "WKJ", "WLE",
+	"WLG", "WLH", "WLK", "WLS", "WMI", "WMN", "WMO", "WMX", "WNA", "WNP", "WNR", "WNZ",
+	"WOL", "WPR", "WPU", "WRE", "WRG", "WRO", "WRZ", "WSN", "WSZ", "WTB", "WTK", "WUH",
+	"WUN", "WUS", "WUX", "WUZ", "WVB", "WWK", "WWT", "WXN", "WYA", "WYS", "XAP", "XBE",
+	"XCH", "XCR", "XFN", "XGR", "XIC", "XIL", "XIY", "XKH", "XLS", "XMH", "XMN", "XMY",
+	"XNA", "XNN", "XPL", "XQP", "XRY", "XSC", "XSP", "XTG", "XUZ", "YAA", "YAB", "YAC",
+	"YAG", "YAK", "YAM", "YAP", "YAT", "YAY", "YAZ", "YBB", "YBE", "YBG", "YBK", "YBL",
+	"YBP", "YBR", "YBX", "YCB", "YCD", "YCG", "YCK", "YCO", "YCS", "YCU", "YCY", "YDA",
+	"YDF", "YDP", "YEG", "YEI", "YEK", "YEV", "YFA", "YFB", "YFC", "YFH", "YFJ", "YFO",
+	"YFS", "YFX", "YGH", "YGJ", "YGK", "YGL", "YGP", "YGR", "YGT", "YGW", "YGX", "YGZ",
+	"YHA", "YHD", "YHI", "YHK", "YHM", "YHO", "YHP", "YHR", "YHU", "YHY", "YHZ", "YIA",
+	"YIF", "YIH", "YIK", "YIN", "YIO", "YIW", "YKA", "YKF", "YKG", "YKL", "YKM", "YKO",
+	"YKQ", "YKS", "YKU", "YLC", "YLE", "YLH", "YLL", "YLW", "YMH", "YMM", "YMN", "YMO",
+	"YMT", "YNA", "YNB", "YNC", "YNJ", "YNL", "YNO", "YNS", "YNT", "YNY", "YNZ", "YOC",
+	"YOG", "YOJ", "YOL", "YOW", "YPA", "YPH", "YPJ", "YPM", "YPO", "YPR", "YPW", "YPX",
+	"YPY", "YQB", "YQC", "YQD", "YQG", "YQK", "YQL", "YQM", "YQQ", "YQR", "YQT", "YQU",
+	"YQX", "YQY", "YQZ", "YRA", "YRB", "YRF", "YRG", "YRL", "YRT", "YSB", "YSF", "YSG",
+	"YSJ", "YSK", "YSM", "YTE", "YTH", "YTQ", "YTS", "YTZ", "YUD", "YUL", "YUM", "YUS",
+	"YUT", "YUX", "YUY", "YVB", "YVC", "YVM", "YVO", "YVP", "YVQ", "YVR", "YVZ", "YWB",
+	"YWG", "YWJ", "YWK", "YWL", "YWP", "YXC", "YXE", "YXH", "YXJ", "YXL", "YXN", "YXP",
+	"YXS", "YXT", "YXU", "YXX", "YXY", "YYB", "YYC", "YYD", "YYE", "YYF", "YYG", "YYH",
+	"YYJ", "YYQ", "YYR", "YYT", "YYY", "YYZ", "YZF", "YZG", "YZP", "YZS", "YZT", "YZV",
+	"YZY", "YZZ", "ZAD", "ZAG", "ZAH", "ZAL", "ZAM", "ZAT", "ZAZ", "ZBF", "ZBR", "ZCL",
+	"ZCO", "ZEL", "ZEM", "ZER", "ZFD", "ZFN", "ZGU", "ZHA", "ZHY", "ZIA", "ZIG", "ZIH",
+	"ZIX", "ZKE", "ZKP", "ZLO", "ZLT", "ZMT", "ZNE", "ZNZ", "ZOS", "ZPB", "ZQN", "ZRH",
+	"ZSA", "ZSE", "ZSJ", "ZTA", "ZTB", "ZTH", "ZUH", "ZUM", "ZVK", "ZWL", "ZYI", "ZYL",
+}
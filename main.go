@@ -14,7 +14,9 @@ import (
 	"github.com/gilby125/google-flights-api/config"
 	"github.com/gilby125/google-flights-api/db"
 	"github.com/gilby125/google-flights-api/hotels"
+	"github.com/gilby125/google-flights-api/pkg/currency"
 	"github.com/gilby125/google-flights-api/pkg/logger"
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
 	"github.com/gilby125/google-flights-api/queue"
 	"github.com/gilby125/google-flights-api/worker"
 	"github.com/gin-gonic/gin"
@@ -90,16 +92,18 @@ func main() {
 				os.Exit(1)
 			}
 
-			// Redis auth + connectivity check
-			redisClient := redis.NewClient(&redis.Options{
-				Addr:     cfg.RedisConfig.Host + ":" + cfg.RedisConfig.Port,
-				Password: cfg.RedisConfig.Password,
-				DB:       cfg.RedisConfig.DB,
-			})
-			defer redisClient.Close()
+			// Redis auth + connectivity check (the Postgres queue backend needs no Redis)
+			if cfg.QueueBackend == config.QueueBackendRedis {
+				redisClient := redis.NewClient(&redis.Options{
+					Addr:     cfg.RedisConfig.Host + ":" + cfg.RedisConfig.Port,
+					Password: cfg.RedisConfig.Password,
+					DB:       cfg.RedisConfig.DB,
+				})
+				defer redisClient.Close()
 
-			if _, err := redisClient.Ping(ctx).Result(); err != nil {
-				os.Exit(1)
+				if _, err := redisClient.Ping(ctx).Result(); err != nil {
+					os.Exit(1)
+				}
 			}
 
 			// Optional Neo4j connectivity check
@@ -145,7 +149,7 @@ func main() {
 	// Initialize database connections with retries
 	var postgresDB db.PostgresDB
 	var neo4jDB *db.Neo4jDB
-	var redisQueue *queue.RedisQueue
+	var jobQueue queue.Queue
 
 	maxRetries := 10
 	retryDelay := 5 * time.Second
@@ -169,10 +173,24 @@ func main() {
 			}
 		}
 
-		if redisQueue == nil {
-			redisQueue, rErr = queue.NewRedisQueue(cfg.RedisConfig)
-			if rErr != nil {
-				logger.Warn("Failed to connect to Redis, retrying...", "error", rErr, "attempt", i+1)
+		if jobQueue == nil {
+			if cfg.QueueBackend == config.QueueBackendPostgres {
+				// The queue tables come with the migrations, which run once the connection is up
+				var pgQueue *queue.PostgresQueue
+				pgQueue, rErr = queue.NewPostgresQueue(db.BuildPostgresConnString(cfg.PostgresConfig), cfg.RedisConfig)
+				if rErr != nil {
+					logger.Warn("Failed to connect to the PostgreSQL queue, retrying...", "error", rErr, "attempt", i+1)
+				} else {
+					jobQueue = pgQueue
+				}
+			} else {
+				var redisQueue *queue.RedisQueue
+				redisQueue, rErr = queue.NewRedisQueue(cfg.RedisConfig)
+				if rErr != nil {
+					logger.Warn("Failed to connect to Redis, retrying...", "error", rErr, "attempt", i+1)
+				} else {
+					jobQueue = redisQueue
+				}
 			}
 		}
 
@@ -234,13 +252,54 @@ func main() {
 	}
 
 	// Initialize queue
-	// redisQueue already initialized in retry loop
+	// jobQueue already initialized in retry loop
+
+	// Get Redis client for leader election (nil with the Postgres queue backend)
+	var redisClient *redis.Client
+	if redisQueue, ok := jobQueue.(*queue.RedisQueue); ok {
+		redisClient = redisQueue.GetClient()
+	}
 
-	// Get Redis client for leader election
-	redisClient := redisQueue.GetClient()
+	// Every request to Google, from the workers and the API alike, goes through one global budget
+	if cfg.RateLimitConfig.Enabled {
+		rl := cfg.RateLimitConfig
+		rlConfig := rl.Limits()
+		if redisClient != nil {
+			ratelimit.SetDefault(ratelimit.NewRedis(redisClient, rlConfig, rl.KeyPrefix))
+		} else {
+			// Without Redis every instance gets its own budget
+			ratelimit.SetDefault(ratelimit.NewLocal(rlConfig))
+		}
+		logger.Info("Google request rate limiting enabled",
+			"offers_per_min", rl.OffersPerMinute,
+			"price_graph_per_min", rl.PriceGraphPerMinute,
+			"burst", rl.Burst)
+	}
 
 	// Initialize worker manager with Redis client for distributed leader election
-	workerManager := worker.NewManager(redisQueue, redisClient, postgresDB, neo4jDB, cfg.WorkerConfig, cfg.FlightConfig, cfg.DealConfig)
+	workerManager := worker.NewManager(jobQueue, redisClient, postgresDB, neo4jDB, cfg.WorkerConfig, cfg.FlightConfig, cfg.DealConfig)
+	if pgQueue, ok := jobQueue.(*queue.PostgresQueue); ok {
+		// The scheduler leader lock lives in Postgres too
+		workerManager.SetLeaderLock(pgQueue)
+	}
+
+	// Stored prices are normalized to the reporting currency with daily rates snapshotted in Postgres
+	var fxProviders []currency.Provider
+	if cfg.CurrencyConfig.RatesFile != "" {
+		fileProvider, err := currency.NewFileProvider(cfg.CurrencyConfig.RatesFile)
+		if err != nil {
+			logger.Warn("Failed to load FX rates file", "error", err)
+		} else {
+			fxProviders = append(fxProviders, fileProvider)
+		}
+	}
+	if cfg.CurrencyConfig.RatesURL != "" {
+		fxProviders = append(fxProviders, currency.NewHTTPProvider(cfg.CurrencyConfig.RatesURL))
+	}
+	workerManager.SetCurrencyConverter(currency.NewConverter(cfg.CurrencyConfig.ReportingCurrency, postgresDB, fxProviders...))
+	logger.Info("Currency normalization configured",
+		"reporting_currency", cfg.CurrencyConfig.ReportingCurrency,
+		"rate_providers", len(fxProviders))
 
 	// Start worker pool if enabled
 	if cfg.WorkerEnabled {
@@ -264,7 +323,7 @@ func main() {
 		}
 
 		// Register all API routes from the api package
-		api.RegisterRoutes(router, postgresDB, neo4jDB, redisQueue, workerManager, cfg, hotelSession)
+		api.RegisterRoutes(router, postgresDB, neo4jDB, jobQueue, workerManager, cfg, hotelSession)
 
 		addr := ":" + cfg.Port
 		if cfg.HTTPBindAddr != "" {
@@ -0,0 +1,47 @@
+// Package apitypes holds the JSON request and response bodies of the REST API. The handlers of
+// the api package build these types and the Go client in pkg/client decodes them, so both sides
+// share one definition of the contract in docs/API_CONTRACT.md. The package has no dependencies
+// besides the standard library, so clients can import it without pulling in the server.
+package apitypes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// DateOnly parses YYYY-MM-DD or RFC3339 timestamps into a date (time at midnight UTC)
+type DateOnly struct {
+	time.Time
+}
+
+// UnmarshalJSON accepts either YYYY-MM-DD or RFC3339 timestamps
+func (d *DateOnly) UnmarshalJSON(b []byte) error {
+	str := strings.Trim(string(b), "\"")
+	if str == "" || str == "null" {
+		d.Time = time.Time{}
+		return nil
+	}
+
+	layouts := []string{dateLayout, time.RFC3339}
+	var lastErr error
+	for _, layout := range layouts {
+		parsed, err := time.Parse(layout, str)
+		if err == nil {
+			d.Time = parsed
+			return nil
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("invalid date format %q: %w", str, lastErr)
+}
+
+// MarshalJSON renders dates as YYYY-MM-DD; zero values emit null
+func (d DateOnly) MarshalJSON() ([]byte, error) {
+	if d.Time.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(fmt.Sprintf("\"%s\"", d.Time.Format(dateLayout))), nil
+}
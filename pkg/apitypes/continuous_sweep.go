@@ -0,0 +1,166 @@
+package apitypes
+
+import "time"
+
+// SweepStatus is the state of the continuous sweep runner.
+type SweepStatus struct {
+	IsRunning           bool      `json:"is_running"`
+	IsPaused            bool      `json:"is_paused"`
+	SweepNumber         int       `json:"sweep_number"`
+	RouteIndex          int       `json:"route_index"`
+	TotalRoutes         int       `json:"total_routes"`
+	ProgressPercent     float64   `json:"progress_percent"`
+	CurrentOrigin       string    `json:"current_origin"`
+	CurrentDestination  string    `json:"current_destination"`
+	Class               string    `json:"class"`
+	Stops               string    `json:"stops"`
+	TripLengths         []int     `json:"trip_lengths"`
+	Origins             []string  `json:"origins,omitempty"`
+	QueriesCompleted    int       `json:"queries_completed"`
+	ErrorsCount         int       `json:"errors_count"`
+	LastError           string    `json:"last_error,omitempty"`
+	SweepStartedAt      time.Time `json:"sweep_started_at,omitempty"`
+	EstimatedCompletion time.Time `json:"estimated_completion,omitempty"`
+	PacingMode          string    `json:"pacing_mode"`
+	CurrentDelayMs      int       `json:"current_delay_ms"`
+	MinDelayMs          int       `json:"min_delay_ms"`
+	TargetDurationHours int       `json:"target_duration_hours"`
+	QueriesPerHour      float64   `json:"queries_per_hour"`
+}
+
+// ContinuousSweepProgress is the progress of the continuous sweep saved in Postgres, which
+// survives restarts and is shared by all processes.
+type ContinuousSweepProgress struct {
+	SweepNumber         int        `json:"sweep_number"`
+	RouteIndex          int        `json:"route_index"`
+	TotalRoutes         int        `json:"total_routes"`
+	CurrentOrigin       string     `json:"current_origin,omitempty"`
+	CurrentDestination  string     `json:"current_destination,omitempty"`
+	QueriesCompleted    int        `json:"queries_completed"`
+	ErrorsCount         int        `json:"errors_count"`
+	LastError           string     `json:"last_error,omitempty"`
+	SweepStartedAt      *time.Time `json:"sweep_started_at,omitempty"`
+	LastUpdated         time.Time  `json:"last_updated"`
+	TripLengths         []int      `json:"trip_lengths"`
+	PacingMode          string     `json:"pacing_mode"`
+	TargetDurationHours int        `json:"target_duration_hours"`
+	MinDelayMs          int        `json:"min_delay_ms"`
+	IsRunning           bool       `json:"is_running"`
+	IsPaused            bool       `json:"is_paused"`
+	InternationalOnly   bool       `json:"international_only"`
+	Origins             []string   `json:"origins,omitempty"`
+}
+
+// ContinuousSweepControl are the running and paused flags of the continuous sweep kept in the
+// queue backend, so the sweep can be controlled without Postgres.
+type ContinuousSweepControl struct {
+	IsRunning   bool      `json:"is_running"`
+	IsPaused    bool      `json:"is_paused"`
+	LastUpdated time.Time `json:"last_updated"`
+	Source      string    `json:"source"`
+}
+
+// ContinuousSweepStatusResponse is the response of GET /api/v1/admin/continuous-sweep/status.
+type ContinuousSweepStatusResponse struct {
+	Initialized  bool                                 `json:"initialized"`
+	Message      string                               `json:"message,omitempty"`
+	ServerTime   string                               `json:"server_time"`
+	Status       *SweepStatus                         `json:"status,omitempty"`
+	DB           *ContinuousSweepProgress             `json:"db,omitempty"`
+	DBError      string                               `json:"db_error,omitempty"`
+	Control      *ContinuousSweepControl              `json:"control,omitempty"`
+	ControlError string                               `json:"control_error,omitempty"`
+	Queues       map[string]ContinuousSweepQueueStats `json:"queues,omitempty"`
+}
+
+// ContinuousSweepQueueStats are the counters of a queue of the continuous sweep, or the error
+// which prevented reading them.
+type ContinuousSweepQueueStats struct {
+	Pending    int64  `json:"pending"`
+	Processing int64  `json:"processing"`
+	Completed  int64  `json:"completed"`
+	Failed     int64  `json:"failed"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ContinuousSweepActionResponse is the response of the continuous sweep controls (start, stop,
+// pause, resume, config, skip and restart). Status is null if the runner isn't initialized in
+// the API process; Persist and Drain are only set by some of the controls.
+type ContinuousSweepActionResponse struct {
+	Message        string                       `json:"message"`
+	Status         *SweepStatus                 `json:"status"`
+	Persist        *ContinuousSweepPersistError `json:"persist,omitempty"`
+	Drain          *ContinuousSweepDrainResult  `json:"drain,omitempty"`
+	NearbyAirports []NearbyAirport              `json:"nearby_airports,omitempty"`
+}
+
+// ContinuousSweepPersistError lists the stores which failed to save the new control flags.
+type ContinuousSweepPersistError struct {
+	Errors []string `json:"errors"`
+}
+
+// ContinuousSweepDrainResult tells how much of the sweep queue was dropped when the sweep was
+// stopped.
+type ContinuousSweepDrainResult struct {
+	CanceledProcessing *int64 `json:"canceled_processing,omitempty"`
+	ClearedPending     *int64 `json:"cleared_pending,omitempty"`
+	Error              string `json:"error,omitempty"`
+}
+
+// ContinuousSweepStatsResponse is the JSON-friendly response for sweep stats
+type ContinuousSweepStatsResponse struct {
+	ID                   int        `json:"id"`
+	SweepNumber          int        `json:"sweep_number"`
+	StartedAt            time.Time  `json:"started_at"`
+	CompletedAt          *time.Time `json:"completed_at,omitempty"`
+	TotalRoutes          int        `json:"total_routes"`
+	SuccessfulQueries    int        `json:"successful_queries"`
+	FailedQueries        int        `json:"failed_queries"`
+	TotalDurationSeconds *int       `json:"total_duration_seconds,omitempty"`
+	AvgDelayMs           *int       `json:"avg_delay_ms,omitempty"`
+	MinPriceFound        *float64   `json:"min_price_found,omitempty"`
+	MaxPriceFound        *float64   `json:"max_price_found,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+// ContinuousSweepStatsListResponse is the response of GET /api/v1/admin/continuous-sweep/stats.
+type ContinuousSweepStatsListResponse struct {
+	Stats []ContinuousSweepStatsResponse `json:"stats"`
+	Count int                            `json:"count"`
+}
+
+// ContinuousSweepResultResponse is the JSON-friendly response for sweep results
+type ContinuousSweepResultResponse struct {
+	Origin        string     `json:"origin"`
+	Destination   string     `json:"destination"`
+	DepartureDate time.Time  `json:"departure_date"`
+	ReturnDate    *time.Time `json:"return_date,omitempty"`
+	TripLength    *int       `json:"trip_length,omitempty"`
+	Price         float64    `json:"price"`
+	Currency      string     `json:"currency"`
+	Adults        int        `json:"adults"`
+	Children      int        `json:"children"`
+	InfantsLap    int        `json:"infants_lap"`
+	InfantsSeat   int        `json:"infants_seat"`
+	TripType      string     `json:"trip_type"`
+	Class         string     `json:"class"`
+	Stops         string     `json:"stops"`
+	SearchURL     *string    `json:"search_url,omitempty"`
+	QueriedAt     time.Time  `json:"queried_at"`
+}
+
+// ContinuousSweepResultFilters are the filters applied by GET
+// /api/v1/admin/continuous-sweep/results.
+type ContinuousSweepResultFilters struct {
+	Origin      string    `json:"origin"`
+	Destination string    `json:"destination"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+}
+
+// ContinuousSweepResultsResponse is the response of GET /api/v1/admin/continuous-sweep/results.
+type ContinuousSweepResultsResponse struct {
+	Results []ContinuousSweepResultResponse `json:"results"`
+	Count   int                             `json:"count"`
+	Filters ContinuousSweepResultFilters    `json:"filters"`
+}
@@ -0,0 +1,239 @@
+package apitypes
+
+// DirectSearchResponse is the response of POST /api/search. A search of a single route has
+// Offers; a search whose airports expand to several routes has Routes instead, together with
+// the cheapest offer over all routes. Multi-city searches have Offers and GoogleFlightsURL.
+type DirectSearchResponse struct {
+	Offers           []DirectSearchOffer       `json:"offers,omitempty"`
+	Routes           []DirectSearchRoute       `json:"routes,omitempty"`
+	SearchParams     DirectSearchParams        `json:"search_params"`
+	PriceGraphOnly   bool                      `json:"price_graph_only,omitempty"`
+	GoogleFlightsURL string                    `json:"google_flights_url,omitempty"`
+	Filter           *AirlineGroupFilter       `json:"filter,omitempty"`
+	PriceRange       *PriceRange               `json:"price_range,omitempty"`
+	PriceGraph       *PriceGraph               `json:"price_graph,omitempty"`
+	Cheapest         *DirectSearchCheapest     `json:"cheapest,omitempty"`
+	Warnings         []string                  `json:"warnings,omitempty"`
+	NearbyAirports   []NearbyAirport           `json:"nearby_airports,omitempty"`
+	BatchSummary     *DirectSearchBatchSummary `json:"batch_summary,omitempty"`
+	BatchDebug       []DirectSearchBatchDebug  `json:"batch_debug,omitempty"`
+}
+
+// DirectSearchParams are the parameters a direct search ran with, after the defaults were
+// applied. Searches of several routes add the expanded airports and the route counts.
+type DirectSearchParams struct {
+	DirectSearchRequest
+	*DirectSearchGrid
+}
+
+// DirectSearchGrid describes the routes of a direct search of several routes. OffersCount is
+// the number of offers of the batched searches of large grids.
+type DirectSearchGrid struct {
+	Origins             []string `json:"origins"`
+	Destinations        []string `json:"destinations"`
+	RequestedRouteCount int      `json:"requested_route_count"`
+	ReturnedRouteCount  int      `json:"returned_route_count"`
+	OffersCount         int      `json:"offers_count,omitempty"`
+	PriceGraphOnly      bool     `json:"price_graph_only,omitempty"`
+}
+
+// DirectSearchRoute is a route of a direct search of several routes. Offers is null when the
+// route wasn't searched: it failed (Error is set) or the search only asked for price graphs.
+type DirectSearchRoute struct {
+	Origin                   string                    `json:"origin"`
+	Destination              string                    `json:"destination"`
+	OriginAnchor             string                    `json:"origin_anchor,omitempty"`
+	OriginDistanceMiles      float64                   `json:"origin_distance_miles,omitempty"`
+	DestinationAnchor        string                    `json:"destination_anchor,omitempty"`
+	DestinationDistanceMiles float64                   `json:"destination_distance_miles,omitempty"`
+	Error                    string                    `json:"error,omitempty"`
+	Warning                  string                    `json:"warning,omitempty"`
+	Offers                   []DirectSearchOffer       `json:"offers"`
+	Filter                   *AirlineGroupFilter       `json:"filter,omitempty"`
+	SearchParams             *DirectSearchRequest      `json:"search_params,omitempty"`
+	PriceRange               *PriceRange               `json:"price_range,omitempty"`
+	GooglePriceGraph         *PriceGraph               `json:"google_price_graph,omitempty"`
+	Summary                  *DirectSearchRouteSummary `json:"summary,omitempty"`
+}
+
+// DirectSearchRouteSummary is the cheapest price graph point of a price-graph-only route.
+type DirectSearchRouteSummary struct {
+	Currency      string  `json:"currency"`
+	Price         float64 `json:"price"`
+	DepartureDate string  `json:"departure_date"`
+	ReturnDate    string  `json:"return_date"`
+}
+
+// DirectSearchCheapest is the cheapest offer of a direct search of several routes.
+type DirectSearchCheapest struct {
+	Origin      string            `json:"origin"`
+	Destination string            `json:"destination"`
+	Offer       DirectSearchOffer `json:"offer"`
+}
+
+// DirectSearchOffer is an offer of a direct search. Durations are in minutes. The return legs
+// are set for the cheapest round-trip offers only; MultiCitySegments and Complete are set for
+// multi-city offers, whose Segments are the flights of the first segment.
+type DirectSearchOffer struct {
+	ID                string             `json:"id"`
+	Price             float64            `json:"price"`
+	PriceAvailable    bool               `json:"price_available"`
+	Currency          string             `json:"currency"`
+	TotalDuration     int                `json:"total_duration"`
+	DepartureDate     string             `json:"departure_date"`
+	ReturnDate        string             `json:"return_date,omitempty"`
+	Segments          []FlightSegment    `json:"segments"`
+	Layovers          []Layover          `json:"layovers,omitempty"`
+	ReturnSegments    []FlightSegment    `json:"return_segments,omitempty"`
+	ReturnDuration    int                `json:"return_duration,omitempty"`
+	ReturnLayovers    []Layover          `json:"return_layovers,omitempty"`
+	GoogleFlightsURL  string             `json:"google_flights_url,omitempty"`
+	AirlineGroups     []string           `json:"airline_groups,omitempty"`
+	AirlineCodes      []string           `json:"airline_codes,omitempty"`
+	Fare              *Fare              `json:"fare,omitempty"`
+	MultiCitySegments []MultiCitySegment `json:"multi_city_segments,omitempty"`
+	Complete          *bool              `json:"complete,omitempty"`
+}
+
+// FlightSegment is a flight of an offer. Times are RFC 3339 timestamps and Duration is in
+// minutes.
+type FlightSegment struct {
+	DepartureAirport  string          `json:"departure_airport"`
+	ArrivalAirport    string          `json:"arrival_airport"`
+	DepartureTime     string          `json:"departure_time"`
+	ArrivalTime       string          `json:"arrival_time"`
+	Airline           string          `json:"airline"`
+	AirlineCode       string          `json:"airline_code"`
+	FlightNumber      string          `json:"flight_number"`
+	Duration          int             `json:"duration"`
+	Airplane          string          `json:"airplane"`
+	Legroom           string          `json:"legroom"`
+	Amenities         FlightAmenities `json:"amenities"`
+	Overnight         bool            `json:"overnight"`
+	OftenDelayed      bool            `json:"often_delayed"`
+	OperatingCarrier  string          `json:"operating_carrier,omitempty"`
+	Codeshares        []string        `json:"codeshares,omitempty"`
+	CO2EmissionsGrams int             `json:"co2_emissions_grams,omitempty"`
+	SeatPitchInches   int             `json:"seat_pitch_inches,omitempty"`
+}
+
+// FlightAmenities are the on-board amenities of a flight.
+type FlightAmenities struct {
+	WiFi          bool `json:"wifi"`
+	Power         bool `json:"power"`
+	Entertainment bool `json:"entertainment"`
+}
+
+// Layover is a connection between two flights; Duration is in minutes.
+type Layover struct {
+	ArrivalAirport   string `json:"arrival_airport"`
+	DepartureAirport string `json:"departure_airport"`
+	City             string `json:"city"`
+	Duration         int    `json:"duration"`
+	ChangeOfAirport  bool   `json:"change_of_airport"`
+	Overnight        bool   `json:"overnight"`
+	ShortConnection  bool   `json:"short_connection"`
+}
+
+// Fare describes the fare of an offer. The bags are only set when Google tells which bags are
+// included in the price. BagsUnverified marks offers kept for requested bags which Google didn't
+// confirm.
+type Fare struct {
+	BasicEconomy   bool `json:"basic_economy"`
+	SeatSelection  bool `json:"seat_selection"`
+	CarryOnBags    *int `json:"carry_on_bags,omitempty"`
+	CheckedBags    *int `json:"checked_bags,omitempty"`
+	BagsUnverified bool `json:"bags_unverified,omitempty"`
+}
+
+// MultiCitySegment is a segment of a multi-city offer. Flights is empty until the flights of
+// the segment were chosen.
+type MultiCitySegment struct {
+	Origin        string          `json:"origin"`
+	Destination   string          `json:"destination"`
+	DepartureDate string          `json:"departure_date"`
+	Flights       []FlightSegment `json:"flights,omitempty"`
+}
+
+// PriceRange is Google's range of typical prices for a route.
+type PriceRange struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// PriceGraph is Google's calendar of the cheapest prices of a route over a range of departure
+// dates. Error is set instead of Points when the graph couldn't be loaded.
+type PriceGraph struct {
+	Origin         string                `json:"origin"`
+	Destination    string                `json:"destination"`
+	Currency       string                `json:"currency"`
+	RangeStartDate string                `json:"range_start_date,omitempty"`
+	RangeEndDate   string                `json:"range_end_date,omitempty"`
+	TripLengthDays int                   `json:"trip_length_days,omitempty"`
+	Error          string                `json:"error,omitempty"`
+	ParseErrors    *PriceGraphParseStats `json:"parse_errors,omitempty"`
+	Points         []PriceGraphPoint     `json:"points"`
+	TopPoints      []PriceGraphPoint     `json:"top_points,omitempty"`
+	FilterWarning  string                `json:"filter_warning,omitempty"`
+	FilterNote     *PriceGraphFilterNote `json:"filter_note,omitempty"`
+	Filter         *AirlineGroupFilter   `json:"filter,omitempty"`
+}
+
+// PriceGraphPoint is a price of the graph.
+type PriceGraphPoint struct {
+	DepartureDate    string  `json:"departure_date"`
+	ReturnDate       string  `json:"return_date"`
+	Price            float64 `json:"price"`
+	GoogleFlightsURL string  `json:"google_flights_url,omitempty"`
+}
+
+// PriceGraphFilterNote lists the carriers Google filtered the graph by.
+type PriceGraphFilterNote struct {
+	GoogleCarriers []string `json:"google_carriers"`
+}
+
+// PriceGraphParseStats counts the entries of Google's response which couldn't be read. Samples
+// are redacted diagnostics, never raw payloads.
+type PriceGraphParseStats struct {
+	UnmarshalFailures int      `json:"unmarshal_failures"`
+	DateParseFailures int      `json:"date_parse_failures"`
+	ZeroPriceCount    int      `json:"zero_price_count"`
+	TotalOffersRaw    int      `json:"total_offers_raw"`
+	EmptySections     int      `json:"empty_sections"`
+	Samples           []string `json:"samples,omitempty"`
+}
+
+// DirectSearchBatchSummary describes the batched searches of a large grid of routes.
+type DirectSearchBatchSummary struct {
+	Mode         string `json:"mode"`
+	ChunkSize    int    `json:"chunk_size"`
+	TotalBatches int    `json:"total_batches"`
+	Failed       int    `json:"failed_batches"`
+	Empty        int    `json:"empty_batches"`
+	TotalOffers  int    `json:"total_offers"`
+	DurationMs   int64  `json:"duration_ms"`
+}
+
+// DirectSearchBatchDebug describes a batched search; it is returned with debug_batches.
+type DirectSearchBatchDebug struct {
+	Mode                string         `json:"mode"`
+	Origin              string         `json:"origin,omitempty"`
+	Destination         string         `json:"destination,omitempty"`
+	Origins             []string       `json:"origins,omitempty"`
+	Destinations        []string       `json:"destinations,omitempty"`
+	Offers              int            `json:"offers"`
+	DurationMs          int64          `json:"duration_ms"`
+	Error               string         `json:"error,omitempty"`
+	DestinationOfferMap map[string]int `json:"destination_offer_map,omitempty"`
+	OriginOfferMap      map[string]int `json:"origin_offer_map,omitempty"`
+	MissingDestinations []string       `json:"missing_destinations,omitempty"`
+	MissingOrigins      []string       `json:"missing_origins,omitempty"`
+}
+
+// DirectSearchErrorResponse is the error response of a batched direct search whose offers
+// couldn't be grouped into routes.
+type DirectSearchErrorResponse struct {
+	ErrorResponse
+	BatchSummary *DirectSearchBatchSummary `json:"batch_summary,omitempty"`
+	BatchDebug   []DirectSearchBatchDebug  `json:"batch_debug,omitempty"`
+}
@@ -0,0 +1,162 @@
+package apitypes
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// QueueJob is a job of the queue as shown by the queue admin endpoints.
+type QueueJob struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	Status      string          `json:"status"`
+	StreamID    string          `json:"stream_id,omitempty"`
+	Priority    string          `json:"priority,omitempty"`
+	Tenant      string          `json:"tenant,omitempty"`
+	RunAt       *time.Time      `json:"run_at,omitempty"`
+	// Failures is the timeline of the failed attempts, oldest first.
+	Failures       []QueueJobFailure `json:"failures,omitempty"`
+	EnqueueMeta    *EnqueueMeta      `json:"enqueue_meta,omitempty"`
+	IdempotencyKey string            `json:"idempotency_key,omitempty"`
+}
+
+// QueueJobFailure is a failed attempt of a job.
+type QueueJobFailure struct {
+	Attempt    int       `json:"attempt"`
+	Error      string    `json:"error,omitempty"`
+	ErrorClass string    `json:"error_class,omitempty"`
+	Stack      string    `json:"stack,omitempty"`
+	WorkerID   string    `json:"worker_id,omitempty"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+// EnqueueMeta tells who enqueued a job.
+type EnqueueMeta struct {
+	Actor     string `json:"actor,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Path      string `json:"path,omitempty"`
+	RemoteIP  string `json:"remote_ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// DeadLetter is a job that failed its last attempt, together with the failure that ended it.
+type DeadLetter struct {
+	EntryID         string     `json:"entry_id,omitempty"`
+	Queue           string     `json:"queue"`
+	Job             *QueueJob  `json:"job"`
+	Error           string     `json:"error,omitempty"`
+	ErrorClass      string     `json:"error_class,omitempty"`
+	WorkerID        string     `json:"worker_id,omitempty"`
+	DeadAt          time.Time  `json:"dead_at"`
+	PayloadEditedAt *time.Time `json:"payload_edited_at,omitempty"`
+}
+
+// QueueBacklogResponse is the response of GET /api/v1/admin/queue/:name/backlog.
+type QueueBacklogResponse struct {
+	Queue string      `json:"queue"`
+	Limit int         `json:"limit"`
+	Jobs  []*QueueJob `json:"jobs"`
+}
+
+// QueueJobListResponse is the response of GET /api/v1/admin/queue/:name/jobs.
+type QueueJobListResponse struct {
+	Queue  string      `json:"queue"`
+	State  string      `json:"state"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+	Count  int         `json:"count"`
+	Jobs   []*QueueJob `json:"jobs"`
+}
+
+// QueueJobResponse is the response of GET /api/v1/admin/queue/:name/jobs/:id.
+type QueueJobResponse struct {
+	Queue string    `json:"queue"`
+	Job   *QueueJob `json:"job"`
+}
+
+// QueueCancelJobResponse is the response of POST /api/v1/admin/queue/:name/jobs/:id/cancel.
+type QueueCancelJobResponse struct {
+	Queue   string `json:"queue"`
+	JobID   string `json:"job_id"`
+	Message string `json:"message"`
+}
+
+// QueueActionResponse is the response of the admin actions on a queue (drain, clear,
+// clear-failed, clear-processing, cancel-processing and retry-failed). Only the counters of
+// the action are set.
+type QueueActionResponse struct {
+	Queue    string           `json:"queue"`
+	Canceled *int64           `json:"canceled,omitempty"`
+	Cleared  *int64           `json:"cleared,omitempty"`
+	Retried  *int64           `json:"retried,omitempty"`
+	Limit    int              `json:"limit,omitempty"`
+	Stats    map[string]int64 `json:"stats"`
+}
+
+// QueueDeadLetterListResponse is the response of GET /api/v1/admin/queue/:name/dead-letters.
+type QueueDeadLetterListResponse struct {
+	Queue       string        `json:"queue"`
+	ErrorClass  string        `json:"error_class,omitempty"`
+	Limit       int           `json:"limit"`
+	Offset      int           `json:"offset"`
+	Count       int           `json:"count"`
+	DeadLetters []*DeadLetter `json:"dead_letters"`
+}
+
+// QueueDeadLetterResponse is the response of GET /api/v1/admin/queue/:name/dead-letters/:id and
+// of PUT /api/v1/admin/queue/:name/dead-letters/:id/payload.
+type QueueDeadLetterResponse struct {
+	Queue      string      `json:"queue"`
+	DeadLetter *DeadLetter `json:"dead_letter"`
+}
+
+// QueueDeadLetterReplayResponse is the response of POST /api/v1/admin/queue/:name/dead-letters/replay.
+type QueueDeadLetterReplayResponse struct {
+	Queue    string           `json:"queue"`
+	Replayed []string         `json:"replayed"`
+	Count    int              `json:"count"`
+	Stats    map[string]int64 `json:"stats"`
+}
+
+// QueueEnqueueMetricsResponse is the response of GET /api/v1/admin/queue/:name/enqueues.
+type QueueEnqueueMetricsResponse struct {
+	Queue   string           `json:"queue"`
+	Minutes int              `json:"minutes"`
+	Sources map[string]int64 `json:"sources"`
+}
+
+// RateLimitStatsResponse is the response of GET /api/v1/admin/rate-limits.
+type RateLimitStatsResponse struct {
+	Enabled bool                          `json:"enabled"`
+	Kinds   map[string]RateLimitKindStats `json:"kinds"`
+}
+
+// RateLimitKindStats are the counters of the outbound rate limiter for a kind of request.
+type RateLimitKindStats struct {
+	Requests    int64 `json:"requests"`
+	Delayed     int64 `json:"delayed"`
+	Fallbacks   int64 `json:"fallbacks"`
+	TotalWaitMs int64 `json:"total_wait_ms"`
+	AvgWaitMs   int64 `json:"avg_wait_ms"`
+	MaxWaitMs   int64 `json:"max_wait_ms"`
+}
+
+// WorkerStatusResponse is a worker of GET /api/v1/admin/workers. ID is the number of a worker
+// of the API process and the instance ID of a remote worker.
+type WorkerStatusResponse struct {
+	ID                  any    `json:"id"`
+	Status              string `json:"status"`
+	CurrentJob          string `json:"current_job,omitempty"`
+	ProcessedJobs       int    `json:"processed_jobs"`
+	Uptime              int64  `json:"uptime"`
+	Source              string `json:"source,omitempty"`
+	Hostname            string `json:"hostname,omitempty"`
+	Concurrency         int    `json:"concurrency,omitempty"`
+	HeartbeatAgeSeconds int64  `json:"heartbeat_age_seconds,omitempty"`
+	Version             string `json:"version,omitempty"`
+}
@@ -0,0 +1,308 @@
+package apitypes
+
+import (
+	"encoding/json"
+)
+
+// SearchRequest represents a flight search request
+type SearchRequest struct {
+	Origin        string   `json:"origin" binding:"required_unless=TripType multi_city"`
+	Destination   string   `json:"destination" binding:"required_unless=TripType multi_city"`
+	DepartureDate DateOnly `json:"departure_date" binding:"required"`
+	ReturnDate    DateOnly `json:"return_date,omitempty"`
+	Adults        int      `json:"adults" binding:"required,min=1"`
+	Children      int      `json:"children" binding:"min=0"`
+	InfantsLap    int      `json:"infants_lap" binding:"min=0"`
+	InfantsSeat   int      `json:"infants_seat" binding:"min=0"`
+	TripType      string   `json:"trip_type" binding:"required,oneof=one_way round_trip multi_city"`
+	Class         string   `json:"class" binding:"required,oneof=economy premium_economy business first"`
+	Stops         string   `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"` // Added two_stops_plus
+	Currency      string   `json:"currency" binding:"required,len=3"`
+
+	// Segments of a multi_city search (2-6); origin, destination and departure_date are taken
+	// from the first and last segment.
+	Segments []SearchSegmentRequest `json:"segments,omitempty"`
+}
+
+// SearchSegmentRequest is one flight of a multi-city search.
+type SearchSegmentRequest struct {
+	Origin        string   `json:"origin"`
+	Destination   string   `json:"destination"`
+	DepartureDate DateOnly `json:"departure_date"`
+}
+
+// BulkSearchRequest represents a bulk flight search request
+type BulkSearchRequest struct {
+	Origins           []string `json:"origins" binding:"required_unless=TripType multi_city,omitempty,min=1"`
+	Destinations      []string `json:"destinations" binding:"required_unless=TripType multi_city,omitempty,min=1"`
+	DepartureDateFrom DateOnly `json:"departure_date_from" binding:"required"`
+	DepartureDateTo   DateOnly `json:"departure_date_to" binding:"required"`
+	ReturnDateFrom    DateOnly `json:"return_date_from,omitempty"`
+	ReturnDateTo      DateOnly `json:"return_date_to,omitempty"`
+	TripLength        int      `json:"trip_length,omitempty" binding:"min=0"`
+	Adults            int      `json:"adults" binding:"required,min=1"`
+	Children          int      `json:"children" binding:"min=0"`
+	InfantsLap        int      `json:"infants_lap" binding:"min=0"`
+	InfantsSeat       int      `json:"infants_seat" binding:"min=0"`
+	TripType          string   `json:"trip_type" binding:"required,oneof=one_way round_trip multi_city"`
+	Class             string   `json:"class" binding:"required,oneof=economy premium_economy business first"`
+	Stops             string   `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"`
+	Currency          string   `json:"currency" binding:"required,len=3"`
+	Carriers          []string `json:"carriers,omitempty"`
+	MinLayoverMinutes int      `json:"min_layover_minutes,omitempty" binding:"min=0"`
+	MaxLayoverMinutes int      `json:"max_layover_minutes,omitempty" binding:"min=0"`
+	// Fare options; bag counts request a bag-inclusive price by removing fares without these bags
+	ExcludeBasicEconomy bool `json:"exclude_basic_economy,omitempty"`
+	CarryOnBags         int  `json:"carry_on_bags,omitempty" binding:"min=0"`
+	CheckedBags         int  `json:"checked_bags,omitempty" binding:"min=0"`
+	// Market options; the prices are the ones shown to users of this country and Google domain
+	Country     string `json:"gl,omitempty"`
+	GoogleHost  string `json:"google_host,omitempty"`
+	TZOffsetMin *int   `json:"tz_offset_min,omitempty"`
+	// Segments of a multi_city bulk search (2-6) instead of origins and destinations. The
+	// departure date range moves the whole itinerary: every date searches all segments shifted
+	// by its offset from the first segment.
+	Segments []SearchSegmentRequest `json:"segments,omitempty"`
+}
+
+// JobRequest represents a scheduled job request
+type JobRequest struct {
+	Name              string `json:"name" binding:"required"`
+	Origin            string `json:"origin" binding:"required"`
+	Destination       string `json:"destination" binding:"required"`
+	DateStart         string `json:"date_start" binding:"required"`
+	DateEnd           string `json:"date_end" binding:"required"`
+	ReturnDateStart   string `json:"return_date_start,omitempty"`
+	ReturnDateEnd     string `json:"return_date_end,omitempty"`
+	TripLength        int    `json:"trip_length,omitempty" binding:"min=0"`
+	DynamicDates      bool   `json:"dynamic_dates,omitempty"`       // Use dates relative to execution time
+	DaysFromExecution int    `json:"days_from_execution,omitempty"` // Start searching X days from now
+	SearchWindowDays  int    `json:"search_window_days,omitempty"`  // Search within X days window
+	Adults            int    `json:"adults" binding:"required,min=1"`
+	Children          int    `json:"children" binding:"min=0"`
+	InfantsLap        int    `json:"infants_lap" binding:"min=0"`
+	InfantsSeat       int    `json:"infants_seat" binding:"min=0"`
+	TripType          string `json:"trip_type" binding:"required,oneof=one_way round_trip"`
+	Class             string `json:"class" binding:"required,oneof=economy premium_economy business first"`
+	Stops             string `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"`
+	Currency          string `json:"currency" binding:"required,len=3"`
+	Interval          string `json:"interval" binding:"required"`
+	Time              string `json:"time" binding:"required"`
+	CronExpression    string `json:"cron_expression" binding:"required"`
+}
+
+// BulkJobRequest represents a request to create a scheduled bulk search job
+type BulkJobRequest struct {
+	Name              string   `json:"name" binding:"required"`
+	Origins           []string `json:"origins" binding:"required,min=1"`
+	Destinations      []string `json:"destinations" binding:"required,min=1"`
+	DateStart         string   `json:"date_start" binding:"required"`
+	DateEnd           string   `json:"date_end" binding:"required"`
+	ReturnDateStart   string   `json:"return_date_start,omitempty"`
+	ReturnDateEnd     string   `json:"return_date_end,omitempty"`
+	TripLength        int      `json:"trip_length,omitempty" binding:"min=0"`
+	DynamicDates      bool     `json:"dynamic_dates,omitempty"`       // Use dates relative to execution time
+	DaysFromExecution int      `json:"days_from_execution,omitempty"` // Start searching X days from now
+	SearchWindowDays  int      `json:"search_window_days,omitempty"`  // Search within X days window
+	Adults            int      `json:"adults" binding:"required,min=1"`
+	Children          int      `json:"children" binding:"min=0"`
+	InfantsLap        int      `json:"infants_lap" binding:"min=0"`
+	InfantsSeat       int      `json:"infants_seat" binding:"min=0"`
+	TripType          string   `json:"trip_type" binding:"required,oneof=one_way round_trip"`
+	Class             string   `json:"class" binding:"required,oneof=economy premium_economy business first"`
+	Stops             string   `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"`
+	Currency          string   `json:"currency" binding:"required,len=3"`
+	CronExpression    string   `json:"cron_expression" binding:"required"`
+}
+
+// PriceGraphSweepRequest represents a request to enqueue a price graph sweep
+type PriceGraphSweepRequest struct {
+	Origins           []string `json:"origins" binding:"required,min=1"`
+	Destinations      []string `json:"destinations" binding:"required,min=1"`
+	DepartureDateFrom DateOnly `json:"departure_date_from" binding:"required"`
+	DepartureDateTo   DateOnly `json:"departure_date_to" binding:"required"`
+	TripLengths       []int    `json:"trip_lengths,omitempty"`
+	TripType          string   `json:"trip_type" binding:"required,oneof=one_way round_trip"`
+	// ReturnOrigins makes a round-trip sweep open-jaw: the return flight departs from each of
+	// them. ReturnDestinations optionally replaces the origin as the end of the trip (two-segment
+	// multi-city).
+	ReturnOrigins      []string `json:"return_origins,omitempty"`
+	ReturnDestinations []string `json:"return_destinations,omitempty"`
+	// Class is kept for backward compatibility; prefer Classes for multi-cabin sweeps.
+	Class           string   `json:"class,omitempty"`
+	Classes         []string `json:"classes,omitempty"`
+	Stops           string   `json:"stops" binding:"required,oneof=nonstop one_stop two_stops two_stops_plus any"`
+	Adults          int      `json:"adults" binding:"required,min=1"`
+	Children        int      `json:"children" binding:"min=0"`
+	InfantsLap      int      `json:"infants_lap" binding:"min=0"`
+	InfantsSeat     int      `json:"infants_seat" binding:"min=0"`
+	Currency        string   `json:"currency" binding:"required,len=3"`
+	RateLimitMillis int      `json:"rate_limit_millis,omitempty" binding:"min=0"`
+}
+
+// SweepConfigRequest represents a request to update sweep configuration
+type SweepConfigRequest struct {
+	PacingMode          string `json:"pacing_mode,omitempty"`
+	TargetDurationHours int    `json:"target_duration_hours,omitempty"`
+	MinDelayMs          int    `json:"min_delay_ms,omitempty"`
+	Class               string `json:"class,omitempty"`
+	TripLengths         *[]int `json:"trip_lengths,omitempty"`
+	// Origins restricts the sweep to routes from these airports (codes, REGION:* or NEAR:*
+	// tokens) to the top 100 airports; an empty list sweeps every top 100 route again.
+	Origins *[]string `json:"origins,omitempty"`
+}
+
+// DirectSearchRequest represents a direct flight search request (non-queued)
+type DirectSearchRequest struct {
+	Origin        string `json:"origin" form:"origin"`
+	Destination   string `json:"destination" form:"destination"`
+	DepartureDate string `json:"departure_date" form:"departure_date"`
+	ReturnDate    string `json:"return_date" form:"return_date"`
+	TripType      string `json:"trip_type" form:"trip_type"`
+	Class         string `json:"class" form:"class"`
+	Stops         string `json:"stops" form:"stops"`
+	Adults        int    `json:"adults" form:"adults"`
+	Children      int    `json:"children" form:"children"`
+	InfantsLap    int    `json:"infants_lap" form:"infants_lap"`
+	InfantsSeat   int    `json:"infants_seat" form:"infants_seat"`
+	Currency      string `json:"currency" form:"currency"`
+
+	IncludePriceGraph           bool   `json:"include_price_graph" form:"include_price_graph"`
+	PriceGraphWindowDays        int    `json:"price_graph_window_days" form:"price_graph_window_days"`
+	PriceGraphDepartureDateFrom string `json:"price_graph_departure_date_from" form:"price_graph_departure_date_from"`
+	PriceGraphDepartureDateTo   string `json:"price_graph_departure_date_to" form:"price_graph_departure_date_to"`
+	PriceGraphTripLengthDays    int    `json:"price_graph_trip_length_days" form:"price_graph_trip_length_days"`
+	PriceGraphTopN              int    `json:"price_graph_top_n" form:"price_graph_top_n"`
+
+	// DebugBatches enables per-batch diagnostics in multi-route searches.
+	// Intended for troubleshooting missing origin→destination pairs.
+	DebugBatches bool `json:"debug_batches" form:"debug_batches"`
+
+	// Airline group filtering (best-effort).
+	// - If IncludeAirlineGroups is non-empty, offers must match at least one included group.
+	// - If ExcludeAirlineGroups is non-empty, offers matching any excluded group are removed.
+	//
+	// Group tokens are like: GROUP:LOW_COST, GROUP:STAR_ALLIANCE, GROUP:ONEWORLD, GROUP:SKYTEAM.
+	IncludeAirlineGroups []string `json:"include_airline_groups" form:"include_airline_groups"`
+	ExcludeAirlineGroups []string `json:"exclude_airline_groups" form:"exclude_airline_groups"`
+
+	// Carriers optionally restricts results at the Google query level (affects offers and calendar graph).
+	// Values: airline IATA 2-letter codes (e.g., "UA", "DL") and/or alliance strings
+	// ("STAR_ALLIANCE", "ONEWORLD", "SKYTEAM").
+	//
+	// NOTE: This is an inclusive filter. Exclusion semantics are not supported here; use group filters
+	// for post-fetch filtering.
+	Carriers []string `json:"carriers" form:"carriers"`
+
+	// Layover limits in minutes (0 means no limit). Offers with a connection outside of the limits
+	// are removed, e.g. 35-minute international connections or 11-hour overnights.
+	MinLayoverMinutes int `json:"min_layover_minutes" form:"min_layover_minutes"`
+	MaxLayoverMinutes int `json:"max_layover_minutes" form:"max_layover_minutes"`
+
+	// Fare options. ExcludeBasicEconomy removes basic economy fares; CarryOnBags and CheckedBags
+	// remove fares which don't include the given bags. Fares with unknown bags are kept.
+	ExcludeBasicEconomy bool `json:"exclude_basic_economy" form:"exclude_basic_economy"`
+	CarryOnBags         int  `json:"carry_on_bags" form:"carry_on_bags"`
+	CheckedBags         int  `json:"checked_bags" form:"checked_bags"`
+
+	// Market options. Country is the point of sale (e.g. "DE"), GoogleHost the Google domain
+	// (e.g. "www.google.de") and TZOffsetMin the user timezone as returned by JavaScript's
+	// getTimezoneOffset. Empty values search as a US user.
+	Country     string `json:"gl" form:"gl"`
+	GoogleHost  string `json:"google_host" form:"google_host"`
+	TZOffsetMin *int   `json:"tz_offset_min" form:"tz_offset_min"`
+
+	// Segments of a multi_city search (2-6, JSON only). Origin, Destination and the dates are
+	// ignored for multi-city searches.
+	Segments []SearchSegmentRequest `json:"segments,omitempty" form:"-"`
+}
+
+// FlexDateSearchRequest asks for the price of every departure/return date combination within
+// DepartureFlexDays and ReturnFlexDays of the given dates. Without a return date the grid is
+// a single row of one-way prices. Grids cover up to ±3 days around each date, i.e. at most 7x7
+// cells.
+type FlexDateSearchRequest struct {
+	Origin            string   `json:"origin" binding:"required,len=3"`
+	Destination       string   `json:"destination" binding:"required,len=3"`
+	DepartureDate     DateOnly `json:"departure_date" binding:"required"`
+	ReturnDate        DateOnly `json:"return_date,omitempty"`
+	DepartureFlexDays int      `json:"departure_flex_days" binding:"min=0,max=3"`
+	ReturnFlexDays    int      `json:"return_flex_days" binding:"min=0,max=3"`
+	Adults            int      `json:"adults" binding:"required,min=1"`
+	Children          int      `json:"children" binding:"min=0"`
+	InfantsLap        int      `json:"infants_lap" binding:"min=0"`
+	InfantsSeat       int      `json:"infants_seat" binding:"min=0"`
+	Class             string   `json:"class" binding:"required,oneof=economy premium_economy business first"`
+	Stops             string   `json:"stops" binding:"required,oneof=nonstop one_stop two_stops any"`
+	Currency          string   `json:"currency" binding:"required,len=3"`
+	// MaxOfferSearches caps the GetOffers calls (default 6, at most one per cell). Round trips
+	// take another call per search for the return flights, so it is kept low.
+	MaxOfferSearches int `json:"max_offer_searches" binding:"min=0,max=12"`
+}
+
+// ExploreAnywhereRequest asks for the cheapest destinations from an origin ("Anywhere" search).
+// Departures span Month (YYYY-MM) or DepartureDateFrom..DepartureDateTo, by default the next 30
+// days. A TripLength of 0 searches one-way fares.
+type ExploreAnywhereRequest struct {
+	Origin            string   `json:"origin" binding:"required,len=3"`
+	Month             string   `json:"month,omitempty"`
+	DepartureDateFrom DateOnly `json:"departure_date_from,omitempty"`
+	DepartureDateTo   DateOnly `json:"departure_date_to,omitempty"`
+	TripLength        int      `json:"trip_length" binding:"min=0,max=30"`
+	Adults            int      `json:"adults" binding:"min=0"`
+	Class             string   `json:"class" binding:"omitempty,oneof=economy premium_economy business first"`
+	Stops             string   `json:"stops" binding:"omitempty,oneof=nonstop one_stop two_stops any"`
+	Currency          string   `json:"currency" binding:"omitempty,len=3"`
+	MaxPrice          float64  `json:"max_price" binding:"min=0"`
+	Limit             int      `json:"limit" binding:"min=0,max=200"`
+	// Persist stores the destinations as price points of new or existing Neo4j routes, so they
+	// show up in the graph explore endpoints.
+	Persist bool `json:"persist"`
+}
+
+// HotelSearchRequest represents a hotel search request
+type HotelSearchRequest struct {
+	Location     string   `json:"location" binding:"required"`
+	CheckInDate  DateOnly `json:"checkin_date" binding:"required"`
+	CheckOutDate DateOnly `json:"checkout_date" binding:"required"`
+	Adults       int      `json:"adults" binding:"required,min=1"`
+	Children     int      `json:"children" binding:"min=0"`
+	Currency     string   `json:"currency" binding:"required,len=3"`
+}
+
+// PosComparisonRequest prices one itinerary in several points of sale
+type PosComparisonRequest struct {
+	Origin        string                       `json:"origin" binding:"required,len=3"`
+	Destination   string                       `json:"destination" binding:"required,len=3"`
+	DepartureDate DateOnly                     `json:"departure_date" binding:"required"`
+	ReturnDate    DateOnly                     `json:"return_date,omitempty"`
+	Adults        int                          `json:"adults" binding:"required,min=1"`
+	Children      int                          `json:"children" binding:"min=0"`
+	InfantsLap    int                          `json:"infants_lap" binding:"min=0"`
+	InfantsSeat   int                          `json:"infants_seat" binding:"min=0"`
+	TripType      string                       `json:"trip_type" binding:"required,oneof=one_way round_trip"`
+	Class         string                       `json:"class" binding:"required,oneof=economy premium_economy business first"`
+	Stops         string                       `json:"stops" binding:"required,oneof=nonstop one_stop two_stops any"`
+	Currency      string                       `json:"currency" binding:"required,len=3"` // reporting currency
+	Markets       []PosComparisonMarketRequest `json:"markets" binding:"required,min=2,max=20,dive"`
+}
+
+// PosComparisonMarketRequest is a point of sale of a [PosComparisonRequest]
+type PosComparisonMarketRequest struct {
+	Country    string `json:"gl" binding:"required,len=2"`
+	Currency   string `json:"currency,omitempty"` // currency charged in the market (defaults to the reporting currency)
+	GoogleHost string `json:"google_host,omitempty"`
+}
+
+// DeadLetterPayloadRequest is the body of PUT /api/v1/admin/queue/:name/dead-letters/:id/payload.
+type DeadLetterPayloadRequest struct {
+	Payload json.RawMessage `json:"payload" binding:"required"`
+}
+
+// DeadLetterReplayRequest is the body of POST /api/v1/admin/queue/:name/dead-letters/replay. It
+// replays the listed jobs, or else every dead letter of the error class, up to limit.
+type DeadLetterReplayRequest struct {
+	JobIDs     []string `json:"job_ids,omitempty"`
+	ErrorClass string   `json:"error_class,omitempty"`
+	Limit      int      `json:"limit,omitempty"`
+}
@@ -0,0 +1,751 @@
+package apitypes
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Optional fields are pointers or omitempty, as the handlers leave them out or send null.
+
+// ErrorResponse is the body of the error responses of all endpoints. Warnings are the notes of
+// the airport expansion of the searches which failed after it.
+type ErrorResponse struct {
+	Error    string   `json:"error"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ExpansionLimitResponse is the error response of the endpoints which refuse a search or a job
+// whose airports expand to too many routes or scheduled jobs.
+type ExpansionLimitResponse struct {
+	ErrorResponse
+	TotalRoutes  int `json:"total_routes,omitempty"`
+	MaxRoutes    int `json:"max_routes,omitempty"`
+	TotalJobs    int `json:"total_jobs,omitempty"`
+	MaxJobs      int `json:"max_jobs,omitempty"`
+	Origins      int `json:"origins"`
+	Destinations int `json:"destinations"`
+}
+
+// MessageResponse is the response of the actions which only confirm their result.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// JobAcceptedResponse is the response of the endpoints which enqueue a single queue job.
+type JobAcceptedResponse struct {
+	JobID   string `json:"job_id"`
+	Message string `json:"message"`
+}
+
+// ScheduledJobSavedResponse is the response of creating or updating a scheduled job.
+type ScheduledJobSavedResponse struct {
+	ID      int    `json:"id"`
+	Message string `json:"message"`
+}
+
+// BulkSearchAcceptedResponse is the response of POST /api/v1/bulk-search. TotalSearches is
+// only set for multi-city bulk searches, which search every departure date of the range.
+type BulkSearchAcceptedResponse struct {
+	JobID          string          `json:"job_id"`
+	BulkSearchID   int             `json:"bulk_search_id"`
+	TotalSearches  int             `json:"total_searches,omitempty"`
+	Message        string          `json:"message"`
+	Warnings       []string        `json:"warnings,omitempty"`
+	NearbyAirports []NearbyAirport `json:"nearby_airports,omitempty"`
+}
+
+// NearbyAirport is an airport matched by a NEAR:<code>:<miles> token, with the airport it is
+// near and the distance to it.
+type NearbyAirport struct {
+	Code          string  `json:"code"`
+	Anchor        string  `json:"anchor"`
+	DistanceMiles float64 `json:"distance_miles"`
+}
+
+// AirportResponse is an airport of GET /api/v1/airports.
+type AirportResponse struct {
+	Code      string   `json:"code"`
+	Name      string   `json:"name"`
+	City      string   `json:"city"`
+	Country   string   `json:"country"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// AirlineResponse is an airline of GET /api/v1/airlines. The fields keep the capitalized names
+// the endpoint has always sent.
+type AirlineResponse struct {
+	Code    string
+	Name    string
+	Country string
+}
+
+// TopAirport is an airport of GET /api/v1/airports/top, the airports of the continuous sweep.
+// Like AirlineResponse, it keeps the capitalized field names.
+type TopAirport struct {
+	Code    string
+	Country string
+}
+
+// SearchOffer is a flight offer of a queued search.
+type SearchOffer struct {
+	ID               int                  `json:"id"`
+	Price            float64              `json:"price"`
+	Currency         string               `json:"currency"`
+	CreatedAt        time.Time            `json:"created_at"`
+	Segments         []SearchOfferSegment `json:"segments"`
+	AirlineCodes     string               `json:"airline_codes,omitempty"`
+	OutboundDuration *int64               `json:"outbound_duration,omitempty"`
+	OutboundStops    *int64               `json:"outbound_stops,omitempty"`
+	ReturnDuration   *int64               `json:"return_duration,omitempty"`
+	ReturnStops      *int64               `json:"return_stops,omitempty"`
+}
+
+// SearchOfferSegment is a flight of an offer of a queued search. The fields keep the
+// capitalized names the endpoint has always sent.
+type SearchOfferSegment struct {
+	ID               int
+	FlightOfferID    int
+	AirlineCode      string
+	FlightNumber     string
+	DepartureAirport string
+	ArrivalAirport   string
+	DepartureTime    time.Time
+	ArrivalTime      time.Time
+	Duration         int
+	Airplane         string
+	Legroom          string
+	IsReturn         bool
+	SegmentIndex     int // segment of a multi-city search, 0 otherwise
+}
+
+// SearchSegmentResponse is a requested segment of a multi-city search.
+type SearchSegmentResponse struct {
+	Origin        string `json:"origin"`
+	Destination   string `json:"destination"`
+	DepartureDate string `json:"departure_date"`
+}
+
+// SearchResponse is a queued search; Offers and the multi-city fields are only set by
+// GET /api/v1/search/:id.
+type SearchResponse struct {
+	ID            int                     `json:"id"`
+	Origin        string                  `json:"origin"`
+	Destination   string                  `json:"destination"`
+	DepartureDate time.Time               `json:"departure_date"`
+	ReturnDate    *time.Time              `json:"return_date,omitempty"`
+	Status        string                  `json:"status"`
+	CreatedAt     time.Time               `json:"created_at"`
+	TripType      string                  `json:"trip_type,omitempty"`
+	Segments      []SearchSegmentResponse `json:"segments,omitempty"`
+	Offers        []SearchOffer           `json:"offers,omitempty"`
+}
+
+// SearchListResponse is a page of GET /api/v1/search.
+type SearchListResponse struct {
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	PerPage    int              `json:"per_page"`
+	TotalPages int              `json:"total_pages"`
+	Data       []SearchResponse `json:"data"`
+}
+
+// BulkSearchResultResponse is a result row of a bulk search.
+type BulkSearchResultResponse struct {
+	Origin               string          `json:"origin"`
+	Destination          string          `json:"destination"`
+	DepartureDate        time.Time       `json:"departure_date"`
+	ReturnDate           *time.Time      `json:"return_date,omitempty"`
+	Price                float64         `json:"price"`
+	Currency             string          `json:"currency"`
+	AirlineCode          *string         `json:"airline_code,omitempty"`
+	Duration             *int            `json:"duration,omitempty"`
+	SrcAirportCode       string          `json:"src_airport_code,omitempty"`
+	DstAirportCode       string          `json:"dst_airport_code,omitempty"`
+	SrcCity              string          `json:"src_city,omitempty"`
+	DstCity              string          `json:"dst_city,omitempty"`
+	FlightDuration       *int            `json:"flight_duration,omitempty"`
+	ReturnFlightDuration *int            `json:"return_flight_duration,omitempty"`
+	OutboundFlights      json.RawMessage `json:"outbound_flights,omitempty"`
+	ReturnFlights        json.RawMessage `json:"return_flights,omitempty"`
+	OfferJSON            json.RawMessage `json:"offer_json,omitempty"`
+	SegmentFlights       json.RawMessage `json:"segment_flights,omitempty"`
+}
+
+// PaginationResponse describes the page of a paginated response.
+type PaginationResponse struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	TotalPages int `json:"total_pages"`
+}
+
+// BulkSearchResponse is the response of GET /api/v1/bulk-search/:id.
+type BulkSearchResponse struct {
+	ID            int                        `json:"id"`
+	JobID         *int                       `json:"job_id"`
+	Status        string                     `json:"status"`
+	TotalSearches int                        `json:"total_searches"`
+	Completed     int                        `json:"completed"`
+	TotalOffers   int                        `json:"total_offers"`
+	ErrorCount    int                        `json:"error_count"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	UpdatedAt     time.Time                  `json:"updated_at"`
+	CompletedAt   *time.Time                 `json:"completed_at,omitempty"`
+	MinPrice      *float64                   `json:"min_price,omitempty"`
+	MaxPrice      *float64                   `json:"max_price,omitempty"`
+	AveragePrice  *float64                   `json:"average_price,omitempty"`
+	Results       []BulkSearchResultResponse `json:"results"`
+	Pagination    PaginationResponse         `json:"pagination"`
+}
+
+// BulkSearchSummary is a bulk search run in the admin endpoints.
+type BulkSearchSummary struct {
+	ID           int        `json:"id"`
+	JobID        *int       `json:"job_id,omitempty"`
+	Status       string     `json:"status"`
+	TotalRoutes  int        `json:"total_routes"`
+	Completed    int        `json:"completed"`
+	TotalOffers  int        `json:"total_offers"`
+	ErrorCount   int        `json:"error_count"`
+	Currency     string     `json:"currency"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	MinPrice     *float64   `json:"min_price,omitempty"`
+	MaxPrice     *float64   `json:"max_price,omitempty"`
+	AveragePrice *float64   `json:"average_price,omitempty"`
+}
+
+// BulkSearchListResponse is the response of GET /api/v1/admin/bulk-jobs.
+type BulkSearchListResponse struct {
+	Items []BulkSearchSummary `json:"items"`
+	Count int                 `json:"count"`
+}
+
+// BulkSearchResultsResponse is the response of GET /api/v1/admin/bulk-jobs/:id.
+type BulkSearchResultsResponse struct {
+	Summary BulkSearchSummary          `json:"summary"`
+	Results []BulkSearchResultResponse `json:"results"`
+	Count   int                        `json:"count"`
+}
+
+// BulkSearchOffer is an offer captured during a bulk search.
+type BulkSearchOffer struct {
+	BulkSearchResultResponse
+	AirlineCodes          []string  `json:"airline_codes,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
+	DistanceMiles         *float64  `json:"distance_miles,omitempty"`
+	CostPerMile           *float64  `json:"cost_per_mile,omitempty"`
+	NormalizedPrice       *float64  `json:"normalized_price,omitempty"`
+	NormalizedCurrency    string    `json:"normalized_currency,omitempty"`
+	NormalizedCostPerMile *float64  `json:"normalized_cost_per_mile,omitempty"`
+}
+
+// BulkSearchGridCell is the cheapest offer of a date (pair) of a route.
+type BulkSearchGridCell struct {
+	DepartureDate time.Time  `json:"departure_date"`
+	ReturnDate    *time.Time `json:"return_date,omitempty"`
+	Price         float64    `json:"price"`
+	Currency      string     `json:"currency"`
+	AirlineCodes  []string   `json:"airline_codes,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// BulkSearchRouteGrid is the date grid of a route of a bulk search.
+type BulkSearchRouteGrid struct {
+	Origin      string               `json:"origin"`
+	Destination string               `json:"destination"`
+	Cells       []BulkSearchGridCell `json:"cells"`
+}
+
+// BulkSearchOffersResponse is the response of GET /api/v1/admin/bulk-jobs/:id/offers.
+type BulkSearchOffersResponse struct {
+	Items []BulkSearchOffer     `json:"items"`
+	Count int                   `json:"count"`
+	Grid  []BulkSearchRouteGrid `json:"grid"`
+}
+
+// ScheduledJobDetails are the search parameters of a scheduled job. The job list only has the
+// route and the dynamic date settings; a single job has the full parameters.
+type ScheduledJobDetails struct {
+	Origin             string  `json:"origin"`
+	Destination        string  `json:"destination"`
+	DynamicDates       bool    `json:"dynamic_dates"`
+	DaysFromExecution  *int    `json:"days_from_execution,omitempty"`
+	SearchWindowDays   *int    `json:"search_window_days,omitempty"`
+	TripLength         *int    `json:"trip_length,omitempty"`
+	DepartureDateStart string  `json:"departure_date_start,omitempty"`
+	DepartureDateEnd   string  `json:"departure_date_end,omitempty"`
+	Adults             int     `json:"adults,omitempty"`
+	Children           int     `json:"children,omitempty"`
+	InfantsLap         int     `json:"infants_lap,omitempty"`
+	InfantsSeat        int     `json:"infants_seat,omitempty"`
+	TripType           string  `json:"trip_type,omitempty"`
+	Class              string  `json:"class,omitempty"`
+	Stops              string  `json:"stops,omitempty"`
+	Currency           string  `json:"currency,omitempty"`
+	ReturnDateStart    *string `json:"return_date_start,omitempty"`
+	ReturnDateEnd      *string `json:"return_date_end,omitempty"`
+}
+
+// ScheduledJobResponse is a scheduled job of the admin endpoints.
+type ScheduledJobResponse struct {
+	ID             int                 `json:"id"`
+	Name           string              `json:"name"`
+	CronExpression string              `json:"cron_expression"`
+	Enabled        bool                `json:"enabled"`
+	LastRun        *time.Time          `json:"last_run"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	Details        ScheduledJobDetails `json:"details"`
+}
+
+// BulkJobCreated is a scheduled job created by POST /api/v1/admin/bulk-jobs.
+type BulkJobCreated struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	Origin         string `json:"origin"`
+	Destination    string `json:"destination"`
+	CronExpression string `json:"cron_expression"`
+}
+
+// BulkJobsCreatedResponse is the response of POST /api/v1/admin/bulk-jobs.
+type BulkJobsCreatedResponse struct {
+	Message        string           `json:"message"`
+	Jobs           []BulkJobCreated `json:"jobs"`
+	Warnings       []string         `json:"warnings"`
+	NearbyAirports []NearbyAirport  `json:"nearby_airports,omitempty"`
+}
+
+// PriceGraphSweepSummary is a price graph sweep run.
+type PriceGraphSweepSummary struct {
+	ID               int        `json:"id"`
+	JobID            *int       `json:"job_id,omitempty"`
+	Status           string     `json:"status"`
+	OriginCount      int        `json:"origin_count"`
+	DestinationCount int        `json:"destination_count"`
+	TripLengthMin    *int       `json:"trip_length_min"`
+	TripLengthMax    *int       `json:"trip_length_max"`
+	Currency         string     `json:"currency"`
+	ErrorCount       int        `json:"error_count"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	StartedAt        *time.Time `json:"started_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+}
+
+// PriceGraphSweepAcceptedResponse is the response of POST /api/v1/admin/price-graph-sweeps.
+type PriceGraphSweepAcceptedResponse struct {
+	Message        string                 `json:"message"`
+	SweepID        int                    `json:"sweep_id"`
+	Classes        []string               `json:"classes"`
+	Warnings       []string               `json:"warnings"`
+	Sweep          PriceGraphSweepSummary `json:"sweep"`
+	NearbyAirports []NearbyAirport        `json:"nearby_airports,omitempty"`
+}
+
+// PriceGraphSweepListResponse is the response of GET /api/v1/admin/price-graph-sweeps.
+type PriceGraphSweepListResponse struct {
+	Items []PriceGraphSweepSummary `json:"items"`
+	Count int                      `json:"count"`
+}
+
+// PriceGraphResult is a cheapest fare found by a price graph sweep.
+type PriceGraphResult struct {
+	ID                int        `json:"id"`
+	SweepID           int        `json:"sweep_id"`
+	Origin            string     `json:"origin"`
+	Destination       string     `json:"destination"`
+	DepartureDate     time.Time  `json:"departure_date"`
+	ReturnDate        *time.Time `json:"return_date"`
+	TripLength        *int       `json:"trip_length"`
+	Price             float64    `json:"price"`
+	Currency          string     `json:"currency"`
+	Adults            int        `json:"adults"`
+	Children          int        `json:"children"`
+	InfantsLap        int        `json:"infants_lap"`
+	InfantsSeat       int        `json:"infants_seat"`
+	TripType          string     `json:"trip_type"`
+	Class             string     `json:"class"`
+	Stops             string     `json:"stops"`
+	SearchURL         *string    `json:"search_url"`
+	QueriedAt         time.Time  `json:"queried_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ReturnOrigin      string     `json:"return_origin,omitempty"`
+	ReturnDestination string     `json:"return_destination,omitempty"`
+}
+
+// PriceGraphSweepResultsResponse is the response of GET /api/v1/admin/price-graph-sweeps/:id.
+type PriceGraphSweepResultsResponse struct {
+	Sweep   PriceGraphSweepSummary `json:"sweep"`
+	Results []PriceGraphResult     `json:"results"`
+	Count   int                    `json:"count"`
+}
+
+// DealResponse is a detected deal.
+type DealResponse struct {
+	ID                 int       `json:"id"`
+	Origin             string    `json:"origin"`
+	Destination        string    `json:"destination"`
+	DepartureDate      string    `json:"departure_date"`
+	ReturnDate         string    `json:"return_date,omitempty"`
+	Price              float64   `json:"price"`
+	Currency           string    `json:"currency"`
+	DiscountPercent    *float64  `json:"discount_percent"`
+	DealScore          *int      `json:"deal_score"`
+	DealClassification *string   `json:"deal_classification"`
+	CostPerMile        *float64  `json:"cost_per_mile"`
+	CabinClass         string    `json:"cabin_class"`
+	Status             string    `json:"status"`
+	FirstSeenAt        time.Time `json:"first_seen_at"`
+	TimesSeen          int       `json:"times_seen"`
+	SearchURL          string    `json:"search_url,omitempty"`
+	NormalizedPrice    *float64  `json:"normalized_price,omitempty"`
+	NormalizedCurrency string    `json:"normalized_currency,omitempty"`
+}
+
+// DealFilterResponse is the filter applied by GET /api/v1/admin/deals.
+type DealFilterResponse struct {
+	Origin         string `json:"origin"`
+	Destination    string `json:"destination"`
+	Classification string `json:"classification"`
+	Status         string `json:"status"`
+}
+
+// DealListResponse is the response of GET /api/v1/admin/deals.
+type DealListResponse struct {
+	Deals  []DealResponse     `json:"deals"`
+	Count  int                `json:"count"`
+	Filter DealFilterResponse `json:"filter"`
+}
+
+// DealAlertResponse is a published deal alert.
+type DealAlertResponse struct {
+	ID                 int        `json:"id"`
+	DetectedDealID     int        `json:"detected_deal_id"`
+	Origin             string     `json:"origin"`
+	Destination        string     `json:"destination"`
+	Price              float64    `json:"price"`
+	Currency           string     `json:"currency"`
+	DiscountPercent    *float64   `json:"discount_percent"`
+	DealClassification *string    `json:"deal_classification"`
+	DealScore          *int       `json:"deal_score"`
+	PublishedAt        time.Time  `json:"published_at"`
+	PublishMethod      string     `json:"publish_method"`
+	NotificationSent   bool       `json:"notification_sent"`
+	NotificationSentAt *time.Time `json:"notification_sent_at,omitempty"`
+}
+
+// DealAlertListResponse is the response of GET /api/v1/admin/deal-alerts.
+type DealAlertListResponse struct {
+	Alerts []DealAlertResponse `json:"alerts"`
+	Count  int                 `json:"count"`
+}
+
+// PricePoint is a price of the route graph.
+type PricePoint struct {
+	Date    time.Time `json:"date"`
+	Price   float64   `json:"price"`
+	Airline string    `json:"airline"`
+}
+
+// PriceHistoryResponse is the response of GET /api/v1/price-history/:origin/:destination.
+type PriceHistoryResponse struct {
+	Origin      string             `json:"origin"`
+	Destination string             `json:"destination"`
+	History     []PricePoint       `json:"history"`
+	Filter      AirlineGroupFilter `json:"filter"`
+}
+
+// AirlineGroupFilter are the airline group filters applied to a response. FilteredOut is the
+// number of offers they removed, where the endpoint counts them.
+type AirlineGroupFilter struct {
+	IncludeAirlineGroups []string `json:"include_airline_groups,omitempty"`
+	ExcludeAirlineGroups []string `json:"exclude_airline_groups,omitempty"`
+	FilteredOut          int      `json:"filtered_out,omitempty"`
+}
+
+// SampleAirport is an airport of the cached sample list of GET /api/airports.
+type SampleAirport struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	City string `json:"city"`
+}
+
+// SamplePricePoint is a price of the sample history of GET /api/price-history.
+type SamplePricePoint struct {
+	Date        string  `json:"date"`
+	Price       float64 `json:"price"`
+	Origin      string  `json:"origin"`
+	Destination string  `json:"destination"`
+}
+
+// CheapestPathResponse is the response of GET /api/v1/graph/path.
+type CheapestPathResponse struct {
+	Origin   string       `json:"origin"`
+	Dest     string       `json:"dest"`
+	MaxHops  int          `json:"maxHops"`
+	MaxPrice float64      `json:"maxPrice"`
+	Paths    []PathResult `json:"paths"`
+}
+
+// PathResult is a route through the graph with the price of every leg.
+type PathResult struct {
+	Stops      []string  `json:"stops"`       // airport codes in order
+	TotalPrice float64   `json:"total_price"` // sum of the leg prices
+	Legs       []LegInfo `json:"legs"`
+}
+
+// LegInfo is the price of a leg of a [PathResult].
+type LegInfo struct {
+	Origin      string  `json:"origin"`
+	Destination string  `json:"destination"`
+	Price       float64 `json:"price"`
+	Airline     string  `json:"airline,omitempty"`
+}
+
+// ConnectionsResponse is the response of GET /api/v1/graph/connections.
+type ConnectionsResponse struct {
+	Origin      string       `json:"origin"`
+	MaxHops     int          `json:"maxHops"`
+	MaxPrice    float64      `json:"maxPrice"`
+	Count       int          `json:"count"`
+	Connections []Connection `json:"connections"`
+}
+
+// Connection is a destination reachable from the origin of GET /api/v1/graph/connections.
+type Connection struct {
+	Airport      string  `json:"airport"`
+	Name         string  `json:"name"`
+	Country      string  `json:"country"`
+	CheapestPath float64 `json:"cheapest_path"` // cheapest total price to reach the airport
+	Hops         int     `json:"hops"`
+}
+
+// RouteStats is the response of GET /api/v1/graph/route-stats and /api/v1/graph/route-details.
+// Source is "price_point" when the statistics come from date-specific prices and "route" when
+// they come from the route averages, which have no dates or samples.
+type RouteStats struct {
+	Origin      string   `json:"origin"`
+	Destination string   `json:"destination"`
+	MinPrice    float64  `json:"min_price"`
+	MaxPrice    float64  `json:"max_price"`
+	AvgPrice    float64  `json:"avg_price"`
+	PricePoints int      `json:"price_points"`
+	Airlines    []string `json:"airlines"`
+
+	Source     string `json:"source,omitempty"`
+	Note       string `json:"note,omitempty"`
+	RouteEdges int    `json:"route_edges,omitempty"`
+
+	MinPriceDate       string `json:"min_price_date,omitempty"`
+	MinPriceAirline    string `json:"min_price_airline,omitempty"`
+	MinPriceSeenAt     string `json:"min_price_seen_at,omitempty"`
+	MinPriceTripType   string `json:"min_price_trip_type,omitempty"`
+	MinPriceReturnDate string `json:"min_price_return_date,omitempty"`
+
+	MaxPriceDate       string `json:"max_price_date,omitempty"`
+	MaxPriceAirline    string `json:"max_price_airline,omitempty"`
+	MaxPriceSeenAt     string `json:"max_price_seen_at,omitempty"`
+	MaxPriceTripType   string `json:"max_price_trip_type,omitempty"`
+	MaxPriceReturnDate string `json:"max_price_return_date,omitempty"`
+
+	FirstSeenAt string `json:"first_seen_at,omitempty"`
+	LastSeenAt  string `json:"last_seen_at,omitempty"`
+
+	Samples []RoutePricePoint `json:"samples,omitempty"`
+}
+
+// RoutePricePoint is a price sample of [RouteStats].
+type RoutePricePoint struct {
+	Date       string  `json:"date,omitempty"`
+	Price      float64 `json:"price"`
+	Airline    string  `json:"airline,omitempty"`
+	SeenAt     string  `json:"seen_at,omitempty"`
+	TripType   string  `json:"trip_type,omitempty"`
+	ReturnDate string  `json:"return_date,omitempty"`
+}
+
+// ExploreEdge is a destination of GET /api/v1/graph/explore with its cheapest price.
+type ExploreEdge struct {
+	OriginCode string  `json:"origin_code"`
+	OriginLat  float64 `json:"origin_lat"`
+	OriginLon  float64 `json:"origin_lon"`
+
+	DestCode    string  `json:"dest_code"`
+	DestName    string  `json:"dest_name,omitempty"`
+	DestCity    string  `json:"dest_city,omitempty"`
+	DestCountry string  `json:"dest_country,omitempty"`
+	DestLat     float64 `json:"dest_lat"`
+	DestLon     float64 `json:"dest_lon"`
+
+	CheapestPrice float64 `json:"cheapest_price"`
+	Hops          int     `json:"hops"`
+}
+
+// ExploreResponse is the response of GET /api/v1/graph/explore.
+type ExploreResponse struct {
+	Origin          string   `json:"origin"`
+	Origins         []string `json:"origins,omitempty"`
+	MaxHops         int      `json:"maxHops"`
+	MaxPrice        float64  `json:"maxPrice"`
+	DateFrom        string   `json:"dateFrom,omitempty"`
+	DateTo          string   `json:"dateTo,omitempty"`
+	Limit           int      `json:"limit"`
+	Source          string   `json:"source"`
+	MaxAgeDays      int      `json:"maxAgeDays,omitempty"`
+	TripType        string   `json:"tripType,omitempty"`
+	Airlines        []string `json:"airlines,omitempty"`
+	ExcludeAirlines []string `json:"excludeAirlines,omitempty"`
+
+	Count int           `json:"count"`
+	Edges []ExploreEdge `json:"edges"`
+}
+
+// FlexDateMatrix is the price grid of a [FlexDateSearchRequest]. Cells are ordered by
+// departure date, then return date; dates which are in the past or have the return before the
+// departure have no cell.
+type FlexDateMatrix struct {
+	Origin          string         `json:"origin"`
+	Destination     string         `json:"destination"`
+	Currency        string         `json:"currency"`
+	DepartureDates  []string       `json:"departure_dates"`
+	ReturnDates     []string       `json:"return_dates,omitempty"`
+	Cells           []FlexDateCell `json:"cells"`
+	Cheapest        *FlexDateCell  `json:"cheapest,omitempty"`
+	PriceGraphCalls int            `json:"price_graph_calls"`
+	OfferSearches   int            `json:"offer_searches"`
+	Warnings        []string       `json:"warnings,omitempty"`
+	Cached          bool           `json:"cached"`
+}
+
+// FlexDateCell is one departure/return date combination. Source is "offers" when the price comes
+// from a flight search (and Itinerary is set), "price_graph" when it comes from Google's price
+// graph only, and empty when no price was found.
+type FlexDateCell struct {
+	DepartureDate   string             `json:"departure_date"`
+	ReturnDate      string             `json:"return_date,omitempty"`
+	Price           float64            `json:"price,omitempty"`
+	PriceGraphPrice float64            `json:"price_graph_price,omitempty"`
+	Source          string             `json:"source"`
+	Itinerary       *FlexDateItinerary `json:"itinerary,omitempty"`
+}
+
+// FlexDateItinerary is the cheapest offer found for a cell. Round trips also have the cheapest
+// return flights of the offer.
+type FlexDateItinerary struct {
+	Price          float64         `json:"price"`
+	TotalDuration  int             `json:"total_duration"` // minutes
+	Segments       []FlightSegment `json:"segments"`
+	ReturnSegments []FlightSegment `json:"return_segments,omitempty"`
+	ReturnDuration int             `json:"return_duration,omitempty"` // minutes
+	AirlineCodes   []string        `json:"airline_codes,omitempty"`
+}
+
+// ExploreAnywhereResponse lists the destinations of an explore search, cheapest first.
+type ExploreAnywhereResponse struct {
+	Origin            string                       `json:"origin"`
+	Currency          string                       `json:"currency"`
+	DepartureDateFrom string                       `json:"departure_date_from"`
+	DepartureDateTo   string                       `json:"departure_date_to"`
+	TripLength        int                          `json:"trip_length"`
+	TripType          string                       `json:"trip_type"`
+	Count             int                          `json:"count"`
+	Destinations      []ExploreAnywhereDestination `json:"destinations"`
+	Persisted         int                          `json:"persisted,omitempty"`
+	Warnings          []string                     `json:"warnings,omitempty"`
+	Cached            bool                         `json:"cached"`
+}
+
+// ExploreAnywhereDestination is a destination with its cheapest fare. Stops is -1 when Google
+// didn't tell, and DistanceMiles is the great-circle distance from the origin when both ends
+// have known coordinates.
+type ExploreAnywhereDestination struct {
+	Rank            int     `json:"rank"`
+	CityID          string  `json:"city_id"`
+	City            string  `json:"city,omitempty"`
+	Country         string  `json:"country,omitempty"`
+	AirportCode     string  `json:"airport_code,omitempty"`
+	Lat             float64 `json:"lat,omitempty"`
+	Lon             float64 `json:"lon,omitempty"`
+	Price           float64 `json:"price"`
+	DepartureDate   string  `json:"departure_date"`
+	ReturnDate      string  `json:"return_date,omitempty"`
+	Stops           int     `json:"stops"`
+	AirlineCode     string  `json:"airline_code,omitempty"`
+	DurationMinutes int     `json:"duration_minutes,omitempty"`
+	DistanceMiles   float64 `json:"distance_miles,omitempty"`
+}
+
+// HotelSearchResponse is the response of POST /api/v1/hotels/search.
+type HotelSearchResponse struct {
+	Location     string       `json:"location"`
+	CheckInDate  DateOnly     `json:"checkin_date"`
+	CheckOutDate DateOnly     `json:"checkout_date"`
+	Offers       []HotelOffer `json:"offers"`
+	Count        int          `json:"count"`
+}
+
+// HotelOffer is a hotel with its price for the searched stay.
+type HotelOffer struct {
+	Name        string   `json:"name"`
+	Price       float64  `json:"price"`
+	Currency    string   `json:"currency"`
+	Rating      float64  `json:"rating"`
+	Stars       int      `json:"stars,omitempty"`
+	Address     string   `json:"address,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Images      []string `json:"images,omitempty"`
+	HotelID     string   `json:"hotel_id,omitempty"`
+	Latitude    float64  `json:"latitude,omitempty"`
+	Longitude   float64  `json:"longitude,omitempty"`
+}
+
+// PosComparisonAcceptedResponse is the response of POST /api/v1/pos-comparison.
+type PosComparisonAcceptedResponse struct {
+	ComparisonID int    `json:"comparison_id"`
+	JobID        string `json:"job_id"`
+	Status       string `json:"status"`
+	Markets      int    `json:"markets"`
+}
+
+// PosComparisonResponse is the response of GET /api/v1/pos-comparison/:id. Cheapest and
+// Savings are set once the comparison found a price in at least one market.
+type PosComparisonResponse struct {
+	ID            int                   `json:"id"`
+	Status        string                `json:"status"`
+	Origin        string                `json:"origin"`
+	Destination   string                `json:"destination"`
+	DepartureDate string                `json:"departure_date"`
+	ReturnDate    string                `json:"return_date,omitempty"`
+	TripType      string                `json:"trip_type"`
+	Class         string                `json:"class"`
+	Currency      string                `json:"currency"`
+	MarketCount   int                   `json:"market_count"`
+	CreatedAt     time.Time             `json:"created_at"`
+	Markets       []PosComparisonMarket `json:"markets"`
+	Cheapest      *PosComparisonPrice   `json:"cheapest,omitempty"`
+	Savings       *float64              `json:"savings,omitempty"`
+	Error         string                `json:"error,omitempty"`
+	CompletedAt   *time.Time            `json:"completed_at,omitempty"`
+}
+
+// PosComparisonMarket is the result of a market of a comparison. Price is in Currency, the
+// currency of the market; NormalizedPrice is in the currency of the comparison.
+type PosComparisonMarket struct {
+	Country         string   `json:"gl"`
+	Currency        string   `json:"currency"`
+	AirlineCodes    []string `json:"airline_codes"`
+	FlightNumbers   []string `json:"flight_numbers"`
+	GoogleHost      string   `json:"google_host,omitempty"`
+	Price           *float64 `json:"price,omitempty"`
+	NormalizedPrice *float64 `json:"normalized_price,omitempty"`
+	SearchURL       string   `json:"search_url,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// PosComparisonPrice is the cheapest market of a comparison.
+type PosComparisonPrice struct {
+	Country string  `json:"gl"`
+	Price   float64 `json:"price"`
+}
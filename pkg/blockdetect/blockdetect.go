@@ -0,0 +1,91 @@
+// Package blockdetect recognizes the responses Google serves instead of the requested data: the
+// "unusual traffic" page, the cookie consent interstitial and plain blocks. The flights and hotels
+// packages return its errors, so callers can back off instead of treating them as parse failures.
+package blockdetect
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrRateLimited means Google throttles the client (HTTP 429 or the "unusual traffic" page).
+	ErrRateLimited = errors.New("google: rate limited (unusual traffic)")
+	// ErrConsentRequired means Google redirected to the cookie consent interstitial.
+	ErrConsentRequired = errors.New("google: consent required")
+	// ErrBlocked means Google refused the request or served an unexpected HTML page.
+	ErrBlocked = errors.New("google: request blocked")
+	// ErrSchemaChanged means the response was received but none of it matched the expected layout.
+	ErrSchemaChanged = errors.New("google: unexpected response schema")
+)
+
+// PeekSize is the number of bytes of the body inspected by the callers of [ClassifyBody].
+const PeekSize = 4096
+
+var (
+	rateLimitedMarkers = [][]byte{
+		[]byte("unusual traffic from your computer network"),
+		[]byte("/sorry/index"),
+		[]byte("g-recaptcha"),
+		[]byte("captcha-form"),
+	}
+	consentMarkers = [][]byte{
+		[]byte("consent.google.com"),
+		[]byte("Before you continue to Google"),
+	}
+)
+
+// IsBlock reports whether err is one of the errors which require backing off.
+func IsBlock(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrConsentRequired) || errors.Is(err, ErrBlocked)
+}
+
+// Classify checks the status and the final URL of a response. It returns nil for responses which
+// may contain the requested data.
+func Classify(resp *http.Response) error {
+	if resp == nil {
+		return nil
+	}
+
+	if resp.Request != nil && resp.Request.URL != nil {
+		u := resp.Request.URL
+		if strings.HasPrefix(u.Host, "consent.") {
+			return ErrConsentRequired
+		}
+		if strings.HasPrefix(u.Path, "/sorry") {
+			return ErrRateLimited
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusForbidden:
+		return ErrBlocked
+	}
+	return nil
+}
+
+// ClassifyBody looks for the markers of the interstitials in the beginning of an HTML body. It
+// returns nil if none is found.
+func ClassifyBody(body []byte) error {
+	for _, marker := range rateLimitedMarkers {
+		if bytes.Contains(body, marker) {
+			return ErrRateLimited
+		}
+	}
+	for _, marker := range consentMarkers {
+		if bytes.Contains(body, marker) {
+			return ErrConsentRequired
+		}
+	}
+	return nil
+}
+
+// IsHTML reports whether the body is an HTML page.
+func IsHTML(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return len(body) > 0 && body[0] == '<'
+}
@@ -0,0 +1,44 @@
+package blockdetect
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func response(status int, rawURL string) *http.Response {
+	u, _ := url.Parse(rawURL)
+	return &http.Response{StatusCode: status, Request: &http.Request{URL: u}}
+}
+
+func TestClassify(t *testing.T) {
+	assert.NoError(t, Classify(nil))
+	assert.NoError(t, Classify(response(http.StatusOK, "https://www.google.com/_/FlightsFrontendUi/data")))
+	assert.ErrorIs(t, Classify(response(http.StatusTooManyRequests, "https://www.google.com/_/FlightsFrontendUi/data")), ErrRateLimited)
+	assert.ErrorIs(t, Classify(response(http.StatusOK, "https://www.google.com/sorry/index?continue=x")), ErrRateLimited)
+	assert.ErrorIs(t, Classify(response(http.StatusOK, "https://consent.google.com/ml?continue=x")), ErrConsentRequired)
+	assert.ErrorIs(t, Classify(response(http.StatusForbidden, "https://www.google.com/travel/search")), ErrBlocked)
+}
+
+func TestClassifyBody(t *testing.T) {
+	assert.ErrorIs(t, ClassifyBody([]byte(`<html><body>Our systems have detected unusual traffic from your computer network.</body></html>`)), ErrRateLimited)
+	assert.ErrorIs(t, ClassifyBody([]byte(`<html><form action="https://consent.google.com/save"></form></html>`)), ErrConsentRequired)
+	assert.NoError(t, ClassifyBody([]byte(`)]}'`+"\n\n"+`[["wrb.fr",null,"[]"]]`)))
+}
+
+func TestIsBlock(t *testing.T) {
+	assert.True(t, IsBlock(fmt.Errorf("query failed: %w", ErrRateLimited)))
+	assert.True(t, IsBlock(ErrConsentRequired))
+	assert.True(t, IsBlock(ErrBlocked))
+	assert.False(t, IsBlock(ErrSchemaChanged))
+	assert.False(t, IsBlock(fmt.Errorf("timeout")))
+}
+
+func TestIsHTML(t *testing.T) {
+	assert.True(t, IsHTML([]byte("\n  <!doctype html>")))
+	assert.False(t, IsHTML([]byte(")]}'")))
+	assert.False(t, IsHTML(nil))
+}
@@ -1,9 +1,11 @@
 package cache
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -91,6 +93,118 @@ func (c *RedisCache) Clear(ctx context.Context) error {
 	return iter.Err()
 }
 
+// DefaultMemoryCacheEntries bounds a MemoryCache created without an explicit size.
+const DefaultMemoryCacheEntries = 10000
+
+// MemoryCache implements Cache in process memory, for deployments without Redis.
+// Entries are dropped once their TTL passes, and the least recently used ones once the cache
+// holds maxEntries, so keys that are never read again don't accumulate.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	recency    *list.List // of *memoryEntry, most recently used first
+}
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero for no expiry
+}
+
+// NewMemoryCache creates a new in-memory cache instance holding at most maxEntries entries, or
+// DefaultMemoryCacheEntries if maxEntries is not positive.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryCacheEntries
+	}
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		recency:    list.New(),
+	}
+}
+
+// lookup returns the live entry of key and marks it used, deleting it if it expired. The
+// caller holds c.mu.
+func (c *MemoryCache) lookup(key string) (*memoryEntry, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.recency.MoveToFront(element)
+	return entry, true
+}
+
+// remove deletes the entry of element. The caller holds c.mu.
+func (c *MemoryCache) remove(element *list.Element) {
+	c.recency.Remove(element)
+	delete(c.entries, element.Value.(*memoryEntry).key)
+}
+
+// Get retrieves a value from cache
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.lookup(key)
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Set stores a value in cache with TTL, evicting the least recently used entry when full
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := &memoryEntry{key: key, value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.recency.MoveToFront(element)
+		return nil
+	}
+	c.entries[key] = c.recency.PushFront(entry)
+	for c.recency.Len() > c.maxEntries {
+		c.remove(c.recency.Back())
+	}
+	return nil
+}
+
+// Delete removes a value from cache
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	return nil
+}
+
+// Exists checks if a key exists in cache
+func (c *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.lookup(key)
+	return ok, nil
+}
+
+// Clear removes all keys
+func (c *MemoryCache) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.recency.Init()
+	return nil
+}
+
 // CacheManager provides high-level caching operations
 type CacheManager struct {
 	cache Cache
@@ -186,6 +300,18 @@ func PriceHistoryKey(origin, destination string) string {
 	return fmt.Sprintf("price_history:%s:%s", origin, destination)
 }
 
+// FlexDateMatrixKey identifies a flex-date price grid; variant holds the remaining search
+// options (cabin, stops, currency, passengers).
+func FlexDateMatrixKey(origin, destination, departureDate, returnDate string, departureFlex, returnFlex int, variant string) string {
+	return fmt.Sprintf("flex_dates:%s:%s:%s:%s:%d:%d:%s", origin, destination, departureDate, returnDate, departureFlex, returnFlex, variant)
+}
+
+// ExploreAnywhereKey identifies the destinations of an explore ("Anywhere") search; variant holds
+// the remaining search options (cabin, stops, currency, passengers).
+func ExploreAnywhereKey(origin, departureFrom, departureTo string, tripLength int, variant string) string {
+	return fmt.Sprintf("explore_anywhere:%s:%s:%s:%d:%s", origin, departureFrom, departureTo, tripLength, variant)
+}
+
 // Error definitions
 var (
 	ErrCacheMiss = fmt.Errorf("cache miss")
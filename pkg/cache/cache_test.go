@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+	_, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+
+	_, err = c.Get(ctx, "b")
+	assert.Equal(t, ErrCacheMiss, err, "b was used least recently")
+	value, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+	assert.Len(t, c.entries, 2)
+
+	require.NoError(t, c.Set(ctx, "c", []byte("4"), 0))
+	value, err = c.Get(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("4"), value)
+	assert.Equal(t, 2, c.recency.Len())
+}
+
+func TestMemoryCacheExpires(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(0)
+	assert.Equal(t, DefaultMemoryCacheEntries, c.maxEntries)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	ok, err := c.Exists(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Zero(t, c.recency.Len())
+
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+	require.NoError(t, c.Delete(ctx, "b"))
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+	require.NoError(t, c.Clear(ctx))
+	assert.Empty(t, c.entries)
+	assert.Zero(t, c.recency.Len())
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+)
+
+// ListJobs returns the scheduled jobs (GET /api/v1/admin/jobs).
+func (c *Client) ListJobs(ctx context.Context) ([]apitypes.ScheduledJobResponse, error) {
+	var resp []apitypes.ScheduledJobResponse
+	if err := c.get(ctx, "/api/v1/admin/jobs", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetJob returns a scheduled job with its search parameters (GET /api/v1/admin/jobs/:id).
+func (c *Client) GetJob(ctx context.Context, id int) (*apitypes.ScheduledJobResponse, error) {
+	var resp apitypes.ScheduledJobResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/jobs/%s", id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateJob creates a scheduled search job (POST /api/v1/admin/jobs).
+func (c *Client) CreateJob(ctx context.Context, req apitypes.JobRequest) (*apitypes.ScheduledJobSavedResponse, error) {
+	var resp apitypes.ScheduledJobSavedResponse
+	if err := c.post(ctx, "/api/v1/admin/jobs", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateJob replaces a scheduled job (PUT /api/v1/admin/jobs/:id).
+func (c *Client) UpdateJob(ctx context.Context, id int, req apitypes.JobRequest) (*apitypes.ScheduledJobSavedResponse, error) {
+	var resp apitypes.ScheduledJobSavedResponse
+	if err := c.put(ctx, pathf("/api/v1/admin/jobs/%s", id), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteJob deletes a scheduled job (DELETE /api/v1/admin/jobs/:id).
+func (c *Client) DeleteJob(ctx context.Context, id int) (*apitypes.MessageResponse, error) {
+	var resp apitypes.MessageResponse
+	if err := c.delete(ctx, pathf("/api/v1/admin/jobs/%s", id), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RunJob enqueues a scheduled job now (POST /api/v1/admin/jobs/:id/run).
+func (c *Client) RunJob(ctx context.Context, id int) (*apitypes.JobAcceptedResponse, error) {
+	var resp apitypes.JobAcceptedResponse
+	if err := c.post(ctx, pathf("/api/v1/admin/jobs/%s/run", id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// EnableJob enables a scheduled job (POST /api/v1/admin/jobs/:id/enable).
+func (c *Client) EnableJob(ctx context.Context, id int) (*apitypes.MessageResponse, error) {
+	var resp apitypes.MessageResponse
+	if err := c.post(ctx, pathf("/api/v1/admin/jobs/%s/enable", id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DisableJob disables a scheduled job (POST /api/v1/admin/jobs/:id/disable).
+func (c *Client) DisableJob(ctx context.Context, id int) (*apitypes.MessageResponse, error) {
+	var resp apitypes.MessageResponse
+	if err := c.post(ctx, pathf("/api/v1/admin/jobs/%s/disable", id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateBulkJobs creates a scheduled bulk search job per route (POST /api/v1/admin/bulk-jobs).
+func (c *Client) CreateBulkJobs(ctx context.Context, req apitypes.BulkJobRequest) (*apitypes.BulkJobsCreatedResponse, error) {
+	var resp apitypes.BulkJobsCreatedResponse
+	if err := c.post(ctx, "/api/v1/admin/bulk-jobs", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListBulkSearches returns the bulk search runs, newest first (GET /api/v1/admin/bulk-jobs).
+func (c *Client) ListBulkSearches(ctx context.Context, opts ListOptions) (*apitypes.BulkSearchListResponse, error) {
+	var resp apitypes.BulkSearchListResponse
+	if err := c.get(ctx, "/api/v1/admin/bulk-jobs", opts.query(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetBulkSearchResults returns the summary and results of a bulk search run
+// (GET /api/v1/admin/bulk-jobs/:id).
+func (c *Client) GetBulkSearchResults(ctx context.Context, id int, opts ListOptions) (*apitypes.BulkSearchResultsResponse, error) {
+	var resp apitypes.BulkSearchResultsResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/bulk-jobs/%s", id), opts.query(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BulkSearchOffersOptions selects the offers of a bulk search run; SortBy defaults to price.
+type BulkSearchOffersOptions struct {
+	ListOptions
+	SortBy string
+}
+
+// GetBulkSearchOffers returns the offers of a bulk search run and their date grid
+// (GET /api/v1/admin/bulk-jobs/:id/offers).
+func (c *Client) GetBulkSearchOffers(ctx context.Context, id int, opts BulkSearchOffersOptions) (*apitypes.BulkSearchOffersResponse, error) {
+	query := opts.ListOptions.query()
+	setString(query, "sort_by", opts.SortBy)
+
+	var resp apitypes.BulkSearchOffersResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/bulk-jobs/%s/offers", id), query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreatePriceGraphSweep enqueues a price graph sweep (POST /api/v1/admin/price-graph-sweeps).
+func (c *Client) CreatePriceGraphSweep(ctx context.Context, req apitypes.PriceGraphSweepRequest) (*apitypes.PriceGraphSweepAcceptedResponse, error) {
+	var resp apitypes.PriceGraphSweepAcceptedResponse
+	if err := c.post(ctx, "/api/v1/admin/price-graph-sweeps", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListPriceGraphSweeps returns the price graph sweep runs
+// (GET /api/v1/admin/price-graph-sweeps).
+func (c *Client) ListPriceGraphSweeps(ctx context.Context, opts ListOptions) (*apitypes.PriceGraphSweepListResponse, error) {
+	var resp apitypes.PriceGraphSweepListResponse
+	if err := c.get(ctx, "/api/v1/admin/price-graph-sweeps", opts.query(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetPriceGraphSweepResults returns a price graph sweep and its fares
+// (GET /api/v1/admin/price-graph-sweeps/:id).
+func (c *Client) GetPriceGraphSweepResults(ctx context.Context, id int, opts ListOptions) (*apitypes.PriceGraphSweepResultsResponse, error) {
+	var resp apitypes.PriceGraphSweepResultsResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/price-graph-sweeps/%s", id), opts.query(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DealListOptions filters the detected deals; Status defaults to active on the server.
+type DealListOptions struct {
+	ListOptions
+	Origin         string
+	Destination    string
+	Classification string
+	Status         string
+}
+
+// ListDeals returns the detected deals (GET /api/v1/admin/deals).
+func (c *Client) ListDeals(ctx context.Context, opts DealListOptions) (*apitypes.DealListResponse, error) {
+	query := opts.ListOptions.query()
+	setString(query, "origin", opts.Origin)
+	setString(query, "destination", opts.Destination)
+	setString(query, "classification", opts.Classification)
+	setString(query, "status", opts.Status)
+
+	var resp apitypes.DealListResponse
+	if err := c.get(ctx, "/api/v1/admin/deals", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListDealAlerts returns the published deal alerts (GET /api/v1/admin/deal-alerts).
+func (c *Client) ListDealAlerts(ctx context.Context, opts ListOptions) (*apitypes.DealAlertListResponse, error) {
+	var resp apitypes.DealAlertListResponse
+	if err := c.get(ctx, "/api/v1/admin/deal-alerts", opts.query(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,195 @@
+// Package client is a typed Go client for the REST API of the flights server. Requests and
+// responses are the types of pkg/apitypes, which the handlers build too, so the client and the
+// server can't drift apart from the contract in docs/API_CONTRACT.md without breaking the
+// build. The client doesn't import the server packages.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config holds the configuration of a Client.
+type Config struct {
+	// BaseURL is the address of the server, e.g. http://localhost:8080.
+	BaseURL string
+	// Token authenticates admin requests with a Bearer token; Username and Password with basic
+	// auth instead. Public endpoints ignore them.
+	Token    string
+	Username string
+	Password string
+	// HTTPClient replaces the default client, which has a 60 second timeout.
+	HTTPClient *http.Client
+	// UserAgent is sent with every request if set.
+	UserAgent string
+}
+
+// Client calls the REST API of a flights server. It is safe for concurrent use.
+type Client struct {
+	config     Config
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+// APIError is returned for responses with a non-2xx status code.
+type APIError struct {
+	StatusCode int
+	// Message is the "error" field of the response, or its body if it has none.
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// New creates a client for the server at config.BaseURL.
+func New(config Config) (*Client, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("base URL is required")
+	}
+	baseURL, err := url.Parse(strings.TrimRight(config.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", config.BaseURL)
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 60 * time.Second}
+	}
+	return &Client{config: config, baseURL: baseURL, httpClient: httpClient}, nil
+}
+
+// get sends a GET request to path and decodes the response into out.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// post sends a POST request with in as JSON body, if not nil, and decodes the response into out.
+func (c *Client) post(ctx context.Context, path string, in, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, nil, in, out)
+}
+
+// put sends a PUT request with in as JSON body and decodes the response into out.
+func (c *Client) put(ctx context.Context, path string, in, out interface{}) error {
+	return c.do(ctx, http.MethodPut, path, nil, in, out)
+}
+
+// delete sends a DELETE request to path and decodes the response into out.
+func (c *Client) delete(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodDelete, path, nil, nil, out)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	// path is escaped (see pathf), so both forms of the URL path are set
+	endpoint := *c.baseURL
+	endpoint.RawPath = c.baseURL.EscapedPath() + path
+	unescaped, err := url.PathUnescape(endpoint.RawPath)
+	if err != nil {
+		return fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	endpoint.Path = unescaped
+	if len(query) > 0 {
+		endpoint.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.config.UserAgent != "" {
+		req.Header.Set("User-Agent", c.config.UserAgent)
+	}
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	} else if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s %s: failed to read response: %w", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp.StatusCode, respBody)
+	}
+	if out == nil || len(bytes.TrimSpace(respBody)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("%s %s: failed to decode response: %w", method, path, err)
+	}
+	return nil
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error != "" {
+		message = payload.Error
+	}
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return &APIError{StatusCode: statusCode, Message: message}
+}
+
+// pathf formats a path with escaped segments.
+func pathf(format string, segments ...interface{}) string {
+	escaped := make([]interface{}, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(fmt.Sprint(segment))
+	}
+	return fmt.Sprintf(format, escaped...)
+}
+
+// setInt adds a positive value to query.
+func setInt(query url.Values, key string, value int) {
+	if value > 0 {
+		query.Set(key, fmt.Sprint(value))
+	}
+}
+
+// setFloat adds a positive value to query.
+func setFloat(query url.Values, key string, value float64) {
+	if value > 0 {
+		query.Set(key, fmt.Sprint(value))
+	}
+}
+
+// setString adds a non-empty value to query.
+func setString(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gilby125/google-flights-api/api"
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/gilby125/google-flights-api/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.Handler, config Config) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config.BaseURL = server.URL + "/"
+	c, err := New(config)
+	require.NoError(t, err)
+	return c
+}
+
+func newTestRedisQueue(t *testing.T) *queue.RedisQueue {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, port, ok := strings.Cut(mr.Addr(), ":")
+	require.True(t, ok)
+
+	q, err := queue.NewRedisQueue(config.RedisConfig{
+		Host:                   host,
+		Port:                   port,
+		QueueGroup:             "test_group",
+		QueueStreamPrefix:      "test_stream",
+		QueueBlockTimeout:      50 * time.Millisecond,
+		QueueVisibilityTimeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	return q
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{})
+	assert.Error(t, err)
+
+	_, err = New(Config{BaseURL: "localhost:8080"})
+	assert.Error(t, err)
+
+	c, err := New(Config{BaseURL: "http://localhost:8080/"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", c.baseURL.String())
+}
+
+func TestClient_SearchAndQueueAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	q := newTestRedisQueue(t)
+
+	router := gin.New()
+	router.POST("/api/v1/search", api.CreateSearch(q))
+	router.GET("/api/v1/admin/queue", api.GetQueueStatus(q))
+	router.GET("/api/v1/admin/queue/:name/jobs", api.ListQueueJobs(q))
+	router.GET("/api/v1/admin/queue/:name/jobs/:id", api.GetQueueJob(q))
+	router.POST("/api/v1/admin/queue/:name/drain", api.DrainQueue(q))
+	c := newTestClient(t, router, Config{})
+	ctx := context.Background()
+
+	departure := time.Now().AddDate(0, 1, 0)
+	accepted, err := c.Search(ctx, apitypes.SearchRequest{
+		Origin:        "JFK",
+		Destination:   "LAX",
+		DepartureDate: apitypes.DateOnly{Time: departure},
+		ReturnDate:    apitypes.DateOnly{Time: departure.AddDate(0, 0, 7)},
+		Adults:        1,
+		TripType:      "round_trip",
+		Class:         "economy",
+		Stops:         "any",
+		Currency:      "usd",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, accepted.JobID)
+	assert.Equal(t, "Flight search job created successfully", accepted.Message)
+
+	stats, err := c.QueueStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats["flight_search"]["pending"])
+
+	jobs, err := c.ListQueueJobs(ctx, "flight_search", QueueJobListOptions{State: "pending"})
+	require.NoError(t, err)
+	require.Len(t, jobs.Jobs, 1)
+	assert.Equal(t, accepted.JobID, jobs.Jobs[0].ID)
+
+	job, err := c.GetQueueJob(ctx, "flight_search", accepted.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, "flight_search", job.Job.Type)
+
+	drained, err := c.DrainQueue(ctx, "flight_search")
+	require.NoError(t, err)
+	require.NotNil(t, drained.Cleared)
+	assert.Equal(t, int64(1), *drained.Cleared)
+	assert.Equal(t, int64(0), drained.Stats["pending"])
+}
+
+func TestClient_Graph(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	neo4jDB := new(mocks.MockNeo4jDatabase)
+	neo4jDB.On("FindCheapestPath", mock.Anything, "ORD", "LHR", 3, 800.0).Return([]db.PathResult{
+		{Stops: []string{"ORD", "JFK", "LHR"}, TotalPrice: 640},
+	}, nil)
+	neo4jDB.On("GetRouteStats", mock.Anything, "ORD", "XXX").Return(nil, nil)
+
+	router := gin.New()
+	router.GET("/api/v1/graph/path", api.GetCheapestPath(neo4jDB))
+	router.GET("/api/v1/graph/route-stats", api.GetRouteStats(neo4jDB))
+	c := newTestClient(t, router, Config{})
+	ctx := context.Background()
+
+	paths, err := c.CheapestPath(ctx, "ORD", "LHR", GraphSearchOptions{MaxHops: 3, MaxPrice: 800})
+	require.NoError(t, err)
+	assert.Equal(t, 3, paths.MaxHops)
+	require.Len(t, paths.Paths, 1)
+	assert.Equal(t, 640.0, paths.Paths[0].TotalPrice)
+
+	_, err = c.RouteStats(ctx, "ORD", "XXX")
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "no price data found for this route", apiErr.Message)
+	neo4jDB.AssertExpectations(t)
+}
+
+func TestClient_AuthAndQuery(t *testing.T) {
+	var gotAuth, gotQuery, gotPath string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotQuery = r.URL.RawQuery
+		gotPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"deals":[{"id":7,"origin":"JFK","destination":"CDG","price":312.5,"deal_score":88,"discount_percent":null}],"count":1}`))
+	})
+
+	c := newTestClient(t, handler, Config{Token: "secret"})
+	deals, err := c.ListDeals(context.Background(), DealListOptions{Origin: "JFK", ListOptions: ListOptions{Limit: 10}})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", gotAuth)
+	assert.Equal(t, "/api/v1/admin/deals", gotPath)
+	assert.Equal(t, "limit=10&origin=JFK", gotQuery)
+	require.Len(t, deals.Deals, 1)
+	require.NotNil(t, deals.Deals[0].DealScore)
+	assert.Equal(t, 88, *deals.Deals[0].DealScore)
+	assert.Nil(t, deals.Deals[0].DiscountPercent)
+
+	c = newTestClient(t, handler, Config{Username: "admin", Password: "pw"})
+	_, err = c.ContinuousSweepStatus(context.Background())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(gotAuth, "Basic "))
+
+	_, err = c.GetQueueJob(context.Background(), "flight_search", "a/b")
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/admin/queue/flight_search/jobs/a%2Fb", gotPath)
+}
+
+func TestClient_ErrorWithoutJSON(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+	})
+
+	c := newTestClient(t, handler, Config{})
+	_, err := c.ListSearches(context.Background(), PageOptions{})
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, "upstream unavailable", apiErr.Message)
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+)
+
+// ContinuousSweepStatus returns the state of the continuous sweep
+// (GET /api/v1/admin/continuous-sweep/status).
+func (c *Client) ContinuousSweepStatus(ctx context.Context) (*apitypes.ContinuousSweepStatusResponse, error) {
+	var resp apitypes.ContinuousSweepStatusResponse
+	if err := c.get(ctx, "/api/v1/admin/continuous-sweep/status", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StartContinuousSweep starts the continuous sweep (POST /api/v1/admin/continuous-sweep/start).
+func (c *Client) StartContinuousSweep(ctx context.Context) (*apitypes.ContinuousSweepActionResponse, error) {
+	return c.continuousSweepAction(ctx, "start")
+}
+
+// StopContinuousSweep stops the continuous sweep and drains its queue
+// (POST /api/v1/admin/continuous-sweep/stop).
+func (c *Client) StopContinuousSweep(ctx context.Context) (*apitypes.ContinuousSweepActionResponse, error) {
+	return c.continuousSweepAction(ctx, "stop")
+}
+
+// PauseContinuousSweep pauses the continuous sweep (POST /api/v1/admin/continuous-sweep/pause).
+func (c *Client) PauseContinuousSweep(ctx context.Context) (*apitypes.ContinuousSweepActionResponse, error) {
+	return c.continuousSweepAction(ctx, "pause")
+}
+
+// ResumeContinuousSweep resumes the continuous sweep (POST /api/v1/admin/continuous-sweep/resume).
+func (c *Client) ResumeContinuousSweep(ctx context.Context) (*apitypes.ContinuousSweepActionResponse, error) {
+	return c.continuousSweepAction(ctx, "resume")
+}
+
+// SkipContinuousSweepRoute skips the current route of the continuous sweep
+// (POST /api/v1/admin/continuous-sweep/skip).
+func (c *Client) SkipContinuousSweepRoute(ctx context.Context) (*apitypes.ContinuousSweepActionResponse, error) {
+	return c.continuousSweepAction(ctx, "skip")
+}
+
+// RestartContinuousSweep restarts the current sweep from its first route
+// (POST /api/v1/admin/continuous-sweep/restart).
+func (c *Client) RestartContinuousSweep(ctx context.Context) (*apitypes.ContinuousSweepActionResponse, error) {
+	return c.continuousSweepAction(ctx, "restart")
+}
+
+// UpdateContinuousSweepConfig changes the configuration of the continuous sweep; zero fields are
+// left unchanged (PUT /api/v1/admin/continuous-sweep/config).
+func (c *Client) UpdateContinuousSweepConfig(ctx context.Context, req apitypes.SweepConfigRequest) (*apitypes.ContinuousSweepActionResponse, error) {
+	var resp apitypes.ContinuousSweepActionResponse
+	if err := c.put(ctx, "/api/v1/admin/continuous-sweep/config", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ContinuousSweepStats returns the statistics of the last limit sweeps; 0 uses the server
+// default (GET /api/v1/admin/continuous-sweep/stats).
+func (c *Client) ContinuousSweepStats(ctx context.Context, limit int) (*apitypes.ContinuousSweepStatsListResponse, error) {
+	query := url.Values{}
+	setInt(query, "limit", limit)
+
+	var resp apitypes.ContinuousSweepStatsListResponse
+	if err := c.get(ctx, "/api/v1/admin/continuous-sweep/stats", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ContinuousSweepResultsOptions filters the results of the continuous sweep; From and To bound
+// the departure dates if not zero.
+type ContinuousSweepResultsOptions struct {
+	ListOptions
+	Origin      string
+	Destination string
+	From        time.Time
+	To          time.Time
+}
+
+// ContinuousSweepResults returns the fares found by the continuous sweep
+// (GET /api/v1/admin/continuous-sweep/results).
+func (c *Client) ContinuousSweepResults(ctx context.Context, opts ContinuousSweepResultsOptions) (*apitypes.ContinuousSweepResultsResponse, error) {
+	query := opts.ListOptions.query()
+	setString(query, "origin", opts.Origin)
+	setString(query, "destination", opts.Destination)
+	if !opts.From.IsZero() {
+		query.Set("from", opts.From.Format("2006-01-02"))
+	}
+	if !opts.To.IsZero() {
+		query.Set("to", opts.To.Format("2006-01-02"))
+	}
+
+	var resp apitypes.ContinuousSweepResultsResponse
+	if err := c.get(ctx, "/api/v1/admin/continuous-sweep/results", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) continuousSweepAction(ctx context.Context, action string) (*apitypes.ContinuousSweepActionResponse, error) {
+	var resp apitypes.ContinuousSweepActionResponse
+	if err := c.post(ctx, "/api/v1/admin/continuous-sweep/"+action, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
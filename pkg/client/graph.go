@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+)
+
+// GraphSearchOptions limits the routes of the graph traversals; zero values use the server
+// defaults.
+type GraphSearchOptions struct {
+	MaxHops  int
+	MaxPrice float64
+}
+
+func (o GraphSearchOptions) query() url.Values {
+	query := url.Values{}
+	setInt(query, "maxHops", o.MaxHops)
+	setFloat(query, "maxPrice", o.MaxPrice)
+	return query
+}
+
+// CheapestPath returns the cheapest multi-hop routes between two airports
+// (GET /api/v1/graph/path).
+func (c *Client) CheapestPath(ctx context.Context, origin, dest string, opts GraphSearchOptions) (*apitypes.CheapestPathResponse, error) {
+	query := opts.query()
+	query.Set("origin", origin)
+	query.Set("dest", dest)
+
+	var resp apitypes.CheapestPathResponse
+	if err := c.get(ctx, "/api/v1/graph/path", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Connections returns the destinations reachable from an origin (GET /api/v1/graph/connections).
+func (c *Client) Connections(ctx context.Context, origin string, opts GraphSearchOptions) (*apitypes.ConnectionsResponse, error) {
+	query := opts.query()
+	query.Set("origin", origin)
+
+	var resp apitypes.ConnectionsResponse
+	if err := c.get(ctx, "/api/v1/graph/connections", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RouteStats returns the price statistics of a route (GET /api/v1/graph/route-stats).
+func (c *Client) RouteStats(ctx context.Context, origin, dest string) (*apitypes.RouteStats, error) {
+	query := url.Values{}
+	query.Set("origin", origin)
+	query.Set("dest", dest)
+
+	var resp apitypes.RouteStats
+	if err := c.get(ctx, "/api/v1/graph/route-stats", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RouteDetailsOptions filters the prices of GET /api/v1/graph/route-details.
+type RouteDetailsOptions struct {
+	DateFrom     string // YYYY-MM-DD
+	DateTo       string // YYYY-MM-DD
+	TripType     string
+	MaxAgeDays   int
+	LimitSamples int
+	Airlines     []string
+	// ExcludeAirlines replaces the server's excluded airlines if not nil; an empty slice
+	// excludes none.
+	ExcludeAirlines []string
+}
+
+// RouteDetails returns the price statistics and samples of a route, with filters
+// (GET /api/v1/graph/route-details).
+func (c *Client) RouteDetails(ctx context.Context, origin, dest string, opts RouteDetailsOptions) (*apitypes.RouteStats, error) {
+	query := url.Values{}
+	query.Set("origin", origin)
+	query.Set("dest", dest)
+	setString(query, "dateFrom", opts.DateFrom)
+	setString(query, "dateTo", opts.DateTo)
+	setString(query, "tripType", opts.TripType)
+	setInt(query, "maxAgeDays", opts.MaxAgeDays)
+	setInt(query, "limitSamples", opts.LimitSamples)
+	setString(query, "airlines", strings.Join(opts.Airlines, ","))
+	if opts.ExcludeAirlines != nil {
+		query.Set("excludeAirlines", strings.Join(opts.ExcludeAirlines, ","))
+	}
+
+	var resp apitypes.RouteStats
+	if err := c.get(ctx, "/api/v1/graph/route-details", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExploreOptions selects the routes of GET /api/v1/graph/explore; Origins must have at least one
+// airport.
+type ExploreOptions struct {
+	Origins         []string
+	MaxHops         int
+	MaxPrice        float64
+	DateFrom        string // YYYY-MM-DD
+	DateTo          string // YYYY-MM-DD
+	Source          string
+	MaxAgeDays      int
+	TripType        string
+	Class           string
+	Limit           int
+	Airlines        []string
+	ExcludeAirlines []string
+}
+
+// Explore returns the routes from one or more origins for map UIs (GET /api/v1/graph/explore).
+func (c *Client) Explore(ctx context.Context, opts ExploreOptions) (*apitypes.ExploreResponse, error) {
+	query := url.Values{}
+	setString(query, "origins", strings.Join(opts.Origins, ","))
+	setInt(query, "maxHops", opts.MaxHops)
+	setFloat(query, "maxPrice", opts.MaxPrice)
+	setString(query, "dateFrom", opts.DateFrom)
+	setString(query, "dateTo", opts.DateTo)
+	setString(query, "source", opts.Source)
+	setInt(query, "maxAgeDays", opts.MaxAgeDays)
+	setString(query, "tripType", opts.TripType)
+	setString(query, "class", opts.Class)
+	setInt(query, "limit", opts.Limit)
+	setString(query, "airlines", strings.Join(opts.Airlines, ","))
+	setString(query, "excludeAirlines", strings.Join(opts.ExcludeAirlines, ","))
+
+	var resp apitypes.ExploreResponse
+	if err := c.get(ctx, "/api/v1/graph/explore", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+)
+
+// QueueStats returns the job counters of every queue by state (GET /api/v1/admin/queue).
+func (c *Client) QueueStats(ctx context.Context) (map[string]map[string]int64, error) {
+	var resp map[string]map[string]int64
+	if err := c.get(ctx, "/api/v1/admin/queue", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueueBacklog returns up to limit unacked jobs of a queue; 0 uses the server default
+// (GET /api/v1/admin/queue/:name/backlog).
+func (c *Client) QueueBacklog(ctx context.Context, queueName string, limit int) (*apitypes.QueueBacklogResponse, error) {
+	query := url.Values{}
+	setInt(query, "limit", limit)
+
+	var resp apitypes.QueueBacklogResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/queue/%s/backlog", queueName), query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// QueueJobListOptions selects the jobs of a queue; State defaults to failed on the server.
+type QueueJobListOptions struct {
+	ListOptions
+	State string
+}
+
+// ListQueueJobs returns the jobs of a queue in a state (GET /api/v1/admin/queue/:name/jobs).
+func (c *Client) ListQueueJobs(ctx context.Context, queueName string, opts QueueJobListOptions) (*apitypes.QueueJobListResponse, error) {
+	query := opts.ListOptions.query()
+	setString(query, "state", opts.State)
+
+	var resp apitypes.QueueJobListResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/queue/%s/jobs", queueName), query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetQueueJob returns a job of a queue (GET /api/v1/admin/queue/:name/jobs/:id).
+func (c *Client) GetQueueJob(ctx context.Context, queueName, jobID string) (*apitypes.QueueJobResponse, error) {
+	var resp apitypes.QueueJobResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/queue/%s/jobs/%s", queueName, jobID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelQueueJob requests the cancellation of a job (POST /api/v1/admin/queue/:name/jobs/:id/cancel).
+func (c *Client) CancelQueueJob(ctx context.Context, queueName, jobID string) (*apitypes.QueueCancelJobResponse, error) {
+	var resp apitypes.QueueCancelJobResponse
+	if err := c.post(ctx, pathf("/api/v1/admin/queue/%s/jobs/%s/cancel", queueName, jobID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// QueueEnqueueMetrics returns the enqueue counts of a queue by source over the last minutes; 0
+// uses the server default (GET /api/v1/admin/queue/:name/enqueues).
+func (c *Client) QueueEnqueueMetrics(ctx context.Context, queueName string, minutes int) (*apitypes.QueueEnqueueMetricsResponse, error) {
+	query := url.Values{}
+	setInt(query, "minutes", minutes)
+
+	var resp apitypes.QueueEnqueueMetricsResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/queue/%s/enqueues", queueName), query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DrainQueue cancels the processing jobs of a queue and clears its pending jobs
+// (POST /api/v1/admin/queue/:name/drain).
+func (c *Client) DrainQueue(ctx context.Context, queueName string) (*apitypes.QueueActionResponse, error) {
+	return c.queueAction(ctx, queueName, "drain", nil)
+}
+
+// ClearQueue clears the pending jobs of a queue (POST /api/v1/admin/queue/:name/clear).
+func (c *Client) ClearQueue(ctx context.Context, queueName string) (*apitypes.QueueActionResponse, error) {
+	return c.queueAction(ctx, queueName, "clear", nil)
+}
+
+// ClearQueueFailed clears the failed jobs of a queue (POST /api/v1/admin/queue/:name/clear-failed).
+func (c *Client) ClearQueueFailed(ctx context.Context, queueName string) (*apitypes.QueueActionResponse, error) {
+	return c.queueAction(ctx, queueName, "clear-failed", nil)
+}
+
+// ClearQueueProcessing clears the processing jobs of a queue
+// (POST /api/v1/admin/queue/:name/clear-processing).
+func (c *Client) ClearQueueProcessing(ctx context.Context, queueName string) (*apitypes.QueueActionResponse, error) {
+	return c.queueAction(ctx, queueName, "clear-processing", nil)
+}
+
+// CancelQueueProcessing requests the cancellation of the processing jobs of a queue
+// (POST /api/v1/admin/queue/:name/cancel-processing).
+func (c *Client) CancelQueueProcessing(ctx context.Context, queueName string) (*apitypes.QueueActionResponse, error) {
+	return c.queueAction(ctx, queueName, "cancel-processing", nil)
+}
+
+// RetryQueueFailed re-enqueues up to limit failed jobs of a queue; 0 uses the server default
+// (POST /api/v1/admin/queue/:name/retry-failed).
+func (c *Client) RetryQueueFailed(ctx context.Context, queueName string, limit int) (*apitypes.QueueActionResponse, error) {
+	query := url.Values{}
+	setInt(query, "limit", limit)
+	return c.queueAction(ctx, queueName, "retry-failed", query)
+}
+
+// DeadLetterListOptions selects the dead letters of a queue; an empty ErrorClass lists all.
+type DeadLetterListOptions struct {
+	ListOptions
+	ErrorClass string
+}
+
+// ListDeadLetters returns the jobs of a queue that ran out of attempts, most recent first
+// (GET /api/v1/admin/queue/:name/dead-letters).
+func (c *Client) ListDeadLetters(ctx context.Context, queueName string, opts DeadLetterListOptions) (*apitypes.QueueDeadLetterListResponse, error) {
+	query := opts.ListOptions.query()
+	setString(query, "error_class", opts.ErrorClass)
+
+	var resp apitypes.QueueDeadLetterListResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/queue/%s/dead-letters", queueName), query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetDeadLetter returns the dead letter of a job (GET /api/v1/admin/queue/:name/dead-letters/:id).
+func (c *Client) GetDeadLetter(ctx context.Context, queueName, jobID string) (*apitypes.QueueDeadLetterResponse, error) {
+	var resp apitypes.QueueDeadLetterResponse
+	if err := c.get(ctx, pathf("/api/v1/admin/queue/%s/dead-letters/%s", queueName, jobID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateDeadLetterPayload replaces the payload a dead letter is replayed with
+// (PUT /api/v1/admin/queue/:name/dead-letters/:id/payload).
+func (c *Client) UpdateDeadLetterPayload(ctx context.Context, queueName, jobID string, payload json.RawMessage) (*apitypes.QueueDeadLetterResponse, error) {
+	var resp apitypes.QueueDeadLetterResponse
+	req := apitypes.DeadLetterPayloadRequest{Payload: payload}
+	if err := c.put(ctx, pathf("/api/v1/admin/queue/%s/dead-letters/%s/payload", queueName, jobID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReplayDeadLetters re-enqueues the dead letters of a queue selected by req
+// (POST /api/v1/admin/queue/:name/dead-letters/replay).
+func (c *Client) ReplayDeadLetters(ctx context.Context, queueName string, req apitypes.DeadLetterReplayRequest) (*apitypes.QueueDeadLetterReplayResponse, error) {
+	var resp apitypes.QueueDeadLetterReplayResponse
+	if err := c.post(ctx, pathf("/api/v1/admin/queue/%s/dead-letters/replay", queueName), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) queueAction(ctx context.Context, queueName, action string, query url.Values) (*apitypes.QueueActionResponse, error) {
+	var resp apitypes.QueueActionResponse
+	if err := c.do(ctx, http.MethodPost, pathf("/api/v1/admin/queue/%s/", queueName)+action, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
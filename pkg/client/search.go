@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+)
+
+// PageOptions selects a page of a paginated list; zero values use the server defaults.
+type PageOptions struct {
+	Page    int
+	PerPage int
+}
+
+func (o PageOptions) query() url.Values {
+	query := url.Values{}
+	setInt(query, "page", o.Page)
+	setInt(query, "per_page", o.PerPage)
+	return query
+}
+
+// ListOptions selects a window of a list; zero values use the server defaults.
+type ListOptions struct {
+	Limit  int
+	Offset int
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	setInt(query, "limit", o.Limit)
+	setInt(query, "offset", o.Offset)
+	return query
+}
+
+// Search enqueues a flight search (POST /api/v1/search).
+func (c *Client) Search(ctx context.Context, req apitypes.SearchRequest) (*apitypes.JobAcceptedResponse, error) {
+	var resp apitypes.JobAcceptedResponse
+	if err := c.post(ctx, "/api/v1/search", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DirectSearch searches flights and returns the offers right away (POST /api/search). Searches
+// whose airports expand to several routes return Routes instead of Offers.
+func (c *Client) DirectSearch(ctx context.Context, req apitypes.DirectSearchRequest) (*apitypes.DirectSearchResponse, error) {
+	var resp apitypes.DirectSearchResponse
+	if err := c.post(ctx, "/api/search", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSearch returns a stored search with its offers (GET /api/v1/search/:id).
+func (c *Client) GetSearch(ctx context.Context, id int) (*apitypes.SearchResponse, error) {
+	var resp apitypes.SearchResponse
+	if err := c.get(ctx, pathf("/api/v1/search/%s", id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListSearches returns a page of the recent searches (GET /api/v1/search).
+func (c *Client) ListSearches(ctx context.Context, opts PageOptions) (*apitypes.SearchListResponse, error) {
+	var resp apitypes.SearchListResponse
+	if err := c.get(ctx, "/api/v1/search", opts.query(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FlexDates returns the price grid of a range of departure dates and trip lengths
+// (POST /api/v1/flex-dates).
+func (c *Client) FlexDates(ctx context.Context, req apitypes.FlexDateSearchRequest) (*apitypes.FlexDateMatrix, error) {
+	var resp apitypes.FlexDateMatrix
+	if err := c.post(ctx, "/api/v1/flex-dates", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BulkSearch enqueues a bulk search over routes and dates (POST /api/v1/bulk-search).
+func (c *Client) BulkSearch(ctx context.Context, req apitypes.BulkSearchRequest) (*apitypes.BulkSearchAcceptedResponse, error) {
+	var resp apitypes.BulkSearchAcceptedResponse
+	if err := c.post(ctx, "/api/v1/bulk-search", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetBulkSearch returns the progress and a page of the results of a bulk search
+// (GET /api/v1/bulk-search/:id).
+func (c *Client) GetBulkSearch(ctx context.Context, id int, opts PageOptions) (*apitypes.BulkSearchResponse, error) {
+	var resp apitypes.BulkSearchResponse
+	if err := c.get(ctx, pathf("/api/v1/bulk-search/%s", id), opts.query(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PriceHistoryOptions filters the price history by airline groups or codes.
+type PriceHistoryOptions struct {
+	IncludeAirlineGroups []string
+	ExcludeAirlineGroups []string
+}
+
+// PriceHistory returns the price points of a route in the graph
+// (GET /api/v1/price-history/:origin/:destination).
+func (c *Client) PriceHistory(ctx context.Context, origin, destination string, opts PriceHistoryOptions) (*apitypes.PriceHistoryResponse, error) {
+	query := url.Values{}
+	for _, group := range opts.IncludeAirlineGroups {
+		query.Add("include_airline_groups", group)
+	}
+	for _, group := range opts.ExcludeAirlineGroups {
+		query.Add("exclude_airline_groups", group)
+	}
+
+	var resp apitypes.PriceHistoryResponse
+	if err := c.get(ctx, pathf("/api/v1/price-history/%s/%s", origin, destination), query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
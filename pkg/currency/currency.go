@@ -0,0 +1,253 @@
+// Package currency converts prices between currencies with daily exchange rate snapshots. Rates come
+// from a chain of [Provider]s (a CSV seed file, an HTTP rate source) and every snapshot fetched from
+// them is stored in Postgres, so historical prices are always normalized with the rates of their day.
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gilby125/google-flights-api/db"
+)
+
+// DefaultReportingCurrency is the currency prices are normalized to when none is configured.
+const DefaultReportingCurrency = "USD"
+
+// DefaultMaxStaleness is how old a stored snapshot may be when no provider has rates for the day.
+const DefaultMaxStaleness = 7 * 24 * time.Hour
+
+// DefaultFailureTTL is how long a converter remembers that no provider had rates for a day, so a
+// batch of prices of that day doesn't query the store and every provider once per price.
+const DefaultFailureTTL = 5 * time.Minute
+
+// ErrNoRates is returned when there are no exchange rates for a day or a currency.
+var ErrNoRates = errors.New("currency: no exchange rates")
+
+// Snapshot holds the exchange rates in effect on one day: one unit of Base costs Rates[code] units
+// of code.
+type Snapshot struct {
+	Date   time.Time
+	Base   string
+	Rates  map[string]float64
+	Source string
+}
+
+func (s *Snapshot) rate(code string) (float64, bool) {
+	if code == s.Base {
+		return 1, true
+	}
+	rate, ok := s.Rates[code]
+	return rate, ok && rate > 0
+}
+
+// Convert converts amount from one currency to another, crossing through the base currency of the
+// snapshot.
+func (s *Snapshot) Convert(amount float64, from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return amount, nil
+	}
+	fromRate, ok := s.rate(from)
+	if !ok {
+		return 0, fmt.Errorf("%w for %s on %s", ErrNoRates, from, s.Date.Format("2006-01-02"))
+	}
+	toRate, ok := s.rate(to)
+	if !ok {
+		return 0, fmt.Errorf("%w for %s on %s", ErrNoRates, to, s.Date.Format("2006-01-02"))
+	}
+	return amount / fromRate * toRate, nil
+}
+
+// Provider is a source of exchange rates.
+type Provider interface {
+	// Snapshot returns the rates in effect on day: the rates published that day or, if there are
+	// none (weekends, holidays), the latest ones published before it. It returns [ErrNoRates] if
+	// it has no rates for the day.
+	Snapshot(ctx context.Context, day time.Time) (*Snapshot, error)
+}
+
+// Store persists the daily snapshots.
+type Store interface {
+	// GetFXRates returns the latest snapshot stored on or before day.
+	GetFXRates(ctx context.Context, day time.Time) ([]db.FXRate, error)
+	UpsertFXRates(ctx context.Context, rates []db.FXRate) error
+}
+
+// Converter normalizes prices to a reporting currency. A snapshot is looked up in memory, then in
+// the store and then in the providers, in order; snapshots fetched from a provider are written back
+// to the store under the day they were requested for. A day no provider had rates for is not looked
+// up again for [DefaultFailureTTL].
+type Converter struct {
+	reporting    string
+	store        Store
+	providers    []Provider
+	maxStaleness time.Duration
+	failureTTL   time.Duration
+
+	mu     sync.Mutex
+	cache  map[string]*Snapshot
+	failed map[string]failedLookup
+}
+
+// failedLookup is a day no provider had rates for. fallback is the stale stored snapshot used
+// instead, if any.
+type failedLookup struct {
+	fallback *Snapshot
+	err      error
+	until    time.Time
+}
+
+// NewConverter creates a converter to the reporting currency. store may be nil.
+func NewConverter(reporting string, store Store, providers ...Provider) *Converter {
+	reporting = strings.ToUpper(strings.TrimSpace(reporting))
+	if reporting == "" {
+		reporting = DefaultReportingCurrency
+	}
+	return &Converter{
+		reporting:    reporting,
+		store:        store,
+		providers:    providers,
+		maxStaleness: DefaultMaxStaleness,
+		failureTTL:   DefaultFailureTTL,
+		cache:        make(map[string]*Snapshot),
+		failed:       make(map[string]failedLookup),
+	}
+}
+
+// Reporting returns the reporting currency.
+func (c *Converter) Reporting() string {
+	return c.reporting
+}
+
+// Convert converts amount from one currency to another with the rates in effect on day.
+func (c *Converter) Convert(ctx context.Context, amount float64, from, to string, day time.Time) (float64, error) {
+	if strings.EqualFold(from, to) {
+		return amount, nil
+	}
+	snapshot, err := c.Snapshot(ctx, day)
+	if err != nil {
+		return 0, err
+	}
+	return snapshot.Convert(amount, from, to)
+}
+
+// Normalize converts amount to the reporting currency with the rates in effect on day. The result
+// is rounded to cents.
+func (c *Converter) Normalize(ctx context.Context, amount float64, from string, day time.Time) (float64, error) {
+	converted, err := c.Convert(ctx, amount, from, c.reporting, day)
+	if err != nil {
+		return 0, err
+	}
+	return math.Round(converted*100) / 100, nil
+}
+
+// Snapshot returns the rates in effect on day.
+func (c *Converter) Snapshot(ctx context.Context, day time.Time) (*Snapshot, error) {
+	day = truncateDay(day)
+	key := day.Format("2006-01-02")
+
+	c.mu.Lock()
+	cached := c.cache[key]
+	failed, isFailed := c.failed[key]
+	c.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+	if isFailed && time.Now().Before(failed.until) {
+		if failed.fallback != nil {
+			return failed.fallback, nil
+		}
+		return nil, failed.err
+	}
+
+	var stored *Snapshot
+	if c.store != nil {
+		rates, err := c.store.GetFXRates(ctx, day)
+		if err != nil {
+			log.Printf("Warning: failed to load FX rates for %s: %v", key, err)
+		} else if len(rates) > 0 {
+			stored = snapshotFromRates(rates)
+			if stored.Date.Equal(day) {
+				c.remember(key, stored)
+				return stored, nil
+			}
+		}
+	}
+
+	var lastErr error = ErrNoRates
+	for _, provider := range c.providers {
+		snapshot, err := provider.Snapshot(ctx, day)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		snapshot = &Snapshot{Date: day, Base: snapshot.Base, Rates: snapshot.Rates, Source: snapshot.Source}
+		if c.store != nil {
+			if err := c.store.UpsertFXRates(ctx, snapshot.rates()); err != nil {
+				log.Printf("Warning: failed to store FX rates for %s: %v", key, err)
+			}
+		}
+		c.remember(key, snapshot)
+		return snapshot, nil
+	}
+
+	if stored == nil || day.Sub(stored.Date) > c.maxStaleness {
+		stored = nil
+	}
+	err := fmt.Errorf("rates for %s: %w", key, lastErr)
+	if ctx.Err() == nil {
+		c.mu.Lock()
+		c.failed[key] = failedLookup{fallback: stored, err: err, until: time.Now().Add(c.failureTTL)}
+		c.mu.Unlock()
+	}
+	if stored != nil {
+		return stored, nil
+	}
+	return nil, err
+}
+
+func (c *Converter) remember(key string, snapshot *Snapshot) {
+	c.mu.Lock()
+	c.cache[key] = snapshot
+	delete(c.failed, key)
+	c.mu.Unlock()
+}
+
+func (s *Snapshot) rates() []db.FXRate {
+	rates := make([]db.FXRate, 0, len(s.Rates))
+	for code, rate := range s.Rates {
+		rates = append(rates, db.FXRate{
+			RateDate:      s.Date,
+			BaseCurrency:  s.Base,
+			QuoteCurrency: code,
+			Rate:          rate,
+			Source:        s.Source,
+		})
+	}
+	return rates
+}
+
+func snapshotFromRates(rates []db.FXRate) *Snapshot {
+	snapshot := &Snapshot{
+		Date:   truncateDay(rates[0].RateDate),
+		Base:   rates[0].BaseCurrency,
+		Rates:  make(map[string]float64, len(rates)),
+		Source: rates[0].Source,
+	}
+	for _, r := range rates {
+		if r.BaseCurrency == snapshot.Base {
+			snapshot.Rates[r.QuoteCurrency] = r.Rate
+		}
+	}
+	return snapshot
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,149 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCSV = `date,base,quote,rate
+2025-01-02,EUR,USD,1.04
+2025-01-02,EUR,GBP,0.83
+# weekend gap
+2025-01-06,EUR,USD,1.03
+2025-01-06,EUR,GBP,0.83
+`
+
+type memoryStore struct {
+	rates []db.FXRate
+}
+
+func (s *memoryStore) GetFXRates(ctx context.Context, day time.Time) ([]db.FXRate, error) {
+	var latest time.Time
+	for _, r := range s.rates {
+		if !r.RateDate.After(day) && r.RateDate.After(latest) {
+			latest = r.RateDate
+		}
+	}
+	var rates []db.FXRate
+	for _, r := range s.rates {
+		if r.RateDate.Equal(latest) {
+			rates = append(rates, r)
+		}
+	}
+	return rates, nil
+}
+
+func (s *memoryStore) UpsertFXRates(ctx context.Context, rates []db.FXRate) error {
+	s.rates = append(s.rates, rates...)
+	return nil
+}
+
+func date(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestFileProviderSnapshot(t *testing.T) {
+	provider, err := ParseCSV(strings.NewReader(testCSV), "test")
+	require.NoError(t, err)
+
+	snapshot, err := provider.Snapshot(context.Background(), date("2025-01-04"))
+	require.NoError(t, err)
+	assert.Equal(t, date("2025-01-02"), snapshot.Date)
+	assert.Equal(t, 1.04, snapshot.Rates["USD"])
+
+	_, err = provider.Snapshot(context.Background(), date("2024-12-31"))
+	assert.ErrorIs(t, err, ErrNoRates)
+
+	_, err = ParseCSV(strings.NewReader("2025-01-02,EUR,USD,1.04\n2025-01-02,USD,GBP,0.8\n"), "test")
+	assert.Error(t, err)
+}
+
+func TestConverterNormalize(t *testing.T) {
+	provider, err := ParseCSV(strings.NewReader(testCSV), "test")
+	require.NoError(t, err)
+	store := &memoryStore{}
+	converter := NewConverter("usd", store, provider)
+	ctx := context.Background()
+
+	// Cross rate through the EUR base.
+	usd, err := converter.Normalize(ctx, 83, "GBP", date("2025-01-03"))
+	require.NoError(t, err)
+	assert.Equal(t, 104.0, usd)
+
+	// The snapshot of the day was stored.
+	require.Len(t, store.rates, 2)
+	assert.Equal(t, date("2025-01-03"), store.rates[0].RateDate)
+
+	same, err := converter.Normalize(ctx, 10, "USD", date("2025-01-03"))
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, same)
+
+	_, err = converter.Normalize(ctx, 10, "JPY", date("2025-01-03"))
+	assert.ErrorIs(t, err, ErrNoRates)
+}
+
+func TestConverterFallsBackToStore(t *testing.T) {
+	store := &memoryStore{rates: []db.FXRate{
+		{RateDate: date("2025-01-02"), BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: 1.25},
+	}}
+	converter := NewConverter("USD", store)
+	ctx := context.Background()
+
+	usd, err := converter.Normalize(ctx, 100, "EUR", date("2025-01-05"))
+	require.NoError(t, err)
+	assert.Equal(t, 125.0, usd)
+
+	_, err = converter.Normalize(ctx, 100, "EUR", date("2025-02-05"))
+	assert.ErrorIs(t, err, ErrNoRates)
+}
+
+type failingProvider struct {
+	calls int
+}
+
+func (p *failingProvider) Snapshot(ctx context.Context, day time.Time) (*Snapshot, error) {
+	p.calls++
+	return nil, ErrNoRates
+}
+
+func TestConverterRemembersFailedLookups(t *testing.T) {
+	provider := &failingProvider{}
+	converter := NewConverter("USD", nil, provider)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := converter.Normalize(ctx, 100, "EUR", date("2025-01-05"))
+		assert.ErrorIs(t, err, ErrNoRates)
+	}
+	assert.Equal(t, 1, provider.calls, "a failed day shouldn't be looked up again within the TTL")
+
+	failed := converter.failed["2025-01-05"]
+	failed.until = time.Now() // the TTL has passed
+	converter.failed["2025-01-05"] = failed
+	_, err := converter.Normalize(ctx, 100, "EUR", date("2025-01-05"))
+	assert.ErrorIs(t, err, ErrNoRates)
+	assert.Equal(t, 2, provider.calls, "a failed day should be looked up again after the TTL")
+}
+
+func TestHTTPProviderSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2025-01-04", r.URL.Path)
+		assert.Equal(t, "EUR", r.URL.Query().Get("base"))
+		_, _ = w.Write([]byte(`{"amount":1.0,"base":"EUR","date":"2025-01-03","rates":{"USD":1.03}}`))
+	}))
+	defer server.Close()
+
+	snapshot, err := NewHTTPProvider(server.URL).Snapshot(context.Background(), date("2025-01-04"))
+	require.NoError(t, err)
+	assert.Equal(t, date("2025-01-03"), snapshot.Date)
+	assert.Equal(t, 1.03, snapshot.Rates["USD"])
+}
@@ -0,0 +1,101 @@
+package currency
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileProvider serves rates from a CSV seed. Each row is one rate:
+//
+//	date,base,quote,rate
+//	2025-01-02,EUR,USD,1.0354
+//
+// A header row is skipped. All rows of a date must share the same base currency.
+type FileProvider struct {
+	snapshots []*Snapshot // sorted by date
+}
+
+// NewFileProvider loads the CSV seed at path.
+func NewFileProvider(path string) (*FileProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open FX rates file: %w", err)
+	}
+	defer f.Close()
+
+	provider, err := ParseCSV(f, "file:"+path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return provider, nil
+}
+
+// ParseCSV reads a CSV seed. source is recorded as the source of the snapshots.
+func ParseCSV(r io.Reader, source string) (*FileProvider, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	byDate := make(map[string]*Snapshot)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse FX rates: %w", err)
+		}
+		if line == 1 && strings.EqualFold(record[0], "date") {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, record[0])
+		}
+		base, quote := strings.ToUpper(record[1]), strings.ToUpper(record[2])
+		rate, err := strconv.ParseFloat(record[3], 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, record[3])
+		}
+
+		snapshot, ok := byDate[record[0]]
+		if !ok {
+			snapshot = &Snapshot{Date: date, Base: base, Rates: make(map[string]float64), Source: source}
+			byDate[record[0]] = snapshot
+		}
+		if snapshot.Base != base {
+			return nil, fmt.Errorf("line %d: base %s differs from %s of %s", line, base, snapshot.Base, record[0])
+		}
+		snapshot.Rates[quote] = rate
+	}
+
+	provider := &FileProvider{snapshots: make([]*Snapshot, 0, len(byDate))}
+	for _, snapshot := range byDate {
+		provider.snapshots = append(provider.snapshots, snapshot)
+	}
+	sort.Slice(provider.snapshots, func(i, j int) bool {
+		return provider.snapshots[i].Date.Before(provider.snapshots[j].Date)
+	})
+	return provider, nil
+}
+
+// Snapshot implements [Provider].
+func (p *FileProvider) Snapshot(ctx context.Context, day time.Time) (*Snapshot, error) {
+	day = truncateDay(day)
+	i := sort.Search(len(p.snapshots), func(i int) bool {
+		return p.snapshots[i].Date.After(day)
+	})
+	if i == 0 {
+		return nil, ErrNoRates
+	}
+	return p.snapshots[i-1], nil
+}
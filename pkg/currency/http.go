@@ -0,0 +1,69 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPProvider fetches rates from an ECB-style JSON API such as Frankfurter:
+//
+//	GET {BaseURL}/2025-01-02?base=EUR
+//	{"base":"EUR","date":"2025-01-02","rates":{"USD":1.0354,...}}
+type HTTPProvider struct {
+	BaseURL string
+	Base    string // base currency requested from the API (default EUR)
+	Client  *http.Client
+}
+
+// NewHTTPProvider creates a provider for the API at baseURL.
+func NewHTTPProvider(baseURL string) *HTTPProvider {
+	return &HTTPProvider{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Base:    "EUR",
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type httpRatesResponse struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Snapshot implements [Provider].
+func (p *HTTPProvider) Snapshot(ctx context.Context, day time.Time) (*Snapshot, error) {
+	url := fmt.Sprintf("%s/%s?base=%s", p.BaseURL, truncateDay(day).Format("2006-01-02"), p.Base)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch FX rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNoRates
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch FX rates: unexpected status %s", resp.Status)
+	}
+
+	var body httpRatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode FX rates: %w", err)
+	}
+	if body.Base == "" || len(body.Rates) == 0 {
+		return nil, ErrNoRates
+	}
+	date, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		date = truncateDay(day)
+	}
+	return &Snapshot{Date: date, Base: strings.ToUpper(body.Base), Rates: body.Rates, Source: p.BaseURL}, nil
+}
@@ -22,9 +22,9 @@ type DealDetector struct {
 }
 
 type BaselineStore interface {
-	GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class string) (*db.RouteBaseline, error)
+	GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class, currency string) (*db.RouteBaseline, error)
 	UpsertRouteBaseline(ctx context.Context, baseline db.RouteBaseline) error
-	GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class string, windowDays int) ([]float64, error)
+	GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class, currency string, windowDays int) ([]float64, error)
 }
 
 // NewDealDetector creates a new deal detector instance
@@ -52,11 +52,18 @@ func DefaultDealConfig() config.DealConfig {
 	}
 }
 
-// DetectDeal checks if a price result qualifies as a deal
+// DetectDeal checks if a price result qualifies as a deal. Results normalized to the reporting
+// currency are compared with the baseline of that currency, others with the baseline of their own.
 func (d *DealDetector) DetectDeal(ctx context.Context, result db.PriceGraphResultRecord) (*db.DetectedDeal, error) {
+	price, currency := comparablePrice(result)
+	costPerMile := result.CostPerMile
+	if result.NormalizedCostPerMile.Valid {
+		costPerMile = result.NormalizedCostPerMile
+	}
+
 	// Get baseline for this route
 	baseline, err := d.getBaseline(ctx, result.Origin, result.Destination,
-		int(result.TripLength.Int32), result.Class)
+		int(result.TripLength.Int32), result.Class, currency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get baseline: %w", err)
 	}
@@ -75,10 +82,10 @@ func (d *DealDetector) DetectDeal(ctx context.Context, result db.PriceGraphResul
 		return nil, nil
 	}
 
-	discountPercent := (baselinePrice - result.Price) / baselinePrice
+	discountPercent := (baselinePrice - price) / baselinePrice
 
 	// Check if this qualifies as a deal
-	if discountPercent < d.config.GoodDealThreshold && !d.isCostPerMileDeal(result) {
+	if discountPercent < d.config.GoodDealThreshold && !d.isCostPerMileDeal(costPerMile, result.Class) {
 		return nil, nil // Not a deal
 	}
 
@@ -86,10 +93,10 @@ func (d *DealDetector) DetectDeal(ctx context.Context, result db.PriceGraphResul
 	classification := d.classifyDeal(discountPercent)
 
 	// Calculate composite score (0-100)
-	score := d.calculateScore(discountPercent, result.CostPerMile.Float64, result.Class)
+	score := d.calculateScore(discountPercent, costPerMile.Float64, result.Class)
 
 	// Generate fingerprint for deduplication
-	fingerprint := d.generateFingerprint(result)
+	fingerprint := d.generateFingerprint(result, price)
 
 	// Set expiration
 	expiresAt := time.Now().Add(time.Duration(d.config.DealTTLHours) * time.Hour)
@@ -119,6 +126,10 @@ func (d *DealDetector) DetectDeal(ctx context.Context, result db.PriceGraphResul
 		Status:             db.DealStatusActive,
 		ExpiresAt:          sql.NullTime{Time: expiresAt, Valid: true},
 	}
+	if result.NormalizedCurrency.Valid {
+		deal.NormalizedPrice = result.NormalizedPrice
+		deal.NormalizedCurrency = result.NormalizedCurrency
+	}
 
 	return deal, nil
 }
@@ -167,13 +178,13 @@ func (d *DealDetector) calculateScore(discountPercent, costPerMile float64, cabi
 }
 
 // isCostPerMileDeal checks if the deal qualifies based on cost-per-mile alone
-func (d *DealDetector) isCostPerMileDeal(result db.PriceGraphResultRecord) bool {
-	if !result.CostPerMile.Valid || result.CostPerMile.Float64 <= 0 {
+func (d *DealDetector) isCostPerMileDeal(costPerMile sql.NullFloat64, cabinClass string) bool {
+	if !costPerMile.Valid || costPerMile.Float64 <= 0 {
 		return false
 	}
 
-	threshold := d.getCostPerMileThreshold(result.Class)
-	return result.CostPerMile.Float64 <= threshold
+	threshold := d.getCostPerMileThreshold(cabinClass)
+	return costPerMile.Float64 <= threshold
 }
 
 // getCostPerMileThreshold returns the CPM threshold for a cabin class
@@ -191,9 +202,9 @@ func (d *DealDetector) getCostPerMileThreshold(cabinClass string) float64 {
 }
 
 // generateFingerprint creates a unique identifier for deduplication
-func (d *DealDetector) generateFingerprint(result db.PriceGraphResultRecord) string {
+func (d *DealDetector) generateFingerprint(result db.PriceGraphResultRecord, price float64) string {
 	// Fingerprint based on: route + price bucket + date range
-	priceBucket := int(result.Price/50) * 50 // Round to nearest $50
+	priceBucket := int(price/50) * 50 // Round to nearest 50 units of the compared currency
 
 	data := fmt.Sprintf("%s-%s-%d-%s-%s",
 		result.Origin,
@@ -207,10 +218,19 @@ func (d *DealDetector) generateFingerprint(result db.PriceGraphResultRecord) str
 	return hex.EncodeToString(hash[:])
 }
 
-// getBaseline retrieves the price baseline for a route
-func (d *DealDetector) getBaseline(ctx context.Context, origin, dest string, tripLength int, class string) (*db.RouteBaseline, error) {
+// comparablePrice returns the price of a result in the reporting currency if it was normalized,
+// otherwise in the currency it was quoted in.
+func comparablePrice(result db.PriceGraphResultRecord) (float64, string) {
+	if result.NormalizedPrice.Valid && result.NormalizedCurrency.Valid {
+		return result.NormalizedPrice.Float64, result.NormalizedCurrency.String
+	}
+	return result.Price, result.Currency
+}
+
+// getBaseline retrieves the price baseline for a route in the given currency
+func (d *DealDetector) getBaseline(ctx context.Context, origin, dest string, tripLength int, class, currency string) (*db.RouteBaseline, error) {
 	// First try to get exact match
-	baseline, err := d.db.GetRouteBaseline(ctx, origin, dest, tripLength, class)
+	baseline, err := d.db.GetRouteBaseline(ctx, origin, dest, tripLength, class, currency)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -219,12 +239,12 @@ func (d *DealDetector) getBaseline(ctx context.Context, origin, dest string, tri
 	}
 
 	// Try to calculate baseline from recent prices
-	return d.calculateBaseline(ctx, origin, dest, tripLength, class)
+	return d.calculateBaseline(ctx, origin, dest, tripLength, class, currency)
 }
 
 // calculateBaseline computes baseline statistics from price history
-func (d *DealDetector) calculateBaseline(ctx context.Context, origin, dest string, tripLength int, class string) (*db.RouteBaseline, error) {
-	prices, err := d.db.GetPriceHistoryForRoute(ctx, origin, dest, tripLength, class, d.config.BaselineWindowDays)
+func (d *DealDetector) calculateBaseline(ctx context.Context, origin, dest string, tripLength int, class, currency string) (*db.RouteBaseline, error) {
+	prices, err := d.db.GetPriceHistoryForRoute(ctx, origin, dest, tripLength, class, currency, d.config.BaselineWindowDays)
 	if err != nil {
 		return nil, err
 	}
@@ -241,6 +261,7 @@ func (d *DealDetector) calculateBaseline(ctx context.Context, origin, dest strin
 		Destination: dest,
 		TripLength:  tripLength,
 		Class:       class,
+		Currency:    currency,
 		SampleCount: len(prices),
 		MeanPrice:   sql.NullFloat64{Float64: mean(prices), Valid: true},
 		MedianPrice: sql.NullFloat64{Float64: median(prices), Valid: true},
@@ -2,6 +2,7 @@ package deals
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/gilby125/google-flights-api/db"
@@ -13,8 +14,8 @@ type mockBaselineStore struct {
 	mock.Mock
 }
 
-func (m *mockBaselineStore) GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class string) (*db.RouteBaseline, error) {
-	args := m.Called(ctx, origin, dest, tripLength, class)
+func (m *mockBaselineStore) GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class, currency string) (*db.RouteBaseline, error) {
+	args := m.Called(ctx, origin, dest, tripLength, class, currency)
 	var baseline *db.RouteBaseline
 	if b := args.Get(0); b != nil {
 		baseline = b.(*db.RouteBaseline)
@@ -27,8 +28,8 @@ func (m *mockBaselineStore) UpsertRouteBaseline(ctx context.Context, baseline db
 	return args.Error(0)
 }
 
-func (m *mockBaselineStore) GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class string, windowDays int) ([]float64, error) {
-	args := m.Called(ctx, origin, dest, tripLength, class, windowDays)
+func (m *mockBaselineStore) GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class, currency string, windowDays int) ([]float64, error) {
+	args := m.Called(ctx, origin, dest, tripLength, class, currency, windowDays)
 	var prices []float64
 	if p := args.Get(0); p != nil {
 		prices = p.([]float64)
@@ -45,20 +46,63 @@ func TestDealDetector_GetBaseline_UsesAllHistoryWhenWindowDaysZero(t *testing.T)
 
 	detector := NewDealDetector(mockDB, cfg)
 
-	mockDB.On("GetRouteBaseline", mock.Anything, "SFO", "LAX", 7, "economy").
+	mockDB.On("GetRouteBaseline", mock.Anything, "SFO", "LAX", 7, "economy", "USD").
 		Return((*db.RouteBaseline)(nil), nil).
 		Once()
-	mockDB.On("GetPriceHistoryForRoute", mock.Anything, "SFO", "LAX", 7, "economy", 0).
+	mockDB.On("GetPriceHistoryForRoute", mock.Anything, "SFO", "LAX", 7, "economy", "USD", 0).
 		Return([]float64{500, 450, 400}, nil).
 		Once()
 	mockDB.On("UpsertRouteBaseline", mock.Anything, mock.Anything).
 		Return(nil).
 		Once()
 
-	baseline, err := detector.getBaseline(context.Background(), "SFO", "LAX", 7, "economy")
+	baseline, err := detector.getBaseline(context.Background(), "SFO", "LAX", 7, "economy", "USD")
 	require.NoError(t, err)
 	require.NotNil(t, baseline)
 	require.Equal(t, 3, baseline.SampleCount)
 
 	mockDB.AssertExpectations(t)
 }
+
+func TestDealDetector_DetectDeal_UsesNormalizedPrice(t *testing.T) {
+	t.Parallel()
+
+	mockDB := &mockBaselineStore{}
+	cfg := DefaultDealConfig()
+	cfg.BaselineMinSamples = 3
+
+	detector := NewDealDetector(mockDB, cfg)
+
+	// Baselines are kept per currency: the route has no USD baseline yet, so it is computed from
+	// USD history.
+	mockDB.On("GetRouteBaseline", mock.Anything, "FRA", "JFK", 7, "economy", "USD").
+		Return((*db.RouteBaseline)(nil), nil).
+		Once()
+	mockDB.On("GetPriceHistoryForRoute", mock.Anything, "FRA", "JFK", 7, "economy", "USD", 0).
+		Return([]float64{1000, 1000, 1000}, nil).
+		Once()
+	mockDB.On("UpsertRouteBaseline", mock.Anything, mock.MatchedBy(func(b db.RouteBaseline) bool {
+		return b.Currency == "USD"
+	})).
+		Return(nil).
+		Once()
+
+	deal, err := detector.DetectDeal(context.Background(), db.PriceGraphResultRecord{
+		Origin:             "FRA",
+		Destination:        "JFK",
+		TripLength:         sql.NullInt32{Int32: 7, Valid: true},
+		Class:              "economy",
+		Price:              450,
+		Currency:           "EUR",
+		NormalizedPrice:    sql.NullFloat64{Float64: 500, Valid: true},
+		NormalizedCurrency: sql.NullString{String: "USD", Valid: true},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, deal)
+	require.InDelta(t, 50, deal.DiscountPercent.Float64, 0.001)
+	require.Equal(t, 450.0, deal.Price)
+	require.Equal(t, "EUR", deal.Currency)
+	require.Equal(t, 500.0, deal.NormalizedPrice.Float64)
+
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,233 @@
+// Package export streams tabular query results as CSV, NDJSON or Parquet files. Writers
+// encode rows as they arrive, so an export never holds more than a row (CSV, NDJSON) or a row
+// group (Parquet) in memory.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	CSV     Format = "csv"
+	NDJSON  Format = "ndjson"
+	Parquet Format = "parquet"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{CSV, NDJSON, Parquet}
+
+// ParseFormat returns the format named s; the empty string is CSV.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", CSV:
+		return CSV, nil
+	case NDJSON, Parquet:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (want csv, ndjson or parquet)", s)
+	}
+}
+
+// ContentType returns the media type of files in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case NDJSON:
+		return "application/x-ndjson"
+	case Parquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Type is the type of the values of a column.
+type Type int
+
+const (
+	String    Type = iota // string
+	Int                   // int64
+	Float                 // float64
+	Bool                  // bool
+	Date                  // time.Time, written as YYYY-MM-DD
+	Timestamp             // time.Time
+	JSON                  // json.RawMessage, embedded as is in NDJSON and as text otherwise
+)
+
+// Column is a column of an export.
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Writer writes the rows of an export. Every row has a value per column, of the Go type of its
+// column Type or nil for NULL. Close must be called to complete the file.
+type Writer interface {
+	Write(row []interface{}) error
+	Close() error
+}
+
+// NewWriter returns a writer of files in format to w.
+func NewWriter(format Format, w io.Writer, columns []Column) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w, columns)
+	case NDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w), columns: columns}, nil
+	case Parquet:
+		return newParquetWriter(w, columns, defaultRowGroupSize), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+const dateLayout = "2006-01-02"
+
+// checkRow returns an error if row doesn't have a value of the right type for every column.
+func checkRow(columns []Column, row []interface{}) error {
+	if len(row) != len(columns) {
+		return fmt.Errorf("row has %d values, want %d", len(row), len(columns))
+	}
+	for i, value := range row {
+		if value == nil {
+			continue
+		}
+		ok := false
+		switch columns[i].Type {
+		case String:
+			_, ok = value.(string)
+		case Int:
+			_, ok = value.(int64)
+		case Float:
+			_, ok = value.(float64)
+		case Bool:
+			_, ok = value.(bool)
+		case Date, Timestamp:
+			_, ok = value.(time.Time)
+		case JSON:
+			_, ok = value.(json.RawMessage)
+		}
+		if !ok {
+			return fmt.Errorf("column %s: unexpected value of type %T", columns[i].Name, value)
+		}
+	}
+	return nil
+}
+
+// formatText renders a value for the text formats; NULL is the empty string.
+func formatText(t Type, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if t == Date {
+			return v.Format(dateLayout)
+		}
+		return v.UTC().Format(time.RFC3339)
+	case json.RawMessage:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []Column
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), columns: columns, record: make([]string, len(columns))}
+	for i, column := range columns {
+		cw.record[i] = column.Name
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) Write(row []interface{}) error {
+	if err := checkRow(cw.columns, row); err != nil {
+		return err
+	}
+	for i, value := range row {
+		cw.record[i] = formatText(cw.columns[i].Type, value)
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	columns []Column
+	buf     []byte
+}
+
+// Write writes row as a JSON object with the columns as keys, in column order.
+func (nw *ndjsonWriter) Write(row []interface{}) error {
+	if err := checkRow(nw.columns, row); err != nil {
+		return err
+	}
+	buf := append(nw.buf[:0], '{')
+	for i, value := range row {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendQuote(buf, nw.columns[i].Name)
+		buf = append(buf, ':')
+
+		switch v := value.(type) {
+		case nil:
+			buf = append(buf, "null"...)
+		case json.RawMessage:
+			if !json.Valid(v) {
+				return fmt.Errorf("column %s: invalid JSON", nw.columns[i].Name)
+			}
+			buf = append(buf, v...)
+		case float64:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", nw.columns[i].Name, err)
+			}
+			buf = append(buf, encoded...)
+		case int64, bool:
+			buf = append(buf, formatText(nw.columns[i].Type, v)...)
+		default:
+			encoded, err := json.Marshal(formatText(nw.columns[i].Type, v))
+			if err != nil {
+				return err
+			}
+			buf = append(buf, encoded...)
+		}
+	}
+	buf = append(buf, '}', '\n')
+	nw.buf = buf
+	_, err := nw.w.Write(buf)
+	return err
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nw.w.Flush()
+}
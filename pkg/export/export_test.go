@@ -0,0 +1,368 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testColumns = []Column{
+	{Name: "origin", Type: String},
+	{Name: "trip_length", Type: Int},
+	{Name: "price", Type: Float},
+	{Name: "nonstop", Type: Bool},
+	{Name: "departure_date", Type: Date},
+	{Name: "queried_at", Type: Timestamp},
+	{Name: "flights", Type: JSON},
+}
+
+var queriedAt = time.Date(2026, 5, 1, 12, 30, 0, 0, time.UTC)
+
+var testRows = [][]interface{}{
+	{"JFK", int64(7), 412.5, true, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), queriedAt, json.RawMessage(`[{"flight_number":"BA 112"}]`)},
+	{"EWR, \"NJ\"", nil, 99.0, false, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), nil, nil},
+	{nil, int64(-1), nil, nil, nil, queriedAt.Add(time.Microsecond), json.RawMessage(`{}`)},
+}
+
+func writeAll(t *testing.T, format Format, rows [][]interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, testColumns)
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range append(Formats, "") {
+		_, err := ParseFormat(string(format))
+		assert.NoError(t, err)
+	}
+	_, err := ParseFormat("xlsx")
+	assert.Error(t, err)
+	assert.Equal(t, "application/x-ndjson", NDJSON.ContentType())
+}
+
+func TestCSV(t *testing.T) {
+	out := writeAll(t, CSV, testRows)
+	assert.Equal(t, "origin,trip_length,price,nonstop,departure_date,queried_at,flights\n"+
+		`JFK,7,412.5,true,2026-06-01,2026-05-01T12:30:00Z,"[{""flight_number"":""BA 112""}]"`+"\n"+
+		`"EWR, ""NJ""",,99,false,1969-12-31,,`+"\n"+
+		`,-1,,,,2026-05-01T12:30:00Z,{}`+"\n", string(out))
+
+	assert.Equal(t, "origin,trip_length,price,nonstop,departure_date,queried_at,flights\n", string(writeAll(t, CSV, nil)))
+}
+
+func TestNDJSON(t *testing.T) {
+	out := writeAll(t, NDJSON, testRows)
+	lines := bytes.Split(bytes.TrimSuffix(out, []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Equal(t, `{"origin":"JFK","trip_length":7,"price":412.5,"nonstop":true,"departure_date":"2026-06-01","queried_at":"2026-05-01T12:30:00Z","flights":[{"flight_number":"BA 112"}]}`, string(lines[0]))
+	assert.JSONEq(t, `{"origin":"EWR, \"NJ\"","trip_length":null,"price":99,"nonstop":false,"departure_date":"1969-12-31","queried_at":null,"flights":null}`, string(lines[1]))
+	assert.JSONEq(t, `{"origin":null,"trip_length":-1,"price":null,"nonstop":null,"departure_date":null,"queried_at":"2026-05-01T12:30:00Z","flights":{}}`, string(lines[2]))
+
+	var buf bytes.Buffer
+	w, _ := NewWriter(NDJSON, &buf, testColumns[6:])
+	assert.Error(t, w.Write([]interface{}{json.RawMessage(`{`)}))
+}
+
+func TestWriteChecksRows(t *testing.T) {
+	for _, format := range Formats {
+		var buf bytes.Buffer
+		w, err := NewWriter(format, &buf, testColumns[:2])
+		require.NoError(t, err)
+		assert.Error(t, w.Write([]interface{}{"JFK"}), format)
+		assert.Error(t, w.Write([]interface{}{"JFK", 7}), format, "ints must be int64")
+	}
+}
+
+func TestParquet(t *testing.T) {
+	for _, rowGroupSize := range []int{defaultRowGroupSize, 2} {
+		var buf bytes.Buffer
+		w := newParquetWriter(&buf, testColumns, rowGroupSize)
+		for _, row := range testRows {
+			require.NoError(t, w.Write(row))
+		}
+		require.NoError(t, w.Close())
+
+		file := readParquet(t, buf.Bytes())
+		assert.Equal(t, []string{"origin", "trip_length", "price", "nonstop", "departure_date", "queried_at", "flights"}, file.names)
+		assert.Equal(t, []int64{6, 2, 5, 0, 1, 2, 6}, file.types)
+		assert.Equal(t, int64(3), file.numRows)
+		assert.Equal(t, [][]interface{}{
+			{"JFK", int64(7), 412.5, true, int32(20605), queriedAt.UnixMicro(), `[{"flight_number":"BA 112"}]`},
+			{"EWR, \"NJ\"", nil, 99.0, false, int32(-1), nil, nil},
+			{nil, int64(-1), nil, nil, nil, queriedAt.UnixMicro() + 1, `{}`},
+		}, file.rows)
+		if rowGroupSize == 2 {
+			assert.Equal(t, 2, file.rowGroups)
+		}
+	}
+
+	file := readParquet(t, writeAll(t, Parquet, nil))
+	assert.Equal(t, int64(0), file.numRows)
+	assert.Empty(t, file.rows)
+}
+
+// parquetFile is the content of a file decoded by readParquet.
+type parquetFile struct {
+	names     []string
+	types     []int64
+	numRows   int64
+	rowGroups int
+	rows      [][]interface{}
+}
+
+// readParquet decodes the files of parquetWriter: OPTIONAL flat columns with a PLAIN encoded,
+// uncompressed data page per column chunk.
+func readParquet(t *testing.T, data []byte) parquetFile {
+	t.Helper()
+	require.True(t, len(data) >= 12)
+	require.Equal(t, "PAR1", string(data[:4]))
+	require.Equal(t, "PAR1", string(data[len(data)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footerStart := len(data) - 8 - footerLen
+	r := &thriftReader{buf: data[footerStart : len(data)-8]}
+	meta := r.readStruct()
+	require.Empty(t, r.buf, "trailing bytes after FileMetaData")
+
+	var file parquetFile
+	schema := meta[2].([]interface{})
+	for _, element := range schema[1:] {
+		fields := element.(map[int16]interface{})
+		assert.Equal(t, int64(1), fields[3], "columns are OPTIONAL")
+		file.names = append(file.names, string(fields[4].([]byte)))
+		file.types = append(file.types, fields[1].(int64))
+	}
+	file.numRows = meta[3].(int64)
+
+	for _, group := range meta[4].([]interface{}) {
+		file.rowGroups++
+		groupFields := group.(map[int16]interface{})
+		numRows := int(groupFields[3].(int64))
+		rows := make([][]interface{}, numRows)
+		for i := range rows {
+			rows[i] = make([]interface{}, len(file.names))
+		}
+
+		for c, chunk := range groupFields[1].([]interface{}) {
+			chunkMeta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+			offset := int(chunkMeta[9].(int64))
+			pr := &thriftReader{buf: data[offset:]}
+			header := pr.readStruct()
+			size := int(header[3].(int64))
+			assert.Equal(t, int64(numRows), header[5].(map[int16]interface{})[1])
+			page := pr.buf[:size]
+			assert.Equal(t, int(chunkMeta[7].(int64)), len(data[offset:])-len(pr.buf)+size)
+
+			levelsLen := int(binary.LittleEndian.Uint32(page))
+			levels := page[4 : 4+levelsLen]
+			runHeader, n := binary.Uvarint(levels)
+			require.Equal(t, uint64(1), runHeader&1, "bit-packed run")
+			bits := levels[n:]
+			values := page[4+levelsLen:]
+
+			valueIndex := 0
+			for row := 0; row < numRows; row++ {
+				if bits[row/8]&(1<<(row%8)) == 0 {
+					continue
+				}
+				switch file.types[c] {
+				case 0:
+					rows[row][c] = values[valueIndex/8]&(1<<(valueIndex%8)) != 0
+				case 1:
+					rows[row][c] = int32(binary.LittleEndian.Uint32(values))
+					values = values[4:]
+				case 2:
+					rows[row][c] = int64(binary.LittleEndian.Uint64(values))
+					values = values[8:]
+				case 5:
+					rows[row][c] = math.Float64frombits(binary.LittleEndian.Uint64(values))
+					values = values[8:]
+				case 6:
+					length := binary.LittleEndian.Uint32(values)
+					rows[row][c] = string(values[4 : 4+length])
+					values = values[4+length:]
+				}
+				valueIndex++
+			}
+		}
+		file.rows = append(file.rows, rows...)
+	}
+	return file
+}
+
+// thriftReader decodes the Thrift compact protocol into maps of field IDs to values: int64 for
+// integers, []byte for binaries, []interface{} for lists and nested maps for structs.
+type thriftReader struct {
+	buf []byte
+}
+
+func (r *thriftReader) byte() byte {
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *thriftReader) varint() int64 {
+	v, n := binary.Varint(r.buf)
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *thriftReader) readStruct() map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		typ := header & 0x0f
+		if delta := int16(header >> 4); delta != 0 {
+			last += delta
+		} else {
+			last = int16(r.varint())
+		}
+		fields[last] = r.readValue(typ)
+	}
+}
+
+func (r *thriftReader) readValue(typ byte) interface{} {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := r.uvarint()
+		v := r.buf[:n]
+		r.buf = r.buf[n:]
+		return v
+	case thriftList:
+		header := r.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.readValue(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return r.readStruct()
+	default:
+		panic("unexpected thrift type")
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// TestParquetGolden pins the bytes of parquetWriter so encoding changes are deliberate. Before
+// refreshing the golden file with "go test ./pkg/export -run TestParquetGolden -update", check
+// that TestParquetPyArrow passes with pyarrow installed.
+func TestParquetGolden(t *testing.T) {
+	const goldenPath = "testdata/export.golden.parquet"
+
+	var buf bytes.Buffer
+	w := newParquetWriter(&buf, testColumns, 2)
+	for _, row := range testRows {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Close())
+
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0o755))
+		require.NoError(t, os.WriteFile(goldenPath, buf.Bytes(), 0o644))
+	}
+
+	golden, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	assert.Equal(t, golden, buf.Bytes())
+}
+
+// pyarrowScript prints the schema and the rows of the Parquet file argv[1] as read by pyarrow.
+// Timestamps are printed as microseconds since the epoch and dates as YYYY-MM-DD.
+const pyarrowScript = `
+import datetime, json, sys
+import pyarrow.parquet as pq
+
+def value(v):
+    if isinstance(v, datetime.datetime):
+        if v.tzinfo is not None:
+            v = v.astimezone(datetime.timezone.utc).replace(tzinfo=None)
+        return (v - datetime.datetime(1970, 1, 1)) // datetime.timedelta(microseconds=1)
+    if isinstance(v, datetime.date):
+        return v.isoformat()
+    return v
+
+table = pq.read_table(sys.argv[1])
+print(json.dumps({
+    "schema": [[field.name, str(field.type)] for field in table.schema],
+    "rows": [[value(v) for v in row.values()] for row in table.to_pylist()],
+}))
+`
+
+// TestParquetPyArrow reads the output of parquetWriter with pyarrow, a reader which shares no
+// code with it, and checks the schema and the values. It is skipped if pyarrow isn't installed.
+func TestParquetPyArrow(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil || exec.Command(python, "-c", "import pyarrow.parquet").Run() != nil {
+		t.Skip("pyarrow is not installed")
+	}
+
+	// Two rows per row group, so that the file has more than one.
+	var buf bytes.Buffer
+	w := newParquetWriter(&buf, testColumns, 2)
+	for _, row := range testRows {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Close())
+	path := filepath.Join(t.TempDir(), "export.parquet")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	out, err := exec.Command(python, "-c", pyarrowScript, path).Output()
+	require.NoError(t, err, "pyarrow could not read the file")
+	var got struct {
+		Schema [][2]string     `json:"schema"`
+		Rows   [][]interface{} `json:"rows"`
+	}
+	require.NoError(t, json.Unmarshal(out, &got))
+
+	require.Len(t, got.Schema, len(testColumns))
+	wantTypes := []string{"string", "int64", "double", "bool", "date32[day]", "timestamp[us", "string"}
+	for i, column := range testColumns {
+		assert.Equal(t, column.Name, got.Schema[i][0])
+		assert.True(t, strings.HasPrefix(got.Schema[i][1], wantTypes[i]), "type of %s: %s", column.Name, got.Schema[i][1])
+	}
+
+	want := [][]interface{}{
+		{"JFK", 7, 412.5, true, "2026-06-01", queriedAt.UnixMicro(), `[{"flight_number":"BA 112"}]`},
+		{"EWR, \"NJ\"", nil, 99.0, false, "1969-12-31", nil, nil},
+		{nil, -1, nil, nil, nil, queriedAt.UnixMicro() + 1, `{}`},
+	}
+	wantJSON, err := json.Marshal(want)
+	require.NoError(t, err)
+	var wantRows [][]interface{}
+	require.NoError(t, json.Unmarshal(wantJSON, &wantRows))
+	assert.Equal(t, wantRows, got.Rows)
+}
@@ -0,0 +1,337 @@
+package export
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+// The Parquet writer buffers a row group at a time and writes each of its columns as a single
+// uncompressed, PLAIN encoded data page. All columns are OPTIONAL, so NULLs only cost their
+// definition level.
+
+const (
+	defaultRowGroupSize = 10000
+	// maxRowGroupBytes flushes row groups early if their values get large, e.g. JSON columns
+	maxRowGroupBytes = 32 << 20
+)
+
+var parquetMagic = []byte("PAR1")
+
+// Parquet physical types
+const (
+	parquetBoolean   = 0
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+)
+
+// Parquet converted types
+const (
+	convertedNone            = -1
+	convertedUTF8            = 0
+	convertedDate            = 6
+	convertedTimestampMicros = 10
+)
+
+const (
+	encodingPlain     = 0
+	encodingRLE       = 3
+	repetitionOpt     = 1
+	pageTypeData      = 0
+	codecUncompressed = 0
+)
+
+func physicalType(t Type) (physical, converted int32) {
+	switch t {
+	case Int:
+		return parquetInt64, convertedNone
+	case Float:
+		return parquetDouble, convertedNone
+	case Bool:
+		return parquetBoolean, convertedNone
+	case Date:
+		return parquetInt32, convertedDate
+	case Timestamp:
+		return parquetInt64, convertedTimestampMicros
+	default: // String, JSON
+		return parquetByteArray, convertedUTF8
+	}
+}
+
+// columnBuffer holds the values of a column of the current row group.
+type columnBuffer struct {
+	defined []bool
+	// values are the PLAIN encoded non-NULL values; booleans are bit packed when the page is
+	// written
+	values []byte
+	bools  []bool
+}
+
+type columnChunkMeta struct {
+	dataPageOffset int64
+	size           int64
+	numValues      int64
+}
+
+type rowGroupMeta struct {
+	columns []columnChunkMeta
+	size    int64
+	numRows int64
+}
+
+type parquetWriter struct {
+	w            io.Writer
+	offset       int64
+	columns      []Column
+	buffers      []columnBuffer
+	rows         int
+	bufferedSize int
+	rowGroupSize int
+	rowGroups    []rowGroupMeta
+	numRows      int64
+	closed       bool
+	err          error
+}
+
+func newParquetWriter(w io.Writer, columns []Column, rowGroupSize int) *parquetWriter {
+	return &parquetWriter{
+		w:            w,
+		columns:      columns,
+		buffers:      make([]columnBuffer, len(columns)),
+		rowGroupSize: rowGroupSize,
+	}
+}
+
+func (pw *parquetWriter) Write(row []interface{}) error {
+	if pw.err != nil {
+		return pw.err
+	}
+	if pw.closed {
+		return errors.New("parquet writer is closed")
+	}
+	if err := checkRow(pw.columns, row); err != nil {
+		return err
+	}
+
+	for i, value := range row {
+		buf := &pw.buffers[i]
+		buf.defined = append(buf.defined, value != nil)
+		before := len(buf.values)
+		switch v := value.(type) {
+		case nil:
+		case string:
+			buf.values = appendByteArray(buf.values, []byte(v))
+		case json.RawMessage:
+			buf.values = appendByteArray(buf.values, v)
+		case int64:
+			buf.values = binary.LittleEndian.AppendUint64(buf.values, uint64(v))
+		case float64:
+			buf.values = binary.LittleEndian.AppendUint64(buf.values, math.Float64bits(v))
+		case bool:
+			buf.bools = append(buf.bools, v)
+		case time.Time:
+			if pw.columns[i].Type == Date {
+				buf.values = binary.LittleEndian.AppendUint32(buf.values, uint32(daysSinceEpoch(v)))
+			} else {
+				buf.values = binary.LittleEndian.AppendUint64(buf.values, uint64(v.UnixMicro()))
+			}
+		}
+		pw.bufferedSize += len(buf.values) - before
+	}
+	pw.rows++
+
+	if pw.rows >= pw.rowGroupSize || pw.bufferedSize >= maxRowGroupBytes {
+		pw.err = pw.flushRowGroup()
+	}
+	return pw.err
+}
+
+// Close writes the buffered rows and the footer of the file.
+func (pw *parquetWriter) Close() error {
+	if pw.closed {
+		return pw.err
+	}
+	pw.closed = true
+	if pw.err != nil {
+		return pw.err
+	}
+	if pw.rows > 0 {
+		if pw.err = pw.flushRowGroup(); pw.err != nil {
+			return pw.err
+		}
+	}
+	if pw.offset == 0 {
+		if pw.err = pw.write(parquetMagic); pw.err != nil {
+			return pw.err
+		}
+	}
+
+	footer := pw.fileMetadata()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	footer = append(footer, parquetMagic...)
+	pw.err = pw.write(footer)
+	return pw.err
+}
+
+func (pw *parquetWriter) write(p []byte) error {
+	n, err := pw.w.Write(p)
+	pw.offset += int64(n)
+	return err
+}
+
+func (pw *parquetWriter) flushRowGroup() error {
+	if pw.offset == 0 {
+		if err := pw.write(parquetMagic); err != nil {
+			return err
+		}
+	}
+
+	group := rowGroupMeta{numRows: int64(pw.rows)}
+	for i := range pw.buffers {
+		buf := &pw.buffers[i]
+		body := encodeDefinitionLevels(buf.defined)
+		if pw.columns[i].Type == Bool {
+			body = appendBitPacked(body, buf.bools)
+		} else {
+			body = append(body, buf.values...)
+		}
+
+		header := pageHeader(len(body), pw.rows)
+		chunk := columnChunkMeta{dataPageOffset: pw.offset, numValues: int64(pw.rows)}
+		if err := pw.write(header); err != nil {
+			return err
+		}
+		if err := pw.write(body); err != nil {
+			return err
+		}
+		chunk.size = pw.offset - chunk.dataPageOffset
+		group.columns = append(group.columns, chunk)
+		group.size += chunk.size
+
+		buf.defined = buf.defined[:0]
+		buf.values = buf.values[:0]
+		buf.bools = buf.bools[:0]
+	}
+
+	pw.rowGroups = append(pw.rowGroups, group)
+	pw.numRows += int64(pw.rows)
+	pw.rows = 0
+	pw.bufferedSize = 0
+	return nil
+}
+
+// pageHeader encodes the PageHeader of a data page.
+func pageHeader(size, numValues int) []byte {
+	var t thriftWriter
+	t.beginStruct()
+	t.i32Field(1, pageTypeData)
+	t.i32Field(2, int32(size)) // uncompressed_page_size
+	t.i32Field(3, int32(size)) // compressed_page_size
+	t.structField(5)           // data_page_header
+	t.i32Field(1, int32(numValues))
+	t.i32Field(2, encodingPlain)
+	t.i32Field(3, encodingRLE) // definition levels
+	t.i32Field(4, encodingRLE) // repetition levels
+	t.endStruct()
+	t.endStruct()
+	return t.buf
+}
+
+// fileMetadata encodes the FileMetaData of the footer.
+func (pw *parquetWriter) fileMetadata() []byte {
+	var t thriftWriter
+	t.beginStruct()
+	t.i32Field(1, 1) // version
+
+	t.listField(2, thriftStruct, len(pw.columns)+1) // schema
+	t.beginStruct()
+	t.stringField(4, "schema")
+	t.i32Field(5, int32(len(pw.columns))) // num_children
+	t.endStruct()
+	for _, column := range pw.columns {
+		physical, converted := physicalType(column.Type)
+		t.beginStruct()
+		t.i32Field(1, physical)
+		t.i32Field(3, repetitionOpt)
+		t.stringField(4, column.Name)
+		if converted != convertedNone {
+			t.i32Field(6, converted)
+		}
+		t.endStruct()
+	}
+
+	t.i64Field(3, pw.numRows)
+	t.listField(4, thriftStruct, len(pw.rowGroups))
+	for _, group := range pw.rowGroups {
+		t.beginStruct()
+		t.listField(1, thriftStruct, len(group.columns))
+		for i, chunk := range group.columns {
+			physical, _ := physicalType(pw.columns[i].Type)
+			t.beginStruct()
+			t.i64Field(2, chunk.dataPageOffset) // file_offset
+			t.structField(3)                    // meta_data
+			t.i32Field(1, physical)
+			t.listField(2, thriftI32, 2) // encodings
+			t.varint(encodingPlain)
+			t.varint(encodingRLE)
+			t.listField(3, thriftBinary, 1) // path_in_schema
+			t.str(pw.columns[i].Name)
+			t.i32Field(4, codecUncompressed)
+			t.i64Field(5, chunk.numValues)
+			t.i64Field(6, chunk.size) // total_uncompressed_size
+			t.i64Field(7, chunk.size) // total_compressed_size
+			t.i64Field(9, chunk.dataPageOffset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64Field(2, group.size)
+		t.i64Field(3, group.numRows)
+		t.endStruct()
+	}
+
+	t.stringField(6, "google-flights-api export")
+	t.endStruct()
+	return t.buf
+}
+
+// encodeDefinitionLevels encodes the definition levels of an OPTIONAL column (1 for values, 0
+// for NULLs) as a single bit-packed run of the RLE/bit-packing hybrid, prefixed by its length.
+func encodeDefinitionLevels(defined []bool) []byte {
+	groups := (len(defined) + 7) / 8
+	run := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+	run = appendBitPacked(run, defined)
+
+	out := binary.LittleEndian.AppendUint32(make([]byte, 0, 4+len(run)), uint32(len(run)))
+	return append(out, run...)
+}
+
+// appendBitPacked packs bits LSB first, padding the last byte with zeros.
+func appendBitPacked(dst []byte, bits []bool) []byte {
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8 && i+j < len(bits); j++ {
+			if bits[i+j] {
+				b |= 1 << j
+			}
+		}
+		dst = append(dst, b)
+	}
+	return dst
+}
+
+func appendByteArray(dst, value []byte) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(value)))
+	return append(dst, value...)
+}
+
+// daysSinceEpoch returns the calendar date of t, in its location, as days since 1970-01-01.
+func daysSinceEpoch(t time.Time) int32 {
+	year, month, day := t.Date()
+	seconds := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix()
+	return int32(math.Floor(float64(seconds) / 86400))
+}
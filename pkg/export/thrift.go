@@ -0,0 +1,90 @@
+package export
+
+import "encoding/binary"
+
+// Types of the Thrift compact protocol, which encodes the metadata of Parquet files.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes Thrift structs with the compact protocol. Structs are written field by
+// field in ascending field ID order, as the compact protocol encodes the field IDs as deltas.
+type thriftWriter struct {
+	buf       []byte
+	lastField int16
+	// stack holds the last field IDs of the enclosing structs
+	stack []int16
+}
+
+func (t *thriftWriter) uvarint(v uint64) {
+	t.buf = binary.AppendUvarint(t.buf, v)
+}
+
+func (t *thriftWriter) varint(v int64) {
+	t.buf = binary.AppendVarint(t.buf, v) // zigzag, as the compact protocol
+}
+
+func (t *thriftWriter) fieldHeader(typ byte, id int16) {
+	if delta := id - t.lastField; delta > 0 && delta <= 15 {
+		t.buf = append(t.buf, byte(delta)<<4|typ)
+	} else {
+		t.buf = append(t.buf, typ)
+		t.varint(int64(id))
+	}
+	t.lastField = id
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(thriftI32, id)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(thriftI64, id)
+	t.varint(v)
+}
+
+func (t *thriftWriter) stringField(id int16, s string) {
+	t.fieldHeader(thriftBinary, id)
+	t.str(s)
+}
+
+func (t *thriftWriter) str(s string) {
+	t.uvarint(uint64(len(s)))
+	t.buf = append(t.buf, s...)
+}
+
+// listField writes the header of a list field of size elements of type elem, which must be
+// followed by the elements.
+func (t *thriftWriter) listField(id int16, elem byte, size int) {
+	t.fieldHeader(thriftList, id)
+	if size < 15 {
+		t.buf = append(t.buf, byte(size)<<4|elem)
+		return
+	}
+	t.buf = append(t.buf, 0xf0|elem)
+	t.uvarint(uint64(size))
+}
+
+// structField starts a struct field; its fields follow until endStruct.
+func (t *thriftWriter) structField(id int16) {
+	t.fieldHeader(thriftStruct, id)
+	t.beginStruct()
+}
+
+// beginStruct starts a struct which isn't a field, such as an element of a list or the top
+// level struct.
+func (t *thriftWriter) beginStruct() {
+	t.stack = append(t.stack, t.lastField)
+	t.lastField = 0
+}
+
+func (t *thriftWriter) endStruct() {
+	t.buf = append(t.buf, 0) // stop field
+	t.lastField = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
@@ -0,0 +1,138 @@
+package macros
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gilby125/google-flights-api/iata"
+	"github.com/gilby125/google-flights-api/pkg/geo"
+)
+
+// nearPrefix is the prefix for nearby-airport tokens: NEAR:<IATA>:<radius in miles>, e.g.
+// NEAR:SFO:150 for every airport within 150 miles of San Francisco.
+const nearPrefix = "NEAR:"
+
+// MaxNearRadiusMiles is the largest radius a NEAR: token accepts.
+const MaxNearRadiusMiles = 500
+
+// AirportLocation is an airport with its coordinates in decimal degrees.
+type AirportLocation struct {
+	Code string
+	Lat  float64
+	Lon  float64
+}
+
+// NearbyAirport is an airport matched by a NEAR: token, with its great-circle distance from
+// the anchor airport of the token.
+type NearbyAirport struct {
+	Code          string  `json:"code"`
+	Anchor        string  `json:"anchor"`
+	DistanceMiles float64 `json:"distance_miles"`
+}
+
+var (
+	iataLocationsOnce sync.Once
+	iataLocations     []AirportLocation
+)
+
+// initIATALocations collects the coordinates of every airport known to the iata package.
+func initIATALocations() {
+	iataLocationsOnce.Do(func() {
+		for _, code := range iata.Codes() {
+			location := iata.IATATimeZone(code)
+			if location.Lat == 0 && location.Lon == 0 {
+				continue
+			}
+			iataLocations = append(iataLocations, AirportLocation{Code: code, Lat: location.Lat, Lon: location.Lon})
+		}
+	})
+}
+
+// IsNearToken returns true if the input looks like a NEAR: token.
+func IsNearToken(input string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(input)), nearPrefix)
+}
+
+// ParseNearToken splits a NEAR:<IATA>:<miles> token into its anchor airport and radius.
+func ParseNearToken(input string) (anchor string, radiusMiles float64, err error) {
+	token := strings.ToUpper(strings.TrimSpace(input))
+	parts := strings.Split(strings.TrimPrefix(token, nearPrefix), ":")
+	if !IsNearToken(token) || len(parts) != 2 {
+		return "", 0, fmt.Errorf("invalid nearby-airport token: %s (expected NEAR:<IATA>:<miles>)", input)
+	}
+
+	anchor = parts[0]
+	if !airportCodePattern.MatchString(anchor) {
+		return "", 0, fmt.Errorf("invalid anchor airport in %s (expected 3 uppercase letters)", input)
+	}
+	radiusMiles, err = strconv.ParseFloat(parts[1], 64)
+	if err != nil || radiusMiles <= 0 || radiusMiles > MaxNearRadiusMiles {
+		return "", 0, fmt.Errorf("invalid radius in %s (expected 1-%d miles)", input, MaxNearRadiusMiles)
+	}
+	return anchor, radiusMiles, nil
+}
+
+// NearbyAirports expands a NEAR: token into the airports within its radius, closest first; the
+// anchor itself comes first with a distance of 0. Coordinates come from the iata package and
+// extra (e.g. the Postgres airports table), whose entries take precedence.
+func NearbyAirports(token string, extra []AirportLocation) ([]NearbyAirport, error) {
+	anchor, radiusMiles, err := ParseNearToken(token)
+	if err != nil {
+		return nil, err
+	}
+	initIATALocations()
+
+	locations := make(map[string]AirportLocation, len(iataLocations)+len(extra))
+	for _, location := range iataLocations {
+		locations[location.Code] = location
+	}
+	for _, location := range extra {
+		code := strings.ToUpper(strings.TrimSpace(location.Code))
+		if !airportCodePattern.MatchString(code) || !(geo.Coordinates{Lat: location.Lat, Lon: location.Lon}).IsValid() {
+			continue
+		}
+		location.Code = code
+		locations[code] = location
+	}
+
+	origin, ok := locations[anchor]
+	if !ok {
+		return nil, fmt.Errorf("unknown anchor airport %s: no coordinates available", anchor)
+	}
+
+	result := []NearbyAirport{}
+	for code, location := range locations {
+		distance := geo.Haversine(origin.Lat, origin.Lon, location.Lat, location.Lon)
+		if code != anchor && distance > radiusMiles {
+			continue
+		}
+		result = append(result, NearbyAirport{
+			Code:          code,
+			Anchor:        anchor,
+			DistanceMiles: math.Round(distance*10) / 10,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Code == anchor || result[j].Code == anchor {
+			return result[i].Code == anchor
+		}
+		if result[i].DistanceMiles != result[j].DistanceMiles {
+			return result[i].DistanceMiles < result[j].DistanceMiles
+		}
+		return result[i].Code < result[j].Code
+	})
+	return result, nil
+}
+
+// NearbyAirportCodes returns the airport codes of NearbyAirports.
+func NearbyAirportCodes(airports []NearbyAirport) []string {
+	codes := make([]string, 0, len(airports))
+	for _, airport := range airports {
+		codes = append(codes, airport.Code)
+	}
+	return codes
+}
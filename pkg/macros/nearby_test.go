@@ -0,0 +1,119 @@
+package macros
+
+import (
+	"testing"
+)
+
+func TestParseNearToken(t *testing.T) {
+	anchor, radius, err := ParseNearToken(" near:sfo:150 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if anchor != "SFO" || radius != 150 {
+		t.Errorf("expected SFO within 150 miles, got %s within %v", anchor, radius)
+	}
+
+	for _, input := range []string{"NEAR:SFO", "NEAR:SF:10", "NEAR:SFO:0", "NEAR:SFO:501", "NEAR:SFO:abc", "NEAR:SFO:10:20"} {
+		if _, _, err := ParseNearToken(input); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestNearbyAirports(t *testing.T) {
+	nearby, err := NearbyAirports("NEAR:SFO:50", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nearby) < 3 || nearby[0].Code != "SFO" || nearby[0].DistanceMiles != 0 {
+		t.Fatalf("expected SFO first, got %v", nearby)
+	}
+
+	byCode := make(map[string]NearbyAirport, len(nearby))
+	for i, airport := range nearby {
+		if airport.Anchor != "SFO" {
+			t.Errorf("unexpected anchor for %s: %s", airport.Code, airport.Anchor)
+		}
+		if airport.DistanceMiles > 50 {
+			t.Errorf("%s is %v miles away", airport.Code, airport.DistanceMiles)
+		}
+		if i > 0 && airport.DistanceMiles < nearby[i-1].DistanceMiles {
+			t.Errorf("airports aren't sorted by distance: %v", nearby)
+		}
+		byCode[airport.Code] = airport
+	}
+	for _, code := range []string{"OAK", "SJC"} {
+		if _, ok := byCode[code]; !ok {
+			t.Errorf("expected %s within 50 miles of SFO", code)
+		}
+	}
+	if _, ok := byCode["LAX"]; ok {
+		t.Error("LAX isn't within 50 miles of SFO")
+	}
+	if oak := byCode["OAK"]; oak.DistanceMiles < 5 || oak.DistanceMiles > 20 {
+		t.Errorf("unexpected SFO-OAK distance: %v", oak.DistanceMiles)
+	}
+}
+
+func TestNearbyAirports_ExtraLocations(t *testing.T) {
+	extra := []AirportLocation{
+		{Code: "qqq", Lat: 37.70, Lon: -122.30}, // not in the iata package
+		{Code: "ZZZ", Lat: 95, Lon: 0},          // invalid coordinates are ignored
+	}
+	nearby, err := NearbyAirports("NEAR:SFO:20", extra)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := false
+	for _, airport := range nearby {
+		if airport.Code == "ZZZ" {
+			t.Error("ZZZ has invalid coordinates")
+		}
+		found = found || airport.Code == "QQQ"
+	}
+	if !found {
+		t.Errorf("expected QQQ from the extra locations, got %v", nearby)
+	}
+
+	if _, err := NearbyAirports("NEAR:QQX:20", extra); err == nil {
+		t.Error("expected error for an anchor without coordinates")
+	}
+}
+
+func TestExpandAirportTokens_Near(t *testing.T) {
+	airports, warnings, err := ExpandAirportTokens([]string{"OAK", "near:sfo:50"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	if airports[0] != "OAK" || airports[1] != "SFO" {
+		t.Errorf("expected OAK then SFO, got %v", airports)
+	}
+	seen := map[string]bool{}
+	for _, code := range airports {
+		if seen[code] {
+			t.Errorf("duplicate airport %s", code)
+		}
+		seen[code] = true
+	}
+
+	airports, _, err = ExpandAirportTokensWithOverrides([]string{"NEAR:SFO:50"}, map[string][]string{"near:sfo:50": {"SFO", "SJC"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(airports) != 2 || airports[1] != "SJC" {
+		t.Errorf("expected the override, got %v", airports)
+	}
+
+	if _, _, err := ExpandAirportTokens([]string{"NEAR:SFO:9999"}); err == nil {
+		t.Error("expected error for an oversized radius")
+	}
+}
+
+func TestValidateNoRegionTokens_Near(t *testing.T) {
+	if err := ValidateNoRegionTokens("JFK", "NEAR:SFO:100"); err == nil {
+		t.Error("expected error for NEAR token")
+	}
+}
@@ -1,4 +1,4 @@
-// Package macros provides expansion utilities for region and airline group tokens.
+// Package macros provides expansion utilities for region, nearby-airport and airline group tokens.
 //
 // IMPORTANT: v1 Limitations
 //   - Region expansion is a curated set (primarily db.Top100Airports, plus small
@@ -351,16 +351,18 @@ func IsRegionToken(input string) bool {
 	return strings.HasPrefix(strings.ToUpper(input), regionPrefix)
 }
 
-// ExpandAirportTokens expands airport IATA codes + REGION:* tokens into airport codes.
+// ExpandAirportTokens expands airport IATA codes + REGION:* and NEAR:* tokens into airport codes.
 // - uppercases, trims, dedupes
 // - validates airports: ^[A-Z]{3}$
 // - unknown token => error (handlers return 400)
+// - NEAR:<IATA>:<miles> uses the coordinates of the iata package (see NearbyAirports)
 func ExpandAirportTokens(inputs []string) (airports []string, warnings []string, err error) {
 	return ExpandAirportTokensWithOverrides(inputs, nil)
 }
 
-// ExpandAirportTokensWithOverrides expands airport IATA codes + REGION:* tokens into airport codes,
-// allowing the caller to provide explicit expansions for specific region tokens (e.g. REGION:WORLD_ALL).
+// ExpandAirportTokensWithOverrides expands airport IATA codes + REGION:* and NEAR:* tokens into airport codes,
+// allowing the caller to provide explicit expansions for specific tokens (e.g. REGION:WORLD_ALL, or
+// NEAR:* tokens expanded with the Postgres airports table).
 //
 // Overrides are token -> []IATA (case-insensitive on the token key).
 func ExpandAirportTokensWithOverrides(inputs []string, overrides map[string][]string) (airports []string, warnings []string, err error) {
@@ -406,6 +408,22 @@ func ExpandAirportTokensWithOverrides(inputs []string, overrides map[string][]st
 					result = append(result, code)
 				}
 			}
+		} else if IsNearToken(token) {
+			nearbyAirports, ok := normalizedOverrides[token]
+			if !ok {
+				nearby, err := NearbyAirports(token, nil)
+				if err != nil {
+					return nil, nil, err
+				}
+				nearbyAirports = NearbyAirportCodes(nearby)
+			}
+
+			for _, code := range nearbyAirports {
+				if !seen[code] {
+					seen[code] = true
+					result = append(result, code)
+				}
+			}
 		} else {
 			// Validate as IATA airport code
 			if !airportCodePattern.MatchString(token) {
@@ -422,7 +440,7 @@ func ExpandAirportTokensWithOverrides(inputs []string, overrides map[string][]st
 	return result, warnings, nil
 }
 
-// ValidateNoRegionTokens returns an error if any input contains a REGION:* token.
+// ValidateNoRegionTokens returns an error if any input contains a REGION:* or NEAR:* token.
 // Used for single-route endpoints that don't support region expansion.
 func ValidateNoRegionTokens(inputs ...string) error {
 	for _, input := range inputs {
@@ -430,6 +448,9 @@ func ValidateNoRegionTokens(inputs ...string) error {
 		if IsRegionToken(token) {
 			return fmt.Errorf("region tokens are not supported on single-route endpoints; use bulk search or price graph sweep endpoints instead: %s", input)
 		}
+		if IsNearToken(token) {
+			return fmt.Errorf("nearby-airport tokens are not supported on single-route endpoints; use bulk search or price graph sweep endpoints instead: %s", input)
+		}
 	}
 	return nil
 }
@@ -20,27 +20,9 @@ func AdminAuth(cfg config.AdminAuthConfig) gin.HandlerFunc {
 			return
 		}
 
-		// Check for Bearer token first
-		authHeader := c.GetHeader("Authorization")
-		if cfg.Token != "" && strings.HasPrefix(authHeader, "Bearer ") {
-			token := strings.TrimPrefix(authHeader, "Bearer ")
-			if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) == 1 {
-				c.Next()
-				return
-			}
-		}
-
-		// Check Basic Auth
-		if cfg.Username != "" && cfg.Password != "" {
-			username, password, hasAuth := c.Request.BasicAuth()
-			if hasAuth {
-				usernameMatch := subtle.ConstantTimeCompare([]byte(username), []byte(cfg.Username)) == 1
-				passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(cfg.Password)) == 1
-				if usernameMatch && passwordMatch {
-					c.Next()
-					return
-				}
-			}
+		if authenticated(c, cfg) {
+			c.Next()
+			return
 		}
 
 		// No valid auth found
@@ -50,3 +32,31 @@ func AdminAuth(cfg config.AdminAuthConfig) gin.HandlerFunc {
 		})
 	}
 }
+
+// authenticated reports whether the request carries the admin credentials, either as a Bearer
+// token or with Basic Auth.
+func authenticated(c *gin.Context, cfg config.AdminAuthConfig) bool {
+	if !cfg.Enabled {
+		return false
+	}
+
+	// Check for Bearer token first
+	authHeader := c.GetHeader("Authorization")
+	if cfg.Token != "" && strings.HasPrefix(authHeader, "Bearer ") {
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) == 1 {
+			return true
+		}
+	}
+
+	// Check Basic Auth
+	if cfg.Username != "" && cfg.Password != "" {
+		username, password, hasAuth := c.Request.BasicAuth()
+		if hasAuth {
+			usernameMatch := subtle.ConstantTimeCompare([]byte(username), []byte(cfg.Username)) == 1
+			passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(cfg.Password)) == 1
+			return usernameMatch && passwordMatch
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gilby125/google-flights-api/pkg/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateRequest returns a middleware that rejects JSON request bodies which don't match the
+// schema of their operation in doc with 400. Routes without an operation or a request body in
+// doc and bodies of other content types (e.g. forms) are passed through.
+func ValidateRequest(doc *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := doc.Operation(c.Request.Method, c.FullPath())
+		if op == nil || op.RequestSchema() == nil || c.ContentType() != gin.MIMEJSON {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return
+		}
+		// restore the body for the handler
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if len(bytes.TrimSpace(body)) == 0 {
+			if op.RequestBody.Required {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "request body is required"})
+				return
+			}
+			c.Next()
+			return
+		}
+		if err := doc.ValidateJSON(op.RequestSchema(), body); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/gin-gonic/gin"
+)
+
+const tenantHeader = "X-Tenant-ID"
+
+// Tenant puts the tenant of the X-Tenant-ID header on the request context, so that the jobs of
+// different tenants take turns in the queue. As a tenant gets a share of the workers, the header
+// is only trusted for the tenants in allowed or from requests with the admin credentials of auth;
+// other requests enqueue their jobs without a tenant.
+func Tenant(auth config.AdminAuthConfig, allowed []string) gin.HandlerFunc {
+	allowedSet := make(map[string]struct{}, len(allowed))
+	for _, tenant := range allowed {
+		allowedSet[tenant] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		tenant := strings.TrimSpace(c.GetHeader(tenantHeader))
+		if tenant == "" || c.Request == nil {
+			c.Next()
+			return
+		}
+		if _, ok := allowedSet[tenant]; ok || authenticated(c, auth) {
+			c.Request = c.Request.WithContext(queue.WithTenant(c.Request.Context(), tenant))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/pkg/middleware"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantTrustsOnlyAllowedOrAuthenticatedCallers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := config.AdminAuthConfig{Enabled: true, Token: "secret"}
+	router := gin.New()
+	router.Use(middleware.Tenant(auth, []string{"acme"}))
+	router.GET("/tenant", func(c *gin.Context) {
+		c.String(http.StatusOK, queue.TenantFromContext(c.Request.Context()))
+	})
+
+	for _, tc := range []struct {
+		name   string
+		tenant string
+		token  string
+		want   string
+	}{
+		{"allowed tenant", "acme", "", "acme"},
+		{"unknown tenant", "globex", "", ""},
+		{"wrong credentials", "globex", "guess", ""},
+		{"authenticated caller", "globex", "secret", "globex"},
+		{"no header", "", "secret", ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+		if tc.tenant != "" {
+			req.Header.Set("X-Tenant-ID", tc.tenant)
+		}
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tc.want, w.Body.String(), tc.name)
+	}
+}
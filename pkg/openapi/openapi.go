@@ -0,0 +1,168 @@
+// Package openapi builds OpenAPI 3 documents from Go types and validates JSON values against
+// them. Schemas follow the encoding/json tags of the types and the gin binding tags of request
+// types, so a document generated from the handler types describes what the handlers accept.
+package openapi
+
+import (
+	"strings"
+)
+
+// Version is the OpenAPI version of the documents.
+const Version = "3.0.3"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API of a Document.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lower-case HTTP method.
+type PathItem map[string]*Operation
+
+// Operation is an API operation.
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path or query parameter of an Operation.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the request body of an Operation.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an Operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a request or response body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the shared schemas and security schemes of a Document.
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is an authentication scheme of the API.
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// JSONContent returns the content of a JSON body with schema.
+func JSONContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// NewDocument creates an empty document.
+func NewDocument(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+	}
+}
+
+// AddOperation adds op to the document. The path can use gin parameters (":id", "*path"); a
+// path parameter is added to op for each of them unless op already declares it.
+func (d *Document) AddOperation(method, path string, op *Operation) {
+	path = PathFromGin(path)
+	for _, name := range pathParams(path) {
+		if !op.hasParameter(name, "path") {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	item, ok := d.Paths[path]
+	if !ok {
+		item = PathItem{}
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// Operation returns the operation of method and path (in gin or OpenAPI form), or nil.
+func (d *Document) Operation(method, path string) *Operation {
+	item, ok := d.Paths[PathFromGin(path)]
+	if !ok {
+		return nil
+	}
+	return item[strings.ToLower(method)]
+}
+
+// RequestSchema returns the schema of the JSON request body, or nil if op has none.
+func (op *Operation) RequestSchema() *Schema {
+	if op.RequestBody == nil {
+		return nil
+	}
+	return op.RequestBody.Content["application/json"].Schema
+}
+
+// ResponseSchema returns the schema of the JSON response with status, or nil if op has none.
+func (op *Operation) ResponseSchema(status string) *Schema {
+	return op.Responses[status].Content["application/json"].Schema
+}
+
+func (op *Operation) hasParameter(name, in string) bool {
+	for _, p := range op.Parameters {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// PathFromGin converts the parameters of a gin route path to OpenAPI ones, e.g.
+// /jobs/:id to /jobs/{id}.
+func PathFromGin(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParams(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, segment[1:len(segment)-1])
+		}
+	}
+	return names
+}
@@ -0,0 +1,162 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBase struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testMarket struct {
+	Country string `json:"gl" binding:"required,len=2"`
+}
+
+type testNode struct {
+	Name     string      `json:"name"`
+	Children []*testNode `json:"children,omitempty"`
+}
+
+type testRequest struct {
+	testBase
+	Origin   string            `json:"origin" binding:"required,len=3"`
+	Class    string            `json:"class" binding:"omitempty,oneof=economy business"`
+	Adults   int               `json:"adults" binding:"required,min=1,max=9"`
+	Currency string            `json:"currency" binding:"omitempty,len=3"`
+	Codes    []string          `json:"codes" binding:"required_unless=Origin XXX,omitempty,min=1"`
+	Markets  []testMarket      `json:"markets" binding:"required,min=2,max=20,dive"`
+	Price    *float64          `json:"price,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Raw      json.RawMessage   `json:"raw,omitempty"`
+	Tree     testNode          `json:"tree"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func newTestDocument(t *testing.T) (*Document, *Schema) {
+	t.Helper()
+	g := NewGenerator()
+	schema := g.Schema(testRequest{})
+	doc := NewDocument(Info{Title: "test", Version: "1"})
+	doc.Components.Schemas = g.Schemas()
+	return doc, schema
+}
+
+func TestGeneratorSchema(t *testing.T) {
+	doc, ref := newTestDocument(t)
+	assert.Equal(t, "#/components/schemas/testRequest", ref.Ref)
+
+	schema := doc.Components.Schemas["testRequest"]
+	require.NotNil(t, schema)
+	assert.Equal(t, []string{"adults", "markets", "origin"}, schema.Required)
+
+	props := schema.Properties
+	assert.Contains(t, props, "id", "embedded fields are flattened")
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, props["created_at"])
+	assert.NotContains(t, props, "Ignored")
+	assert.NotContains(t, props, "internal")
+
+	assert.Equal(t, 3, *props["origin"].MinLength)
+	assert.Equal(t, 3, *props["origin"].MaxLength)
+	assert.Equal(t, []string{"", "economy", "business"}, props["class"].Enum)
+	assert.Equal(t, 1.0, *props["adults"].Minimum)
+	assert.Equal(t, 9.0, *props["adults"].Maximum)
+	assert.Nil(t, props["currency"].MinLength, "omitempty keeps the empty value valid")
+	assert.Nil(t, props["codes"].MinItems)
+	assert.Equal(t, 2, *props["markets"].MinItems)
+	assert.Equal(t, 20, *props["markets"].MaxItems)
+	assert.Equal(t, "#/components/schemas/testMarket", props["markets"].Items.Ref)
+	assert.Equal(t, &Schema{Type: "number", Format: "double", Nullable: true}, props["price"])
+	assert.Equal(t, &Schema{Type: "string"}, props["tags"].AdditionalProperties)
+	assert.Equal(t, &Schema{}, props["raw"])
+
+	node := doc.Components.Schemas["testNode"]
+	require.NotNil(t, node)
+	assert.Equal(t, "#/components/schemas/testNode", node.Properties["children"].Items.Ref)
+}
+
+func TestGeneratorOverrideAndNameCollision(t *testing.T) {
+	type Date struct{ time.Time }
+	g := NewGenerator()
+	g.Override(Date{}, Schema{Type: "string", Format: "date"})
+	assert.Equal(t, &Schema{Type: "string", Format: "date"}, g.Schema(Date{}))
+	assert.Equal(t, &Schema{Type: "string", Format: "date", Nullable: true}, g.Schema(&Date{}))
+
+	// a type with the name of an existing component is qualified by its package
+	assert.Equal(t, componentPrefix+"testMarket", g.Schema(testMarket{}).Ref)
+	type testMarket struct {
+		Name string `json:"name"`
+	}
+	assert.Equal(t, componentPrefix+"openapi.testMarket", g.Schema(testMarket{}).Ref)
+	assert.Equal(t, componentPrefix+"openapi.testMarket", g.Schema(&testMarket{}).Ref)
+	assert.Len(t, g.Schemas(), 2)
+}
+
+func TestValidate(t *testing.T) {
+	doc, schema := newTestDocument(t)
+	valid := `{"origin":"JFK","adults":2,"markets":[{"gl":"US"},{"gl":"DE"}],"class":"","price":null,"tags":{"a":"b"},"raw":[1,"x"],"unknown":{}}`
+	require.NoError(t, doc.ValidateJSON(schema, []byte(valid)))
+
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`[]`, "must be an object"},
+		{`{"adults":2,"markets":[{"gl":"US"},{"gl":"DE"}]}`, "origin is required"},
+		{`{"ORIGIN":"JFK","adults":2.5,"markets":[]}`, "adults must be an integer"},
+		{`{"origin":"JFK","adults":10,"markets":[]}`, "adults must be at most 9"},
+		{`{"origin":"JFKX","adults":1,"markets":[{"gl":"US"},{"gl":"DE"}]}`, "origin must be at most 3 characters long"},
+		{`{"origin":"JFK","adults":1,"markets":[{"gl":"US"}]}`, "markets must have at least 2 items"},
+		{`{"origin":"JFK","adults":1,"markets":[{"gl":"US"},{"gl":"DEU"}]}`, "markets[1].gl must be at most 2 characters long"},
+		{`{"origin":"JFK","adults":1,"markets":[{},{}]}`, "markets[0].gl is required"},
+		{`{"origin":"JFK","adults":1,"markets":[{"gl":"US"},{"gl":"DE"}],"class":"first"}`, "class must be one of [ economy business]"},
+		{`{"origin":"JFK","adults":1,"markets":[{"gl":"US"},{"gl":"DE"}],"tags":{"a":1}}`, "tags.a must be a string"},
+		{`{"origin":"JFK","adults":1,"markets":[{"gl":"US"},{"gl":"DE"}],"tree":{"children":[{"name":false}]}}`, "tree.children[0].name must be a string"},
+		{`{"origin":"JFK"`, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		err := doc.ValidateJSON(schema, []byte(tt.body))
+		if assert.Error(t, err, tt.body) {
+			assert.Contains(t, err.Error(), tt.want, tt.body)
+		}
+	}
+}
+
+func TestValidateResponseJSON(t *testing.T) {
+	doc, schema := newTestDocument(t)
+	valid := `{"origin":"JFK","adults":2,"markets":[{"gl":"US"},{"gl":"DE"}],"tags":{"any":"key"},"raw":{"free":"form"}}`
+	require.NoError(t, doc.ValidateResponseJSON(schema, []byte(valid)))
+
+	err := doc.ValidateResponseJSON(schema, []byte(`{"origin":"JFK","adults":2,"markets":[{"gl":"US"},{"gl":"DE"}],"unknown":{}}`))
+	require.Error(t, err)
+	assert.Equal(t, "unknown is not in the schema", err.Error())
+
+	err = doc.ValidateResponseJSON(schema, []byte(`{"origin":"JFK","adults":2,"markets":[{"gl":"US"},{"gl":"DE","extra":1}]}`))
+	require.Error(t, err)
+	assert.Equal(t, "markets[1].extra is not in the schema", err.Error())
+}
+
+func TestDocumentOperations(t *testing.T) {
+	assert.Equal(t, "/queue/{name}/jobs/{id}", PathFromGin("/queue/:name/jobs/:id"))
+	assert.Equal(t, "/admin/{filepath}", PathFromGin("/admin/*filepath"))
+
+	doc := NewDocument(Info{Title: "test", Version: "1"})
+	op := &Operation{Parameters: []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer"}}}}
+	doc.AddOperation("GET", "/queue/:name/jobs/:id", op)
+
+	assert.Same(t, op, doc.Operation("GET", "/queue/:name/jobs/:id"))
+	assert.Same(t, op, doc.Operation("get", "/queue/{name}/jobs/{id}"))
+	assert.Nil(t, doc.Operation("POST", "/queue/:name/jobs/:id"))
+	assert.Nil(t, op.RequestSchema())
+	assert.Nil(t, op.ResponseSchema("200"))
+
+	require.Len(t, op.Parameters, 2)
+	assert.Equal(t, "integer", op.Parameters[0].Schema.Type, "declared parameters are kept")
+	assert.Equal(t, Parameter{Name: "name", In: "path", Required: true, Schema: &Schema{Type: "string"}}, op.Parameters[1])
+}
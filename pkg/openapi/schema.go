@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is an OpenAPI schema object.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+const componentPrefix = "#/components/schemas/"
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Generator creates the schemas of Go types. Named struct types become component schemas which
+// are referenced by name; use Schemas for the components of a Document.
+type Generator struct {
+	schemas   map[string]*Schema
+	names     map[reflect.Type]string
+	overrides map[reflect.Type]Schema
+}
+
+// NewGenerator creates a generator without components.
+func NewGenerator() *Generator {
+	return &Generator{
+		schemas:   map[string]*Schema{},
+		names:     map[reflect.Type]string{},
+		overrides: map[reflect.Type]Schema{},
+	}
+}
+
+// Override sets the schema of the type of value, e.g. for a type with custom JSON encoding.
+func (g *Generator) Override(value interface{}, schema Schema) {
+	g.overrides[reflect.TypeOf(value)] = schema
+}
+
+// Schema returns the schema of the type of value.
+func (g *Generator) Schema(value interface{}) *Schema {
+	return g.schemaOf(reflect.TypeOf(value))
+}
+
+// Schemas returns the component schemas created so far by name.
+func (g *Generator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+func (g *Generator) schemaOf(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	if override, ok := g.overrides[t]; ok {
+		return &override
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := g.schemaOf(t.Elem())
+		// OpenAPI 3.0 ignores the siblings of $ref
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: true}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem()), Nullable: true}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		return g.structRef(t)
+	default:
+		// interfaces and anything else encoding/json can't describe statically
+		return &Schema{}
+	}
+}
+
+// structRef returns a reference to the component of a named struct, creating it on first use.
+// Anonymous structs are inlined.
+func (g *Generator) structRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.structSchema(t)
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := g.schemas[name]; taken {
+			name = path.Base(t.PkgPath()) + "." + name
+		}
+		g.names[t] = name
+		// the placeholder reserves the name while recursive types are generated
+		g.schemas[name] = &Schema{}
+		g.schemas[name] = g.structSchema(t)
+	}
+	return &Schema{Ref: componentPrefix + name}
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// addFields adds the JSON fields of t to schema; embedded structs without a name tag are
+// flattened like encoding/json does.
+func (g *Generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schemaOf(field.Type)
+		if applyBinding(property, field.Tag.Get("binding")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// applyBinding adds the constraints of a gin binding tag to schema and reports whether the
+// field is required. Only constraints which are checked for every value are added: a field
+// with omitempty keeps its zero value valid, and conditional rules like required_unless are
+// left to the handlers.
+func applyBinding(schema *Schema, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	rules, elementRules, hasDive := strings.Cut(tag, ",dive")
+	if hasDive && schema.Items != nil {
+		applyBinding(schema.Items, strings.TrimPrefix(elementRules, ","))
+	}
+
+	ruleList := strings.Split(rules, ",")
+	omitEmpty := slices.Contains(ruleList, "omitempty")
+
+	required := false
+	for _, rule := range ruleList {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			values := strings.Fields(param)
+			if omitEmpty {
+				values = append([]string{""}, values...)
+			}
+			if schema.Ref == "" {
+				schema.Enum = values
+			}
+		case "min", "max", "len":
+			if !omitEmpty && schema.Ref == "" {
+				setBound(schema, name, param)
+			}
+		}
+	}
+	return required
+}
+
+// setBound sets a min, max or len rule on schema: a bound of the value for numbers, of the
+// length for strings and of the item count for arrays.
+func setBound(schema *Schema, rule, param string) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	size := int(value)
+	setMin := rule == "min" || rule == "len"
+	setMax := rule == "max" || rule == "len"
+
+	switch schema.Type {
+	case "integer", "number":
+		if setMin {
+			schema.Minimum = &value
+		}
+		if setMax {
+			schema.Maximum = &value
+		}
+	case "string":
+		if setMin {
+			schema.MinLength = &size
+		}
+		if setMax {
+			schema.MaxLength = &size
+		}
+	case "array":
+		if setMin {
+			schema.MinItems = &size
+		}
+		if setMax {
+			schema.MaxItems = &size
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError reports a value which doesn't match its schema.
+type ValidationError struct {
+	// Field is the path of the value, e.g. markets[1].gl; empty for the whole document.
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + " " + e.Message
+}
+
+// ValidateJSON decodes data and validates it against schema, see Validate.
+func (d *Document) ValidateJSON(schema *Schema, data []byte) error {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return err
+	}
+	return d.Validate(schema, value)
+}
+
+// ValidateResponseJSON decodes a response body and validates it against schema like
+// ValidateJSON, but also rejects the properties the schema doesn't declare, so a handler can't
+// send fields which are missing from the document.
+func (d *Document) ValidateResponseJSON(schema *Schema, data []byte) error {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return err
+	}
+	return d.validate(schema, value, "", true)
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	return value, nil
+}
+
+// Validate checks a value decoded from JSON (with numbers as json.Number) against schema,
+// resolving references to the components of d. It checks types, required properties, enums,
+// bounds and lengths, but not formats. Unknown properties are allowed and null is valid
+// anywhere, as encoding/json decodes it to the zero value of the Go type.
+func (d *Document) Validate(schema *Schema, value interface{}) error {
+	return d.validate(schema, value, "", false)
+}
+
+// validate checks value against schema; strict rejects undeclared properties.
+func (d *Document) validate(schema *Schema, value interface{}, field string, strict bool) error {
+	if schema == nil || value == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		resolved := d.Components.Schemas[strings.TrimPrefix(schema.Ref, componentPrefix)]
+		if resolved == nil {
+			return &ValidationError{Field: field, Message: fmt.Sprintf("has unknown schema %s", schema.Ref)}
+		}
+		return d.validate(resolved, value, field, strict)
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return &ValidationError{Field: field, Message: "must be an object"}
+		}
+		return d.validateObject(schema, object, field, strict)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return &ValidationError{Field: field, Message: "must be an array"}
+		}
+		if schema.MinItems != nil && len(array) < *schema.MinItems {
+			return &ValidationError{Field: field, Message: fmt.Sprintf("must have at least %d items", *schema.MinItems)}
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			return &ValidationError{Field: field, Message: fmt.Sprintf("must have at most %d items", *schema.MaxItems)}
+		}
+		for i, item := range array {
+			if err := d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", field, i), strict); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return &ValidationError{Field: field, Message: "must be a string"}
+		}
+		return validateString(schema, s, field)
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return &ValidationError{Field: field, Message: "must be an integer"}
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return &ValidationError{Field: field, Message: "must be an integer"}
+		}
+		return validateNumber(schema, float64(i), field)
+	case "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			return &ValidationError{Field: field, Message: "must be a number"}
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return &ValidationError{Field: field, Message: "must be a number"}
+		}
+		return validateNumber(schema, f, field)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &ValidationError{Field: field, Message: "must be a boolean"}
+		}
+	}
+	return nil
+}
+
+func (d *Document) validateObject(schema *Schema, object map[string]interface{}, field string, strict bool) error {
+	for _, name := range schema.Required {
+		if lookupProperty(object, name) == nil {
+			return &ValidationError{Field: joinField(field, name), Message: "is required"}
+		}
+	}
+	// sorted, so the same document always reports the same error
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property := propertySchema(schema, name)
+		if property == nil && strict {
+			return &ValidationError{Field: joinField(field, name), Message: "is not in the schema"}
+		}
+		if err := d.validate(property, object[name], joinField(field, name), strict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupProperty returns the value of a property like encoding/json matches it to a struct
+// field: by exact name, else case-insensitively.
+func lookupProperty(object map[string]interface{}, name string) interface{} {
+	if value, ok := object[name]; ok {
+		return value
+	}
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return nil
+}
+
+// propertySchema returns the schema of a property, matched like lookupProperty does.
+func propertySchema(schema *Schema, name string) *Schema {
+	if property, ok := schema.Properties[name]; ok {
+		return property
+	}
+	for key, property := range schema.Properties {
+		if strings.EqualFold(key, name) {
+			return property
+		}
+	}
+	return schema.AdditionalProperties
+}
+
+func validateString(schema *Schema, s, field string) error {
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if s == allowed {
+				return nil
+			}
+		}
+		return &ValidationError{Field: field, Message: fmt.Sprintf("must be one of [%s]", strings.Join(schema.Enum, " "))}
+	}
+	length := utf8.RuneCountInString(s)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("must be at least %d characters long", *schema.MinLength)}
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("must be at most %d characters long", *schema.MaxLength)}
+	}
+	return nil
+}
+
+func validateNumber(schema *Schema, n float64, field string) error {
+	if schema.Minimum != nil && n < *schema.Minimum {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("must be at least %v", *schema.Minimum)}
+	}
+	if schema.Maximum != nil && n > *schema.Maximum {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("must be at most %v", *schema.Maximum)}
+	}
+	return nil
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
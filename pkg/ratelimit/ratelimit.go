@@ -0,0 +1,227 @@
+// Package ratelimit throttles the requests sent to Google. A [RedisLimiter] shares one token bucket
+// per request kind across all processes of the fleet; a [LocalLimiter] does the same within a single
+// process and is used as the fallback when Redis is unavailable.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kinds of the requests sent to Google.
+const (
+	KindOffers     = "offers"      // flight search (GetShoppingResults)
+	KindPriceGraph = "price_graph" // price graph (GetCalendarGraph)
+	KindLocation   = "location"    // city and airport lookup
+	KindHotels     = "hotels"      // hotel search
+	KindSession    = "session"     // session initialization (cookies)
+)
+
+// Limiter delays a request until it fits into the budget of its kind.
+type Limiter interface {
+	// Wait blocks until a request of the given kind may be sent. It returns the context error if
+	// the context is done before that.
+	Wait(ctx context.Context, kind string) error
+}
+
+// Rate is the budget of a request kind: Burst requests at once, refilled at PerMinute requests per
+// minute. A zero PerMinute means no limit.
+type Rate struct {
+	PerMinute float64
+	Burst     int
+}
+
+func (r Rate) unlimited() bool { return r.PerMinute <= 0 }
+
+func (r Rate) burst() float64 {
+	if r.Burst < 1 {
+		return 1
+	}
+	return float64(r.Burst)
+}
+
+// perMillisecond returns the refill rate in tokens per millisecond.
+func (r Rate) perMillisecond() float64 { return r.PerMinute / float64(time.Minute/time.Millisecond) }
+
+// Config configures the limiters. Kinds without an entry in Rates use Default.
+type Config struct {
+	Rates   map[string]Rate
+	Default Rate
+}
+
+func (c Config) rate(kind string) Rate {
+	if rate, ok := c.Rates[kind]; ok {
+		return rate
+	}
+	return c.Default
+}
+
+// KindStats describes the waits of a request kind.
+type KindStats struct {
+	Requests  int64         `json:"requests"`   // requests which went through the limiter
+	Delayed   int64         `json:"delayed"`    // requests which had to wait
+	Fallbacks int64         `json:"fallbacks"`  // requests limited locally because Redis failed
+	TotalWait time.Duration `json:"total_wait"` // sum of the waits
+	MaxWait   time.Duration `json:"max_wait"`   // longest wait
+}
+
+type kindMetrics struct {
+	requests, delayed, fallbacks atomic.Int64
+	totalWait, maxWait           atomic.Int64
+}
+
+type metrics struct {
+	kinds sync.Map // kind -> *kindMetrics
+}
+
+func (m *metrics) get(kind string) *kindMetrics {
+	km, _ := m.kinds.LoadOrStore(kind, &kindMetrics{})
+	return km.(*kindMetrics)
+}
+
+func (m *metrics) observe(kind string, wait time.Duration) {
+	km := m.get(kind)
+	km.requests.Add(1)
+	if wait <= 0 {
+		return
+	}
+	km.delayed.Add(1)
+	km.totalWait.Add(int64(wait))
+	for {
+		maxWait := km.maxWait.Load()
+		if int64(wait) <= maxWait || km.maxWait.CompareAndSwap(maxWait, int64(wait)) {
+			return
+		}
+	}
+}
+
+func (m *metrics) stats() map[string]KindStats {
+	stats := map[string]KindStats{}
+	m.kinds.Range(func(key, value any) bool {
+		km := value.(*kindMetrics)
+		stats[key.(string)] = KindStats{
+			Requests:  km.requests.Load(),
+			Delayed:   km.delayed.Load(),
+			Fallbacks: km.fallbacks.Load(),
+			TotalWait: time.Duration(km.totalWait.Load()),
+			MaxWait:   time.Duration(km.maxWait.Load()),
+		}
+		return true
+	})
+	return stats
+}
+
+// sleep waits for d or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// LocalLimiter is an in-memory token bucket per request kind. It is safe for concurrent use by
+// multiple goroutines.
+type LocalLimiter struct {
+	cfg     Config
+	now     func() time.Time
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	metrics metrics
+}
+
+// NewLocal creates a [LocalLimiter].
+func NewLocal(cfg Config) *LocalLimiter {
+	return &LocalLimiter{cfg: cfg, now: time.Now, buckets: map[string]*bucket{}}
+}
+
+// reserve takes a token of the kind and returns how long the caller has to wait for it. The token
+// is taken even if it isn't available yet, so concurrent callers queue up behind each other.
+func (l *LocalLimiter) reserve(kind string) time.Duration {
+	rate := l.cfg.rate(kind)
+	if rate.unlimited() {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[kind]
+	if !ok {
+		b = &bucket{tokens: rate.burst(), last: now}
+		l.buckets[kind] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(rate.burst(), b.tokens+float64(elapsed)/float64(time.Millisecond)*rate.perMillisecond())
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(-b.tokens/rate.perMillisecond())) * time.Millisecond
+}
+
+// Wait implements [Limiter].
+func (l *LocalLimiter) Wait(ctx context.Context, kind string) error {
+	wait := l.reserve(kind)
+	l.metrics.observe(kind, wait)
+	return sleep(ctx, wait)
+}
+
+// Stats returns the wait metrics per request kind.
+func (l *LocalLimiter) Stats() map[string]KindStats {
+	return l.metrics.stats()
+}
+
+var defaultLimiter atomic.Pointer[Limiter]
+
+// SetDefault installs the limiter used by [Wait]. It is called once at startup, so that every
+// session of the process shares the same budget. A nil limiter disables the limiting.
+func SetDefault(l Limiter) {
+	if l == nil {
+		defaultLimiter.Store(nil)
+		return
+	}
+	defaultLimiter.Store(&l)
+}
+
+// Default returns the limiter installed by [SetDefault] or nil.
+func Default() Limiter {
+	if l := defaultLimiter.Load(); l != nil {
+		return *l
+	}
+	return nil
+}
+
+// Wait waits on the default limiter. It returns immediately if no limiter is installed.
+func Wait(ctx context.Context, kind string) error {
+	if l := Default(); l != nil {
+		return l.Wait(ctx, kind)
+	}
+	return nil
+}
+
+// Stats returns the wait metrics of the default limiter, or nil if it doesn't collect them.
+func Stats() map[string]KindStats {
+	if l, ok := Default().(interface{ Stats() map[string]KindStats }); ok {
+		return l.Stats()
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalLimiterReserve(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLocal(Config{Rates: map[string]Rate{KindOffers: {PerMinute: 60, Burst: 2}}})
+	l.now = func() time.Time { return now }
+
+	assert.Zero(t, l.reserve(KindOffers))
+	assert.Zero(t, l.reserve(KindOffers))
+	assert.Equal(t, time.Second, l.reserve(KindOffers))
+	assert.Equal(t, 2*time.Second, l.reserve(KindOffers))
+
+	now = now.Add(3 * time.Second)
+	assert.Zero(t, l.reserve(KindOffers))
+
+	// Kinds without a rate aren't limited.
+	for i := 0; i < 10; i++ {
+		assert.Zero(t, l.reserve(KindHotels))
+	}
+}
+
+func TestLocalLimiterWaitMetrics(t *testing.T) {
+	l := NewLocal(Config{Default: Rate{PerMinute: 6000, Burst: 1}})
+	ctx := context.Background()
+
+	require.NoError(t, l.Wait(ctx, KindPriceGraph))
+	require.NoError(t, l.Wait(ctx, KindPriceGraph))
+
+	stats := l.Stats()[KindPriceGraph]
+	assert.Equal(t, int64(2), stats.Requests)
+	assert.Equal(t, int64(1), stats.Delayed)
+	assert.Greater(t, stats.MaxWait, time.Duration(0))
+}
+
+func TestLocalLimiterWaitCancelled(t *testing.T) {
+	l := NewLocal(Config{Default: Rate{PerMinute: 1, Burst: 1}})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, l.Wait(ctx, KindOffers))
+	assert.ErrorIs(t, l.Wait(ctx, KindOffers), context.DeadlineExceeded)
+}
+
+func TestRedisLimiterSharesBudget(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	cfg := Config{Rates: map[string]Rate{KindOffers: {PerMinute: 60, Burst: 2}}}
+	l1 := NewRedis(client, cfg, "test")
+	l2 := NewRedis(client, cfg, "test")
+	ctx := context.Background()
+
+	wait, err := l1.reserve(ctx, KindOffers)
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+	wait, err = l2.reserve(ctx, KindOffers)
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+
+	// The third request has to wait, whichever process sends it.
+	wait, err = l1.reserve(ctx, KindOffers)
+	require.NoError(t, err)
+	assert.Greater(t, wait, 900*time.Millisecond)
+	assert.True(t, mr.Exists("test:offers"))
+}
+
+func TestRedisLimiterFallback(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer client.Close()
+	mr.Close()
+
+	l := NewRedis(client, Config{Default: Rate{PerMinute: 6000, Burst: 5}}, "")
+	require.NoError(t, l.Wait(context.Background(), KindLocation))
+
+	stats := l.Stats()[KindLocation]
+	assert.Equal(t, int64(1), stats.Requests)
+	assert.Equal(t, int64(1), stats.Fallbacks)
+}
+
+func TestDefaultLimiter(t *testing.T) {
+	defer SetDefault(nil)
+
+	assert.NoError(t, Wait(context.Background(), KindOffers))
+	assert.Nil(t, Stats())
+
+	SetDefault(NewLocal(Config{}))
+	require.NoError(t, Wait(context.Background(), KindOffers))
+	assert.Equal(t, int64(1), Stats()[KindOffers].Requests)
+}
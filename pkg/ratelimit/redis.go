@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix is the prefix of the Redis keys of the buckets.
+const DefaultPrefix = "flights:ratelimit"
+
+// reserveScript takes a token from the bucket in KEYS[1] and returns the wait in milliseconds. The
+// clock of Redis is used, so the workers of the fleet don't need synchronized clocks.
+//
+// ARGV: refill rate in tokens per millisecond, burst.
+var reserveScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate)
+	ts = now
+end
+
+tokens = tokens - 1
+local wait = 0
+if tokens < 0 then
+	wait = math.ceil(-tokens / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + wait + 1000)
+return wait
+`)
+
+// RedisLimiter is a token bucket per request kind stored in Redis, so all processes using the same
+// Redis share one global budget. If Redis fails, the request is limited by a [LocalLimiter] with the
+// same rates instead of failing. It is safe for concurrent use by multiple goroutines.
+type RedisLimiter struct {
+	client   redis.Scripter
+	cfg      Config
+	prefix   string
+	fallback *LocalLimiter
+	metrics  metrics
+	degraded atomic.Bool
+}
+
+// NewRedis creates a [RedisLimiter]. If prefix is empty, [DefaultPrefix] is used.
+func NewRedis(client redis.Scripter, cfg Config, prefix string) *RedisLimiter {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return &RedisLimiter{client: client, cfg: cfg, prefix: prefix, fallback: NewLocal(cfg)}
+}
+
+func (l *RedisLimiter) reserve(ctx context.Context, kind string) (time.Duration, error) {
+	rate := l.cfg.rate(kind)
+	if rate.unlimited() {
+		return 0, nil
+	}
+
+	key := fmt.Sprintf("%s:%s", l.prefix, kind)
+	wait, err := reserveScript.Run(ctx, l.client, []string{key}, rate.perMillisecond(), rate.burst()).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+// Wait implements [Limiter].
+func (l *RedisLimiter) Wait(ctx context.Context, kind string) error {
+	wait, err := l.reserve(ctx, kind)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !l.degraded.Swap(true) {
+			log.Printf("ratelimit: redis unavailable, limiting locally: %v", err)
+		}
+		l.metrics.get(kind).fallbacks.Add(1)
+		wait = l.fallback.reserve(kind)
+	} else if l.degraded.Swap(false) {
+		log.Printf("ratelimit: redis available again")
+	}
+
+	l.metrics.observe(kind, wait)
+	return sleep(ctx, wait)
+}
+
+// Stats returns the wait metrics per request kind of this process.
+func (l *RedisLimiter) Stats() map[string]KindStats {
+	return l.metrics.stats()
+}
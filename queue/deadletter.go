@@ -0,0 +1,396 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxFailures is how many failed attempts a job keeps in its timeline; older ones are dropped.
+const maxFailures = 20
+
+// maxStackSize caps the stack stored with a failed attempt.
+const maxStackSize = 8 << 10
+
+// deadLetterMaxLen caps the dead-letter stream of a job type; the oldest entries are trimmed.
+const deadLetterMaxLen = 10000
+
+var (
+	// ErrDeadLetterNotFound is returned for jobs that are not in the dead-letter stream.
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	// ErrInvalidPayload is returned for replacement payloads that are not valid JSON.
+	ErrInvalidPayload = errors.New("payload is not valid JSON")
+)
+
+// Failure is one failed attempt of a job.
+type Failure struct {
+	Attempt int    `json:"attempt"`
+	Error   string `json:"error,omitempty"`
+	// ErrorClass groups errors with the same cause, such as "rate_limited" or "timeout".
+	ErrorClass string    `json:"error_class,omitempty"`
+	Stack      string    `json:"stack,omitempty"`
+	WorkerID   string    `json:"worker_id,omitempty"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+// DeadLetter is a job that failed its last attempt, together with the failure that ended it.
+// The job keeps its payload and the timeline of its failed attempts.
+type DeadLetter struct {
+	EntryID    string    `json:"entry_id,omitempty"`
+	Queue      string    `json:"queue"`
+	Job        *Job      `json:"job"`
+	Error      string    `json:"error,omitempty"`
+	ErrorClass string    `json:"error_class,omitempty"`
+	WorkerID   string    `json:"worker_id,omitempty"`
+	DeadAt     time.Time `json:"dead_at"`
+	// PayloadEditedAt is set once an admin has changed the payload for a replay.
+	PayloadEditedAt *time.Time `json:"payload_edited_at,omitempty"`
+}
+
+// DeadLetterFilter selects dead letters. Empty fields match every dead letter.
+type DeadLetterFilter struct {
+	ErrorClass string
+	JobIDs     []string
+}
+
+func (f DeadLetterFilter) matches(dl *DeadLetter) bool {
+	return f.ErrorClass == "" || strings.EqualFold(f.ErrorClass, dl.ErrorClass)
+}
+
+// DeadLetterQueue is implemented by queues that record why jobs failed and keep the jobs that
+// ran out of attempts in a dead-letter stream, from where they can be inspected and replayed.
+type DeadLetterQueue interface {
+	// NackWithFailure is Nack that records failure in the job's attempt timeline.
+	NackWithFailure(ctx context.Context, queueName, jobID string, failure Failure) error
+	// ListDeadLetters lists the dead letters of a queue matching filter, most recent first.
+	ListDeadLetters(ctx context.Context, queueName string, filter DeadLetterFilter, limit, offset int) ([]*DeadLetter, error)
+	// GetDeadLetter returns the dead letter of a job, or ErrDeadLetterNotFound.
+	GetDeadLetter(ctx context.Context, queueName, jobID string) (*DeadLetter, error)
+	// UpdateDeadLetterPayload replaces the payload a dead letter is replayed with.
+	UpdateDeadLetterPayload(ctx context.Context, queueName, jobID string, payload json.RawMessage) (*DeadLetter, error)
+	// ReplayDeadLetters re-enqueues up to limit dead letters matching filter with fresh attempts.
+	ReplayDeadLetters(ctx context.Context, queueName string, filter DeadLetterFilter, limit int) (replayed []string, err error)
+}
+
+// recordFailure appends the failure of the current attempt to the job's timeline.
+func (job *Job) recordFailure(failure Failure) {
+	failure.Attempt = job.Attempts
+	if failure.FailedAt.IsZero() {
+		failure.FailedAt = time.Now().UTC()
+	}
+	if len(failure.Stack) > maxStackSize {
+		failure.Stack = failure.Stack[:maxStackSize]
+	}
+	// Only the latest stack is kept; the earlier ones would bloat every retry of the job.
+	for i := range job.Failures {
+		job.Failures[i].Stack = ""
+	}
+	job.Failures = append(job.Failures, failure)
+	if n := len(job.Failures); n > maxFailures {
+		job.Failures = job.Failures[n-maxFailures:]
+	}
+}
+
+// lastFailure returns the most recent failed attempt of the job.
+func (job *Job) lastFailure() Failure {
+	if len(job.Failures) == 0 {
+		return Failure{}
+	}
+	return job.Failures[len(job.Failures)-1]
+}
+
+// deadLetter moves a job that ran out of attempts into the dead-letter stream of queueName.
+func (q *RedisQueue) deadLetter(ctx context.Context, queueName string, job *Job) error {
+	last := job.lastFailure()
+	dl := &DeadLetter{
+		Queue:      queueName,
+		Job:        job,
+		Error:      last.Error,
+		ErrorClass: last.ErrorClass,
+		WorkerID:   last.WorkerID,
+		DeadAt:     time.Now().UTC(),
+	}
+	return q.writeDeadLetter(ctx, queueName, dl)
+}
+
+// replaceDeadLetterScript adds a dead letter and points the index at it, dropping the entry it
+// replaces. Entries are immutable, so an edited dead letter moves to the end of the stream.
+var replaceDeadLetterScript = redis.NewScript(`
+local id = redis.call("XADD", KEYS[1], "MAXLEN", "~", ARGV[3], "*", "dead_letter", ARGV[2])
+local old = redis.call("HGET", KEYS[2], ARGV[1])
+if old then
+	redis.call("XDEL", KEYS[1], old)
+end
+redis.call("HSET", KEYS[2], ARGV[1], id)
+return id
+`)
+
+func (q *RedisQueue) writeDeadLetter(ctx context.Context, queueName string, dl *DeadLetter) error {
+	dl.EntryID = ""
+	dlBytes, err := json.Marshal(dl)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	keys := []string{q.deadLetterKey(queueName), q.deadLetterIndexKey(queueName)}
+	entryID, err := replaceDeadLetterScript.Run(ctx, q.client, keys, dl.Job.ID, dlBytes, deadLetterMaxLen).Text()
+	if err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	dl.EntryID = entryID
+	return nil
+}
+
+// ListDeadLetters lists the dead letters of queueName matching filter, most recent first.
+func (q *RedisQueue) ListDeadLetters(ctx context.Context, queueName string, filter DeadLetterFilter, limit, offset int) ([]*DeadLetter, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	if len(filter.JobIDs) > 0 {
+		return q.deadLettersByID(ctx, queueName, filter, limit, offset)
+	}
+
+	dls := []*DeadLetter{}
+	skipped := 0
+	end := "+"
+	for len(dls) < limit {
+		msgs, err := q.client.XRevRangeN(ctx, q.deadLetterKey(queueName), end, "-", 100).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dead letters: %w", err)
+		}
+		if end != "+" && len(msgs) > 0 && msgs[0].ID == end {
+			msgs = msgs[1:]
+		}
+		if len(msgs) == 0 {
+			break
+		}
+
+		for _, msg := range msgs {
+			dl, err := decodeDeadLetter(msg)
+			if err != nil || !filter.matches(dl) {
+				continue
+			}
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			dls = append(dls, dl)
+			if len(dls) == limit {
+				break
+			}
+		}
+		end = msgs[len(msgs)-1].ID
+	}
+	return dls, nil
+}
+
+func (q *RedisQueue) deadLettersByID(ctx context.Context, queueName string, filter DeadLetterFilter, limit, offset int) ([]*DeadLetter, error) {
+	dls := make([]*DeadLetter, 0, len(filter.JobIDs))
+	for _, jobID := range filter.JobIDs {
+		dl, err := q.GetDeadLetter(ctx, queueName, jobID)
+		if errors.Is(err, ErrDeadLetterNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if filter.matches(dl) {
+			dls = append(dls, dl)
+		}
+	}
+
+	if offset >= len(dls) {
+		return []*DeadLetter{}, nil
+	}
+	dls = dls[offset:]
+	if len(dls) > limit {
+		dls = dls[:limit]
+	}
+	return dls, nil
+}
+
+// GetDeadLetter returns the dead letter of jobID in queueName.
+func (q *RedisQueue) GetDeadLetter(ctx context.Context, queueName, jobID string) (*DeadLetter, error) {
+	entryID, err := q.client.HGet(ctx, q.deadLetterIndexKey(queueName), jobID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up dead letter: %w", err)
+	}
+
+	msgs, err := q.client.XRange(ctx, q.deadLetterKey(queueName), entryID, entryID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead letter: %w", err)
+	}
+	if len(msgs) == 0 {
+		// The entry was trimmed from the stream.
+		_ = q.client.HDel(ctx, q.deadLetterIndexKey(queueName), jobID).Err()
+		return nil, ErrDeadLetterNotFound
+	}
+	return decodeDeadLetter(msgs[0])
+}
+
+// UpdateDeadLetterPayload replaces the payload of the dead letter of jobID, which must be valid
+// JSON. The job is replayed with the new payload.
+func (q *RedisQueue) UpdateDeadLetterPayload(ctx context.Context, queueName, jobID string, payload json.RawMessage) (*DeadLetter, error) {
+	if !json.Valid(payload) {
+		return nil, ErrInvalidPayload
+	}
+
+	dl, err := q.GetDeadLetter(ctx, queueName, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	dl.Job.Payload = append(json.RawMessage(nil), payload...)
+	dl.PayloadEditedAt = &now
+	if err := q.writeDeadLetter(ctx, queueName, dl); err != nil {
+		return nil, err
+	}
+	return dl, nil
+}
+
+// ReplayDeadLetters re-enqueues up to limit dead letters of queueName matching filter. The jobs
+// start over with no attempts but keep their failure timeline.
+func (q *RedisQueue) ReplayDeadLetters(ctx context.Context, queueName string, filter DeadLetterFilter, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	replayed := []string{}
+	for len(replayed) < limit {
+		// Replayed jobs leave the stream, so every pass starts over from the top.
+		batch := limit - len(replayed)
+		if batch > 500 {
+			batch = 500
+		}
+		dls, err := q.ListDeadLetters(ctx, queueName, filter, batch, 0)
+		if err != nil {
+			return replayed, err
+		}
+		if len(dls) == 0 {
+			break
+		}
+		for _, dl := range dls {
+			if err := q.requeueFailed(ctx, queueName, dl.Job, dl.EntryID); err != nil {
+				return replayed, err
+			}
+			replayed = append(replayed, dl.Job.ID)
+		}
+		if len(filter.JobIDs) > 0 {
+			break
+		}
+	}
+	return replayed, nil
+}
+
+// failedJob returns a failed job from its dead letter, which may carry an edited payload and
+// outlives the job key, or else from the job key.
+func (q *RedisQueue) failedJob(ctx context.Context, queueName, jobID string) (*Job, string, error) {
+	dl, err := q.GetDeadLetter(ctx, queueName, jobID)
+	if err == nil {
+		return dl.Job, dl.EntryID, nil
+	}
+	if !errors.Is(err, ErrDeadLetterNotFound) {
+		return nil, "", err
+	}
+	job, _, err := q.getStoredJob(ctx, jobID)
+	return job, "", err
+}
+
+// requeueFailed enqueues a failed job again with fresh attempts and drops its dead letter, the
+// stream entry entryID or, if that is empty, the one the index points at. The dead letter is
+// only dropped once the job is back in its lane, so a failed enqueue loses neither.
+func (q *RedisQueue) requeueFailed(ctx context.Context, queueName string, job *Job, entryID string) error {
+	job.Attempts = 0
+	job.Status = "pending"
+	job.StreamID = ""
+	job.RunAt = nil
+
+	requeuePayload, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job for retry: %w", err)
+	}
+
+	l := q.jobLane(queueName, job)
+	if err := q.ensureGroup(ctx, l.stream); err != nil {
+		return err
+	}
+	msgID, err := q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: l.stream,
+		Values: map[string]interface{}{"job": requeuePayload},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to requeue failed job: %w", err)
+	}
+	if err := q.registerLane(ctx, l); err != nil {
+		return err
+	}
+
+	job.StreamID = msgID
+	if err := q.persistJob(ctx, job); err != nil {
+		return err
+	}
+	if err := q.removeDeadLetter(ctx, queueName, job.ID, entryID); err != nil {
+		return err
+	}
+
+	_ = q.client.SRem(ctx, q.failedKey(queueName), job.ID).Err()
+	_ = q.client.SAdd(ctx, q.pendingKey(queueName), job.ID).Err()
+	return nil
+}
+
+func (q *RedisQueue) removeDeadLetter(ctx context.Context, queueName, jobID, entryID string) error {
+	if entryID == "" {
+		var err error
+		entryID, err = q.client.HGet(ctx, q.deadLetterIndexKey(queueName), jobID).Result()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up dead letter: %w", err)
+		}
+	}
+
+	pipe := q.client.TxPipeline()
+	pipe.XDel(ctx, q.deadLetterKey(queueName), entryID)
+	pipe.HDel(ctx, q.deadLetterIndexKey(queueName), jobID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove dead letter: %w", err)
+	}
+	return nil
+}
+
+func decodeDeadLetter(msg redis.XMessage) (*DeadLetter, error) {
+	raw, ok := msg.Values["dead_letter"].(string)
+	if !ok {
+		return nil, fmt.Errorf("dead letter %s has no payload", msg.ID)
+	}
+
+	var dl DeadLetter
+	if err := json.Unmarshal([]byte(raw), &dl); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dead letter %s: %w", msg.ID, err)
+	}
+	if dl.Job == nil {
+		return nil, fmt.Errorf("dead letter %s has no job", msg.ID)
+	}
+	dl.EntryID = msg.ID
+	return &dl, nil
+}
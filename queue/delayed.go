@@ -0,0 +1,225 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultRetryMaxDelay caps the retry backoff when QueueRetryMaxDelay is not set.
+const defaultRetryMaxDelay = 15 * time.Minute
+
+// promoteBatch caps the jobs of one job type moved by a single promotion pass.
+const promoteBatch = 100
+
+// Promoter is implemented by queues whose scheduled jobs have to be moved into their lanes
+// once they are due. Workers call PromoteDue periodically.
+type Promoter interface {
+	PromoteDue(ctx context.Context, queueNames []string) (promoted int64, err error)
+}
+
+// Deferrer is implemented by queues which can put a dequeued job back without charging it an
+// attempt. Workers defer the jobs they couldn't run because Google blocked them, as the block
+// says nothing about the job itself. A deferred job waits like a job added with EnqueueAt: in the
+// scheduled jobs of its type, until a [Promoter] delivers it again.
+type Deferrer interface {
+	Defer(ctx context.Context, queueName, jobID string, runAt time.Time) error
+}
+
+// EnqueueAt adds a job that waits in the scheduled set of its job type until runAt. A runAt
+// that is not in the future enqueues the job right away. Deferred and backing-off jobs wait in
+// the same set (see schedule).
+func (q *RedisQueue) EnqueueAt(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (string, error) {
+	return q.enqueue(ctx, jobType, payload, runAt)
+}
+
+// EnqueueAfter adds a job that waits in the scheduled set of its job type for delay.
+func (q *RedisQueue) EnqueueAfter(ctx context.Context, jobType string, payload interface{}, delay time.Duration) (string, error) {
+	return q.enqueue(ctx, jobType, payload, time.Now().Add(delay))
+}
+
+// schedule stores job as scheduled and adds it to the scheduled set, scored by runAt in
+// Unix milliseconds. It is the one path into the set: EnqueueAt, Defer and the backoff of Nack
+// all go through it, so PromoteDue delivers their jobs alike.
+func (q *RedisQueue) schedule(ctx context.Context, queueName string, job *Job, runAt time.Time) error {
+	runAt = runAt.UTC()
+	job.Status = "scheduled"
+	job.RunAt = &runAt
+	if err := q.persistJob(ctx, job); err != nil {
+		return err
+	}
+
+	if err := q.client.ZAdd(ctx, q.scheduledKey(queueName), redis.Z{
+		Score:  float64(runAt.UnixMilli()),
+		Member: job.ID,
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to schedule job: %w", err)
+	}
+	return nil
+}
+
+// Defer implements [Deferrer]. The job leaves the processing set and waits in the scheduled set
+// until runAt, with its attempt count as it was before it was dequeued.
+func (q *RedisQueue) Defer(ctx context.Context, queueName, jobID string, runAt time.Time) error {
+	job, _, err := q.getStoredJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Attempts > 0 {
+		job.Attempts--
+	}
+
+	if job.StreamID != "" {
+		stream := q.jobLane(queueName, job).stream
+		if err := q.client.XAck(ctx, stream, q.cfg.QueueGroup, job.StreamID).Err(); err != nil {
+			return fmt.Errorf("failed to ack message before deferring: %w", err)
+		}
+		_ = q.client.XDel(ctx, stream, job.StreamID).Err()
+		job.StreamID = ""
+	}
+
+	if err := q.schedule(ctx, queueName, job, runAt); err != nil {
+		return err
+	}
+	if err := q.client.SRem(ctx, q.processingKey(queueName), jobID).Err(); err != nil {
+		return fmt.Errorf("failed to clear processing flag: %w", err)
+	}
+	return nil
+}
+
+// retryDelay returns how long a job waits after failing its attempt-th attempt: the base
+// delay (REDIS_QUEUE_RETRY_BASE_DELAY) doubled for every earlier attempt and capped at the
+// maximum, of which a random part up to half is dropped so that jobs which failed together do
+// not retry together. Without a base delay jobs are retried right away.
+func retryDelay(cfg config.RedisConfig, attempt int) time.Duration {
+	base := cfg.QueueRetryBaseDelay
+	if base <= 0 {
+		return 0
+	}
+	maxDelay := cfg.QueueRetryMaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+	if maxDelay < base {
+		maxDelay = base
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	half := delay / 2
+	return delay - half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// promoteScript moves a due job from the scheduled set into its lane. ARGV[2] is the job
+// without a stream_id, which is only known once the entry is added, so the stored copy gets
+// it appended as the last field. The job is not decoded, so that its payload is stored byte for
+// byte (cjson would turn empty arrays into objects and round large integers).
+var promoteScript = redis.NewScript(`
+if redis.call("ZREM", KEYS[1], ARGV[1]) == 0 then
+	return false
+end
+local id = redis.call("XADD", KEYS[2], "*", "job", ARGV[2])
+redis.call("SADD", KEYS[3], ARGV[1])
+redis.call("SET", KEYS[4], string.sub(ARGV[2], 1, -2) .. ',"stream_id":"' .. id .. '"}', "PX", ARGV[3])
+return id
+`)
+
+// PromoteDue moves the scheduled jobs of queueNames that are due into their lanes and returns
+// how many it moved. A job is moved atomically, so any number of workers can promote at once.
+func (q *RedisQueue) PromoteDue(ctx context.Context, queueNames []string) (int64, error) {
+	var promoted int64
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	for _, queueName := range queueNames {
+		jobIDs, err := q.client.ZRangeByScore(ctx, q.scheduledKey(queueName), &redis.ZRangeBy{
+			Min:   "-inf",
+			Max:   now,
+			Count: promoteBatch,
+		}).Result()
+		if err != nil {
+			return promoted, fmt.Errorf("failed to list due jobs: %w", err)
+		}
+
+		for _, jobID := range jobIDs {
+			ok, err := q.promote(ctx, queueName, jobID)
+			if err != nil {
+				return promoted, err
+			}
+			if ok {
+				promoted++
+			}
+		}
+	}
+	return promoted, nil
+}
+
+func (q *RedisQueue) promote(ctx context.Context, queueName, jobID string) (bool, error) {
+	job, _, err := q.getStoredJob(ctx, jobID)
+	if errors.Is(err, redis.Nil) {
+		// The job expired or was cleared, so there is nothing left to deliver.
+		_ = q.client.ZRem(ctx, q.scheduledKey(queueName), jobID).Err()
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	l := q.jobLane(queueName, job)
+	if err := q.ensureGroup(ctx, l.stream); err != nil {
+		return false, err
+	}
+
+	job.Status = "pending"
+	job.StreamID = ""
+	jobBytes, err := json.Marshal(job)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal job for promotion: %w", err)
+	}
+
+	keys := []string{q.scheduledKey(queueName), l.stream, q.pendingKey(queueName), q.jobKey(jobID)}
+	err = promoteScript.Run(ctx, q.client, keys, jobID, jobBytes, jobTTL.Milliseconds()).Err()
+	if errors.Is(err, redis.Nil) {
+		// Another worker promoted it first.
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to promote job: %w", err)
+	}
+	if err := q.registerLane(ctx, l); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// listScheduled lists the scheduled jobs of queueName, the next one due first.
+func (q *RedisQueue) listScheduled(ctx context.Context, queueName string, limit, offset int) ([]*Job, error) {
+	jobIDs, err := q.client.ZRange(ctx, q.scheduledKey(queueName), int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled jobs: %w", err)
+	}
+
+	jobs := make([]*Job, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		job, err := q.GetJob(ctx, jobID)
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,113 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// IdempotentPayload is implemented by payloads that identify the work they describe. Jobs
+// enqueued with the same key within the dedup window are collapsed into the first one.
+type IdempotentPayload interface {
+	IdempotencyKey() string
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey makes jobs enqueued with the returned context use key instead of the key of
+// their payload. Keys are limited to 255 characters; an empty or longer key leaves the context
+// unchanged.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	key = strings.TrimSpace(key)
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key stored on the context, or "" if none is set.
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if key, ok := ctx.Value(idempotencyKey{}).(string); ok {
+		return key
+	}
+	return ""
+}
+
+// jobIdempotencyKey returns the key deduplicating a job: the one on ctx, else the payload's.
+func jobIdempotencyKey(ctx context.Context, payload interface{}) string {
+	if key := IdempotencyKeyFromContext(ctx); key != "" {
+		return key
+	}
+	if p, ok := payload.(IdempotentPayload); ok {
+		return p.IdempotencyKey()
+	}
+	return ""
+}
+
+// swapIdempotencyScript sets KEYS[1] to ARGV[2] if it holds ARGV[1] ("" for a missing key) and
+// returns the value it found. An empty ARGV[2] deletes the key.
+var swapIdempotencyScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if not current then
+	current = ""
+end
+if current ~= ARGV[1] then
+	return current
+end
+if ARGV[2] == "" then
+	redis.call("DEL", KEYS[1])
+else
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+end
+return current
+`)
+
+// reserveIdempotencyKey claims key for jobID for the dedup window. It returns the ID of the job
+// that already holds the key, or "" if jobID got it. Jobs that failed or expired give up
+// their key, so the work can be enqueued again.
+func (q *RedisQueue) reserveIdempotencyKey(ctx context.Context, jobType, tenant, key, jobID string) (string, error) {
+	redisKey := q.idempotencyKey(jobType, tenant, key)
+	window := q.cfg.QueueDedupWindow.Milliseconds()
+
+	expected := ""
+	for attempt := 0; attempt < 3; attempt++ {
+		current, err := swapIdempotencyScript.Run(ctx, q.client, []string{redisKey}, expected, jobID, window).Text()
+		if err != nil {
+			return "", fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if current == expected {
+			return "", nil
+		}
+
+		status, err := q.GetJobStatus(ctx, current)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return "", err
+		}
+		if err == nil && status != "failed" {
+			return current, nil
+		}
+		// Take over the key of the dead job; another enqueue may race us to it.
+		expected = current
+	}
+	return "", fmt.Errorf("failed to reserve idempotency key %q: too much contention", key)
+}
+
+// releaseIdempotencyKey drops the key of a job that could not be enqueued.
+func (q *RedisQueue) releaseIdempotencyKey(ctx context.Context, jobType, tenant, key, jobID string) {
+	_ = swapIdempotencyScript.Run(ctx, q.client, []string{q.idempotencyKey(jobType, tenant, key)}, jobID, "", 0).Err()
+}
+
+func (q *RedisQueue) idempotencyKey(jobType, tenant, key string) string {
+	return fmt.Sprintf("queue:%s:idempotency:%s:%s", jobType, tenant, key)
+}
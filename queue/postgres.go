@@ -0,0 +1,1110 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/lib/pq"
+)
+
+// ErrJobNotFound is returned by PostgresQueue for job IDs it has no job for.
+var ErrJobNotFound = errors.New("job not found")
+
+// postgresPollInterval is how often an idle Dequeue looks for new jobs while it waits for up
+// to QueueBlockTimeout.
+const postgresPollInterval = 200 * time.Millisecond
+
+// postgresPurgeInterval is how often expired jobs, cancellations and metrics are deleted.
+const postgresPurgeInterval = time.Minute
+
+// PostgresQueue implements the Queue interface on the queue_jobs table, for deployments that
+// run Postgres but not Redis. Workers claim jobs with SELECT ... FOR UPDATE SKIP LOCKED and
+// hold them for the visibility timeout, after which another worker may claim them again.
+// The queue settings of the Redis config apply as they do to RedisQueue.
+type PostgresQueue struct {
+	db        *sql.DB
+	cfg       config.RedisConfig
+	scheduler *fairScheduler
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+var (
+	_ FairQueue       = (*PostgresQueue)(nil)
+	_ Promoter        = (*PostgresQueue)(nil)
+	_ DeadLetterQueue = (*PostgresQueue)(nil)
+)
+
+// NewPostgresQueue creates a queue on the Postgres database at connString, which must have the
+// queue tables of the migrations.
+func NewPostgresQueue(connString string, cfg config.RedisConfig) (*PostgresQueue, error) {
+	db, err := sql.Open("postgres", connString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PostgreSQL queue: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to PostgreSQL queue: %w", err)
+	}
+
+	return &PostgresQueue{
+		db:        db,
+		cfg:       cfg,
+		scheduler: newFairScheduler(cfg.QueuePriorityWeights),
+	}, nil
+}
+
+// Close closes the queue's database connections.
+func (q *PostgresQueue) Close() error {
+	return q.db.Close()
+}
+
+// Enqueue adds a job to the queue. The job goes to the lane of the priority and tenant
+// stored on ctx, falling back to DefaultPriority(jobType) and no tenant.
+func (q *PostgresQueue) Enqueue(ctx context.Context, jobType string, payload interface{}) (string, error) {
+	return q.enqueue(ctx, jobType, payload, time.Time{})
+}
+
+// EnqueueAt adds a job that stays scheduled until runAt. A runAt that is not in the future
+// enqueues the job right away.
+func (q *PostgresQueue) EnqueueAt(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (string, error) {
+	return q.enqueue(ctx, jobType, payload, runAt)
+}
+
+// EnqueueAfter adds a job that stays scheduled for delay.
+func (q *PostgresQueue) EnqueueAfter(ctx context.Context, jobType string, payload interface{}, delay time.Duration) (string, error) {
+	return q.enqueue(ctx, jobType, payload, time.Now().Add(delay))
+}
+
+// enqueue adds a job that is delivered at runAt, or right away if runAt is not in the future.
+// A job with the idempotency key of a job enqueued within the dedup window is not added; the
+// ID of the earlier job is returned instead.
+func (q *PostgresQueue) enqueue(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (string, error) {
+	priority := PriorityFromContext(ctx)
+	if priority == "" {
+		priority = DefaultPriority(jobType)
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	job := &Job{
+		ID:          fmt.Sprintf("%s-%d", jobType, time.Now().UnixNano()),
+		Type:        jobType,
+		Payload:     payloadBytes,
+		CreatedAt:   time.Now().UTC(),
+		Attempts:    0,
+		MaxAttempts: 3,
+		Status:      "pending",
+		Priority:    priority,
+		Tenant:      TenantFromContext(ctx),
+	}
+	if meta := EnqueueMetaFromContext(ctx); !meta.isEmpty() {
+		job.EnqueueMeta = &meta
+	}
+	if runAt.After(job.CreatedAt) {
+		runAt = runAt.UTC()
+		job.Status = "scheduled"
+		job.RunAt = &runAt
+	} else {
+		runAt = job.CreatedAt
+	}
+
+	var key string
+	if q.cfg.QueueDedupWindow > 0 {
+		key = jobIdempotencyKey(ctx, payload)
+	}
+
+	jobID := job.ID
+	err = q.inTx(ctx, func(tx *sql.Tx) error {
+		if key != "" {
+			existingID, err := q.duplicateJob(ctx, tx, jobType, job.Tenant, key)
+			if err != nil {
+				return err
+			}
+			if existingID != "" {
+				jobID = existingID
+				return nil
+			}
+			job.IdempotencyKey = key
+		}
+		return q.insertJob(ctx, tx, job, runAt)
+	})
+	if err != nil {
+		return "", err
+	}
+	if jobID != job.ID {
+		return jobID, nil
+	}
+
+	// Best-effort enqueue metrics (do not fail enqueue if metrics cannot be recorded).
+	_ = q.recordEnqueueMetric(ctx, jobType, job.EnqueueMeta)
+
+	return jobID, nil
+}
+
+func (q *PostgresQueue) insertJob(ctx context.Context, tx *sql.Tx, job *Job, runAt time.Time) error {
+	jobBytes, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO queue_jobs (id, type, priority, tenant, status, run_at, idempotency_key, job, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)`,
+		job.ID, job.Type, string(job.Priority), job.Tenant, job.Status, runAt, job.IdempotencyKey, string(jobBytes), job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add job to queue: %w", err)
+	}
+	return nil
+}
+
+// duplicateJob returns the ID of a job enqueued with key within the dedup window that has not
+// failed, or "" if there is none. The key stays locked until tx ends, so that concurrent
+// enqueues of the same work see each other.
+func (q *PostgresQueue) duplicateJob(ctx context.Context, tx *sql.Tx, jobType, tenant, key string) (string, error) {
+	lockKey := fmt.Sprintf("queue:%s:idempotency:%s:%s", jobType, tenant, key)
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey); err != nil {
+		return "", fmt.Errorf("failed to lock idempotency key: %w", err)
+	}
+
+	var jobID string
+	err := tx.QueryRowContext(ctx, `
+		SELECT id FROM queue_jobs
+		WHERE type = $1 AND tenant = $2 AND idempotency_key = $3 AND status <> 'failed' AND created_at > $4
+		ORDER BY created_at DESC
+		LIMIT 1`,
+		jobType, tenant, key, time.Now().Add(-q.cfg.QueueDedupWindow)).Scan(&jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	return jobID, nil
+}
+
+// Dequeue retrieves the next job of one job type, serving its priority lanes by weight.
+func (q *PostgresQueue) Dequeue(ctx context.Context, queueName string) (*Job, error) {
+	return q.DequeueFair(ctx, []string{queueName})
+}
+
+// DequeueFair retrieves the next job of any of queueNames. Jobs whose visibility timeout ran
+// out are reclaimed first; otherwise the lanes are read in the order picked by the fair
+// scheduler, and while all of them are empty it polls for up to QueueBlockTimeout.
+func (q *PostgresQueue) DequeueFair(ctx context.Context, queueNames []string) (*Job, error) {
+	deadline := time.Now().Add(q.cfg.QueueBlockTimeout)
+	for {
+		job, err := q.claimStale(ctx, queueNames)
+		if err != nil || job != nil {
+			return job, err
+		}
+		if job, err := q.readFair(ctx, queueNames); err != nil || job != nil {
+			return job, err
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return nil, nil
+		}
+		if wait > postgresPollInterval {
+			wait = postgresPollInterval
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (q *PostgresQueue) claimStale(ctx context.Context, queueNames []string) (*Job, error) {
+	return q.claim(ctx, `
+		SELECT job FROM queue_jobs
+		WHERE type = ANY($1) AND status = 'processing' AND locked_until < now()
+		ORDER BY locked_until
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`,
+		pq.Array(queueNames))
+}
+
+// readFair claims the next ready job, trying priorities in the order of the fair scheduler
+// and, within a priority, the job types and then the tenants in turn.
+func (q *PostgresQueue) readFair(ctx context.Context, queueNames []string) (*Job, error) {
+	now := time.Now().UTC()
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT DISTINCT type, priority, tenant FROM queue_jobs
+		WHERE type = ANY($1) AND status = 'pending' AND run_at <= $2
+		ORDER BY type, priority, tenant`,
+		pq.Array(queueNames), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queue lanes: %w", err)
+	}
+	tenants := make(map[string][]string)
+	for rows.Next() {
+		var jobType, priority, tenant string
+		if err := rows.Scan(&jobType, &priority, &tenant); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan queue lane: %w", err)
+		}
+		key := priority + ":" + jobType
+		tenants[key] = append(tenants[key], tenant)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list queue lanes: %w", err)
+	}
+
+	empty := make(map[Priority]bool, len(Priorities))
+	for {
+		p := q.scheduler.peek(empty)
+		if p == "" {
+			return nil, nil
+		}
+
+		var jobTypes []string
+		for _, queueName := range queueNames {
+			if len(tenants[string(p)+":"+queueName]) > 0 {
+				jobTypes = append(jobTypes, queueName)
+			}
+		}
+
+		typeKey := string(p)
+		firstType := q.scheduler.start(typeKey, len(jobTypes))
+		for i := range jobTypes {
+			ti := (firstType + i) % len(jobTypes)
+			jobType := jobTypes[ti]
+			tenantKey := string(p) + ":" + jobType
+			laneTenants := tenants[tenantKey]
+			firstLane := q.scheduler.start(tenantKey, len(laneTenants))
+			for j := range laneTenants {
+				li := (firstLane + j) % len(laneTenants)
+				job, err := q.claim(ctx, `
+					SELECT job FROM queue_jobs
+					WHERE type = $1 AND priority = $2 AND tenant = $3 AND status = 'pending' AND run_at <= $4
+					ORDER BY run_at, seq
+					LIMIT 1
+					FOR UPDATE SKIP LOCKED`,
+					jobType, string(p), laneTenants[li], now)
+				if err != nil {
+					return nil, err
+				}
+				if job == nil {
+					// Other workers claimed the lane's jobs first.
+					continue
+				}
+
+				q.scheduler.take(p, empty)
+				q.scheduler.advance(typeKey, ti)
+				q.scheduler.advance(tenantKey, li)
+				return job, nil
+			}
+		}
+		empty[p] = true
+		q.scheduler.idle(p)
+	}
+}
+
+// claim locks the job selected by query, which must select one row FOR UPDATE SKIP LOCKED,
+// and hands it to the caller for one attempt of the visibility timeout.
+func (q *PostgresQueue) claim(ctx context.Context, query string, args ...interface{}) (*Job, error) {
+	var job *Job
+	err := q.inTx(ctx, func(tx *sql.Tx) error {
+		var raw []byte
+		err := tx.QueryRowContext(ctx, query, args...).Scan(&raw)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to claim job: %w", err)
+		}
+
+		claimed, err := decodeStoredJob(raw)
+		if err != nil {
+			return err
+		}
+		claimed.Attempts++
+		claimed.Status = "processing"
+		jobBytes, err := json.Marshal(claimed)
+		if err != nil {
+			return fmt.Errorf("failed to marshal job for storage: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE queue_jobs
+			SET status = 'processing', locked_until = now() + make_interval(secs => $2), job = $3
+			WHERE id = $1`,
+			claimed.ID, q.cfg.QueueVisibilityTimeout.Seconds(), string(jobBytes))
+		if err != nil {
+			return fmt.Errorf("failed to mark job processing: %w", err)
+		}
+		job = claimed
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Ack acknowledges a job as completed
+func (q *PostgresQueue) Ack(ctx context.Context, queueName, jobID string) error {
+	return q.inTx(ctx, func(tx *sql.Tx) error {
+		job, err := q.lockJob(ctx, tx, jobID)
+		if err != nil {
+			return err
+		}
+		job.Status = "completed"
+		return q.saveJob(ctx, tx, job, time.Time{}, true)
+	})
+}
+
+// Nack marks a job as failed or retries it: after the retry backoff when one is configured,
+// right away otherwise.
+func (q *PostgresQueue) Nack(ctx context.Context, queueName, jobID string) error {
+	return q.NackWithFailure(ctx, queueName, jobID, Failure{})
+}
+
+// NackWithFailure is Nack that adds failure to the job's attempt timeline. A job out of
+// attempts becomes a dead letter.
+func (q *PostgresQueue) NackWithFailure(ctx context.Context, queueName, jobID string, failure Failure) error {
+	return q.inTx(ctx, func(tx *sql.Tx) error {
+		job, err := q.lockJob(ctx, tx, jobID)
+		if err != nil {
+			return err
+		}
+		job.recordFailure(failure)
+
+		if delay := retryDelay(q.cfg, job.Attempts); delay > 0 && job.Attempts < job.MaxAttempts {
+			runAt := time.Now().Add(delay).UTC()
+			job.Status = "scheduled"
+			job.RunAt = &runAt
+			return q.saveJob(ctx, tx, job, runAt, false)
+		}
+
+		if job.Attempts < job.MaxAttempts {
+			// Retries go to the back of their lane.
+			job.Status = "pending"
+			return q.saveJob(ctx, tx, job, time.Now().UTC(), false)
+		}
+
+		job.Status = "failed"
+		if err := q.saveJob(ctx, tx, job, time.Time{}, false); err != nil {
+			return err
+		}
+		last := job.lastFailure()
+		return q.writeDeadLetter(ctx, tx, &DeadLetter{
+			Queue:      queueName,
+			Job:        job,
+			Error:      last.Error,
+			ErrorClass: last.ErrorClass,
+			WorkerID:   last.WorkerID,
+			DeadAt:     time.Now().UTC(),
+		})
+	})
+}
+
+// Defer implements [Deferrer]. The job is scheduled for runAt with its attempt count as it was
+// before it was claimed.
+func (q *PostgresQueue) Defer(ctx context.Context, queueName, jobID string, runAt time.Time) error {
+	return q.inTx(ctx, func(tx *sql.Tx) error {
+		job, err := q.lockJob(ctx, tx, jobID)
+		if err != nil {
+			return err
+		}
+		if job.Attempts > 0 {
+			job.Attempts--
+		}
+		runAt = runAt.UTC()
+		job.Status = "scheduled"
+		job.RunAt = &runAt
+		return q.saveJob(ctx, tx, job, runAt, false)
+	})
+}
+
+// GetJobStatus gets the status of a job
+func (q *PostgresQueue) GetJobStatus(ctx context.Context, jobID string) (string, error) {
+	var status string
+	err := q.db.QueryRowContext(ctx, `SELECT status FROM queue_jobs WHERE id = $1`, jobID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to get job details: %w", ErrJobNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get job details: %w", err)
+	}
+	return status, nil
+}
+
+// GetQueueStats gets statistics for a queue
+func (q *PostgresQueue) GetQueueStats(ctx context.Context, queueName string) (map[string]int64, error) {
+	stats := map[string]int64{
+		"pending":    0,
+		"scheduled":  0,
+		"processing": 0,
+		"completed":  0,
+		"failed":     0,
+	}
+
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT status, count(*) FROM queue_jobs
+		WHERE type = $1 AND status IN ('pending', 'scheduled', 'processing', 'completed', 'failed')
+		GROUP BY status`,
+		queueName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan queue count: %w", err)
+		}
+		stats[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get queue counts: %w", err)
+	}
+	return stats, nil
+}
+
+func (q *PostgresQueue) CancelJob(ctx context.Context, queueName, jobID string) error {
+	if jobID == "" {
+		return fmt.Errorf("missing job id")
+	}
+	expiresAt := time.Now().Add(jobTTL)
+
+	// Always set the cancel flag (even if the job was cleared), so workers can observe it.
+	if err := q.setCancelFlag(ctx, jobID, expiresAt); err != nil {
+		return err
+	}
+
+	// Best-effort: mark a job that hasn't started canceled (do not delete; keep for debugging),
+	// which keeps it from starting later. A processing job keeps its row for the worker to ack,
+	// and a finished one its outcome; the cancel flag alone is recorded for those.
+	_, _ = q.db.ExecContext(ctx, `
+		UPDATE queue_jobs
+		SET status = 'canceled', job = jsonb_set(job, '{status}', '"canceled"'), expires_at = $2
+		WHERE id = $1 AND status IN ('pending', 'scheduled')`,
+		jobID, expiresAt)
+	return nil
+}
+
+func (q *PostgresQueue) CancelProcessing(ctx context.Context, queueName string) (int64, error) {
+	// Set cancel flags only; the workers observe them, abort and ack their jobs.
+	res, err := q.db.ExecContext(ctx, `
+		INSERT INTO queue_job_cancels (job_id, expires_at)
+		SELECT id, $2::timestamptz FROM queue_jobs WHERE type = $1 AND status = 'processing'
+		ON CONFLICT (job_id) DO UPDATE SET expires_at = EXCLUDED.expires_at`,
+		queueName, time.Now().Add(jobTTL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to set cancel flags: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (q *PostgresQueue) setCancelFlag(ctx context.Context, jobID string, expiresAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, `
+		INSERT INTO queue_job_cancels (job_id, expires_at) VALUES ($1, $2)
+		ON CONFLICT (job_id) DO UPDATE SET expires_at = EXCLUDED.expires_at`,
+		jobID, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to set cancel flag: %w", err)
+	}
+	return nil
+}
+
+func (q *PostgresQueue) IsJobCanceled(ctx context.Context, jobID string) (bool, error) {
+	if jobID == "" {
+		return false, nil
+	}
+	var canceled bool
+	err := q.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM queue_job_cancels WHERE job_id = $1 AND expires_at > $2)`,
+		jobID, time.Now()).Scan(&canceled)
+	if err != nil {
+		return false, fmt.Errorf("failed to check cancel flag: %w", err)
+	}
+	return canceled, nil
+}
+
+func (q *PostgresQueue) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	var raw []byte
+	err := q.db.QueryRowContext(ctx, `SELECT job FROM queue_jobs WHERE id = $1`, jobID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get job details: %w", ErrJobNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job details: %w", err)
+	}
+	return decodeStoredJob(raw)
+}
+
+func (q *PostgresQueue) ListJobs(ctx context.Context, queueName, state string, limit, offset int) ([]*Job, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	state = strings.ToLower(strings.TrimSpace(state))
+	order := "created_at DESC"
+	switch state {
+	case "scheduled":
+		// The next one due first.
+		order = "run_at, seq"
+	case "pending", "processing", "completed", "failed":
+	default:
+		return nil, fmt.Errorf("invalid state %q", state)
+	}
+
+	return q.queryJobs(ctx, `
+		SELECT job FROM queue_jobs
+		WHERE type = $1 AND status = $2
+		ORDER BY `+order+`
+		LIMIT $3 OFFSET $4`,
+		queueName, state, limit, offset)
+}
+
+// GetBacklog returns the most recently queued jobs that are waiting or running, newest first.
+func (q *PostgresQueue) GetBacklog(ctx context.Context, queueName string, limit int) ([]*Job, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	return q.queryJobs(ctx, `
+		SELECT job FROM queue_jobs
+		WHERE type = $1 AND status IN ('pending', 'processing')
+		ORDER BY run_at DESC, seq DESC
+		LIMIT $2`,
+		queueName, limit)
+}
+
+func (q *PostgresQueue) GetEnqueueMetrics(ctx context.Context, queueName string, minutes int) (map[string]int64, error) {
+	if minutes <= 0 {
+		minutes = 60
+	}
+	if minutes > 24*60 {
+		minutes = 24 * 60
+	}
+
+	since := time.Now().UTC().Truncate(time.Minute).Add(-time.Duration(minutes) * time.Minute)
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT source, sum(count)::bigint FROM queue_enqueue_metrics
+		WHERE queue = $1 AND minute > $2
+		GROUP BY source`,
+		queueName, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enqueue metrics: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]int64)
+	for rows.Next() {
+		var source string
+		var n int64
+		if err := rows.Scan(&source, &n); err != nil {
+			return nil, fmt.Errorf("failed to read enqueue metrics: %w", err)
+		}
+		out[source] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read enqueue metrics: %w", err)
+	}
+	return out, nil
+}
+
+func (q *PostgresQueue) recordEnqueueMetric(ctx context.Context, queueName string, meta *EnqueueMeta) error {
+	_, err := q.db.ExecContext(ctx, `
+		INSERT INTO queue_enqueue_metrics (queue, minute, source, count) VALUES ($1, $2, $3, 1)
+		ON CONFLICT (queue, minute, source) DO UPDATE SET count = queue_enqueue_metrics.count + 1`,
+		queueName, time.Now().UTC().Truncate(time.Minute), enqueueSource(meta))
+	return err
+}
+
+func (q *PostgresQueue) ClearQueue(ctx context.Context, queueName string) (int64, error) {
+	return q.deleteJobs(ctx, queueName, "pending", "scheduled")
+}
+
+func (q *PostgresQueue) ClearFailed(ctx context.Context, queueName string) (int64, error) {
+	// Dead letters are the failed jobs, so they go too.
+	return q.deleteJobs(ctx, queueName, "failed")
+}
+
+func (q *PostgresQueue) ClearProcessing(ctx context.Context, queueName string) (int64, error) {
+	return q.deleteJobs(ctx, queueName, "processing")
+}
+
+func (q *PostgresQueue) deleteJobs(ctx context.Context, queueName string, statuses ...string) (int64, error) {
+	res, err := q.db.ExecContext(ctx, `DELETE FROM queue_jobs WHERE type = $1 AND status = ANY($2)`,
+		queueName, pq.Array(statuses))
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear %s jobs: %w", strings.Join(statuses, "/"), err)
+	}
+	return res.RowsAffected()
+}
+
+func (q *PostgresQueue) RetryFailed(ctx context.Context, queueName string, limit int) (int64, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT id FROM queue_jobs WHERE type = $1 AND status = 'failed' ORDER BY created_at LIMIT $2`,
+		queueName, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list failed jobs: %w", err)
+	}
+	var jobIDs []string
+	for rows.Next() {
+		var jobID string
+		if err := rows.Scan(&jobID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to list failed jobs: %w", err)
+		}
+		jobIDs = append(jobIDs, jobID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to list failed jobs: %w", err)
+	}
+
+	var retried int64
+	for _, jobID := range jobIDs {
+		ok, err := q.requeueFailed(ctx, queueName, jobID)
+		if err != nil {
+			return retried, err
+		}
+		if ok {
+			retried++
+		}
+	}
+	return retried, nil
+}
+
+// PromoteDue makes the scheduled jobs of queueNames that are due pending and returns how many
+// it promoted. Now and then it also deletes expired jobs, cancel flags and metrics.
+func (q *PostgresQueue) PromoteDue(ctx context.Context, queueNames []string) (int64, error) {
+	if err := q.purgeExpired(ctx); err != nil {
+		return 0, err
+	}
+
+	res, err := q.db.ExecContext(ctx, `
+		UPDATE queue_jobs SET status = 'pending', job = jsonb_set(job, '{status}', '"pending"')
+		WHERE id IN (
+			SELECT id FROM queue_jobs
+			WHERE type = ANY($1) AND status = 'scheduled' AND run_at <= $2
+			ORDER BY run_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)`,
+		pq.Array(queueNames), time.Now().UTC(), promoteBatch*len(queueNames))
+	if err != nil {
+		return 0, fmt.Errorf("failed to promote due jobs: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// purgeExpired deletes finished jobs and cancel flags past their expiry, enqueue metrics past
+// their retention and the oldest dead letters of job types with too many, at most once per
+// postgresPurgeInterval.
+func (q *PostgresQueue) purgeExpired(ctx context.Context) error {
+	q.mu.Lock()
+	if time.Since(q.lastPurge) < postgresPurgeInterval {
+		q.mu.Unlock()
+		return nil
+	}
+	q.lastPurge = time.Now()
+	q.mu.Unlock()
+
+	now := time.Now()
+	if _, err := q.db.ExecContext(ctx, `DELETE FROM queue_jobs WHERE expires_at < $1`, now); err != nil {
+		return fmt.Errorf("failed to delete expired jobs: %w", err)
+	}
+	if _, err := q.db.ExecContext(ctx, `DELETE FROM queue_job_cancels WHERE expires_at < $1`, now); err != nil {
+		return fmt.Errorf("failed to delete expired cancel flags: %w", err)
+	}
+	if _, err := q.db.ExecContext(ctx, `DELETE FROM queue_enqueue_metrics WHERE minute < $1`, now.Add(-enqueueMetricTTL)); err != nil {
+		return fmt.Errorf("failed to delete expired enqueue metrics: %w", err)
+	}
+	_, err := q.db.ExecContext(ctx, `
+		DELETE FROM queue_jobs WHERE id IN (
+			SELECT id FROM (
+				SELECT id, row_number() OVER (PARTITION BY type ORDER BY dead_letter_at DESC) AS n
+				FROM queue_jobs WHERE status = 'failed'
+			) ranked
+			WHERE n > $1
+		)`,
+		deadLetterMaxLen)
+	if err != nil {
+		return fmt.Errorf("failed to trim dead letters: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetters lists the dead letters of queueName matching filter, most recent first.
+func (q *PostgresQueue) ListDeadLetters(ctx context.Context, queueName string, filter DeadLetterFilter, limit, offset int) ([]*DeadLetter, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	jobIDs := filter.JobIDs
+	if jobIDs == nil {
+		jobIDs = []string{}
+	}
+	rows, err := q.db.QueryContext(ctx, `
+		SELECT dead_letter FROM queue_jobs
+		WHERE type = $1 AND status = 'failed' AND dead_letter IS NOT NULL
+			AND ($2 = '' OR lower(dead_letter->>'error_class') = lower($2))
+			AND (cardinality($3::text[]) = 0 OR id = ANY($3))
+		ORDER BY dead_letter_at DESC, seq DESC
+		LIMIT $4 OFFSET $5`,
+		queueName, filter.ErrorClass, pq.Array(jobIDs), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	dls := []*DeadLetter{}
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("failed to list dead letters: %w", err)
+		}
+		dl, err := decodeStoredDeadLetter(raw)
+		if err != nil {
+			continue
+		}
+		dls = append(dls, dl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	return dls, nil
+}
+
+// GetDeadLetter returns the dead letter of jobID in queueName.
+func (q *PostgresQueue) GetDeadLetter(ctx context.Context, queueName, jobID string) (*DeadLetter, error) {
+	var raw []byte
+	err := q.db.QueryRowContext(ctx, `
+		SELECT dead_letter FROM queue_jobs
+		WHERE id = $1 AND type = $2 AND status = 'failed' AND dead_letter IS NOT NULL`,
+		jobID, queueName).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead letter: %w", err)
+	}
+	return decodeStoredDeadLetter(raw)
+}
+
+// UpdateDeadLetterPayload replaces the payload of the dead letter of jobID, which must be valid
+// JSON. The job is replayed with the new payload.
+func (q *PostgresQueue) UpdateDeadLetterPayload(ctx context.Context, queueName, jobID string, payload json.RawMessage) (*DeadLetter, error) {
+	if !json.Valid(payload) {
+		return nil, ErrInvalidPayload
+	}
+
+	var dl *DeadLetter
+	err := q.inTx(ctx, func(tx *sql.Tx) error {
+		var raw []byte
+		err := tx.QueryRowContext(ctx, `
+			SELECT dead_letter FROM queue_jobs
+			WHERE id = $1 AND type = $2 AND status = 'failed' AND dead_letter IS NOT NULL
+			FOR UPDATE`,
+			jobID, queueName).Scan(&raw)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrDeadLetterNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read dead letter: %w", err)
+		}
+		if dl, err = decodeStoredDeadLetter(raw); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		dl.Job.Payload = append(json.RawMessage(nil), payload...)
+		dl.PayloadEditedAt = &now
+		return q.writeDeadLetter(ctx, tx, dl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dl, nil
+}
+
+// ReplayDeadLetters re-enqueues up to limit dead letters of queueName matching filter. The jobs
+// start over with no attempts but keep their failure timeline.
+func (q *PostgresQueue) ReplayDeadLetters(ctx context.Context, queueName string, filter DeadLetterFilter, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	replayed := []string{}
+	for len(replayed) < limit {
+		// Replayed jobs are no dead letters anymore, so every pass starts over from the top.
+		batch := limit - len(replayed)
+		if batch > 500 {
+			batch = 500
+		}
+		dls, err := q.ListDeadLetters(ctx, queueName, filter, batch, 0)
+		if err != nil {
+			return replayed, err
+		}
+		n := len(replayed)
+		for _, dl := range dls {
+			ok, err := q.requeueFailed(ctx, queueName, dl.Job.ID)
+			if err != nil {
+				return replayed, err
+			}
+			if ok {
+				replayed = append(replayed, dl.Job.ID)
+			}
+		}
+		if len(replayed) == n || len(filter.JobIDs) > 0 {
+			break
+		}
+	}
+	return replayed, nil
+}
+
+// writeDeadLetter stores dl with the failed job it belongs to, in the row locked by tx.
+func (q *PostgresQueue) writeDeadLetter(ctx context.Context, tx *sql.Tx, dl *DeadLetter) error {
+	dlBytes, err := json.Marshal(dl)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE queue_jobs SET dead_letter = $2, dead_letter_at = $3 WHERE id = $1`,
+		dl.Job.ID, string(dlBytes), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	return nil
+}
+
+// requeueFailed enqueues a failed job again with fresh attempts and the payload of its dead
+// letter, which may have been edited. It reports false if the job is not failed (anymore).
+func (q *PostgresQueue) requeueFailed(ctx context.Context, queueName, jobID string) (bool, error) {
+	requeued := false
+	err := q.inTx(ctx, func(tx *sql.Tx) error {
+		var rawJob, rawDeadLetter []byte
+		err := tx.QueryRowContext(ctx, `
+			SELECT job, dead_letter FROM queue_jobs
+			WHERE id = $1 AND type = $2 AND status = 'failed'
+			FOR UPDATE`,
+			jobID, queueName).Scan(&rawJob, &rawDeadLetter)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get failed job: %w", err)
+		}
+
+		var job *Job
+		if rawDeadLetter != nil {
+			dl, err := decodeStoredDeadLetter(rawDeadLetter)
+			if err != nil {
+				return err
+			}
+			job = dl.Job
+		} else if job, err = decodeStoredJob(rawJob); err != nil {
+			return err
+		}
+
+		job.Attempts = 0
+		job.Status = "pending"
+		job.RunAt = nil
+		jobBytes, err := json.Marshal(job)
+		if err != nil {
+			return fmt.Errorf("failed to marshal job for retry: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE queue_jobs
+			SET status = 'pending', run_at = $2, locked_until = NULL, expires_at = NULL,
+				dead_letter = NULL, dead_letter_at = NULL, job = $3
+			WHERE id = $1`,
+			jobID, time.Now().UTC(), string(jobBytes))
+		if err != nil {
+			return fmt.Errorf("failed to requeue failed job: %w", err)
+		}
+		requeued = true
+		return nil
+	})
+	return requeued, err
+}
+
+// TryLock takes the lock key for owner for ttl, unless another owner holds it.
+func (q *PostgresQueue) TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	res, err := q.db.ExecContext(ctx, `
+		INSERT INTO queue_locks (key, owner, expires_at) VALUES ($1, $2, now() + make_interval(secs => $3))
+		ON CONFLICT (key) DO UPDATE SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at
+		WHERE queue_locks.expires_at <= now()`,
+		key, owner, ttl.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to take lock: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// RenewLock extends the lock key by ttl if owner still holds it.
+func (q *PostgresQueue) RenewLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	res, err := q.db.ExecContext(ctx, `
+		UPDATE queue_locks SET expires_at = now() + make_interval(secs => $3)
+		WHERE key = $1 AND owner = $2 AND expires_at > now()`,
+		key, owner, ttl.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to renew lock: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// Unlock releases the lock key if owner holds it.
+func (q *PostgresQueue) Unlock(ctx context.Context, key, owner string) (bool, error) {
+	res, err := q.db.ExecContext(ctx, `DELETE FROM queue_locks WHERE key = $1 AND owner = $2`, key, owner)
+	if err != nil {
+		return false, fmt.Errorf("failed to release lock: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// inTx runs fn in a transaction that is committed if fn succeeds and rolled back otherwise.
+func (q *PostgresQueue) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin queue transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit queue transaction: %w", err)
+	}
+	return nil
+}
+
+// lockJob loads jobID and locks its row until tx ends.
+func (q *PostgresQueue) lockJob(ctx context.Context, tx *sql.Tx, jobID string) (*Job, error) {
+	var raw []byte
+	err := tx.QueryRowContext(ctx, `SELECT job FROM queue_jobs WHERE id = $1 FOR UPDATE`, jobID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get job details: %w", ErrJobNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job details: %w", err)
+	}
+	return decodeStoredJob(raw)
+}
+
+// saveJob stores job and its status in the row locked by tx and ends its visibility timeout.
+// A non-zero runAt moves the job to that place in its lane; finished jobs expire after jobTTL.
+func (q *PostgresQueue) saveJob(ctx context.Context, tx *sql.Tx, job *Job, runAt time.Time, finished bool) error {
+	jobBytes, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job for storage: %w", err)
+	}
+
+	var runAtArg, expiresAt sql.NullTime
+	if !runAt.IsZero() {
+		runAtArg = sql.NullTime{Time: runAt, Valid: true}
+	}
+	if finished {
+		expiresAt = sql.NullTime{Time: time.Now().Add(jobTTL), Valid: true}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE queue_jobs
+		SET status = $2, run_at = COALESCE($3, run_at), locked_until = NULL, expires_at = $4, job = $5
+		WHERE id = $1`,
+		job.ID, job.Status, runAtArg, expiresAt, string(jobBytes))
+	if err != nil {
+		return fmt.Errorf("failed to store job: %w", err)
+	}
+	return nil
+}
+
+func (q *PostgresQueue) queryJobs(ctx context.Context, query string, args ...interface{}) ([]*Job, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []*Job{}
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("failed to list jobs: %w", err)
+		}
+		job, err := decodeStoredJob(raw)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func decodeStoredJob(raw []byte) (*Job, error) {
+	var job Job
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+	return &job, nil
+}
+
+func decodeStoredDeadLetter(raw []byte) (*DeadLetter, error) {
+	var dl DeadLetter
+	if err := json.Unmarshal(raw, &dl); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dead letter: %w", err)
+	}
+	if dl.Job == nil {
+		return nil, fmt.Errorf("dead letter has no job")
+	}
+	return &dl, nil
+}
@@ -0,0 +1,212 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Priority is the lane a job waits in. Workers serve the lanes in proportion to their
+// weights, so high priority jobs go first without starving the lower lanes.
+type Priority string
+
+const (
+	PriorityHigh   Priority = "high"
+	PriorityNormal Priority = "normal"
+	PriorityLow    Priority = "low"
+)
+
+// Priorities lists the lanes from highest to lowest.
+var Priorities = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// DefaultPriorityWeights is the share of dequeues each lane gets while every lane has work:
+// out of 13 jobs, 8 come from high, 4 from normal and 1 from low.
+var DefaultPriorityWeights = map[Priority]int{
+	PriorityHigh:   8,
+	PriorityNormal: 4,
+	PriorityLow:    1,
+}
+
+const maxTenantLength = 64
+
+// ParsePriority parses a priority name; the empty string is PriorityNormal.
+func ParsePriority(s string) (Priority, error) {
+	switch p := Priority(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return PriorityNormal, nil
+	case PriorityHigh, PriorityNormal, PriorityLow:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid priority %q (want high, normal or low)", s)
+	}
+}
+
+// DefaultPriority returns the lane for jobType when the enqueuing context does not set one:
+// interactive searches are high, background sweeps are low and everything else is normal.
+func DefaultPriority(jobType string) Priority {
+	switch jobType {
+	case "flight_search", "pos_comparison":
+		return PriorityHigh
+	case "price_graph_sweep", "continuous_price_graph":
+		return PriorityLow
+	default:
+		return PriorityNormal
+	}
+}
+
+type priorityKey struct{}
+
+type tenantKey struct{}
+
+// WithPriority makes jobs enqueued with the returned context use priority p.
+// An empty or unknown priority leaves the context unchanged.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if p == "" {
+		return ctx
+	}
+	if _, err := ParsePriority(string(p)); err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority stored on the context, or "" if none is set.
+func PriorityFromContext(ctx context.Context) Priority {
+	if ctx == nil {
+		return ""
+	}
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return ""
+}
+
+// WithTenant makes jobs enqueued with the returned context belong to tenant. Tenants get
+// their own sub-lanes and take turns within a lane, so one tenant's backlog cannot hold up
+// another's. Tenants are limited to 64 letters, digits, '.', '_' and '-'; anything else
+// leaves the context unchanged.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tenant = strings.TrimSpace(tenant)
+	if !validTenant(tenant) {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant stored on the context, or "" if none is set.
+func TenantFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+		return tenant
+	}
+	return ""
+}
+
+func validTenant(tenant string) bool {
+	if tenant == "" || len(tenant) > maxTenantLength {
+		return false
+	}
+	for _, r := range tenant {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// fairScheduler decides which lane a worker reads next. Priorities are picked by smooth
+// weighted round robin over the priorities that still have work; job types within a
+// priority and tenants within a lane take turns.
+type fairScheduler struct {
+	mu      sync.Mutex
+	weights map[Priority]int
+	current map[Priority]int
+	cursors map[string]int
+}
+
+func newFairScheduler(weights map[string]int) *fairScheduler {
+	s := &fairScheduler{
+		weights: make(map[Priority]int, len(Priorities)),
+		current: make(map[Priority]int, len(Priorities)),
+		cursors: make(map[string]int),
+	}
+	for _, p := range Priorities {
+		s.weights[p] = DefaultPriorityWeights[p]
+		if w, ok := weights[string(p)]; ok && w > 0 {
+			s.weights[p] = w
+		}
+	}
+	return s
+}
+
+// peek returns the priority whose turn it is, ignoring the priorities in skip, without
+// taking the turn. It returns "" when every priority is skipped.
+func (s *fairScheduler) peek(skip map[Priority]bool) Priority {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var best Priority
+	for _, p := range Priorities {
+		if skip[p] {
+			continue
+		}
+		if best == "" || s.current[p]+s.weights[p] > s.current[best]+s.weights[best] {
+			best = p
+		}
+	}
+	return best
+}
+
+// take records that served got a turn while the priorities in skip had no work.
+func (s *fairScheduler) take(served Priority, skip map[Priority]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := 0
+	for _, p := range Priorities {
+		if skip[p] {
+			continue
+		}
+		s.current[p] += s.weights[p]
+		total += s.weights[p]
+	}
+	s.current[served] -= total
+}
+
+// idle drops the credit p built up while it had no work, so that it cannot take a run of
+// turns once work arrives.
+func (s *fairScheduler) idle(p Priority) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current[p] > 0 {
+		s.current[p] = 0
+	}
+}
+
+// start returns the index that the turn for key starts at among n items.
+func (s *fairScheduler) start(key string, n int) int {
+	if n == 0 {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursors[key] % n
+}
+
+// advance moves the turn for key past the item at index served.
+func (s *fairScheduler) advance(key string, served int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursors[key] = served + 1
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -69,12 +70,24 @@ type Job struct {
 	MaxAttempts int             `json:"max_attempts"`
 	Status      string          `json:"status"`
 	StreamID    string          `json:"stream_id,omitempty"`
-	EnqueueMeta *EnqueueMeta    `json:"enqueue_meta,omitempty"`
+	Priority    Priority        `json:"priority,omitempty"`
+	Tenant      string          `json:"tenant,omitempty"`
+	RunAt       *time.Time      `json:"run_at,omitempty"`
+	// Failures is the timeline of the failed attempts, oldest first.
+	Failures    []Failure    `json:"failures,omitempty"`
+	EnqueueMeta *EnqueueMeta `json:"enqueue_meta,omitempty"`
+
+	// IdempotencyKey collapses the jobs enqueued with the same key within the dedup window.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 // Queue defines the interface for a job queue
 type Queue interface {
 	Enqueue(ctx context.Context, jobType string, payload interface{}) (string, error)
+	// EnqueueAt adds a job that is not delivered to workers before runAt.
+	EnqueueAt(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (string, error)
+	// EnqueueAfter adds a job that is not delivered to workers before delay has passed.
+	EnqueueAfter(ctx context.Context, jobType string, payload interface{}, delay time.Duration) (string, error)
 	Dequeue(ctx context.Context, queueName string) (*Job, error)
 	Ack(ctx context.Context, queueName, jobID string) error
 	Nack(ctx context.Context, queueName, jobID string) error
@@ -88,23 +101,32 @@ type Queue interface {
 	IsJobCanceled(ctx context.Context, jobID string) (bool, error)
 	// GetJob fetches persisted job details by job ID.
 	GetJob(ctx context.Context, jobID string) (*Job, error)
-	// ListJobs lists jobs from the status set (pending/processing/completed/failed).
+	// ListJobs lists jobs from the status set (pending/scheduled/processing/completed/failed).
 	ListJobs(ctx context.Context, queueName, state string, limit, offset int) ([]*Job, error)
 	// GetBacklog returns the most recent unacked stream entries for the queue.
 	GetBacklog(ctx context.Context, queueName string, limit int) ([]*Job, error)
 	// GetEnqueueMetrics aggregates enqueue sources over the last N minutes.
 	GetEnqueueMetrics(ctx context.Context, queueName string, minutes int) (map[string]int64, error)
-	// ClearFailed removes all failed jobs for the named queue.
+	// ClearFailed removes all failed jobs and dead letters for the named queue.
 	ClearFailed(ctx context.Context, queueName string) (cleared int64, err error)
 	// ClearProcessing removes all "processing" jobs for the named queue and acks/dels their stream entries when possible.
 	ClearProcessing(ctx context.Context, queueName string) (cleared int64, err error)
-	// RetryFailed moves up to limit failed jobs back into pending by re-enqueueing them.
+	// RetryFailed moves up to limit failed jobs back into pending by re-enqueueing them with the
+	// payload of their dead letter when there is one.
 	RetryFailed(ctx context.Context, queueName string, limit int) (retried int64, err error)
-	// ClearQueue removes all pending jobs from the named queue without touching in-flight processing jobs.
+	// ClearQueue removes all pending and scheduled jobs from the named queue without touching in-flight processing jobs.
 	// It is intended for admin/debug use when a backlog needs to be drained safely.
 	ClearQueue(ctx context.Context, queueName string) (cleared int64, err error)
 }
 
+// FairQueue is a Queue that can pick the next job across several job types, weighing
+// their priority lanes and tenants instead of draining one job type at a time.
+type FairQueue interface {
+	Queue
+	// DequeueFair returns the next job of any of queueNames, or nil if there is none.
+	DequeueFair(ctx context.Context, queueNames []string) (*Job, error)
+}
+
 // RedisQueue implements the Queue interface using Redis Streams
 type RedisQueue struct {
 	client          *redis.Client
@@ -113,6 +135,8 @@ type RedisQueue struct {
 	mu              sync.Mutex
 	ensuredStreams  map[string]struct{}
 	lastAutoClaimID map[string]string
+	lastClaimAt     map[string]time.Time
+	scheduler       *fairScheduler
 }
 
 type ContinuousSweepControl struct {
@@ -149,12 +173,27 @@ func NewRedisQueue(cfg config.RedisConfig) (*RedisQueue, error) {
 		consumerName:    consumerName,
 		ensuredStreams:  make(map[string]struct{}),
 		lastAutoClaimID: make(map[string]string),
+		lastClaimAt:     make(map[string]time.Time),
+		scheduler:       newFairScheduler(cfg.QueuePriorityWeights),
 	}, nil
 }
 
-// Enqueue adds a job to the queue
+// Enqueue adds a job to the queue. The job goes to the lane of the priority and tenant
+// stored on ctx, falling back to DefaultPriority(jobType) and no tenant.
 func (q *RedisQueue) Enqueue(ctx context.Context, jobType string, payload interface{}) (string, error) {
-	if err := q.ensureStream(ctx, jobType); err != nil {
+	return q.enqueue(ctx, jobType, payload, time.Time{})
+}
+
+// enqueue adds a job that is delivered at runAt, or right away if runAt is not in the future.
+// A job with the idempotency key of a job enqueued within the dedup window is not added; the
+// ID of the earlier job is returned instead.
+func (q *RedisQueue) enqueue(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (_ string, err error) {
+	priority := PriorityFromContext(ctx)
+	if priority == "" {
+		priority = DefaultPriority(jobType)
+	}
+	l := q.newLane(jobType, priority, TenantFromContext(ctx))
+	if err := q.ensureGroup(ctx, l.stream); err != nil {
 		return "", err
 	}
 
@@ -173,19 +212,46 @@ func (q *RedisQueue) Enqueue(ctx context.Context, jobType string, payload interf
 		Attempts:    0,
 		MaxAttempts: 3,
 		Status:      "pending",
+		Priority:    l.priority,
+		Tenant:      l.tenant,
 	}
 	if meta := EnqueueMetaFromContext(ctx); !meta.isEmpty() {
 		job.EnqueueMeta = &meta
 	}
 
+	if q.cfg.QueueDedupWindow > 0 {
+		if key := jobIdempotencyKey(ctx, payload); key != "" {
+			existingID, err := q.reserveIdempotencyKey(ctx, jobType, l.tenant, key, jobID)
+			if err != nil {
+				return "", err
+			}
+			if existingID != "" {
+				return existingID, nil
+			}
+			job.IdempotencyKey = key
+			defer func() {
+				if err != nil {
+					q.releaseIdempotencyKey(context.WithoutCancel(ctx), jobType, l.tenant, key, jobID)
+				}
+			}()
+		}
+	}
+
+	if runAt.After(job.CreatedAt) {
+		if err := q.schedule(ctx, jobType, job, runAt); err != nil {
+			return "", err
+		}
+		_ = q.recordEnqueueMetric(ctx, jobType, job.EnqueueMeta)
+		return jobID, nil
+	}
+
 	enqueueBytes, err := json.Marshal(job)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal job: %w", err)
 	}
 
-	stream := q.streamName(jobType)
 	msgID, err := q.client.XAdd(ctx, &redis.XAddArgs{
-		Stream: stream,
+		Stream: l.stream,
 		Values: map[string]interface{}{
 			"job": enqueueBytes,
 		},
@@ -193,6 +259,9 @@ func (q *RedisQueue) Enqueue(ctx context.Context, jobType string, payload interf
 	if err != nil {
 		return "", fmt.Errorf("failed to add job to stream: %w", err)
 	}
+	if err := q.registerLane(ctx, l); err != nil {
+		return "", err
+	}
 
 	job.StreamID = msgID
 	if err := q.persistJob(ctx, job); err != nil {
@@ -209,45 +278,48 @@ func (q *RedisQueue) Enqueue(ctx context.Context, jobType string, payload interf
 	return jobID, nil
 }
 
-// Dequeue retrieves a job from the queue
+// Dequeue retrieves the next job of one job type, serving its priority lanes by weight.
 func (q *RedisQueue) Dequeue(ctx context.Context, queueName string) (*Job, error) {
-	if err := q.ensureStream(ctx, queueName); err != nil {
-		return nil, err
-	}
+	return q.DequeueFair(ctx, []string{queueName})
+}
 
-	// First attempt to reclaim stale messages
-	if job, err := q.claimStale(ctx, queueName); err != nil {
+// DequeueFair retrieves the next job of any of queueNames. Stale jobs of crashed consumers
+// are reclaimed first; otherwise the lanes with undelivered entries are read in the order
+// picked by the fair scheduler, and when there are none it blocks for up to QueueBlockTimeout.
+func (q *RedisQueue) DequeueFair(ctx context.Context, queueNames []string) (*Job, error) {
+	lanes, err := q.lanes(ctx, queueNames)
+	if err != nil {
 		return nil, err
-	} else if job != nil {
-		return job, nil
-	}
-
-	stream := q.streamName(queueName)
-	cmd := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
-		Group:    q.cfg.QueueGroup,
-		Consumer: q.consumerName,
-		Streams:  []string{stream, ">"},
-		Count:    1,
-		Block:    q.cfg.QueueBlockTimeout,
-	})
-	res, err := cmd.Result()
-	if errors.Is(err, redis.Nil) || len(res) == 0 {
-		return nil, nil
 	}
+	states, err := q.laneStates(ctx, lanes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read from stream: %w", err)
+		return nil, err
 	}
 
-	if len(res[0].Messages) == 0 {
-		return nil, nil
+	for _, l := range lanes {
+		if job, err := q.claimStale(ctx, l); err != nil {
+			return nil, err
+		} else if job != nil {
+			return job, nil
+		}
 	}
 
-	job, err := q.prepareMessage(ctx, queueName, res[0].Messages[0])
-	if err != nil {
-		return nil, err
+	ready := make(map[string]bool, len(lanes))
+	var idle []lane
+	for i, l := range lanes {
+		switch {
+		case states[i].ready():
+			ready[l.stream] = true
+		case states[i].lastID == "":
+			idle = append(idle, l)
+		}
 	}
+	q.dropIdleLanes(ctx, idle)
 
-	return job, nil
+	if job, err := q.readFair(ctx, lanes, ready); err != nil || job != nil {
+		return job, err
+	}
+	return q.readBlocking(ctx, lanes, states)
 }
 
 // Ack acknowledges a job as completed
@@ -257,7 +329,7 @@ func (q *RedisQueue) Ack(ctx context.Context, queueName, jobID string) error {
 		return err
 	}
 
-	stream := q.streamName(queueName)
+	stream := q.jobLane(queueName, job).stream
 
 	job.Status = "completed"
 	if err := q.persistJob(ctx, job); err != nil {
@@ -284,14 +356,23 @@ func (q *RedisQueue) Ack(ctx context.Context, queueName, jobID string) error {
 	return nil
 }
 
-// Nack marks a job as failed or requeues it
+// Nack marks a job as failed or retries it: after the retry backoff when one is configured,
+// right away otherwise.
 func (q *RedisQueue) Nack(ctx context.Context, queueName, jobID string) error {
+	return q.NackWithFailure(ctx, queueName, jobID, Failure{})
+}
+
+// NackWithFailure is Nack that adds failure to the job's attempt timeline. A job out of
+// attempts moves to the dead-letter stream.
+func (q *RedisQueue) NackWithFailure(ctx context.Context, queueName, jobID string, failure Failure) error {
 	job, jobKey, err := q.getStoredJob(ctx, jobID)
 	if err != nil {
 		return err
 	}
+	job.recordFailure(failure)
 
-	stream := q.streamName(queueName)
+	l := q.jobLane(queueName, job)
+	stream := l.stream
 
 	if job.StreamID != "" {
 		if err := q.client.XAck(ctx, stream, q.cfg.QueueGroup, job.StreamID).Err(); err != nil {
@@ -300,6 +381,17 @@ func (q *RedisQueue) Nack(ctx context.Context, queueName, jobID string) error {
 		_ = q.client.XDel(ctx, stream, job.StreamID).Err()
 	}
 
+	if delay := retryDelay(q.cfg, job.Attempts); delay > 0 && job.Attempts < job.MaxAttempts {
+		job.StreamID = ""
+		if err := q.schedule(ctx, queueName, job, time.Now().Add(delay)); err != nil {
+			return err
+		}
+		if err := q.client.SRem(ctx, q.processingKey(queueName), jobID).Err(); err != nil {
+			return fmt.Errorf("failed to clear processing flag: %w", err)
+		}
+		return nil
+	}
+
 	if job.Attempts < job.MaxAttempts {
 		job.Status = "pending"
 		job.StreamID = ""
@@ -321,6 +413,9 @@ func (q *RedisQueue) Nack(ctx context.Context, queueName, jobID string) error {
 		if err != nil {
 			return fmt.Errorf("failed to requeue job: %w", err)
 		}
+		if err := q.registerLane(ctx, l); err != nil {
+			return err
+		}
 
 		job.StreamID = msgID
 		if err := q.persistJob(ctx, job); err != nil {
@@ -345,6 +440,9 @@ func (q *RedisQueue) Nack(ctx context.Context, queueName, jobID string) error {
 		if err := q.client.SAdd(ctx, q.failedKey(queueName), jobID).Err(); err != nil {
 			return fmt.Errorf("failed to add job to failed set: %w", err)
 		}
+		if err := q.deadLetter(ctx, queueName, job); err != nil {
+			return err
+		}
 	}
 
 	_ = q.client.Expire(ctx, jobKey, jobTTL).Err()
@@ -389,7 +487,13 @@ func (q *RedisQueue) GetQueueStats(ctx context.Context, queueName string) (map[s
 		return nil, fmt.Errorf("failed to get failed count: %w", err)
 	}
 
+	scheduledCount, err := q.client.ZCard(ctx, q.scheduledKey(queueName)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled count: %w", err)
+	}
+
 	stats["pending"] = pendingCount
+	stats["scheduled"] = scheduledCount
 	stats["processing"] = processingCount
 	stats["completed"] = completedCount
 	stats["failed"] = failedCount
@@ -418,10 +522,11 @@ func (q *RedisQueue) CancelJob(ctx context.Context, queueName, jobID string) err
 
 	// Best-effort: remove from pending set so it doesn't start later.
 	_ = q.client.SRem(ctx, q.pendingKey(queueName), jobID).Err()
+	_ = q.client.ZRem(ctx, q.scheduledKey(queueName), jobID).Err()
 
 	// Best-effort: if we still have stream bookkeeping, ack/del it so it won't get re-delivered.
-	stream := q.streamName(queueName)
 	if job, _, err := q.getStoredJob(ctx, jobID); err == nil && job != nil && job.StreamID != "" {
+		stream := q.jobLane(queueName, job).stream
 		_ = q.client.XAck(ctx, stream, q.cfg.QueueGroup, job.StreamID).Err()
 		_ = q.client.XDel(ctx, stream, job.StreamID).Err()
 	}
@@ -489,6 +594,8 @@ func (q *RedisQueue) ListJobs(ctx context.Context, queueName, state string, limi
 
 	var setKey string
 	switch strings.ToLower(strings.TrimSpace(state)) {
+	case "scheduled":
+		return q.listScheduled(ctx, queueName, limit, offset)
 	case "pending":
 		setKey = q.pendingKey(queueName)
 	case "processing":
@@ -543,13 +650,24 @@ func (q *RedisQueue) GetBacklog(ctx context.Context, queueName string, limit int
 		limit = 500
 	}
 
-	stream := q.streamName(queueName)
-	msgs, err := q.client.XRevRangeN(ctx, stream, "+", "-", int64(limit)).Result()
+	lanes, err := q.lanes(ctx, []string{queueName})
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return []*Job{}, nil
+		return nil, err
+	}
+	var msgs []redis.XMessage
+	for _, l := range lanes {
+		laneMsgs, err := q.client.XRevRangeN(ctx, l.stream, "+", "-", int64(limit)).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("failed to read queue backlog: %w", err)
 		}
-		return nil, fmt.Errorf("failed to read queue backlog: %w", err)
+		msgs = append(msgs, laneMsgs...)
+	}
+	// Stream IDs start with the enqueue time, so this orders the lanes' entries newest first.
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return compareStreamIDs(msgs[i].ID, msgs[j].ID) > 0
+	})
+	if len(msgs) > limit {
+		msgs = msgs[:limit]
 	}
 
 	out := make([]*Job, 0, len(msgs))
@@ -652,7 +770,6 @@ func (q *RedisQueue) ClearQueue(ctx context.Context, queueName string) (int64, e
 		return 0, err
 	}
 
-	stream := q.streamName(queueName)
 	jobIDs, err := q.client.SMembers(ctx, q.pendingKey(queueName)).Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to list pending jobs: %w", err)
@@ -673,7 +790,7 @@ func (q *RedisQueue) ClearQueue(ctx context.Context, queueName string) (int64, e
 			}
 
 			if job.StreamID != "" {
-				_ = q.client.XDel(ctx, stream, job.StreamID).Err()
+				_ = q.client.XDel(ctx, q.jobLane(queueName, &job).stream, job.StreamID).Err()
 			}
 			_ = q.client.Del(ctx, jobKey).Err()
 		}
@@ -682,6 +799,18 @@ func (q *RedisQueue) ClearQueue(ctx context.Context, queueName string) (int64, e
 		cleared++
 	}
 
+	scheduledIDs, err := q.client.ZRange(ctx, q.scheduledKey(queueName), 0, -1).Result()
+	if err != nil {
+		return cleared, fmt.Errorf("failed to list scheduled jobs: %w", err)
+	}
+	for _, jobID := range scheduledIDs {
+		// Only jobs still scheduled are cleared; the promoter may have delivered the others.
+		if n, err := q.client.ZRem(ctx, q.scheduledKey(queueName), jobID).Result(); err == nil && n > 0 {
+			_ = q.client.Del(ctx, q.jobKey(jobID)).Err()
+			cleared++
+		}
+	}
+
 	return cleared, nil
 }
 
@@ -701,6 +830,9 @@ func (q *RedisQueue) ClearFailed(ctx context.Context, queueName string) (int64,
 		_ = q.client.SRem(ctx, q.failedKey(queueName), jobID).Err()
 		cleared++
 	}
+	if err := q.client.Del(ctx, q.deadLetterKey(queueName), q.deadLetterIndexKey(queueName)).Err(); err != nil {
+		return cleared, fmt.Errorf("failed to clear dead letters: %w", err)
+	}
 	return cleared, nil
 }
 
@@ -709,7 +841,6 @@ func (q *RedisQueue) ClearProcessing(ctx context.Context, queueName string) (int
 		return 0, err
 	}
 
-	stream := q.streamName(queueName)
 	jobIDs, err := q.client.SMembers(ctx, q.processingKey(queueName)).Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to list processing jobs: %w", err)
@@ -719,6 +850,7 @@ func (q *RedisQueue) ClearProcessing(ctx context.Context, queueName string) (int
 	for _, jobID := range jobIDs {
 		job, _, getErr := q.getStoredJob(ctx, jobID)
 		if getErr == nil && job != nil && job.StreamID != "" {
+			stream := q.jobLane(queueName, job).stream
 			_ = q.client.XAck(ctx, stream, q.cfg.QueueGroup, job.StreamID).Err()
 			_ = q.client.XDel(ctx, stream, job.StreamID).Err()
 		}
@@ -741,7 +873,6 @@ func (q *RedisQueue) RetryFailed(ctx context.Context, queueName string, limit in
 		limit = 1000
 	}
 
-	stream := q.streamName(queueName)
 	jobIDs, err := q.client.SMembers(ctx, q.failedKey(queueName)).Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to list failed jobs: %w", err)
@@ -753,7 +884,7 @@ func (q *RedisQueue) RetryFailed(ctx context.Context, queueName string, limit in
 			break
 		}
 
-		job, _, err := q.getStoredJob(ctx, jobID)
+		job, entryID, err := q.failedJob(ctx, queueName, jobID)
 		if err != nil {
 			if errors.Is(err, redis.Nil) {
 				_ = q.client.SRem(ctx, q.failedKey(queueName), jobID).Err()
@@ -762,30 +893,9 @@ func (q *RedisQueue) RetryFailed(ctx context.Context, queueName string, limit in
 			return retried, err
 		}
 
-		job.Attempts = 0
-		job.Status = "pending"
-		job.StreamID = ""
-
-		requeuePayload, err := json.Marshal(job)
-		if err != nil {
-			return retried, fmt.Errorf("failed to marshal job for retry: %w", err)
-		}
-
-		msgID, err := q.client.XAdd(ctx, &redis.XAddArgs{
-			Stream: stream,
-			Values: map[string]interface{}{"job": requeuePayload},
-		}).Result()
-		if err != nil {
-			return retried, fmt.Errorf("failed to requeue failed job: %w", err)
-		}
-
-		job.StreamID = msgID
-		if err := q.persistJob(ctx, job); err != nil {
+		if err := q.requeueFailed(ctx, queueName, job, entryID); err != nil {
 			return retried, err
 		}
-
-		_ = q.client.SRem(ctx, q.failedKey(queueName), jobID).Err()
-		_ = q.client.SAdd(ctx, q.pendingKey(queueName), jobID).Err()
 		retried++
 	}
 
@@ -793,8 +903,10 @@ func (q *RedisQueue) RetryFailed(ctx context.Context, queueName string, limit in
 }
 
 func (q *RedisQueue) ensureStream(ctx context.Context, queueName string) error {
-	stream := q.streamName(queueName)
+	return q.ensureGroup(ctx, q.streamName(queueName))
+}
 
+func (q *RedisQueue) ensureGroup(ctx context.Context, stream string) error {
 	q.mu.Lock()
 	if _, ok := q.ensuredStreams[stream]; ok {
 		q.mu.Unlock()
@@ -802,7 +914,9 @@ func (q *RedisQueue) ensureStream(ctx context.Context, queueName string) error {
 	}
 	q.mu.Unlock()
 
-	err := q.client.XGroupCreateMkStream(ctx, stream, q.cfg.QueueGroup, "$").Err()
+	// The group starts at the beginning of the stream: an idle tenant lane may have been deleted
+	// and recreated by an enqueue before its group is created again.
+	err := q.client.XGroupCreateMkStream(ctx, stream, q.cfg.QueueGroup, "0").Err()
 	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
 		return fmt.Errorf("failed to create consumer group: %w", err)
 	}
@@ -813,14 +927,26 @@ func (q *RedisQueue) ensureStream(ctx context.Context, queueName string) error {
 	return nil
 }
 
-func (q *RedisQueue) claimStale(ctx context.Context, queueName string) (*Job, error) {
-	stream := q.streamName(queueName)
+// staleClaimInterval is how long a lane waits before scanning for stale entries again once
+// a scan of its whole pending list found none.
+const staleClaimInterval = 5 * time.Second
+
+func (q *RedisQueue) claimStale(ctx context.Context, l lane) (*Job, error) {
+	stream := l.stream
+	interval := staleClaimInterval
+	if v := q.cfg.QueueVisibilityTimeout; v > 0 && v < interval {
+		interval = v
+	}
 
 	q.mu.Lock()
 	startID := q.lastAutoClaimID[stream]
 	if startID == "" {
 		startID = "0-0"
 	}
+	if startID == "0-0" && time.Since(q.lastClaimAt[stream]) < interval {
+		q.mu.Unlock()
+		return nil, nil
+	}
 	q.mu.Unlock()
 
 	messages, nextID, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
@@ -831,19 +957,26 @@ func (q *RedisQueue) claimStale(ctx context.Context, queueName string) (*Job, er
 		Start:    startID,
 		Count:    1,
 	}).Result()
+	if isNoGroup(err) {
+		q.forgetGroup(stream)
+		return nil, nil
+	}
 	if err != nil && !errors.Is(err, redis.Nil) {
 		return nil, fmt.Errorf("failed to auto-claim messages: %w", err)
 	}
 
 	q.mu.Lock()
 	q.lastAutoClaimID[stream] = nextID
+	if len(messages) == 0 && (nextID == "" || nextID == "0-0") {
+		q.lastClaimAt[stream] = time.Now()
+	}
 	q.mu.Unlock()
 
 	if len(messages) == 0 {
 		return nil, nil
 	}
 
-	job, err := q.prepareMessage(ctx, queueName, messages[0])
+	job, err := q.prepareMessage(ctx, l, messages[0])
 	if err != nil {
 		return nil, err
 	}
@@ -851,7 +984,129 @@ func (q *RedisQueue) claimStale(ctx context.Context, queueName string) (*Job, er
 	return job, nil
 }
 
-func (q *RedisQueue) prepareMessage(ctx context.Context, queueName string, msg redis.XMessage) (*Job, error) {
+// readFair reads the next entry without blocking, trying priorities in the order of the
+// fair scheduler and, within a priority, the job types and then the tenants in turn. Only the
+// streams in ready are read; the other lanes count as empty.
+func (q *RedisQueue) readFair(ctx context.Context, lanes []lane, ready map[string]bool) (*Job, error) {
+	type typeLanes struct {
+		queueName string
+		lanes     []lane
+	}
+	byPriority := make(map[Priority][]*typeLanes, len(Priorities))
+	for _, l := range lanes {
+		groups := byPriority[l.priority]
+		if n := len(groups); n == 0 || groups[n-1].queueName != l.queueName {
+			groups = append(groups, &typeLanes{queueName: l.queueName})
+			byPriority[l.priority] = groups
+		}
+		g := groups[len(groups)-1]
+		g.lanes = append(g.lanes, l)
+	}
+
+	empty := make(map[Priority]bool, len(Priorities))
+	for {
+		p := q.scheduler.peek(empty)
+		if p == "" {
+			return nil, nil
+		}
+
+		groups := byPriority[p]
+		typeKey := string(p)
+		firstType := q.scheduler.start(typeKey, len(groups))
+		for i := range groups {
+			ti := (firstType + i) % len(groups)
+			g := groups[ti]
+			tenantKey := string(p) + ":" + g.queueName
+			firstLane := q.scheduler.start(tenantKey, len(g.lanes))
+			for j := range g.lanes {
+				li := (firstLane + j) % len(g.lanes)
+				l := g.lanes[li]
+				if !ready[l.stream] {
+					continue
+				}
+				msg, err := q.readLane(ctx, l)
+				if err != nil {
+					return nil, err
+				}
+				if msg == nil {
+					continue
+				}
+
+				q.scheduler.take(p, empty)
+				q.scheduler.advance(typeKey, ti)
+				q.scheduler.advance(tenantKey, li)
+				return q.prepareMessage(ctx, l, *msg)
+			}
+		}
+		empty[p] = true
+		q.scheduler.idle(p)
+	}
+}
+
+func (q *RedisQueue) readLane(ctx context.Context, l lane) (*redis.XMessage, error) {
+	res, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.cfg.QueueGroup,
+		Consumer: q.consumerName,
+		Streams:  []string{l.stream, ">"},
+		Count:    1,
+		Block:    -1,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if isNoGroup(err) {
+		// The lane was deleted as idle and recreated without the consumer group, which the
+		// next dequeue creates again.
+		q.forgetGroup(l.stream)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from stream: %w", err)
+	}
+	if len(res) == 0 || len(res[0].Messages) == 0 {
+		return nil, nil
+	}
+	return &res[0].Messages[0], nil
+}
+
+// readBlocking waits for a new entry on any of the lanes and then reads one entry like
+// readFair. The wait doesn't read for the consumer group, so the entries stay in order in their
+// lanes and are delivered by priority.
+func (q *RedisQueue) readBlocking(ctx context.Context, lanes []lane, states []laneState) (*Job, error) {
+	if len(lanes) == 0 {
+		return nil, nil
+	}
+
+	streams := make([]string, 0, 2*len(lanes))
+	for _, l := range lanes {
+		streams = append(streams, l.stream)
+	}
+	for _, state := range states {
+		streams = append(streams, state.seen())
+	}
+
+	res, err := q.client.XRead(ctx, &redis.XReadArgs{
+		Streams: streams,
+		Count:   1,
+		Block:   q.cfg.QueueBlockTimeout,
+	}).Result()
+	if errors.Is(err, redis.Nil) || len(res) == 0 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for stream entries: %w", err)
+	}
+
+	ready := make(map[string]bool, len(res))
+	for _, xs := range res {
+		if len(xs.Messages) > 0 {
+			ready[xs.Stream] = true
+		}
+	}
+	return q.readFair(ctx, lanes, ready)
+}
+
+func decodeMessageJob(msg redis.XMessage) (*Job, error) {
 	rawJob, ok := msg.Values["job"]
 	if !ok {
 		return nil, fmt.Errorf("stream message missing job payload")
@@ -871,15 +1126,27 @@ func (q *RedisQueue) prepareMessage(ctx context.Context, queueName string, msg r
 	if err := json.Unmarshal(jobBytes, &job); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal job payload: %w", err)
 	}
+	return &job, nil
+}
 
+func (q *RedisQueue) prepareMessage(ctx context.Context, l lane, msg redis.XMessage) (*Job, error) {
+	job, err := decodeMessageJob(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	queueName := l.queueName
 	job.StreamID = msg.ID
 	if job.Type == "" {
 		job.Type = queueName
 	}
+	if job.Priority == "" {
+		job.Priority = l.priority
+	}
 	job.Attempts++
 	job.Status = "processing"
 
-	if err := q.persistJob(ctx, &job); err != nil {
+	if err := q.persistJob(ctx, job); err != nil {
 		return nil, err
 	}
 
@@ -890,7 +1157,7 @@ func (q *RedisQueue) prepareMessage(ctx context.Context, queueName string, msg r
 		return nil, fmt.Errorf("failed to remove job from pending: %w", err)
 	}
 
-	return &job, nil
+	return job, nil
 }
 
 func (q *RedisQueue) persistJob(ctx context.Context, job *Job) error {
@@ -899,7 +1166,14 @@ func (q *RedisQueue) persistJob(ctx context.Context, job *Job) error {
 		return fmt.Errorf("failed to marshal job for storage: %w", err)
 	}
 
-	if err := q.client.Set(ctx, q.jobKey(job.ID), jobBytes, jobTTL).Err(); err != nil {
+	ttl := jobTTL
+	if job.RunAt != nil {
+		// Scheduled jobs have to outlive their wait.
+		if wait := time.Until(*job.RunAt); wait > 0 {
+			ttl += wait
+		}
+	}
+	if err := q.client.Set(ctx, q.jobKey(job.ID), jobBytes, ttl).Err(); err != nil {
 		return fmt.Errorf("failed to store job: %w", err)
 	}
 	return nil
@@ -924,6 +1198,228 @@ func (q *RedisQueue) streamName(jobType string) string {
 	return fmt.Sprintf("%s:%s", q.cfg.QueueStreamPrefix, jobType)
 }
 
+// lane is a stream that jobs of one type and priority wait in. Jobs enqueued for a tenant
+// get a lane of their own, registered in the job type's tenants set while it has entries.
+type lane struct {
+	queueName string
+	priority  Priority
+	tenant    string
+	stream    string
+}
+
+func (q *RedisQueue) newLane(queueName string, priority Priority, tenant string) lane {
+	if priority == "" {
+		priority = PriorityNormal
+	}
+	l := lane{queueName: queueName, priority: priority, tenant: tenant, stream: q.streamName(queueName)}
+	// Normal priority jobs without a tenant keep using the job type's original stream.
+	if priority != PriorityNormal || tenant != "" {
+		l.stream += ":" + string(priority)
+	}
+	if tenant != "" {
+		l.stream += ":tenant:" + tenant
+	}
+	return l
+}
+
+func (q *RedisQueue) jobLane(queueName string, job *Job) lane {
+	return q.newLane(queueName, job.Priority, job.Tenant)
+}
+
+// lanes returns every lane of queueNames, ordered by job type, then priority, with the
+// shared lane ahead of the tenant lanes.
+func (q *RedisQueue) lanes(ctx context.Context, queueNames []string) ([]lane, error) {
+	pipe := q.client.Pipeline()
+	cmds := make([]*redis.StringSliceCmd, 0, len(queueNames))
+	for _, queueName := range queueNames {
+		cmds = append(cmds, pipe.SMembers(ctx, q.tenantsKey(queueName)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to list queue tenants: %w", err)
+	}
+
+	var lanes []lane
+	for i, queueName := range queueNames {
+		members, _ := cmds[i].Result()
+		sort.Strings(members)
+		for _, p := range Priorities {
+			lanes = append(lanes, q.newLane(queueName, p, ""))
+			for _, member := range members {
+				if priority, tenant, ok := strings.Cut(member, ":"); ok && Priority(priority) == p && tenant != "" {
+					lanes = append(lanes, q.newLane(queueName, p, tenant))
+				}
+			}
+		}
+	}
+
+	for _, l := range lanes {
+		if err := q.ensureGroup(ctx, l.stream); err != nil {
+			return nil, err
+		}
+	}
+	return lanes, nil
+}
+
+func (q *RedisQueue) registerLane(ctx context.Context, l lane) error {
+	if l.tenant == "" {
+		return nil
+	}
+	if err := q.client.SAdd(ctx, q.tenantsKey(l.queueName), string(l.priority)+":"+l.tenant).Err(); err != nil {
+		return fmt.Errorf("failed to register tenant lane: %w", err)
+	}
+	return nil
+}
+
+// laneState is what a dequeue knows about a lane before reading it.
+type laneState struct {
+	lastID    string // ID of the newest entry of the stream, "" if it has none
+	delivered string // ID of the newest entry delivered to the consumer group
+}
+
+// ready reports whether the lane has entries which weren't delivered yet.
+func (s laneState) ready() bool {
+	return s.lastID != "" && compareStreamIDs(s.lastID, s.delivered) > 0
+}
+
+// seen returns the ID after which new entries of the lane are waited for.
+func (s laneState) seen() string {
+	switch {
+	case s.lastID == "" && s.delivered == "":
+		return "0-0"
+	case compareStreamIDs(s.lastID, s.delivered) > 0:
+		return s.lastID
+	default:
+		return s.delivered
+	}
+}
+
+// laneStates looks up the newest entry of every lane and how far the consumer group got in a
+// single round trip, so that a dequeue only reads the lanes which have undelivered entries.
+// Lanes whose consumer group is missing get it created again.
+func (q *RedisQueue) laneStates(ctx context.Context, lanes []lane) ([]laneState, error) {
+	pipe := q.client.Pipeline()
+	newest := make([]*redis.XMessageSliceCmd, len(lanes))
+	groups := make([]*redis.XInfoGroupsCmd, len(lanes))
+	for i, l := range lanes {
+		newest[i] = pipe.XRevRangeN(ctx, l.stream, "+", "-", 1)
+		groups[i] = pipe.XInfoGroups(ctx, l.stream)
+	}
+	// The errors are checked per command: XINFO fails for the streams deleted as idle.
+	_, _ = pipe.Exec(ctx)
+
+	states := make([]laneState, len(lanes))
+	for i, l := range lanes {
+		msgs, err := newest[i].Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("failed to read stream %s: %w", l.stream, err)
+		}
+		if len(msgs) > 0 {
+			states[i].lastID = msgs[0].ID
+		}
+
+		infos, err := groups[i].Result()
+		if err != nil {
+			continue
+		}
+		found := false
+		for _, info := range infos {
+			if info.Name == q.cfg.QueueGroup {
+				states[i].delivered = info.LastDeliveredID
+				found = true
+			}
+		}
+		if !found {
+			// An enqueue recreated the stream after it was deleted as idle.
+			q.forgetGroup(l.stream)
+			if err := q.ensureGroup(ctx, l.stream); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return states, nil
+}
+
+// dropIdleLaneScript unregisters and deletes a tenant lane only if its stream is empty, so it
+// cannot race with an enqueue, which adds the entry before registering the lane.
+var dropIdleLaneScript = redis.NewScript(`
+if redis.call("XLEN", KEYS[1]) == 0 then
+	redis.call("SREM", KEYS[2], ARGV[1])
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// dropIdleLanes deletes the tenant lanes without entries, in-flight ones included, so that
+// neither their streams nor their consumer groups pile up in Redis.
+func (q *RedisQueue) dropIdleLanes(ctx context.Context, lanes []lane) {
+	pipe := q.client.Pipeline()
+	var dropped []lane
+	var cmds []*redis.Cmd
+	for _, l := range lanes {
+		if l.tenant == "" {
+			continue
+		}
+		dropped = append(dropped, l)
+		cmds = append(cmds, dropIdleLaneScript.Eval(ctx, pipe, []string{l.stream, q.tenantsKey(l.queueName)}, string(l.priority)+":"+l.tenant))
+	}
+	if len(cmds) == 0 {
+		return
+	}
+	_, _ = pipe.Exec(ctx)
+	for i, cmd := range cmds {
+		if n, err := cmd.Int(); err == nil && n > 0 {
+			q.forgetGroup(dropped[i].stream)
+		}
+	}
+}
+
+// forgetGroup makes the next ensureGroup of stream create its consumer group again.
+func (q *RedisQueue) forgetGroup(stream string) {
+	q.mu.Lock()
+	delete(q.ensuredStreams, stream)
+	q.mu.Unlock()
+}
+
+func isNoGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOGROUP")
+}
+
+func priorityRank(p Priority) int {
+	for i, candidate := range Priorities {
+		if candidate == p {
+			return i
+		}
+	}
+	return len(Priorities)
+}
+
+// compareStreamIDs compares two stream entry IDs ("<ms>-<seq>") by time, then sequence.
+func compareStreamIDs(a, b string) int {
+	aMs, aSeq := parseStreamID(a)
+	bMs, bSeq := parseStreamID(b)
+	switch {
+	case aMs != bMs:
+		if aMs < bMs {
+			return -1
+		}
+		return 1
+	case aSeq != bSeq:
+		if aSeq < bSeq {
+			return -1
+		}
+		return 1
+	default:
+		return 0
+	}
+}
+
+func parseStreamID(id string) (ms, seq uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseUint(msPart, 10, 64)
+	seq, _ = strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
+
 func (q *RedisQueue) jobKey(jobID string) string {
 	return fmt.Sprintf("job:%s", jobID)
 }
@@ -948,12 +1444,29 @@ func (q *RedisQueue) failedKey(queueName string) string {
 	return fmt.Sprintf("queue:%s:failed", queueName)
 }
 
+func (q *RedisQueue) scheduledKey(queueName string) string {
+	return fmt.Sprintf("queue:%s:scheduled", queueName)
+}
+
+func (q *RedisQueue) deadLetterKey(queueName string) string {
+	return fmt.Sprintf("queue:%s:dead", queueName)
+}
+
+func (q *RedisQueue) deadLetterIndexKey(queueName string) string {
+	return fmt.Sprintf("queue:%s:dead:index", queueName)
+}
+
+func (q *RedisQueue) tenantsKey(queueName string) string {
+	return fmt.Sprintf("queue:%s:tenants", queueName)
+}
+
 func (q *RedisQueue) enqueueMetricKey(queueName string, ts time.Time) string {
 	// minute bucket key, e.g. queue:flight_search:enqueues:202601261505
 	return fmt.Sprintf("queue:%s:enqueues:%s", queueName, ts.Format("200601021504"))
 }
 
-func (q *RedisQueue) recordEnqueueMetric(ctx context.Context, queueName string, meta *EnqueueMeta) error {
+// enqueueSource names who enqueued a job in the enqueue metrics.
+func enqueueSource(meta *EnqueueMeta) string {
 	source := "unknown"
 	if meta != nil {
 		if meta.Actor != "" && meta.Method != "" && meta.Path != "" {
@@ -966,6 +1479,11 @@ func (q *RedisQueue) recordEnqueueMetric(ctx context.Context, queueName string,
 			source = fmt.Sprintf("request_id:%s", meta.RequestID)
 		}
 	}
+	return source
+}
+
+func (q *RedisQueue) recordEnqueueMetric(ctx context.Context, queueName string, meta *EnqueueMeta) error {
+	source := enqueueSource(meta)
 
 	key := q.enqueueMetricKey(queueName, time.Now().UTC().Truncate(time.Minute))
 	pipe := q.client.Pipeline()
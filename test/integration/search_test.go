@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gilby125/google-flights-api/api"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 	"github.com/gilby125/google-flights-api/test/mocks" // Need mocks
 
 	// Removed unused comment
@@ -17,8 +18,8 @@ import (
 	"github.com/stretchr/testify/mock" // Need mock
 )
 
-func dateOnly(t time.Time) api.DateOnly {
-	return api.DateOnly{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+func dateOnly(t time.Time) apitypes.DateOnly {
+	return apitypes.DateOnly{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
 }
 
 func TestCreateSearch(t *testing.T) {
@@ -33,7 +34,7 @@ func TestCreateSearch(t *testing.T) {
 	}{
 		{
 			name: "valid one-way search",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
@@ -50,7 +51,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "valid round-trip search",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
@@ -68,7 +69,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "valid stops two_stops_plus", // Fixed validation tag in handler
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "LHR",
 				Destination:   "SYD",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 1, 0)),
@@ -99,7 +100,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "invalid departure date (past)",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, -1)), // Past date should fail validation
@@ -114,7 +115,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "invalid return date (before departure)",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 14)),
@@ -130,7 +131,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "unsupported trip type",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
@@ -145,7 +146,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "invalid currency",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
@@ -160,7 +161,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "invalid cabin class",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
@@ -175,7 +176,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "invalid stops value",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
@@ -190,7 +191,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "invalid origin airport code format",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "123", // Invalid format should fail validation
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
@@ -205,7 +206,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "invalid destination airport code format (too long)",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "TOOLONG", // Invalid format should fail validation
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
@@ -220,7 +221,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "zero adults",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
@@ -235,7 +236,7 @@ func TestCreateSearch(t *testing.T) {
 		},
 		{
 			name: "negative adults",
-			payload: api.SearchRequest{
+			payload: apitypes.SearchRequest{
 				Origin:        "JFK",
 				Destination:   "LAX",
 				DepartureDate: dateOnly(time.Now().AddDate(0, 0, 7)),
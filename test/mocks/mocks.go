@@ -5,6 +5,7 @@ import (
 
 	"errors"  // Added import
 	"reflect" // Added for Scan simulation
+	"time"
 
 	"github.com/gilby125/google-flights-api/db" // Added db import for Neo4jResult interface
 	"github.com/gilby125/google-flights-api/queue"
@@ -273,6 +274,16 @@ func (m *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}
 	return args.String(0), args.Error(1)
 }
 
+func (m *Queue) EnqueueAt(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (string, error) {
+	args := m.Called(ctx, jobType, payload, runAt)
+	return args.String(0), args.Error(1)
+}
+
+func (m *Queue) EnqueueAfter(ctx context.Context, jobType string, payload interface{}, delay time.Duration) (string, error) {
+	args := m.Called(ctx, jobType, payload, delay)
+	return args.String(0), args.Error(1)
+}
+
 func (m *Queue) Dequeue(ctx context.Context, queueName string) (*queue.Job, error) {
 	args := m.Called(ctx, queueName)
 	jobArg := args.Get(0)
@@ -179,6 +179,15 @@ func (m *MockPostgresDB) GetFlightSegmentsByOfferID(ctx context.Context, offerID
 	return rows, args.Error(1)
 }
 
+func (m *MockPostgresDB) ListSearchQuerySegments(ctx context.Context, searchQueryID int) ([]db.SearchQuerySegment, error) {
+	args := m.Called(ctx, searchQueryID)
+	var segments []db.SearchQuerySegment
+	if s := args.Get(0); s != nil {
+		segments = s.([]db.SearchQuerySegment)
+	}
+	return segments, args.Error(1)
+}
+
 func (m *MockPostgresDB) CountSearches(ctx context.Context) (int, error) {
 	args := m.Called(ctx)
 	return args.Int(0), args.Error(1)
@@ -435,10 +444,48 @@ func (m *MockPostgresDB) ListContinuousSweepResults(ctx context.Context, filters
 	return results, args.Error(1)
 }
 
+// --- Export Methods Mock Implementations ---
+
+func (m *MockPostgresDB) ExportBulkSearchResults(ctx context.Context, searchID int, filter db.ExportFilter) (db.Rows, error) {
+	args := m.Called(ctx, searchID, filter)
+	var rows db.Rows
+	if r := args.Get(0); r != nil {
+		rows = r.(db.Rows)
+	}
+	return rows, args.Error(1)
+}
+
+func (m *MockPostgresDB) ExportBulkSearchOffers(ctx context.Context, searchID int, filter db.ExportFilter) (db.Rows, error) {
+	args := m.Called(ctx, searchID, filter)
+	var rows db.Rows
+	if r := args.Get(0); r != nil {
+		rows = r.(db.Rows)
+	}
+	return rows, args.Error(1)
+}
+
+func (m *MockPostgresDB) ExportPriceGraphResults(ctx context.Context, sweepID int, filter db.ExportFilter) (db.Rows, error) {
+	args := m.Called(ctx, sweepID, filter)
+	var rows db.Rows
+	if r := args.Get(0); r != nil {
+		rows = r.(db.Rows)
+	}
+	return rows, args.Error(1)
+}
+
+func (m *MockPostgresDB) ExportDetectedDeals(ctx context.Context, filter db.ExportFilter) (db.Rows, error) {
+	args := m.Called(ctx, filter)
+	var rows db.Rows
+	if r := args.Get(0); r != nil {
+		rows = r.(db.Rows)
+	}
+	return rows, args.Error(1)
+}
+
 // --- Deal Detection Methods Mock Implementations ---
 
-func (m *MockPostgresDB) GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class string) (*db.RouteBaseline, error) {
-	args := m.Called(ctx, origin, dest, tripLength, class)
+func (m *MockPostgresDB) GetRouteBaseline(ctx context.Context, origin, dest string, tripLength int, class, currency string) (*db.RouteBaseline, error) {
+	args := m.Called(ctx, origin, dest, tripLength, class, currency)
 	var baseline *db.RouteBaseline
 	if b := args.Get(0); b != nil {
 		baseline = b.(*db.RouteBaseline)
@@ -451,8 +498,8 @@ func (m *MockPostgresDB) UpsertRouteBaseline(ctx context.Context, baseline db.Ro
 	return args.Error(0)
 }
 
-func (m *MockPostgresDB) GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class string, windowDays int) ([]float64, error) {
-	args := m.Called(ctx, origin, dest, tripLength, class, windowDays)
+func (m *MockPostgresDB) GetPriceHistoryForRoute(ctx context.Context, origin, dest string, tripLength int, class, currency string, windowDays int) ([]float64, error) {
+	args := m.Called(ctx, origin, dest, tripLength, class, currency, windowDays)
 	var prices []float64
 	if p := args.Get(0); p != nil {
 		prices = p.([]float64)
@@ -521,3 +568,55 @@ func (m *MockPostgresDB) FinalizeBulkSearch(ctx context.Context, bulkSearchID in
 
 // Ensure MockPostgresDB implements db.PostgresDB
 var _ db.PostgresDB = (*MockPostgresDB)(nil)
+
+func (m *MockPostgresDB) CreatePosComparison(ctx context.Context, comparison db.PosComparison) (int, error) {
+	args := m.Called(ctx, comparison)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPostgresDB) UpdatePosComparisonStatus(ctx context.Context, comparisonID int, status string) error {
+	args := m.Called(ctx, comparisonID, status)
+	return args.Error(0)
+}
+
+func (m *MockPostgresDB) CompletePosComparison(ctx context.Context, summary db.PosComparisonSummary) error {
+	args := m.Called(ctx, summary)
+	return args.Error(0)
+}
+
+func (m *MockPostgresDB) GetPosComparisonByID(ctx context.Context, comparisonID int) (*db.PosComparison, error) {
+	args := m.Called(ctx, comparisonID)
+	var comparison *db.PosComparison
+	if c := args.Get(0); c != nil {
+		comparison = c.(*db.PosComparison)
+	}
+	return comparison, args.Error(1)
+}
+
+func (m *MockPostgresDB) InsertPosComparisonResult(ctx context.Context, result db.PosComparisonResult) error {
+	args := m.Called(ctx, result)
+	return args.Error(0)
+}
+
+func (m *MockPostgresDB) ListPosComparisonResults(ctx context.Context, comparisonID int) ([]db.PosComparisonResult, error) {
+	args := m.Called(ctx, comparisonID)
+	var results []db.PosComparisonResult
+	if r := args.Get(0); r != nil {
+		results = r.([]db.PosComparisonResult)
+	}
+	return results, args.Error(1)
+}
+
+func (m *MockPostgresDB) GetFXRates(ctx context.Context, day time.Time) ([]db.FXRate, error) {
+	args := m.Called(ctx, day)
+	var rates []db.FXRate
+	if r := args.Get(0); r != nil {
+		rates = r.([]db.FXRate)
+	}
+	return rates, args.Error(1)
+}
+
+func (m *MockPostgresDB) UpsertFXRates(ctx context.Context, rates []db.FXRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/gilby125/google-flights-api/queue" // Adjust import path if necessary
 	"github.com/stretchr/testify/mock"
@@ -18,6 +19,18 @@ func (m *MockQueue) Enqueue(ctx context.Context, jobType string, payload interfa
 	return args.String(0), args.Error(1)
 }
 
+// EnqueueAt mocks the EnqueueAt method
+func (m *MockQueue) EnqueueAt(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (string, error) {
+	args := m.Called(ctx, jobType, payload, runAt)
+	return args.String(0), args.Error(1)
+}
+
+// EnqueueAfter mocks the EnqueueAfter method
+func (m *MockQueue) EnqueueAfter(ctx context.Context, jobType string, payload interface{}, delay time.Duration) (string, error) {
+	args := m.Called(ctx, jobType, payload, delay)
+	return args.String(0), args.Error(1)
+}
+
 // Dequeue mocks the Dequeue method
 func (m *MockQueue) Dequeue(ctx context.Context, queueName string) (*queue.Job, error) {
 	args := m.Called(ctx, queueName)
@@ -0,0 +1,93 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gilby125/google-flights-api/api"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminDeadLetterEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	jobID, err := q.Enqueue(ctx, "bulk_search", map[string]string{"origin": "XXX"})
+	require.NoError(t, err)
+	for {
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		if job == nil {
+			break
+		}
+		require.NoError(t, q.NackWithFailure(ctx, "bulk_search", job.ID, queue.Failure{Error: "unknown airport XXX", ErrorClass: "error", WorkerID: "worker-1"}))
+	}
+
+	router := gin.New()
+	router.GET("/api/v1/admin/queue/:name/dead-letters", api.ListDeadLetters(q))
+	router.POST("/api/v1/admin/queue/:name/dead-letters/replay", api.ReplayDeadLetters(q))
+	router.GET("/api/v1/admin/queue/:name/dead-letters/:id", api.GetDeadLetter(q))
+	router.PUT("/api/v1/admin/queue/:name/dead-letters/:id/payload", api.UpdateDeadLetterPayload(q))
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodGet, "/api/v1/admin/queue/bulk_search/dead-letters?error_class=error", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list apitypes.QueueDeadLetterListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Count)
+	assert.Equal(t, jobID, list.DeadLetters[0].Job.ID)
+	assert.Equal(t, "worker-1", list.DeadLetters[0].WorkerID)
+	assert.Len(t, list.DeadLetters[0].Job.Failures, 3)
+
+	w = serve(http.MethodGet, "/api/v1/admin/queue/bulk_search/dead-letters?error_class=timeout", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Zero(t, list.Count)
+	assert.NotNil(t, list.DeadLetters)
+
+	w = serve(http.MethodGet, "/api/v1/admin/queue/bulk_search/dead-letters/missing", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(http.MethodPut, "/api/v1/admin/queue/bulk_search/dead-letters/"+jobID+"/payload", `{"payload":{"origin":"JFK"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var one apitypes.QueueDeadLetterResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &one))
+	assert.JSONEq(t, `{"origin":"JFK"}`, string(one.DeadLetter.Job.Payload))
+	assert.NotNil(t, one.DeadLetter.PayloadEditedAt)
+
+	w = serve(http.MethodPost, "/api/v1/admin/queue/bulk_search/dead-letters/replay", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(http.MethodPost, "/api/v1/admin/queue/bulk_search/dead-letters/replay", `{"job_ids":["`+jobID+`"]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var replay apitypes.QueueDeadLetterReplayResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &replay))
+	assert.Equal(t, []string{jobID}, replay.Replayed)
+	assert.Equal(t, int64(1), replay.Stats["pending"])
+	assert.Equal(t, int64(0), replay.Stats["failed"])
+
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.JSONEq(t, `{"origin":"JFK"}`, string(job.Payload))
+
+	w = serve(http.MethodGet, "/api/v1/admin/queue/not_a_queue/dead-letters", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
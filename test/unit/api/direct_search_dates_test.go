@@ -5,13 +5,14 @@ import (
 	"time"
 
 	"github.com/gilby125/google-flights-api/api"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestPlanDirectSearchDates_PriceGraphOnlyDefaultsRangeAndTripLength(t *testing.T) {
 	now := time.Date(2026, 1, 28, 15, 4, 0, 0, time.UTC)
-	req := api.DirectSearchRequest{
+	req := apitypes.DirectSearchRequest{
 		TripType:          "round_trip",
 		IncludePriceGraph: true,
 	}
@@ -29,7 +30,7 @@ func TestPlanDirectSearchDates_PriceGraphOnlyDefaultsRangeAndTripLength(t *testi
 
 func TestPlanDirectSearchDates_ErrorsWhenNoDepartureAndNoPriceGraph(t *testing.T) {
 	now := time.Date(2026, 1, 28, 15, 4, 0, 0, time.UTC)
-	req := api.DirectSearchRequest{IncludePriceGraph: false}
+	req := apitypes.DirectSearchRequest{IncludePriceGraph: false}
 
 	_, err := api.PlanDirectSearchDates(now, req)
 	require.Error(t, err)
@@ -37,7 +38,7 @@ func TestPlanDirectSearchDates_ErrorsWhenNoDepartureAndNoPriceGraph(t *testing.T
 
 func TestPlanDirectSearchDates_ParsesFixedDates(t *testing.T) {
 	now := time.Date(2026, 1, 28, 15, 4, 0, 0, time.UTC)
-	req := api.DirectSearchRequest{
+	req := apitypes.DirectSearchRequest{
 		DepartureDate:     "2026-03-01",
 		ReturnDate:        "2026-03-08",
 		TripType:          "round_trip",
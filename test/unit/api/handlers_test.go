@@ -19,6 +19,8 @@ import (
 	"github.com/gilby125/google-flights-api/config"
 	"github.com/gilby125/google-flights-api/db" // Import db package
 	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/apitypes"
+	"github.com/gilby125/google-flights-api/queue"
 	"github.com/gilby125/google-flights-api/test/mocks" // Assuming mocks are here
 	"github.com/gilby125/google-flights-api/worker"
 	"github.com/gin-gonic/gin"
@@ -26,8 +28,8 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-func dateOnly(t time.Time) api.DateOnly {
-	return api.DateOnly{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+func dateOnly(t time.Time) apitypes.DateOnly {
+	return apitypes.DateOnly{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
 }
 
 // --- Test Setup ---
@@ -156,7 +158,7 @@ func TestCreateSearch_Success(t *testing.T) {
 	router := setupRouter()
 	router.POST("/search", api.CreateSearch(mockQueue)) // Use the actual handler constructor
 
-	searchReq := api.SearchRequest{
+	searchReq := apitypes.SearchRequest{
 		Origin:        "LHR",
 		Destination:   "JFK",
 		DepartureDate: dateOnly(time.Now().AddDate(0, 1, 0)),
@@ -227,7 +229,7 @@ func TestCreateSearch_ValidationError(t *testing.T) {
 	router := setupRouter()
 	router.POST("/search", api.CreateSearch(mockQueue))
 
-	searchReq := api.SearchRequest{
+	searchReq := apitypes.SearchRequest{
 		// Missing required fields like Origin, Destination, etc.
 		Adults: 0, // Invalid value
 	}
@@ -250,7 +252,7 @@ func TestCreateSearch_EnqueueError(t *testing.T) {
 	router := setupRouter()
 	router.POST("/search", api.CreateSearch(mockQueue))
 
-	searchReq := api.SearchRequest{
+	searchReq := apitypes.SearchRequest{
 		Origin:        "LHR",
 		Destination:   "JFK",
 		DepartureDate: dateOnly(time.Now().AddDate(0, 1, 0)),
@@ -292,6 +294,50 @@ func TestCreateSearch_EnqueueError(t *testing.T) {
 	mockQueue.AssertExpectations(t)
 }
 
+// queuedBulkSearchJob is the queued bulk_search job jobID of the bulk search bulkSearchID.
+func queuedBulkSearchJob(jobID string, bulkSearchID int) *queue.Job {
+	return &queue.Job{ID: jobID, Type: "bulk_search", Payload: json.RawMessage(fmt.Sprintf(`{"bulk_search_id":%d}`, bulkSearchID))}
+}
+
+func TestCreateBulkSearch_ReportsQueuedDuplicate(t *testing.T) {
+	mockQueue := new(mocks.Queue)
+	mockPostgres := new(mocks.MockPostgresDB)
+	router := setupRouter()
+	router.POST("/bulk-search", api.CreateBulkSearch(mockQueue, mockPostgres, nil))
+
+	bulkReq := apitypes.BulkSearchRequest{
+		Origins:           []string{"LHR"},
+		Destinations:      []string{"JFK"},
+		DepartureDateFrom: dateOnly(time.Now().AddDate(0, 1, 0)),
+		DepartureDateTo:   dateOnly(time.Now().AddDate(0, 1, 7)),
+		Adults:            1,
+		TripType:          "one_way",
+		Class:             "economy",
+		Stops:             "any",
+		Currency:          "USD",
+	}
+
+	// The same search is already queued for bulk search 99.
+	mockPostgres.On("CreateBulkSearchRecord", mock.Anything, mock.Anything, 1, "USD", "queued").Return(124, nil)
+	mockQueue.On("Enqueue", mock.Anything, "bulk_search", mock.Anything).Return("bulk-job-1", nil)
+	mockQueue.On("GetJob", mock.Anything, "bulk-job-1").Return(queuedBulkSearchJob("bulk-job-1", 99), nil)
+	mockPostgres.On("UpdateBulkSearchStatus", mock.Anything, 124, "duplicate").Return(nil)
+
+	body, _ := json.Marshal(bulkReq)
+	req, _ := http.NewRequest(http.MethodPost, "/bulk-search", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "bulk-job-1", response["job_id"])
+	assert.Equal(t, float64(99), response["bulk_search_id"])
+	mockQueue.AssertExpectations(t)
+	mockPostgres.AssertExpectations(t)
+}
+
 func TestCreateBulkSearch_Success(t *testing.T) {
 	// Arrange
 	mockQueue := new(mocks.Queue)
@@ -299,7 +345,7 @@ func TestCreateBulkSearch_Success(t *testing.T) {
 	router := setupRouter()
 	router.POST("/bulk-search", api.CreateBulkSearch(mockQueue, mockPostgres, nil))
 
-	bulkReq := api.BulkSearchRequest{
+	bulkReq := apitypes.BulkSearchRequest{
 		Origins:           []string{"LHR", "LGW"},
 		Destinations:      []string{"JFK", "EWR"},
 		DepartureDateFrom: dateOnly(time.Now().AddDate(0, 1, 0)),
@@ -337,6 +383,7 @@ func TestCreateBulkSearch_Success(t *testing.T) {
 	mockQueue.On("Enqueue", mock.Anything, "bulk_search",
 		mock.MatchedBy(matchBulkSearchPayload(expectedPayload)),
 	).Return(expectedJobID, nil)
+	mockQueue.On("GetJob", mock.Anything, expectedJobID).Return(queuedBulkSearchJob(expectedJobID, createdBulkSearchID), nil)
 
 	// Act
 	body, _ := json.Marshal(bulkReq)
@@ -364,7 +411,7 @@ func TestCreateBulkSearch_SkipsSelfRoutesInTotal(t *testing.T) {
 	router := setupRouter()
 	router.POST("/bulk-search", api.CreateBulkSearch(mockQueue, mockPostgres, nil))
 
-	bulkReq := api.BulkSearchRequest{
+	bulkReq := apitypes.BulkSearchRequest{
 		Origins:           []string{"SJU", "STT"},
 		Destinations:      []string{"SJU", "ANU"},
 		DepartureDateFrom: dateOnly(time.Now().AddDate(0, 1, 0)),
@@ -403,6 +450,7 @@ func TestCreateBulkSearch_SkipsSelfRoutesInTotal(t *testing.T) {
 	mockQueue.On("Enqueue", mock.Anything, "bulk_search",
 		mock.MatchedBy(matchBulkSearchPayload(expectedPayload)),
 	).Return(expectedJobID, nil)
+	mockQueue.On("GetJob", mock.Anything, expectedJobID).Return(queuedBulkSearchJob(expectedJobID, createdBulkSearchID), nil)
 
 	// Act
 	body, _ := json.Marshal(bulkReq)
@@ -441,7 +489,7 @@ func TestCreateBulkSearch_ValidationError(t *testing.T) {
 	router := setupRouter()
 	router.POST("/bulk-search", api.CreateBulkSearch(mockQueue, mockPostgres, nil))
 
-	bulkReq := api.BulkSearchRequest{
+	bulkReq := apitypes.BulkSearchRequest{
 		// Missing required fields
 	}
 
@@ -463,7 +511,7 @@ func TestCreateBulkSearch_EnqueueError(t *testing.T) {
 	router := setupRouter()
 	router.POST("/bulk-search", api.CreateBulkSearch(mockQueue, mockPostgres, nil))
 
-	bulkReq := api.BulkSearchRequest{
+	bulkReq := apitypes.BulkSearchRequest{
 		Origins:           []string{"LHR"},
 		Destinations:      []string{"JFK"},
 		DepartureDateFrom: dateOnly(time.Now().AddDate(0, 1, 0)),
@@ -519,6 +567,7 @@ func TestGetQueueStatus_Success(t *testing.T) {
 	router.GET("/queue/status", api.GetQueueStatus(mockQueue)) // Use the actual handler constructor
 
 	expectedStatsFS := map[string]int64{"pending": 10, "active": 2}
+	expectedStatsPOS := map[string]int64{"pending": 1, "active": 0}
 	expectedStatsBS := map[string]int64{"pending": 5, "active": 1}
 	expectedStatsBSR := map[string]int64{"pending": 7, "active": 3}
 	expectedStatsPGS := map[string]int64{"pending": 0, "active": 0}
@@ -526,6 +575,7 @@ func TestGetQueueStatus_Success(t *testing.T) {
 
 	// Configure mock
 	mockQueue.On("GetQueueStats", mock.Anything, "flight_search").Return(expectedStatsFS, nil)
+	mockQueue.On("GetQueueStats", mock.Anything, "pos_comparison").Return(expectedStatsPOS, nil)
 	mockQueue.On("GetQueueStats", mock.Anything, "bulk_search").Return(expectedStatsBS, nil)
 	mockQueue.On("GetQueueStats", mock.Anything, "bulk_search_route").Return(expectedStatsBSR, nil)
 	mockQueue.On("GetQueueStats", mock.Anything, "price_graph_sweep").Return(expectedStatsPGS, nil)
@@ -542,6 +592,7 @@ func TestGetQueueStatus_Success(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedStatsFS, response["flight_search"])
+	assert.Equal(t, expectedStatsPOS, response["pos_comparison"])
 	assert.Equal(t, expectedStatsBS, response["bulk_search"])
 	assert.Equal(t, expectedStatsBSR, response["bulk_search_route"])
 	assert.Equal(t, expectedStatsPGS, response["price_graph_sweep"])
@@ -862,7 +913,7 @@ func TestGetSearchByID_Success(t *testing.T) {
 	}
 	mockSegmentRows.On("Next").Return(true).Times(len(mockSegments))
 	mockSegmentRows.On("Next").Return(false)
-	mockSegmentRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+	mockSegmentRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
 		*(args.Get(0).(*string)) = mockSegments[0].AirlineCode
 		*(args.Get(1).(*string)) = mockSegments[0].FlightNumber
 		*(args.Get(2).(*string)) = mockSegments[0].DepartureAirport
@@ -873,8 +924,9 @@ func TestGetSearchByID_Success(t *testing.T) {
 		*(args.Get(7).(*string)) = mockSegments[0].Airplane
 		*(args.Get(8).(*string)) = mockSegments[0].Legroom
 		*(args.Get(9).(*bool)) = mockSegments[0].IsReturn
+		*(args.Get(10).(*int)) = mockSegments[0].SegmentIndex
 	}).Once()
-	mockSegmentRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+	mockSegmentRows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
 		*(args.Get(0).(*string)) = mockSegments[1].AirlineCode
 		*(args.Get(1).(*string)) = mockSegments[1].FlightNumber
 		*(args.Get(2).(*string)) = mockSegments[1].DepartureAirport
@@ -885,6 +937,7 @@ func TestGetSearchByID_Success(t *testing.T) {
 		*(args.Get(7).(*string)) = mockSegments[1].Airplane
 		*(args.Get(8).(*string)) = mockSegments[1].Legroom
 		*(args.Get(9).(*bool)) = mockSegments[1].IsReturn
+		*(args.Get(10).(*int)) = mockSegments[1].SegmentIndex
 	}).Once()
 	mockSegmentRows.On("Close").Return(nil)
 	mockSegmentRows.On("Err").Return(nil)
@@ -56,3 +56,10 @@ func TestParseRouteInputs_MissingDestination(t *testing.T) {
 	_, _, err := api.ParseRouteInputs("JFK", "")
 	assert.Error(t, err)
 }
+
+func TestParseRouteInputs_NearToken(t *testing.T) {
+	origins, destinations, err := api.ParseRouteInputs("near:sfo:150, OAK", "LHR")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NEAR:SFO:150", "OAK"}, origins)
+	assert.Equal(t, []string{"LHR"}, destinations)
+}
@@ -0,0 +1,585 @@
+package queue_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conformanceQueue is what every queue backend implements.
+type conformanceQueue interface {
+	queue.FairQueue
+	queue.Promoter
+	queue.DeadLetterQueue
+	queue.Deferrer
+}
+
+// queueFactory creates an empty queue with cfg.
+type queueFactory func(t *testing.T, cfg config.RedisConfig) conformanceQueue
+
+func TestRedisQueue_Conformance(t *testing.T) {
+	runQueueConformance(t, func(t *testing.T, cfg config.RedisConfig) conformanceQueue {
+		mr := miniredis.RunT(t)
+		host, port, ok := strings.Cut(mr.Addr(), ":")
+		require.True(t, ok)
+		cfg.Host = host
+		cfg.Port = port
+
+		q, err := queue.NewRedisQueue(cfg)
+		require.NoError(t, err)
+		return q
+	})
+}
+
+func TestPostgresQueue_Conformance(t *testing.T) {
+	connString := postgresQueueURL(t)
+	runQueueConformance(t, func(t *testing.T, cfg config.RedisConfig) conformanceQueue {
+		truncateQueueTables(t, connString)
+		q, err := queue.NewPostgresQueue(connString, cfg)
+		require.NoError(t, err)
+		t.Cleanup(func() { q.Close() })
+		return q
+	})
+}
+
+func TestPostgresQueue_LeaderLock(t *testing.T) {
+	connString := postgresQueueURL(t)
+	truncateQueueTables(t, connString)
+	q, err := queue.NewPostgresQueue(connString, config.RedisConfig{})
+	require.NoError(t, err)
+	defer q.Close()
+	ctx := context.Background()
+
+	ok, err := q.TryLock(ctx, "scheduler", "a", 200*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = q.TryLock(ctx, "scheduler", "b", 200*time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, ok, "the lock has an owner")
+
+	ok, err = q.RenewLock(ctx, "scheduler", "b", 200*time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, ok, "only the owner renews")
+	ok, err = q.RenewLock(ctx, "scheduler", "a", 200*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	time.Sleep(250 * time.Millisecond)
+	ok, err = q.TryLock(ctx, "scheduler", "b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "an expired lock can be taken over")
+
+	ok, err = q.Unlock(ctx, "scheduler", "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = q.Unlock(ctx, "scheduler", "b")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestPostgresQueue_CancelKeepsStartedJobs(t *testing.T) {
+	connString := postgresQueueURL(t)
+	truncateQueueTables(t, connString)
+	q, err := queue.NewPostgresQueue(connString, config.RedisConfig{})
+	require.NoError(t, err)
+	defer q.Close()
+	ctx := context.Background()
+
+	processingID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+	require.NoError(t, err)
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	require.Equal(t, processingID, job.ID)
+
+	require.NoError(t, q.CancelJob(ctx, "bulk_search", processingID))
+	status, err := q.GetJobStatus(ctx, processingID)
+	require.NoError(t, err)
+	assert.Equal(t, "processing", status, "the worker acks the job it holds")
+	canceled, err := q.IsJobCanceled(ctx, processingID)
+	require.NoError(t, err)
+	assert.True(t, canceled)
+
+	require.NoError(t, q.Ack(ctx, "bulk_search", processingID))
+	require.NoError(t, q.CancelJob(ctx, "bulk_search", processingID))
+	status, err = q.GetJobStatus(ctx, processingID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", status, "a finished job keeps its outcome")
+}
+
+// postgresQueueURL returns the database the Postgres queue tests run against, migrated, or
+// skips the test if there is none.
+func postgresQueueURL(t *testing.T) string {
+	t.Helper()
+	connString := os.Getenv("TEST_POSTGRES_URL")
+	if connString == "" {
+		t.Skip("set TEST_POSTGRES_URL to run the Postgres queue tests")
+	}
+	require.NoError(t, db.RunMigrations(connString))
+	return connString
+}
+
+func truncateQueueTables(t *testing.T, connString string) {
+	t.Helper()
+	pg, err := sql.Open("postgres", connString)
+	require.NoError(t, err)
+	defer pg.Close()
+	_, err = pg.Exec(`TRUNCATE queue_jobs, queue_job_cancels, queue_enqueue_metrics, queue_locks`)
+	require.NoError(t, err)
+}
+
+// runQueueConformance checks the behavior every queue backend shares. Backend specifics such
+// as stream IDs are left to the backend's own tests.
+func runQueueConformance(t *testing.T, newQueue queueFactory) {
+	base := config.RedisConfig{
+		QueueGroup:             "test_group",
+		QueueStreamPrefix:      "test_stream",
+		QueueBlockTimeout:      50 * time.Millisecond,
+		QueueVisibilityTimeout: 50 * time.Millisecond,
+	}
+	ctx := context.Background()
+
+	t.Run("enqueue, dequeue and ack", func(t *testing.T) {
+		q := newQueue(t, base)
+
+		jobID, err := q.Enqueue(ctx, "bulk_search", map[string]string{"origin": "JFK"})
+		require.NoError(t, err)
+		stats, err := q.GetQueueStats(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats["pending"])
+
+		job, err := q.GetJob(ctx, jobID)
+		require.NoError(t, err)
+		assert.Equal(t, "bulk_search", job.Type)
+		assert.JSONEq(t, `{"origin":"JFK"}`, string(job.Payload))
+
+		job, err = q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, jobID, job.ID)
+		assert.Equal(t, 1, job.Attempts)
+		status, err := q.GetJobStatus(ctx, jobID)
+		require.NoError(t, err)
+		assert.Equal(t, "processing", status)
+
+		require.NoError(t, q.Ack(ctx, "bulk_search", jobID))
+		status, err = q.GetJobStatus(ctx, jobID)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", status)
+		stats, err = q.GetQueueStats(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats["pending"])
+		assert.Equal(t, int64(0), stats["processing"])
+		assert.Equal(t, int64(1), stats["completed"])
+
+		completed, err := q.ListJobs(ctx, "bulk_search", "completed", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, completed, 1)
+		assert.Equal(t, jobID, completed[0].ID)
+		_, err = q.ListJobs(ctx, "bulk_search", "bogus", 10, 0)
+		assert.Error(t, err)
+
+		job, err = q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Nil(t, job)
+		_, err = q.GetJobStatus(ctx, "missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("nack retries until the job fails", func(t *testing.T) {
+		q := newQueue(t, base)
+
+		jobID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+		require.NoError(t, err)
+		for attempt := 1; attempt <= 3; attempt++ {
+			job, err := q.Dequeue(ctx, "bulk_search")
+			require.NoError(t, err)
+			require.NotNil(t, job, "attempt %d", attempt)
+			assert.Equal(t, attempt, job.Attempts)
+			require.NoError(t, q.Nack(ctx, "bulk_search", job.ID))
+		}
+
+		status, err := q.GetJobStatus(ctx, jobID)
+		require.NoError(t, err)
+		assert.Equal(t, "failed", status)
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Nil(t, job)
+
+		retried, err := q.RetryFailed(ctx, "bulk_search", 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), retried)
+		job, err = q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, jobID, job.ID)
+		assert.Equal(t, 1, job.Attempts, "retried jobs start over")
+	})
+
+	t.Run("retry backoff schedules the retry", func(t *testing.T) {
+		cfg := base
+		cfg.QueueRetryBaseDelay = 100 * time.Millisecond
+		cfg.QueueRetryMaxDelay = time.Second
+		q := newQueue(t, cfg)
+
+		jobID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+		require.NoError(t, err)
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		require.NoError(t, q.Nack(ctx, "bulk_search", job.ID))
+
+		retry, err := q.GetJob(ctx, jobID)
+		require.NoError(t, err)
+		assert.Equal(t, "scheduled", retry.Status)
+		require.NotNil(t, retry.RunAt)
+
+		job, err = q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Nil(t, job, "retries wait for their backoff")
+
+		time.Sleep(time.Until(*retry.RunAt) + 10*time.Millisecond)
+		promoted, err := q.PromoteDue(ctx, []string{"bulk_search"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), promoted)
+		job, err = q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, 2, job.Attempts)
+	})
+
+	t.Run("deferred jobs keep their attempts", func(t *testing.T) {
+		q := newQueue(t, base)
+
+		jobID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+		require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			job, err := q.Dequeue(ctx, "bulk_search")
+			require.NoError(t, err)
+			require.NotNil(t, job, "defer %d", i)
+			assert.Equal(t, 1, job.Attempts)
+			require.NoError(t, q.Defer(ctx, "bulk_search", job.ID, time.Now().Add(50*time.Millisecond)))
+
+			deferred, err := q.GetJob(ctx, jobID)
+			require.NoError(t, err)
+			assert.Equal(t, "scheduled", deferred.Status)
+			stats, err := q.GetQueueStats(ctx, "bulk_search")
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), stats["processing"])
+
+			job, err = q.Dequeue(ctx, "bulk_search")
+			require.NoError(t, err)
+			assert.Nil(t, job, "deferred jobs wait until runAt")
+			time.Sleep(60 * time.Millisecond)
+			promoted, err := q.PromoteDue(ctx, []string{"bulk_search"})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), promoted)
+		}
+
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, 1, job.Attempts, "deferring doesn't use up the attempts")
+		require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+	})
+
+	t.Run("unacked jobs are redelivered after the visibility timeout", func(t *testing.T) {
+		q := newQueue(t, base)
+
+		jobID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+		require.NoError(t, err)
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+
+		time.Sleep(100 * time.Millisecond)
+		job, err = q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job, "the worker holding the job is presumed dead")
+		assert.Equal(t, jobID, job.ID)
+		require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		q := newQueue(t, base)
+
+		canceledID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+		require.NoError(t, err)
+		runningID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 2})
+		require.NoError(t, err)
+
+		require.NoError(t, q.CancelJob(ctx, "bulk_search", canceledID))
+		assert.Error(t, q.CancelJob(ctx, "bulk_search", ""))
+		canceled, err := q.IsJobCanceled(ctx, canceledID)
+		require.NoError(t, err)
+		assert.True(t, canceled)
+		status, err := q.GetJobStatus(ctx, canceledID)
+		require.NoError(t, err)
+		assert.Equal(t, "canceled", status)
+
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, runningID, job.ID, "canceled jobs do not start")
+		canceled, err = q.IsJobCanceled(ctx, runningID)
+		require.NoError(t, err)
+		assert.False(t, canceled)
+
+		n, err := q.CancelProcessing(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		canceled, err = q.IsJobCanceled(ctx, runningID)
+		require.NoError(t, err)
+		assert.True(t, canceled)
+
+		// A cancellation outlives the job it was requested for.
+		require.NoError(t, q.CancelJob(ctx, "bulk_search", "cleared-job"))
+		canceled, err = q.IsJobCanceled(ctx, "cleared-job")
+		require.NoError(t, err)
+		assert.True(t, canceled)
+	})
+
+	t.Run("priority lanes and tenants", func(t *testing.T) {
+		q := newQueue(t, base)
+
+		_, err := q.Enqueue(ctx, "bulk_search", map[string]string{"lane": "normal"})
+		require.NoError(t, err)
+		highID, err := q.Enqueue(queue.WithPriority(ctx, queue.PriorityHigh), "bulk_search", map[string]string{"lane": "high"})
+		require.NoError(t, err)
+
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, highID, job.ID)
+		assert.Equal(t, queue.PriorityHigh, job.Priority)
+
+		// A nacked job is retried in its own lane, behind the rest of the backlog.
+		require.NoError(t, q.Nack(ctx, "bulk_search", job.ID))
+		backlog, err := q.GetBacklog(ctx, "bulk_search", 10)
+		require.NoError(t, err)
+		require.Len(t, backlog, 2)
+		assert.Equal(t, highID, backlog[0].ID)
+		assert.Equal(t, queue.PriorityNormal, backlog[1].Priority)
+		for i := 0; i < 2; i++ {
+			job, err = q.Dequeue(ctx, "bulk_search")
+			require.NoError(t, err)
+			require.NotNil(t, job)
+			require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+		}
+
+		// A tenant with a large backlog does not hold up the others.
+		acme := queue.WithTenant(ctx, "acme")
+		for i := 0; i < 5; i++ {
+			_, err := q.Enqueue(acme, "bulk_search", map[string]int{"n": i})
+			require.NoError(t, err)
+		}
+		_, err = q.Enqueue(queue.WithTenant(ctx, "globex"), "bulk_search", map[string]int{"n": 0})
+		require.NoError(t, err)
+
+		tenants := map[string]bool{}
+		for i := 0; i < 2; i++ {
+			job, err := q.DequeueFair(ctx, []string{"bulk_search", "flight_search"})
+			require.NoError(t, err)
+			require.NotNil(t, job)
+			tenants[job.Tenant] = true
+		}
+		assert.Equal(t, map[string]bool{"acme": true, "globex": true}, tenants)
+	})
+
+	t.Run("delayed jobs are promoted when due", func(t *testing.T) {
+		q := newQueue(t, base)
+
+		jobID, err := q.EnqueueAfter(ctx, "bulk_search", map[string]string{"origin": "JFK"}, 100*time.Millisecond)
+		require.NoError(t, err)
+		status, err := q.GetJobStatus(ctx, jobID)
+		require.NoError(t, err)
+		assert.Equal(t, "scheduled", status)
+		scheduled, err := q.ListJobs(ctx, "bulk_search", "scheduled", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, scheduled, 1)
+		require.NotNil(t, scheduled[0].RunAt)
+
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Nil(t, job, "scheduled jobs must not be delivered early")
+		promoted, err := q.PromoteDue(ctx, []string{"bulk_search"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), promoted)
+
+		time.Sleep(120 * time.Millisecond)
+		promoted, err = q.PromoteDue(ctx, []string{"bulk_search"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), promoted)
+		job, err = q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, jobID, job.ID)
+
+		pastID, err := q.EnqueueAt(ctx, "bulk_search", map[string]int{"n": 2}, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		status, err = q.GetJobStatus(ctx, pastID)
+		require.NoError(t, err)
+		assert.Equal(t, "pending", status, "a runAt in the past enqueues right away")
+	})
+
+	t.Run("enqueue metrics", func(t *testing.T) {
+		q := newQueue(t, base)
+
+		scheduler := queue.WithEnqueueMeta(ctx, queue.EnqueueMeta{Actor: "scheduler"})
+		for i := 0; i < 2; i++ {
+			_, err := q.Enqueue(scheduler, "bulk_search", map[string]int{"n": i})
+			require.NoError(t, err)
+		}
+		_, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 3})
+		require.NoError(t, err)
+
+		metrics, err := q.GetEnqueueMetrics(ctx, "bulk_search", 5)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{"scheduler": 2, "unknown": 1}, metrics)
+	})
+
+	t.Run("clearing", func(t *testing.T) {
+		q := newQueue(t, base)
+
+		_, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+		require.NoError(t, err)
+		_, err = q.Enqueue(ctx, "bulk_search", map[string]int{"n": 2})
+		require.NoError(t, err)
+		_, err = q.EnqueueAfter(ctx, "bulk_search", map[string]int{"n": 3}, time.Hour)
+		require.NoError(t, err)
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+
+		cleared, err := q.ClearQueue(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), cleared, "pending and scheduled jobs go")
+		stats, err := q.GetQueueStats(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats["pending"])
+		assert.Equal(t, int64(0), stats["scheduled"])
+		assert.Equal(t, int64(1), stats["processing"])
+
+		cleared, err = q.ClearProcessing(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), cleared)
+		stats, err = q.GetQueueStats(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), stats["processing"])
+	})
+
+	t.Run("dead letters", func(t *testing.T) {
+		q := newQueue(t, base)
+		failAll := func(failure queue.Failure) {
+			for {
+				job, err := q.Dequeue(ctx, "bulk_search")
+				require.NoError(t, err)
+				if job == nil {
+					return
+				}
+				require.NoError(t, q.NackWithFailure(ctx, "bulk_search", job.ID, failure))
+			}
+		}
+
+		timeoutID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+		require.NoError(t, err)
+		failAll(queue.Failure{Error: "context deadline exceeded", ErrorClass: "timeout", WorkerID: "worker-a"})
+		payloadID, err := q.Enqueue(ctx, "bulk_search", map[string]string{"origin": "XXX"})
+		require.NoError(t, err)
+		failAll(queue.Failure{Error: "unknown airport", ErrorClass: "invalid_payload"})
+
+		dl, err := q.GetDeadLetter(ctx, "bulk_search", timeoutID)
+		require.NoError(t, err)
+		assert.Equal(t, "bulk_search", dl.Queue)
+		assert.Equal(t, "timeout", dl.ErrorClass)
+		assert.Equal(t, "worker-a", dl.WorkerID)
+		assert.Len(t, dl.Job.Failures, 3)
+		_, err = q.GetDeadLetter(ctx, "bulk_search", "missing")
+		assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+
+		all, err := q.ListDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{}, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		assert.Equal(t, payloadID, all[0].Job.ID, "most recent first")
+		timeouts, err := q.ListDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{ErrorClass: "timeout"}, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, timeouts, 1)
+		assert.Equal(t, timeoutID, timeouts[0].Job.ID)
+
+		_, err = q.UpdateDeadLetterPayload(ctx, "bulk_search", payloadID, []byte(`{"origin":`))
+		assert.ErrorIs(t, err, queue.ErrInvalidPayload)
+		dl, err = q.UpdateDeadLetterPayload(ctx, "bulk_search", payloadID, []byte(`{"origin":"JFK"}`))
+		require.NoError(t, err)
+		require.NotNil(t, dl.PayloadEditedAt)
+
+		replayed, err := q.ReplayDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{JobIDs: []string{payloadID}}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{payloadID}, replayed)
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, payloadID, job.ID)
+		assert.JSONEq(t, `{"origin":"JFK"}`, string(job.Payload))
+		assert.Len(t, job.Failures, 3, "replayed jobs keep their failure timeline")
+		require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+
+		cleared, err := q.ClearFailed(ctx, "bulk_search")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), cleared)
+		_, err = q.GetDeadLetter(ctx, "bulk_search", timeoutID)
+		assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+	})
+
+	t.Run("idempotency keys", func(t *testing.T) {
+		cfg := base
+		cfg.QueueDedupWindow = time.Minute
+		q := newQueue(t, cfg)
+		payload := routePayload{Origin: "JFK", Destination: "LHR"}
+
+		firstID, err := q.Enqueue(ctx, "bulk_search_route", payload)
+		require.NoError(t, err)
+		dupID, err := q.Enqueue(ctx, "bulk_search_route", payload)
+		require.NoError(t, err)
+		assert.Equal(t, firstID, dupID)
+		tenantID, err := q.Enqueue(queue.WithTenant(ctx, "acme"), "bulk_search_route", payload)
+		require.NoError(t, err)
+		assert.NotEqual(t, firstID, tenantID)
+		keyedID, err := q.Enqueue(queue.WithIdempotencyKey(ctx, "JFK-LHR"), "bulk_search_route", map[string]int{"n": 1})
+		require.NoError(t, err)
+		assert.Equal(t, firstID, keyedID)
+
+		job, err := q.GetJob(ctx, firstID)
+		require.NoError(t, err)
+		assert.Equal(t, "JFK-LHR", job.IdempotencyKey)
+
+		// Failed jobs give up their key.
+		for {
+			job, err := q.Dequeue(ctx, "bulk_search_route")
+			require.NoError(t, err)
+			if job == nil {
+				break
+			}
+			if job.ID == firstID {
+				require.NoError(t, q.Nack(ctx, "bulk_search_route", job.ID))
+			} else {
+				require.NoError(t, q.Ack(ctx, "bulk_search_route", job.ID))
+			}
+		}
+		status, err := q.GetJobStatus(ctx, firstID)
+		require.NoError(t, err)
+		require.Equal(t, "failed", status)
+		retryID, err := q.Enqueue(ctx, "bulk_search_route", payload)
+		require.NoError(t, err)
+		assert.NotEqual(t, firstID, retryID)
+	})
+}
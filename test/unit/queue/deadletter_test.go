@@ -0,0 +1,192 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failUntilDead dequeues and nacks the next job of queueName until it runs out of attempts.
+func failUntilDead(t *testing.T, q *queue.RedisQueue, ctx context.Context, queueName string, failure queue.Failure) *queue.Job {
+	t.Helper()
+	var job *queue.Job
+	for {
+		next, err := q.Dequeue(ctx, queueName)
+		require.NoError(t, err)
+		if next == nil {
+			require.NotNil(t, job, "no job to fail")
+			return job
+		}
+		job = next
+		require.NoError(t, q.NackWithFailure(ctx, queueName, job.ID, failure))
+	}
+}
+
+func TestRedisQueue_DeadLetterAfterMaxAttempts(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	jobID, err := q.Enqueue(ctx, "bulk_search", map[string]string{"origin": "JFK"})
+	require.NoError(t, err)
+	failUntilDead(t, q, ctx, "bulk_search", queue.Failure{Error: "google: rate limited", ErrorClass: "rate_limited", WorkerID: "worker-a", Stack: "goroutine 1"})
+
+	status, err := q.GetJobStatus(ctx, jobID)
+	require.NoError(t, err)
+	assert.Equal(t, "failed", status)
+
+	dl, err := q.GetDeadLetter(ctx, "bulk_search", jobID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, dl.EntryID)
+	assert.Equal(t, "bulk_search", dl.Queue)
+	assert.Equal(t, "google: rate limited", dl.Error)
+	assert.Equal(t, "rate_limited", dl.ErrorClass)
+	assert.Equal(t, "worker-a", dl.WorkerID)
+	assert.JSONEq(t, `{"origin":"JFK"}`, string(dl.Job.Payload))
+
+	require.Len(t, dl.Job.Failures, 3)
+	for i, failure := range dl.Job.Failures {
+		assert.Equal(t, i+1, failure.Attempt)
+		assert.False(t, failure.FailedAt.IsZero())
+	}
+	assert.Empty(t, dl.Job.Failures[0].Stack, "only the last stack is kept")
+	assert.Equal(t, "goroutine 1", dl.Job.Failures[2].Stack)
+
+	_, err = q.GetDeadLetter(ctx, "bulk_search", "missing")
+	assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+}
+
+func TestRedisQueue_ListAndReplayDeadLettersByClass(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	timeoutID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+	require.NoError(t, err)
+	failUntilDead(t, q, ctx, "bulk_search", queue.Failure{Error: "context deadline exceeded", ErrorClass: "timeout"})
+	payloadID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 2})
+	require.NoError(t, err)
+	failUntilDead(t, q, ctx, "bulk_search", queue.Failure{Error: "bad payload", ErrorClass: "invalid_payload"})
+
+	all, err := q.ListDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, payloadID, all[0].Job.ID, "most recent first")
+	assert.Equal(t, timeoutID, all[1].Job.ID)
+
+	page, err := q.ListDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{}, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, timeoutID, page[0].Job.ID)
+
+	timeouts, err := q.ListDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{ErrorClass: "timeout"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, timeouts, 1)
+	assert.Equal(t, timeoutID, timeouts[0].Job.ID)
+
+	replayed, err := q.ReplayDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{ErrorClass: "timeout"}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{timeoutID}, replayed)
+
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, timeoutID, job.ID)
+	assert.Equal(t, 1, job.Attempts, "replayed jobs start over")
+	assert.Len(t, job.Failures, 3, "replayed jobs keep their failure timeline")
+	require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+
+	_, err = q.GetDeadLetter(ctx, "bulk_search", timeoutID)
+	assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+
+	stats, err := q.GetQueueStats(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats["failed"])
+	assert.Equal(t, int64(1), stats["completed"])
+}
+
+func TestRedisQueue_EditDeadLetterPayloadBeforeReplay(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	jobID, err := q.Enqueue(ctx, "bulk_search", map[string]string{"origin": "XXX"})
+	require.NoError(t, err)
+	failUntilDead(t, q, ctx, "bulk_search", queue.Failure{Error: "unknown airport", ErrorClass: "error"})
+
+	_, err = q.UpdateDeadLetterPayload(ctx, "bulk_search", jobID, []byte(`{"origin":`))
+	assert.ErrorIs(t, err, queue.ErrInvalidPayload)
+	_, err = q.UpdateDeadLetterPayload(ctx, "bulk_search", "missing", []byte(`{}`))
+	assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+
+	dl, err := q.UpdateDeadLetterPayload(ctx, "bulk_search", jobID, []byte(`{"origin":"JFK"}`))
+	require.NoError(t, err)
+	require.NotNil(t, dl.PayloadEditedAt)
+
+	all, err := q.ListDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 1, "an edit replaces the dead letter")
+	assert.JSONEq(t, `{"origin":"JFK"}`, string(all[0].Job.Payload))
+
+	// RetryFailed replays the edited payload too.
+	retried, err := q.RetryFailed(ctx, "bulk_search", 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), retried)
+
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.JSONEq(t, `{"origin":"JFK"}`, string(job.Payload))
+
+	all, err = q.ListDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{}, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestRedisQueue_ClearFailedDropsDeadLetters(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	jobID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+	require.NoError(t, err)
+	failUntilDead(t, q, ctx, "bulk_search", queue.Failure{Error: "boom"})
+
+	cleared, err := q.ClearFailed(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), cleared)
+
+	_, err = q.GetDeadLetter(ctx, "bulk_search", jobID)
+	assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+	replayed, err := q.ReplayDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{JobIDs: []string{jobID}}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, replayed)
+}
+
+func TestRedisQueue_ReplayKeepsDeadLetterWhenEnqueueFails(t *testing.T) {
+	mr, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	jobID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+	require.NoError(t, err)
+	failUntilDead(t, q, ctx, "bulk_search", queue.Failure{Error: "boom"})
+
+	// Break the lane, so that adding the job to it fails.
+	mr.Del("test_stream:bulk_search")
+	require.NoError(t, mr.Set("test_stream:bulk_search", "not a stream"))
+	_, err = q.ReplayDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{JobIDs: []string{jobID}}, 10)
+	require.Error(t, err)
+	_, err = q.GetDeadLetter(ctx, "bulk_search", jobID)
+	require.NoError(t, err, "the dead letter survives the failed replay")
+
+	mr.Del("test_stream:bulk_search")
+	replayed, err := q.ReplayDeadLetters(ctx, "bulk_search", queue.DeadLetterFilter{JobIDs: []string{jobID}}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{jobID}, replayed)
+	_, err = q.GetDeadLetter(ctx, "bulk_search", jobID)
+	assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, jobID, job.ID)
+}
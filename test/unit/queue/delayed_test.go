@@ -0,0 +1,183 @@
+package queue_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryingRedisQueue(t *testing.T, base, max time.Duration) (*miniredis.Miniredis, *queue.RedisQueue) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, port, ok := strings.Cut(mr.Addr(), ":")
+	require.True(t, ok)
+
+	q, err := queue.NewRedisQueue(config.RedisConfig{
+		Host:                   host,
+		Port:                   port,
+		QueueGroup:             "test_group",
+		QueueStreamPrefix:      "test_stream",
+		QueueBlockTimeout:      50 * time.Millisecond,
+		QueueVisibilityTimeout: 50 * time.Millisecond,
+		QueueRetryBaseDelay:    base,
+		QueueRetryMaxDelay:     max,
+	})
+	require.NoError(t, err)
+
+	return mr, q
+}
+
+func TestRedisQueue_EnqueueAfterIsPromotedWhenDue(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	// An empty array and a large integer, which a decode and re-encode of the job would change
+	payload := `{"origin":"JFK","carriers":[],"sweep_id":12345678901234567}`
+	jobID, err := q.EnqueueAfter(ctx, "bulk_search", json.RawMessage(payload), 100*time.Millisecond)
+	require.NoError(t, err)
+
+	status, err := q.GetJobStatus(ctx, jobID)
+	require.NoError(t, err)
+	assert.Equal(t, "scheduled", status)
+
+	stats, err := q.GetQueueStats(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats["scheduled"])
+	assert.Equal(t, int64(0), stats["pending"])
+
+	scheduled, err := q.ListJobs(ctx, "bulk_search", "scheduled", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, scheduled, 1)
+	assert.Equal(t, jobID, scheduled[0].ID)
+	require.NotNil(t, scheduled[0].RunAt)
+
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Nil(t, job, "scheduled jobs must not be delivered early")
+
+	promoted, err := q.PromoteDue(ctx, []string{"bulk_search"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), promoted)
+
+	time.Sleep(120 * time.Millisecond)
+	promoted, err = q.PromoteDue(ctx, []string{"bulk_search"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), promoted)
+
+	promoted, err = q.PromoteDue(ctx, []string{"bulk_search"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), promoted, "a job is promoted once")
+
+	stored, err := q.GetJob(ctx, jobID)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", stored.Status)
+	assert.NotEmpty(t, stored.StreamID, "the stored job should know its stream entry")
+	assert.Equal(t, payload, string(stored.Payload), "the payload should be stored byte for byte")
+
+	job, err = q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, jobID, job.ID)
+	assert.NotEmpty(t, job.StreamID)
+	assert.Equal(t, payload, string(job.Payload))
+	require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+
+	stats, err = q.GetQueueStats(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats["scheduled"])
+	assert.Equal(t, int64(1), stats["completed"])
+}
+
+func TestRedisQueue_EnqueueAtInThePastIsImmediate(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	jobID, err := q.EnqueueAt(ctx, "bulk_search", map[string]int{"n": 1}, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, jobID, job.ID)
+}
+
+func TestRedisQueue_NackBacksOff(t *testing.T) {
+	_, q := newRetryingRedisQueue(t, 200*time.Millisecond, time.Second)
+	ctx := context.Background()
+
+	jobID, err := q.Enqueue(queue.WithPriority(ctx, queue.PriorityHigh), "bulk_search", map[string]int{"n": 1})
+	require.NoError(t, err)
+
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+
+	before := time.Now()
+	require.NoError(t, q.Nack(ctx, "bulk_search", jobID))
+
+	retry, err := q.GetJob(ctx, jobID)
+	require.NoError(t, err)
+	assert.Equal(t, "scheduled", retry.Status)
+	require.NotNil(t, retry.RunAt)
+	// The first retry waits between half and all of the base delay.
+	assert.WithinRange(t, *retry.RunAt, before.Add(100*time.Millisecond), time.Now().Add(200*time.Millisecond))
+
+	stats, err := q.GetQueueStats(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats["scheduled"])
+	assert.Equal(t, int64(0), stats["processing"])
+
+	job, err = q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	time.Sleep(time.Until(*retry.RunAt) + 10*time.Millisecond)
+	promoted, err := q.PromoteDue(ctx, []string{"bulk_search"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), promoted)
+
+	job, err = q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, jobID, job.ID)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, queue.PriorityHigh, job.Priority, "retries keep their lane")
+
+	// The second retry waits between half and all of twice the base delay.
+	before = time.Now()
+	require.NoError(t, q.Nack(ctx, "bulk_search", jobID))
+	retry, err = q.GetJob(ctx, jobID)
+	require.NoError(t, err)
+	assert.WithinRange(t, *retry.RunAt, before.Add(200*time.Millisecond), time.Now().Add(400*time.Millisecond))
+}
+
+func TestRedisQueue_ClearQueueDropsScheduled(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	scheduledID, err := q.EnqueueAfter(ctx, "bulk_search", map[string]int{"n": 1}, time.Hour)
+	require.NoError(t, err)
+	_, err = q.Enqueue(ctx, "bulk_search", map[string]int{"n": 2})
+	require.NoError(t, err)
+
+	cleared, err := q.ClearQueue(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), cleared)
+
+	stats, err := q.GetQueueStats(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats["scheduled"])
+	assert.Equal(t, int64(0), stats["pending"])
+
+	_, err = q.GetJob(ctx, scheduledID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package queue_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type routePayload struct {
+	Origin      string `json:"origin"`
+	Destination string `json:"destination"`
+}
+
+func (p routePayload) IdempotencyKey() string {
+	return p.Origin + "-" + p.Destination
+}
+
+func newDedupRedisQueue(t *testing.T, window time.Duration) (*miniredis.Miniredis, *queue.RedisQueue) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, port, ok := strings.Cut(mr.Addr(), ":")
+	require.True(t, ok)
+
+	q, err := queue.NewRedisQueue(config.RedisConfig{
+		Host:                   host,
+		Port:                   port,
+		QueueGroup:             "test_group",
+		QueueStreamPrefix:      "test_stream",
+		QueueBlockTimeout:      50 * time.Millisecond,
+		QueueVisibilityTimeout: 50 * time.Millisecond,
+		QueueDedupWindow:       window,
+	})
+	require.NoError(t, err)
+
+	return mr, q
+}
+
+func TestRedisQueue_DuplicatePayloadsCollapse(t *testing.T) {
+	mr, q := newDedupRedisQueue(t, time.Minute)
+	ctx := context.Background()
+
+	firstID, err := q.Enqueue(ctx, "continuous_price_graph", routePayload{Origin: "JFK", Destination: "LHR"})
+	require.NoError(t, err)
+	dupID, err := q.Enqueue(ctx, "continuous_price_graph", routePayload{Origin: "JFK", Destination: "LHR"})
+	require.NoError(t, err)
+	assert.Equal(t, firstID, dupID)
+
+	otherID, err := q.Enqueue(ctx, "continuous_price_graph", routePayload{Origin: "JFK", Destination: "CDG"})
+	require.NoError(t, err)
+	assert.NotEqual(t, firstID, otherID)
+
+	// Keys are per job type and tenant.
+	searchID, err := q.Enqueue(ctx, "flight_search", routePayload{Origin: "JFK", Destination: "LHR"})
+	require.NoError(t, err)
+	assert.NotEqual(t, firstID, searchID)
+	tenantID, err := q.Enqueue(queue.WithTenant(ctx, "acme"), "continuous_price_graph", routePayload{Origin: "JFK", Destination: "LHR"})
+	require.NoError(t, err)
+	assert.NotEqual(t, firstID, tenantID)
+
+	stats, err := q.GetQueueStats(ctx, "continuous_price_graph")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats["pending"])
+
+	job, err := q.GetJob(ctx, firstID)
+	require.NoError(t, err)
+	assert.Equal(t, "JFK-LHR", job.IdempotencyKey)
+
+	// Completed jobs still collapse duplicates until the window is over.
+	job, err = q.Dequeue(ctx, "continuous_price_graph")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	require.NoError(t, q.Ack(ctx, "continuous_price_graph", job.ID))
+	dupID, err = q.Enqueue(ctx, "continuous_price_graph", routePayload{Origin: "JFK", Destination: "LHR"})
+	require.NoError(t, err)
+	assert.Equal(t, firstID, dupID)
+
+	mr.FastForward(time.Minute + time.Second)
+	laterID, err := q.Enqueue(ctx, "continuous_price_graph", routePayload{Origin: "JFK", Destination: "LHR"})
+	require.NoError(t, err)
+	assert.NotEqual(t, firstID, laterID)
+}
+
+func TestRedisQueue_IdempotencyKeyFromContext(t *testing.T) {
+	_, q := newDedupRedisQueue(t, time.Minute)
+	ctx := queue.WithIdempotencyKey(context.Background(), "request-42")
+
+	firstID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+	require.NoError(t, err)
+	dupID, err := q.EnqueueAfter(ctx, "bulk_search", map[string]int{"n": 2}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, firstID, dupID)
+
+	// Payloads without a key are never collapsed.
+	a, err := q.Enqueue(context.Background(), "bulk_search", map[string]int{"n": 1})
+	require.NoError(t, err)
+	b, err := q.Enqueue(context.Background(), "bulk_search", map[string]int{"n": 1})
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+}
+
+func TestRedisQueue_FailedJobReleasesIdempotencyKey(t *testing.T) {
+	_, q := newDedupRedisQueue(t, time.Minute)
+	ctx := context.Background()
+	payload := routePayload{Origin: "JFK", Destination: "LHR"}
+
+	firstID, err := q.Enqueue(ctx, "bulk_search_route", payload)
+	require.NoError(t, err)
+	failUntilDead(t, q, ctx, "bulk_search_route", queue.Failure{Error: "boom"})
+
+	retryID, err := q.Enqueue(ctx, "bulk_search_route", payload)
+	require.NoError(t, err)
+	assert.NotEqual(t, firstID, retryID)
+
+	dupID, err := q.Enqueue(ctx, "bulk_search_route", payload)
+	require.NoError(t, err)
+	assert.Equal(t, retryID, dupID)
+}
+
+func TestRedisQueue_DedupDisabled(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	a, err := q.Enqueue(ctx, "continuous_price_graph", routePayload{Origin: "JFK", Destination: "LHR"})
+	require.NoError(t, err)
+	b, err := q.Enqueue(ctx, "continuous_price_graph", routePayload{Origin: "JFK", Destination: "LHR"})
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+}
+
+func TestIdempotencyKeyContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", queue.IdempotencyKeyFromContext(ctx))
+	assert.Equal(t, "abc", queue.IdempotencyKeyFromContext(queue.WithIdempotencyKey(ctx, " abc ")))
+	assert.Equal(t, "", queue.IdempotencyKeyFromContext(queue.WithIdempotencyKey(ctx, strings.Repeat("k", 256))))
+}
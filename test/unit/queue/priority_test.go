@@ -0,0 +1,220 @@
+package queue_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func enqueueN(t *testing.T, q *queue.RedisQueue, ctx context.Context, jobType string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		_, err := q.Enqueue(ctx, jobType, map[string]int{"n": i})
+		require.NoError(t, err)
+	}
+}
+
+func TestRedisQueue_PriorityLanesShareByWeight(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+	queues := []string{"flight_search", "continuous_price_graph"}
+
+	enqueueN(t, q, ctx, "continuous_price_graph", 18)
+	enqueueN(t, q, ctx, "flight_search", 18)
+
+	var order []string
+	for i := 0; i < 36; i++ {
+		job, err := q.DequeueFair(ctx, queues)
+		require.NoError(t, err)
+		require.NotNil(t, job, "dequeue %d", i)
+		order = append(order, job.Type)
+		require.NoError(t, q.Ack(ctx, job.Type, job.ID))
+	}
+
+	// With the default 8:1 weights the low lane gets one of every nine turns while both have work.
+	low := 0
+	for _, jobType := range order[:18] {
+		if jobType == "continuous_price_graph" {
+			low++
+		}
+	}
+	assert.Equal(t, "flight_search", order[0])
+	assert.GreaterOrEqual(t, low, 1, "background jobs must not be starved: %v", order)
+	assert.LessOrEqual(t, low, 3, "background jobs must not crowd out searches: %v", order)
+
+	job, err := q.DequeueFair(ctx, queues)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestRedisQueue_PriorityFromContext(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	_, err := q.Enqueue(ctx, "bulk_search", map[string]string{"lane": "normal"})
+	require.NoError(t, err)
+	highID, err := q.Enqueue(queue.WithPriority(ctx, queue.PriorityHigh), "bulk_search", map[string]string{"lane": "high"})
+	require.NoError(t, err)
+
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, highID, job.ID)
+	assert.Equal(t, queue.PriorityHigh, job.Priority)
+
+	// A nacked job is retried in its own lane.
+	require.NoError(t, q.Nack(ctx, "bulk_search", job.ID))
+	backlog, err := q.GetBacklog(ctx, "bulk_search", 10)
+	require.NoError(t, err)
+	require.Len(t, backlog, 2)
+	assert.Equal(t, highID, backlog[0].ID)
+	assert.Equal(t, queue.PriorityHigh, backlog[0].Priority)
+	assert.Equal(t, queue.PriorityNormal, backlog[1].Priority)
+
+	attempts := map[queue.Priority]int{}
+	for i := 0; i < 2; i++ {
+		job, err = q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		attempts[job.Priority] = job.Attempts
+		require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+	}
+	assert.Equal(t, map[queue.Priority]int{queue.PriorityHigh: 2, queue.PriorityNormal: 1}, attempts)
+
+	stats, err := q.GetQueueStats(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats["pending"])
+	assert.Equal(t, int64(2), stats["completed"])
+}
+
+func TestRedisQueue_TenantsTakeTurns(t *testing.T) {
+	_, q := newTestRedisQueue(t)
+	ctx := context.Background()
+
+	enqueueN(t, q, queue.WithTenant(ctx, "acme"), "bulk_search", 4)
+	enqueueN(t, q, queue.WithTenant(ctx, "globex"), "bulk_search", 2)
+
+	var tenants []string
+	for i := 0; i < 6; i++ {
+		job, err := q.Dequeue(ctx, "bulk_search")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		tenants = append(tenants, job.Tenant)
+		require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+	}
+	assert.Equal(t, []string{"acme", "globex", "acme", "globex", "acme", "acme"}, tenants)
+
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+// redisQueueOn creates another queue on the Redis of mr, like a second process of the fleet.
+func redisQueueOn(t *testing.T, mr *miniredis.Miniredis, blockTimeout time.Duration) *queue.RedisQueue {
+	t.Helper()
+	host, port, ok := strings.Cut(mr.Addr(), ":")
+	require.True(t, ok)
+	q, err := queue.NewRedisQueue(config.RedisConfig{
+		Host:                   host,
+		Port:                   port,
+		QueueGroup:             "test_group",
+		QueueStreamPrefix:      "test_stream",
+		QueueBlockTimeout:      blockTimeout,
+		QueueVisibilityTimeout: time.Minute,
+	})
+	require.NoError(t, err)
+	return q
+}
+
+func TestRedisQueue_IdleTenantLanesAreDeleted(t *testing.T) {
+	mr, q := newTestRedisQueue(t)
+	other := redisQueueOn(t, mr, 50*time.Millisecond)
+	ctx := context.Background()
+	acme := queue.WithTenant(ctx, "acme")
+	stream := "test_stream:bulk_search:normal:tenant:acme"
+
+	enqueueN(t, other, acme, "bulk_search", 1)
+	job, err := q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+
+	job, err = q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	assert.Nil(t, job)
+	assert.False(t, mr.Exists(stream), "the idle tenant stream is deleted")
+	members, _ := mr.SMembers("queue:bulk_search:tenants")
+	assert.NotContains(t, members, "normal:acme")
+
+	// The other process still believes the stream has its consumer group.
+	enqueueN(t, other, acme, "bulk_search", 1)
+	job, err = q.Dequeue(ctx, "bulk_search")
+	require.NoError(t, err)
+	require.NotNil(t, job, "the recreated lane is read")
+	assert.Equal(t, "acme", job.Tenant)
+	require.NoError(t, q.Ack(ctx, "bulk_search", job.ID))
+}
+
+func TestRedisQueue_BlockingDequeueLeavesOtherEntriesInPlace(t *testing.T) {
+	mr, _ := newTestRedisQueue(t)
+	q := redisQueueOn(t, mr, 2*time.Second)
+	ctx := context.Background()
+	queues := []string{"bulk_search", "flight_search"}
+
+	dequeued := make(chan *queue.Job, 1)
+	go func() {
+		job, err := q.DequeueFair(ctx, queues)
+		assert.NoError(t, err)
+		dequeued <- job
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	firstID, err := q.Enqueue(ctx, "bulk_search", map[string]int{"n": 1})
+	require.NoError(t, err)
+	secondID, err := q.Enqueue(ctx, "flight_search", map[string]int{"n": 2})
+	require.NoError(t, err)
+	second, err := q.GetJob(ctx, secondID)
+	require.NoError(t, err)
+
+	job := <-dequeued
+	require.NotNil(t, job)
+	assert.Equal(t, firstID, job.ID)
+
+	// The entry of the second job wasn't read and moved to the tail of its lane.
+	after, err := q.GetJob(ctx, secondID)
+	require.NoError(t, err)
+	assert.Equal(t, second.StreamID, after.StreamID)
+	assert.Equal(t, "pending", after.Status)
+	job, err = q.DequeueFair(ctx, queues)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, secondID, job.ID)
+	assert.Equal(t, 1, job.Attempts)
+}
+
+func TestPriorityContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, queue.Priority(""), queue.PriorityFromContext(ctx))
+	assert.Equal(t, queue.PriorityLow, queue.PriorityFromContext(queue.WithPriority(ctx, queue.PriorityLow)))
+	assert.Equal(t, queue.Priority(""), queue.PriorityFromContext(queue.WithPriority(ctx, "urgent")))
+
+	assert.Equal(t, "team-1", queue.TenantFromContext(queue.WithTenant(ctx, " team-1 ")))
+	assert.Equal(t, "", queue.TenantFromContext(queue.WithTenant(ctx, "a:b")))
+
+	p, err := queue.ParsePriority("HIGH")
+	require.NoError(t, err)
+	assert.Equal(t, queue.PriorityHigh, p)
+	_, err = queue.ParsePriority("urgent")
+	assert.Error(t, err)
+
+	assert.Equal(t, queue.PriorityHigh, queue.DefaultPriority("flight_search"))
+	assert.Equal(t, queue.PriorityNormal, queue.DefaultPriority("bulk_search"))
+	assert.Equal(t, queue.PriorityLow, queue.DefaultPriority("continuous_price_graph"))
+}
@@ -454,7 +454,7 @@ func TestManager_JobPrioritization(t *testing.T) {
 	mockPgDb.On("BeginTx", mock.Anything).Return(mockTxFlight, nil).Once() // Expect BeginTx once for flight job
 	// Mock QueryRowContext + Scan for flightJob
 	mockScannerFlightInsertQuery := new(mocks.MockQueryRowScanner)
-	mockTxFlight.On("QueryRowContext", mock.Anything, mock.MatchedBy(func(query string) bool { return strings.Contains(query, "INSERT INTO search_queries") }), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerFlightInsertQuery).Once()
+	mockTxFlight.On("QueryRowContext", mock.Anything, mock.MatchedBy(func(query string) bool { return strings.Contains(query, "INSERT INTO search_queries") }), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerFlightInsertQuery).Once()
 	mockScannerFlightInsertQuery.On("Scan", mock.AnythingOfType("*int")).Return(nil).Run(func(args mock.Arguments) { *args.Get(0).(*int) = 1 }).Once()
 
 	mockScannerFlightGetSearchID := new(mocks.MockQueryRowScanner)
@@ -463,7 +463,7 @@ func TestManager_JobPrioritization(t *testing.T) {
 	mockScannerFlightGetSearchID.On("Scan", mock.AnythingOfType("*string")).Return(nil).Run(func(args mock.Arguments) { *args.Get(0).(*string) = "flight-uuid" }).Maybe()
 
 	mockScannerFlightInsertOffer := new(mocks.MockQueryRowScanner)
-	mockTxFlight.On("QueryRowContext", mock.Anything, mock.MatchedBy(func(query string) bool { return strings.Contains(query, "INSERT INTO flight_offers") }), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerFlightInsertOffer).Maybe()
+	mockTxFlight.On("QueryRowContext", mock.Anything, mock.MatchedBy(func(query string) bool { return strings.Contains(query, "INSERT INTO flight_offers") }), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerFlightInsertOffer).Maybe()
 	mockScannerFlightInsertOffer.On("Scan", mock.AnythingOfType("*int")).Return(nil).Run(func(args mock.Arguments) { *args.Get(0).(*int) = 10 }).Maybe()
 
 	// Mock ExecContext calls for flightJob - Use Maybe() as they are inside loop
@@ -483,7 +483,7 @@ func TestManager_JobPrioritization(t *testing.T) {
 	mockPgDb.On("BeginTx", mock.Anything).Return(mockTxBulk, nil).Once() // Expect BeginTx once for bulk job (or more if it iterates)
 	// Mock QueryRowContext + Scan for bulkJob
 	mockScannerBulkInsertQuery := new(mocks.MockQueryRowScanner)
-	mockTxBulk.On("QueryRowContext", mock.Anything, mock.MatchedBy(func(query string) bool { return strings.Contains(query, "INSERT INTO search_queries") }), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerBulkInsertQuery).Once()
+	mockTxBulk.On("QueryRowContext", mock.Anything, mock.MatchedBy(func(query string) bool { return strings.Contains(query, "INSERT INTO search_queries") }), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerBulkInsertQuery).Once()
 	mockScannerBulkInsertQuery.On("Scan", mock.AnythingOfType("*int")).Return(nil).Run(func(args mock.Arguments) { *args.Get(0).(*int) = 2 }).Once()
 
 	mockScannerBulkGetSearchID := new(mocks.MockQueryRowScanner)
@@ -492,7 +492,7 @@ func TestManager_JobPrioritization(t *testing.T) {
 	mockScannerBulkGetSearchID.On("Scan", mock.AnythingOfType("*string")).Return(nil).Run(func(args mock.Arguments) { *args.Get(0).(*string) = "bulk-uuid" }).Maybe()
 
 	mockScannerBulkInsertOffer := new(mocks.MockQueryRowScanner)
-	mockTxBulk.On("QueryRowContext", mock.Anything, mock.MatchedBy(func(query string) bool { return strings.Contains(query, "INSERT INTO flight_offers") }), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerBulkInsertOffer).Maybe()
+	mockTxBulk.On("QueryRowContext", mock.Anything, mock.MatchedBy(func(query string) bool { return strings.Contains(query, "INSERT INTO flight_offers") }), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerBulkInsertOffer).Maybe()
 	mockScannerBulkInsertOffer.On("Scan", mock.AnythingOfType("*int")).Return(nil).Run(func(args mock.Arguments) { *args.Get(0).(*int) = 11 }).Maybe()
 
 	// Mock ExecContext calls for bulkJob - Use Maybe() as they are inside loop
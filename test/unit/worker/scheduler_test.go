@@ -120,11 +120,8 @@ func TestScheduler_AddJob_Success(t *testing.T) {
 	expectedQueueName := "scheduled_jobs"
 	expectedJobID := "mockJobID" // Mock Enqueue will return this
 
-	// Expect Enqueue to be called with the correct queue name and marshaled job data
-	mockQueue.On("Enqueue", mock.Anything, expectedQueueName, mock.MatchedBy(func(jobBytes []byte) bool {
-		var job queue.Job
-		err := json.Unmarshal(jobBytes, &job)
-		assert.NoError(t, err)
+	// Expect Enqueue to be called with the correct queue name and the job
+	mockQueue.On("Enqueue", mock.Anything, expectedQueueName, mock.MatchedBy(func(job queue.Job) bool {
 		assert.Equal(t, "scheduled_job", job.Type)
 		assert.Equal(t, json.RawMessage(payload), job.Payload)
 		assert.NotEmpty(t, job.ID) // ID is generated internally
@@ -150,7 +147,7 @@ func TestScheduler_AddJob_EnqueueError(t *testing.T) {
 	expectedQueueName := "scheduled_jobs"
 
 	// Expect Enqueue to be called and return an error
-	mockQueue.On("Enqueue", mock.Anything, expectedQueueName, mock.AnythingOfType("[]uint8")).Return("", expectedError).Once()
+	mockQueue.On("Enqueue", mock.Anything, expectedQueueName, mock.AnythingOfType("queue.Job")).Return("", expectedError).Once()
 
 	scheduler := worker.NewScheduler(mockQueue, mockDB, mockCron)
 	err := scheduler.AddJob(payload)
@@ -5,14 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/gilby125/google-flights-api/db"
 	"github.com/gilby125/google-flights-api/flights"
 	"github.com/gilby125/google-flights-api/test/mocks"
 	"github.com/gilby125/google-flights-api/worker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var runWorkerCoreTests = os.Getenv("ENABLE_WORKER_TESTS") == "1"
@@ -162,7 +165,7 @@ func TestWorker_StoreFlightOffers_Success(t *testing.T) {
 
 	// Mock Search Query Insert
 	mockScannerInsertQuery := new(mocks.MockQueryRowScanner)
-	mockTx.On("QueryRowContext", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerInsertQuery).Once()
+	mockTx.On("QueryRowContext", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerInsertQuery).Once()
 	mockScannerInsertQuery.On("Scan", mock.AnythingOfType("*int")).Return(nil).Run(func(args mock.Arguments) { *args.Get(0).(*int) = 1 }).Once() // Simulate query ID 1
 
 	// Mock Get Search ID
@@ -172,7 +175,7 @@ func TestWorker_StoreFlightOffers_Success(t *testing.T) {
 
 	// Mock Offer Insert
 	mockScannerInsertOffer := new(mocks.MockQueryRowScanner)
-	mockTx.On("QueryRowContext", mock.Anything, mock.AnythingOfType("string"), 1, "test-uuid", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerInsertOffer).Once()
+	mockTx.On("QueryRowContext", mock.Anything, mock.AnythingOfType("string"), 1, "test-uuid", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockScannerInsertOffer).Once()
 	mockScannerInsertOffer.On("Scan", mock.AnythingOfType("*int")).Return(nil).Run(func(args mock.Arguments) { *args.Get(0).(*int) = 10 }).Once() // Simulate offer ID 10
 
 	// FIX: Correct ExecContext mock expectations for variadic args
@@ -260,3 +263,109 @@ func TestWorker_StoreFlightOffers_Success(t *testing.T) {
 // Add more tests for StoreFlightOffers error paths (BeginTx error, Insert errors, Commit error, etc.)
 
 // Add tests for processPriceGraphSearch if needed
+
+// recordingTx is a db.Tx that records the statements run in it. Every row it returns has ID 1.
+type recordingTx struct {
+	execs [][]interface{}
+	rows  [][]interface{}
+}
+
+func (tx *recordingTx) Commit() error   { return nil }
+func (tx *recordingTx) Rollback() error { return nil }
+
+func (tx *recordingTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	tx.execs = append(tx.execs, append([]interface{}{query}, args...))
+	return nil, nil
+}
+
+func (tx *recordingTx) QueryRowContext(ctx context.Context, query string, args ...any) db.RowScanner {
+	tx.rows = append(tx.rows, append([]interface{}{query}, args...))
+	return idScanner{}
+}
+
+type idScanner struct{}
+
+func (idScanner) Scan(dest ...interface{}) error {
+	*dest[0].(*int) = 1
+	return nil
+}
+
+// insertedSegments returns the args of the flight_segments inserts run in tx.
+func (tx *recordingTx) insertedSegments() [][]interface{} {
+	var segments [][]interface{}
+	for _, exec := range tx.execs {
+		if strings.Contains(exec[0].(string), "INSERT INTO flight_segments") {
+			segments = append(segments, exec[1:])
+		}
+	}
+	return segments
+}
+
+func TestWorker_StoreFlightOffers_StoresReturnLegs(t *testing.T) {
+	mockPgDb := new(mocks.MockPostgresDB)
+	workerInstance := worker.NewWorker(mockPgDb, nil)
+	tx := &recordingTx{}
+	mockPgDb.On("BeginTx", mock.Anything).Return(tx, nil).Once()
+
+	depart := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+	leg := func(number, from, to string, dep time.Time, d time.Duration) flights.Flight {
+		return flights.Flight{
+			DepAirportCode: from, ArrAirportCode: to, AirlineName: "TestAir", FlightNumber: number,
+			DepTime: dep, ArrTime: dep.Add(d), Duration: d,
+		}
+	}
+	offers := []flights.FullOffer{{
+		Offer:                flights.Offer{StartDate: depart, ReturnDate: depart.AddDate(0, 0, 7), Price: 640},
+		SrcAirportCode:       "LHR",
+		DstAirportCode:       "JFK",
+		FlightDuration:       8 * time.Hour,
+		Flight:               []flights.Flight{leg("TA100", "LHR", "JFK", depart, 8*time.Hour)},
+		ReturnFlightDuration: 11 * time.Hour,
+		ReturnFlight: []flights.Flight{
+			leg("TA200", "JFK", "BOS", depart.AddDate(0, 0, 7), 90*time.Minute),
+			leg("TA201", "BOS", "LHR", depart.AddDate(0, 0, 7).Add(3*time.Hour), 7*time.Hour),
+		},
+	}}
+
+	err := workerInstance.StoreFlightOffers(context.Background(), worker.FlightSearchPayload{
+		Origin: "LHR", Destination: "JFK", DepartureDate: depart, ReturnDate: depart.AddDate(0, 0, 7),
+		Adults: 1, TripType: "round_trip", Class: "economy", Currency: "USD", Country: "de", GoogleHost: "www.google.de",
+	}, offers, nil)
+	require.NoError(t, err)
+	mockPgDb.AssertExpectations(t)
+
+	// Offer insert: search_id, price, currency, airline_codes, outbound_duration, outbound_stops, return_duration, return_stops, country
+	var offerArgs []interface{}
+	for _, row := range tx.rows {
+		if strings.Contains(row[0].(string), "INSERT INTO flight_offers") {
+			offerArgs = row[1:]
+		}
+	}
+	require.Len(t, offerArgs, 9)
+	assert.Equal(t, 480, offerArgs[4])
+	assert.Equal(t, 0, offerArgs[5])
+	assert.Equal(t, sql.NullInt32{Int32: 660, Valid: true}, offerArgs[6])
+	assert.Equal(t, sql.NullInt32{Int32: 1, Valid: true}, offerArgs[7])
+	assert.Equal(t, "DE", offerArgs[8])
+
+	// Query insert: ..., status, country, google_host
+	for _, row := range tx.rows {
+		if strings.Contains(row[0].(string), "INSERT INTO search_queries") {
+			assert.Equal(t, "DE", row[13])
+			assert.Equal(t, sql.NullString{String: "www.google.de", Valid: true}, row[14])
+		}
+	}
+
+	// Segment insert: offer ID, airline code, flight number, departure airport, ..., is_return (10), segment_index
+	segments := tx.insertedSegments()
+	require.Len(t, segments, 3)
+	var numbers []string
+	for _, segment := range segments {
+		numbers = append(numbers, segment[2].(string))
+	}
+	assert.Equal(t, []string{"TA100", "TA200", "TA201"}, numbers)
+	assert.Equal(t, false, segments[0][10])
+	assert.Equal(t, true, segments[1][10])
+	assert.Equal(t, true, segments[2][10])
+	assert.Equal(t, "BOS", segments[2][3])
+}
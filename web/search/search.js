@@ -64,6 +64,9 @@ const elements = {
   recurringOptions: document.getElementById("recurringOptions"),
   createRecurringBtn: document.getElementById("createRecurringBtn"),
   recurringStatus: document.getElementById("recurringStatus"),
+  multiCityOptions: document.getElementById("multiCityOptions"),
+  multiCitySegments: document.getElementById("multiCitySegments"),
+  addMultiCitySegmentBtn: document.getElementById("addMultiCitySegmentBtn"),
   loadingIndicatorText: document.getElementById("loadingIndicatorText"),
   loadingIndicatorMeta: document.getElementById("loadingIndicatorMeta"),
 };
@@ -119,6 +122,9 @@ let advancedOptionsVisible =
   getComputedStyle(elements.advancedOptions).display !== "none";
 let activeBulkSearch = null;
 
+const MIN_MULTI_CITY_SEGMENTS = 2;
+const MAX_MULTI_CITY_SEGMENTS = 6;
+
 const ACTIVE_BULK_SEARCH_STORAGE_KEY = "flight-search-active-bulk-search-v1";
 const ACTIVE_BULK_SEARCH_STORAGE_TTL_MS = 24 * 60 * 60 * 1000; // 24 hours
 
@@ -539,7 +545,7 @@ function splitAirportList(raw) {
     let token = normalizeAirportToken(part);
     if (!token) continue;
 
-    if (!/^[A-Z0-9]{3}$/.test(token)) {
+    if (!/^[A-Z0-9]{3}$/.test(token) && !token.startsWith("NEAR:")) {
       const regionToken = canonicalizeRegionToken(token);
       if (!regionToken) continue;
       token = regionToken;
@@ -650,6 +656,16 @@ function initSearchPage() {
     elements.searchForm.addEventListener("submit", handleSearch);
   }
 
+  if (inputs.tripType) {
+    inputs.tripType.addEventListener("change", setTripTypeFieldVisibility);
+  }
+  if (elements.addMultiCitySegmentBtn) {
+    elements.addMultiCitySegmentBtn.addEventListener("click", () =>
+      addMultiCitySegmentRow(),
+    );
+  }
+  setTripTypeFieldVisibility();
+
   if (elements.recurringBtn && elements.recurringOptions) {
     elements.recurringBtn.addEventListener("click", () => {
       const visible =
@@ -725,13 +741,96 @@ function applyPrefillFromUrl() {
   if (inputs.destination && destination) inputs.destination.value = destination;
   if (inputs.departureDate && departureDate) inputs.departureDate.value = departureDate;
   if (inputs.returnDate && returnDate) inputs.returnDate.value = returnDate;
-  if (inputs.tripType && (tripType === "one_way" || tripType === "round_trip")) inputs.tripType.value = tripType;
+  if (inputs.tripType && (tripType === "one_way" || tripType === "round_trip" || tripType === "multi_city")) inputs.tripType.value = tripType;
   if (inputs.travelClass && cabin) inputs.travelClass.value = cabin;
   if (inputs.stops && stops) inputs.stops.value = stops;
   if (inputs.adults && adults && /^\d+$/.test(adults)) inputs.adults.value = adults;
   if (inputs.currency && currency && currency.length === 3) inputs.currency.value = currency.toUpperCase();
 }
 
+function setTripTypeFieldVisibility() {
+  const multiCity = inputs.tripType?.value === "multi_city";
+  document.querySelectorAll(".singleTripField").forEach((el) => {
+    el.style.display = multiCity ? "none" : "block";
+  });
+  if (elements.multiCityOptions) {
+    elements.multiCityOptions.style.display = multiCity ? "block" : "none";
+  }
+  if (multiCity && elements.multiCitySegments) {
+    while (
+      elements.multiCitySegments.children.length < MIN_MULTI_CITY_SEGMENTS
+    ) {
+      addMultiCitySegmentRow();
+    }
+  }
+}
+
+function addMultiCitySegmentRow() {
+  const container = elements.multiCitySegments;
+  if (!container) return;
+  const rows = container.querySelectorAll(".multiCitySegment");
+  if (rows.length >= MAX_MULTI_CITY_SEGMENTS) {
+    showAlert(`Multi-city trips support up to ${MAX_MULTI_CITY_SEGMENTS} flights.`, "warning");
+    return;
+  }
+
+  // Each flight starts where the previous one landed, a day later, unless edited.
+  const previous = rows.length ? rows[rows.length - 1] : null;
+  const previousDestination = previous?.querySelector(".segmentDestination")?.value || "";
+  const previousDate = previous?.querySelector(".segmentDate")?.value || "";
+
+  const row = document.createElement("div");
+  row.className = "multiCitySegment row g-2 align-items-end";
+  row.innerHTML = `
+        <div class="col-md-4">
+            <label class="form-label small mb-1">From</label>
+            <input type="text" class="form-control segmentOrigin" placeholder="e.g. JFK" list="originList">
+        </div>
+        <div class="col-md-4">
+            <label class="form-label small mb-1">To</label>
+            <input type="text" class="form-control segmentDestination" placeholder="e.g. LHR" list="destinationList">
+        </div>
+        <div class="col-md-3">
+            <label class="form-label small mb-1">Date</label>
+            <input type="date" class="form-control segmentDate">
+        </div>
+        <div class="col-md-1 text-end">
+            <button type="button" class="btn btn-outline-danger removeSegmentBtn" title="Remove flight">
+                <i class="bi bi-x-lg"></i>
+            </button>
+        </div>
+    `;
+
+  row.querySelector(".segmentOrigin").value = previousDestination;
+  const dateInput = row.querySelector(".segmentDate");
+  dateInput.min = new Date().toISOString().split("T")[0];
+  if (previousDate) dateInput.value = addDaysToDateString(previousDate, 1);
+  row.querySelector(".removeSegmentBtn").addEventListener("click", () => {
+    if (container.querySelectorAll(".multiCitySegment").length <= MIN_MULTI_CITY_SEGMENTS) {
+      showAlert(`Multi-city trips need at least ${MIN_MULTI_CITY_SEGMENTS} flights.`, "warning");
+      return;
+    }
+    row.remove();
+  });
+
+  container.appendChild(row);
+}
+
+function readMultiCitySegments() {
+  const rows = elements.multiCitySegments
+    ? Array.from(elements.multiCitySegments.querySelectorAll(".multiCitySegment"))
+    : [];
+  return rows.map((row, i) => {
+    const origin = normalizeAirportToken(row.querySelector(".segmentOrigin")?.value);
+    const destination = normalizeAirportToken(row.querySelector(".segmentDestination")?.value);
+    const departureDate = String(row.querySelector(".segmentDate")?.value || "").trim();
+    if (!origin || !destination || !departureDate) {
+      throw new Error(`Please fill in origin, destination and date for flight ${i + 1}.`);
+    }
+    return { origin, destination, departure_date: departureDate };
+  });
+}
+
 function setRecurringDynamicFieldVisibility(show) {
   document.querySelectorAll(".recurringDynamicField").forEach((el) => {
     el.style.display = show ? "block" : "none";
@@ -1396,6 +1495,11 @@ async function handleSearch(event) {
   if (elements.asyncBulkDiag) elements.asyncBulkDiag.textContent = "";
 
   try {
+    if (inputs.tripType?.value === "multi_city") {
+      await handleMultiCitySearch();
+      return;
+    }
+
     const originInput = inputs.origin;
     const destinationInput = inputs.destination;
     const departureInput = inputs.departureDate;
@@ -1595,6 +1699,75 @@ async function handleSearch(event) {
   }
 }
 
+// Multi-city searches send the flights as segments and skip the multi-route and
+// price graph handling of handleSearch.
+async function handleMultiCitySearch() {
+  const segments = readMultiCitySegments();
+  if (
+    segments.length < MIN_MULTI_CITY_SEGMENTS ||
+    segments.length > MAX_MULTI_CITY_SEGMENTS
+  ) {
+    throw new Error(
+      `Multi-city trips need between ${MIN_MULTI_CITY_SEGMENTS} and ${MAX_MULTI_CITY_SEGMENTS} flights.`,
+    );
+  }
+
+  let adults = parseInt(inputs.adults?.value || "1", 10);
+  let children = 0;
+  let infantsLap = 0;
+  let infantsSeat = 0;
+  let currency = "USD";
+  if (advancedOptionsVisible) {
+    children = parseInt(inputs.children?.value || "0", 10);
+    infantsLap = parseInt(inputs.infantsLap?.value || "0", 10);
+    infantsSeat = parseInt(inputs.infantsSeat?.value || "0", 10);
+    currency = (inputs.currency?.value || "USD").toUpperCase();
+  }
+
+  const searchData = {
+    trip_type: "multi_city",
+    segments,
+    class: inputs.travelClass ? inputs.travelClass.value : "economy",
+    stops: stopsToApiValue(inputs.stops ? inputs.stops.value : "any"),
+    adults,
+    children,
+    infants_lap: infantsLap,
+    infants_seat: infantsSeat,
+    currency,
+  };
+
+  const carrierTokens = parseCarrierTokens(inputs.googleCarriers?.value);
+  if (carrierTokens.length > 0) {
+    searchData.carriers = carrierTokens;
+  }
+
+  console.log("Sending multi-city search request:", searchData);
+
+  const response = await fetch(ENDPOINTS.SEARCH, {
+    method: "POST",
+    headers: {
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify(searchData),
+  });
+
+  if (!response.ok) {
+    let errorData = null;
+    try {
+      errorData = await response.json();
+    } catch {}
+    throw new Error(errorData?.error || `Search failed (${response.status})`);
+  }
+
+  const searchResult = await response.json();
+  console.log("Multi-city search results:", searchResult);
+
+  if (Array.isArray(searchResult?.warnings) && searchResult.warnings.length) {
+    showAlert(searchResult.warnings.join(" "), "warning");
+  }
+  displaySearchResults(searchResult);
+}
+
 function isDirectSearchTooLargeError(message) {
   const msg = String(message || "").toLowerCase();
   return (
@@ -3238,8 +3411,14 @@ function createFlightCard(offer, searchParamsOverride, options) {
       ? `${departureSegment.flight_number || ""} +${segments.length - 1} more`
       : departureSegment.flight_number || "";
 
-  const routeLabel =
-    departureSegment && arrivalSegment
+  const multiCitySegments = Array.isArray(offer.multi_city_segments)
+    ? offer.multi_city_segments
+    : [];
+  const routeLabel = multiCitySegments.length
+    ? multiCitySegments
+        .map((segment) => `${segment.origin} - ${segment.destination}`)
+        .join(", ")
+    : departureSegment && arrivalSegment
       ? `${departureSegment.departure_airport} - ${arrivalSegment.arrival_airport}`
       : "";
 
@@ -3266,7 +3445,9 @@ function createFlightCard(offer, searchParamsOverride, options) {
       ? options.routeMinPrice
       : null;
   const priceLabel = hasPrice
-    ? `${escapeHtml(currency)} ${Number(offer.price).toFixed(2)}`
+    ? `${escapeHtml(currency)} ${Number(offer.price).toFixed(2)}${
+        multiCitySegments.length && offer.complete === false ? " (partial)" : ""
+      }`
     : fallbackMinPrice != null
       ? `From ${escapeHtml(fallbackCurrency)} ${Number(
           fallbackMinPrice,
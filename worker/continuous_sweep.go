@@ -33,6 +33,9 @@ type ContinuousSweepConfig struct {
 	TargetDurationHours int
 	MinDelayMs          int
 	InternationalOnly   bool
+	// Origins restricts the sweep to routes from these airports to the top 100 airports.
+	// When empty, every route between the top 100 airports is swept.
+	Origins []string
 }
 
 // DefaultContinuousSweepConfig returns the default configuration
@@ -113,6 +116,7 @@ type ContinuousPriceGraphPayload struct {
 	Stops          string    `json:"stops"`
 	Adults         int       `json:"adults"`
 	Currency       string    `json:"currency"`
+	SweepNumber    int       `json:"sweep_number,omitempty"` // sweep that enqueued the job
 }
 
 // NewContinuousSweepRunner creates a new continuous sweep runner
@@ -122,24 +126,42 @@ func NewContinuousSweepRunner(
 	notifier *notify.NTFYClient,
 	config ContinuousSweepConfig,
 ) *ContinuousSweepRunner {
-	// Generate routes based on configuration
-	var routes []db.Route
-	if config.InternationalOnly {
-		routes = db.GenerateInternationalRoutes(db.Top100Airports)
-	} else {
-		routes = db.GenerateAllRoutes(db.Top100Airports)
-	}
-
 	return &ContinuousSweepRunner{
 		postgresDB:   postgresDB,
 		queue:        queue,
 		notifier:     notifier,
 		config:       config,
-		routes:       routes,
+		routes:       continuousSweepRoutes(config),
 		recentErrors: make([]time.Time, 0),
 	}
 }
 
+// continuousSweepRoutes generates the routes to sweep based on configuration
+func continuousSweepRoutes(config ContinuousSweepConfig) []db.Route {
+	if len(config.Origins) == 0 {
+		if config.InternationalOnly {
+			return db.GenerateInternationalRoutes(db.Top100Airports)
+		}
+		return db.GenerateAllRoutes(db.Top100Airports)
+	}
+
+	var routes []db.Route
+	for _, origin := range config.Origins {
+		originCountry := db.GetAirportCountry(origin)
+		for _, dest := range db.Top100Airports {
+			if origin == dest.Code {
+				continue
+			}
+			// Airports outside the top 100 have no known country and are kept.
+			if config.InternationalOnly && originCountry != "" && originCountry == dest.Country {
+				continue
+			}
+			routes = append(routes, db.Route{Origin: origin, Destination: dest.Code})
+		}
+	}
+	return routes
+}
+
 // Start begins the continuous sweep process.
 // Note: This method creates its own long-lived context independent of HTTP requests.
 func (r *ContinuousSweepRunner) Start() error {
@@ -401,6 +423,62 @@ func (r *ContinuousSweepRunner) Resume() {
 	}
 }
 
+// PauseForBackoff pauses the sweep after Google rate limited or blocked a request on route, sends
+// the rate limit alert and resumes the sweep once backoff has passed. A sweep which is already
+// paused is left alone, so a manual pause isn't lifted.
+func (r *ContinuousSweepRunner) PauseForBackoff(route string, cause error, backoff time.Duration) {
+	r.mu.RLock()
+	running := r.isRunning
+	paused := r.isPaused
+	sweepNum := r.sweepNumber
+	r.mu.RUnlock()
+
+	if !running || paused {
+		return
+	}
+
+	log.Printf("Pausing continuous sweep for %v: %v on %s", backoff, cause, route)
+	r.Pause()
+	if r.notifier != nil && r.notifier.IsEnabled() {
+		r.notifier.AlertRateLimited(sweepNum, route)
+	}
+
+	r.autoResumeMu.Lock()
+	if r.autoResumeActive {
+		r.autoResumeMu.Unlock()
+		return
+	}
+	r.autoResumeActive = true
+	r.autoResumeMu.Unlock()
+
+	go func() {
+		defer func() {
+			r.autoResumeMu.Lock()
+			r.autoResumeActive = false
+			r.autoResumeMu.Unlock()
+		}()
+
+		timer := time.NewTimer(backoff)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.stopCh:
+			return
+		case <-r.ctx.Done():
+			return
+		}
+
+		r.mu.RLock()
+		running := r.isRunning
+		paused := r.isPaused
+		r.mu.RUnlock()
+		if running && paused {
+			log.Printf("Resuming continuous sweep after backoff")
+			r.Resume()
+		}
+	}()
+}
+
 func (r *ContinuousSweepRunner) PauseAndAutoResumeAfterQueueDrain(queueName string) {
 	r.mu.RLock()
 	running := r.isRunning
@@ -468,13 +546,32 @@ func (r *ContinuousSweepRunner) PauseAndAutoResumeAfterQueueDrain(queueName stri
 	}()
 }
 
-// SetConfig updates the configuration (safe to call while running)
+// SetConfig updates the configuration (safe to call while running).
+// Changing the origins regenerates the routes; callers should restart the sweep.
 func (r *ContinuousSweepRunner) SetConfig(config ContinuousSweepConfig) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if !equalStringSlice(config.Origins, r.config.Origins) || config.InternationalOnly != r.config.InternationalOnly {
+		r.routes = continuousSweepRoutes(config)
+		if r.routeIndex >= len(r.routes) {
+			r.routeIndex = 0
+		}
+	}
 	r.config = config
 }
 
+func equalStringSlice(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // GetConfig returns a copy of the current configuration.
 func (r *ContinuousSweepRunner) GetConfig() ContinuousSweepConfig {
 	r.mu.RLock()
@@ -502,6 +599,7 @@ func (r *ContinuousSweepRunner) GetStatus() db.SweepStatusResponse {
 		Class:               r.config.Class,
 		Stops:               r.config.Stops,
 		TripLengths:         append([]int(nil), r.config.TripLengths...),
+		Origins:             append([]string(nil), r.config.Origins...),
 	}
 
 	if status.TotalRoutes > 0 {
@@ -715,10 +813,12 @@ func (r *ContinuousSweepRunner) processRoute(ctx context.Context, route db.Route
 	r.mu.RLock()
 	tripLengths := r.config.TripLengths
 	config := r.config
+	sweepNumber := r.sweepNumber
 	r.mu.RUnlock()
 
-	// Calculate date range
-	startDate := time.Now().AddDate(0, 0, 7) // Start 1 week from now
+	// Calculate date range; whole days, so the jobs of a sweep have stable idempotency keys
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 7) // Start 1 week from now
 	endDate := startDate.AddDate(0, 0, config.DepartureWindowDays)
 
 	for _, tripLength := range tripLengths {
@@ -742,6 +842,7 @@ func (r *ContinuousSweepRunner) processRoute(ctx context.Context, route db.Route
 			Stops:          config.Stops,
 			Adults:         config.Adults,
 			Currency:       config.Currency,
+			SweepNumber:    sweepNumber,
 		}
 
 		if _, err := r.queue.Enqueue(ctx, "continuous_price_graph", payload); err != nil {
@@ -896,6 +997,7 @@ func (r *ContinuousSweepRunner) saveProgress(ctx context.Context) {
 		IsRunning:           r.isRunning,
 		IsPaused:            r.isPaused,
 		InternationalOnly:   r.config.InternationalOnly,
+		Origins:             append([]string(nil), r.config.Origins...),
 	}
 	if r.routeIndex < len(r.routes) {
 		progress.CurrentOrigin = sql.NullString{String: r.routes[r.routeIndex].Origin, Valid: true}
@@ -917,6 +1019,12 @@ func (r *ContinuousSweepRunner) restoreProgress(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// Restore the origins the sweep was restricted to
+	if !equalStringSlice(progress.Origins, r.config.Origins) && len(progress.Origins) > 0 {
+		r.config.Origins = append([]string(nil), progress.Origins...)
+		r.routes = continuousSweepRoutes(r.config)
+	}
+
 	// Check if InternationalOnly config has changed - if so, reset the sweep
 	// since the route set would be different
 	if progress.InternationalOnly != r.config.InternationalOnly {
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/flights"
+	fx "github.com/gilby125/google-flights-api/pkg/currency"
+	"github.com/gilby125/google-flights-api/pkg/geo"
+	"golang.org/x/text/currency"
+)
+
+// SetCurrencyConverter sets the converter used to normalize stored prices to the reporting
+// currency. It must be called before Start; without a converter prices are stored as quoted and
+// point-of-sale comparisons ask Google for the reporting currency in every market.
+func (m *Manager) SetCurrencyConverter(converter *fx.Converter) {
+	m.fxConverter = converter
+}
+
+// normalizedPrice is a price converted to the reporting currency.
+type normalizedPrice struct {
+	Price       sql.NullFloat64
+	Currency    sql.NullString
+	CostPerMile sql.NullFloat64
+}
+
+// normalizePrice converts price from the quoted currency to the reporting currency with today's
+// rates. The cost per mile is recomputed from the converted price when the distance is known. A
+// missing rate leaves the result empty; the price is still stored as quoted.
+func (m *Manager) normalizePrice(ctx context.Context, price float64, quoted string, distanceMiles sql.NullFloat64) normalizedPrice {
+	if m.fxConverter == nil || price <= 0 {
+		return normalizedPrice{}
+	}
+	converted, err := m.fxConverter.Normalize(ctx, price, strings.ToUpper(quoted), time.Now())
+	if err != nil {
+		log.Printf("Warning: failed to normalize %.2f %s to %s: %v", price, quoted, m.fxConverter.Reporting(), err)
+		return normalizedPrice{}
+	}
+
+	n := normalizedPrice{
+		Price:    sql.NullFloat64{Float64: converted, Valid: isDBSafePrice(converted)},
+		Currency: sql.NullString{String: m.fxConverter.Reporting(), Valid: true},
+	}
+	if !n.Price.Valid {
+		return normalizedPrice{}
+	}
+	if distanceMiles.Valid && distanceMiles.Float64 > 0 {
+		n.CostPerMile = sql.NullFloat64{Float64: geo.CostPerMile(converted, distanceMiles.Float64), Valid: true}
+	}
+	return n
+}
+
+// marketConverter returns the converter of point-of-sale comparisons, or nil if prices can't be
+// converted (markets are then searched in the reporting currency).
+func (m *Manager) marketConverter() flights.Converter {
+	if m.fxConverter == nil {
+		return nil
+	}
+	return func(ctx context.Context, amount float64, from, to currency.Unit) (float64, error) {
+		return m.fxConverter.Convert(ctx, amount, from.String(), to.String(), time.Now())
+	}
+}
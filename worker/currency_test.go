@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	fx "github.com/gilby125/google-flights-api/pkg/currency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/currency"
+)
+
+func TestNormalizePrice(t *testing.T) {
+	m := &Manager{}
+	assert.Equal(t, normalizedPrice{}, m.normalizePrice(context.Background(), 100, "EUR", sql.NullFloat64{}))
+	assert.Nil(t, m.marketConverter())
+
+	provider, err := fx.ParseCSV(strings.NewReader("2020-01-01,EUR,USD,1.2\n"), "test")
+	require.NoError(t, err)
+	m.SetCurrencyConverter(fx.NewConverter("USD", nil, provider))
+
+	n := m.normalizePrice(context.Background(), 100, "eur", sql.NullFloat64{Float64: 1000, Valid: true})
+	assert.Equal(t, sql.NullFloat64{Float64: 120, Valid: true}, n.Price)
+	assert.Equal(t, sql.NullString{String: "USD", Valid: true}, n.Currency)
+	assert.InDelta(t, 0.12, n.CostPerMile.Float64, 1e-9)
+
+	// Without a rate the price is kept as quoted only.
+	assert.Equal(t, normalizedPrice{}, m.normalizePrice(context.Background(), 100, "JPY", sql.NullFloat64{}))
+
+	converted, err := m.marketConverter()(context.Background(), 120, currency.USD, currency.EUR)
+	require.NoError(t, err)
+	assert.InDelta(t, 100, converted, 1e-9)
+}
@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/blockdetect"
+	"github.com/gilby125/google-flights-api/queue"
+)
+
+// Backoff after Google rate limited or blocked a request. It doubles with every block until a job
+// succeeds again.
+const (
+	minGoogleBackoff = time.Minute
+	maxGoogleBackoff = 30 * time.Minute
+)
+
+// jobRoute returns the route of a job for the logs and alerts, e.g. "JFK->LHR".
+func jobRoute(queueName string, job *queue.Job) string {
+	var route struct {
+		Origin      string
+		Destination string
+	}
+	if err := json.Unmarshal(job.Payload, &route); err == nil && route.Origin != "" && route.Destination != "" {
+		return fmt.Sprintf("%s->%s", route.Origin, route.Destination)
+	}
+	return fmt.Sprintf("%s job %s", queueName, job.ID)
+}
+
+// observeGoogleOutcome adjusts the backoff after a job. Blocks start or extend the backoff and pause
+// the continuous sweep; a successful job ends the backoff.
+func (m *Manager) observeGoogleOutcome(queueName string, job *queue.Job, err error) {
+	switch {
+	case err == nil:
+		m.googleBackoffMu.Lock()
+		m.googleBackoff = 0
+		m.googleBackoffMu.Unlock()
+	case blockdetect.IsBlock(err):
+		m.handleGoogleBlock(jobRoute(queueName, job), err)
+	case errors.Is(err, flights.ErrSchemaChanged):
+		log.Printf("Google response schema changed (%s): %v", jobRoute(queueName, job), err)
+	}
+}
+
+func (m *Manager) handleGoogleBlock(route string, cause error) {
+	m.googleBackoffMu.Lock()
+	if time.Now().Before(m.googleBlockedUntil) {
+		// Another worker already backs off for the same block.
+		m.googleBackoffMu.Unlock()
+		return
+	}
+	backoff := m.googleBackoff * 2
+	if backoff < minGoogleBackoff {
+		backoff = minGoogleBackoff
+	}
+	if backoff > maxGoogleBackoff {
+		backoff = maxGoogleBackoff
+	}
+	m.googleBackoff = backoff
+	m.googleBlockedUntil = time.Now().Add(backoff)
+	m.googleBackoffMu.Unlock()
+
+	log.Printf("Google blocked a request on %s (%v); backing off for %v", route, cause, backoff)
+
+	if runner := m.GetSweepRunner(); runner != nil {
+		runner.PauseForBackoff(route, cause, backoff)
+	}
+}
+
+// googleBackoffEnd returns when the current backoff is over. Without a backoff in progress, it
+// returns the end of the shortest one.
+func (m *Manager) googleBackoffEnd() time.Time {
+	m.googleBackoffMu.Lock()
+	defer m.googleBackoffMu.Unlock()
+	if now := time.Now(); !m.googleBlockedUntil.After(now) {
+		return now.Add(minGoogleBackoff)
+	}
+	return m.googleBlockedUntil
+}
+
+// waitForGoogleBackoff blocks until the backoff is over. It returns false if the manager is stopped
+// in the meantime.
+func (m *Manager) waitForGoogleBackoff() bool {
+	m.googleBackoffMu.Lock()
+	wait := time.Until(m.googleBlockedUntil)
+	m.googleBackoffMu.Unlock()
+
+	if wait <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-m.stopChan:
+		return false
+	}
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRoute(t *testing.T) {
+	payload, _ := json.Marshal(ContinuousPriceGraphPayload{Origin: "JFK", Destination: "LHR"})
+	assert.Equal(t, "JFK->LHR", jobRoute("continuous_price_graph", &queue.Job{ID: "1", Payload: payload}))
+	assert.Equal(t, "bulk_search job 2", jobRoute("bulk_search", &queue.Job{ID: "2", Payload: []byte(`{}`)}))
+}
+
+func TestObserveGoogleOutcomeBackoff(t *testing.T) {
+	m := &Manager{stopChan: make(chan struct{})}
+	job := &queue.Job{ID: "1", Payload: []byte(`{}`)}
+
+	m.observeGoogleOutcome("continuous_price_graph", job, fmt.Errorf("query failed: %w", flights.ErrRateLimited))
+	assert.Equal(t, minGoogleBackoff, m.googleBackoff)
+	assert.True(t, m.googleBlockedUntil.After(time.Now()))
+
+	// Other workers hitting the same block don't extend the backoff.
+	m.observeGoogleOutcome("continuous_price_graph", job, flights.ErrBlocked)
+	assert.Equal(t, minGoogleBackoff, m.googleBackoff)
+
+	// The next block after the backoff doubles it.
+	m.googleBlockedUntil = time.Now().Add(-time.Second)
+	m.observeGoogleOutcome("continuous_price_graph", job, flights.ErrConsentRequired)
+	assert.Equal(t, 2*minGoogleBackoff, m.googleBackoff)
+
+	// Schema changes and other errors don't back off.
+	m.googleBlockedUntil = time.Time{}
+	m.observeGoogleOutcome("continuous_price_graph", job, flights.ErrSchemaChanged)
+	m.observeGoogleOutcome("continuous_price_graph", job, fmt.Errorf("timeout"))
+	assert.True(t, m.googleBlockedUntil.IsZero())
+	assert.True(t, m.waitForGoogleBackoff())
+
+	m.observeGoogleOutcome("continuous_price_graph", job, nil)
+	assert.Zero(t, m.googleBackoff)
+}
+
+func TestWaitForGoogleBackoffStops(t *testing.T) {
+	m := &Manager{stopChan: make(chan struct{}), googleBlockedUntil: time.Now().Add(time.Hour)}
+	close(m.stopChan)
+	assert.False(t, m.waitForGoogleBackoff())
+}
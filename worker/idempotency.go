@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/queue"
+)
+
+// payloadKey derives an idempotency key from every field of a payload, so that only payloads
+// describing the same search share a key.
+func payloadKey(payload interface{}) string {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
+
+// bulkSearchKey holds the fields that define what a bulk search (or one of its routes) searches.
+// It leaves out BulkSearchID, JobID and TotalRoutes, which differ between runs of the same search,
+// and keeps dates to the day, since dynamic dates are computed from the time of the run.
+type bulkSearchKey struct {
+	Origins             []string        `json:"origins"`
+	Destinations        []string        `json:"destinations"`
+	DepartureDateFrom   string          `json:"departure_date_from"`
+	DepartureDateTo     string          `json:"departure_date_to"`
+	ReturnDateFrom      string          `json:"return_date_from,omitempty"`
+	ReturnDateTo        string          `json:"return_date_to,omitempty"`
+	TripLength          int             `json:"trip_length"`
+	TripType            string          `json:"trip_type"`
+	Class               string          `json:"class"`
+	Stops               string          `json:"stops"`
+	Currency            string          `json:"currency"`
+	Adults              int             `json:"adults"`
+	Children            int             `json:"children"`
+	InfantsLap          int             `json:"infants_lap"`
+	InfantsSeat         int             `json:"infants_seat"`
+	Carriers            []string        `json:"carriers,omitempty"`
+	MinLayoverMinutes   int             `json:"min_layover_minutes"`
+	MaxLayoverMinutes   int             `json:"max_layover_minutes"`
+	ExcludeBasicEconomy bool            `json:"exclude_basic_economy"`
+	CarryOnBags         int             `json:"carry_on_bags"`
+	CheckedBags         int             `json:"checked_bags"`
+	Country             string          `json:"gl,omitempty"`
+	GoogleHost          string          `json:"google_host,omitempty"`
+	TZOffsetMin         *int            `json:"tz_offset_min,omitempty"`
+	Segments            []SearchSegment `json:"segments,omitempty"`
+}
+
+// keyDate formats t to the day, leaving the zero time empty.
+func keyDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// QueuedBulkSearchID returns the bulk search of the queued bulk_search or bulk_search_route job
+// jobID, or 0 if it has none or can't be read. Since runs of the same search share a job, it tells
+// the caller whether the job it enqueued belongs to another bulk search.
+func QueuedBulkSearchID(ctx context.Context, q queue.Queue, jobID string) int {
+	job, err := q.GetJob(ctx, jobID)
+	if err != nil || job == nil {
+		return 0
+	}
+	var payload struct {
+		BulkSearchID int `json:"bulk_search_id"`
+	}
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return 0
+	}
+	return payload.BulkSearchID
+}
+
+// IdempotencyKey collapses identical searches enqueued within the queue's dedup window.
+func (p FlightSearchPayload) IdempotencyKey() string {
+	return payloadKey(p)
+}
+
+// IdempotencyKey collapses a route enqueued twice with the same search, e.g. when the bulk search
+// job is redelivered after fanning out, or when two runs of the same bulk search fan out the
+// same route. The coordinator only counts the routes that were not collapsed into another bulk
+// search's route (see processBulkSearchCheapFirst).
+func (p BulkSearchRoutePayload) IdempotencyKey() string {
+	return payloadKey(bulkSearchKey{
+		Origins:             []string{p.Origin},
+		Destinations:        []string{p.Destination},
+		DepartureDateFrom:   keyDate(p.DepartureDateFrom),
+		DepartureDateTo:     keyDate(p.DepartureDateTo),
+		TripLength:          p.TripLength,
+		TripType:            p.TripType,
+		Class:               p.Class,
+		Stops:               p.Stops,
+		Currency:            strings.ToUpper(p.Currency),
+		Adults:              p.Adults,
+		Children:            p.Children,
+		InfantsLap:          p.InfantsLap,
+		InfantsSeat:         p.InfantsSeat,
+		Carriers:            p.Carriers,
+		MinLayoverMinutes:   p.MinLayoverMinutes,
+		MaxLayoverMinutes:   p.MaxLayoverMinutes,
+		ExcludeBasicEconomy: p.ExcludeBasicEconomy,
+		CarryOnBags:         p.CarryOnBags,
+		CheckedBags:         p.CheckedBags,
+		Country:             p.Country,
+		GoogleHost:          p.GoogleHost,
+		TZOffsetMin:         p.TZOffsetMin,
+	})
+}
+
+// IdempotencyKey collapses a bulk search enqueued twice with the same search, e.g. by a scheduler
+// tick and an admin "run now" of the same job (see BulkSearchPayloadForJob).
+func (p BulkSearchPayload) IdempotencyKey() string {
+	origins, destinations := p.Origins, p.Destinations
+	if len(origins) == 0 && p.Origin != "" {
+		origins = []string{p.Origin}
+	}
+	if len(destinations) == 0 && p.Destination != "" {
+		destinations = []string{p.Destination}
+	}
+	return payloadKey(bulkSearchKey{
+		Origins:             origins,
+		Destinations:        destinations,
+		DepartureDateFrom:   keyDate(p.DepartureDateFrom),
+		DepartureDateTo:     keyDate(p.DepartureDateTo),
+		ReturnDateFrom:      keyDate(p.ReturnDateFrom),
+		ReturnDateTo:        keyDate(p.ReturnDateTo),
+		TripLength:          p.TripLength,
+		TripType:            p.TripType,
+		Class:               p.Class,
+		Stops:               p.Stops,
+		Currency:            strings.ToUpper(p.Currency),
+		Adults:              p.Adults,
+		Children:            p.Children,
+		InfantsLap:          p.InfantsLap,
+		InfantsSeat:         p.InfantsSeat,
+		Carriers:            p.Carriers,
+		MinLayoverMinutes:   p.MinLayoverMinutes,
+		MaxLayoverMinutes:   p.MaxLayoverMinutes,
+		ExcludeBasicEconomy: p.ExcludeBasicEconomy,
+		CarryOnBags:         p.CarryOnBags,
+		CheckedBags:         p.CheckedBags,
+		Country:             p.Country,
+		GoogleHost:          p.GoogleHost,
+		TZOffsetMin:         p.TZOffsetMin,
+		Segments:            p.Segments,
+	})
+}
+
+// IdempotencyKey collapses a route and date range enqueued again within the same sweep, e.g. by a
+// sweep restarted right after it was stopped. The next sweep over the route gets a new key.
+func (p ContinuousPriceGraphPayload) IdempotencyKey() string {
+	return payloadKey(p)
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayloadIdempotencyKeys(t *testing.T) {
+	var _ queue.IdempotentPayload = FlightSearchPayload{}
+	var _ queue.IdempotentPayload = ContinuousPriceGraphPayload{}
+	var _ queue.IdempotentPayload = BulkSearchPayload{}
+
+	route := BulkSearchRoutePayload{BulkSearchID: 1, Origin: "JFK", Destination: "LHR", Adults: 1}
+	same := route
+	assert.NotEmpty(t, route.IdempotencyKey())
+	assert.Equal(t, route.IdempotencyKey(), same.IdempotencyKey())
+
+	other := route
+	other.BulkSearchID = 2
+	other.TotalRoutes = 4
+	assert.Equal(t, route.IdempotencyKey(), other.IdempotencyKey(), "runs of the same search should share the route")
+
+	other = route
+	other.Destination = "CDG"
+	assert.NotEqual(t, route.IdempotencyKey(), other.IdempotencyKey())
+}
+
+func TestBulkSearchKeyIgnoresRun(t *testing.T) {
+	day := time.Date(2026, 10, 30, 6, 0, 0, 0, time.UTC)
+	payload := BulkSearchPayload{
+		Origins: []string{"JFK"}, Destinations: []string{"LHR"},
+		DepartureDateFrom: day, DepartureDateTo: day.AddDate(0, 0, 2),
+		Adults: 1, TripType: "round_trip", Class: "economy", Stops: "any", Currency: "USD",
+	}
+
+	run := payload
+	run.BulkSearchID = 12
+	run.JobID = 7
+	run.DepartureDateFrom = day.Add(5 * time.Hour)
+	run.Currency = "usd"
+	assert.Equal(t, payload.IdempotencyKey(), run.IdempotencyKey(), "another run of the same search on the same day")
+
+	other := payload
+	other.DepartureDateFrom = day.AddDate(0, 0, 1)
+	assert.NotEqual(t, payload.IdempotencyKey(), other.IdempotencyKey())
+
+	other = payload
+	other.Adults = 2
+	assert.NotEqual(t, payload.IdempotencyKey(), other.IdempotencyKey())
+}
+
+func TestContinuousPriceGraphKeySeparatesSweeps(t *testing.T) {
+	payload := ContinuousPriceGraphPayload{Origin: "JFK", Destination: "LHR", TripLength: 7, SweepNumber: 3}
+	same := payload
+	assert.Equal(t, payload.IdempotencyKey(), same.IdempotencyKey())
+
+	next := payload
+	next.SweepNumber = 4
+	assert.NotEqual(t, payload.IdempotencyKey(), next.IdempotencyKey(), "the next sweep should search the route again")
+}
@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"runtime/debug"
+
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/blockdetect"
+	"github.com/gilby125/google-flights-api/queue"
+)
+
+// jobPanic is the error of a job whose handler panicked.
+type jobPanic struct {
+	value interface{}
+	stack []byte
+}
+
+func (p *jobPanic) Error() string {
+	return fmt.Sprintf("panic: %v", p.value)
+}
+
+// runJob is processJob that turns a panic into an error, so that a single bad job fails on its
+// own instead of taking down the worker.
+func (m *Manager) runJob(ctx context.Context, worker *Worker, queueName string, job *queue.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &jobPanic{value: r, stack: debug.Stack()}
+		}
+	}()
+	return m.processJob(ctx, worker, queueName, job)
+}
+
+// nack returns a failed job to the queue. Queues with a dead-letter stream also record why the
+// attempt failed and on which worker.
+func (m *Manager) nack(ctx context.Context, queueName, jobID string, cause error) error {
+	if dlq, ok := m.queue.(queue.DeadLetterQueue); ok {
+		return dlq.NackWithFailure(ctx, queueName, jobID, m.jobFailure(cause))
+	}
+	return m.queue.Nack(ctx, queueName, jobID)
+}
+
+// retryLater returns a failed job to the queue. Jobs failed by a Google block are deferred until
+// the backoff is over without being charged the attempt, if the queue supports it: the block says
+// nothing about the job, and nacking would dead-letter healthy jobs during a long block. Deferred
+// jobs wait with the jobs enqueued with EnqueueAt and are delivered again by the promoter loop.
+func (m *Manager) retryLater(ctx context.Context, queueName, jobID string, cause error) error {
+	if deferrer, ok := m.queue.(queue.Deferrer); ok && blockdetect.IsBlock(cause) {
+		return deferrer.Defer(ctx, queueName, jobID, m.googleBackoffEnd())
+	}
+	return m.nack(ctx, queueName, jobID, cause)
+}
+
+func (m *Manager) jobFailure(cause error) queue.Failure {
+	failure := queue.Failure{
+		Error:      cause.Error(),
+		ErrorClass: errorClass(cause),
+		WorkerID:   m.config.WorkerID,
+	}
+	var p *jobPanic
+	if errors.As(cause, &p) {
+		failure.Stack = string(p.stack)
+	}
+	return failure
+}
+
+// errorClass groups job errors by cause, so that dead letters can be filtered and replayed by
+// what went wrong.
+func errorClass(err error) string {
+	var (
+		p         *jobPanic
+		netErr    net.Error
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &p):
+		return "panic"
+	case errors.Is(err, blockdetect.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, blockdetect.ErrConsentRequired):
+		return "consent_required"
+	case errors.Is(err, blockdetect.ErrBlocked):
+		return "blocked"
+	case errors.Is(err, flights.ErrSchemaChanged):
+		return "schema_changed"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return "invalid_payload"
+	default:
+		return "error"
+	}
+}
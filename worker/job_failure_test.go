@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gilby125/google-flights-api/config"
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorClass(t *testing.T) {
+	var payload struct{ N int }
+	unmarshalErr := json.Unmarshal([]byte(`{"N":"x"}`), &payload)
+
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("search failed: %w", flights.ErrRateLimited), "rate_limited"},
+		{flights.ErrConsentRequired, "consent_required"},
+		{flights.ErrBlocked, "blocked"},
+		{fmt.Errorf("parse: %w", flights.ErrSchemaChanged), "schema_changed"},
+		{fmt.Errorf("request: %w", context.DeadlineExceeded), "timeout"},
+		{context.Canceled, "canceled"},
+		{fmt.Errorf("failed to unmarshal bulk search payload: %w", unmarshalErr), "invalid_payload"},
+		{&jobPanic{value: "nil map"}, "panic"},
+		{errors.New("boom"), "error"},
+	} {
+		assert.Equal(t, tc.want, errorClass(tc.err), tc.err.Error())
+	}
+}
+
+func TestJobFailure(t *testing.T) {
+	m := &Manager{config: config.WorkerConfig{WorkerID: "worker-1"}}
+
+	failure := m.jobFailure(&jobPanic{value: "index out of range", stack: []byte("goroutine 7 [running]")})
+	assert.Equal(t, "panic: index out of range", failure.Error)
+	assert.Equal(t, "panic", failure.ErrorClass)
+	assert.Equal(t, "goroutine 7 [running]", failure.Stack)
+	assert.Equal(t, "worker-1", failure.WorkerID)
+
+	failure = m.jobFailure(flights.ErrBlocked)
+	assert.Equal(t, "blocked", failure.ErrorClass)
+	assert.Empty(t, failure.Stack)
+}
+
+// deferringQueue records how failed jobs are returned to the queue.
+type deferringQueue struct {
+	queue.Queue
+	nacked   []string
+	deferred map[string]time.Time
+}
+
+func (q *deferringQueue) Nack(ctx context.Context, queueName, jobID string) error {
+	q.nacked = append(q.nacked, jobID)
+	return nil
+}
+
+func (q *deferringQueue) Defer(ctx context.Context, queueName, jobID string, runAt time.Time) error {
+	q.deferred[jobID] = runAt
+	return nil
+}
+
+func TestRetryLaterDefersBlockedJobs(t *testing.T) {
+	q := &deferringQueue{deferred: map[string]time.Time{}}
+	blockedUntil := time.Now().Add(time.Hour)
+	m := &Manager{queue: q, googleBlockedUntil: blockedUntil}
+	ctx := context.Background()
+
+	assert.NoError(t, m.retryLater(ctx, "bulk_search", "blocked", fmt.Errorf("search JFK->LHR failed: %w", flights.ErrRateLimited)))
+	assert.NoError(t, m.retryLater(ctx, "bulk_search", "broken", errors.New("boom")))
+
+	assert.Equal(t, map[string]time.Time{"blocked": blockedUntil}, q.deferred, "blocked jobs wait for the backoff")
+	assert.Equal(t, []string{"broken"}, q.nacked)
+}
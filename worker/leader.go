@@ -12,10 +12,18 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-// LeaderElector manages distributed leader election using Redis.
+// LeaderLock is a lock with an owner and an expiry, such as a Redis key or a row of the
+// Postgres queue. Each method reports whether it took, renewed or released the lock.
+type LeaderLock interface {
+	TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	RenewLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	Unlock(ctx context.Context, key, owner string) (bool, error)
+}
+
+// LeaderElector manages distributed leader election using a Redis key or another LeaderLock.
 // Only the leader instance runs the scheduler to prevent duplicate job execution.
 type LeaderElector struct {
-	redisClient    *redis.Client
+	lock           LeaderLock
 	lockKey        string
 	lockTTL        time.Duration
 	renewInterval  time.Duration
@@ -37,6 +45,19 @@ func NewLeaderElector(
 	renewInterval time.Duration,
 	onBecomeLeader func(),
 	onLoseLeader func(),
+) *LeaderElector {
+	return NewLeaderElectorWithLock(redisLeaderLock{client: redisClient}, lockKey, lockTTL, renewInterval, onBecomeLeader, onLoseLeader)
+}
+
+// NewLeaderElectorWithLock creates a leader elector that holds leadership with lock instead of
+// a Redis key.
+func NewLeaderElectorWithLock(
+	lock LeaderLock,
+	lockKey string,
+	lockTTL time.Duration,
+	renewInterval time.Duration,
+	onBecomeLeader func(),
+	onLoseLeader func(),
 ) *LeaderElector {
 	hostname, err := os.Hostname()
 	if err != nil || hostname == "" {
@@ -45,7 +66,7 @@ func NewLeaderElector(
 	instanceID := fmt.Sprintf("%s-%d", hostname, time.Now().UnixNano())
 
 	return &LeaderElector{
-		redisClient:    redisClient,
+		lock:           lock,
 		lockKey:        lockKey,
 		lockTTL:        lockTTL,
 		renewInterval:  renewInterval,
@@ -137,13 +158,42 @@ func (le *LeaderElector) tryMaintainLeadership() {
 }
 
 func (le *LeaderElector) tryAcquireLock(ctx context.Context) bool {
-	// SET key value NX PX ttl - only set if not exists
-	result, err := le.redisClient.SetNX(ctx, le.lockKey, le.instanceID, le.lockTTL).Result()
+	acquired, err := le.lock.TryLock(ctx, le.lockKey, le.instanceID, le.lockTTL)
 	if err != nil {
 		log.Printf("Error acquiring leader lock: %v", err)
 		return false
 	}
-	return result
+	return acquired
+}
+
+func (le *LeaderElector) renewLock(ctx context.Context) bool {
+	renewed, err := le.lock.RenewLock(ctx, le.lockKey, le.instanceID, le.lockTTL)
+	if err != nil {
+		log.Printf("Error renewing leader lock: %v", err)
+		return false
+	}
+	return renewed
+}
+
+func (le *LeaderElector) releaseLock(ctx context.Context) {
+	released, err := le.lock.Unlock(ctx, le.lockKey, le.instanceID)
+	if err != nil {
+		log.Printf("Error releasing leader lock: %v", err)
+	} else if released {
+		log.Printf("Successfully released leader lock (instance: %s)", le.instanceID)
+	} else {
+		log.Printf("Lock was not held by this instance or already released (instance: %s)", le.instanceID)
+	}
+}
+
+// redisLeaderLock keeps locks in Redis keys holding the owner.
+type redisLeaderLock struct {
+	client *redis.Client
+}
+
+func (l redisLeaderLock) TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	// SET key value NX PX ttl - only set if not exists
+	return l.client.SetNX(ctx, key, owner, ttl).Result()
 }
 
 // Lua script to renew lock only if we own it.
@@ -156,18 +206,9 @@ var renewScript = redis.NewScript(`
 	end
 `)
 
-func (le *LeaderElector) renewLock(ctx context.Context) bool {
-	result, err := renewScript.Run(ctx, le.redisClient,
-		[]string{le.lockKey},
-		le.instanceID,
-		le.lockTTL.Milliseconds(),
-	).Int()
-
-	if err != nil {
-		log.Printf("Error renewing leader lock: %v", err)
-		return false
-	}
-	return result == 1
+func (l redisLeaderLock) RenewLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	result, err := renewScript.Run(ctx, l.client, []string{key}, owner, ttl.Milliseconds()).Int()
+	return result == 1, err
 }
 
 // Lua script to release lock only if we own it.
@@ -180,17 +221,7 @@ var releaseScript = redis.NewScript(`
 	end
 `)
 
-func (le *LeaderElector) releaseLock(ctx context.Context) {
-	result, err := releaseScript.Run(ctx, le.redisClient,
-		[]string{le.lockKey},
-		le.instanceID,
-	).Int()
-
-	if err != nil {
-		log.Printf("Error releasing leader lock: %v", err)
-	} else if result == 1 {
-		log.Printf("Successfully released leader lock (instance: %s)", le.instanceID)
-	} else {
-		log.Printf("Lock was not held by this instance or already released (instance: %s)", le.instanceID)
-	}
+func (l redisLeaderLock) Unlock(ctx context.Context, key, owner string) (bool, error) {
+	result, err := releaseScript.Run(ctx, l.client, []string{key}, owner).Int()
+	return result == 1, err
 }
@@ -17,7 +17,9 @@ import (
 	"github.com/gilby125/google-flights-api/db"
 	"github.com/gilby125/google-flights-api/flights"
 	"github.com/gilby125/google-flights-api/iata"
+	"github.com/gilby125/google-flights-api/pkg/blockdetect"
 	"github.com/gilby125/google-flights-api/pkg/buildinfo"
+	fx "github.com/gilby125/google-flights-api/pkg/currency"
 	"github.com/gilby125/google-flights-api/pkg/deals"
 	"github.com/gilby125/google-flights-api/pkg/geo"
 	"github.com/gilby125/google-flights-api/pkg/worker_registry"
@@ -140,6 +142,26 @@ func flightsToJSON(flights []flights.Flight) []byte {
 			"flight_number":    f.FlightNumber,
 			"airline_name":     f.AirlineName,
 			"legroom":          f.Legroom,
+			"amenities": map[string]bool{
+				"wifi":          f.Amenities.WiFi,
+				"power":         f.Amenities.Power,
+				"entertainment": f.Amenities.Entertainment,
+			},
+			"below_average_legroom": f.BelowAverageLegroom,
+			"overnight":             f.Overnight,
+			"often_delayed":         f.OftenDelayed,
+		}
+		if f.OperatingCarrier != "" {
+			leg["operating_carrier"] = f.OperatingCarrier
+		}
+		if len(f.Codeshares) > 0 {
+			leg["codeshares"] = f.Codeshares
+		}
+		if f.CO2Emissions > 0 {
+			leg["co2_emissions_grams"] = f.CO2Emissions
+		}
+		if f.SeatPitch > 0 {
+			leg["seat_pitch_inches"] = f.SeatPitch
 		}
 		if f.Unknown != nil {
 			leg["unknown"] = f.Unknown
@@ -203,7 +225,7 @@ type Manager struct {
 	workers       []*Worker
 	stopChan      chan struct{}
 	workerWg      sync.WaitGroup
-	flightCache   map[string]*flights.Session
+	sessionPools  map[string]*flights.SessionPool
 	cacheMutex    sync.RWMutex
 	scheduler     *Scheduler
 	statsMutex    sync.RWMutex
@@ -211,11 +233,17 @@ type Manager struct {
 	leaderElector *LeaderElector
 	redisClient   *redis.Client
 	sweepRunner   *ContinuousSweepRunner
-	sweepMutex    sync.RWMutex // Protects sweepRunner access
+	sweepMutex    sync.RWMutex  // Protects sweepRunner access
+	fxConverter   *fx.Converter // Normalizes stored prices to the reporting currency (optional)
 
 	bulkBusyMu        sync.Mutex
 	bulkBusyCached    bool
 	bulkBusyCheckedAt time.Time
+
+	// Backoff after Google rate limited or blocked a request (see observeGoogleOutcome)
+	googleBackoffMu    sync.Mutex
+	googleBackoff      time.Duration
+	googleBlockedUntil time.Time
 }
 
 // NewManager creates a new worker manager.
@@ -249,7 +277,7 @@ func NewManager(queue queue.Queue, redisClient *redis.Client, postgresDB db.Post
 		topNDeals:    topNDeals,
 		excludedSet:  excludedSet,
 		stopChan:     make(chan struct{}),
-		flightCache:  make(map[string]*flights.Session),
+		sessionPools: make(map[string]*flights.SessionPool),
 		scheduler:    scheduler,
 		workerStates: make([]*workerState, workerConfig.Concurrency),
 		redisClient:  redisClient,
@@ -270,6 +298,31 @@ func NewManager(queue queue.Queue, redisClient *redis.Client, postgresDB db.Post
 	return m
 }
 
+// SetLeaderLock enables leader election for the scheduler with lock, for deployments without
+// Redis. It must be called before Start.
+func (m *Manager) SetLeaderLock(lock LeaderLock) {
+	m.leaderElector = NewLeaderElectorWithLock(
+		lock,
+		m.config.SchedulerLockKey,
+		m.config.SchedulerLockTTL,
+		m.config.SchedulerLockRenew,
+		m.onBecomeLeader,
+		m.onLoseLeader,
+	)
+}
+
+// workerQueueNames are the job types the workers process.
+var workerQueueNames = []string{
+	"flight_search",
+	"pos_comparison",
+	"bulk_search",
+	"bulk_search_route",
+	"price_graph_sweep",
+	"continuous_price_graph",
+}
+
+// bulkSearchBusy reports whether bulk searches are pending or running. Workers on queues
+// that cannot schedule fairly use it to hold background sweeps back.
 func (m *Manager) bulkSearchBusy() bool {
 	if m == nil || m.queue == nil {
 		return false
@@ -402,6 +455,7 @@ func (m *Manager) Start() {
 	m.statsMutex.Unlock()
 
 	m.startRegistryHeartbeat()
+	m.startPromoter()
 
 	// Create and start workers (ALL instances run workers)
 	for i := 0; i < m.config.Concurrency; i++ {
@@ -436,6 +490,36 @@ func (m *Manager) GetQueue() queue.Queue {
 	return m.queue
 }
 
+// promoteInterval is how often workers move due scheduled jobs into the queue.
+const promoteInterval = time.Second
+
+// startPromoter moves scheduled jobs into the queue once they are due. Every instance runs it;
+// a job is only ever promoted once.
+func (m *Manager) startPromoter() {
+	promoter, ok := m.queue.(queue.Promoter)
+	if !ok {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(promoteInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.stopChan:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if _, err := promoter.PromoteDue(ctx, workerQueueNames); err != nil {
+					log.Printf("Failed to promote scheduled jobs: %v", err)
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
 func (m *Manager) startRegistryHeartbeat() {
 	if m == nil || m.redisClient == nil || m.config.WorkerID == "" {
 		return
@@ -581,9 +665,9 @@ func (m *Manager) Stop() {
 	}
 	m.statsMutex.Unlock()
 
-	// Clear flight sessions cache
+	// Clear flight session pools
 	m.cacheMutex.Lock()
-	m.flightCache = make(map[string]*flights.Session)
+	m.sessionPools = make(map[string]*flights.SessionPool)
 	m.cacheMutex.Unlock()
 }
 
@@ -614,11 +698,25 @@ func (m *Manager) runWorker(id int, worker *Worker) {
 			})
 			return
 		default:
+			// Queues that schedule fairly pick the next job across every job type themselves,
+			// weighing the priority lanes instead of draining one queue at a time.
+			if fairQueue, ok := m.queue.(queue.FairQueue); ok {
+				if err := m.processNext(id, worker, fairQueue); err != nil {
+					log.Printf("Worker %d error processing queues: %v", displayID, err)
+					time.Sleep(100 * time.Millisecond)
+				}
+				continue
+			}
+
 			// Process jobs from different queues
 			if err := m.processQueue(id, worker, "flight_search"); err != nil {
 				log.Printf("Worker %d error processing flight_search queue: %v", displayID, err)
 			}
 
+			if err := m.processQueue(id, worker, "pos_comparison"); err != nil {
+				log.Printf("Worker %d error processing pos_comparison queue: %v", displayID, err)
+			}
+
 			if err := m.processQueue(id, worker, "bulk_search"); err != nil {
 				log.Printf("Worker %d error processing bulk_search queue: %v", displayID, err)
 			}
@@ -650,8 +748,28 @@ func (m *Manager) runWorker(id int, worker *Worker) {
 	}
 }
 
+// processNext processes the next job of any job type the workers handle.
+func (m *Manager) processNext(workerIndex int, worker *Worker, fairQueue queue.FairQueue) error {
+	return m.processDequeued(workerIndex, worker, "", func(ctx context.Context) (*queue.Job, error) {
+		return fairQueue.DequeueFair(ctx, workerQueueNames)
+	})
+}
+
 // processQueue processes a job from the specified queue
 func (m *Manager) processQueue(workerIndex int, worker *Worker, queueName string) error {
+	return m.processDequeued(workerIndex, worker, queueName, func(ctx context.Context) (*queue.Job, error) {
+		return m.queue.Dequeue(ctx, queueName)
+	})
+}
+
+// processDequeued processes the job returned by dequeue. An empty queueName means the job's
+// own type.
+func (m *Manager) processDequeued(workerIndex int, worker *Worker, queueName string, dequeue func(ctx context.Context) (*queue.Job, error)) error {
+	// Don't take jobs while backing off from a Google block
+	if !m.waitForGoogleBackoff() {
+		return nil
+	}
+
 	// Create a context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), m.config.JobTimeout)
 	defer cancel()
@@ -662,7 +780,7 @@ func (m *Manager) processQueue(workerIndex int, worker *Worker, queueName string
 	}
 
 	// Dequeue a job
-	job, err := m.queue.Dequeue(ctx, queueName)
+	job, err := dequeue(ctx)
 	if err != nil {
 		// Don't return error if context times out waiting for job
 		if ctx.Err() == context.DeadlineExceeded {
@@ -690,6 +808,9 @@ func (m *Manager) processQueue(workerIndex int, worker *Worker, queueName string
 		})
 		return nil
 	}
+	if queueName == "" {
+		queueName = job.Type
+	}
 
 	m.updateWorkerState(workerIndex, func(state *workerState) {
 		state.Status = "processing"
@@ -719,12 +840,15 @@ func (m *Manager) processQueue(workerIndex int, worker *Worker, queueName string
 	// Per-job cancel watcher: if an admin requests cancellation, cancel the job context so HTTP calls can abort.
 	jobCtx, jobCancel := context.WithCancel(ctx)
 	defer jobCancel()
+	// Jobs enqueued while processing (such as bulk search route fan-out) inherit this job's lane.
+	jobCtx = queue.WithTenant(queue.WithPriority(jobCtx, job.Priority), job.Tenant)
 	stopCancelWatch := m.watchJobCancel(jobCtx, job.ID, jobCancel)
 	defer stopCancelWatch()
 
 	// Process the job
-	err = m.processJob(jobCtx, worker, queueName, job)
+	err = m.runJob(jobCtx, worker, queueName, job)
 	jobDuration := time.Since(jobStartTime)
+	m.observeGoogleOutcome(queueName, job, err)
 
 	if err != nil {
 		log.Printf("Error processing job %s after %v: %v", job.ID, jobDuration, err)
@@ -749,8 +873,8 @@ func (m *Manager) processQueue(workerIndex int, worker *Worker, queueName string
 			log.Printf("Job %s timed out after %v (deadline exceeded)", job.ID, jobDuration)
 		}
 
-		// Nack the job
-		if nackErr := m.queue.Nack(ctx, queueName, job.ID); nackErr != nil {
+		// Nack the job, or defer it if a Google block failed it
+		if nackErr := m.retryLater(ctx, queueName, job.ID, err); nackErr != nil {
 			log.Printf("Error nacking job %s: %v", job.ID, nackErr)
 		}
 		m.updateWorkerState(workerIndex, func(state *workerState) {
@@ -815,7 +939,7 @@ func (m *Manager) processJob(ctx context.Context, worker *Worker, queueName stri
 	switch queueName {
 	case "flight_search":
 		// Get cached session for direct search (works fine)
-		session, err := m.getFlightSession("direct_search")
+		session, err := m.getFlightSession(ctx, "direct_search")
 		if err != nil {
 			return fmt.Errorf("failed to get flight session: %w", err)
 		}
@@ -827,9 +951,21 @@ func (m *Manager) processJob(ctx context.Context, worker *Worker, queueName stri
 
 		// Process the flight search
 		return m.processFlightSearch(ctx, worker, session, payload)
+	case "pos_comparison":
+		session, err := m.getFlightSession(ctx, "direct_search")
+		if err != nil {
+			return fmt.Errorf("failed to get flight session: %w", err)
+		}
+
+		var payload PosComparisonPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal pos comparison payload: %w", err)
+		}
+
+		return m.processPosComparison(ctx, session, payload)
 	case "bulk_search":
 		// Get fresh session for bulk search (avoids stale session issues)
-		session, err := m.getFlightSession("bulk_search")
+		session, err := m.getFlightSession(ctx, "bulk_search")
 		if err != nil {
 			return fmt.Errorf("failed to get flight session: %w", err)
 		}
@@ -839,6 +975,10 @@ func (m *Manager) processJob(ctx context.Context, worker *Worker, queueName stri
 			return fmt.Errorf("failed to unmarshal bulk search payload: %w", err)
 		}
 
+		if payload.TripType == "multi_city" {
+			return m.processBulkMultiCitySearch(ctx, worker, session, payload)
+		}
+
 		// Cheap-first currently requires TripLength for round trips; fall back to the legacy
 		// implementation when TripLength isn't provided (return-window mode).
 		if payload.TripType == "round_trip" && payload.TripLength == 0 {
@@ -851,7 +991,7 @@ func (m *Manager) processJob(ctx context.Context, worker *Worker, queueName stri
 		return m.processBulkSearchCheapFirst(ctx, worker, session, payload)
 	case "bulk_search_route":
 		// Process a single route from a fanned-out bulk search
-		session, err := m.getFlightSession("bulk_search")
+		session, err := m.getFlightSession(ctx, "bulk_search")
 		if err != nil {
 			return fmt.Errorf("failed to get flight session: %w", err)
 		}
@@ -863,7 +1003,7 @@ func (m *Manager) processJob(ctx context.Context, worker *Worker, queueName stri
 
 		return m.processBulkSearchRoute(ctx, worker, session, payload)
 	case "price_graph_sweep":
-		session, err := m.getFlightSession("price_graph")
+		session, err := m.getFlightSession(ctx, "price_graph")
 		if err != nil {
 			return fmt.Errorf("failed to get flight session: %w", err)
 		}
@@ -901,7 +1041,7 @@ func (m *Manager) processJob(ctx context.Context, worker *Worker, queueName stri
 			}
 		}
 
-		session, err := m.getFlightSession("price_graph")
+		session, err := m.getFlightSession(ctx, "price_graph")
 		if err != nil {
 			return fmt.Errorf("failed to get flight session: %w", err)
 		}
@@ -1009,6 +1149,10 @@ func (m *Manager) processContinuousPriceGraph(ctx context.Context, worker *Worke
 		Stops:         defaultString(payload.Stops, "any"),
 		QueriedAt:     time.Now(),
 	}
+	normalized := m.normalizePrice(ctx, record.Price, record.Currency, distanceMiles)
+	record.NormalizedPrice = normalized.Price
+	record.NormalizedCurrency = normalized.Currency
+	record.NormalizedCostPerMile = normalized.CostPerMile
 
 	searchURL, urlErr := session.SerializeURL(ctx, flights.Args{
 		Date:        cheapest.StartDate,
@@ -1091,52 +1235,43 @@ func truncateToDay(d time.Time) time.Time {
 	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
 }
 
-// getFlightSession gets or creates a flight session based on session type
-func (m *Manager) getFlightSession(sessionType string) (*flights.Session, error) {
+// getFlightSession draws a flight session from the pool of the session type. Each session type
+// has its own pool, so a flagged bulk search session doesn't affect direct searches.
+func (m *Manager) getFlightSession(ctx context.Context, sessionType string) (*flights.Session, error) {
 	var sessionKey string
 
-	// Use consistent caching strategy for both search types since regular search works fine
 	switch sessionType {
 	case "bulk_search":
-		// Use cached session like direct search for better reliability
 		sessionKey = "bulk_search"
 	case "price_graph":
 		sessionKey = "price_graph"
 	case "direct_search":
-		// Use cached session for direct search (works fine)
 		sessionKey = "direct_search"
 	default:
 		// Fallback to default behavior
 		sessionKey = "default"
 	}
 
-	// Check if we have a cached session
 	m.cacheMutex.RLock()
-	session, exists := m.flightCache[sessionKey]
+	pool, exists := m.sessionPools[sessionKey]
 	m.cacheMutex.RUnlock()
 
-	if exists {
-		return session, nil
-	}
-
-	// Create a new session
-	m.cacheMutex.Lock()
-	defer m.cacheMutex.Unlock()
-
-	// Check again in case another goroutine created the session
-	session, exists = m.flightCache[sessionKey]
-	if exists {
-		return session, nil
+	if !exists {
+		m.cacheMutex.Lock()
+		// Check again in case another goroutine created the pool
+		pool, exists = m.sessionPools[sessionKey]
+		if !exists {
+			pool = flights.NewSessionPool(m.flightConfig.SessionPoolOptions())
+			m.sessionPools[sessionKey] = pool
+		}
+		m.cacheMutex.Unlock()
 	}
 
-	// Create a new session
-	newSession, err := flights.New()
+	session, err := pool.Get(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create flight session: %w", err)
 	}
-
-	m.flightCache[sessionKey] = newSession
-	return newSession, nil
+	return session, nil
 }
 
 // processFlightSearch processes a flight search job
@@ -1148,6 +1283,8 @@ func (m *Manager) processFlightSearch(ctx context.Context, worker *Worker, sessi
 		tripType = flights.OneWay
 	case "round_trip":
 		tripType = flights.RoundTrip
+	case "multi_city":
+		tripType = flights.MultiCity
 	default:
 		return fmt.Errorf("invalid trip type: %s", payload.TripType)
 	}
@@ -1165,31 +1302,34 @@ func (m *Manager) processFlightSearch(ctx context.Context, worker *Worker, sessi
 	flightStops := parseStops(payload.Stops)
 
 	// Get flight offers
-	offers, priceRange, err := session.GetOffers(
-		ctx,
-		flights.Args{
-			Date:        payload.DepartureDate,
-			ReturnDate:  payload.ReturnDate,
-			SrcAirports: []string{payload.Origin},
-			DstAirports: []string{payload.Destination},
-			Options: flights.Options{
-				Travelers: flights.Travelers{
-					Adults:       payload.Adults,
-					Children:     payload.Children,
-					InfantOnLap:  payload.InfantsLap,
-					InfantInSeat: payload.InfantsSeat,
-				},
-				Currency: cur,
-				Stops:    flightStops, // Use parsed value
-				Class:    flightClass, // Use parsed value
-				TripType: tripType,
-				Lang:     language.English,
+	args := flights.Args{
+		Date:        payload.DepartureDate,
+		ReturnDate:  payload.ReturnDate,
+		SrcAirports: []string{payload.Origin},
+		DstAirports: []string{payload.Destination},
+		Segments:    multiCitySegments(payload.Segments),
+		Options: flights.Options{
+			Travelers: flights.Travelers{
+				Adults:       payload.Adults,
+				Children:     payload.Children,
+				InfantOnLap:  payload.InfantsLap,
+				InfantInSeat: payload.InfantsSeat,
 			},
+			Currency:   cur,
+			Stops:      flightStops, // Use parsed value
+			Class:      flightClass, // Use parsed value
+			TripType:   tripType,
+			Lang:       language.English,
+			Country:    payload.Country,
+			GoogleHost: payload.GoogleHost,
 		},
-	)
+	}
+	offers, priceRange, err := session.GetOffers(ctx, args)
 	if err != nil {
 		return fmt.Errorf("failed to get flight offers: %w", err)
 	}
+	fillReturnFlights(ctx, session, args, offers, returnLegOffersLimit)
+	fillMultiCityFlights(ctx, session, args, offers, returnLegOffersLimit)
 
 	// Store the results
 	// Pass the original payload (with string Class/Stops) to StoreFlightOffers
@@ -1197,6 +1337,28 @@ func (m *Manager) processFlightSearch(ctx context.Context, worker *Worker, sessi
 	return worker.StoreFlightOffers(ctx, payload, offers, priceRange)
 }
 
+// returnLegOffersLimit caps how many offers of a single round-trip search get their return leg
+// requested. Every return leg costs one more request to Google.
+const returnLegOffersLimit = 5
+
+// fillReturnFlights requests the return legs of the cheapest round-trip offers. Failures are only
+// logged; the offers keep their outbound-only itineraries in that case.
+func fillReturnFlights(ctx context.Context, session *flights.Session, args flights.Args, offers []flights.FullOffer, limit int) {
+	if args.TripType != flights.RoundTrip || len(offers) == 0 {
+		return
+	}
+	if err := session.FillReturnFlights(ctx, args, offers, limit); err != nil {
+		log.Printf("Warning: failed to get return flights for %v -> %v: %v", args.SrcAirports, args.DstAirports, err)
+	}
+}
+
+// fillReturnFlight requests the return leg of a single round-trip offer.
+func fillReturnFlight(ctx context.Context, session *flights.Session, args flights.Args, offer *flights.FullOffer) {
+	offers := []flights.FullOffer{*offer}
+	fillReturnFlights(ctx, session, args, offers, 1)
+	*offer = offers[0]
+}
+
 // processBulkSearch processes a bulk search job
 func (m *Manager) processBulkSearch(ctx context.Context, worker *Worker, session *flights.Session, payload BulkSearchPayload) (err error) {
 	// Validate origins and destinations
@@ -1326,35 +1488,49 @@ func (m *Manager) processBulkSearch(ctx context.Context, worker *Worker, session
 				}
 
 				// Search for flights on this specific date
-				offers, priceRange, err := session.GetOffers(
-					ctx,
-					flights.Args{
-						Date:        searchDate,
-						ReturnDate:  returnDate,
-						SrcAirports: []string{origin},
-						DstAirports: []string{destination},
-						Options: flights.Options{
-							Travelers: flights.Travelers{
-								Adults:       payload.Adults,
-								Children:     payload.Children,
-								InfantOnLap:  payload.InfantsLap,
-								InfantInSeat: payload.InfantsSeat,
-							},
-							Currency: cur,
-							Stops:    flightStops,
-							Class:    flightClass,
-							TripType: tripType,
-							Lang:     language.English,
-							Carriers: payload.Carriers,
+				args := flights.Args{
+					Date:        searchDate,
+					ReturnDate:  returnDate,
+					SrcAirports: []string{origin},
+					DstAirports: []string{destination},
+					Options: flights.Options{
+						Travelers: flights.Travelers{
+							Adults:       payload.Adults,
+							Children:     payload.Children,
+							InfantOnLap:  payload.InfantsLap,
+							InfantInSeat: payload.InfantsSeat,
 						},
+						Currency:       cur,
+						Stops:          flightStops,
+						Class:          flightClass,
+						TripType:       tripType,
+						Lang:           language.English,
+						Carriers:       payload.Carriers,
+						Country:        payload.Country,
+						GoogleHost:     payload.GoogleHost,
+						TimezoneOffset: payload.TZOffsetMin,
 					},
-				)
+					MinLayover:          time.Duration(payload.MinLayoverMinutes) * time.Minute,
+					MaxLayover:          time.Duration(payload.MaxLayoverMinutes) * time.Minute,
+					ExcludeBasicEconomy: payload.ExcludeBasicEconomy,
+					RequiredBags:        flights.Bags{CarryOn: payload.CarryOnBags, Checked: payload.CheckedBags},
+				}
+				offers, priceRange, err := session.GetOffers(ctx, args)
 
 				if err != nil {
 					log.Printf("Error searching %s -> %s on %s: %v", origin, destination, searchDate.Format("2006-01-02"), err)
-					searchErrors = append(searchErrors, fmt.Errorf("search %s->%s on %s failed: %w", origin, destination, searchDate.Format("2006-01-02"), err))
+					searchErr := fmt.Errorf("search %s->%s on %s failed: %w", origin, destination, searchDate.Format("2006-01-02"), err)
+					if blockdetect.IsBlock(err) {
+						// The remaining searches would hit the same block; the job is retried once
+						// the backoff is over.
+						return searchErr
+					}
+					searchErrors = append(searchErrors, searchErr)
 					continue
 				}
+				// Only the cheapest itinerary gets its return leg; a bulk search already issues one
+				// request per route and date.
+				fillReturnFlights(ctx, session, args, offers, 1)
 
 				routeResult.SearchedDates++
 				routeResult.TotalOffers += len(offers)
@@ -1384,6 +1560,8 @@ func (m *Manager) processBulkSearch(ctx context.Context, worker *Worker, session
 						Class:         payload.Class,
 						Stops:         payload.Stops,
 						Currency:      payload.Currency,
+						Country:       payload.Country,
+						GoogleHost:    payload.GoogleHost,
 					}
 
 					if err := worker.StoreFlightOffers(ctx, searchPayload, offers, priceRange); err != nil {
@@ -1415,6 +1593,10 @@ func (m *Manager) processBulkSearch(ctx context.Context, worker *Worker, session
 								ReturnFlightsJSON:    flightsToJSON(offer.ReturnFlight),
 								OfferJSON:            offerToJSON(offer),
 							}
+							normalized := m.normalizePrice(ctx, offerRecord.Price, offerRecord.Currency, distanceMiles)
+							offerRecord.NormalizedPrice = normalized.Price
+							offerRecord.NormalizedCurrency = normalized.Currency
+							offerRecord.NormalizedCostPerMile = normalized.CostPerMile
 
 							if insertErr := m.postgresDB.InsertBulkSearchOffer(ctx, offerRecord); insertErr != nil {
 								log.Printf("Failed to insert bulk offer for %s -> %s: %v", origin, destination, insertErr)
@@ -1456,6 +1638,8 @@ func (m *Manager) processBulkSearch(ctx context.Context, worker *Worker, session
 			Class:         payload.Class,
 			Stops:         payload.Stops,
 			Currency:      payload.Currency,
+			Country:       payload.Country,
+			GoogleHost:    payload.GoogleHost,
 		}
 
 		// Store just the best offer with a special marker
@@ -1539,6 +1723,9 @@ func (m *Manager) processBulkSearch(ctx context.Context, worker *Worker, session
 			ReturnFlightsJSON:    flightsToJSON(result.BestOffer.ReturnFlight),
 			OfferJSON:            offerToJSON(result.BestOffer),
 		}
+		normalized := m.normalizePrice(ctx, record.Price, record.Currency, sql.NullFloat64{})
+		record.NormalizedPrice = normalized.Price
+		record.NormalizedCurrency = normalized.Currency
 
 		if insertErr := m.postgresDB.InsertBulkSearchResult(ctx, record); insertErr != nil {
 			log.Printf("Failed to insert bulk search result for %s -> %s: %v", result.Origin, result.Destination, insertErr)
@@ -1621,7 +1808,7 @@ func (m *Manager) isExcludedAirline(offer flights.FullOffer) bool {
 }
 
 // scoreDeal calculates a composite deal score where lower is better.
-// It considers price, duration overhead, stops, and departure time.
+// It considers price, duration overhead, stops, departure time, and the fare brand.
 func scoreDeal(offer flights.FullOffer, distanceMiles float64) float64 {
 	score := offer.Price
 
@@ -1657,6 +1844,11 @@ func scoreDeal(offer flights.FullOffer, distanceMiles float64) float64 {
 		}
 	}
 
+	// Penalize basic economy: $60, roughly the bags it leaves out
+	if offer.Fare.BasicEconomy {
+		score += 60
+	}
+
 	return score
 }
 
@@ -1720,43 +1912,61 @@ func (m *Manager) processBulkSearchCheapFirst(ctx context.Context, worker *Worke
 
 	// Fan out: enqueue individual route jobs
 	enqueuedCount := 0
+	sharedCount := 0
 	for _, origin := range payload.Origins {
 		for _, destination := range payload.Destinations {
 			if origin == destination {
 				continue
 			}
 			routePayload := BulkSearchRoutePayload{
-				BulkSearchID:      bulkSearchID,
-				TotalRoutes:       totalRoutes,
-				Origin:            origin,
-				Destination:       destination,
-				DepartureDateFrom: payload.DepartureDateFrom,
-				DepartureDateTo:   payload.DepartureDateTo,
-				TripLength:        payload.TripLength,
-				TripType:          payload.TripType,
-				Class:             payload.Class,
-				Stops:             payload.Stops,
-				Currency:          payload.Currency,
-				Adults:            payload.Adults,
-				Children:          payload.Children,
-				InfantsLap:        payload.InfantsLap,
-				InfantsSeat:       payload.InfantsSeat,
-				Carriers:          payload.Carriers,
+				BulkSearchID:        bulkSearchID,
+				TotalRoutes:         totalRoutes,
+				Origin:              origin,
+				Destination:         destination,
+				DepartureDateFrom:   payload.DepartureDateFrom,
+				DepartureDateTo:     payload.DepartureDateTo,
+				TripLength:          payload.TripLength,
+				TripType:            payload.TripType,
+				Class:               payload.Class,
+				Stops:               payload.Stops,
+				Currency:            payload.Currency,
+				Adults:              payload.Adults,
+				Children:            payload.Children,
+				InfantsLap:          payload.InfantsLap,
+				InfantsSeat:         payload.InfantsSeat,
+				Carriers:            payload.Carriers,
+				Country:             payload.Country,
+				GoogleHost:          payload.GoogleHost,
+				TZOffsetMin:         payload.TZOffsetMin,
+				MinLayoverMinutes:   payload.MinLayoverMinutes,
+				MaxLayoverMinutes:   payload.MaxLayoverMinutes,
+				ExcludeBasicEconomy: payload.ExcludeBasicEconomy,
+				CarryOnBags:         payload.CarryOnBags,
+				CheckedBags:         payload.CheckedBags,
 			}
 
-			if _, enqueueErr := m.queue.Enqueue(ctx, "bulk_search_route", routePayload); enqueueErr != nil {
+			routeJobID, enqueueErr := m.queue.Enqueue(ctx, "bulk_search_route", routePayload)
+			if enqueueErr != nil {
 				log.Printf("[BulkSearchCoordinator] Failed to enqueue route %s->%s: %v", origin, destination, enqueueErr)
 				// Continue with other routes - don't fail the whole search
-			} else {
-				enqueuedCount++
+				continue
+			}
+			// A route collapsed into the same search of another bulk search counts toward that
+			// bulk search, so this one must not wait for it.
+			if owner := QueuedBulkSearchID(ctx, m.queue, routeJobID); owner != 0 && owner != bulkSearchID {
+				log.Printf("[BulkSearchCoordinator] Route %s->%s is already queued for bulk_search %d (job %s); not counting it for bulk_search %d",
+					origin, destination, owner, routeJobID, bulkSearchID)
+				sharedCount++
+				continue
 			}
+			enqueuedCount++
 		}
 	}
 
 	log.Printf("[BulkSearchCoordinator] Enqueued %d/%d route jobs for bulk_search %d",
 		enqueuedCount, totalRoutes, bulkSearchID)
 
-	if enqueuedCount == 0 {
+	if enqueuedCount == 0 && sharedCount == 0 {
 		return fmt.Errorf("failed to enqueue any routes for bulk_search %d", bulkSearchID)
 	}
 
@@ -1873,13 +2083,20 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 					InfantOnLap:  payload.InfantsLap,
 					InfantInSeat: payload.InfantsSeat,
 				},
-				Currency: cur,
-				Stops:    flightStops,
-				Class:    flightClass,
-				TripType: tripType,
-				Lang:     language.English,
-				Carriers: payload.Carriers,
+				Currency:       cur,
+				Stops:          flightStops,
+				Class:          flightClass,
+				TripType:       tripType,
+				Lang:           language.English,
+				Carriers:       payload.Carriers,
+				Country:        payload.Country,
+				GoogleHost:     payload.GoogleHost,
+				TimezoneOffset: payload.TZOffsetMin,
 			},
+			MinLayover:          time.Duration(payload.MinLayoverMinutes) * time.Minute,
+			MaxLayover:          time.Duration(payload.MaxLayoverMinutes) * time.Minute,
+			ExcludeBasicEconomy: payload.ExcludeBasicEconomy,
+			RequiredBags:        flights.Bags{CarryOn: payload.CarryOnBags, Checked: payload.CheckedBags},
 		}
 
 		callCtx, cancel := context.WithTimeout(ctx, bulkOffersCallTimeout)
@@ -1887,6 +2104,10 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 		cancel()
 		if err != nil {
 			log.Printf("[BulkSearchRoute] Error getting offers for %s on %s: %v", routeKey, depDate.Format("2006-01-02"), err)
+			if blockdetect.IsBlock(err) {
+				// The route is deferred without counting its progress.
+				return fmt.Errorf("offers for %s on %s: %w", routeKey, depDate.Format("2006-01-02"), err)
+			}
 			m.finalizeBulkSearchRouteIfComplete(ctx, payload.BulkSearchID)
 			return nil
 		}
@@ -1908,6 +2129,10 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 		}
 
 		if bestOffer != nil {
+			callCtx, cancel := context.WithTimeout(ctx, bulkOffersCallTimeout)
+			fillReturnFlight(callCtx, session, args, bestOffer)
+			cancel()
+
 			log.Printf("[BulkSearchRoute] Route %s: best deal $%.2f on %s", routeKey, bestOffer.Price, depDate.Format("2006-01-02"))
 
 			ret := sql.NullTime{}
@@ -1947,6 +2172,9 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 				ReturnFlightsJSON:    flightsToJSON(bestOffer.ReturnFlight),
 				OfferJSON:            offerToJSON(*bestOffer),
 			}
+			normalized := m.normalizePrice(ctx, record.Price, record.Currency, sql.NullFloat64{})
+			record.NormalizedPrice = normalized.Price
+			record.NormalizedCurrency = normalized.Currency
 
 			if insertErr := m.postgresDB.InsertBulkSearchResult(ctx, record); insertErr != nil {
 				log.Printf("[BulkSearchRoute] Failed to insert result for %s: %v", routeKey, insertErr)
@@ -1973,12 +2201,15 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 				InfantOnLap:  payload.InfantsLap,
 				InfantInSeat: payload.InfantsSeat,
 			},
-			Currency: cur,
-			Stops:    flightStops,
-			Class:    flightClass,
-			TripType: tripType,
-			Lang:     language.English,
-			Carriers: payload.Carriers,
+			Currency:       cur,
+			Stops:          flightStops,
+			Class:          flightClass,
+			TripType:       tripType,
+			Lang:           language.English,
+			Carriers:       payload.Carriers,
+			Country:        payload.Country,
+			GoogleHost:     payload.GoogleHost,
+			TimezoneOffset: payload.TZOffsetMin,
 		},
 	}
 
@@ -1987,6 +2218,10 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 	cancel()
 	if err != nil {
 		log.Printf("[BulkSearchRoute] Error getting price graph for %s: %v", routeKey, err)
+		if blockdetect.IsBlock(err) {
+			// The route is deferred without counting its progress.
+			return fmt.Errorf("price graph for %s: %w", routeKey, err)
+		}
 		// Increment progress even on error. Returning a non-nil error would NACK and retry the job,
 		// which can double-count progress and prematurely finalize the bulk search.
 		m.finalizeBulkSearchRouteIfComplete(ctx, payload.BulkSearchID)
@@ -2031,6 +2266,7 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 	var bestOffer *flights.FullOffer
 	var bestScore float64 = math.MaxFloat64
 	var bestDate, bestReturn time.Time
+	var bestArgs flights.Args
 
 	for _, priceOffer := range topOffers {
 		args := flights.Args{
@@ -2045,13 +2281,20 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 					InfantOnLap:  payload.InfantsLap,
 					InfantInSeat: payload.InfantsSeat,
 				},
-				Currency: cur,
-				Stops:    flightStops,
-				Class:    flightClass,
-				TripType: tripType,
-				Lang:     language.English,
-				Carriers: payload.Carriers,
+				Currency:       cur,
+				Stops:          flightStops,
+				Class:          flightClass,
+				TripType:       tripType,
+				Lang:           language.English,
+				Carriers:       payload.Carriers,
+				Country:        payload.Country,
+				GoogleHost:     payload.GoogleHost,
+				TimezoneOffset: payload.TZOffsetMin,
 			},
+			MinLayover:          time.Duration(payload.MinLayoverMinutes) * time.Minute,
+			MaxLayover:          time.Duration(payload.MaxLayoverMinutes) * time.Minute,
+			ExcludeBasicEconomy: payload.ExcludeBasicEconomy,
+			RequiredBags:        flights.Bags{CarryOn: payload.CarryOnBags, Checked: payload.CheckedBags},
 		}
 
 		callCtx, cancel := context.WithTimeout(ctx, bulkOffersCallTimeout)
@@ -2060,6 +2303,9 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 		if err != nil {
 			log.Printf("[BulkSearchRoute] Error getting offers for %s on %s: %v",
 				routeKey, priceOffer.StartDate.Format("2006-01-02"), err)
+			if blockdetect.IsBlock(err) {
+				return fmt.Errorf("offers for %s on %s: %w", routeKey, priceOffer.StartDate.Format("2006-01-02"), err)
+			}
 			continue
 		}
 
@@ -2077,12 +2323,17 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 				bestOffer = &fullOffers[i]
 				bestDate = priceOffer.StartDate
 				bestReturn = priceOffer.ReturnDate
+				bestArgs = args
 			}
 		}
 	}
 
 	// Store the result
 	if bestOffer != nil {
+		callCtx, cancel := context.WithTimeout(ctx, bulkOffersCallTimeout)
+		fillReturnFlight(callCtx, session, bestArgs, bestOffer)
+		cancel()
+
 		log.Printf("[BulkSearchRoute] Route %s: best deal $%.2f on %s",
 			routeKey, bestOffer.Price, bestDate.Format("2006-01-02"))
 
@@ -2123,6 +2374,9 @@ func (m *Manager) processBulkSearchRoute(ctx context.Context, worker *Worker, se
 			ReturnFlightsJSON:    flightsToJSON(bestOffer.ReturnFlight),
 			OfferJSON:            offerToJSON(*bestOffer),
 		}
+		normalized := m.normalizePrice(ctx, record.Price, record.Currency, sql.NullFloat64{})
+		record.NormalizedPrice = normalized.Price
+		record.NormalizedCurrency = normalized.Currency
 
 		if insertErr := m.postgresDB.InsertBulkSearchResult(ctx, record); insertErr != nil {
 			log.Printf("[BulkSearchRoute] Failed to insert result for %s: %v", routeKey, insertErr)
@@ -2281,105 +2535,142 @@ func (m *Manager) processPriceGraphSweep(ctx context.Context, worker *Worker, se
 		Lang:     language.English,
 	}
 
+	// Open-jaw sweeps search every return leg; nil is the plain round-trip or one-way graph.
+	returnLegs := []*sweepReturnLeg{nil}
+	if len(payload.ReturnOrigins) > 0 {
+		returnLegs = returnLegs[:0]
+		for _, returnOrigin := range payload.ReturnOrigins {
+			if len(payload.ReturnDestinations) == 0 {
+				returnLegs = append(returnLegs, &sweepReturnLeg{Origin: returnOrigin})
+				continue
+			}
+			for _, returnDestination := range payload.ReturnDestinations {
+				returnLegs = append(returnLegs, &sweepReturnLeg{Origin: returnOrigin, Destination: returnDestination})
+			}
+		}
+	}
+
 	for _, origin := range payload.Origins {
 		for _, destination := range payload.Destinations {
-			for _, length := range tripLengths {
-				for _, class := range normalizedClasses {
-					select {
-					case <-ctx.Done():
-						return ctx.Err()
-					default:
-					}
-
-					options.Class = parseClass(class)
-					args := flights.PriceGraphArgs{
-						RangeStartDate: payload.DepartureDateFrom,
-						RangeEndDate:   payload.DepartureDateTo,
-						TripLength:     length,
-						SrcAirports:    []string{origin},
-						DstAirports:    []string{destination},
-						Options:        options,
-					}
-
-					offers, _, sweepErr := session.GetPriceGraph(ctx, args)
-					if sweepErr != nil {
-						errorCount++
-						log.Printf("Price graph sweep error for %s -> %s (class %s, length %d): %v", origin, destination, class, length, sweepErr)
-						continue
-					}
-
-					for _, offer := range offers {
-						// Price=0 means price unavailable; skip these rows.
-						if offer.Price <= 0 {
-							continue
-						}
-						distanceMiles, costPerMile := calculateDistanceAndCostPerMile(origin, destination, offer.Price)
-						record := db.PriceGraphResultRecord{
-							SweepID:       sweepID,
-							Origin:        origin,
-							Destination:   destination,
-							DepartureDate: offer.StartDate,
-							ReturnDate:    timeToNullTime(offer.ReturnDate),
-							TripLength:    nullInt32(length),
-							Price:         offer.Price,
-							Currency:      strings.ToUpper(payload.Currency),
-							DistanceMiles: distanceMiles,
-							CostPerMile:   costPerMile,
-							Adults:        payload.Adults,
-							Children:      payload.Children,
-							InfantsLap:    payload.InfantsLap,
-							InfantsSeat:   payload.InfantsSeat,
-							TripType:      payload.TripType,
-							Class:         class,
-							Stops:         payload.Stops,
-							QueriedAt:     time.Now(),
+			for _, leg := range returnLegs {
+				for _, length := range tripLengths {
+					for _, class := range normalizedClasses {
+						select {
+						case <-ctx.Done():
+							return ctx.Err()
+						default:
 						}
 
-						searchURL, urlErr := session.SerializeURL(ctx, flights.Args{
-							Date:        offer.StartDate,
-							ReturnDate:  offer.ReturnDate,
-							SrcAirports: []string{origin},
-							DstAirports: []string{destination},
-							Options:     options,
-						})
-						if urlErr == nil && searchURL != "" {
-							record.SearchURL = sql.NullString{String: searchURL, Valid: true}
-						} else if urlErr != nil {
-							log.Printf("Failed to serialize Google Flights URL for %s->%s: %v", origin, destination, urlErr)
+						options.Class = parseClass(class)
+						args := flights.PriceGraphArgs{
+							RangeStartDate: payload.DepartureDateFrom,
+							RangeEndDate:   payload.DepartureDateTo,
+							TripLength:     length,
+							SrcAirports:    []string{origin},
+							DstAirports:    []string{destination},
+							Options:        options,
+						}
+						recordTripType := payload.TripType
+						route := fmt.Sprintf("%s -> %s", origin, destination)
+						var returnOrigin, returnDestination string
+						if leg != nil {
+							returnOrigin, returnDestination = leg.Origin, leg.Destination
+							if returnDestination == "" {
+								returnDestination = origin
+							}
+							args.ReturnLeg = &flights.PriceGraphLeg{
+								SrcAirports: []string{returnOrigin},
+								DstAirports: []string{returnDestination},
+							}
+							recordTripType = leg.tripType(origin)
+							route = fmt.Sprintf("%s, %s -> %s", route, returnOrigin, returnDestination)
 						}
 
-						if insertErr := m.postgresDB.InsertPriceGraphResult(ctx, record); insertErr != nil {
+						offers, _, sweepErr := session.GetPriceGraph(ctx, args)
+						if sweepErr != nil {
 							errorCount++
-							log.Printf("Failed to store price graph result for %s -> %s on %s: %v", origin, destination, offer.StartDate.Format("2006-01-02"), insertErr)
+							log.Printf("Price graph sweep error for %s (class %s, length %d): %v", route, class, length, sweepErr)
+							if blockdetect.IsBlock(sweepErr) {
+								return fmt.Errorf("price graph sweep of %s: %w", route, sweepErr)
+							}
 							continue
 						}
-						resultsInserted++
-
-						// Sync price point to Neo4j for graph analytics (idempotent via MERGE)
-						if m.neo4jDB != nil {
-							dateStr := offer.StartDate.Format("2006-01-02")
-							returnDateStr := ""
-							if !offer.ReturnDate.IsZero() {
-								returnDateStr = offer.ReturnDate.Format("2006-01-02")
+
+						for _, offer := range offers {
+							// Price=0 means price unavailable; skip these rows.
+							if offer.Price <= 0 {
+								continue
 							}
-							tripType := strings.TrimSpace(strings.ToLower(payload.TripType))
-							if tripType == "" {
-								if returnDateStr == "" {
-									tripType = "one_way"
-								} else {
-									tripType = "round_trip"
-								}
+							distanceMiles, costPerMile := calculateDistanceAndCostPerMile(origin, destination, offer.Price)
+							record := db.PriceGraphResultRecord{
+								SweepID:       sweepID,
+								Origin:        origin,
+								Destination:   destination,
+								DepartureDate: offer.StartDate,
+								ReturnDate:    timeToNullTime(offer.ReturnDate),
+								TripLength:    nullInt32(length),
+								Price:         offer.Price,
+								Currency:      strings.ToUpper(payload.Currency),
+								DistanceMiles: distanceMiles,
+								CostPerMile:   costPerMile,
+								Adults:        payload.Adults,
+								Children:      payload.Children,
+								InfantsLap:    payload.InfantsLap,
+								InfantsSeat:   payload.InfantsSeat,
+								TripType:      recordTripType,
+								Class:         class,
+								Stops:         payload.Stops,
+								QueriedAt:     time.Now(),
+
+								ReturnOrigin:      returnOrigin,
+								ReturnDestination: returnDestination,
 							}
-							if syncErr := m.neo4jDB.AddPricePoint(origin, destination, dateStr, returnDateStr, offer.Price, "", tripType, class); syncErr != nil {
-								log.Printf("Warning: failed to sync price point to Neo4j for %s->%s: %v", origin, destination, syncErr)
+							normalized := m.normalizePrice(ctx, record.Price, record.Currency, distanceMiles)
+							record.NormalizedPrice = normalized.Price
+							record.NormalizedCurrency = normalized.Currency
+							record.NormalizedCostPerMile = normalized.CostPerMile
+
+							searchURL, urlErr := session.SerializeURL(ctx, args.OfferArgs(offer))
+							if urlErr == nil && searchURL != "" {
+								record.SearchURL = sql.NullString{String: searchURL, Valid: true}
+							} else if urlErr != nil {
+								log.Printf("Failed to serialize Google Flights URL for %s: %v", route, urlErr)
+							}
+
+							if insertErr := m.postgresDB.InsertPriceGraphResult(ctx, record); insertErr != nil {
+								errorCount++
+								log.Printf("Failed to store price graph result for %s on %s: %v", route, offer.StartDate.Format("2006-01-02"), insertErr)
+								continue
+							}
+							resultsInserted++
+
+							// Sync price point to Neo4j for graph analytics (idempotent via MERGE).
+							// Open-jaw prices aren't prices of the origin -> destination route.
+							if m.neo4jDB != nil && leg == nil {
+								dateStr := offer.StartDate.Format("2006-01-02")
+								returnDateStr := ""
+								if !offer.ReturnDate.IsZero() {
+									returnDateStr = offer.ReturnDate.Format("2006-01-02")
+								}
+								tripType := strings.TrimSpace(strings.ToLower(payload.TripType))
+								if tripType == "" {
+									if returnDateStr == "" {
+										tripType = "one_way"
+									} else {
+										tripType = "round_trip"
+									}
+								}
+								if syncErr := m.neo4jDB.AddPricePoint(origin, destination, dateStr, returnDateStr, offer.Price, "", tripType, class); syncErr != nil {
+									log.Printf("Warning: failed to sync price point to Neo4j for %s->%s: %v", origin, destination, syncErr)
+								}
 							}
 						}
-					}
 
-					select {
-					case <-ctx.Done():
-						return ctx.Err()
-					case <-time.After(rateDelay):
+						select {
+						case <-ctx.Done():
+							return ctx.Err()
+						case <-time.After(rateDelay):
+						}
 					}
 				}
 			}
@@ -2402,6 +2693,21 @@ func (m *Manager) processPriceGraphSweep(ctx context.Context, worker *Worker, se
 	return nil
 }
 
+// sweepReturnLeg is the return flight of an open-jaw price graph sweep. An empty destination
+// means the origin of the trip.
+type sweepReturnLeg struct {
+	Origin      string
+	Destination string
+}
+
+// tripType returns the trip type stored with the results of the leg.
+func (l *sweepReturnLeg) tripType(origin string) string {
+	if l.Destination == "" || l.Destination == origin {
+		return "open_jaw"
+	}
+	return "multi_city"
+}
+
 // generateDateRange generates a slice of dates within the given range for searching
 func (m *Manager) generateDateRange(startDate, endDate time.Time, tripLength int) []time.Time {
 	var dates []time.Time
@@ -0,0 +1,335 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/blockdetect"
+	"github.com/gilby125/google-flights-api/pkg/geo"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// multiCitySegments converts the segments of a payload to flights segments. It returns nil for
+// searches without segments.
+func multiCitySegments(segments []SearchSegment) []flights.Segment {
+	if len(segments) == 0 {
+		return nil
+	}
+	result := make([]flights.Segment, 0, len(segments))
+	for _, segment := range segments {
+		result = append(result, flights.Segment{
+			Date:        segment.DepartureDate,
+			SrcAirports: []string{segment.Origin},
+			DstAirports: []string{segment.Destination},
+		})
+	}
+	return result
+}
+
+// fillMultiCityFlights requests the following segments of the cheapest multi-city offers. Failures
+// are only logged; the offers keep the flights of their first segment in that case.
+func fillMultiCityFlights(ctx context.Context, session *flights.Session, args flights.Args, offers []flights.FullOffer, limit int) {
+	if args.TripType != flights.MultiCity || len(offers) == 0 {
+		return
+	}
+	if err := session.FillMultiCityFlights(ctx, args, offers, limit); err != nil {
+		log.Printf("Warning: failed to get multi-city segment flights for %d segments: %v", len(args.Segments), err)
+	}
+}
+
+// segmentFlightsToJSON encodes the flights of every segment of a multi-city offer as a JSON array
+// of flightsToJSON arrays. Offers whose segments weren't filled return nil.
+func segmentFlightsToJSON(offer flights.FullOffer) []byte {
+	if len(offer.SegmentFlights) == 0 {
+		return nil
+	}
+	segments := make([]json.RawMessage, 0, len(offer.SegmentFlights))
+	for _, segment := range offer.SegmentFlights {
+		segments = append(segments, flightsToJSON(segment))
+	}
+	data, err := json.Marshal(segments)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func firstAirlineCode(legs []flights.Flight) string {
+	if len(legs) == 0 || len(legs[0].FlightNumber) < 2 {
+		return ""
+	}
+	return legs[0].FlightNumber[:2]
+}
+
+// multiCityDistance returns the distance in miles flown over all segments, and the cost per mile
+// of price. Both are null if the coordinates of any airport are unknown.
+func multiCityDistance(segments []SearchSegment, price float64) (sql.NullFloat64, sql.NullFloat64) {
+	var total float64
+	for _, segment := range segments {
+		distance, _ := calculateDistanceAndCostPerMile(segment.Origin, segment.Destination, price)
+		if !distance.Valid {
+			return sql.NullFloat64{}, sql.NullFloat64{}
+		}
+		total += distance.Float64
+	}
+	if total == 0 {
+		return sql.NullFloat64{}, sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: total, Valid: true},
+		sql.NullFloat64{Float64: geo.CostPerMile(price, total), Valid: true}
+}
+
+// processBulkMultiCitySearch searches the multi-city itinerary of the payload once per departure
+// date in [DepartureDateFrom, DepartureDateTo]. Every date shifts all segments by the same number
+// of days. All offers are stored, and the cheapest complete itinerary of each date becomes a
+// bulk search result.
+func (m *Manager) processBulkMultiCitySearch(ctx context.Context, worker *Worker, session *flights.Session, payload BulkSearchPayload) (err error) {
+	if len(payload.Segments) < 2 {
+		return fmt.Errorf("multi-city bulk search payload requires at least two segments")
+	}
+
+	cur, err := currency.ParseISO(payload.Currency)
+	if err != nil {
+		log.Printf("Warning: Invalid currency '%s', defaulting to USD. Error: %v", payload.Currency, err)
+		cur = currency.USD
+	}
+
+	first := payload.Segments[0]
+	last := payload.Segments[len(payload.Segments)-1]
+	from, to := payload.DepartureDateFrom, payload.DepartureDateTo
+	if from.IsZero() {
+		from = first.DepartureDate
+	}
+	if to.IsZero() || to.Before(from) {
+		to = from
+	}
+	dateRange := m.generateDateRange(from, to, 0)
+
+	var bulkSearchID int
+	if payload.BulkSearchID > 0 {
+		bulkSearchID = payload.BulkSearchID
+		if updateErr := m.postgresDB.UpdateBulkSearchStatus(ctx, bulkSearchID, "running"); updateErr != nil {
+			log.Printf("Failed to update bulk search %d status to running: %v", bulkSearchID, updateErr)
+		}
+	} else {
+		var jobRef sql.NullInt32
+		if payload.JobID > 0 {
+			jobRef = sql.NullInt32{Int32: int32(payload.JobID), Valid: true}
+		}
+		newID, createErr := m.postgresDB.CreateBulkSearchRecord(ctx, jobRef, len(dateRange), payload.Currency, "running")
+		if createErr != nil {
+			return fmt.Errorf("failed to create bulk search record: %w", createErr)
+		}
+		bulkSearchID = newID
+	}
+
+	defer func() {
+		if bulkSearchID > 0 && err != nil {
+			if updateErr := m.postgresDB.UpdateBulkSearchStatus(ctx, bulkSearchID, "failed"); updateErr != nil {
+				log.Printf("Failed to mark bulk search %d as failed: %v", bulkSearchID, updateErr)
+			}
+		}
+	}()
+
+	log.Printf("Starting multi-city bulk search %d: %d segments %s -> %s across %d dates",
+		bulkSearchID, len(payload.Segments), first.Origin, last.Destination, len(dateRange))
+
+	var (
+		searchErrors   []error
+		completedDates int
+		totalOffers    int
+		minPrice       = math.MaxFloat64
+		maxPrice       float64
+		sumPrice       float64
+		upperCurrency  = strings.ToUpper(payload.Currency)
+		firstDeparture = truncateToDay(first.DepartureDate)
+	)
+
+	for _, searchDate := range dateRange {
+		shiftDays := int(math.Round(truncateToDay(searchDate).Sub(firstDeparture).Hours() / 24))
+		segments := make([]SearchSegment, 0, len(payload.Segments))
+		for _, segment := range payload.Segments {
+			segment.DepartureDate = segment.DepartureDate.AddDate(0, 0, shiftDays)
+			segments = append(segments, segment)
+		}
+
+		args := flights.Args{
+			Date:        segments[0].DepartureDate,
+			SrcAirports: []string{first.Origin},
+			DstAirports: []string{last.Destination},
+			Segments:    multiCitySegments(segments),
+			Options: flights.Options{
+				Travelers: flights.Travelers{
+					Adults:       payload.Adults,
+					Children:     payload.Children,
+					InfantOnLap:  payload.InfantsLap,
+					InfantInSeat: payload.InfantsSeat,
+				},
+				Currency:       cur,
+				Stops:          parseStops(payload.Stops),
+				Class:          parseClass(payload.Class),
+				TripType:       flights.MultiCity,
+				Lang:           language.English,
+				Carriers:       payload.Carriers,
+				Country:        payload.Country,
+				GoogleHost:     payload.GoogleHost,
+				TimezoneOffset: payload.TZOffsetMin,
+			},
+			MinLayover:          time.Duration(payload.MinLayoverMinutes) * time.Minute,
+			MaxLayover:          time.Duration(payload.MaxLayoverMinutes) * time.Minute,
+			ExcludeBasicEconomy: payload.ExcludeBasicEconomy,
+			RequiredBags:        flights.Bags{CarryOn: payload.CarryOnBags, Checked: payload.CheckedBags},
+		}
+
+		dateLabel := searchDate.Format("2006-01-02")
+		offers, priceRange, searchErr := session.GetOffers(ctx, args)
+		if searchErr != nil {
+			log.Printf("Error searching multi-city itinerary on %s: %v", dateLabel, searchErr)
+			searchErr = fmt.Errorf("multi-city search on %s failed: %w", dateLabel, searchErr)
+			if blockdetect.IsBlock(searchErr) {
+				// The remaining dates would hit the same block; the job is retried once the
+				// backoff is over.
+				return searchErr
+			}
+			searchErrors = append(searchErrors, searchErr)
+			continue
+		}
+		// Only the cheapest itinerary gets its following segments; every segment costs one more
+		// request per date.
+		fillMultiCityFlights(ctx, session, args, offers, 1)
+		if len(offers) == 0 {
+			continue
+		}
+
+		searchPayload := FlightSearchPayload{
+			Origin:        first.Origin,
+			Destination:   last.Destination,
+			DepartureDate: segments[0].DepartureDate,
+			Adults:        payload.Adults,
+			Children:      payload.Children,
+			InfantsLap:    payload.InfantsLap,
+			InfantsSeat:   payload.InfantsSeat,
+			TripType:      payload.TripType,
+			Class:         payload.Class,
+			Stops:         payload.Stops,
+			Currency:      payload.Currency,
+			Segments:      segments,
+			Country:       payload.Country,
+			GoogleHost:    payload.GoogleHost,
+		}
+		if storeErr := worker.StoreFlightOffers(ctx, searchPayload, offers, priceRange); storeErr != nil {
+			log.Printf("Error storing multi-city offers on %s: %v", dateLabel, storeErr)
+			searchErrors = append(searchErrors, fmt.Errorf("failed to store multi-city offers on %s: %w", dateLabel, storeErr))
+		}
+
+		var best *flights.FullOffer
+		for i := range offers {
+			offer := offers[i]
+			distanceMiles, costPerMile := multiCityDistance(segments, offer.Price)
+			offerRecord := db.BulkSearchOfferRecord{
+				BulkSearchID:        bulkSearchID,
+				Origin:              first.Origin,
+				Destination:         last.Destination,
+				DepartureDate:       segments[0].DepartureDate,
+				Price:               offer.Price,
+				Currency:            upperCurrency,
+				AirlineCodes:        airlineCodesFromOffer(offer),
+				SrcAirportCode:      nullString(offer.SrcAirportCode),
+				DstAirportCode:      nullString(offer.DstAirportCode),
+				SrcCity:             nullString(offer.SrcCity),
+				DstCity:             nullString(offer.DstCity),
+				FlightDuration:      durationToNullMinutes(offer.FlightDuration),
+				DistanceMiles:       distanceMiles,
+				CostPerMile:         costPerMile,
+				OutboundFlightsJSON: flightsToJSON(offer.Flight),
+				OfferJSON:           offerToJSON(offer),
+				SegmentFlightsJSON:  segmentFlightsToJSON(offer),
+			}
+			normalized := m.normalizePrice(ctx, offerRecord.Price, offerRecord.Currency, distanceMiles)
+			offerRecord.NormalizedPrice = normalized.Price
+			offerRecord.NormalizedCurrency = normalized.Currency
+			offerRecord.NormalizedCostPerMile = normalized.CostPerMile
+			if insertErr := m.postgresDB.InsertBulkSearchOffer(ctx, offerRecord); insertErr != nil {
+				log.Printf("Failed to insert multi-city bulk offer on %s: %v", dateLabel, insertErr)
+			}
+
+			// Only itineraries with every segment priced are comparable.
+			if len(offer.SegmentFlights) == len(segments) && isDBSafePrice(offer.Price) &&
+				(best == nil || offer.Price < best.Price) {
+				best = &offers[i]
+			}
+		}
+		totalOffers += len(offers)
+		if best == nil {
+			continue
+		}
+
+		record := db.BulkSearchResultRecord{
+			BulkSearchID:        bulkSearchID,
+			Origin:              first.Origin,
+			Destination:         last.Destination,
+			DepartureDate:       segments[0].DepartureDate,
+			Price:               best.Price,
+			Currency:            upperCurrency,
+			AirlineCode:         nullString(firstAirlineCode(best.Flight)),
+			Duration:            durationToNullMinutes(best.FlightDuration),
+			SrcAirportCode:      nullString(best.SrcAirportCode),
+			DstAirportCode:      nullString(best.DstAirportCode),
+			SrcCity:             nullString(best.SrcCity),
+			DstCity:             nullString(best.DstCity),
+			FlightDuration:      durationToNullMinutes(best.FlightDuration),
+			OutboundFlightsJSON: flightsToJSON(best.Flight),
+			OfferJSON:           offerToJSON(*best),
+			SegmentFlightsJSON:  segmentFlightsToJSON(*best),
+		}
+		normalized := m.normalizePrice(ctx, record.Price, record.Currency, sql.NullFloat64{})
+		record.NormalizedPrice = normalized.Price
+		record.NormalizedCurrency = normalized.Currency
+		if insertErr := m.postgresDB.InsertBulkSearchResult(ctx, record); insertErr != nil {
+			log.Printf("Failed to insert multi-city bulk search result on %s: %v", dateLabel, insertErr)
+		}
+
+		completedDates++
+		sumPrice += best.Price
+		minPrice = math.Min(minPrice, best.Price)
+		maxPrice = math.Max(maxPrice, best.Price)
+	}
+
+	summary := db.BulkSearchSummary{
+		ID:          bulkSearchID,
+		Status:      "completed",
+		Completed:   completedDates,
+		TotalOffers: totalOffers,
+		ErrorCount:  len(searchErrors),
+	}
+	switch {
+	case completedDates == 0:
+		summary.Status = "failed"
+	case len(searchErrors) > 0:
+		summary.Status = "completed_with_errors"
+	}
+	if completedDates > 0 {
+		summary.MinPrice = sql.NullFloat64{Float64: minPrice, Valid: true}
+		summary.MaxPrice = sql.NullFloat64{Float64: maxPrice, Valid: true}
+		summary.AveragePrice = sql.NullFloat64{Float64: sumPrice / float64(completedDates), Valid: true}
+	}
+	if completeErr := m.postgresDB.CompleteBulkSearch(ctx, summary); completeErr != nil {
+		log.Printf("Failed to update bulk search summary for %d: %v", bulkSearchID, completeErr)
+	}
+
+	log.Printf("Multi-city bulk search %d completed: cheapest itineraries for %d of %d dates, %d offers",
+		bulkSearchID, completedDates, len(dateRange), totalOffers)
+	if len(searchErrors) > 0 && completedDates == 0 {
+		return fmt.Errorf("all multi-city searches failed: %d errors occurred", len(searchErrors))
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/gilby125/google-flights-api/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// statusRecorderDB records the bulk search statuses; the other methods are not expected.
+type statusRecorderDB struct {
+	db.PostgresDB
+	statuses []string
+}
+
+func (d *statusRecorderDB) UpdateBulkSearchStatus(ctx context.Context, id int, status string) error {
+	d.statuses = append(d.statuses, status)
+	return nil
+}
+
+func TestProcessBulkMultiCitySearchStopsOnBlock(t *testing.T) {
+	var requests atomic.Int32
+	session, err := flights.NewWithOptions(context.Background(), flights.SessionOptions{
+		Cookies: func(context.Context) ([]string, error) { return []string{"NID=1"}, nil },
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			requests.Add(1)
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+		}),
+		RateLimiter: ratelimit.NewLocal(ratelimit.Config{}),
+	})
+	require.NoError(t, err)
+
+	pg := &statusRecorderDB{}
+	m := &Manager{postgresDB: pg}
+	day := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	payload := BulkSearchPayload{
+		BulkSearchID: 5,
+		Segments: []SearchSegment{
+			{Origin: "JFK", Destination: "LHR", DepartureDate: day},
+			{Origin: "CDG", Destination: "JFK", DepartureDate: day.AddDate(0, 0, 7)},
+		},
+		DepartureDateFrom: day,
+		DepartureDateTo:   day.AddDate(0, 0, 4),
+		Adults:            1,
+		TripType:          "multi_city",
+		Currency:          "USD",
+	}
+
+	err = m.processBulkMultiCitySearch(context.Background(), nil, session, payload)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, flights.ErrRateLimited), "the block should reach the backoff: %v", err)
+	assert.EqualValues(t, 1, requests.Load(), "the remaining dates should not be searched")
+	assert.Equal(t, []string{"running", "failed"}, pg.statuses)
+}
@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gilby125/google-flights-api/db"
+	"github.com/gilby125/google-flights-api/flights"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// processPosComparison prices the itinerary of the payload in every market and stores the cheapest
+// offer of each market.
+func (m *Manager) processPosComparison(ctx context.Context, session *flights.Session, payload PosComparisonPayload) error {
+	if m.postgresDB == nil {
+		return fmt.Errorf("pos comparison requires postgres")
+	}
+	if err := m.postgresDB.UpdatePosComparisonStatus(ctx, payload.ComparisonID, "processing"); err != nil {
+		log.Printf("Warning: failed to mark pos comparison %d as processing: %v", payload.ComparisonID, err)
+	}
+
+	args, markets, err := posComparisonArgs(payload)
+	if err == nil {
+		convert := m.marketConverter()
+		if convert == nil {
+			log.Printf("Warning: no currency converter set; pos comparison %d prices every market in %s instead of its own currency", payload.ComparisonID, args.Currency)
+		}
+		var comparison *flights.MarketComparison
+		comparison, err = session.CompareMarkets(ctx, args, markets, convert)
+		if err == nil {
+			return m.storePosComparison(ctx, session, args, payload.ComparisonID, comparison)
+		}
+	}
+
+	summary := db.PosComparisonSummary{
+		ComparisonID: payload.ComparisonID,
+		Status:       "failed",
+		ErrorMessage: nullString(err.Error()),
+	}
+	if completeErr := m.postgresDB.CompletePosComparison(ctx, summary); completeErr != nil {
+		log.Printf("Warning: failed to mark pos comparison %d as failed: %v", payload.ComparisonID, completeErr)
+	}
+	return fmt.Errorf("pos comparison %d: %w", payload.ComparisonID, err)
+}
+
+func posComparisonArgs(payload PosComparisonPayload) (flights.Args, []flights.Market, error) {
+	var tripType flights.TripType
+	switch payload.TripType {
+	case "one_way":
+		tripType = flights.OneWay
+	case "round_trip":
+		tripType = flights.RoundTrip
+	default:
+		return flights.Args{}, nil, fmt.Errorf("invalid trip type: %s", payload.TripType)
+	}
+
+	cur, err := currency.ParseISO(payload.Currency)
+	if err != nil {
+		return flights.Args{}, nil, fmt.Errorf("invalid currency %q: %w", payload.Currency, err)
+	}
+
+	markets := make([]flights.Market, 0, len(payload.Markets))
+	for _, pm := range payload.Markets {
+		market := flights.Market{Country: strings.ToUpper(pm.Country), GoogleHost: pm.GoogleHost}
+		if pm.Currency != "" {
+			market.Currency, err = currency.ParseISO(pm.Currency)
+			if err != nil {
+				return flights.Args{}, nil, fmt.Errorf("invalid currency %q of market %s: %w", pm.Currency, pm.Country, err)
+			}
+		}
+		markets = append(markets, market)
+	}
+
+	args := flights.Args{
+		Date:        payload.DepartureDate,
+		ReturnDate:  payload.ReturnDate,
+		SrcAirports: []string{payload.Origin},
+		DstAirports: []string{payload.Destination},
+		Options: flights.Options{
+			Travelers: flights.Travelers{
+				Adults:       payload.Adults,
+				Children:     payload.Children,
+				InfantOnLap:  payload.InfantsLap,
+				InfantInSeat: payload.InfantsSeat,
+			},
+			Currency: cur,
+			Stops:    parseStops(payload.Stops),
+			Class:    parseClass(payload.Class),
+			TripType: tripType,
+			Lang:     language.English,
+		},
+	}
+	return args, markets, nil
+}
+
+func (m *Manager) storePosComparison(ctx context.Context, session *flights.Session, args flights.Args, comparisonID int, comparison *flights.MarketComparison) error {
+	failed := 0
+	for _, offer := range comparison.Offers {
+		result := db.PosComparisonResult{
+			ComparisonID: comparisonID,
+			Country:      offer.Country,
+			GoogleHost:   nullString(offer.GoogleHost),
+			Currency:     offer.Currency.String(),
+		}
+
+		marketArgs := args
+		marketArgs.Country = offer.Country
+		marketArgs.GoogleHost = offer.GoogleHost
+		marketArgs.Currency = offer.Currency
+		if url, err := session.SerializeURL(ctx, marketArgs); err == nil {
+			result.SearchURL = nullString(url)
+		}
+
+		switch {
+		case offer.Err != nil:
+			failed++
+			result.ErrorMessage = nullString(offer.Err.Error())
+		case offer.Offer != nil && isDBSafePrice(offer.Price) && isDBSafePrice(offer.NormalizedPrice):
+			result.Price = sql.NullFloat64{Float64: offer.Price, Valid: true}
+			result.NormalizedPrice = sql.NullFloat64{Float64: offer.NormalizedPrice, Valid: true}
+			result.AirlineCodes = airlineCodesFromOffer(*offer.Offer)
+			result.FlightNumbers = flightNumbersFromOffer(*offer.Offer)
+		}
+
+		if err := m.postgresDB.InsertPosComparisonResult(ctx, result); err != nil {
+			return fmt.Errorf("failed to store pos comparison %d result for %s: %w", comparisonID, offer.Country, err)
+		}
+	}
+
+	summary := db.PosComparisonSummary{ComparisonID: comparisonID, Status: "completed"}
+	if failed > 0 {
+		summary.Status = "completed_with_errors"
+	}
+	if cheapest := comparison.Cheapest(); cheapest != nil && isDBSafePrice(cheapest.NormalizedPrice) {
+		summary.CheapestCountry = nullString(cheapest.Country)
+		summary.CheapestPrice = sql.NullFloat64{Float64: cheapest.NormalizedPrice, Valid: true}
+		summary.Savings = sql.NullFloat64{Float64: comparison.Savings(), Valid: true}
+		log.Printf("POS comparison %d: cheapest market %s at %.2f %s (saves %.2f)",
+			comparisonID, cheapest.Country, cheapest.NormalizedPrice, comparison.Currency, comparison.Savings())
+	}
+	return m.postgresDB.CompletePosComparison(ctx, summary)
+}
+
+func flightNumbersFromOffer(offer flights.FullOffer) []string {
+	numbers := make([]string, 0, len(offer.Flight)+len(offer.ReturnFlight))
+	for _, leg := range offer.Flight {
+		numbers = append(numbers, leg.FlightNumber)
+	}
+	for _, leg := range offer.ReturnFlight {
+		numbers = append(numbers, leg.FlightNumber)
+	}
+	return numbers
+}
@@ -3,7 +3,6 @@ package worker
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -86,16 +85,10 @@ func (s *Scheduler) AddJob(payload []byte) error {
 		Payload: payload,
 	}
 
-	// Serialize the job
-	jobBytes, err := json.Marshal(job)
-	if err != nil {
-		return fmt.Errorf("failed to marshal job: %w", err)
-	}
-
 	// Add the job to the queue
 	queueName := "scheduled_jobs"
 	ctx := queue.WithEnqueueMeta(context.Background(), queue.EnqueueMeta{Actor: "scheduler"})
-	_, err = s.queue.Enqueue(ctx, queueName, jobBytes)
+	_, err := s.queue.Enqueue(ctx, queueName, job)
 	if err != nil {
 		return fmt.Errorf("failed to enqueue job: %w", err)
 	}
@@ -159,7 +152,8 @@ func (s *Scheduler) loadScheduledBulkSearches() error {
 
 // executeScheduledBulkSearch executes a scheduled bulk search job
 func (s *Scheduler) executeScheduledBulkSearch(jobID int, jobName string) {
-	ctx := queue.WithEnqueueMeta(context.Background(), queue.EnqueueMeta{Actor: "scheduler"})
+	// Scheduled bulk searches are background work and yield to interactive searches.
+	ctx := queue.WithPriority(queue.WithEnqueueMeta(context.Background(), queue.EnqueueMeta{Actor: "scheduler"}), queue.PriorityLow)
 	log.Printf("Executing scheduled bulk search: %s (ID: %d)", jobName, jobID)
 
 	// Get job details from database
@@ -169,13 +163,43 @@ func (s *Scheduler) executeScheduledBulkSearch(jobID int, jobName string) {
 		return
 	}
 
-	// Calculate dates based on execution time if dynamic dates are enabled
+	bulkSearchPayload := BulkSearchPayloadForJob(jobID, details, time.Now())
+	log.Printf("Dates for job %s: departure %s to %s (dynamic: %t)", jobName,
+		bulkSearchPayload.DepartureDateFrom.Format("2006-01-02"), bulkSearchPayload.DepartureDateTo.Format("2006-01-02"), details.DynamicDates)
+
+	totalRoutes := len(bulkSearchPayload.Origins) * len(bulkSearchPayload.Destinations)
+	if totalRoutes == 0 {
+		log.Printf("Scheduled bulk search %s has no routes to process", jobName)
+		return
+	}
+
+	// The bulk search record is created by the worker coordinating the search, so that a tick
+	// collapsed into an admin "run now" of the same job (see BulkSearchPayload.IdempotencyKey)
+	// leaves no record behind.
+	bulkJobID, err := s.queue.Enqueue(ctx, "bulk_search", bulkSearchPayload)
+	if err != nil {
+		log.Printf("Error enqueuing bulk search for job %s: %v", jobName, err)
+		return
+	}
+
+	// Update the last run time
+	err = s.postgresDB.UpdateJobLastRun(ctx, jobID)
+	if err != nil {
+		log.Printf("Error updating last run time for job %s: %v", jobName, err)
+		// Don't return - the job was successfully enqueued
+	}
+
+	log.Printf("Successfully enqueued scheduled bulk search: %s (bulk job ID: %s)", jobName, bulkJobID)
+}
+
+// BulkSearchPayloadForJob returns the bulk search run by the scheduled job jobID at now. Jobs with
+// dynamic dates search from DaysFromExecution days after now for SearchWindowDays days; the
+// others search their stored dates. The scheduler and the admin "run now" endpoint share it, so
+// that both enqueue the same search.
+func BulkSearchPayloadForJob(jobID int, details *db.JobDetails, now time.Time) BulkSearchPayload {
 	var departureDateFrom, departureDateTo, returnDateFrom, returnDateTo time.Time
 
 	if details.DynamicDates {
-		// Use dates relative to current execution time
-		now := time.Now()
-
 		// Get days from execution (default to 0 if not set)
 		daysFromExecution := 0
 		if details.DaysFromExecution.Valid {
@@ -192,16 +216,11 @@ func (s *Scheduler) executeScheduledBulkSearch(jobID int, jobName string) {
 		departureDateFrom = now.AddDate(0, 0, daysFromExecution)
 		departureDateTo = departureDateFrom.AddDate(0, 0, searchWindowDays-1)
 
-		log.Printf("Dynamic dates for job %s: searching %d days from now (%s to %s)",
-			jobName, daysFromExecution, departureDateFrom.Format("2006-01-02"), departureDateTo.Format("2006-01-02"))
-
 		// For round trips, calculate return dates if trip length is specified
 		if details.TripType == "round_trip" && details.TripLength.Valid {
 			tripLength := int(details.TripLength.Int32)
 			returnDateFrom = departureDateFrom.AddDate(0, 0, tripLength)
 			returnDateTo = departureDateTo.AddDate(0, 0, tripLength)
-			log.Printf("Round trip return dates: %s to %s (trip length: %d days)",
-				returnDateFrom.Format("2006-01-02"), returnDateTo.Format("2006-01-02"), tripLength)
 		}
 	} else {
 		// Use static dates from job details
@@ -214,9 +233,6 @@ func (s *Scheduler) executeScheduledBulkSearch(jobID int, jobName string) {
 		if details.ReturnDateEnd.Valid {
 			returnDateTo = details.ReturnDateEnd.Time
 		}
-
-		log.Printf("Static dates for job %s: departure %s to %s",
-			jobName, departureDateFrom.Format("2006-01-02"), departureDateTo.Format("2006-01-02"))
 	}
 
 	// Get trip length
@@ -225,9 +241,7 @@ func (s *Scheduler) executeScheduledBulkSearch(jobID int, jobName string) {
 		tripLength = int(details.TripLength.Int32)
 	}
 
-	// Create bulk search payload with calculated dates
-	currency := strings.ToUpper(details.Currency)
-	bulkSearchPayload := BulkSearchPayload{
+	return BulkSearchPayload{
 		Origins:           []string{details.Origin},
 		Destinations:      []string{details.Destination},
 		DepartureDateFrom: departureDateFrom,
@@ -242,55 +256,9 @@ func (s *Scheduler) executeScheduledBulkSearch(jobID int, jobName string) {
 		TripType:          details.TripType,
 		Class:             details.Class,
 		Stops:             details.Stops,
-		Currency:          currency,
+		Currency:          strings.ToUpper(details.Currency),
+		JobID:             jobID,
 	}
-
-	totalRoutes := len(bulkSearchPayload.Origins) * len(bulkSearchPayload.Destinations)
-	if totalRoutes == 0 {
-		log.Printf("Scheduled bulk search %s has no routes to process", jobName)
-		return
-	}
-
-	bulkSearchID, err := s.postgresDB.CreateBulkSearchRecord(
-		ctx,
-		sql.NullInt32{Int32: int32(jobID), Valid: true},
-		totalRoutes,
-		currency,
-		"queued",
-	)
-	if err != nil {
-		log.Printf("Error creating bulk search record for job %s: %v", jobName, err)
-		return
-	}
-	bulkSearchPayload.BulkSearchID = bulkSearchID
-	bulkSearchPayload.JobID = jobID
-
-	// Serialize the payload
-	payloadBytes, err := json.Marshal(bulkSearchPayload)
-	if err != nil {
-		log.Printf("Error serializing bulk search payload for job %s: %v", jobName, err)
-		return
-	}
-
-	// Enqueue the bulk search job
-	bulkJobID := fmt.Sprintf("scheduled_bulk_search-%d-%d", jobID, time.Now().Unix())
-	_, err = s.queue.Enqueue(ctx, "bulk_search", payloadBytes)
-	if err != nil {
-		log.Printf("Error enqueuing bulk search for job %s: %v", jobName, err)
-		if updateErr := s.postgresDB.UpdateBulkSearchStatus(ctx, bulkSearchID, "failed"); updateErr != nil {
-			log.Printf("Failed to update bulk search status after enqueue failure: %v", updateErr)
-		}
-		return
-	}
-
-	// Update the last run time
-	err = s.postgresDB.UpdateJobLastRun(ctx, jobID)
-	if err != nil {
-		log.Printf("Error updating last run time for job %s: %v", jobName, err)
-		// Don't return - the job was successfully enqueued
-	}
-
-	log.Printf("Successfully enqueued scheduled bulk search: %s (bulk job ID: %s)", jobName, bulkJobID)
 }
 
 // EnqueuePriceGraphSweep enqueues a price graph sweep job and returns the sweep ID
@@ -0,0 +1,15 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/gilby125/google-flights-api/flights"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreDealPenalizesBasicEconomy(t *testing.T) {
+	standard := flights.FullOffer{Offer: flights.Offer{Price: 220}}
+	basic := flights.FullOffer{Offer: flights.Offer{Price: 200}, Fare: flights.Fare{BasicEconomy: true}}
+
+	assert.Less(t, scoreDeal(standard, 0), scoreDeal(basic, 0))
+}
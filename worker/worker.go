@@ -5,7 +5,7 @@ import (
 	"context"
 
 	// "crypto/tls" // Unused
-	// "database/sql" // Unused
+	"database/sql"
 	// "encoding/json" // Unused
 	"encoding/pem"
 	"fmt"
@@ -110,50 +110,106 @@ type FlightSearchPayload struct {
 	Class         string // Changed to string for JSON unmarshal
 	Stops         string // Changed to string for JSON unmarshal
 	Currency      string
+	Segments      []SearchSegment // multi_city only; Origin/Destination span the whole trip
+	Country       string          // point of sale, see flights.Options; empty for the default market
+	GoogleHost    string
+}
+
+// SearchSegment is one flight of a multi-city search.
+type SearchSegment struct {
+	Origin        string    `json:"origin"`
+	Destination   string    `json:"destination"`
+	DepartureDate time.Time `json:"departure_date"`
+}
+
+// PosComparisonPayload is a point-of-sale comparison: the same itinerary searched in every market
+// and normalized to Currency.
+type PosComparisonPayload struct {
+	ComparisonID  int                   `json:"comparison_id"`
+	Origin        string                `json:"origin"`
+	Destination   string                `json:"destination"`
+	DepartureDate time.Time             `json:"departure_date"`
+	ReturnDate    time.Time             `json:"return_date"`
+	Adults        int                   `json:"adults"`
+	Children      int                   `json:"children,omitempty"`
+	InfantsLap    int                   `json:"infants_lap,omitempty"`
+	InfantsSeat   int                   `json:"infants_seat,omitempty"`
+	TripType      string                `json:"trip_type"`
+	Class         string                `json:"class"`
+	Stops         string                `json:"stops"`
+	Currency      string                `json:"currency"` // reporting currency
+	Markets       []PosComparisonMarket `json:"markets"`
+}
+
+// PosComparisonMarket is a point of sale of a [PosComparisonPayload].
+type PosComparisonMarket struct {
+	Country    string `json:"gl"`
+	Currency   string `json:"currency,omitempty"` // currency charged in the market
+	GoogleHost string `json:"google_host,omitempty"`
 }
 
 type BulkSearchPayload struct {
-	Origin            string
-	Destination       string
-	Origins           []string
-	Destinations      []string
-	DepartureDateFrom time.Time
-	DepartureDateTo   time.Time
-	ReturnDateFrom    time.Time
-	ReturnDateTo      time.Time
-	TripLength        int
-	Adults            int
-	Children          int
-	InfantsLap        int
-	InfantsSeat       int
-	TripType          string
-	Class             string // Changed to string for JSON unmarshal
-	Stops             string // Changed to string for JSON unmarshal
-	Currency          string
-	Carriers          []string `json:"carriers,omitempty"`
-	BulkSearchID      int      `json:"bulk_search_id,omitempty"`
-	JobID             int      `json:"job_id,omitempty"`
+	Origin              string
+	Destination         string
+	Origins             []string
+	Destinations        []string
+	DepartureDateFrom   time.Time
+	DepartureDateTo     time.Time
+	ReturnDateFrom      time.Time
+	ReturnDateTo        time.Time
+	TripLength          int
+	Adults              int
+	Children            int
+	InfantsLap          int
+	InfantsSeat         int
+	TripType            string
+	Class               string // Changed to string for JSON unmarshal
+	Stops               string // Changed to string for JSON unmarshal
+	Currency            string
+	Carriers            []string `json:"carriers,omitempty"`
+	MinLayoverMinutes   int      `json:"min_layover_minutes,omitempty"`
+	MaxLayoverMinutes   int      `json:"max_layover_minutes,omitempty"`
+	ExcludeBasicEconomy bool     `json:"exclude_basic_economy,omitempty"`
+	CarryOnBags         int      `json:"carry_on_bags,omitempty"`
+	CheckedBags         int      `json:"checked_bags,omitempty"`
+	Country             string   `json:"gl,omitempty"`            // point of sale, see flights.Options
+	GoogleHost          string   `json:"google_host,omitempty"`   // e.g. "www.google.de"
+	TZOffsetMin         *int     `json:"tz_offset_min,omitempty"` // user timezone, JavaScript getTimezoneOffset
+	BulkSearchID        int      `json:"bulk_search_id,omitempty"`
+	JobID               int      `json:"job_id,omitempty"`
+
+	// Segments of a multi_city bulk search. Every departure date from DepartureDateFrom to
+	// DepartureDateTo shifts all segments by its offset from the first segment's date.
+	Segments []SearchSegment `json:"segments,omitempty"`
 }
 
 // BulkSearchRoutePayload represents a single route in a fanned-out bulk search.
 // Each route is processed independently by any available worker.
 type BulkSearchRoutePayload struct {
-	BulkSearchID      int       `json:"bulk_search_id"`
-	TotalRoutes       int       `json:"total_routes"` // Total routes in the parent bulk search
-	Origin            string    `json:"origin"`
-	Destination       string    `json:"destination"`
-	DepartureDateFrom time.Time `json:"departure_date_from"`
-	DepartureDateTo   time.Time `json:"departure_date_to"`
-	TripLength        int       `json:"trip_length"`
-	TripType          string    `json:"trip_type"`
-	Class             string    `json:"class"`
-	Stops             string    `json:"stops"`
-	Currency          string    `json:"currency"`
-	Adults            int       `json:"adults"`
-	Children          int       `json:"children,omitempty"`
-	InfantsLap        int       `json:"infants_lap,omitempty"`
-	InfantsSeat       int       `json:"infants_seat,omitempty"`
-	Carriers          []string  `json:"carriers,omitempty"`
+	BulkSearchID        int       `json:"bulk_search_id"`
+	TotalRoutes         int       `json:"total_routes"` // Total routes in the parent bulk search
+	Origin              string    `json:"origin"`
+	Destination         string    `json:"destination"`
+	DepartureDateFrom   time.Time `json:"departure_date_from"`
+	DepartureDateTo     time.Time `json:"departure_date_to"`
+	TripLength          int       `json:"trip_length"`
+	TripType            string    `json:"trip_type"`
+	Class               string    `json:"class"`
+	Stops               string    `json:"stops"`
+	Currency            string    `json:"currency"`
+	Adults              int       `json:"adults"`
+	Children            int       `json:"children,omitempty"`
+	InfantsLap          int       `json:"infants_lap,omitempty"`
+	InfantsSeat         int       `json:"infants_seat,omitempty"`
+	Carriers            []string  `json:"carriers,omitempty"`
+	MinLayoverMinutes   int       `json:"min_layover_minutes,omitempty"`
+	MaxLayoverMinutes   int       `json:"max_layover_minutes,omitempty"`
+	ExcludeBasicEconomy bool      `json:"exclude_basic_economy,omitempty"`
+	CarryOnBags         int       `json:"carry_on_bags,omitempty"`
+	CheckedBags         int       `json:"checked_bags,omitempty"`
+	Country             string    `json:"gl,omitempty"`
+	GoogleHost          string    `json:"google_host,omitempty"`
+	TZOffsetMin         *int      `json:"tz_offset_min,omitempty"`
 }
 
 // PriceGraphSweepPayload defines the data needed to execute a price graph sweep
@@ -166,6 +222,10 @@ type PriceGraphSweepPayload struct {
 	DepartureDateTo   time.Time `json:"departure_date_to"`
 	TripLengths       []int     `json:"trip_lengths,omitempty"`
 	TripType          string    `json:"trip_type"`
+	// ReturnOrigins makes the sweep open-jaw: the return flight departs from each of them, back
+	// to the origin or to each of ReturnDestinations (two-segment multi-city).
+	ReturnOrigins      []string `json:"return_origins,omitempty"`
+	ReturnDestinations []string `json:"return_destinations,omitempty"`
 	// Class is kept for backward compatibility; prefer Classes for multi-cabin sweeps.
 	Class           string   `json:"class,omitempty"`
 	Classes         []string `json:"classes,omitempty"`
@@ -178,6 +238,12 @@ type PriceGraphSweepPayload struct {
 	RateLimitMillis int      `json:"rate_limit_millis,omitempty"`
 }
 
+// market returns the point of sale the search is priced in, as stored with its rows. The Google
+// host is NULL for the default host.
+func (p FlightSearchPayload) market() (country string, googleHost sql.NullString) {
+	return strings.ToUpper(defaultString(p.Country, flights.DefaultCountry)), nullString(strings.ToLower(p.GoogleHost))
+}
+
 // StoreFlightOffers stores flight offers in the database (Exported for testing)
 func (w *Worker) StoreFlightOffers(ctx context.Context, payload FlightSearchPayload, offers []flights.FullOffer, priceRange *flights.PriceRange) error {
 	// Begin a transaction using the interface method
@@ -190,11 +256,12 @@ func (w *Worker) StoreFlightOffers(ctx context.Context, payload FlightSearchPayl
 	// Insert the search query
 	var queryID int
 	searchID := uuid.New().String()
+	country, googleHost := payload.market()
 	err = tx.QueryRowContext(
 		ctx,
 		`INSERT INTO search_queries
-		(origin, destination, departure_date, return_date, adults, children, infants_lap, infants_seat, trip_type, class, stops, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) -- Removed search_id ($1)
+		(origin, destination, departure_date, return_date, adults, children, infants_lap, infants_seat, trip_type, class, stops, status, country, google_host)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) -- Removed search_id ($1)
 		RETURNING id`,
 		payload.Origin,
 		payload.Destination,
@@ -208,6 +275,8 @@ func (w *Worker) StoreFlightOffers(ctx context.Context, payload FlightSearchPayl
 		payload.Class,
 		payload.Stops,
 		"completed",
+		country,
+		googleHost,
 	).Scan(&queryID)
 
 	if err != nil {
@@ -240,11 +309,38 @@ func (w *Worker) StoreFlightOffers(ctx context.Context, payload FlightSearchPayl
 		return fmt.Errorf("failed to insert search results: %w", err)
 	}
 
+	for i, segment := range payload.Segments {
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO search_query_segments
+			(search_query_id, segment_index, origin, destination, departure_date)
+			VALUES ($1, $2, $3, $4, $5)`,
+			queryID,
+			i,
+			segment.Origin,
+			segment.Destination,
+			segment.DepartureDate,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert search query segment %d: %w", i, err)
+		}
+	}
+
 	// Store each offer
 	for _, offer := range offers {
 		// Insert the flight offer
 		var offerID int
 		totalDuration := int(offer.FlightDuration.Minutes())
+		outboundStops := 0
+		if len(offer.Flight) > 1 {
+			outboundStops = len(offer.Flight) - 1
+		}
+		// Only round trips whose return leg was fetched have a return duration
+		var returnDuration, returnStops sql.NullInt32
+		if len(offer.ReturnFlight) > 0 {
+			returnDuration = durationToNullMinutes(offer.ReturnFlightDuration)
+			returnStops = sql.NullInt32{Int32: int32(len(offer.ReturnFlight) - 1), Valid: true}
+		}
 		// The searchID is generated above (line 142) and used directly below.
 		// No need to fetch it back from search_queries.
 		// Extract airline codes from the offer (using the first flight segment for now)
@@ -265,17 +361,18 @@ func (w *Worker) StoreFlightOffers(ctx context.Context, payload FlightSearchPayl
 		err = tx.QueryRowContext(
 			ctx,
 			`INSERT INTO flight_offers
-			(search_id, price, currency, airline_codes, outbound_duration, outbound_stops, return_duration, return_stops)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			(search_id, price, currency, airline_codes, outbound_duration, outbound_stops, return_duration, return_stops, country)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 			RETURNING id`,
 			searchID,
 			offer.Price,
 			payload.Currency,
 			airlineCodesStr,
-			totalDuration, // outbound_duration (in minutes)
-			0,             // outbound_stops (placeholder - needs proper calculation)
-			totalDuration, // return_duration (placeholder - same as outbound for now)
-			0,             // return_stops (placeholder)
+			totalDuration,  // outbound_duration (in minutes)
+			outboundStops,  // outbound_stops
+			returnDuration, // return_duration (in minutes)
+			returnStops,    // return_stops
+			country,
 		).Scan(&offerID)
 
 		if err != nil {
@@ -283,7 +380,7 @@ func (w *Worker) StoreFlightOffers(ctx context.Context, payload FlightSearchPayl
 		}
 
 		// Store flight segments
-		for _, flight := range offer.Flight {
+		for _, flight := range indexedFlights(offer) {
 			// Ensure airline exists
 			_, err = tx.ExecContext(
 				ctx,
@@ -337,8 +434,8 @@ func (w *Worker) StoreFlightOffers(ctx context.Context, payload FlightSearchPayl
 				ctx,
 				`INSERT INTO flight_segments
 				(flight_offer_id, airline_code, flight_number, departure_airport, arrival_airport,
-				departure_time, arrival_time, duration, airplane, legroom, is_return)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+				departure_time, arrival_time, duration, airplane, legroom, is_return, segment_index)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
 				offerID,
 				flight.FlightNumber[:2], // Airline code
 				flight.FlightNumber,
@@ -349,7 +446,8 @@ func (w *Worker) StoreFlightOffers(ctx context.Context, payload FlightSearchPayl
 				duration,
 				flight.Airplane,
 				flight.Legroom,
-				false, // Not a return flight
+				flight.IsReturn,
+				flight.SegmentIndex,
 			)
 			if err != nil {
 				return fmt.Errorf("failed to insert flight segment: %w", err)
@@ -370,6 +468,32 @@ func (w *Worker) StoreFlightOffers(ctx context.Context, payload FlightSearchPayl
 	return nil
 }
 
+// indexedFlight is a flight of an offer with the multi-city segment it belongs to.
+type indexedFlight struct {
+	flights.Flight
+	SegmentIndex int
+	IsReturn     bool
+}
+
+// indexedFlights returns the outbound flights of an offer, or the flights of every segment once
+// a multi-city offer has been filled, followed by the return flights of a round trip.
+func indexedFlights(offer flights.FullOffer) []indexedFlight {
+	segments := offer.SegmentFlights
+	if len(segments) == 0 {
+		segments = [][]flights.Flight{offer.Flight}
+	}
+	var result []indexedFlight
+	for i, segment := range segments {
+		for _, flight := range segment {
+			result = append(result, indexedFlight{Flight: flight, SegmentIndex: i})
+		}
+	}
+	for _, flight := range offer.ReturnFlight {
+		result = append(result, indexedFlight{Flight: flight, IsReturn: true})
+	}
+	return result
+}
+
 // StoreFlightInNeo4j stores flight data in Neo4j for graph analysis (Exported for testing)
 func (w *Worker) StoreFlightInNeo4j(ctx context.Context, offer flights.FullOffer, class string) error {
 	if w.neo4jDB == nil {